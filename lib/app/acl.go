@@ -68,7 +68,7 @@ func (r *ApplicationsACL) appContext(locator loc.Locator) *users.Context {
 }
 
 func (r *ApplicationsACL) CreateImportOperation(req *ImportRequest) (*storage.AppOperation, error) {
-	if err := r.check(req.Repository, teleservices.VerbCreate); err != nil {
+	if err := r.checkOperation(req.Repository, storage.OperationVerbImport); err != nil {
 		return nil, trace.Wrap(err)
 	}
 	return r.applications.CreateImportOperation(req)
@@ -197,7 +197,7 @@ func (r *ApplicationsACL) UpsertApp(locator loc.Locator, reader io.Reader, label
 
 // StartAppHook starts application hook specified with req asynchronously
 func (r *ApplicationsACL) StartAppHook(ctx context.Context, req HookRunRequest) (*HookRef, error) {
-	if err := r.check(req.Application.Repository, teleservices.VerbRead); err != nil {
+	if err := r.checkOperation(req.Application.Repository, storage.OperationVerbRunHook); err != nil {
 		return nil, trace.Wrap(err)
 	}
 	return r.applications.StartAppHook(ctx, req)
@@ -248,6 +248,12 @@ func (r *ApplicationsACL) check(repoName, verb string) error {
 	return r.checker.CheckAccessToRule(r.repoContext(repoName), teledefaults.Namespace, storage.KindApp, verb, false)
 }
 
+// checkOperation checks whether the user has the permissions to run the specified
+// operation on apps in the repository, see users.CheckAccess
+func (r *ApplicationsACL) checkOperation(repoName, operation string) error {
+	return users.CheckAccess(r.checker, r.repoContext(repoName), teledefaults.Namespace, storage.KindOperation, operation)
+}
+
 // checkApp checks whether the user has the requested permissions to the specified app
 func (r *ApplicationsACL) checkApp(locator loc.Locator, verb string) error {
 	return r.checker.CheckAccessToRule(r.appContext(locator),
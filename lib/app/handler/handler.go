@@ -64,6 +64,8 @@ type WebHandlerConfig struct {
 	Charts helm.Repository
 	// Authenticator is used to authenticate requests.
 	Authenticator users.Authenticator
+	// RateLimiter optionally limits the requests of each user and API key.
+	RateLimiter *httplib.RateLimiter
 }
 
 // CheckAndSetDefaults validates the config and sets some defaults.
@@ -110,25 +112,25 @@ func NewWebHandler(cfg WebHandlerConfig) (*WebHandler, error) {
 	h.POST("/app/v1/operations/import", h.needsAuth(h.createImportOperation))
 	h.GET("/app/v1/operations/import/:operation_id", h.needsAuth(h.getImportedApp))
 	h.GET("/app/v1/operations/import/:operation_id/progress", h.needsAuth(h.getOperationProgress))
-	h.GET("/app/v1/operations/import/:operation_id/logs", h.needsAuth(h.getOperationLogs))
+	h.GET("/app/v1/operations/import/:operation_id/logs", h.needsAuthStream(h.getOperationLogs))
 	h.GET("/app/v1/operations/import/:operation_id/crash-report", h.needsAuth(h.getOperationCrashReport))
 	h.POST("/app/v1/operations/export/:repository_id/:package_id/:version", h.needsAuth(h.exportApp))
 	h.POST("/app/v1/operations/uninstall/:repository_id/:package_id/:version", h.needsAuth(h.uninstallApp))
 	h.GET("/app/v1/applications/:repository_id/:package_id/:version", h.needsAuth(h.getApp))
-	h.POST("/app/v1/applications/:repository_id", h.needsAuth(h.createApp))
+	h.POST("/app/v1/applications/:repository_id", h.needsAuthTransfer(h.createApp))
 	h.POST("/app/v1/applications/:repository_id/:package_id/:version/hook/start", h.needsAuth(h.startAppHook))
-	h.GET("/app/v1/applications/:repository_id/:package_id/:version/hook/:namespace/:name/wait", h.needsAuth(h.waitAppHook))
-	h.GET("/app/v1/applications/:repository_id/:package_id/:version/hook/:namespace/:name/stream", h.needsAuth(h.streamAppHookLogs))
+	h.GET("/app/v1/applications/:repository_id/:package_id/:version/hook/:namespace/:name/wait", h.needsAuthStream(h.waitAppHook))
+	h.GET("/app/v1/applications/:repository_id/:package_id/:version/hook/:namespace/:name/stream", h.needsAuthStream(h.streamAppHookLogs))
 	h.DELETE("/app/v1/applications/:repository_id/:package_id/:version/hook/:namespace/:name", h.needsAuth(h.deleteAppHookJob))
 
 	h.GET("/app/v1/applications/:repository_id/:package_id/:version/status", h.needsAuth(h.getAppStatus))
 	h.DELETE("/app/v1/applications/:repository_id/:package_id/:version", h.needsAuth(h.deleteApp))
 	h.GET("/app/v1/applications/:repository_id/:package_id/:version/manifest", h.needsAuth(h.getAppManifest))
 	h.GET("/app/v1/applications/:repository_id/:package_id/:version/resources", h.needsAuth(h.getAppResources))
-	h.GET("/app/v1/applications/:repository_id/:package_id/:version/standalone-installer", h.needsAuth(h.getAppInstaller))
+	h.GET("/app/v1/applications/:repository_id/:package_id/:version/standalone-installer", h.needsAuthTransfer(h.getAppInstaller))
 	// this method will allow to pass access token in headers, so web api should submit a form to the following URL
 	// the server will reply with the download response
-	h.POST("/app/v1/applications/:repository_id/:package_id/:version/standalone-installer", h.needsAuth(h.getAppInstaller))
+	h.POST("/app/v1/applications/:repository_id/:package_id/:version/standalone-installer", h.needsAuthTransfer(h.getAppInstaller))
 
 	// Gravity install URLs
 	h.GET("/telekube/install", h.wrap(h.telekubeInstallScript))
@@ -1039,7 +1041,25 @@ func (h *WebHandler) wrap(fn func(w http.ResponseWriter, r *http.Request, p http
 	}
 }
 
+// needsAuth authenticates the request and accounts it against the read
+// or write budget depending on the request method.
 func (h *WebHandler) needsAuth(fn serviceHandler) httprouter.Handle {
+	return h.authenticate("", fn)
+}
+
+// needsAuthTransfer authenticates the request and accounts it against
+// the transfer budget.
+func (h *WebHandler) needsAuthTransfer(fn serviceHandler) httprouter.Handle {
+	return h.authenticate(httplib.RequestTransfer, fn)
+}
+
+// needsAuthStream authenticates the request and accounts it against
+// the stream budget.
+func (h *WebHandler) needsAuthStream(fn serviceHandler) httprouter.Handle {
+	return h.authenticate(httplib.RequestStream, fn)
+}
+
+func (h *WebHandler) authenticate(class httplib.RequestClass, fn serviceHandler) httprouter.Handle {
 	return func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
 		logger := log.WithFields(fields.FromRequest(r))
 
@@ -1056,8 +1076,11 @@ func (h *WebHandler) needsAuth(fn serviceHandler) httprouter.Handle {
 			user:         authResult.User,
 		}
 
-		if err := fn(w, r, params, context); err != nil {
-			if !trace.IsNotFound(err) && !trace.IsAlreadyExists(err) {
+		err = h.rateLimit(w, r, class, authResult.User, func() error {
+			return fn(w, r, params, context)
+		})
+		if err != nil {
+			if !trace.IsNotFound(err) && !trace.IsAlreadyExists(err) && !trace.IsLimitExceeded(err) {
 				logger.WithError(err).Error("Handler error.")
 			} else {
 				logger.WithError(err).Debug("Handler error.")
@@ -1067,6 +1090,18 @@ func (h *WebHandler) needsAuth(fn serviceHandler) httprouter.Handle {
 	}
 }
 
+// rateLimit invokes fn if the request fits the budgets of the user and the API key.
+// The class is derived from the request method if unspecified.
+func (h *WebHandler) rateLimit(w http.ResponseWriter, r *http.Request, class httplib.RequestClass, user storage.User, fn func() error) error {
+	if h.RateLimiter == nil {
+		return fn()
+	}
+	if class == "" {
+		class = httplib.RequestClassFor(r)
+	}
+	return h.RateLimiter.Limit(w, class, httplib.RequestSubjects(r, user.GetName(), ""), fn)
+}
+
 func importOperation(params httprouter.Params) (*storage.AppOperation, error) {
 	operationID := params[0].Value
 	return &storage.AppOperation{
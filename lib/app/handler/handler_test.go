@@ -33,6 +33,7 @@ import (
 	"github.com/gravitational/gravity/lib/defaults"
 	"github.com/gravitational/gravity/lib/docker"
 	"github.com/gravitational/gravity/lib/helm"
+	"github.com/gravitational/gravity/lib/openapi"
 	"github.com/gravitational/gravity/lib/pack/localpack"
 	"github.com/gravitational/gravity/lib/storage"
 	"github.com/gravitational/gravity/lib/storage/keyval"
@@ -51,8 +52,11 @@ type HandlerSuite struct {
 	backend storage.Backend
 	suite   suite.AppsSuite
 	server  *httptest.Server
-	user    storage.User
-	users   users.Identity
+	// validator checks the requests and the responses
+	// against the specification
+	validator *openapi.Validator
+	user      storage.User
+	users     users.Identity
 
 	dir string
 }
@@ -131,9 +135,13 @@ func (r *HandlerSuite) SetUpTest(c *C) {
 		})
 		c.Assert(err, IsNil)
 
+		spec, err := openapi.Load("../../openapi/openapi.yaml")
+		c.Assert(err, IsNil)
+		r.validator = openapi.NewValidator(spec, openapi.APIApp, handler)
+
 		// It is important that we launch TLS server as authentication
 		// middleware on the handler expects TLS connections.
-		r.server = httptest.NewTLSServer(handler)
+		r.server = httptest.NewTLSServer(r.validator)
 
 		apps, err := client.NewAuthenticatedClient(
 			r.server.URL, r.user.GetName(), "admin-password",
@@ -149,6 +157,9 @@ func (r *HandlerSuite) TearDownTest(c *C) {
 	if r.server != nil {
 		r.server.Close()
 	}
+	if r.validator != nil {
+		c.Assert(r.validator.Error(), IsNil)
+	}
 	if r.backend != nil {
 		c.Assert(r.backend.Close(), IsNil)
 	}
@@ -0,0 +1,104 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autoscale
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gravitational/gravity/lib/constants"
+	"github.com/gravitational/gravity/lib/ops"
+	"github.com/gravitational/gravity/lib/storage"
+
+	"github.com/gravitational/rigging"
+	"github.com/gravitational/trace"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Autoscaler integrates the cluster with a cloud provider's group of
+// automatically scaled instances
+type Autoscaler interface {
+	// ProcessEvents reacts to instance group changes and removes
+	// deleted instances from the cluster until the context is cancelled
+	ProcessEvents(ctx context.Context, operator Operator)
+	// PublishDiscovery periodically publishes the information new instances
+	// need to join the cluster until the context is cancelled
+	PublishDiscovery(ctx context.Context, operator ops.Operator)
+}
+
+// Operator is a simplified operator interface to mock in tests
+type Operator interface {
+	GetLocalSite() (*ops.Site, error)
+	CreateSiteShrinkOperation(context.Context, ops.CreateSiteShrinkOperationRequest) (*ops.SiteOperationKey, error)
+}
+
+// RemoveInstance removes the server with the specified cloud instance ID
+// from the cluster.
+// The server is removed in forced mode as the instance is expected to be
+// offline by the time it is removed
+func RemoveInstance(ctx context.Context, operator Operator, instanceID string) (*storage.Server, error) {
+	cluster, err := operator.GetLocalSite()
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	server, err := ops.FindServerByInstanceID(cluster, instanceID)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	_, err = operator.CreateSiteShrinkOperation(ctx,
+		ops.CreateSiteShrinkOperationRequest{
+			AccountID:   cluster.AccountID,
+			SiteDomain:  cluster.Domain,
+			Servers:     []string{server.Hostname},
+			Force:       true,
+			NodeRemoved: true,
+		})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return server, nil
+}
+
+// GetServiceURL returns the URL of the cluster's gravity-site load balancer
+func GetServiceURL(client kubernetes.Interface) (string, error) {
+	service, err := client.CoreV1().Services(constants.KubeSystemNamespace).Get(constants.GravityServiceName, metav1.GetOptions{})
+	if err != nil {
+		return "", trace.Wrap(rigging.ConvertError(err))
+	}
+	var port int32
+	for _, p := range service.Spec.Ports {
+		if p.Name == constants.GravityServicePortName {
+			port = p.Port
+			break
+		}
+	}
+	if port == 0 {
+		return "", trace.NotFound("no port %q found for service %q", constants.GravityServicePortName, constants.GravityServiceName)
+	}
+	for _, ingress := range service.Status.LoadBalancer.Ingress {
+		if ingress.Hostname != "" {
+			return fmt.Sprintf("https://%v:%v", ingress.Hostname, port), nil
+		}
+		if ingress.IP != "" {
+			return fmt.Sprintf("https://%v:%v", ingress.IP, port), nil
+		}
+	}
+	return "", trace.NotFound("ingress load balancer not found for %v", constants.GravityServiceName)
+}
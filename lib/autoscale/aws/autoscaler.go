@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/gravitational/gravity/lib/autoscale"
 	gaws "github.com/gravitational/gravity/lib/cloudprovider/aws"
 	"github.com/gravitational/gravity/lib/defaults"
 	"github.com/gravitational/gravity/lib/utils"
@@ -93,6 +94,8 @@ func (cfg *Config) CheckAndSetDefaults() error {
 	return nil
 }
 
+var _ autoscale.Autoscaler = (*Autoscaler)(nil)
+
 // New returns new instance of AWS autoscaler
 func New(cfg Config) (*Autoscaler, error) {
 	if err := cfg.CheckAndSetDefaults(); err != nil {
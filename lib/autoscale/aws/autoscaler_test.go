@@ -74,7 +74,8 @@ func (s *AutoscalerSuite) TestInstanceTerminate(c *check.C) {
 			Servers: []storage.Server{server},
 		},
 	})
-	go a.ProcessEvents(ctx, queue.url, op)
+	a.QueueURL = queue.url
+	go a.ProcessEvents(ctx, op)
 
 	// send terminated event
 	msg := &message{
@@ -135,7 +136,8 @@ func (s *AutoscalerSuite) TestInstanceLaunching(c *check.C) {
 		Domain:       "example.com",
 		ClusterState: storage.ClusterState{},
 	})
-	go a.ProcessEvents(ctx, queue.url, op)
+	a.QueueURL = queue.url
+	go a.ProcessEvents(ctx, op)
 
 	// send launched event
 	instanceID := "instance-1"
@@ -18,15 +18,13 @@ package aws
 
 import (
 	"context"
-	"fmt"
 	"time"
 
-	"github.com/gravitational/gravity/lib/constants"
+	"github.com/gravitational/gravity/lib/autoscale"
 	"github.com/gravitational/gravity/lib/defaults"
 	"github.com/gravitational/gravity/lib/ops"
 
 	"github.com/gravitational/trace"
-	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // PublishDiscovery periodically updates discovery information
@@ -85,31 +83,8 @@ func (a *Autoscaler) syncToken(ctx context.Context, operator ops.Operator, clust
 	return nil
 }
 
-func (a *Autoscaler) getServiceURL() (string, error) {
-	service, err := a.Client.CoreV1().Services(constants.KubeSystemNamespace).Get(constants.GravityServiceName, v1.GetOptions{})
-	if err != nil {
-		return "", trace.Wrap(err)
-	}
-	var port int32
-	for _, p := range service.Spec.Ports {
-		if p.Name == constants.GravityServicePortName {
-			port = p.Port
-			break
-		}
-	}
-	if port == 0 {
-		return "", trace.NotFound("no port %q found for service %q", constants.GravityServicePortName, constants.GravityServiceName)
-	}
-	for _, ingress := range service.Status.LoadBalancer.Ingress {
-		if ingress.Hostname != "" {
-			return fmt.Sprintf("https://%v:%v", ingress.Hostname, port), nil
-		}
-	}
-	return "", trace.NotFound("ingress load balancer not found for %v", constants.GravityServiceName)
-}
-
 func (a *Autoscaler) syncMasterService(ctx context.Context, force bool) error {
-	serviceURL, err := a.getServiceURL()
+	serviceURL, err := autoscale.GetServiceURL(a.Client)
 	if err != nil {
 		return trace.Wrap(err)
 	}
@@ -21,7 +21,7 @@ import (
 	"encoding/json"
 	"regexp"
 
-	"github.com/gravitational/gravity/lib/ops"
+	"github.com/gravitational/gravity/lib/autoscale"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
@@ -62,7 +62,8 @@ func (a *Autoscaler) GetQueueURL(ctx context.Context) (string, error) {
 
 // ProcessEvents listens for events on SQS queue that are sent by the auto scaling
 // group lifecycle hooks.
-func (a *Autoscaler) ProcessEvents(ctx context.Context, queueURL string, operator Operator) {
+func (a *Autoscaler) ProcessEvents(ctx context.Context, operator autoscale.Operator) {
+	queueURL := a.QueueURL
 	a.WithField("queue", queueURL).Info("Start processing events.")
 	for {
 		out, err := a.Queue.ReceiveMessageWithContext(ctx, &sqs.ReceiveMessageInput{
@@ -96,7 +97,7 @@ func (a *Autoscaler) ProcessEvents(ctx context.Context, queueURL string, operato
 	}
 }
 
-func (a *Autoscaler) processEvent(ctx context.Context, operator Operator, event HookEvent) error {
+func (a *Autoscaler) processEvent(ctx context.Context, operator autoscale.Operator, event HookEvent) error {
 	a.WithField("event", event).Info("Received autoscale event.")
 	switch event.Type {
 	case InstanceLaunching:
@@ -148,29 +149,11 @@ func (a *Autoscaler) ensureInstanceTerminated(ctx context.Context, event HookEve
 	return nil
 }
 
-func (a *Autoscaler) removeInstance(ctx context.Context, operator Operator, event HookEvent) error {
-	cluster, err := operator.GetLocalSite()
+func (a *Autoscaler) removeInstance(ctx context.Context, operator autoscale.Operator, event HookEvent) error {
+	server, err := autoscale.RemoveInstance(ctx, operator, event.InstanceID)
 	if err != nil {
 		return trace.Wrap(err)
 	}
-
-	server, err := ops.FindServerByInstanceID(cluster, event.InstanceID)
-	if err != nil {
-		return trace.Wrap(err)
-	}
-
-	_, err = operator.CreateSiteShrinkOperation(ctx,
-		ops.CreateSiteShrinkOperationRequest{
-			AccountID:   cluster.AccountID,
-			SiteDomain:  cluster.Domain,
-			Servers:     []string{server.Hostname},
-			Force:       true,
-			NodeRemoved: true,
-		})
-	if err != nil {
-		return trace.Wrap(err)
-	}
-
 	a.Debugf("initiated shrink operation for node %v", server.Hostname)
 	return nil
 }
@@ -0,0 +1,162 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gce
+
+import (
+	"context"
+	"time"
+
+	"github.com/gravitational/gravity/lib/autoscale"
+	"github.com/gravitational/gravity/lib/defaults"
+
+	gcemeta "cloud.google.com/go/compute/metadata"
+	"github.com/gravitational/trace"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// InstanceGroupAttribute is the instance metadata attribute with the
+	// name of the managed instance group to autoscale
+	InstanceGroupAttribute = "gravity-autoscale-group"
+	// JoinTokenAttribute is the instance metadata attribute with the
+	// cluster join token
+	JoinTokenAttribute = "gravity-join-token"
+	// ServiceURLAttribute is the instance metadata attribute with the
+	// cluster service URL
+	ServiceURLAttribute = "gravity-service-url"
+)
+
+// Autoscaler is GCE autoscaler server, it enables instances of a managed
+// instance group to discover cluster information via instance metadata
+// and masters to remove instances from the cluster as they are deleted
+// from the group
+type Autoscaler struct {
+	// Config is Autoscaler config
+	Config
+	*log.Entry
+
+	// instances is the set of active group instances from the last
+	// sync keyed by instance ID.
+	// Only accessed by the ProcessEvents loop
+	instances map[string]ManagedInstance
+	// removed is the set of instances that have been removed from the
+	// cluster but are still listed in the group.
+	// Only accessed by the ProcessEvents loop
+	removed map[string]bool
+	// published maps instance name to the discovery information that
+	// has been published to its metadata.
+	// Only accessed by the PublishDiscovery loop
+	published map[string]discovery
+	// getServiceURL returns the cluster service URL
+	getServiceURL func() (string, error)
+}
+
+// Config is autoscaler config
+type Config struct {
+	// ClusterName is a Gravity cluster name
+	ClusterName string
+	// InstanceGroup identifies the managed instance group to autoscale.
+	// Project and zone default to those of the local instance
+	InstanceGroup InstanceGroup
+	// Client is an optional kubernetes client
+	Client kubernetes.Interface
+	// Compute is Compute Engine API client
+	Compute Compute
+	// PollInterval specifies how often the instance group is polled for changes
+	PollInterval time.Duration
+}
+
+// CheckAndSetDefaults checks and sets default values
+func (cfg *Config) CheckAndSetDefaults() error {
+	if cfg.ClusterName == "" {
+		return trace.BadParameter("missing parameter ClusterName")
+	}
+	if cfg.InstanceGroup.Name == "" {
+		return trace.BadParameter("missing parameter InstanceGroup")
+	}
+	var err error
+	if cfg.InstanceGroup.Project == "" {
+		cfg.InstanceGroup.Project, err = gcemeta.ProjectID()
+		if err != nil {
+			return trace.Wrap(err)
+		}
+	}
+	if cfg.InstanceGroup.Zone == "" {
+		cfg.InstanceGroup.Zone, err = gcemeta.Zone()
+		if err != nil {
+			return trace.Wrap(err)
+		}
+	}
+	if cfg.Compute == nil {
+		cfg.Compute = NewCompute(context.TODO())
+	}
+	if cfg.PollInterval == 0 {
+		cfg.PollInterval = defaults.InstanceGroupPollInterval
+	}
+	return nil
+}
+
+var _ autoscale.Autoscaler = (*Autoscaler)(nil)
+
+// New returns new instance of GCE autoscaler
+func New(cfg Config) (*Autoscaler, error) {
+	if err := cfg.CheckAndSetDefaults(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	a := &Autoscaler{
+		Config: cfg,
+		Entry: log.WithFields(log.Fields{
+			trace.Component: "autoscale",
+			"group":         cfg.InstanceGroup.String(),
+		}),
+		instances: make(map[string]ManagedInstance),
+		removed:   make(map[string]bool),
+		published: make(map[string]discovery),
+	}
+	a.getServiceURL = func() (string, error) {
+		return autoscale.GetServiceURL(a.Client)
+	}
+	return a, nil
+}
+
+// GetInstanceGroup returns the name of the managed instance group to
+// autoscale from the local instance metadata
+func GetInstanceGroup() (string, error) {
+	return getInstanceAttribute(InstanceGroupAttribute)
+}
+
+// GetJoinToken returns the cluster join token from the local instance metadata
+func GetJoinToken() (string, error) {
+	return getInstanceAttribute(JoinTokenAttribute)
+}
+
+// GetServiceURL returns the cluster service URL from the local instance metadata
+func GetServiceURL() (string, error) {
+	return getInstanceAttribute(ServiceURLAttribute)
+}
+
+func getInstanceAttribute(name string) (string, error) {
+	value, err := gcemeta.InstanceAttributeValue(name)
+	if err != nil {
+		if _, ok := err.(gcemeta.NotDefinedError); ok {
+			return "", trace.NotFound("instance metadata attribute %q is not set", name)
+		}
+		return "", trace.Wrap(err)
+	}
+	return value, nil
+}
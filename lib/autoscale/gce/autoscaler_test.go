@@ -0,0 +1,250 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gce
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+
+	"github.com/gravitational/gravity/lib/ops"
+	"github.com/gravitational/gravity/lib/storage"
+
+	"github.com/gravitational/trace"
+	"gopkg.in/check.v1"
+)
+
+func TestAutoscaler(t *testing.T) { check.TestingT(t) }
+
+type AutoscalerSuite struct{}
+
+var _ = check.Suite(&AutoscalerSuite{})
+
+func (s *AutoscalerSuite) TestRemovesDeletedInstances(c *check.C) {
+	compute := newFakeCompute(
+		ManagedInstance{ID: "1", Name: "node-1"},
+		ManagedInstance{ID: "2", Name: "node-2"},
+		ManagedInstance{ID: "3", Name: "node-3"},
+	)
+	a := newTestAutoscaler(c, compute)
+	operator := newMockOperator(
+		storage.Server{InstanceID: "1", Hostname: "node-1"},
+		storage.Server{InstanceID: "2", Hostname: "node-2"},
+		storage.Server{InstanceID: "3", Hostname: "node-3"},
+	)
+	ctx := context.TODO()
+
+	c.Assert(a.syncInstances(ctx, operator), check.IsNil)
+	c.Assert(operator.removed, check.HasLen, 0)
+
+	// node-2 is being deleted and node-3 has disappeared from the group
+	compute.instances = []ManagedInstance{
+		{ID: "1", Name: "node-1"},
+		{ID: "2", Name: "node-2", CurrentAction: ActionDeleting},
+		{Name: "node-4"},
+	}
+	c.Assert(a.syncInstances(ctx, operator), check.IsNil)
+	sort.Strings(operator.removed)
+	c.Assert(operator.removed, check.DeepEquals, []string{"node-2", "node-3"})
+
+	// node-2 is still being deleted and is not removed again
+	c.Assert(a.syncInstances(ctx, operator), check.IsNil)
+	c.Assert(operator.removed, check.HasLen, 2)
+}
+
+func (s *AutoscalerSuite) TestRetriesFailedRemoval(c *check.C) {
+	compute := newFakeCompute(ManagedInstance{ID: "1", Name: "node-1"})
+	a := newTestAutoscaler(c, compute)
+	operator := newMockOperator(storage.Server{InstanceID: "1", Hostname: "node-1"})
+	ctx := context.TODO()
+
+	c.Assert(a.syncInstances(ctx, operator), check.IsNil)
+	compute.instances = nil
+	operator.err = trace.CompareFailed("another operation is in progress")
+	c.Assert(a.syncInstances(ctx, operator), check.NotNil)
+
+	operator.err = nil
+	c.Assert(a.syncInstances(ctx, operator), check.IsNil)
+	c.Assert(operator.removed, check.DeepEquals, []string{"node-1"})
+}
+
+func (s *AutoscalerSuite) TestPublishesDiscovery(c *check.C) {
+	compute := newFakeCompute(
+		ManagedInstance{ID: "1", Name: "node-1"},
+		ManagedInstance{ID: "2", Name: "node-2", CurrentAction: ActionDeleting},
+	)
+	a := newTestAutoscaler(c, compute)
+	operator := newMockOperator()
+	ctx := context.TODO()
+
+	c.Assert(a.syncDiscovery(ctx, operator), check.IsNil)
+	c.Assert(compute.metadata, check.DeepEquals, map[string]map[string]string{
+		"node-1": {
+			JoinTokenAttribute:  "token-1",
+			ServiceURLAttribute: "https://10.0.0.1:3009",
+		},
+	})
+
+	// Discovery is only published to instances without up-to-date information
+	compute.instances = append(compute.instances, ManagedInstance{Name: "node-3"})
+	compute.updates = 0
+	c.Assert(a.syncDiscovery(ctx, operator), check.IsNil)
+	c.Assert(compute.updates, check.Equals, 1)
+
+	operator.token = "token-2"
+	compute.updates = 0
+	c.Assert(a.syncDiscovery(ctx, operator), check.IsNil)
+	c.Assert(compute.updates, check.Equals, 2)
+	c.Assert(compute.metadata["node-1"][JoinTokenAttribute], check.Equals, "token-2")
+}
+
+func (s *AutoscalerSuite) TestComputeClient(c *check.C) {
+	var metadataUpdate metadata
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/projects/p/zones/z/instanceGroupManagers/g/listManagedInstances":
+			if r.URL.Query().Get("pageToken") == "" {
+				writeJSON(w, `{"managedInstances": [{"instance": "https://compute/projects/p/zones/z/instances/node-1", "id": "1001", "currentAction": "NONE"}], "nextPageToken": "next"}`)
+				return
+			}
+			writeJSON(w, `{"managedInstances": [{"instance": "https://compute/projects/p/zones/z/instances/node-2", "currentAction": "CREATING"}]}`)
+		case "/projects/p/zones/z/instances/node-1":
+			writeJSON(w, `{"metadata": {"fingerprint": "abc", "items": [{"key": "startup-script", "value": "echo"}, {"key": "gravity-join-token", "value": "old"}]}}`)
+		case "/projects/p/zones/z/instances/node-1/setMetadata":
+			json.NewDecoder(r.Body).Decode(&metadataUpdate)
+			writeJSON(w, `{}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			writeJSON(w, `{"error": {"code": 404, "message": "not found"}}`)
+		}
+	}))
+	defer server.Close()
+	client := &computeClient{Client: server.Client(), baseURL: server.URL}
+	group := InstanceGroup{Project: "p", Zone: "z", Name: "g"}
+	ctx := context.TODO()
+
+	instances, err := client.ListManagedInstances(ctx, group)
+	c.Assert(err, check.IsNil)
+	c.Assert(instances, check.DeepEquals, []ManagedInstance{
+		{ID: "1001", Name: "node-1", CurrentAction: "NONE"},
+		{Name: "node-2", CurrentAction: "CREATING"},
+	})
+
+	err = client.SetInstanceMetadata(ctx, group, "node-1", map[string]string{JoinTokenAttribute: "new"})
+	c.Assert(err, check.IsNil)
+	c.Assert(metadataUpdate, check.DeepEquals, metadata{
+		Fingerprint: "abc",
+		Items: []metadataItem{
+			{Key: "startup-script", Value: "echo"},
+			{Key: JoinTokenAttribute, Value: "new"},
+		},
+	})
+
+	_, err = client.ListManagedInstances(ctx, InstanceGroup{Project: "p", Zone: "z", Name: "missing"})
+	c.Assert(trace.IsNotFound(err), check.Equals, true, check.Commentf("%v", err))
+}
+
+func newTestAutoscaler(c *check.C, compute *fakeCompute) *Autoscaler {
+	a, err := New(Config{
+		ClusterName:   "example.com",
+		InstanceGroup: InstanceGroup{Project: "p", Zone: "z", Name: "g"},
+		Compute:       compute,
+	})
+	c.Assert(err, check.IsNil)
+	a.getServiceURL = func() (string, error) {
+		return "https://10.0.0.1:3009", nil
+	}
+	return a
+}
+
+func writeJSON(w http.ResponseWriter, data string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(data))
+}
+
+func newFakeCompute(instances ...ManagedInstance) *fakeCompute {
+	return &fakeCompute{
+		instances: instances,
+		metadata:  make(map[string]map[string]string),
+	}
+}
+
+type fakeCompute struct {
+	instances []ManagedInstance
+	metadata  map[string]map[string]string
+	updates   int
+}
+
+func (r *fakeCompute) ListManagedInstances(ctx context.Context, group InstanceGroup) ([]ManagedInstance, error) {
+	return r.instances, nil
+}
+
+func (r *fakeCompute) SetInstanceMetadata(ctx context.Context, group InstanceGroup, name string, items map[string]string) error {
+	if r.metadata[name] == nil {
+		r.metadata[name] = make(map[string]string)
+	}
+	for key, value := range items {
+		r.metadata[name][key] = value
+	}
+	r.updates++
+	return nil
+}
+
+func newMockOperator(servers ...storage.Server) *mockOperator {
+	return &mockOperator{
+		site: ops.Site{
+			AccountID: "1",
+			Domain:    "example.com",
+			ClusterState: storage.ClusterState{
+				Servers: servers,
+			},
+		},
+		token: "token-1",
+	}
+}
+
+// mockOperator implements the subset of the operator used by the autoscaler
+type mockOperator struct {
+	ops.Operator
+	site    ops.Site
+	token   string
+	err     error
+	removed []string
+}
+
+func (o *mockOperator) GetLocalSite() (*ops.Site, error) {
+	return &o.site, nil
+}
+
+func (o *mockOperator) GetExpandToken(ops.SiteKey) (*storage.ProvisioningToken, error) {
+	return &storage.ProvisioningToken{Token: o.token}, nil
+}
+
+func (o *mockOperator) CreateSiteShrinkOperation(ctx context.Context, req ops.CreateSiteShrinkOperationRequest) (*ops.SiteOperationKey, error) {
+	if o.err != nil {
+		return nil, o.err
+	}
+	o.removed = append(o.removed, req.Servers...)
+	return &ops.SiteOperationKey{
+		AccountID:   o.site.AccountID,
+		SiteDomain:  o.site.Domain,
+		OperationID: "op-1",
+	}, nil
+}
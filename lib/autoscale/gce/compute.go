@@ -0,0 +1,255 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gce
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+
+	"github.com/gravitational/trace"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+const (
+	// ActionDeleting is the current action of a managed instance being deleted
+	ActionDeleting = "DELETING"
+	// ActionAbandoning is the current action of a managed instance being
+	// removed from the group
+	ActionAbandoning = "ABANDONING"
+	// computeAPIURL is the base URL of the Compute Engine API
+	computeAPIURL = "https://www.googleapis.com/compute/v1"
+)
+
+// Compute is a subset of Compute Engine API used by the autoscaler
+type Compute interface {
+	// ListManagedInstances returns instances of the specified managed instance group
+	ListManagedInstances(ctx context.Context, group InstanceGroup) ([]ManagedInstance, error)
+	// SetInstanceMetadata adds or updates the specified metadata items
+	// on the instance with the given name from the specified group
+	SetInstanceMetadata(ctx context.Context, group InstanceGroup, name string, items map[string]string) error
+}
+
+// InstanceGroup identifies a zonal managed instance group
+type InstanceGroup struct {
+	// Project is the project ID
+	Project string
+	// Zone is the group's zone
+	Zone string
+	// Name is the group name
+	Name string
+}
+
+// String returns a textual representation of this group
+func (r InstanceGroup) String() string {
+	return fmt.Sprintf("%v/%v/%v", r.Project, r.Zone, r.Name)
+}
+
+// ManagedInstance describes an instance of a managed instance group
+type ManagedInstance struct {
+	// ID is the numeric instance ID.
+	// Empty if the instance has not been created yet
+	ID string
+	// Name is the instance name
+	Name string
+	// CurrentAction is the action the group manager is performing on the instance
+	CurrentAction string
+}
+
+// IsBeingRemoved returns true if the instance is being removed from the group
+func (r ManagedInstance) IsBeingRemoved() bool {
+	return r.CurrentAction == ActionDeleting || r.CurrentAction == ActionAbandoning
+}
+
+// NewCompute returns a new Compute Engine API client authenticated
+// with the credentials of the instance's service account
+func NewCompute(ctx context.Context) Compute {
+	return &computeClient{
+		Client:  oauth2.NewClient(ctx, google.ComputeTokenSource("")),
+		baseURL: computeAPIURL,
+	}
+}
+
+type computeClient struct {
+	*http.Client
+	baseURL string
+}
+
+// ListManagedInstances returns instances of the specified managed instance group
+func (r *computeClient) ListManagedInstances(ctx context.Context, group InstanceGroup) (instances []ManagedInstance, err error) {
+	var pageToken string
+	for {
+		query := url.Values{}
+		if pageToken != "" {
+			query.Set("pageToken", pageToken)
+		}
+		var resp listManagedInstancesResponse
+		err := r.do(ctx, http.MethodPost,
+			r.url(group.Project, "zones", group.Zone, "instanceGroupManagers", group.Name, "listManagedInstances"),
+			query, nil, &resp)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		for _, instance := range resp.ManagedInstances {
+			var id string
+			if instance.ID != 0 {
+				id = strconv.FormatUint(instance.ID, 10)
+			}
+			instances = append(instances, ManagedInstance{
+				ID:            id,
+				Name:          path.Base(instance.Instance),
+				CurrentAction: instance.CurrentAction,
+			})
+		}
+		if resp.NextPageToken == "" {
+			return instances, nil
+		}
+		pageToken = resp.NextPageToken
+	}
+}
+
+// SetInstanceMetadata adds or updates the specified metadata items
+// on the instance with the given name from the specified group
+func (r *computeClient) SetInstanceMetadata(ctx context.Context, group InstanceGroup, name string, items map[string]string) error {
+	instanceURL := r.url(group.Project, "zones", group.Zone, "instances", name)
+	var instance instance
+	err := r.do(ctx, http.MethodGet, instanceURL, nil, nil, &instance)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	// The fingerprint of the existing metadata is required to update it
+	metadata := instance.Metadata
+	for key, value := range items {
+		metadata.set(key, value)
+	}
+	return trace.Wrap(r.do(ctx, http.MethodPost, instanceURL+"/setMetadata", nil, metadata, nil))
+}
+
+func (r *computeClient) url(project string, parts ...string) string {
+	return fmt.Sprintf("%v/projects/%v/%v", r.baseURL, project, path.Join(parts...))
+}
+
+func (r *computeClient) do(ctx context.Context, method, url string, query url.Values, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		body = bytes.NewReader(data)
+	}
+	if len(query) != 0 {
+		url = fmt.Sprintf("%v?%v", url, query.Encode())
+	}
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	req = req.WithContext(ctx)
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := r.Do(req)
+	if err != nil {
+		return trace.ConvertSystemError(err)
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return trace.ConvertSystemError(err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return trace.Wrap(convertError(resp.StatusCode, data))
+	}
+	if out == nil {
+		return nil
+	}
+	return trace.Wrap(json.Unmarshal(data, out))
+}
+
+// convertError converts the Compute Engine API error response to a trace error
+func convertError(code int, data []byte) error {
+	var resp errorResponse
+	message := string(data)
+	if err := json.Unmarshal(data, &resp); err == nil && resp.Error.Message != "" {
+		message = resp.Error.Message
+	}
+	switch code {
+	case http.StatusNotFound:
+		return trace.NotFound("%v", message)
+	case http.StatusForbidden, http.StatusUnauthorized:
+		return trace.AccessDenied("%v", message)
+	case http.StatusConflict, http.StatusPreconditionFailed:
+		return trace.CompareFailed("%v", message)
+	case http.StatusTooManyRequests:
+		return trace.LimitExceeded("%v", message)
+	case http.StatusBadRequest:
+		return trace.BadParameter("%v", message)
+	}
+	return trace.Errorf("compute API error (%v): %v", code, message)
+}
+
+type listManagedInstancesResponse struct {
+	ManagedInstances []managedInstance `json:"managedInstances"`
+	NextPageToken    string            `json:"nextPageToken"`
+}
+
+type managedInstance struct {
+	// Instance is the instance URL
+	Instance      string `json:"instance"`
+	ID            uint64 `json:"id,string"`
+	CurrentAction string `json:"currentAction"`
+}
+
+type instance struct {
+	Metadata metadata `json:"metadata"`
+}
+
+type metadata struct {
+	Fingerprint string         `json:"fingerprint"`
+	Items       []metadataItem `json:"items,omitempty"`
+}
+
+func (r *metadata) set(key, value string) {
+	for i := range r.Items {
+		if r.Items[i].Key == key {
+			r.Items[i].Value = value
+			return
+		}
+	}
+	r.Items = append(r.Items, metadataItem{Key: key, Value: value})
+}
+
+type metadataItem struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type errorResponse struct {
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gce
+
+import (
+	"context"
+	"time"
+
+	"github.com/gravitational/gravity/lib/defaults"
+	"github.com/gravitational/gravity/lib/ops"
+
+	"github.com/gravitational/trace"
+)
+
+// PublishDiscovery periodically publishes discovery information
+// to the metadata of the instances in the group
+func (a *Autoscaler) PublishDiscovery(ctx context.Context, operator ops.Operator) {
+	a.Info("Start publishing discovery info.")
+	ticker := time.NewTicker(a.PollInterval)
+	defer ticker.Stop()
+	resyncTicker := time.NewTicker(defaults.DiscoveryResyncInterval)
+	defer resyncTicker.Stop()
+	for {
+		if err := a.syncDiscovery(ctx, operator); err != nil {
+			a.Errorf("Failed to publish discovery: %v.", trace.DebugReport(err))
+		}
+		select {
+		case <-ctx.Done():
+			a.Info("Stop publishing discovery info.")
+			return
+		case <-ticker.C:
+		case <-resyncTicker.C:
+			// Force publishing to all instances in case
+			// the metadata has been modified externally
+			a.published = make(map[string]discovery)
+		}
+	}
+}
+
+// discovery is the cluster discovery information published to instances
+type discovery struct {
+	// token is the cluster join token
+	token string
+	// serviceURL is the cluster service URL
+	serviceURL string
+}
+
+// syncDiscovery publishes cluster discovery information to the instances
+// that do not have the up-to-date information yet
+func (a *Autoscaler) syncDiscovery(ctx context.Context, operator ops.Operator) error {
+	cluster, err := operator.GetLocalSite()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	joinToken, err := operator.GetExpandToken(cluster.Key())
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	serviceURL, err := a.getServiceURL()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	info := discovery{token: joinToken.Token, serviceURL: serviceURL}
+	instances, err := a.Compute.ListManagedInstances(ctx, a.InstanceGroup)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	listed := make(map[string]bool)
+	var errors []error
+	for _, instance := range instances {
+		listed[instance.Name] = true
+		if instance.IsBeingRemoved() || a.published[instance.Name] == info {
+			continue
+		}
+		err := a.Compute.SetInstanceMetadata(ctx, a.InstanceGroup, instance.Name, map[string]string{
+			JoinTokenAttribute:  info.token,
+			ServiceURLAttribute: info.serviceURL,
+		})
+		if err != nil {
+			// The instance might not have been created yet
+			errors = append(errors, trace.Wrap(err, "failed to publish discovery to %v", instance.Name))
+			continue
+		}
+		a.WithField("instance", instance.Name).Debug("Published discovery info.")
+		a.published[instance.Name] = info
+	}
+	for name := range a.published {
+		if !listed[name] {
+			delete(a.published, name)
+		}
+	}
+	return trace.NewAggregate(errors...)
+}
@@ -0,0 +1,44 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/* package gce implements autoscaling integration for GCE cloud provider
+
+Design
+------
+
+* Autoscaler runs on master nodes and watches the managed instance group
+  whose name is set in the gravity-autoscale-group attribute of the master
+  instance's metadata
+* Autoscaler publishes the Gravity load balancer service address and the
+  join token as metadata attributes of every instance in the group
+* Instances started up as a part of the managed instance group discover the
+  cluster by reading the attributes from their own metadata server and join
+  the cluster
+* Autoscaler periodically lists the instances of the group and removes the
+  instances that are being deleted or have disappeared from the group from
+  the cluster in forced mode (as the instance is offline by the time it is
+  removed)
+
+Compute Engine API access is abstracted behind the Compute interface which
+is implemented on top of the Compute Engine REST API using the credentials
+of the instance service account.
+
+Note that instance metadata is readable by anyone with read access to the
+instances in the project, so the join token is only as protected as
+the project itself.
+
+*/
+package gce
@@ -0,0 +1,115 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gce
+
+import (
+	"context"
+	"time"
+
+	"github.com/gravitational/gravity/lib/autoscale"
+
+	"github.com/gravitational/trace"
+)
+
+// ProcessEvents periodically polls the managed instance group and removes
+// the instances deleted from the group from the cluster
+func (a *Autoscaler) ProcessEvents(ctx context.Context, operator autoscale.Operator) {
+	a.Info("Start processing events.")
+	ticker := time.NewTicker(a.PollInterval)
+	defer ticker.Stop()
+	for {
+		if err := a.syncInstances(ctx, operator); err != nil {
+			a.Errorf("Failed to sync instance group: %v.", trace.DebugReport(err))
+		}
+		select {
+		case <-ctx.Done():
+			a.Info("Stop processing events.")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// syncInstances removes instances that are being deleted from the group
+// or have disappeared from the group since the last sync from the cluster.
+//
+// Instances deleted from the group while the autoscaler was not running
+// are not detected
+func (a *Autoscaler) syncInstances(ctx context.Context, operator autoscale.Operator) error {
+	instances, err := a.Compute.ListManagedInstances(ctx, a.InstanceGroup)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	active := make(map[string]ManagedInstance)
+	// removed is the set of instances to remove from the cluster
+	removed := make(map[string]bool)
+	// listed is the set of all instances still listed in the group
+	listed := make(map[string]bool)
+	for _, instance := range instances {
+		if instance.ID == "" {
+			// Instance has not been created yet
+			continue
+		}
+		listed[instance.ID] = true
+		if instance.IsBeingRemoved() {
+			removed[instance.ID] = true
+			continue
+		}
+		active[instance.ID] = instance
+	}
+	for id := range a.instances {
+		if _, ok := active[id]; !ok {
+			removed[id] = true
+		}
+	}
+	var errors []error
+	for id := range removed {
+		if a.removed[id] {
+			continue
+		}
+		if err := a.removeInstance(ctx, operator, id); err != nil {
+			errors = append(errors, err)
+			// Retry on the next sync
+			if instance, ok := a.instances[id]; ok && !listed[id] {
+				active[id] = instance
+			}
+			continue
+		}
+		a.removed[id] = true
+	}
+	for id := range a.removed {
+		if !listed[id] {
+			delete(a.removed, id)
+		}
+	}
+	a.instances = active
+	return trace.NewAggregate(errors...)
+}
+
+func (a *Autoscaler) removeInstance(ctx context.Context, operator autoscale.Operator, instanceID string) error {
+	log := a.WithField("instance", instanceID)
+	server, err := autoscale.RemoveInstance(ctx, operator, instanceID)
+	if err != nil {
+		if trace.IsNotFound(err) {
+			log.Info("Instance is not a cluster member.")
+			return nil
+		}
+		return trace.Wrap(err)
+	}
+	log.Infof("Initiated shrink operation for node %v.", server.Hostname)
+	return nil
+}
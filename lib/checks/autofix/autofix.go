@@ -19,9 +19,13 @@ package autofix
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
 	"sort"
 	"strings"
 
+	"github.com/gravitational/gravity/lib/checks/host"
 	"github.com/gravitational/gravity/lib/defaults"
 	"github.com/gravitational/gravity/lib/utils"
 
@@ -33,26 +37,75 @@ import (
 
 // Fix takes a list of failed probes and attempts to fix some of them
 func Fix(ctx context.Context, probes []*agentpb.Probe, progress utils.Progress) (fixed, unfixed []*agentpb.Probe) {
-	// reorder the probes so "kernel module" ones go before "sysctl parameter"
-	// ones because some kernel parameters cannot be set unless a certain
-	// module is loaded, so they have to be fixed in order
-	sort.Slice(probes, func(i, j int) bool {
-		return probes[i].Checker == monitoring.KernelModuleCheckerID
-	})
+	plans, unfixed := PlanFixes(probes)
+	for _, plan := range plans {
+		if err := plan.Apply(ctx, progress); err != nil {
+			logrus.Debugf("Failed to auto-fix probe %#v: %v", *plan.Probe, err)
+			unfixed = append(unfixed, plan.Probe)
+		} else {
+			fixed = append(fixed, plan.Probe)
+		}
+	}
+	return fixed, unfixed
+}
+
+// PlanFixes computes the changes required to fix the specified failed probes
+// without applying them.
+// Returns the list of fix plans and the list of probes that cannot be fixed
+func PlanFixes(probes []*agentpb.Probe) (plans []Plan, unfixable []*agentpb.Probe) {
 	for _, probe := range probes {
 		// we should only have gotten failed probes here but in case we got
 		// something else, skip it
 		if probe.Status != agentpb.Probe_Failed {
 			continue
 		}
-		if err := fixProbe(ctx, probe, progress); err != nil {
-			logrus.Debugf("Failed to auto-fix probe %#v: %v", *probe, err)
-			unfixed = append(unfixed, probe)
-		} else {
-			fixed = append(fixed, probe)
+		actions, err := planProbe(probe)
+		if err != nil {
+			logrus.Debugf("Probe %#v cannot be auto-fixed: %v", *probe, err)
+			unfixable = append(unfixable, probe)
+			continue
 		}
+		plans = append(plans, Plan{Probe: probe, Actions: actions})
 	}
-	return fixed, unfixed
+	// reorder the plans so "kernel module" ones go before "sysctl parameter"
+	// ones because some kernel parameters cannot be set unless a certain
+	// module is loaded, so they have to be fixed in order
+	sort.SliceStable(plans, func(i, j int) bool {
+		return plans[i].Probe.Checker == monitoring.KernelModuleCheckerID &&
+			plans[j].Probe.Checker != monitoring.KernelModuleCheckerID
+	})
+	return plans, unfixable
+}
+
+// Plan describes the changes required to fix a failed probe
+type Plan struct {
+	// Probe is the failed probe
+	Probe *agentpb.Probe
+	// Actions lists the changes to apply in order
+	Actions []Action
+}
+
+// Apply applies the plan's actions in order
+func (r Plan) Apply(ctx context.Context, progress utils.Progress) error {
+	for _, action := range r.Actions {
+		if err := action.apply(ctx, progress); err != nil {
+			return trace.Wrap(err)
+		}
+	}
+	return nil
+}
+
+// Action describes a single change to the node
+type Action struct {
+	// Description is the human-readable description of the change
+	Description string
+	// apply makes the change
+	apply func(context.Context, utils.Progress) error
+}
+
+// String returns the description of this action
+func (r Action) String() string {
+	return r.Description
 }
 
 // AutoloadModules generates a systemd modules-load.d file for all kernel modules required by gravity.
@@ -76,48 +129,180 @@ func AutoloadModules(ctx context.Context, modules []monitoring.ModuleRequest, pr
 
 // GetFixable returns a list of failed probes that can be attempted to auto-fix
 func GetFixable(probes []*agentpb.Probe) (failed, fixable []*agentpb.Probe) {
+	plans, failed := PlanFixes(probes)
+	for _, plan := range plans {
+		fixable = append(fixable, plan.Probe)
+	}
+	return failed, fixable
+}
+
+// SplitHostProbes splits the provided probes into node probes and probes
+// of host environment checkers
+func SplitHostProbes(probes []*agentpb.Probe) (node, hostProbes []*agentpb.Probe) {
 	for _, probe := range probes {
-		// we should only have gotten failed probes here but in case we got
-		// something else, skip it
-		if probe.Status == agentpb.Probe_Failed {
-			switch probe.Checker {
-			case monitoring.KernelModuleCheckerID, monitoring.IPForwardCheckerID, monitoring.NetfilterCheckerID, monitoring.MountsCheckerID:
-				fixable = append(fixable, probe)
-			default:
-				failed = append(failed, probe)
-			}
+		if host.IsHostChecker(probe.Checker) {
+			hostProbes = append(hostProbes, probe)
+		} else {
+			node = append(node, probe)
 		}
 	}
-	return failed, fixable
+	return node, hostProbes
 }
 
-// fixProbe attempts to fix the provided failed probe
-func fixProbe(ctx context.Context, probe *agentpb.Probe, progress utils.Progress) error {
+// planProbe returns the list of actions to fix the provided failed probe
+func planProbe(probe *agentpb.Probe) ([]Action, error) {
 	switch probe.Checker {
 	case monitoring.KernelModuleCheckerID:
 		var data monitoring.KernelModuleCheckerData
-		if err := json.Unmarshal(probe.CheckerData, &data); err != nil {
-			return trace.Wrap(err)
+		if err := unmarshalData(probe, &data); err != nil {
+			return nil, trace.Wrap(err)
 		}
 		if data.Module.Name == "" {
-			return trace.BadParameter("empty probe data: %#v", data)
-		}
-		if err := modprobe(ctx, data.Module.Name, data.Module.Names, progress); err != nil {
-			return trace.Wrap(err)
+			return nil, trace.BadParameter("empty probe data: %#v", data)
 		}
+		return []Action{{
+			Description: fmt.Sprintf("Load kernel module %v", data.Module.Name),
+			apply: func(ctx context.Context, progress utils.Progress) error {
+				return modprobe(ctx, data.Module.Name, data.Module.Names, progress)
+			},
+		}}, nil
 	case monitoring.IPForwardCheckerID, monitoring.NetfilterCheckerID, monitoring.MountsCheckerID:
 		var data monitoring.SysctlCheckerData
-		if err := json.Unmarshal(probe.CheckerData, &data); err != nil {
-			return trace.Wrap(err)
+		if err := unmarshalData(probe, &data); err != nil {
+			return nil, trace.Wrap(err)
 		}
 		if data.ParameterName == "" || data.ParameterValue == "" {
-			return trace.BadParameter("empty probe data: %#v", data)
+			return nil, trace.BadParameter("empty probe data: %#v", data)
 		}
-		if err := setSysctlParameter(ctx, data.ParameterName, data.ParameterValue, progress); err != nil {
-			return trace.Wrap(err)
+		return []Action{{
+			Description: fmt.Sprintf("Set kernel parameter %v=%v and persist it in %v",
+				data.ParameterName, data.ParameterValue, defaults.SysctlPath),
+			apply: func(ctx context.Context, progress utils.Progress) error {
+				return setSysctlParameter(ctx, data.ParameterName, data.ParameterValue, progress)
+			},
+		}}, nil
+	case host.ServiceCheckerID:
+		var data host.ServiceCheckerData
+		if err := unmarshalData(probe, &data); err != nil {
+			return nil, trace.Wrap(err)
+		}
+		if data.Service == "" {
+			return nil, trace.BadParameter("empty probe data: %#v", data)
+		}
+		return []Action{{
+			Description: fmt.Sprintf("Stop and disable service %v", data.Service),
+			apply: func(ctx context.Context, progress utils.Progress) error {
+				return disableService(ctx, data.Service, progress)
+			},
+		}}, nil
+	case host.SwapCheckerID:
+		var data host.SwapCheckerData
+		if err := unmarshalData(probe, &data); err != nil {
+			return nil, trace.Wrap(err)
+		}
+		return planDisableSwap(data)
+	case host.SELinuxBooleanCheckerID:
+		var data host.SELinuxBooleanCheckerData
+		if err := unmarshalData(probe, &data); err != nil {
+			return nil, trace.Wrap(err)
+		}
+		if data.Name == "" {
+			return nil, trace.BadParameter("empty probe data: %#v", data)
 		}
+		return []Action{{
+			Description: fmt.Sprintf("Set SELinux boolean %v to %v persistently",
+				data.Name, formatBoolean(data.Value)),
+			apply: func(ctx context.Context, progress utils.Progress) error {
+				return setSELinuxBoolean(ctx, data.Name, data.Value, progress)
+			},
+		}}, nil
+	case host.HostsCheckerID:
+		var data host.HostsCheckerData
+		if err := unmarshalData(probe, &data); err != nil {
+			return nil, trace.Wrap(err)
+		}
+		if data.Hostname == "" || data.Addr == "" {
+			return nil, trace.BadParameter("no address to map hostname %q to", data.Hostname)
+		}
+		entry := fmt.Sprintf("%v %v", data.Addr, data.Hostname)
+		return []Action{{
+			Description: fmt.Sprintf("Add %q to %v", entry, defaults.HostsPath),
+			apply: func(ctx context.Context, progress utils.Progress) error {
+				return addHostsEntry(entry, progress)
+			},
+		}}, nil
+	case host.TimeSyncCheckerID:
+		var data host.TimeSyncCheckerData
+		if err := unmarshalData(probe, &data); err != nil {
+			return nil, trace.Wrap(err)
+		}
+		if data.NTPEnabled {
+			return nil, trace.BadParameter("network time synchronization is already enabled")
+		}
+		return []Action{{
+			Description: "Enable network time synchronization",
+			apply:       enableTimeSync,
+		}}, nil
+	case host.HugepageCheckerID:
+		var data host.HugepageCheckerData
+		if err := unmarshalData(probe, &data); err != nil {
+			return nil, trace.Wrap(err)
+		}
+		if data.Expected == "" {
+			return nil, trace.BadParameter("empty probe data: %#v", data)
+		}
+		return []Action{
+			{
+				Description: fmt.Sprintf("Set transparent hugepages to %q", data.Expected),
+				apply: func(ctx context.Context, progress utils.Progress) error {
+					return setHugepages(data.Expected, progress)
+				},
+			},
+			{
+				Description: fmt.Sprintf("Persist transparent hugepages setting in %v",
+					defaults.TransparentHugepageTmpfilesPath),
+				apply: func(ctx context.Context, progress utils.Progress) error {
+					return persistHugepages(data.Expected)
+				},
+			},
+		}, nil
 	default:
-		return trace.NotImplemented("probe %v can't be auto-fixed", probe.Checker)
+		return nil, trace.NotImplemented("probe %v can't be auto-fixed", probe.Checker)
+	}
+}
+
+// planDisableSwap returns the actions to turn off the active swap areas
+// and to prevent them from being activated on boot
+func planDisableSwap(data host.SwapCheckerData) ([]Action, error) {
+	if len(data.Devices) == 0 {
+		return nil, trace.BadParameter("empty probe data: %#v", data)
+	}
+	actions := []Action{{
+		Description: fmt.Sprintf("Turn off swap on %v", strings.Join(data.Devices, ", ")),
+		apply: func(ctx context.Context, progress utils.Progress) error {
+			return swapoff(ctx, data.Devices, progress)
+		},
+	}}
+	fstab, err := ioutil.ReadFile(defaults.FstabPath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, trace.ConvertSystemError(err)
+	}
+	_, entries := host.CommentOutSwap(fstab)
+	for _, entry := range entries {
+		entry := entry
+		actions = append(actions, Action{
+			Description: fmt.Sprintf("Comment out %q in %v", entry, defaults.FstabPath),
+			apply: func(ctx context.Context, progress utils.Progress) error {
+				return commentOutLine(defaults.FstabPath, entry)
+			},
+		})
+	}
+	return actions, nil
+}
+
+func unmarshalData(probe *agentpb.Probe, data interface{}) error {
+	if err := json.Unmarshal(probe.CheckerData, data); err != nil {
+		return trace.Wrap(err)
 	}
 	return nil
 }
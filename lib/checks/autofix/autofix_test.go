@@ -0,0 +1,90 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autofix
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gravitational/gravity/lib/checks/host"
+
+	"github.com/gravitational/satellite/agent/proto/agentpb"
+	"github.com/gravitational/satellite/monitoring"
+	"gopkg.in/check.v1"
+)
+
+func TestAutofix(t *testing.T) { check.TestingT(t) }
+
+type AutofixSuite struct{}
+
+var _ = check.Suite(&AutofixSuite{})
+
+func (*AutofixSuite) TestPlansFixes(c *check.C) {
+	sysctl := newProbe(c, monitoring.IPForwardCheckerID, monitoring.SysctlCheckerData{
+		ParameterName:  "net.ipv4.ip_forward",
+		ParameterValue: "1",
+	})
+	module := newProbe(c, monitoring.KernelModuleCheckerID, monitoring.KernelModuleCheckerData{
+		Module: monitoring.ModuleRequest{Name: "br_netfilter"},
+	})
+	service := newProbe(c, host.ServiceCheckerID, host.ServiceCheckerData{Service: "firewalld"})
+	// no address to map the hostname to
+	hosts := newProbe(c, host.HostsCheckerID, host.HostsCheckerData{Hostname: "node-1"})
+	unknown := &agentpb.Probe{Checker: "unknown", Status: agentpb.Probe_Failed}
+
+	plans, unfixable := PlanFixes([]*agentpb.Probe{sysctl, service, hosts, module, unknown})
+	c.Assert(unfixable, check.DeepEquals, []*agentpb.Probe{hosts, unknown})
+	c.Assert(describe(plans), check.DeepEquals, [][]string{
+		{"Load kernel module br_netfilter"},
+		{"Set kernel parameter net.ipv4.ip_forward=1 and persist it in /etc/sysctl.d/50-gravity.conf"},
+		{"Stop and disable service firewalld"},
+	})
+}
+
+func (*AutofixSuite) TestSplitsHostProbes(c *check.C) {
+	sysctl := newProbe(c, monitoring.IPForwardCheckerID, monitoring.SysctlCheckerData{
+		ParameterName:  "net.ipv4.ip_forward",
+		ParameterValue: "1",
+	})
+	service := newProbe(c, host.ServiceCheckerID, host.ServiceCheckerData{Service: "firewalld"})
+	swap := newProbe(c, host.SwapCheckerID, host.SwapCheckerData{})
+
+	node, hostProbes := SplitHostProbes([]*agentpb.Probe{service, sysctl, swap})
+	c.Assert(node, check.DeepEquals, []*agentpb.Probe{sysctl})
+	c.Assert(hostProbes, check.DeepEquals, []*agentpb.Probe{service, swap})
+}
+
+func newProbe(c *check.C, checker string, data interface{}) *agentpb.Probe {
+	bytes, err := json.Marshal(data)
+	c.Assert(err, check.IsNil)
+	return &agentpb.Probe{
+		Checker:     checker,
+		Status:      agentpb.Probe_Failed,
+		CheckerData: bytes,
+	}
+}
+
+func describe(plans []Plan) (result [][]string) {
+	for _, plan := range plans {
+		var actions []string
+		for _, action := range plan.Actions {
+			actions = append(actions, action.String())
+		}
+		result = append(result, actions)
+	}
+	return result
+}
@@ -19,6 +19,9 @@ package autofix
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
 
 	"github.com/gravitational/gravity/lib/defaults"
 	"github.com/gravitational/gravity/lib/utils"
@@ -54,3 +57,108 @@ func setSysctlParameter(ctx context.Context, name, value string, progress utils.
 	}
 	return nil
 }
+
+// disableService stops the specified systemd service and prevents it
+// from starting on boot
+func disableService(ctx context.Context, service string, progress utils.Progress) error {
+	out, err := utils.RunCommand(ctx, nil, "systemctl", "disable", "--now", service)
+	if err != nil {
+		return trace.Wrap(err, "failed to disable service %v: %s", service, out)
+	}
+	progress.PrintInfo("Auto-disabled service: %v", service)
+	return nil
+}
+
+// swapoff turns off swap on the specified devices
+func swapoff(ctx context.Context, devices []string, progress utils.Progress) error {
+	args := append([]string{"swapoff"}, devices...)
+	out, err := utils.RunCommand(ctx, nil, args...)
+	if err != nil {
+		return trace.Wrap(err, "failed to turn off swap: %s", out)
+	}
+	progress.PrintInfo("Auto-disabled swap on: %v", strings.Join(devices, ","))
+	return nil
+}
+
+// commentOutLine comments out all occurrences of the specified line
+// in the file at path
+func commentOutLine(path, line string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return trace.ConvertSystemError(err)
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return trace.ConvertSystemError(err)
+	}
+	lines := strings.Split(string(data), "\n")
+	for i := range lines {
+		if lines[i] == line {
+			lines[i] = "#" + line
+		}
+	}
+	err = ioutil.WriteFile(path, []byte(strings.Join(lines, "\n")), info.Mode())
+	if err != nil {
+		return trace.ConvertSystemError(err)
+	}
+	return nil
+}
+
+// setSELinuxBoolean sets the specified SELinux boolean and makes sure
+// it persists across reboots
+func setSELinuxBoolean(ctx context.Context, name string, value bool, progress utils.Progress) error {
+	out, err := utils.RunCommand(ctx, nil, "setsebool", "-P", name, formatBoolean(value))
+	if err != nil {
+		return trace.Wrap(err, "failed to set SELinux boolean %v: %s", name, out)
+	}
+	progress.PrintInfo("Auto-set SELinux boolean: %v=%v", name, formatBoolean(value))
+	return nil
+}
+
+// addHostsEntry adds the specified entry to the hosts file
+func addHostsEntry(entry string, progress utils.Progress) error {
+	err := utils.EnsureLineInFile(defaults.HostsPath, entry)
+	if err != nil && !trace.IsAlreadyExists(err) {
+		return trace.Wrap(err)
+	}
+	progress.PrintInfo("Auto-added hosts entry: %v", entry)
+	return nil
+}
+
+// enableTimeSync enables network time synchronization
+func enableTimeSync(ctx context.Context, progress utils.Progress) error {
+	out, err := utils.RunCommand(ctx, nil, "timedatectl", "set-ntp", "true")
+	if err != nil {
+		return trace.Wrap(err, "failed to enable network time synchronization: %s", out)
+	}
+	progress.PrintInfo("Auto-enabled network time synchronization")
+	return nil
+}
+
+// setHugepages updates the transparent hugepages setting
+func setHugepages(value string, progress utils.Progress) error {
+	err := ioutil.WriteFile(defaults.TransparentHugepagePath, []byte(value), defaults.SharedReadMask)
+	if err != nil {
+		return trace.ConvertSystemError(err)
+	}
+	progress.PrintInfo("Auto-set transparent hugepages: %v", value)
+	return nil
+}
+
+// persistHugepages configures systemd-tmpfiles to apply the transparent
+// hugepages setting on boot
+func persistHugepages(value string) error {
+	err := utils.EnsureLineInFile(defaults.TransparentHugepageTmpfilesPath,
+		fmt.Sprintf("w %v - - - - %v", defaults.TransparentHugepagePath, value))
+	if err != nil && !trace.IsAlreadyExists(err) {
+		return trace.Wrap(err)
+	}
+	return nil
+}
+
+func formatBoolean(value bool) string {
+	if value {
+		return "on"
+	}
+	return "off"
+}
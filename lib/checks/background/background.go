@@ -0,0 +1,316 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package background implements checks executed periodically on
+// a running cluster to detect node configuration drift
+package background
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/gravitational/gravity/lib/checks"
+	"github.com/gravitational/gravity/lib/clients"
+	"github.com/gravitational/gravity/lib/constants"
+	"github.com/gravitational/gravity/lib/defaults"
+	"github.com/gravitational/gravity/lib/fsm"
+	"github.com/gravitational/gravity/lib/loc"
+	"github.com/gravitational/gravity/lib/ops"
+	"github.com/gravitational/gravity/lib/rpc"
+	"github.com/gravitational/gravity/lib/storage"
+
+	"github.com/gravitational/rigging"
+	"github.com/gravitational/trace"
+	"github.com/jonboulle/clockwork"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/credentials"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Config describes the background checker configuration
+type Config struct {
+	// Operator is the cluster operator service
+	Operator ops.Operator
+	// Client is the Kubernetes client
+	Client kubernetes.Interface
+	// Credentials is the RPC agent client credentials
+	Credentials credentials.TransportCredentials
+	// Checks lists the checks to execute.
+	// Defaults to DefaultChecks
+	Checks []string
+	// Interval specifies how often the checks are executed
+	Interval time.Duration
+	// Clock is used to timestamp reports
+	Clock clockwork.Clock
+	// FieldLogger is used for logging
+	logrus.FieldLogger
+}
+
+// CheckAndSetDefaults validates the configuration and sets defaults
+func (r *Config) CheckAndSetDefaults() error {
+	if r.Operator == nil {
+		return trace.BadParameter("missing Operator")
+	}
+	if r.Client == nil {
+		return trace.BadParameter("missing Client")
+	}
+	if r.Credentials == nil {
+		return trace.BadParameter("missing Credentials")
+	}
+	if len(r.Checks) == 0 {
+		r.Checks = DefaultChecks
+	}
+	if err := ValidateChecks(r.Checks); err != nil {
+		return trace.Wrap(err)
+	}
+	if r.Interval == 0 {
+		r.Interval = defaults.BackgroundChecksInterval
+	}
+	if r.Clock == nil {
+		r.Clock = clockwork.NewRealClock()
+	}
+	if r.FieldLogger == nil {
+		r.FieldLogger = logrus.WithField(trace.Component, "checks:background")
+	}
+	return nil
+}
+
+// New returns a new background checker
+func New(config Config) (*Checker, error) {
+	if err := config.CheckAndSetDefaults(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return &Checker{Config: config}, nil
+}
+
+// Checker periodically executes checks on cluster nodes.
+// Agents are deployed on the nodes for the duration of the checks if they
+// are not running
+type Checker struct {
+	// Config is the checker configuration
+	Config
+}
+
+// Run executes the checks periodically until the context is cancelled
+func (r *Checker) Run(ctx context.Context) {
+	r.WithField("checks", r.Checks).Info("Starting background checks.")
+	ticker := r.Clock.NewTicker(r.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.Chan():
+			runCtx, cancel := context.WithTimeout(ctx, defaults.BackgroundChecksTimeout)
+			report, err := r.RunOnce(runCtx)
+			cancel()
+			if err != nil {
+				if trace.IsCompareFailed(err) {
+					r.Infof("Skipping background checks: %v.", err)
+					continue
+				}
+				r.WithError(err).Warn("Failed to execute background checks.")
+				continue
+			}
+			updateMetrics(*report)
+			if err := r.saveReport(*report); err != nil {
+				r.WithError(err).Warn("Failed to save background checks report.")
+			}
+		case <-ctx.Done():
+			r.Info("Stopping background checks.")
+			return
+		}
+	}
+}
+
+// RunOnce executes the checks on all cluster nodes and returns the report.
+// Returns a CompareFailed error if the checks cannot be executed because
+// there is an operation in progress
+func (r *Checker) RunOnce(ctx context.Context) (*Report, error) {
+	cluster, err := r.Operator.GetLocalSite()
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	if err := r.checkOperations(cluster.Key()); err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	agents := fsm.NewAgentRunner(r.Credentials)
+	defer agents.Close()
+
+	deployed, err := r.ensureAgents(ctx, *cluster, agents)
+	if err != nil {
+		if trace.IsCompareFailed(err) {
+			return nil, trace.Wrap(err)
+		}
+		r.WithError(err).Warn("Failed to deploy agents.")
+	}
+	if len(deployed) != 0 {
+		defer r.shutdownAgents(cluster.Key(), deployed, agents)
+	}
+
+	report := Report{Timestamp: r.Clock.Now().UTC()}
+	var servers []checks.Server
+	for _, server := range cluster.ClusterState.Servers {
+		node, err := checks.GetServer(ctx, agents, server)
+		if err != nil {
+			report.Nodes = append(report.Nodes, newAgentFailureReport(
+				server.Hostname, server.AdvertiseIP, err))
+			continue
+		}
+		servers = append(servers, *node)
+	}
+	requirements, err := checks.RequirementsFromManifest(cluster.App.Manifest)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	checker, err := checks.New(checks.Config{
+		Remote:       checks.NewRemote(agents),
+		Manifest:     cluster.App.Manifest,
+		Servers:      servers,
+		Requirements: requirements,
+	})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	for _, server := range servers {
+		report.Nodes = append(report.Nodes, r.checkNode(ctx, checker, server))
+	}
+	return &report, nil
+}
+
+// checkOperations returns a CompareFailed error if there is an operation
+// in progress in the specified cluster.
+// Operations deploy and shut down agents on their own, so the checks
+// should not interfere with them
+func (r *Checker) checkOperations(key ops.SiteKey) error {
+	operations, err := ops.GetActiveOperations(key, r.Operator)
+	if err != nil && !trace.IsNotFound(err) {
+		return trace.Wrap(err)
+	}
+	if len(operations) != 0 {
+		return trace.CompareFailed("operation %v is in progress", operations[0].Type)
+	}
+	return nil
+}
+
+// shutdownAgents shuts down the agents deployed for the checks on the
+// specified nodes unless an operation has been started in the meantime
+// in which case the agents are left running for the operation
+func (r *Checker) shutdownAgents(key ops.SiteKey, deployed []string, agents rpc.AgentRepository) {
+	if err := r.checkOperations(key); err != nil {
+		r.WithError(err).Info("Not shutting down agents.")
+		return
+	}
+	err := rpc.ShutdownAgents(context.TODO(), deployed, r.FieldLogger, agents)
+	if err != nil {
+		r.WithError(err).Warn("Failed to shut down agents.")
+	}
+}
+
+// ensureAgents deploys agents on the cluster nodes where they are not running.
+// Returns the addresses of the nodes with deployed agents
+func (r *Checker) ensureAgents(ctx context.Context, cluster ops.Site, agents rpc.AgentRepository) (deployed []string, err error) {
+	var servers []rpc.DeployServer
+	for _, server := range cluster.ClusterState.Servers {
+		if canExecute(ctx, agents, server) {
+			continue
+		}
+		servers = append(servers, rpc.NewDeployServer(server))
+		deployed = append(deployed, server.AdvertiseIP)
+	}
+	if len(servers) == 0 {
+		return nil, nil
+	}
+	// Make sure no operation has been started since the checks were
+	// scheduled as it would deploy its own agents
+	if err := r.checkOperations(cluster.Key()); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	gravityPackage, err := cluster.App.Manifest.Dependencies.ByName(constants.GravityPackage)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	proxy, err := clients.TeleportProxy(ctx, r.Operator, constants.Localhost, cluster.Domain)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	defer proxy.Close()
+	deployCtx, cancel := context.WithTimeout(ctx, defaults.AgentDeployTimeout)
+	defer cancel()
+	err = rpc.DeployAgents(deployCtx, rpc.DeployAgentsRequest{
+		Servers:        servers,
+		ClusterState:   cluster.ClusterState,
+		GravityPackage: *gravityPackage,
+		SecretsPackage: loc.RPCSecrets,
+		Proxy:          proxy,
+		FieldLogger:    r.FieldLogger,
+	})
+	if err != nil {
+		return deployed, trace.Wrap(err)
+	}
+	return deployed, nil
+}
+
+func canExecute(ctx context.Context, agents rpc.AgentRepository, server storage.Server) bool {
+	ctx, cancel := context.WithTimeout(ctx, defaults.DialTimeout)
+	defer cancel()
+	return agents.CanExecute(ctx, server) == nil
+}
+
+// saveReport stores the report in the cluster
+func (r *Checker) saveReport(report Report) error {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	configMap := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      constants.BackgroundChecksConfigMap,
+			Namespace: defaults.KubeSystemNamespace,
+		},
+		Data: map[string]string{
+			constants.ResourceSpecKey: string(data),
+		},
+	}
+	client := r.Client.CoreV1().ConfigMaps(defaults.KubeSystemNamespace)
+	_, err = client.Create(configMap)
+	err = rigging.ConvertError(err)
+	if err == nil || !trace.IsAlreadyExists(err) {
+		return trace.Wrap(err)
+	}
+	_, err = client.Update(configMap)
+	return trace.Wrap(rigging.ConvertError(err))
+}
+
+// GetReport returns the report of the most recent background checks
+func GetReport(client kubernetes.Interface) (*Report, error) {
+	configMap, err := client.CoreV1().ConfigMaps(defaults.KubeSystemNamespace).
+		Get(constants.BackgroundChecksConfigMap, metav1.GetOptions{})
+	if err != nil {
+		return nil, trace.Wrap(rigging.ConvertError(err))
+	}
+	data, ok := configMap.Data[constants.ResourceSpecKey]
+	if !ok {
+		return nil, trace.NotFound("no background checks report found")
+	}
+	var report Report
+	if err := json.Unmarshal([]byte(data), &report); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return &report, nil
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package background
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	checkFailed = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "gravity_background_check_failed",
+			Help: "Whether the most recent background check has failed on the node (1) or passed (0)",
+		},
+		[]string{"node", "hostname", "check"},
+	)
+	lastRun = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "gravity_background_checks_last_run_timestamp_seconds",
+			Help: "Unix time of the most recent background checks run",
+		},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(checkFailed, lastRun)
+}
+
+// updateMetrics updates the metrics with the results from the specified report
+func updateMetrics(report Report) {
+	// Drop results for the nodes that have left the cluster
+	checkFailed.Reset()
+	for _, node := range report.Nodes {
+		for _, check := range node.Checks {
+			var value float64
+			if check.Failed() {
+				value = 1
+			}
+			checkFailed.WithLabelValues(node.AdvertiseIP, node.Hostname, check.Name).Set(value)
+		}
+	}
+	lastRun.Set(float64(report.Timestamp.Unix()))
+}
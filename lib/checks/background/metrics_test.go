@@ -0,0 +1,58 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package background
+
+import (
+	"testing"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"gopkg.in/check.v1"
+)
+
+func TestBackground(t *testing.T) { check.TestingT(t) }
+
+type BackgroundSuite struct{}
+
+var _ = check.Suite(&BackgroundSuite{})
+
+func (*BackgroundSuite) TestUpdatesMetrics(c *check.C) {
+	updateMetrics(Report{
+		Timestamp: time.Unix(1000, 0),
+		Nodes: []NodeReport{
+			{
+				Hostname:    "node-1",
+				AdvertiseIP: "10.0.0.1",
+				Checks: []CheckResult{
+					{Name: CheckTimeDrift},
+					{Name: CheckKernel, Error: "module br_netfilter is not loaded"},
+				},
+			},
+		},
+	})
+	c.Assert(gaugeValue(c, checkFailed.WithLabelValues("10.0.0.1", "node-1", CheckTimeDrift)), check.Equals, float64(0))
+	c.Assert(gaugeValue(c, checkFailed.WithLabelValues("10.0.0.1", "node-1", CheckKernel)), check.Equals, float64(1))
+	c.Assert(gaugeValue(c, lastRun), check.Equals, float64(1000))
+}
+
+func gaugeValue(c *check.C, metric interface {
+	Write(*dto.Metric) error
+}) float64 {
+	var m dto.Metric
+	c.Assert(metric.Write(&m), check.IsNil)
+	return m.GetGauge().GetValue()
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package background
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/gravitational/gravity/lib/checks"
+	"github.com/gravitational/gravity/lib/utils"
+
+	"github.com/gravitational/trace"
+)
+
+const (
+	// CheckTimeDrift verifies the time drift between a node
+	// and the cluster controller
+	CheckTimeDrift = "time-drift"
+	// CheckDisks verifies disk throughput requirements
+	CheckDisks = "disks"
+	// CheckKernel verifies that the required kernel modules are
+	// loaded and kernel parameters are set
+	CheckKernel = "kernel"
+	// CheckAgent is reported if the checks could not be executed
+	// on a node because its agent was unavailable
+	CheckAgent = "agent"
+)
+
+// AllChecks lists the checks that can be executed periodically
+// on a running cluster.
+// Port availability tests are not applicable since the ports are occupied
+// by the running cluster
+var AllChecks = []string{
+	CheckTimeDrift,
+	CheckDisks,
+	CheckKernel,
+}
+
+// DefaultChecks lists the checks executed if none have been configured.
+// The disk benchmark puts load on the node disks, so it is only executed
+// if explicitly requested
+var DefaultChecks = []string{
+	CheckTimeDrift,
+	CheckKernel,
+}
+
+// ValidateChecks validates the specified list of background checks
+func ValidateChecks(names []string) error {
+	for _, name := range names {
+		if !utils.StringInSlice(AllChecks, name) {
+			return trace.BadParameter("unsupported background check %q, supported checks: %v",
+				name, strings.Join(AllChecks, ", "))
+		}
+	}
+	return nil
+}
+
+// Report describes the outcome of the checks executed
+// periodically on a running cluster
+type Report struct {
+	// Timestamp is the time the checks were executed
+	Timestamp time.Time `json:"timestamp"`
+	// Nodes lists check results for each node
+	Nodes []NodeReport `json:"nodes"`
+}
+
+// NodeReport describes the outcome of the checks executed on a single node
+type NodeReport struct {
+	// Hostname is the node hostname
+	Hostname string `json:"hostname"`
+	// AdvertiseIP is the node advertise IP address
+	AdvertiseIP string `json:"advertise_ip"`
+	// Checks lists results of individual checks
+	Checks []CheckResult `json:"checks"`
+}
+
+// Failed returns the list of failed checks
+func (r NodeReport) Failed() (failed []CheckResult) {
+	for _, check := range r.Checks {
+		if check.Failed() {
+			failed = append(failed, check)
+		}
+	}
+	return failed
+}
+
+// CheckResult describes the outcome of a single check
+type CheckResult struct {
+	// Name is the check name
+	Name string `json:"name"`
+	// Error describes the failure. Empty if the check has passed
+	Error string `json:"error,omitempty"`
+}
+
+// Failed returns true if the check has failed
+func (r CheckResult) Failed() bool {
+	return r.Error != ""
+}
+
+// String returns textual representation of this result
+func (r CheckResult) String() string {
+	if !r.Failed() {
+		return r.Name
+	}
+	return r.Name + ": " + r.Error
+}
+
+// newAgentFailureReport returns a report for the specified node for which
+// checks could not be executed because of the agent failure
+func newAgentFailureReport(hostname, advertiseIP string, err error) NodeReport {
+	return NodeReport{
+		Hostname:    hostname,
+		AdvertiseIP: advertiseIP,
+		Checks: []CheckResult{{
+			Name:  CheckAgent,
+			Error: trace.UserMessage(err),
+		}},
+	}
+}
+
+// nodeChecker executes individual checks on a node
+type nodeChecker interface {
+	// CheckDisks verifies the disk performance requirements
+	CheckDisks(context.Context, checks.Server) error
+	// CheckKernel verifies kernel modules and parameters
+	CheckKernel(context.Context, checks.Server) error
+}
+
+// checkNode executes the specified background checks on the provided server
+func (r *Checker) checkNode(ctx context.Context, checker nodeChecker, server checks.Server) NodeReport {
+	report := NodeReport{
+		Hostname:    server.GetHostname(),
+		AdvertiseIP: server.AdvertiseIP,
+	}
+	for _, name := range r.Checks {
+		var err error
+		switch name {
+		case CheckTimeDrift:
+			err = checks.CheckTimeDrift(r.Clock.Now().UTC(), server)
+		case CheckDisks:
+			err = checker.CheckDisks(ctx, server)
+		case CheckKernel:
+			err = checker.CheckKernel(ctx, server)
+		default:
+			err = trace.BadParameter("unsupported background check %q", name)
+		}
+		result := CheckResult{Name: name}
+		if err != nil {
+			r.WithError(err).Warnf("Background check %v failed on %v.", name, server)
+			result.Error = trace.UserMessage(err)
+		}
+		report.Checks = append(report.Checks, result)
+	}
+	return report
+}
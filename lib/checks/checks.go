@@ -28,6 +28,7 @@ import (
 	"time"
 
 	"github.com/gravitational/gravity/lib/checks/autofix"
+	"github.com/gravitational/gravity/lib/checks/host"
 	"github.com/gravitational/gravity/lib/constants"
 	"github.com/gravitational/gravity/lib/defaults"
 	validationpb "github.com/gravitational/gravity/lib/network/validation/proto"
@@ -114,6 +115,14 @@ type LocalChecksRequest struct {
 	Docker storage.DockerConfig
 	// AutoFix when set to true attempts to fix some common problems
 	AutoFix bool
+	// FixHost when set to true, in addition to AutoFix, also fixes
+	// problems with the host environment (conflicting services, swap,
+	// SELinux booleans, hosts file, time synchronization and transparent
+	// hugepages). Otherwise these are only reported as fixable
+	FixHost bool
+	// DryRun when set to true only computes the changes required to fix
+	// the problems without applying them
+	DryRun bool
 	// Progress is used to report information about auto-fixed problems
 	utils.Progress
 }
@@ -137,6 +146,9 @@ type LocalChecksResult struct {
 	Fixed []*agentpb.Probe
 	// Fixable is a list of probes that can be attempted to auto-fix
 	Fixable []*agentpb.Probe
+	// Plans lists the changes required to fix the fixable probes.
+	// Only computed in dry-run mode
+	Plans []autofix.Plan
 }
 
 // GetFailed returns a list of all failed probes
@@ -166,7 +178,9 @@ func ValidateLocal(ctx context.Context, req LocalChecksRequest) (*LocalChecksRes
 		return nil, trace.Wrap(err)
 	}
 
-	autofix.AutoloadModules(ctx, schema.DefaultKernelModules, req.Progress)
+	if !req.DryRun {
+		autofix.AutoloadModules(ctx, schema.DefaultKernelModules, req.Progress)
+	}
 
 	dockerConfig := DockerConfigFromSchemaValue(req.Manifest.SystemDocker())
 	OverrideDockerConfig(&dockerConfig, req.Docker)
@@ -180,6 +194,18 @@ func ValidateLocal(ctx context.Context, req LocalChecksRequest) (*LocalChecksRes
 		return &LocalChecksResult{}, nil
 	}
 
+	if req.DryRun {
+		plans, failed := autofix.PlanFixes(failedProbes)
+		result := &LocalChecksResult{
+			Failed: failed,
+			Plans:  plans,
+		}
+		for _, plan := range plans {
+			result.Fixable = append(result.Fixable, plan.Probe)
+		}
+		return result, nil
+	}
+
 	if !req.AutoFix {
 		failed, fixable := autofix.GetFixable(failedProbes)
 		return &LocalChecksResult{
@@ -188,11 +214,18 @@ func ValidateLocal(ctx context.Context, req LocalChecksRequest) (*LocalChecksRes
 		}, nil
 	}
 
+	probes := failedProbes
+	var hostProbes []*agentpb.Probe
+	if !req.FixHost {
+		probes, hostProbes = autofix.SplitHostProbes(failedProbes)
+	}
 	// try to auto-fix some of the issues
-	fixed, unfixed := autofix.Fix(ctx, failedProbes, req.Progress)
+	fixed, unfixed := autofix.Fix(ctx, probes, req.Progress)
+	failed, fixable := autofix.GetFixable(hostProbes)
 	return &LocalChecksResult{
-		Failed: unfixed,
-		Fixed:  fixed,
+		Failed:  append(unfixed, failed...),
+		Fixed:   fixed,
+		Fixable: fixable,
 	}, nil
 }
 
@@ -207,6 +240,11 @@ func RunLocalChecks(ctx context.Context, req LocalChecksRequest) error {
 	if err != nil {
 		return trace.Wrap(err)
 	}
+	if len(result.Fixable) != 0 {
+		return trace.BadParameter(fmt.Sprintf("The following pre-flight checks failed:\n%v"+
+			"Run 'gravity check --fix' on this node or pass --fix-host to fix the host environment problems.",
+			FormatFailedChecks(result.GetFailed())))
+	}
 	if len(result.GetFailed()) != 0 {
 		return trace.BadParameter(fmt.Sprintf("The following pre-flight checks failed:\n%v",
 			FormatFailedChecks(result.GetFailed())))
@@ -226,6 +264,19 @@ func FormatFailedChecks(failed []*agentpb.Probe) string {
 	return buf.String()
 }
 
+// FormatFixPlans returns the changes required to fix failed checks
+// formatted as a list
+func FormatFixPlans(plans []autofix.Plan) string {
+	var buf bytes.Buffer
+	for _, plan := range plans {
+		fmt.Fprintf(&buf, "\t%s\n", formatProbe(*plan.Probe))
+		for _, action := range plan.Actions {
+			fmt.Fprintf(&buf, "\t\t* %v\n", action)
+		}
+	}
+	return buf.String()
+}
+
 // OverrideDockerConfig updates given config with values from overrideConfig where necessary
 func OverrideDockerConfig(config *storage.DockerConfig, overrideConfig storage.DockerConfig) {
 	if overrideConfig.StorageDriver != "" {
@@ -281,6 +332,9 @@ type Config struct {
 	Requirements map[string]Requirements
 	// Features allows to turn certain checks off.
 	Features
+	// OnDiskBenchmark is invoked with the result of each disk benchmark.
+	// Optional
+	OnDiskBenchmark func(storage.DiskBenchmark)
 }
 
 // check validates the checker configuration.
@@ -308,6 +362,16 @@ type Features struct {
 	// TestEtcdDisk specifies whether the device where etcd data resides
 	// should be performance-tested.
 	TestEtcdDisk bool
+	// TestNetworkPaths specifies whether the path MTU, latency and packet
+	// loss tests between servers should be executed.
+	TestNetworkPaths bool
+	// TestVXLAN specifies whether the overlay network data path between
+	// servers should be tested. The test is only applicable during install
+	// as it conflicts with the running overlay network.
+	TestVXLAN bool
+	// TestDiskProfiles specifies whether disks should be benchmarked
+	// with fio profiles against the profile disk requirements.
+	TestDiskProfiles bool
 }
 
 // String return textual representation of this server object
@@ -423,7 +487,18 @@ func (r *checker) CheckNode(ctx context.Context, server Server) (failed []*agent
 		}
 	}
 
-	err = r.checkDisks(ctx, server)
+	if r.TestDiskProfiles {
+		err = r.checkDiskProfiles(ctx, server)
+		if err != nil {
+			log.WithError(err).Warn("Failed to validate disk performance requirements.")
+			failed = append(failed, &agentpb.Probe{
+				Detail: err.Error(),
+				Error:  "failed to validate disk performance requirements",
+			})
+		}
+	}
+
+	err = r.CheckDisks(ctx, server)
 	if err != nil {
 		log.WithError(err).Warn("Failed to validate disk requirements.")
 		failed = append(failed, &agentpb.Probe{
@@ -483,11 +558,51 @@ func (r *checker) CheckNodes(ctx context.Context, servers []Server) (failed []*a
 		}
 	}
 
+	if r.TestNetworkPaths {
+		err = r.checkPathMTU(ctx, servers)
+		if err != nil {
+			log.WithError(err).Warn("Failed to validate path MTU requirements.")
+			failed = append(failed, &agentpb.Probe{
+				Detail: err.Error(),
+				Error:  "failed to validate network path MTU requirements",
+			})
+		}
+
+		err = r.checkLatency(ctx, servers)
+		if err != nil {
+			log.WithError(err).Warn("Failed to validate latency requirements.")
+			failed = append(failed, &agentpb.Probe{
+				Detail: err.Error(),
+				Error:  "failed to validate network latency requirements",
+			})
+		}
+
+		err = r.checkPacketLoss(ctx, servers)
+		if err != nil {
+			log.WithError(err).Warn("Failed to validate packet loss requirements.")
+			failed = append(failed, &agentpb.Probe{
+				Detail: err.Error(),
+				Error:  "failed to validate network packet loss requirements",
+			})
+		}
+	}
+
+	if r.TestVXLAN {
+		err = r.checkVXLAN(ctx, servers)
+		if err != nil {
+			log.WithError(err).Warn("Failed to validate overlay network data path.")
+			failed = append(failed, &agentpb.Probe{
+				Detail: err.Error(),
+				Error:  "failed to validate overlay network (VXLAN) data path",
+			})
+		}
+	}
+
 	return failed
 }
 
-// checkDisks verifies that disk performance satisfies the profile requirements.
-func (r *checker) checkDisks(ctx context.Context, server Server) error {
+// CheckDisks verifies that disk performance satisfies the profile requirements.
+func (r *checker) CheckDisks(ctx context.Context, server Server) error {
 	requirements := r.Requirements[server.Server.Role]
 	targets, err := r.collectTargets(ctx, server, requirements)
 	if err != nil {
@@ -520,6 +635,44 @@ func (r *checker) checkDisks(ctx context.Context, server Server) error {
 	return nil
 }
 
+// CheckKernel verifies that the kernel modules required by the profile
+// are loaded and the kernel parameters are set on the specified server
+func (r *checker) CheckKernel(ctx context.Context, server Server) error {
+	requirements := r.Requirements[server.Server.Role]
+	validateCtx, cancel := context.WithTimeout(ctx, defaults.AgentValidationTimeout)
+	defer cancel()
+	failed, err := r.Remote.Validate(validateCtx, server.AdvertiseIP, ValidateConfig{
+		Manifest: r.Manifest,
+		Profile:  server.Server.Role,
+		Docker:   requirements.Docker,
+	})
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	failed = filterKernelProbes(failed)
+	if len(failed) == 0 {
+		return nil
+	}
+	var errors []string
+	for _, probe := range failed {
+		errors = append(errors, formatProbe(*probe))
+	}
+	return trace.BadParameter("%v", strings.Join(errors, "; "))
+}
+
+// filterKernelProbes returns the probes for the kernel modules and
+// kernel parameters checkers
+func filterKernelProbes(probes []*agentpb.Probe) (result []*agentpb.Probe) {
+	for _, probe := range probes {
+		switch probe.Checker {
+		case monitoring.KernelModuleCheckerID, monitoring.IPForwardCheckerID,
+			monitoring.NetfilterCheckerID, monitoring.MountsCheckerID:
+			result = append(result, probe)
+		}
+	}
+	return result
+}
+
 // checkServerDisk runs a simple disk performance test and returns the write speed in bytes per second
 func (r *checker) checkServerDisk(ctx context.Context, server storage.Server, target string) (uint64, error) {
 	var out bytes.Buffer
@@ -649,6 +802,223 @@ func (r *checker) checkBandwidth(ctx context.Context, servers []Server) error {
 	return nil
 }
 
+// checkPathMTU discovers the path MTU between servers and makes sure it
+// satisfies the profile
+func (r *checker) checkPathMTU(ctx context.Context, servers []Server) error {
+	if !r.requiresNetwork(servers, func(n Network) bool { return n.MinMTU != 0 }) {
+		return nil
+	}
+
+	req := constructNetworkProbeRequest(servers, ModePathMTU)
+	log.Infof("Path MTU test request: %v.", req)
+
+	resp, err := r.Remote.CheckPathMTU(ctx, req)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	log.Infof("Path MTU test response: %v.", resp)
+
+	return trace.Wrap(verifyPathMTU(servers, r.Requirements, resp))
+}
+
+// checkLatency measures round-trip time and jitter between servers and
+// makes sure they satisfy the profile
+func (r *checker) checkLatency(ctx context.Context, servers []Server) error {
+	if !r.requiresNetwork(servers, func(n Network) bool { return n.MaxRTT != 0 || n.MaxJitter != 0 }) {
+		return nil
+	}
+
+	req := constructNetworkProbeRequest(servers, ModeLatency)
+	log.Infof("Latency test request: %v.", req)
+
+	resp, err := r.Remote.CheckLatency(ctx, req)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	log.Infof("Latency test response: %v.", resp)
+
+	return trace.Wrap(verifyLatency(servers, r.Requirements, resp))
+}
+
+// checkPacketLoss measures UDP packet loss between servers and makes sure
+// it satisfies the profile
+func (r *checker) checkPacketLoss(ctx context.Context, servers []Server) error {
+	if !r.requiresNetwork(servers, func(n Network) bool { return n.MaxPacketLoss != 0 }) {
+		return nil
+	}
+
+	req := constructNetworkProbeRequest(servers, ModePacketLoss)
+	log.Infof("Packet loss test request: %v.", req)
+
+	resp, err := r.Remote.CheckPacketLoss(ctx, req)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	log.Infof("Packet loss test response: %v.", resp)
+
+	return trace.Wrap(verifyPacketLoss(servers, r.Requirements, resp))
+}
+
+// checkVXLAN makes sure the VXLAN-encapsulated traffic the overlay network
+// relies on passes between servers
+func (r *checker) checkVXLAN(ctx context.Context, servers []Server) error {
+	if len(servers) < 2 {
+		return nil
+	}
+	networkType := r.Manifest.GetNetworkType(servers[0].Provisioner, "")
+	if networkType != schema.NetworkingFlannel {
+		log.Infof("Skipping overlay network test for %q network type.", networkType)
+		return nil
+	}
+
+	req := constructNetworkProbeRequest(servers, ModeVXLAN)
+	log.Infof("Overlay network test request: %v.", req)
+
+	resp, err := r.Remote.CheckVXLAN(ctx, req)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	log.Infof("Overlay network test response: %v.", resp)
+
+	if len(resp.Failures()) != 0 {
+		return trace.BadParameter("%v", strings.Join(resp.Failures(), ", "))
+	}
+	return nil
+}
+
+// requiresNetwork returns true if there are at least two servers and
+// the profile of any of them has a network requirement matching the
+// provided predicate
+func (r *checker) requiresNetwork(servers []Server, fn func(Network) bool) bool {
+	if len(servers) < 2 {
+		return false
+	}
+	for _, server := range servers {
+		if fn(r.Requirements[server.Server.Role].Network) {
+			return true
+		}
+	}
+	return false
+}
+
+// verifyPathMTU makes sure the path MTU discovered from each server
+// satisfies its profile
+func verifyPathMTU(servers []Server, requirements map[string]Requirements, resp PingPongGameResults) error {
+	if len(resp.Failures()) != 0 {
+		return trace.BadParameter("%v", strings.Join(resp.Failures(), ", "))
+	}
+
+	var errors []string
+	for addr, result := range resp {
+		ip, _ := utils.SplitHostPort(addr, "")
+		server, err := findServer(servers, ip)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+
+		minMTU := requirements[server.Server.Role].Network.MinMTU
+		for _, mtu := range result.PathMTUResults {
+			if minMTU != 0 && int(mtu.Mtu) < minMTU {
+				errors = append(errors, fmt.Sprintf(
+					"path MTU from server %q to %v is %v which is lower than required %v",
+					server.ServerInfo.GetHostname(), mtu.Server.Addr, mtu.Mtu, minMTU))
+				continue
+			}
+			log.Infof("Server %q path MTU to %v: %v.",
+				server.ServerInfo.GetHostname(), mtu.Server.Addr, mtu.Mtu)
+		}
+	}
+
+	if len(errors) != 0 {
+		return trace.BadParameter("%v", strings.Join(errors, ", "))
+	}
+	return nil
+}
+
+// verifyLatency makes sure the round-trip time and jitter measured from
+// each server satisfy its profile
+func verifyLatency(servers []Server, requirements map[string]Requirements, resp PingPongGameResults) error {
+	if len(resp.Failures()) != 0 {
+		return trace.BadParameter("%v", strings.Join(resp.Failures(), ", "))
+	}
+
+	var errors []string
+	for addr, result := range resp {
+		ip, _ := utils.SplitHostPort(addr, "")
+		server, err := findServer(servers, ip)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+
+		network := requirements[server.Server.Role].Network
+		for _, latency := range result.LatencyResults {
+			rtt, err := validationpb.DurationFromProto(latency.Rtt.P99)
+			if err != nil {
+				return trace.Wrap(err)
+			}
+			jitter, err := validationpb.DurationFromProto(latency.Jitter.P99)
+			if err != nil {
+				return trace.Wrap(err)
+			}
+			if network.MaxRTT != 0 && rtt > network.MaxRTT {
+				errors = append(errors, fmt.Sprintf(
+					"99th percentile round-trip time from server %q to %v is %v which is higher than allowed %v",
+					server.ServerInfo.GetHostname(), latency.Server.Addr, rtt, network.MaxRTT))
+			}
+			if network.MaxJitter != 0 && jitter > network.MaxJitter {
+				errors = append(errors, fmt.Sprintf(
+					"99th percentile jitter from server %q to %v is %v which is higher than allowed %v",
+					server.ServerInfo.GetHostname(), latency.Server.Addr, jitter, network.MaxJitter))
+			}
+			log.Infof("Server %q latency to %v: round-trip time %v, jitter %v (99th percentile).",
+				server.ServerInfo.GetHostname(), latency.Server.Addr, rtt, jitter)
+		}
+	}
+
+	if len(errors) != 0 {
+		return trace.BadParameter("%v", strings.Join(errors, ", "))
+	}
+	return nil
+}
+
+// verifyPacketLoss makes sure the UDP packet loss measured from each
+// server satisfies its profile
+func verifyPacketLoss(servers []Server, requirements map[string]Requirements, resp PingPongGameResults) error {
+	if len(resp.Failures()) != 0 {
+		return trace.BadParameter("%v", strings.Join(resp.Failures(), ", "))
+	}
+
+	var errors []string
+	for addr, result := range resp {
+		ip, _ := utils.SplitHostPort(addr, "")
+		server, err := findServer(servers, ip)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+
+		maxLoss := requirements[server.Server.Role].Network.MaxPacketLoss
+		for _, loss := range result.PacketLossResults {
+			if maxLoss != 0 && loss.LossPercent() > maxLoss {
+				errors = append(errors, fmt.Sprintf(
+					"packet loss from server %q to %v is %.2f%% which is higher than allowed %v%%",
+					server.ServerInfo.GetHostname(), loss.Server.Addr, loss.LossPercent(), maxLoss))
+				continue
+			}
+			log.Infof("Server %q packet loss to %v: %.2f%%.",
+				server.ServerInfo.GetHostname(), loss.Server.Addr, loss.LossPercent())
+		}
+	}
+
+	if len(errors) != 0 {
+		return trace.BadParameter("%v", strings.Join(errors, ", "))
+	}
+	return nil
+}
+
 // collectTargets returns a list of targets (devices or existing filesystems)
 // for the disk performance test
 func (r *checker) collectTargets(ctx context.Context, server Server, requirements Requirements) ([]diskCheckTarget, error) {
@@ -845,6 +1215,21 @@ func checkSameOS(servers []Server) error {
 	return nil
 }
 
+// CheckTimeDrift checks if time on the specified server is out of sync
+// with the local time given with currentTime
+func CheckTimeDrift(currentTime time.Time, server Server) error {
+	delta := currentServerTime(currentTime, server.LocalTime, server.ServerTime).Sub(currentTime)
+	if delta < 0 {
+		delta *= -1
+	}
+	if delta > defaults.MaxOutOfSyncTimeDelta {
+		return trace.BadParameter("server %v clock is out of sync by %v, "+
+			"sync the time on the server, e.g. using ntp",
+			server.GetHostname(), delta)
+	}
+	return nil
+}
+
 // checkTime checks if time it out of sync between servers
 func checkTime(currentTime time.Time, servers []Server) error {
 	// server can not be out of sync with itself
@@ -912,17 +1297,16 @@ func checkSystemPackages(server Server, dockerConfig schema.Docker) error {
 }
 
 func basicCheckers(options *validationpb.ValidateOptions) health.Checker {
-	return monitoring.NewCompositeChecker(
-		"local",
-		[]health.Checker{
-			monitoring.NewIPForwardChecker(),
-			monitoring.NewBridgeNetfilterChecker(),
-			monitoring.NewMayDetachMountsChecker(),
-			monitoring.DefaultProcessChecker(),
-			defaultPortChecker(options),
-			monitoring.DefaultBootConfigParams(),
-		},
-	)
+	checkers := []health.Checker{
+		monitoring.NewIPForwardChecker(),
+		monitoring.NewBridgeNetfilterChecker(),
+		monitoring.NewMayDetachMountsChecker(),
+		monitoring.DefaultProcessChecker(),
+		defaultPortChecker(options),
+		monitoring.DefaultBootConfigParams(),
+	}
+	checkers = append(checkers, host.DefaultCheckers(options.GetAdvertiseAddr())...)
+	return monitoring.NewCompositeChecker("local", checkers)
 }
 
 func defaultPortChecker(options *validationpb.ValidateOptions) health.Checker {
@@ -1041,6 +1425,33 @@ func constructBandwidthRequest(servers []Server) (PingPongGame, error) {
 	return game, nil
 }
 
+// constructNetworkProbeRequest constructs a ping-pong game request for
+// the specified network probe mode between all pairs of servers
+func constructNetworkProbeRequest(servers []Server, mode string) PingPongGame {
+	game := make(PingPongGame, len(servers))
+	for _, server := range servers {
+		var remote []validationpb.Addr
+		for _, other := range servers {
+			if server.AdvertiseIP != other.AdvertiseIP {
+				remote = append(remote, validationpb.Addr{
+					Network: "udp",
+					Addr:    other.AdvertiseIP,
+				})
+			}
+		}
+		game[server.AdvertiseIP] = PingPongRequest{
+			Duration: defaults.NetworkProbeDuration,
+			Listen: []validationpb.Addr{{
+				Network: "udp",
+				Addr:    server.AdvertiseIP,
+			}},
+			Ping: remote,
+			Mode: mode,
+		}
+	}
+	return game
+}
+
 func findServer(servers []Server, addr string) (*Server, error) {
 	for _, server := range servers {
 		if server.AdvertiseIP == addr {
@@ -21,6 +21,7 @@ import (
 	"time"
 
 	"github.com/gravitational/gravity/lib/defaults"
+	pb "github.com/gravitational/gravity/lib/network/validation/proto"
 	"github.com/gravitational/gravity/lib/schema"
 	"github.com/gravitational/gravity/lib/storage"
 
@@ -117,6 +118,22 @@ func (s *ChecksSuite) TestTime(c *check.C) {
 	c.Assert(trace.IsBadParameter(err), check.Equals, true, check.Commentf("expected BadParameter, got %v", err))
 }
 
+func (s *ChecksSuite) TestChecksTimeDrift(c *check.C) {
+	now := time.Date(2016, 12, 1, 2, 3, 40, 5000, time.UTC)
+	// we have received the info 10 seconds ago
+	localTime := now.Add(-10 * time.Second)
+	server := Server{ServerInfo: ServerInfo{
+		System:     storage.NewSystemInfo(storage.SystemSpecV2{Hostname: "node-1"}),
+		ServerTime: localTime.Add(defaults.MaxOutOfSyncTimeDelta / 2),
+		LocalTime:  localTime,
+	}}
+	c.Assert(CheckTimeDrift(now, server), check.IsNil)
+
+	server.ServerTime = localTime.Add(-defaults.MaxOutOfSyncTimeDelta - time.Second)
+	err := CheckTimeDrift(now, server)
+	c.Assert(trace.IsBadParameter(err), check.Equals, true, check.Commentf("expected BadParameter, got %v", err))
+}
+
 func (s *ChecksSuite) TestCheckSameOS(c *check.C) {
 	infos := []Server{
 		{
@@ -156,3 +173,126 @@ func (s *ChecksSuite) TestCheckSameOS(c *check.C) {
 	c.Assert(checkSameOS(infos[:2]), check.NotNil)
 	c.Assert(checkSameOS(infos[1:]), check.IsNil)
 }
+
+func (s *ChecksSuite) TestVerifiesNetworkPaths(c *check.C) {
+	servers := []Server{
+		newNetworkServer("node-1", "10.0.0.1"),
+		newNetworkServer("node-2", "10.0.0.2"),
+	}
+	requirements := map[string]Requirements{
+		"node": {
+			Network: Network{
+				MinMTU:        1450,
+				MaxRTT:        5 * time.Millisecond,
+				MaxJitter:     2 * time.Millisecond,
+				MaxPacketLoss: 1,
+			},
+		},
+	}
+	node1 := pb.Addr{Network: "udp", Addr: "10.0.0.1"}
+	node2 := pb.Addr{Network: "udp", Addr: "10.0.0.2"}
+
+	err := verifyPathMTU(servers, requirements, PingPongGameResults{
+		"10.0.0.1:3012": {PathMTUResults: []pb.PathMTUResult{{Server: &node2, Mtu: 1500}}},
+		"10.0.0.2:3012": {PathMTUResults: []pb.PathMTUResult{{Server: &node1, Mtu: 1400}}},
+	})
+	c.Assert(trace.IsBadParameter(err), check.Equals, true, check.Commentf("expected BadParameter, got %v", err))
+
+	err = verifyLatency(servers, requirements, PingPongGameResults{
+		"10.0.0.1:3012": {LatencyResults: []pb.LatencyResult{newLatencyResult(node2, time.Millisecond, time.Millisecond)}},
+		"10.0.0.2:3012": {LatencyResults: []pb.LatencyResult{newLatencyResult(node1, 2*time.Millisecond, time.Millisecond)}},
+	})
+	c.Assert(err, check.IsNil)
+
+	err = verifyLatency(servers, requirements, PingPongGameResults{
+		"10.0.0.1:3012": {LatencyResults: []pb.LatencyResult{newLatencyResult(node2, time.Millisecond, 3*time.Millisecond)}},
+	})
+	c.Assert(trace.IsBadParameter(err), check.Equals, true, check.Commentf("expected BadParameter, got %v", err))
+
+	err = verifyPacketLoss(servers, requirements, PingPongGameResults{
+		"10.0.0.1:3012": {PacketLossResults: []pb.PacketLossResult{{Server: &node2, Sent: 100, Received: 100}}},
+		"10.0.0.2:3012": {PacketLossResults: []pb.PacketLossResult{{Server: &node1, Sent: 100, Received: 95}}},
+	})
+	c.Assert(trace.IsBadParameter(err), check.Equals, true, check.Commentf("expected BadParameter, got %v", err))
+
+	err = verifyPacketLoss(servers, requirements, PingPongGameResults{
+		"10.0.0.1:3012": {PacketLossResults: []pb.PacketLossResult{{Server: &node2, Code: 1, Error: "no response"}}},
+	})
+	c.Assert(trace.IsBadParameter(err), check.Equals, true, check.Commentf("expected BadParameter, got %v", err))
+}
+
+func (s *ChecksSuite) TestVerifiesDiskBenchmarks(c *check.C) {
+	server := newNetworkServer("node-1", "10.0.0.1")
+	disk := Disk{
+		Path:            schema.DiskMountEtcd,
+		Profile:         schema.DiskProfileEtcd,
+		MinIOPS:         50,
+		MaxFsyncLatency: 10 * time.Millisecond,
+		FsyncPercentile: 99.9,
+	}
+	benchmark := newDiskBenchmark(server, "/var/lib/gravity/planet/etcd", disk, pb.FioJobResult{
+		Write: &pb.FioWriteResult{Iops: 100},
+		Sync: &pb.FioSyncResult{Latency: &pb.FioSyncLatency{
+			Percentile: map[string]int64{"99.900000": int64(20 * time.Millisecond)},
+		}},
+	})
+	c.Assert(benchmark.FsyncLatency, check.DeepEquals, map[string]time.Duration{
+		"99.9": 20 * time.Millisecond,
+	})
+	err := checkDiskBenchmark(benchmark, disk)
+	c.Assert(err, check.ErrorMatches, "server node-1 has high p99.9 fsync latency of 20ms .*")
+
+	disk.MaxFsyncLatency = 30 * time.Millisecond
+	c.Assert(checkDiskBenchmark(benchmark, disk), check.IsNil)
+
+	disk.MinIOPS = 200
+	err = checkDiskBenchmark(benchmark, disk)
+	c.Assert(err, check.ErrorMatches, "server node-1 has low IOPS of 100 .*")
+}
+
+func (s *ChecksSuite) TestFailsDiskBenchmarkWithoutLatency(c *check.C) {
+	server := newNetworkServer("node-1", "10.0.0.1")
+	disk := Disk{
+		Path:            schema.DiskMountEtcd,
+		Profile:         schema.DiskProfileEtcd,
+		MaxFsyncLatency: 10 * time.Millisecond,
+		FsyncPercentile: 99.9,
+	}
+	// fio has reported a different percentile
+	benchmark := newDiskBenchmark(server, "/var/lib/gravity/planet/etcd", disk, pb.FioJobResult{
+		Write: &pb.FioWriteResult{Iops: 100},
+		Sync: &pb.FioSyncResult{Latency: &pb.FioSyncLatency{
+			Percentile: map[string]int64{"99.000000": int64(time.Millisecond)},
+		}},
+	})
+	c.Assert(benchmark.FsyncLatency, check.IsNil)
+	err := checkDiskBenchmark(benchmark, disk)
+	c.Assert(err, check.ErrorMatches, "server node-1 p99.9 fsync latency .* was not reported by the benchmark .*")
+}
+
+func (s *ChecksSuite) TestFioProfiles(c *check.C) {
+	for _, profile := range schema.DiskProfiles {
+		spec, err := fioProfileJob(profile, "/tmp/fio.test")
+		c.Assert(err, check.IsNil)
+		c.Assert(spec.Check(), check.IsNil)
+	}
+	_, err := fioProfileJob("unknown", "/tmp/fio.test")
+	c.Assert(trace.IsBadParameter(err), check.Equals, true)
+}
+
+func newNetworkServer(hostname, addr string) Server {
+	return Server{
+		Server: storage.Server{AdvertiseIP: addr, Role: "node"},
+		ServerInfo: ServerInfo{
+			System: storage.NewSystemInfo(storage.SystemSpecV2{Hostname: hostname}),
+		},
+	}
+}
+
+func newLatencyResult(server pb.Addr, rtt, jitter time.Duration) pb.LatencyResult {
+	return pb.LatencyResult{
+		Server: &server,
+		Rtt:    &pb.LatencyPercentiles{P99: pb.DurationProto(rtt)},
+		Jitter: &pb.LatencyPercentiles{P99: pb.DurationProto(jitter)},
+	}
+}
@@ -19,10 +19,15 @@ package checks
 import (
 	"context"
 	"path/filepath"
+	"strconv"
+	"time"
 
 	"github.com/gravitational/gravity/lib/defaults"
 	"github.com/gravitational/gravity/lib/network/validation/proto"
+	"github.com/gravitational/gravity/lib/schema"
 	"github.com/gravitational/gravity/lib/state"
+	"github.com/gravitational/gravity/lib/storage"
+	"github.com/gravitational/gravity/lib/utils"
 
 	"github.com/gravitational/trace"
 )
@@ -50,14 +55,165 @@ func (r *checker) checkEtcdDisk(ctx context.Context, server Server) error {
 	return nil
 }
 
+// checkDiskProfiles benchmarks the disks with fio profiles
+// and makes sure they satisfy the profile disk requirements.
+func (r *checker) checkDiskProfiles(ctx context.Context, server Server) error {
+	requirements := r.Requirements[server.Server.Role]
+	var errors []error
+	for _, disk := range requirements.Disks {
+		// etcd only runs on master nodes
+		if disk.Path == schema.DiskMountEtcd && !server.IsMaster() {
+			continue
+		}
+		if err := r.checkDiskProfile(ctx, server, disk); err != nil {
+			errors = append(errors, err)
+		}
+	}
+	return trace.NewAggregate(errors...)
+}
+
+// checkDiskProfile runs the fio profile specified in the disk requirement
+// and compares the results with the requirement.
+func (r *checker) checkDiskProfile(ctx context.Context, server Server, disk Disk) error {
+	dir := diskPath(server.ServerInfo.StateDir, disk.Path)
+	spec, err := fioProfileJob(disk.Profile, filepath.Join(dir, testFile))
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	spec.Percentiles = []float64{disk.FsyncPercentile}
+	res, err := r.Remote.CheckDisks(ctx, server.AdvertiseIP, &proto.CheckDisksRequest{
+		Jobs: []*proto.FioJobSpec{spec},
+	})
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	log.Debugf("Server %v disk test results: %s.", server.Hostname, res.String())
+	if len(res.Jobs) != 1 {
+		return trace.BadParameter("expected 1 job result: %v", res)
+	}
+	benchmark := newDiskBenchmark(server, dir, disk, *res.Jobs[0])
+	if r.OnDiskBenchmark != nil {
+		r.OnDiskBenchmark(benchmark)
+	}
+	return trace.Wrap(checkDiskBenchmark(benchmark, disk))
+}
+
+// newDiskBenchmark returns the disk benchmark for the specified fio job result.
+func newDiskBenchmark(server Server, dir string, disk Disk, res proto.FioJobResult) storage.DiskBenchmark {
+	benchmark := storage.DiskBenchmark{
+		Hostname:    server.GetHostname(),
+		AdvertiseIP: server.AdvertiseIP,
+		Path:        dir,
+		Profile:     disk.Profile,
+		ReadIOPS:    res.GetReadIOPS(),
+		WriteIOPS:   res.GetWriteIOPS(),
+	}
+	if utils.StringInSlice(schema.DiskSyncProfiles, disk.Profile) {
+		// Only record the latency if fio has reported the percentile
+		// so a missing result is not mistaken for zero latency
+		if latency, ok := res.LookupFsyncLatencyPercentile(disk.FsyncPercentile); ok {
+			benchmark.FsyncLatency = map[string]time.Duration{
+				formatPercentile(disk.FsyncPercentile): time.Duration(latency),
+			}
+		}
+	}
+	return benchmark
+}
+
+// checkDiskBenchmark compares the disk benchmark with the disk requirement.
+func checkDiskBenchmark(benchmark storage.DiskBenchmark, disk Disk) error {
+	var errors []error
+	iops := benchmark.ReadIOPS + benchmark.WriteIOPS
+	if disk.MinIOPS != 0 && iops < disk.MinIOPS {
+		errors = append(errors, trace.BadParameter("server %v has low IOPS of %v on %v with %q profile (required minimum is %v)",
+			benchmark.Hostname, iops, benchmark.Path, benchmark.Profile, disk.MinIOPS))
+	}
+	percentile := formatPercentile(disk.FsyncPercentile)
+	latency, ok := benchmark.FsyncLatency[percentile]
+	if disk.MaxFsyncLatency != 0 && !ok {
+		errors = append(errors, trace.BadParameter("server %v p%v fsync latency on %v was not reported by the benchmark (required maximum is %v)",
+			benchmark.Hostname, percentile, benchmark.Path, disk.MaxFsyncLatency))
+	}
+	if disk.MaxFsyncLatency != 0 && ok && latency > disk.MaxFsyncLatency {
+		errors = append(errors, trace.BadParameter("server %v has high p%v fsync latency of %v on %v (required maximum is %v)",
+			benchmark.Hostname, percentile, latency, benchmark.Path, disk.MaxFsyncLatency))
+	}
+	return trace.NewAggregate(errors...)
+}
+
+// diskPath returns the directory on host for the specified disk
+// requirement path which can reference one of the well-known directories.
+func diskPath(stateDir, path string) string {
+	switch path {
+	case schema.DiskMountEtcd:
+		return state.InEtcdDir(stateDir, "")
+	case schema.DiskMountDocker:
+		return state.DockerDir(stateDir)
+	case schema.DiskMountState:
+		return stateDir
+	}
+	return path
+}
+
+// formatPercentile returns a textual representation of the percentile,
+// e.g. "99" or "99.9".
+func formatPercentile(percentile float64) string {
+	return strconv.FormatFloat(percentile, 'f', -1, 64)
+}
+
+// fioProfileJob returns the fio job spec for the specified built-in profile.
+func fioProfileJob(profile, filename string) (*proto.FioJobSpec, error) {
+	switch profile {
+	case schema.DiskProfileEtcd:
+		return fioEtcdSpec(filename), nil
+	case schema.DiskProfileSequentialWrite:
+		return &proto.FioJobSpec{
+			Name:      profile,
+			ReadWrite: "write",
+			IoEngine:  "libaio",
+			Direct:    true,
+			Filename:  filename,
+			BlockSize: "1m",
+			Size_:     "256m",
+			IoDepth:   16,
+			Runtime:   proto.DurationProto(defaults.DiskTestDuration),
+		}, nil
+	case schema.DiskProfileRandomRead, schema.DiskProfileRandomWrite:
+		readWrite := "randwrite"
+		if profile == schema.DiskProfileRandomRead {
+			readWrite = "randread"
+		}
+		return &proto.FioJobSpec{
+			Name:      profile,
+			ReadWrite: readWrite,
+			IoEngine:  "libaio",
+			Direct:    true,
+			Filename:  filename,
+			BlockSize: "4k",
+			Size_:     "64m",
+			IoDepth:   32,
+			Runtime:   proto.DurationProto(defaults.DiskTestDuration),
+		}, nil
+	}
+	return nil, trace.BadParameter("unsupported disk profile %q, supported profiles are %v",
+		profile, schema.DiskProfiles)
+}
+
 // fioEtcdJob constructs a request to check etcd disk performance.
 func fioEtcdJob(filename string) *proto.CheckDisksRequest {
+	return &proto.CheckDisksRequest{
+		Jobs: []*proto.FioJobSpec{fioEtcdSpec(filename)},
+	}
+}
+
+// fioEtcdSpec returns the fio job spec that mimics etcd write-ahead log.
+func fioEtcdSpec(filename string) *proto.FioJobSpec {
 	// The recommendations for the fio configuration for etcd disk test
 	// were adopted from the following blog post:
 	//
 	// https://www.ibm.com/cloud/blog/using-fio-to-tell-whether-your-storage-is-fast-enough-for-etcd
-	spec := &proto.FioJobSpec{
-		Name: "etcd",
+	return &proto.FioJobSpec{
+		Name: schema.DiskProfileEtcd,
 		// perform sequential writes
 		ReadWrite: "write",
 		// use write() syscall for writes
@@ -73,9 +229,6 @@ func fioEtcdJob(filename string) *proto.CheckDisksRequest {
 		// limit total test runtime
 		Runtime: proto.DurationProto(defaults.DiskTestDuration),
 	}
-	return &proto.CheckDisksRequest{
-		Jobs: []*proto.FioJobSpec{spec},
-	}
 }
 
 // formatEtcdErrors returns appropritate formatted error messages based
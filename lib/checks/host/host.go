@@ -0,0 +1,141 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package host implements checkers for host environment problems
+// commonly found on nodes before installation.
+// Failed probes carry checker data describing the problem so they can be
+// fixed automatically (see package autofix)
+package host
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+
+	"github.com/gravitational/satellite/agent/health"
+	"github.com/gravitational/satellite/agent/proto/agentpb"
+	"github.com/gravitational/satellite/monitoring"
+	"github.com/gravitational/trace"
+	"github.com/sirupsen/logrus"
+)
+
+var log = logrus.WithField(trace.Component, "checks:host")
+
+// DefaultCheckers returns the host environment checkers.
+// addr is the address the node hostname should resolve to
+func DefaultCheckers(addr string) []health.Checker {
+	return []health.Checker{
+		NewServiceChecker(DefaultConflictingServices...),
+		NewSwapChecker(),
+		NewSELinuxBooleanChecker(DefaultSELinuxBooleans...),
+		NewHostsChecker(addr),
+		NewTimeSyncChecker(),
+		NewHugepageChecker(),
+	}
+}
+
+const (
+	// ServiceCheckerID is the ID of the checker for conflicting system services
+	ServiceCheckerID = "conflicting-service"
+	// SwapCheckerID is the ID of the checker for enabled swap
+	SwapCheckerID = "swap"
+	// SELinuxBooleanCheckerID is the ID of the checker for SELinux booleans
+	SELinuxBooleanCheckerID = "selinux-boolean"
+	// HostsCheckerID is the ID of the checker for the hostname entry in /etc/hosts
+	HostsCheckerID = "hosts-entry"
+	// TimeSyncCheckerID is the ID of the checker for time synchronization
+	TimeSyncCheckerID = "time-sync"
+	// HugepageCheckerID is the ID of the checker for transparent hugepages
+	HugepageCheckerID = "transparent-hugepage"
+)
+
+// IsHostChecker returns true if the specified checker ID belongs to one
+// of the host environment checkers.
+// Fixes for these change the host configuration beyond gravity's own
+// requirements, so they are only applied on explicit request
+func IsHostChecker(checkerID string) bool {
+	switch checkerID {
+	case ServiceCheckerID, SwapCheckerID, SELinuxBooleanCheckerID,
+		HostsCheckerID, TimeSyncCheckerID, HugepageCheckerID:
+		return true
+	}
+	return false
+}
+
+// ServiceCheckerData gets attached to the conflicting service probes
+type ServiceCheckerData struct {
+	// Service is the name of the conflicting systemd service
+	Service string `json:"service"`
+}
+
+// SwapCheckerData gets attached to the swap probes
+type SwapCheckerData struct {
+	// Devices lists active swap areas
+	Devices []string `json:"devices"`
+}
+
+// SELinuxBooleanCheckerData gets attached to the SELinux boolean probes
+type SELinuxBooleanCheckerData struct {
+	// Name is the name of the SELinux boolean
+	Name string `json:"name"`
+	// Value is the expected value of the boolean
+	Value bool `json:"value"`
+}
+
+// HostsCheckerData gets attached to the hosts entry probes
+type HostsCheckerData struct {
+	// Hostname is the node hostname missing from /etc/hosts
+	Hostname string `json:"hostname"`
+	// Addr is the address the hostname should resolve to.
+	// Can be empty if the address could not be determined
+	Addr string `json:"addr,omitempty"`
+}
+
+// TimeSyncCheckerData gets attached to the time synchronization probes
+type TimeSyncCheckerData struct {
+	// NTPEnabled indicates whether network time synchronization is enabled
+	NTPEnabled bool `json:"ntp_enabled"`
+}
+
+// HugepageCheckerData gets attached to the transparent hugepages probes
+type HugepageCheckerData struct {
+	// Current is the currently active setting
+	Current string `json:"current"`
+	// Expected is the setting transparent hugepages should be configured with
+	Expected string `json:"expected"`
+}
+
+// newFailedProbe returns a new failed probe for the specified checker
+// with the given data attached
+func newFailedProbe(checker, detail string, data interface{}) *agentpb.Probe {
+	bytes, err := json.Marshal(data)
+	if err != nil {
+		return monitoring.NewProbeFromErr(checker,
+			fmt.Sprintf("failed to marshal %v", data), trace.Wrap(err))
+	}
+	return &agentpb.Probe{
+		Checker:     checker,
+		Detail:      detail,
+		Status:      agentpb.Probe_Failed,
+		CheckerData: bytes,
+	}
+}
+
+// hasCommand returns true if the specified command is available on the host
+func hasCommand(name string) bool {
+	_, err := exec.LookPath(name)
+	return err == nil
+}
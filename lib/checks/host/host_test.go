@@ -0,0 +1,108 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package host
+
+import (
+	"testing"
+
+	"gopkg.in/check.v1"
+)
+
+func TestHost(t *testing.T) { check.TestingT(t) }
+
+type HostSuite struct{}
+
+var _ = check.Suite(&HostSuite{})
+
+func (*HostSuite) TestParsesSwaps(c *check.C) {
+	data := []byte(`Filename				Type		Size	Used	Priority
+/dev/sda2                               partition	2097148	0	-2
+/swapfile                               file		1048572	0	-3
+`)
+	c.Assert(ParseSwaps(data), check.DeepEquals, []string{"/dev/sda2", "/swapfile"})
+	c.Assert(ParseSwaps([]byte("Filename\tType\tSize\tUsed\tPriority\n")), check.IsNil)
+}
+
+func (*HostSuite) TestCommentsOutSwap(c *check.C) {
+	data := []byte(`UUID=1234 / ext4 defaults 0 1
+/dev/sda2 none swap sw 0 0
+#/dev/sda3 none swap sw 0 0
+`)
+	result, entries := CommentOutSwap(data)
+	c.Assert(entries, check.DeepEquals, []string{"/dev/sda2 none swap sw 0 0"})
+	c.Assert(string(result), check.Equals, `UUID=1234 / ext4 defaults 0 1
+#/dev/sda2 none swap sw 0 0
+#/dev/sda3 none swap sw 0 0
+`)
+}
+
+func (*HostSuite) TestParsesSELinuxBoolean(c *check.C) {
+	value, err := ParseSELinuxBoolean("container_manage_cgroup --> on\n")
+	c.Assert(err, check.IsNil)
+	c.Assert(value, check.Equals, true)
+	value, err = ParseSELinuxBoolean("container_manage_cgroup --> off\n")
+	c.Assert(err, check.IsNil)
+	c.Assert(value, check.Equals, false)
+	_, err = ParseSELinuxBoolean("Error getting active value for foo")
+	c.Assert(err, check.NotNil)
+}
+
+func (*HostSuite) TestFindsHostsEntry(c *check.C) {
+	data := []byte(`127.0.0.1 localhost
+# 10.0.0.2 node-2
+10.0.0.1 node-1.example.com node-1 # primary
+`)
+	c.Assert(HasHostsEntry(data, "node-1"), check.Equals, true)
+	c.Assert(HasHostsEntry(data, "NODE-1.example.com"), check.Equals, true)
+	c.Assert(HasHostsEntry(data, "node-2"), check.Equals, false)
+}
+
+func (*HostSuite) TestParsesTimedatectl(c *check.C) {
+	testCases := []struct {
+		out     string
+		status  TimeSyncStatus
+		comment string
+	}{
+		{
+			out: `      Local time: Mon 2019-06-03 10:00:00 UTC
+System clock synchronized: yes
+              NTP service: active
+          RTC in local TZ: no`,
+			status:  TimeSyncStatus{NTPEnabled: true, Synchronized: true},
+			comment: "newer format",
+		},
+		{
+			out: `      Local time: Mon 2019-06-03 10:00:00 UTC
+     NTP enabled: no
+NTP synchronized: no`,
+			status:  TimeSyncStatus{},
+			comment: "older format",
+		},
+	}
+	for _, tc := range testCases {
+		c.Assert(ParseTimedatectl(tc.out), check.DeepEquals, tc.status, check.Commentf(tc.comment))
+	}
+}
+
+func (*HostSuite) TestParsesHugepageSetting(c *check.C) {
+	value, err := ParseHugepageSetting("[always] madvise never\n")
+	c.Assert(err, check.IsNil)
+	c.Assert(value, check.Equals, "always")
+	value, err = ParseHugepageSetting("always madvise [never]\n")
+	c.Assert(err, check.IsNil)
+	c.Assert(value, check.Equals, "never")
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package host
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/gravitational/gravity/lib/defaults"
+	"github.com/gravitational/gravity/lib/systeminfo"
+
+	"github.com/gravitational/satellite/agent/health"
+	"github.com/gravitational/satellite/monitoring"
+	"github.com/gravitational/trace"
+)
+
+// NewHostsChecker returns a checker that verifies that the node hostname
+// can be resolved.
+// addr is the address the hostname should resolve to. If unspecified, the
+// only non-loopback address of the node is used
+func NewHostsChecker(addr string) health.Checker {
+	return &hostsChecker{addr: addr}
+}
+
+type hostsChecker struct {
+	addr string
+}
+
+// Name returns the name of this checker.
+// Implements health.Checker
+func (c *hostsChecker) Name() string {
+	return HostsCheckerID
+}
+
+// Check verifies that the node hostname is either listed in /etc/hosts
+// or can be resolved via DNS.
+// Implements health.Checker
+func (c *hostsChecker) Check(ctx context.Context, reporter health.Reporter) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		reporter.Add(monitoring.NewProbeFromErr(c.Name(), "failed to query hostname",
+			trace.ConvertSystemError(err)))
+		return
+	}
+	data, err := ioutil.ReadFile(defaults.HostsPath)
+	if err != nil && !os.IsNotExist(err) {
+		reporter.Add(monitoring.NewProbeFromErr(c.Name(), "failed to read hosts file",
+			trace.ConvertSystemError(err)))
+		return
+	}
+	if HasHostsEntry(data, hostname) {
+		reporter.Add(monitoring.NewSuccessProbe(c.Name()))
+		return
+	}
+	if _, err := net.DefaultResolver.LookupHost(ctx, hostname); err == nil {
+		reporter.Add(monitoring.NewSuccessProbe(c.Name()))
+		return
+	}
+	addr, err := c.hostAddr()
+	if err != nil {
+		log.WithError(err).Warn("Failed to determine host address.")
+	}
+	reporter.Add(newFailedProbe(c.Name(),
+		fmt.Sprintf("hostname %v cannot be resolved, add it to %v", hostname, defaults.HostsPath),
+		HostsCheckerData{Hostname: hostname, Addr: addr}))
+}
+
+// hostAddr returns the address the hostname should resolve to
+func (c *hostsChecker) hostAddr() (string, error) {
+	if c.addr != "" {
+		return c.addr, nil
+	}
+	ifaces, err := systeminfo.NetworkInterfaces()
+	if err != nil {
+		return "", trace.Wrap(err)
+	}
+	if len(ifaces) != 1 {
+		return "", trace.NotFound("expected a single network interface but found %v", len(ifaces))
+	}
+	return ifaces[0].IPv4, nil
+}
+
+// HasHostsEntry returns true if the contents of the hosts file
+// contain an entry for the specified hostname
+func HasHostsEntry(data []byte, hostname string) bool {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		for _, name := range fields[1:] {
+			if strings.EqualFold(name, hostname) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package host
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/gravitational/gravity/lib/defaults"
+
+	"github.com/gravitational/satellite/agent/health"
+	"github.com/gravitational/satellite/monitoring"
+	"github.com/gravitational/trace"
+)
+
+// HugepageNever is the transparent hugepages setting that disables them
+const HugepageNever = "never"
+
+// NewHugepageChecker returns a checker that verifies that transparent
+// hugepages are not enabled system-wide.
+// Always-on transparent hugepages cause latency spikes in etcd and
+// many databases commonly deployed in the cluster
+func NewHugepageChecker() health.Checker {
+	return &hugepageChecker{}
+}
+
+type hugepageChecker struct{}
+
+// Name returns the name of this checker.
+// Implements health.Checker
+func (c *hugepageChecker) Name() string {
+	return HugepageCheckerID
+}
+
+// Check verifies the transparent hugepages setting.
+// Implements health.Checker
+func (c *hugepageChecker) Check(ctx context.Context, reporter health.Reporter) {
+	data, err := ioutil.ReadFile(defaults.TransparentHugepagePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			reporter.Add(monitoring.NewSuccessProbe(c.Name()))
+			return
+		}
+		reporter.Add(monitoring.NewProbeFromErr(c.Name(),
+			"failed to query transparent hugepages setting", trace.ConvertSystemError(err)))
+		return
+	}
+	current, err := ParseHugepageSetting(string(data))
+	if err != nil {
+		reporter.Add(monitoring.NewProbeFromErr(c.Name(),
+			"failed to query transparent hugepages setting", err))
+		return
+	}
+	if current != "always" {
+		reporter.Add(monitoring.NewSuccessProbe(c.Name()))
+		return
+	}
+	reporter.Add(newFailedProbe(c.Name(),
+		fmt.Sprintf("transparent hugepages are set to %q, should be %q or %q",
+			current, "madvise", HugepageNever),
+		HugepageCheckerData{Current: current, Expected: HugepageNever}))
+}
+
+// ParseHugepageSetting returns the active transparent hugepages setting
+// from the contents of the sysfs file in the form:
+//
+//	always madvise [never]
+func ParseHugepageSetting(data string) (string, error) {
+	for _, value := range strings.Fields(data) {
+		if strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]") {
+			return strings.Trim(value, "[]"), nil
+		}
+	}
+	return "", trace.BadParameter("unexpected transparent hugepages setting: %q", data)
+}
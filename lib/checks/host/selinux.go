@@ -0,0 +1,122 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package host
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gravitational/gravity/lib/defaults"
+	"github.com/gravitational/gravity/lib/utils"
+
+	"github.com/gravitational/satellite/agent/health"
+	"github.com/gravitational/satellite/monitoring"
+	"github.com/gravitational/trace"
+)
+
+// SELinuxBoolean describes an SELinux boolean with the expected value
+type SELinuxBoolean struct {
+	// Name is the name of the boolean
+	Name string
+	// Value is the expected value
+	Value bool
+}
+
+// DefaultSELinuxBooleans lists SELinux booleans required by the cluster.
+// Planet runs systemd inside a container which needs to manage cgroups
+var DefaultSELinuxBooleans = []SELinuxBoolean{
+	{Name: "container_manage_cgroup", Value: true},
+}
+
+// NewSELinuxBooleanChecker returns a checker that verifies the values
+// of the specified SELinux booleans
+func NewSELinuxBooleanChecker(booleans ...SELinuxBoolean) health.Checker {
+	return &selinuxBooleanChecker{booleans: booleans}
+}
+
+type selinuxBooleanChecker struct {
+	booleans []SELinuxBoolean
+}
+
+// Name returns the name of this checker.
+// Implements health.Checker
+func (c *selinuxBooleanChecker) Name() string {
+	return SELinuxBooleanCheckerID
+}
+
+// Check verifies the values of SELinux booleans if SELinux is enabled.
+// Implements health.Checker
+func (c *selinuxBooleanChecker) Check(ctx context.Context, reporter health.Reporter) {
+	if _, err := os.Stat(defaults.SELinuxEnforcePath); err != nil || !hasCommand("getsebool") {
+		reporter.Add(monitoring.NewSuccessProbe(c.Name()))
+		return
+	}
+	var failed bool
+	for _, boolean := range c.booleans {
+		out, err := utils.RunCommand(ctx, nil, "getsebool", boolean.Name)
+		if err != nil {
+			// The boolean is not defined by the loaded policy
+			log.Debugf("Failed to query SELinux boolean %v: %s.", boolean.Name, out)
+			continue
+		}
+		value, err := ParseSELinuxBoolean(string(out))
+		if err != nil {
+			failed = true
+			reporter.Add(monitoring.NewProbeFromErr(c.Name(),
+				fmt.Sprintf("failed to query SELinux boolean %v", boolean.Name), err))
+			continue
+		}
+		if value == boolean.Value {
+			continue
+		}
+		failed = true
+		reporter.Add(newFailedProbe(c.Name(),
+			fmt.Sprintf("SELinux boolean %v should be %v", boolean.Name, formatBoolean(boolean.Value)),
+			SELinuxBooleanCheckerData{Name: boolean.Name, Value: boolean.Value}))
+	}
+	if !failed {
+		reporter.Add(monitoring.NewSuccessProbe(c.Name()))
+	}
+}
+
+// ParseSELinuxBoolean parses the output of getsebool for a single boolean
+// in the form:
+//
+//	container_manage_cgroup --> off
+func ParseSELinuxBoolean(out string) (bool, error) {
+	parts := strings.Split(strings.TrimSpace(out), "-->")
+	if len(parts) != 2 {
+		return false, trace.BadParameter("unexpected getsebool output: %q", out)
+	}
+	switch value := strings.TrimSpace(parts[1]); value {
+	case "on":
+		return true, nil
+	case "off":
+		return false, nil
+	default:
+		return false, trace.BadParameter("unexpected SELinux boolean value: %q", value)
+	}
+}
+
+func formatBoolean(value bool) string {
+	if value {
+		return "on"
+	}
+	return "off"
+}
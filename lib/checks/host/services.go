@@ -0,0 +1,73 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package host
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gravitational/gravity/lib/utils"
+
+	"github.com/gravitational/satellite/agent/health"
+	"github.com/gravitational/satellite/monitoring"
+)
+
+// DefaultConflictingServices lists system services known to interfere
+// with the cluster: firewalld rewrites iptables rules managed by kube-proxy
+// and dnsmasq occupies the DNS port used by the cluster DNS
+var DefaultConflictingServices = []string{"firewalld", "dnsmasq"}
+
+// NewServiceChecker returns a checker that verifies that none of the
+// specified systemd services are active
+func NewServiceChecker(services ...string) health.Checker {
+	return &serviceChecker{services: services}
+}
+
+type serviceChecker struct {
+	services []string
+}
+
+// Name returns the name of this checker.
+// Implements health.Checker
+func (c *serviceChecker) Name() string {
+	return ServiceCheckerID
+}
+
+// Check verifies that none of the conflicting services are active.
+// Implements health.Checker
+func (c *serviceChecker) Check(ctx context.Context, reporter health.Reporter) {
+	if !hasCommand("systemctl") {
+		reporter.Add(monitoring.NewSuccessProbe(c.Name()))
+		return
+	}
+	var conflicts bool
+	for _, service := range c.services {
+		// is-active exits with a non-zero code for inactive or unknown services
+		out, _ := utils.RunCommand(ctx, nil, "systemctl", "is-active", service)
+		if strings.TrimSpace(string(out)) != "active" {
+			continue
+		}
+		conflicts = true
+		reporter.Add(newFailedProbe(c.Name(),
+			fmt.Sprintf("conflicting service %v is active", service),
+			ServiceCheckerData{Service: service}))
+	}
+	if !conflicts {
+		reporter.Add(monitoring.NewSuccessProbe(c.Name()))
+	}
+}
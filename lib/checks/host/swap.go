@@ -0,0 +1,104 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package host
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/gravitational/gravity/lib/defaults"
+
+	"github.com/gravitational/satellite/agent/health"
+	"github.com/gravitational/satellite/monitoring"
+	"github.com/gravitational/trace"
+)
+
+// NewSwapChecker returns a checker that verifies that swap is disabled
+func NewSwapChecker() health.Checker {
+	return &swapChecker{}
+}
+
+type swapChecker struct{}
+
+// Name returns the name of this checker.
+// Implements health.Checker
+func (c *swapChecker) Name() string {
+	return SwapCheckerID
+}
+
+// Check verifies that there are no active swap areas.
+// Implements health.Checker
+func (c *swapChecker) Check(ctx context.Context, reporter health.Reporter) {
+	data, err := ioutil.ReadFile(defaults.SwapsPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			reporter.Add(monitoring.NewSuccessProbe(c.Name()))
+			return
+		}
+		reporter.Add(monitoring.NewProbeFromErr(c.Name(), "failed to query swap areas",
+			trace.ConvertSystemError(err)))
+		return
+	}
+	devices := ParseSwaps(data)
+	if len(devices) == 0 {
+		reporter.Add(monitoring.NewSuccessProbe(c.Name()))
+		return
+	}
+	reporter.Add(newFailedProbe(c.Name(),
+		fmt.Sprintf("swap is enabled on %v, it must be disabled for Kubernetes",
+			strings.Join(devices, ", ")),
+		SwapCheckerData{Devices: devices}))
+}
+
+// ParseSwaps returns the list of active swap areas from the contents
+// of /proc/swaps
+func ParseSwaps(data []byte) (devices []string) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		// Skip the header
+		if len(fields) == 0 || fields[0] == "Filename" {
+			continue
+		}
+		devices = append(devices, fields[0])
+	}
+	return devices
+}
+
+// CommentOutSwap returns the contents of the fstab file with all swap
+// entries commented out.
+// Returns the list of entries that have been commented out
+func CommentOutSwap(data []byte) (result []byte, entries []string) {
+	var buf bytes.Buffer
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		fields := strings.Fields(line)
+		if len(fields) >= 3 && !strings.HasPrefix(fields[0], "#") && fields[2] == "swap" {
+			entries = append(entries, line)
+			line = "#" + line
+		}
+		buf.WriteString(line)
+		buf.WriteString("\n")
+	}
+	return buf.Bytes(), entries
+}
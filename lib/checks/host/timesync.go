@@ -0,0 +1,100 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package host
+
+import (
+	"bufio"
+	"context"
+	"strings"
+
+	"github.com/gravitational/gravity/lib/utils"
+
+	"github.com/gravitational/satellite/agent/health"
+	"github.com/gravitational/satellite/monitoring"
+	"github.com/gravitational/trace"
+)
+
+// NewTimeSyncChecker returns a checker that verifies that the system clock
+// is synchronized with network time
+func NewTimeSyncChecker() health.Checker {
+	return &timeSyncChecker{}
+}
+
+type timeSyncChecker struct{}
+
+// Name returns the name of this checker.
+// Implements health.Checker
+func (c *timeSyncChecker) Name() string {
+	return TimeSyncCheckerID
+}
+
+// Check verifies the time synchronization status reported by timedatectl.
+// Implements health.Checker
+func (c *timeSyncChecker) Check(ctx context.Context, reporter health.Reporter) {
+	if !hasCommand("timedatectl") {
+		reporter.Add(monitoring.NewSuccessProbe(c.Name()))
+		return
+	}
+	out, err := utils.RunCommand(ctx, nil, "timedatectl", "status")
+	if err != nil {
+		reporter.Add(monitoring.NewProbeFromErr(c.Name(),
+			"failed to query time synchronization status", trace.Wrap(err, "%s", out)))
+		return
+	}
+	status := ParseTimedatectl(string(out))
+	if status.Synchronized {
+		reporter.Add(monitoring.NewSuccessProbe(c.Name()))
+		return
+	}
+	detail := "system clock is not synchronized, enable network time synchronization"
+	if status.NTPEnabled {
+		detail = "system clock is not synchronized although network time synchronization is enabled"
+	}
+	reporter.Add(newFailedProbe(c.Name(), detail,
+		TimeSyncCheckerData{NTPEnabled: status.NTPEnabled}))
+}
+
+// TimeSyncStatus describes the time synchronization status
+type TimeSyncStatus struct {
+	// NTPEnabled indicates whether network time synchronization is enabled
+	NTPEnabled bool
+	// Synchronized indicates whether the system clock is synchronized
+	Synchronized bool
+}
+
+// ParseTimedatectl parses the output of timedatectl status.
+// Both the older ("NTP enabled", "NTP synchronized") and newer
+// ("NTP service", "System clock synchronized") formats are supported
+func ParseTimedatectl(out string) (status TimeSyncStatus) {
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		parts := strings.SplitN(scanner.Text(), ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		value := strings.TrimSpace(parts[1])
+		switch strings.TrimSpace(parts[0]) {
+		case "NTP enabled", "Network time on":
+			status.NTPEnabled = value == "yes"
+		case "NTP service":
+			status.NTPEnabled = value == "active"
+		case "NTP synchronized", "System clock synchronized":
+			status.Synchronized = value == "yes"
+		}
+	}
+	return status
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/gravitational/gravity/lib/defaults"
 	pb "github.com/gravitational/gravity/lib/network/validation/proto"
 	"github.com/gravitational/gravity/lib/utils"
 
@@ -34,7 +35,7 @@ type PingPongRequest struct {
 	Ping []pb.Addr `json:"ping"`
 	// Duration is the duration of the game
 	Duration time.Duration `json:"duration"`
-	// Mode is the game mode: pingpong or bandwidth
+	// Mode is the game mode: pingpong, bandwidth, pathmtu, latency, packetloss or vxlan
 	Mode string `json:"mode"`
 }
 
@@ -44,11 +45,19 @@ const (
 	ModePingPong = "pingpong"
 	// ModeBandwidth is the mode for testing bandwidth between servers
 	ModeBandwidth = "bandwidth"
+	// ModePathMTU is the mode for discovering path MTU between servers
+	ModePathMTU = "pathmtu"
+	// ModeLatency is the mode for measuring latency between servers
+	ModeLatency = "latency"
+	// ModePacketLoss is the mode for measuring UDP packet loss between servers
+	ModePacketLoss = "packetloss"
+	// ModeVXLAN is the mode for testing overlay network data path between servers
+	ModeVXLAN = "vxlan"
 )
 
 // Checks makes sure the request is correct
 func (r PingPongRequest) Check() error {
-	if !utils.StringInSlice([]string{ModePingPong, ModeBandwidth}, r.Mode) {
+	if !utils.StringInSlice([]string{ModePingPong, ModeBandwidth, ModePathMTU, ModeLatency, ModePacketLoss, ModeVXLAN}, r.Mode) {
 		return trace.BadParameter("unsupported mode %q", r.Mode)
 	}
 	if len(r.Listen) < 1 {
@@ -99,6 +108,61 @@ func (r PingPongRequest) BandwidthProto() *pb.CheckBandwidthRequest {
 	}
 }
 
+// PathMTUProto converts this request to protobuf format
+func (r PingPongRequest) PathMTUProto() *pb.CheckPathMTURequest {
+	listen := r.Listen[0]
+	return &pb.CheckPathMTURequest{
+		Listen:   &listen,
+		Ping:     r.pings(),
+		Duration: pb.DurationProto(r.Duration),
+		MaxMtu:   defaults.NetworkProbeMaxMTU,
+	}
+}
+
+// LatencyProto converts this request to protobuf format
+func (r PingPongRequest) LatencyProto() *pb.CheckLatencyRequest {
+	listen := r.Listen[0]
+	return &pb.CheckLatencyRequest{
+		Listen:   &listen,
+		Ping:     r.pings(),
+		Duration: pb.DurationProto(r.Duration),
+		Count:    defaults.NetworkProbeCount,
+	}
+}
+
+// PacketLossProto converts this request to protobuf format
+func (r PingPongRequest) PacketLossProto() *pb.CheckPacketLossRequest {
+	listen := r.Listen[0]
+	return &pb.CheckPacketLossRequest{
+		Listen:   &listen,
+		Ping:     r.pings(),
+		Duration: pb.DurationProto(r.Duration),
+		Count:    defaults.NetworkProbeCount,
+	}
+}
+
+// VXLANProto converts this request to protobuf format.
+// The VXLAN traffic is sent over the specified port
+func (r PingPongRequest) VXLANProto(port int) *pb.CheckVXLANRequest {
+	listen := r.Listen[0]
+	if port == 0 {
+		port = defaults.VxlanPort
+	}
+	return &pb.CheckVXLANRequest{
+		Listen:   &listen,
+		Ping:     r.pings(),
+		Duration: pb.DurationProto(r.Duration),
+		Port:     int32(port),
+	}
+}
+
+func (r PingPongRequest) pings() (pings []*pb.Addr) {
+	for i := range r.Ping {
+		pings = append(pings, &r.Ping[i])
+	}
+	return pings
+}
+
 // ResultFromPortsProto converts protobuf response to PingPongResult
 func ResultFromPortsProto(resp *pb.CheckPortsResponse, err error) *PingPongResult {
 	result := &PingPongResult{}
@@ -125,6 +189,58 @@ func ResultFromBandwidthProto(resp *pb.CheckBandwidthResponse, err error) *PingP
 	return result
 }
 
+// ResultFromPathMTUProto converts protobuf response to PingPongResult
+func ResultFromPathMTUProto(resp *pb.CheckPathMTUResponse, err error) *PingPongResult {
+	result := &PingPongResult{}
+	if err != nil {
+		result.Code = 1
+		result.Message = err.Error()
+	}
+	for _, mtu := range resp.Results {
+		result.PathMTUResults = append(result.PathMTUResults, *mtu)
+	}
+	return result
+}
+
+// ResultFromLatencyProto converts protobuf response to PingPongResult
+func ResultFromLatencyProto(resp *pb.CheckLatencyResponse, err error) *PingPongResult {
+	result := &PingPongResult{}
+	if err != nil {
+		result.Code = 1
+		result.Message = err.Error()
+	}
+	for _, latency := range resp.Results {
+		result.LatencyResults = append(result.LatencyResults, *latency)
+	}
+	return result
+}
+
+// ResultFromPacketLossProto converts protobuf response to PingPongResult
+func ResultFromPacketLossProto(resp *pb.CheckPacketLossResponse, err error) *PingPongResult {
+	result := &PingPongResult{}
+	if err != nil {
+		result.Code = 1
+		result.Message = err.Error()
+	}
+	for _, loss := range resp.Results {
+		result.PacketLossResults = append(result.PacketLossResults, *loss)
+	}
+	return result
+}
+
+// ResultFromVXLANProto converts protobuf response to PingPongResult
+func ResultFromVXLANProto(resp *pb.CheckVXLANResponse, err error) *PingPongResult {
+	result := &PingPongResult{}
+	if err != nil {
+		result.Code = 1
+		result.Message = err.Error()
+	}
+	for _, vxlan := range resp.Results {
+		result.VXLANResults = append(result.VXLANResults, *vxlan)
+	}
+	return result
+}
+
 // PingPongResult is a result of a ping-pong game
 type PingPongResult struct {
 	// Code means that the whole operation has succeded
@@ -140,6 +256,14 @@ type PingPongResult struct {
 	PingResults []pb.ServerResult `json:"ping_results"`
 	// BandwidthResult is the result of the bandwidth test
 	BandwidthResult uint64 `json:"bandwidth_result"`
+	// PathMTUResults contains path MTU discovered to remote servers
+	PathMTUResults []pb.PathMTUResult `json:"path_mtu_results,omitempty"`
+	// LatencyResults contains latency measured to remote servers
+	LatencyResults []pb.LatencyResult `json:"latency_results,omitempty"`
+	// PacketLossResults contains packet loss measured to remote servers
+	PacketLossResults []pb.PacketLossResult `json:"packet_loss_results,omitempty"`
+	// VXLANResults contains results of the overlay network test to remote servers
+	VXLANResults []pb.VXLANResult `json:"vxlan_results,omitempty"`
 }
 
 // FailureCount returns number of failures in the result
@@ -187,6 +311,32 @@ func (p *PingPongGameResults) Failures() []string {
 					addr, ping.Server.Addr, ping.Server.Network))
 			}
 		}
+		for _, mtu := range result.PathMTUResults {
+			if mtu.Code != 0 {
+				out = append(out, fmt.Sprintf(
+					"server %v failed to discover path MTU to %v: %v",
+					addr, mtu.Server.Addr, mtu.Error))
+			}
+		}
+		for _, latency := range result.LatencyResults {
+			if latency.Code != 0 {
+				out = append(out, fmt.Sprintf(
+					"server %v failed to measure latency to %v: %v",
+					addr, latency.Server.Addr, latency.Error))
+			}
+		}
+		for _, loss := range result.PacketLossResults {
+			if loss.Code != 0 {
+				out = append(out, fmt.Sprintf(
+					"server %v failed to measure packet loss to %v: %v",
+					addr, loss.Server.Addr, loss.Error))
+			}
+		}
+		for _, vxlan := range result.VXLANResults {
+			if vxlan.Code != 0 {
+				out = append(out, vxlan.Error)
+			}
+		}
 	}
 	return out
 }
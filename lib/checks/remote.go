@@ -22,6 +22,7 @@ import (
 	"io"
 	"time"
 
+	"github.com/gravitational/gravity/lib/defaults"
 	"github.com/gravitational/gravity/lib/network/validation/proto"
 	"github.com/gravitational/gravity/lib/rpc"
 	"github.com/gravitational/gravity/lib/rpc/client"
@@ -41,6 +42,14 @@ type Remote interface {
 	CheckPorts(context.Context, PingPongGame) (PingPongGameResults, error)
 	// CheckBandwidth executes network bandwidth test.
 	CheckBandwidth(context.Context, PingPongGame) (PingPongGameResults, error)
+	// CheckPathMTU executes network test to discover path MTU between nodes.
+	CheckPathMTU(context.Context, PingPongGame) (PingPongGameResults, error)
+	// CheckLatency executes network latency test.
+	CheckLatency(context.Context, PingPongGame) (PingPongGameResults, error)
+	// CheckPacketLoss executes network UDP packet loss test.
+	CheckPacketLoss(context.Context, PingPongGame) (PingPongGameResults, error)
+	// CheckVXLAN executes overlay network data path test.
+	CheckVXLAN(context.Context, PingPongGame) (PingPongGameResults, error)
 	// CheckDisks executes disk performance test on the specified node.
 	CheckDisks(ctx context.Context, addr string, req *proto.CheckDisksRequest) (*proto.CheckDisksResponse, error)
 	// Validate performs local checks on the specified node.
@@ -79,16 +88,22 @@ type remote struct {
 // Exec executes the command remotely on the specified node.
 //
 // The command's output is written to the provided writer.
+// The command is aborted if it does not complete within defaults.RPCAgentExecTimeout
 func (r *remote) Exec(ctx context.Context, addr string, command []string, out io.Writer) error {
 	clt, err := r.GetClient(ctx, addr)
 	if err != nil {
 		return trace.Wrap(err)
 	}
-	err = clt.Command(ctx, r.FieldLogger, out, command...)
+	result, err := clt.Exec(ctx, client.ExecRequest{
+		Args:    command,
+		Stdout:  out,
+		Stderr:  out,
+		Timeout: defaults.RPCAgentExecTimeout,
+	})
 	if err != nil {
 		return trace.Wrap(err)
 	}
-	return nil
+	return trace.Wrap(result.Check())
 }
 
 // CheckPorts executes network test to test port availability.
@@ -109,6 +124,42 @@ func (r *remote) CheckBandwidth(ctx context.Context, req PingPongGame) (PingPong
 	return resp, nil
 }
 
+// CheckPathMTU executes network test to discover path MTU between nodes.
+func (r *remote) CheckPathMTU(ctx context.Context, req PingPongGame) (PingPongGameResults, error) {
+	resp, err := pingPong(ctx, r, req, pathMTU)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return resp, nil
+}
+
+// CheckLatency executes network latency test.
+func (r *remote) CheckLatency(ctx context.Context, req PingPongGame) (PingPongGameResults, error) {
+	resp, err := pingPong(ctx, r, req, latency)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return resp, nil
+}
+
+// CheckPacketLoss executes network UDP packet loss test.
+func (r *remote) CheckPacketLoss(ctx context.Context, req PingPongGame) (PingPongGameResults, error) {
+	resp, err := pingPong(ctx, r, req, packetLoss)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return resp, nil
+}
+
+// CheckVXLAN executes overlay network data path test.
+func (r *remote) CheckVXLAN(ctx context.Context, req PingPongGame) (PingPongGameResults, error) {
+	resp, err := pingPong(ctx, r, req, vxlan)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return resp, nil
+}
+
 // CheckDisks executes disk performance test.
 func (r *remote) CheckDisks(ctx context.Context, addr string, req *proto.CheckDisksRequest) (*proto.CheckDisksResponse, error) {
 	clt, err := r.GetClient(ctx, addr)
@@ -190,6 +241,42 @@ func bandwidth(ctx context.Context, addr string, clt client.Client, req PingPong
 	resultsCh <- pingpongResult{addr: addr, resp: ResultFromBandwidthProto(resp, nil)}
 }
 
+func pathMTU(ctx context.Context, addr string, clt client.Client, req PingPongRequest, resultsCh chan<- pingpongResult) {
+	resp, err := clt.CheckPathMTU(ctx, req.PathMTUProto())
+	if err != nil {
+		resultsCh <- pingpongResult{addr: addr, err: err}
+		return
+	}
+	resultsCh <- pingpongResult{addr: addr, resp: ResultFromPathMTUProto(resp, nil)}
+}
+
+func latency(ctx context.Context, addr string, clt client.Client, req PingPongRequest, resultsCh chan<- pingpongResult) {
+	resp, err := clt.CheckLatency(ctx, req.LatencyProto())
+	if err != nil {
+		resultsCh <- pingpongResult{addr: addr, err: err}
+		return
+	}
+	resultsCh <- pingpongResult{addr: addr, resp: ResultFromLatencyProto(resp, nil)}
+}
+
+func packetLoss(ctx context.Context, addr string, clt client.Client, req PingPongRequest, resultsCh chan<- pingpongResult) {
+	resp, err := clt.CheckPacketLoss(ctx, req.PacketLossProto())
+	if err != nil {
+		resultsCh <- pingpongResult{addr: addr, err: err}
+		return
+	}
+	resultsCh <- pingpongResult{addr: addr, resp: ResultFromPacketLossProto(resp, nil)}
+}
+
+func vxlan(ctx context.Context, addr string, clt client.Client, req PingPongRequest, resultsCh chan<- pingpongResult) {
+	resp, err := clt.CheckVXLAN(ctx, req.VXLANProto(0))
+	if err != nil {
+		resultsCh <- pingpongResult{addr: addr, err: err}
+		return
+	}
+	resultsCh <- pingpongResult{addr: addr, resp: ResultFromVXLANProto(resp, nil)}
+}
+
 type pingpongHandler func(ctx context.Context, addr string, clt client.Client,
 	req PingPongRequest, resultsCh chan<- pingpongResult)
 
@@ -17,6 +17,8 @@ limitations under the License.
 package checks
 
 import (
+	"time"
+
 	"github.com/gravitational/gravity/lib/schema"
 	"github.com/gravitational/gravity/lib/storage"
 	"github.com/gravitational/gravity/lib/utils"
@@ -38,6 +40,23 @@ type Requirements struct {
 	Volumes []schema.Volume
 	// Docker describes Docker requirements.
 	Docker storage.DockerConfig
+	// Disks describes disk performance requirements.
+	Disks []Disk
+}
+
+// Disk describes performance requirements for the disk backing a directory.
+type Disk struct {
+	// Path is the directory on host or one of the well-known directory names.
+	Path string
+	// Profile is the name of the fio profile to evaluate the disk with.
+	Profile string
+	// MinIOPS is the minimum required number of I/O operations per second.
+	MinIOPS float64
+	// MaxFsyncLatency is the maximum allowed fsync latency
+	// at FsyncPercentile.
+	MaxFsyncLatency time.Duration
+	// FsyncPercentile is the fsync latency percentile.
+	FsyncPercentile float64
 }
 
 // Network describes network requirements.
@@ -46,6 +65,17 @@ type Network struct {
 	MinTransferRate utils.TransferRate
 	// Ports specifies requirements for ports to be available on server.
 	Ports Ports
+	// MinMTU is the minimum required path MTU to other servers.
+	MinMTU int
+	// MaxRTT is the maximum allowed 99th percentile of round-trip time
+	// to other servers.
+	MaxRTT time.Duration
+	// MaxJitter is the maximum allowed 99th percentile of jitter
+	// to other servers.
+	MaxJitter time.Duration
+	// MaxPacketLoss is the maximum allowed UDP packet loss to other
+	// servers, in percent.
+	MaxPacketLoss float64
 }
 
 // Ports describes port requirements for a specific profile.
@@ -73,13 +103,45 @@ func RequirementsFromManifest(manifest schema.Manifest) (map[string]Requirements
 			Network: Network{
 				MinTransferRate: profile.Requirements.Network.MinTransferRate,
 				Ports:           Ports{TCP: tcp, UDP: udp},
+				MinMTU:          profile.Requirements.Network.MinMTU,
+				MaxPacketLoss:   profile.Requirements.Network.MaxPacketLoss,
 			},
 		}
+		if rtt := profile.Requirements.Network.MaxRTT; rtt != nil {
+			req.Network.MaxRTT = rtt.Duration
+		}
+		if jitter := profile.Requirements.Network.MaxJitter; jitter != nil {
+			req.Network.MaxJitter = jitter.Duration
+		}
+		req.Disks, err = disksFromManifest(profile.Requirements.Disks)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
 		result[profile.Name] = req
 	}
 	return result, nil
 }
 
+// disksFromManifest converts disk requirements from the manifest.
+func disksFromManifest(disks []schema.Disk) (result []Disk, err error) {
+	for _, disk := range disks {
+		if err := disk.CheckAndSetDefaults(); err != nil {
+			return nil, trace.Wrap(err)
+		}
+		req := Disk{
+			Path:            disk.Path,
+			Profile:         disk.Profile,
+			MinIOPS:         disk.MinIOPS,
+			FsyncPercentile: disk.FsyncPercentile,
+		}
+		if latency := disk.MaxFsyncLatency; latency != nil {
+			req.MaxFsyncLatency = latency.Duration
+		}
+		result = append(result, req)
+	}
+	return result, nil
+}
+
 // RequirementsFromManifests generates check requirements as a difference
 // between two manifests - old and new.
 func RequirementsFromManifests(old, new schema.Manifest, profiles map[string]string, docker storage.DockerConfig) (map[string]Requirements, error) {
@@ -509,6 +509,14 @@ const (
 	// UserContext is a context field that contains authenticated user name
 	UserContext = "user.context"
 
+	// SecondFactorContext is a context field that contains the second factor
+	// token provided with the request
+	SecondFactorContext = "second_factor.context"
+
+	// SecondFactorHeader is the HTTP header used to pass the second factor
+	// token for actions that require it
+	SecondFactorHeader = "X-Gravity-Second-Factor"
+
 	// PrivilegedKubeconfig is a path to privileged kube config
 	// that is stored on K8s master node
 	PrivilegedKubeconfig = "/etc/kubernetes/scheduler.kubeconfig"
@@ -592,6 +600,16 @@ const (
 	// AuthGatewayConfigMap is the name of config map with auth gateway configuration.
 	AuthGatewayConfigMap = "auth-gateway"
 
+	// MaintenanceWindowConfigMap is the name of config map with cluster maintenance window.
+	MaintenanceWindowConfigMap = "maintenance-window"
+
+	// BackgroundChecksConfigMap is the name of config map with the results
+	// of the most recent background checks.
+	BackgroundChecksConfigMap = "background-checks"
+
+	// NodePoolConfigMapPrefix is the name prefix of config maps with node pools.
+	NodePoolConfigMapPrefix = "nodepool-"
+
 	// LVMSystemDir specifies the default location where lvm2 keeps state and configuration data
 	LVMSystemDir = "/etc/lvm"
 	// LVMSystemDirEnvvar defines the name of the environment variable that overrides the
@@ -719,6 +737,16 @@ const (
 	//
 	// Used in audit events.
 	ServiceStatusChecker = "@statuschecker"
+	// ServiceNodePoolController is the name of the service that converges
+	// the cluster on the configured node pools.
+	//
+	// Used in audit events.
+	ServiceNodePoolController = "@nodepool"
+	// ServiceBackgroundChecker is the name of the service that periodically
+	// executes checks on cluster nodes.
+	//
+	// Used in audit events.
+	ServiceBackgroundChecker = "@backgroundchecker"
 	// ServiceSystem is the identifier used as a "user" field for events
 	// that are triggered not by a human user but by a system process.
 	//
@@ -729,6 +757,8 @@ const (
 var (
 	// EncodingJSON is for the JSON encoding format
 	EncodingJSON Format = "json"
+	// EncodingJSONL is for the JSON lines encoding format
+	EncodingJSONL Format = "jsonl"
 	// EncodingPEM is for the PEM encoding format
 	EncodingPEM Format = "pem"
 	// EncodingText is for the plaint-text encoding format
@@ -47,6 +47,78 @@ const (
 	// generated for agent
 	AgentTokenBytes = 32
 
+	// APIKeyUsageUpdateInterval specifies how often the last used time
+	// of an API key is updated when the key is used from the same address
+	APIKeyUsageUpdateInterval = time.Minute
+
+	// APIKeyRevocationInterval specifies how often API keys are checked
+	// for revocation due to inactivity
+	APIKeyRevocationInterval = time.Hour
+
+	// AccessRequestDuration is the default duration of temporary
+	// access granted by an access request
+	AccessRequestDuration = time.Hour
+
+	// MaxAccessRequestDuration is the maximum duration of temporary
+	// access granted by an access request
+	MaxAccessRequestDuration = 24 * time.Hour
+
+	// AccessRequestExpirationInterval specifies how often approved
+	// access requests are checked for expiration
+	AccessRequestExpirationInterval = time.Minute
+
+	// AuditForwardInterval specifies how often new audit log records
+	// are sent to the log forwarders
+	AuditForwardInterval = 10 * time.Second
+
+	// AuditSyslogTag is the syslog application name of the audit log
+	// records sent to the log forwarders
+	AuditSyslogTag = "gravity-audit"
+
+	// AuditForwardBatchSize is the maximum number of audit log records
+	// read at once to send to the log forwarders
+	AuditForwardBatchSize = 1000
+
+	// AuditRecordsRetention specifies how long the hash-chained
+	// audit log records are kept
+	AuditRecordsRetention = 90 * 24 * time.Hour
+
+	// AuditRecordsPruneInterval specifies how often the audit log records
+	// older than the retention period are removed
+	AuditRecordsPruneInterval = time.Hour
+
+	// ClusterEventsPollInterval specifies how often operations, their progress
+	// and plans are checked for changes to stream to the cluster event subscribers
+	ClusterEventsPollInterval = 2 * time.Second
+
+	// ClusterEventsStatusInterval specifies how often node status and alerts
+	// are checked for changes to stream to the cluster event subscribers
+	ClusterEventsStatusInterval = 30 * time.Second
+
+	// ClusterEventsKeepAlive specifies how often a keep-alive message is sent
+	// to the cluster event subscribers
+	ClusterEventsKeepAlive = 15 * time.Second
+
+	// ClusterEventsBuffer is the number of cluster events buffered
+	// for a subscriber
+	ClusterEventsBuffer = 100
+
+	// RateLimitConcurrentRetryAfter is the retry hint returned to clients
+	// that exceed the number of concurrent requests
+	RateLimitConcurrentRetryAfter = 5 * time.Second
+
+	// RateLimitIdleTimeout specifies how long the request budget of an idle
+	// user, API key or cluster is kept in memory
+	RateLimitIdleTimeout = 10 * time.Minute
+
+	// RateLimitPruneInterval specifies how often the budgets of idle
+	// users, API keys and clusters are removed
+	RateLimitPruneInterval = time.Minute
+
+	// OIDCDiscoveryTimeout specifies the maximum amount of time to wait
+	// for the OIDC provider to return its configuration
+	OIDCDiscoveryTimeout = 10 * time.Second
+
 	// SignupTokenBytes is length in bytes for crypto random generated signup tokens
 	SignupTokenBytes = 32
 
@@ -85,6 +157,14 @@ const (
 	// EtcdRetryInterval is the retry interval for some etcd commands
 	EtcdRetryInterval = 3 * time.Second
 
+	// EtcdMemberHealthTimeout specifies the maximum amount of time to wait for
+	// a replaced etcd member to become healthy during a rolling etcd upgrade
+	EtcdMemberHealthTimeout = 5 * time.Minute
+
+	// EtcdRollingUpgradeMinMasters is the minimum number of master nodes
+	// required to upgrade etcd one member at a time without losing quorum
+	EtcdRollingUpgradeMinMasters = 3
+
 	// InstallApplicationTimeout is the max allowed time for k8s application to install
 	InstallApplicationTimeout = 90 * time.Minute // 1.5 hours
 
@@ -418,6 +498,9 @@ const (
 	// EtcdDir is the name of the etcd directory
 	EtcdDir = "etcd"
 
+	// DockerDir is the name of the docker directory
+	DockerDir = "docker"
+
 	// ShareDir is the name of the share directory
 	ShareDir = "share"
 
@@ -598,6 +681,14 @@ const (
 	// SiteStatusCheckInterval is how often local gravity site will invoke app status hook
 	SiteStatusCheckInterval = 1 * time.Minute
 
+	// BackgroundChecksInterval is how often the cluster controller executes
+	// background checks on cluster nodes
+	BackgroundChecksInterval = 1 * time.Hour
+
+	// BackgroundChecksTimeout is the maximum amount of time a single run
+	// of background checks can take
+	BackgroundChecksTimeout = 15 * time.Minute
+
 	// OfflineCheckInterval is how often OpsCenter checks whether its sites are online/offline
 	OfflineCheckInterval = 10 * time.Second
 
@@ -687,6 +778,13 @@ const (
 	// RunLevelLabel is the Kubernetes node taint label representing a run-level
 	RunLevelLabel = "gravitational.io/runlevel"
 
+	// MaintenanceLabel is the Kubernetes node label that marks the node in maintenance mode
+	MaintenanceLabel = "gravitational.io/maintenance"
+
+	// NodePoolLabel is the label with the name of the node pool on Kubernetes
+	// nodes provisioned from a node pool and on config maps with node pools
+	NodePoolLabel = "gravitational.io/nodepool"
+
 	// RunLevelSystem is the Kubernetes run-level for system applications
 	RunLevelSystem = "system"
 
@@ -814,6 +912,24 @@ const (
 	BandwidthTestDuration = 20 * time.Second
 	// BandwidthTestMaxServers is the maximum amount of servers participating in the bandwidth test
 	BandwidthTestMaxServers = 3
+	// NetworkProbePort is the UDP port for the path MTU, latency and packet loss tests agents do
+	NetworkProbePort = 4243
+	// NetworkProbeDuration is the duration of a path MTU, latency or packet loss test agents do
+	NetworkProbeDuration = 10 * time.Second
+	// NetworkProbeCount is the number of probes sent to each server during latency and packet loss tests
+	NetworkProbeCount = 100
+	// NetworkProbeTimeout is how long to wait for a single probe to be acknowledged
+	NetworkProbeTimeout = 250 * time.Millisecond
+	// NetworkProbeMaxMTU is the upper bound for the path MTU discovery
+	NetworkProbeMaxMTU = 9000
+	// VXLANTestInterface is the name of the temporary VXLAN interface
+	// agents create to test the overlay network data path
+	VXLANTestInterface = "gravity.vxlan"
+	// VXLANTestVNI is the VXLAN network identifier of the overlay network test
+	VXLANTestVNI = 4242
+	// VXLANTestSubnet is the subnet of the overlay network test.
+	// It is allocated from the range reserved for network device benchmarking
+	VXLANTestSubnet = "198.18.0.0/16"
 	// BandwidthMaxSpeedBytes is the theoretical upper bound on the amount of types transferred per
 	// second during bandwidth test, which is used in HDR histogram
 	BandwidthMaxSpeedBytes = 100000000000 // 100GB
@@ -909,9 +1025,25 @@ const (
 	// RPCAgentBackoffThreshold defines max communication delay before retrying connection to remote agent node
 	RPCAgentBackoffThreshold = 1 * time.Minute
 
+	// RPCAgentExecTimeout specifies the maximum amount of time a command executed
+	// on a remote node with an RPC agent is allowed to run
+	RPCAgentExecTimeout = 10 * time.Minute
+
 	// RPCAgentSecretsPackage specifies the name of the RPC credentials package
 	RPCAgentSecretsPackage = "rpcagent-secrets"
 
+	// RPCCredentialsReloadInterval specifies how often RPC agents check their
+	// credentials for changes
+	RPCCredentialsReloadInterval = 10 * time.Second
+
+	// RPCTransferChunkSize specifies the size of a file chunk sent with a single
+	// message during file transfer between RPC agents
+	RPCTransferChunkSize = 1 * 1024 * 1024
+
+	// RPCTransferTimeout specifies the maximum amount of time to retry an interrupted
+	// file transfer between RPC agents
+	RPCTransferTimeout = 5 * time.Minute
+
 	// ShutdownTimeout specifies the maximum amount of time to wait for completion
 	// when closing
 	ShutdownTimeout = 1 * time.Minute
@@ -1015,6 +1147,20 @@ const (
 	ModulesPath = "/etc/modules-load.d/gravity.conf"
 	// SysctlPath is the path to gravity-specific kernel parameters configuration
 	SysctlPath = "/etc/sysctl.d/50-gravity.conf"
+	// HostsPath is the path to the static table of hostname lookups
+	HostsPath = "/etc/hosts"
+	// FstabPath is the path to the static filesystem table
+	FstabPath = "/etc/fstab"
+	// SwapsPath is the path to the list of active swap areas
+	SwapsPath = "/proc/swaps"
+	// SELinuxEnforcePath is the path to the SELinux enforcement status.
+	// It only exists if SELinux is enabled
+	SELinuxEnforcePath = "/sys/fs/selinux/enforce"
+	// TransparentHugepagePath is the path to the transparent hugepages setting
+	TransparentHugepagePath = "/sys/kernel/mm/transparent_hugepage/enabled"
+	// TransparentHugepageTmpfilesPath is the path to gravity-specific tmpfiles
+	// configuration that persists transparent hugepages setting across reboots
+	TransparentHugepageTmpfilesPath = "/etc/tmpfiles.d/gravity-hugepages.conf"
 
 	// RemoteClusterDialAddr is the "from" address used when dialing remote cluster
 	RemoteClusterDialAddr = "127.0.0.1:3024"
@@ -1145,6 +1291,16 @@ var (
 	// DiscoveryResyncInterval specifies the frequency to force publish cluster discovery details
 	DiscoveryResyncInterval = 10 * time.Minute
 
+	// InstanceGroupPollInterval specifies the frequency to poll cloud
+	// provider instance groups for changes
+	InstanceGroupPollInterval = 30 * time.Second
+
+	// NodePoolReconcileInterval specifies the frequency to reconcile cluster node pools
+	NodePoolReconcileInterval = time.Minute
+	// NodePoolJoinTimeout specifies how long a node pool host is given to join
+	// the cluster before another host is tried
+	NodePoolJoinTimeout = 20 * time.Minute
+
 	// CACertificateExpiry is the validity period of self-signed CA generated
 	// for clusters during installation
 	CACertificateExpiry = 20 * 365 * 24 * time.Hour // 20 years
@@ -96,6 +96,12 @@ func Unmount(out io.Writer, logger log.FieldLogger) (err error) {
 	return nil
 }
 
+// QueryPhysicalVolume returns the disk backing the docker volume group.
+// Returns an empty string if no physical volume has been configured
+func QueryPhysicalVolume(logger log.FieldLogger) (disk string, err error) {
+	return queryPhysicalVolume(logger)
+}
+
 func queryPhysicalVolume(logger log.FieldLogger) (disk string, err error) {
 	logger.Debug("Query physical volume information.")
 
@@ -58,6 +58,9 @@ type planBuilder struct {
 	ServiceUser storage.OSUser
 	// DNSConfig specifies the custom cluster DNS configuration
 	DNSConfig storage.DNSConfig
+	// NodeLabels is a set of labels to restore on the joining node
+	// when it replaces another node
+	NodeLabels map[string]string
 }
 
 // AddInitPhase appends initialization phase to the plan.
@@ -253,6 +256,21 @@ func (b *planBuilder) AddWaitPhase(plan *storage.OperationPlan) {
 	})
 }
 
+// AddLabelsPhase appends phase that restores labels of the replaced node
+// on the joined node
+func (b *planBuilder) AddLabelsPhase(plan *storage.OperationPlan) {
+	plan.Phases = append(plan.Phases, storage.OperationPhase{
+		ID:          LabelsPhase,
+		Description: "Restore labels of the replaced node",
+		Data: &storage.OperationPhaseData{
+			Server:     &b.JoiningNode,
+			ExecServer: &b.JoiningNode,
+			Labels:     b.NodeLabels,
+		},
+		Requires: []string{installphases.WaitPhase},
+	})
+}
+
 // AddStopAgentPhase appends phase that stops RPC agent on a master node
 func (b *planBuilder) AddStopAgentPhase(plan *storage.OperationPlan) {
 	plan.Phases = append(plan.Phases, storage.OperationPhase{
@@ -357,6 +375,7 @@ func (p *Peer) getPlanBuilder(ctx operationContext) (*planBuilder, error) {
 		RegularAgent:    *regularAgent,
 		ServiceUser:     ctx.Cluster.ServiceUser,
 		DNSConfig:       ctx.Cluster.DNSConfig,
+		NodeLabels:      p.nodeLabels,
 	}, nil
 }
 
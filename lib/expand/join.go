@@ -115,6 +115,9 @@ type Peer struct {
 	execDoneC chan install.ExecResult
 	// wg is a wait group used to ensure completion of internal processes
 	wg sync.WaitGroup
+	// nodeLabels is a set of labels to restore on the joined node
+	// when it replaces another node
+	nodeLabels map[string]string
 }
 
 // Run runs the peer operation
@@ -292,6 +295,12 @@ type PeerConfig struct {
 	// SkipWizard specifies to the peer agents that the peer is not a wizard
 	// and attempts to contact the wizard should be skipped
 	SkipWizard bool
+	// ReplaceNode is the optional hostname or advertise IP of the cluster node
+	// this peer replaces. The node is removed from the cluster before the peer
+	// joins and its role and labels are assumed by the peer
+	ReplaceNode string
+	// FixHost enables fixing host environment problems found by pre-flight checks
+	FixHost bool
 }
 
 // CheckAndSetDefaults checks the parameters and autodetects some defaults
@@ -610,6 +619,12 @@ func (p *Peer) dialCluster(addr, operationID string) (*operationContext, error)
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
+	if p.ReplaceNode != "" {
+		err = p.replaceNode(ctx)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+	}
 	err = p.checkAndSetServerProfile(ctx.Cluster.App)
 	if err != nil {
 		return nil, utils.Abort(err)
@@ -725,11 +740,13 @@ func (p *Peer) runLocalChecks(cluster ops.Site, installOperation ops.SiteOperati
 		Role:     p.Role,
 		Docker:   cluster.ClusterState.Docker,
 		Options: &validationpb.ValidateOptions{
-			VxlanPort: int32(installOperation.GetVars().OnPrem.VxlanPort),
-			DnsAddrs:  cluster.DNSConfig.Addrs,
-			DnsPort:   int32(cluster.DNSConfig.Port),
+			VxlanPort:     int32(installOperation.GetVars().OnPrem.VxlanPort),
+			DnsAddrs:      cluster.DNSConfig.Addrs,
+			DnsPort:       int32(cluster.DNSConfig.Port),
+			AdvertiseAddr: p.AdvertiseAddr,
 		},
 		AutoFix: true,
+		FixHost: p.FixHost,
 	})
 }
 
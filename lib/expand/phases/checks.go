@@ -24,6 +24,7 @@ import (
 	"github.com/gravitational/gravity/lib/fsm"
 	"github.com/gravitational/gravity/lib/ops"
 	"github.com/gravitational/gravity/lib/rpc"
+	"github.com/gravitational/gravity/lib/storage"
 
 	"github.com/gravitational/trace"
 	"github.com/sirupsen/logrus"
@@ -73,13 +74,19 @@ func (p *checksExecutor) Execute(ctx context.Context) error {
 	if err != nil {
 		return trace.Wrap(err)
 	}
+	var benchmarks []storage.DiskBenchmark
 	checker, err := checks.New(checks.Config{
 		Remote:       checks.NewRemote(p.Runner),
 		Servers:      []checks.Server{*master, *node},
 		Manifest:     cluster.App.Manifest,
 		Requirements: reqs,
 		Features: checks.Features{
-			TestEtcdDisk: true,
+			TestEtcdDisk:     true,
+			TestNetworkPaths: true,
+			TestDiskProfiles: true,
+		},
+		OnDiskBenchmark: func(benchmark storage.DiskBenchmark) {
+			benchmarks = append(benchmarks, benchmark)
 		},
 	})
 	if err != nil {
@@ -90,6 +97,12 @@ func (p *checksExecutor) Execute(ctx context.Context) error {
 	// the OS check, time drift check, etc).
 	failed := checker.CheckNode(ctx, *node)
 	failed = append(failed, checker.CheckNodes(ctx, []checks.Server{*master, *node})...)
+	if len(benchmarks) != 0 {
+		err := p.Operator.SaveDiskBenchmarks(opKey(p.Plan), benchmarks)
+		if err != nil {
+			p.WithError(err).Warn("Failed to save disk benchmarks.")
+		}
+	}
 	if len(failed) != 0 {
 		return trace.BadParameter("The following checks failed:\n%v",
 			checks.FormatFailedChecks(failed))
@@ -139,53 +139,50 @@ func (p *etcdExecutor) Rollback(ctx context.Context) error {
 }
 
 func (p *etcdExecutor) checkBackup(ctx context.Context, agent rpcclient.Client, backupPath string) error {
-	var out bytes.Buffer
-	err := agent.Command(ctx, p.FieldLogger, &out, utils.PlanetEnterCommand(
+	out, err := execOnNode(ctx, agent, utils.PlanetEnterCommand(
 		defaults.StatBin, backupPath)...)
 	if err != nil {
-		return trace.Wrap(err, "failed to check backup file %v: %s", backupPath, out.String())
+		return trace.Wrap(err, "failed to check backup file %v: %s", backupPath, out)
 	}
 	return nil
 }
 
 func (p *etcdExecutor) stopEtcd(ctx context.Context, agent rpcclient.Client) error {
-	var out bytes.Buffer
-	err := agent.Command(ctx, p.FieldLogger, &out, utils.PlanetEnterCommand(
+	out, err := execOnNode(ctx, agent, utils.PlanetEnterCommand(
 		defaults.SystemctlBin, "stop", "etcd")...)
 	if err != nil {
-		return trace.Wrap(err, "failed to stop etcd: %s", out.String())
+		return trace.Wrap(err, "failed to stop etcd: %s", out)
 	}
 	return nil
 }
 
 func (p *etcdExecutor) wipeEtcd(ctx context.Context, agent rpcclient.Client) error {
-	var out bytes.Buffer
-	err := agent.Command(ctx, p.FieldLogger, &out, utils.PlanetEnterCommand(
+	out, err := execOnNode(ctx, agent, utils.PlanetEnterCommand(
 		defaults.PlanetBin, "etcd", "wipe", "--confirm")...)
 	if err != nil {
-		return trace.Wrap(err, "failed to wipe out etcd data: %s", out.String())
+		return trace.Wrap(err, "failed to wipe out etcd data: %s", out)
 	}
 	return nil
 }
 
 func (p *etcdExecutor) startEtcd(ctx context.Context, agent rpcclient.Client) error {
-	var out bytes.Buffer
-	err := agent.Command(ctx, p.FieldLogger, &out, utils.PlanetEnterCommand(
+	out, err := execOnNode(ctx, agent, utils.PlanetEnterCommand(
 		defaults.SystemctlBin, "start", "etcd")...)
 	if err != nil {
-		return trace.Wrap(err, "failed to start etcd: %s", out.String())
+		return trace.Wrap(err, "failed to start etcd: %s", out)
 	}
 	return nil
 }
 
 func (p *etcdExecutor) restoreEtcd(ctx context.Context, agent rpcclient.Client, backupPath string) error {
-	var out bytes.Buffer
-	err := utils.Retry(defaults.RetryInterval, defaults.RetryLessAttempts, func() error {
-		return agent.Command(ctx, p.FieldLogger, &out, utils.PlanetEnterCommand(
+	var out string
+	err := utils.Retry(defaults.RetryInterval, defaults.RetryLessAttempts, func() (err error) {
+		out, err = execOnNode(ctx, agent, utils.PlanetEnterCommand(
 			defaults.PlanetBin, "etcd", "restore", backupPath)...)
+		return trace.Wrap(err)
 	})
 	if err != nil {
-		return trace.Wrap(err, "failed to restore etcd data: %s", out.String())
+		return trace.Wrap(err, "failed to restore etcd data: %s", out)
 	}
 	return nil
 }
@@ -246,11 +243,10 @@ func (p *etcdBackupExecutor) Execute(ctx context.Context) error {
 }
 
 func (p *etcdBackupExecutor) backupEtcd(ctx context.Context, agent rpcclient.Client, backupPath string) error {
-	var out bytes.Buffer
-	err := agent.Command(ctx, p.FieldLogger, &out, utils.PlanetEnterCommand(
+	out, err := execOnNode(ctx, agent, utils.PlanetEnterCommand(
 		defaults.PlanetBin, "etcd", "backup", backupPath)...)
 	if err != nil {
-		return trace.Wrap(err, "failed to backup etcd data: %s", out.String())
+		return trace.Wrap(err, "failed to backup etcd data: %s", out)
 	}
 	return nil
 }
@@ -284,3 +280,20 @@ func opKey(plan storage.OperationPlan) ops.SiteOperationKey {
 		OperationID: plan.OperationID,
 	}
 }
+
+// execOnNode executes the command specified with args on the node of the given agent.
+// Returns the combined output of the command and an error if the command
+// has not completed successfully within defaults.RPCAgentExecTimeout
+func execOnNode(ctx context.Context, agent rpcclient.Client, args ...string) (output string, err error) {
+	var out bytes.Buffer
+	result, err := agent.Exec(ctx, rpcclient.ExecRequest{
+		Args:    args,
+		Stdout:  &out,
+		Stderr:  &out,
+		Timeout: defaults.RPCAgentExecTimeout,
+	})
+	if err != nil {
+		return out.String(), trace.Wrap(err)
+	}
+	return out.String(), trace.Wrap(result.Check())
+}
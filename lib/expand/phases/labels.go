@@ -0,0 +1,90 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package phases
+
+import (
+	"context"
+
+	"github.com/gravitational/gravity/lib/constants"
+	"github.com/gravitational/gravity/lib/fsm"
+	"github.com/gravitational/gravity/lib/httplib"
+	kubeutils "github.com/gravitational/gravity/lib/kubernetes"
+	"github.com/gravitational/gravity/lib/ops"
+
+	"github.com/gravitational/trace"
+	"github.com/sirupsen/logrus"
+	"k8s.io/client-go/kubernetes"
+)
+
+// NewLabels returns executor that restores labels of the replaced node
+// on the joined Kubernetes node
+func NewLabels(p fsm.ExecutorParams, operator ops.Operator) (*labelsExecutor, error) {
+	client, _, err := httplib.GetUnprivilegedKubeClient(p.Plan.DNSConfig.Addr())
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	logger := &fsm.Logger{
+		FieldLogger: logrus.WithFields(logrus.Fields{
+			constants.FieldPhase: p.Phase.ID,
+		}),
+		Key:      opKey(p.Plan),
+		Operator: operator,
+		Server:   p.Phase.Data.Server,
+	}
+	return &labelsExecutor{
+		FieldLogger:    logger,
+		Client:         client,
+		ExecutorParams: p,
+	}, nil
+}
+
+type labelsExecutor struct {
+	// FieldLogger is used for logging
+	logrus.FieldLogger
+	// Client is Kubernetes client
+	Client *kubernetes.Clientset
+	// ExecutorParams is common executor params
+	fsm.ExecutorParams
+}
+
+// Execute applies the labels to the joined node
+func (p *labelsExecutor) Execute(ctx context.Context) error {
+	p.Progress.NextStep("Restoring node labels")
+	p.WithField("labels", p.Phase.Data.Labels).Info("Restoring node labels.")
+	err := kubeutils.UpdateLabels(ctx, p.Client.CoreV1().Nodes(),
+		p.Phase.Data.Server.KubeNodeID(), p.Phase.Data.Labels)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	p.Info("Node labels restored.")
+	return nil
+}
+
+// Rollback is no-op for this phase
+func (*labelsExecutor) Rollback(ctx context.Context) error {
+	return nil
+}
+
+// PreCheck is no-op for this phase
+func (*labelsExecutor) PreCheck(ctx context.Context) error {
+	return nil
+}
+
+// PostCheck is no-op for this phase
+func (*labelsExecutor) PostCheck(ctx context.Context) error {
+	return nil
+}
@@ -102,6 +102,11 @@ func (p *Peer) getOperationPlan(ctx operationContext) (*storage.OperationPlan, e
 	// wait for the planet to start up and the new Kubernetes node to register
 	builder.AddWaitPhase(plan)
 
+	// restore labels of the node being replaced
+	if len(builder.NodeLabels) != 0 {
+		builder.AddLabelsPhase(plan)
+	}
+
 	// RPC agent started in the beginning is no longer needed so shut it down
 	builder.AddStopAgentPhase(plan)
 
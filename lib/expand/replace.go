@@ -0,0 +1,184 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package expand
+
+import (
+	"time"
+
+	"github.com/gravitational/gravity/lib/constants"
+	"github.com/gravitational/gravity/lib/ops"
+	"github.com/gravitational/gravity/lib/storage"
+	"github.com/gravitational/gravity/lib/utils"
+
+	"github.com/cenkalti/backoff"
+	"github.com/gravitational/trace"
+)
+
+// replaceNode removes the node being replaced from the cluster and configures
+// this peer to join in its place: the peer assumes the role of the removed
+// node and the labels it had are restored once the peer has joined.
+//
+// The node is removed with a shrink operation: the cluster only lets the agent
+// remove an offline node and verifies etcd quorum both before and after the removal.
+// The method is idempotent: if the node has already been removed, its configuration
+// is recovered from the completed shrink operation
+func (p *Peer) replaceNode(ctx *operationContext) error {
+	server, err := findReplacedServer(ctx.Cluster.ClusterState.Servers, p.ReplaceNode)
+	if err != nil && !trace.IsNotFound(err) {
+		return trace.Wrap(err)
+	}
+	_, err = ops.GetExpandOperation(p.JoinBackend)
+	if err != nil && !trace.IsNotFound(err) {
+		return trace.Wrap(err)
+	}
+	// Once the expand operation has been created, the node has been removed
+	// and the cluster state might already include this peer
+	joining := err == nil
+	if server != nil && !joining {
+		if server.AdvertiseIP == p.AdvertiseAddr {
+			return utils.Abort(trace.BadParameter(
+				"node %v cannot replace itself", p.ReplaceNode))
+		}
+		if err := p.removeReplacedNode(ctx, *server); err != nil {
+			return trace.Wrap(err)
+		}
+	}
+	operation, err := findReplaceShrinkOperation(ctx.Operator, ctx.Cluster.Key(), p.ReplaceNode)
+	if err != nil {
+		if trace.IsNotFound(err) {
+			return utils.Abort(trace.NotFound(
+				"node %v is not a member of the cluster", p.ReplaceNode))
+		}
+		return trace.Wrap(err)
+	}
+	if !operation.IsCompleted() {
+		if err := p.waitForShrinkOperation(ctx.Operator, operation.Key()); err != nil {
+			return trace.Wrap(err)
+		}
+	}
+	removed := operation.Shrink.Servers[0]
+	p.Role = removed.Role
+	p.nodeLabels = operation.Shrink.NodeLabels
+	p.WithField("role", p.Role).Infof("Will replace %v.", removed)
+	return nil
+}
+
+// removeReplacedNode removes the specified server from the cluster and
+// refreshes the cluster state in ctx
+func (p *Peer) removeReplacedNode(ctx *operationContext, server storage.Server) error {
+	operation, err := findReplaceShrinkOperation(ctx.Operator, ctx.Cluster.Key(), server.Hostname)
+	if err != nil && !trace.IsNotFound(err) {
+		return trace.Wrap(err)
+	}
+	var key *ops.SiteOperationKey
+	if operation != nil && !operation.IsFinished() {
+		p.Infof("Found existing shrink operation %v.", operation)
+		opKey := operation.Key()
+		key = &opKey
+	} else {
+		p.printStep("Removing %v from the cluster", server.Hostname)
+		key, err = ctx.Operator.CreateSiteShrinkOperation(p.ctx,
+			ops.CreateSiteShrinkOperationRequest{
+				AccountID:  ctx.Cluster.AccountID,
+				SiteDomain: ctx.Cluster.Domain,
+				Servers:    []string{server.Hostname},
+				Force:      true,
+				Replace:    true,
+			})
+		if err != nil {
+			if trace.IsCompareFailed(err) || trace.IsAccessDenied(err) {
+				return utils.Abort(err)
+			}
+			return trace.Wrap(err)
+		}
+	}
+	if err := p.waitForShrinkOperation(ctx.Operator, *key); err != nil {
+		return trace.Wrap(err)
+	}
+	cluster, err := ctx.Operator.GetLocalSite()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	ctx.Cluster = *cluster
+	return nil
+}
+
+// waitForShrinkOperation blocks until the specified shrink operation completes
+func (p *Peer) waitForShrinkOperation(operator ops.Operator, key ops.SiteOperationKey) error {
+	ticker := backoff.NewTicker(backoff.NewConstantBackOff(5 * time.Second))
+	defer ticker.Stop()
+	log := p.WithField(constants.FieldOperationID, key.OperationID)
+	for {
+		select {
+		case <-ticker.C:
+			operation, err := operator.GetSiteOperation(key)
+			if err != nil {
+				return trace.Wrap(err)
+			}
+			if operation.IsFailed() {
+				return utils.Abort(trace.BadParameter(
+					"failed to remove the node, see operation %v for details", operation))
+			}
+			if operation.IsCompleted() {
+				log.Info("Node has been removed.")
+				return nil
+			}
+			log.WithField("state", operation.State).Info("Waiting for the node to be removed.")
+		case <-p.ctx.Done():
+			return trace.Wrap(p.ctx.Err())
+		}
+	}
+}
+
+// findReplacedServer returns the server with the specified hostname
+// or advertise IP address
+func findReplacedServer(servers []storage.Server, node string) (*storage.Server, error) {
+	for _, server := range servers {
+		if server.Hostname == node || server.AdvertiseIP == node {
+			server := server
+			return &server, nil
+		}
+	}
+	return nil, trace.NotFound("no server matching %v found", node)
+}
+
+// findReplaceShrinkOperation returns the most recent shrink operation
+// that removed the node with the specified hostname or advertise IP address
+func findReplaceShrinkOperation(operator ops.Operator, key ops.SiteKey, node string) (*ops.SiteOperation, error) {
+	operations, err := operator.GetSiteOperations(key)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var result *ops.SiteOperation
+	for _, op := range operations {
+		operation := ops.SiteOperation(op)
+		if operation.Type != ops.OperationShrink || operation.Shrink == nil ||
+			len(operation.Shrink.Servers) == 0 || operation.IsFailed() {
+			continue
+		}
+		if _, err := findReplacedServer(operation.Shrink.Servers, node); err != nil {
+			continue
+		}
+		if result == nil || operation.Created.After(result.Created) {
+			result = &operation
+		}
+	}
+	if result == nil {
+		return nil, trace.NotFound("no shrink operation found for %v", node)
+	}
+	return result, nil
+}
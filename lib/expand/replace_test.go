@@ -0,0 +1,182 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package expand
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/gravitational/gravity/lib/install/dispatcher/buffered"
+	"github.com/gravitational/gravity/lib/ops"
+	"github.com/gravitational/gravity/lib/storage"
+	"github.com/gravitational/gravity/lib/storage/keyval"
+	"github.com/gravitational/gravity/lib/utils"
+
+	"github.com/gravitational/trace"
+	"github.com/sirupsen/logrus"
+	check "gopkg.in/check.v1"
+)
+
+type ReplaceSuite struct{}
+
+var _ = check.Suite(&ReplaceSuite{})
+
+func (s *ReplaceSuite) TestReplacesNode(c *check.C) {
+	peer, ctx, operator := s.setup(c)
+
+	err := peer.replaceNode(ctx)
+	c.Assert(err, check.IsNil)
+	c.Assert(operator.requests, check.DeepEquals, []ops.CreateSiteShrinkOperationRequest{{
+		AccountID:  "account",
+		SiteDomain: "example.com",
+		Servers:    []string{"node-2"},
+		Force:      true,
+		Replace:    true,
+	}})
+	c.Assert(peer.Role, check.Equals, "master")
+	c.Assert(peer.nodeLabels, check.DeepEquals, map[string]string{"rack": "r1"})
+	c.Assert(ctx.Cluster.ClusterState.Servers, check.HasLen, 1)
+
+	// resuming the replacement reuses the completed shrink operation
+	peer.Role = ""
+	err = peer.replaceNode(ctx)
+	c.Assert(err, check.IsNil)
+	c.Assert(operator.requests, check.HasLen, 1)
+	c.Assert(peer.Role, check.Equals, "master")
+}
+
+func (s *ReplaceSuite) TestAbortsWhenClusterRefusesRemoval(c *check.C) {
+	peer, ctx, operator := s.setup(c)
+	operator.createErr = trace.CompareFailed("etcd cluster would lose quorum")
+
+	err := peer.replaceNode(ctx)
+	c.Assert(utils.IsAbortError(err), check.Equals, true, check.Commentf("%v", err))
+	c.Assert(peer.Role, check.Equals, "")
+}
+
+func (s *ReplaceSuite) TestRefusesToReplaceItself(c *check.C) {
+	peer, ctx, operator := s.setup(c)
+	peer.AdvertiseAddr = "10.0.0.2"
+
+	err := peer.replaceNode(ctx)
+	c.Assert(utils.IsAbortError(err), check.Equals, true, check.Commentf("%v", err))
+	c.Assert(operator.requests, check.HasLen, 0)
+}
+
+func (s *ReplaceSuite) TestFindsReplacedServer(c *check.C) {
+	servers := []storage.Server{
+		{Hostname: "node-1", AdvertiseIP: "10.0.0.1"},
+		{Hostname: "node-2", AdvertiseIP: "10.0.0.2"},
+	}
+	server, err := findReplacedServer(servers, "node-2")
+	c.Assert(err, check.IsNil)
+	c.Assert(server.AdvertiseIP, check.Equals, "10.0.0.2")
+
+	server, err = findReplacedServer(servers, "10.0.0.1")
+	c.Assert(err, check.IsNil)
+	c.Assert(server.Hostname, check.Equals, "node-1")
+
+	_, err = findReplacedServer(servers, "node-3")
+	c.Assert(err, check.NotNil)
+}
+
+func (s *ReplaceSuite) setup(c *check.C) (*Peer, *operationContext, *replaceOperator) {
+	backend, err := keyval.NewBolt(keyval.BoltConfig{Path: filepath.Join(c.MkDir(), "bolt.db")})
+	c.Assert(err, check.IsNil)
+	cluster := ops.Site{
+		AccountID: "account",
+		Domain:    "example.com",
+		ClusterState: storage.ClusterState{
+			Servers: []storage.Server{
+				{Hostname: "node-1", AdvertiseIP: "10.0.0.1", Role: "master"},
+				{Hostname: "node-2", AdvertiseIP: "10.0.0.2", Role: "master"},
+			},
+		},
+	}
+	operator := &replaceOperator{cluster: cluster}
+	peer := &Peer{
+		PeerConfig: PeerConfig{
+			ReplaceNode:   "10.0.0.2",
+			AdvertiseAddr: "10.0.0.3",
+			JoinBackend:   backend,
+			FieldLogger:   logrus.WithField("test", "replace"),
+		},
+		ctx:        context.TODO(),
+		dispatcher: buffered.New(),
+	}
+	return peer, &operationContext{Operator: operator, Cluster: cluster}, operator
+}
+
+// replaceOperator is the cluster operator that removes nodes
+// with shrink operations that complete immediately
+type replaceOperator struct {
+	ops.Operator
+	cluster    ops.Site
+	operations []storage.SiteOperation
+	requests   []ops.CreateSiteShrinkOperationRequest
+	createErr  error
+}
+
+func (r *replaceOperator) CreateSiteShrinkOperation(ctx context.Context, req ops.CreateSiteShrinkOperationRequest) (*ops.SiteOperationKey, error) {
+	if r.createErr != nil {
+		return nil, r.createErr
+	}
+	r.requests = append(r.requests, req)
+	var servers, removed []storage.Server
+	for _, server := range r.cluster.ClusterState.Servers {
+		if server.Hostname == req.Servers[0] {
+			removed = append(removed, server)
+		} else {
+			servers = append(servers, server)
+		}
+	}
+	r.cluster.ClusterState.Servers = servers
+	operation := storage.SiteOperation{
+		ID:         "shrink",
+		AccountID:  req.AccountID,
+		SiteDomain: req.SiteDomain,
+		Type:       ops.OperationShrink,
+		State:      ops.OperationStateCompleted,
+		Shrink: &storage.ShrinkOperationState{
+			Servers:    removed,
+			NodeLabels: map[string]string{"rack": "r1"},
+			Replace:    req.Replace,
+		},
+	}
+	r.operations = append(r.operations, operation)
+	key := (*ops.SiteOperation)(&operation).Key()
+	return &key, nil
+}
+
+func (r *replaceOperator) GetSiteOperations(ops.SiteKey) (ops.SiteOperations, error) {
+	return r.operations, nil
+}
+
+func (r *replaceOperator) GetSiteOperation(key ops.SiteOperationKey) (*ops.SiteOperation, error) {
+	for _, operation := range r.operations {
+		if operation.ID == key.OperationID {
+			op := ops.SiteOperation(operation)
+			return &op, nil
+		}
+	}
+	return nil, trace.NotFound("operation %v not found", key.OperationID)
+}
+
+func (r *replaceOperator) GetLocalSite() (*ops.Site, error) {
+	cluster := r.cluster
+	return &cluster, nil
+}
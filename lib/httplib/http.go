@@ -18,6 +18,7 @@ package httplib
 
 import (
 	"encoding/base64"
+	"net"
 	"net/http"
 	"net/url"
 	"strings"
@@ -38,6 +39,8 @@ type AuthCreds struct {
 	Username string
 	// Password holds password in case of Basic auth, http token otherwize
 	Password string
+	// RemoteAddr is the address of the client that presented the credentials
+	RemoteAddr string
 }
 
 func (a *AuthCreds) IsToken() bool {
@@ -47,6 +50,18 @@ func (a *AuthCreds) IsToken() bool {
 // ParseAuthHeaders parses authentication headers from HTTP request
 // it currently detects Bearer and Basic auth types
 func ParseAuthHeaders(r *http.Request) (*AuthCreds, error) {
+	creds, err := parseAuthHeaders(r)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	creds.RemoteAddr = r.RemoteAddr
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		creds.RemoteAddr = host
+	}
+	return creds, nil
+}
+
+func parseAuthHeaders(r *http.Request) (*AuthCreds, error) {
 	// according to the doc below oauth 2.0 bearer access token can
 	// come with query parameter
 	// http://self-issued.info/docs/draft-ietf-oauth-v2-bearer.html#query-param
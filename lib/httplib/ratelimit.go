@@ -0,0 +1,419 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package httplib
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gravitational/gravity/lib/constants"
+	"github.com/gravitational/gravity/lib/defaults"
+
+	"github.com/gravitational/trace"
+	"github.com/jonboulle/clockwork"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/time/rate"
+)
+
+// RequestClass defines the budget a request is accounted against
+type RequestClass string
+
+const (
+	// RequestRead is the class of requests that read state
+	RequestRead RequestClass = "read"
+	// RequestWrite is the class of requests that modify state
+	RequestWrite RequestClass = "write"
+	// RequestTransfer is the class of requests that upload or download
+	// large blobs, such as packages and installers
+	RequestTransfer RequestClass = "transfer"
+	// RequestStream is the class of long-lived requests that stream events
+	// or logs, such as event subscriptions, log uploads and websockets.
+	// They have a separate budget so they do not use up the concurrency
+	// slots of the short read and write requests for their whole lifetime
+	RequestStream RequestClass = "stream"
+)
+
+// RequestClassFor returns the read or write class of the request based on its method
+func RequestClassFor(r *http.Request) RequestClass {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return RequestRead
+	}
+	return RequestWrite
+}
+
+const (
+	// SubjectUser identifies the budget of a user
+	SubjectUser = "user"
+	// SubjectAPIKey identifies the budget of an API key
+	SubjectAPIKey = "api_key"
+	// SubjectCluster identifies the budget of a cluster
+	SubjectCluster = "cluster"
+)
+
+// RateSubject identifies the party requests are accounted against
+type RateSubject struct {
+	// Kind is the subject kind: user, API key or cluster
+	Kind string
+	// Name identifies the subject of the specified kind
+	Name string
+}
+
+// String returns the subject description
+func (s RateSubject) String() string {
+	return fmt.Sprintf("%v %v", s.Kind, s.Name)
+}
+
+// RequestSubjects returns the subjects the request is accounted against:
+// the authenticated user, the API key if the request is authenticated with
+// a bearer token outside of a web session and the cluster, if not empty
+func RequestSubjects(r *http.Request, user, cluster string) (subjects []RateSubject) {
+	if user != "" {
+		subjects = append(subjects, RateSubject{Kind: SubjectUser, Name: user})
+	}
+	if creds, err := ParseAuthHeaders(r); err == nil && creds.Type == AuthBearer && !hasSessionCookie(r) {
+		// do not keep the key itself in memory longer than necessary
+		hash := sha256.Sum256([]byte(creds.Password))
+		subjects = append(subjects, RateSubject{Kind: SubjectAPIKey, Name: hex.EncodeToString(hash[:8])})
+	}
+	if cluster != "" {
+		subjects = append(subjects, RateSubject{Kind: SubjectCluster, Name: cluster})
+	}
+	return subjects
+}
+
+// RateLimit defines the request budget of a single subject
+type RateLimit struct {
+	// Rate is the sustained number of requests per second.
+	// The rate is unlimited if unspecified
+	Rate float64 `yaml:"rate" json:"rate,omitempty"`
+	// Burst is the number of requests that can be made at once
+	// on top of the rate. Defaults to the rate rounded up
+	Burst int `yaml:"burst" json:"burst,omitempty"`
+	// MaxConcurrent is the maximum number of requests served at the same time.
+	// The number of concurrent requests is unlimited if unspecified
+	MaxConcurrent int `yaml:"max_concurrent" json:"max_concurrent,omitempty"`
+}
+
+// IsEmpty returns true if the limit does not restrict requests
+func (l RateLimit) IsEmpty() bool {
+	return l.Rate == 0 && l.MaxConcurrent == 0
+}
+
+// Check validates the limit
+func (l RateLimit) Check() error {
+	if l.Rate < 0 || l.Burst < 0 || l.MaxConcurrent < 0 {
+		return trace.BadParameter("rate limits cannot be negative")
+	}
+	return nil
+}
+
+// RateLimits defines the separate budgets for reads, writes, transfers and streams
+type RateLimits struct {
+	// Read is the budget of read requests
+	Read RateLimit `yaml:"read" json:"read"`
+	// Write is the budget of write requests
+	Write RateLimit `yaml:"write" json:"write"`
+	// Transfer is the budget of blob uploads and downloads
+	Transfer RateLimit `yaml:"transfer" json:"transfer"`
+	// Stream is the budget of long-lived streaming requests
+	Stream RateLimit `yaml:"stream" json:"stream"`
+}
+
+// Get returns the budget for the specified request class
+func (l RateLimits) Get(class RequestClass) RateLimit {
+	switch class {
+	case RequestWrite:
+		return l.Write
+	case RequestTransfer:
+		return l.Transfer
+	case RequestStream:
+		return l.Stream
+	}
+	return l.Read
+}
+
+// Check validates the limits
+func (l RateLimits) Check() error {
+	for _, limit := range []RateLimit{l.Read, l.Write, l.Transfer, l.Stream} {
+		if err := limit.Check(); err != nil {
+			return trace.Wrap(err)
+		}
+	}
+	return nil
+}
+
+// RateLimiterConfig defines the request budgets of each user, API key and cluster
+type RateLimiterConfig struct {
+	// User is the budget of each user
+	User RateLimits `yaml:"user" json:"user"`
+	// APIKey is the budget of each API key
+	APIKey RateLimits `yaml:"api_key" json:"api_key"`
+	// Cluster is the budget of requests to each cluster
+	Cluster RateLimits `yaml:"cluster" json:"cluster"`
+	// Clock is used to control time in tests
+	Clock clockwork.Clock `yaml:"-" json:"-"`
+}
+
+// IsEmpty returns true if no limits have been configured
+func (c RateLimiterConfig) IsEmpty() bool {
+	for _, limits := range []RateLimits{c.User, c.APIKey, c.Cluster} {
+		for _, class := range []RequestClass{RequestRead, RequestWrite, RequestTransfer, RequestStream} {
+			if !limits.Get(class).IsEmpty() {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// CheckAndSetDefaults validates the configuration and sets defaults
+func (c *RateLimiterConfig) CheckAndSetDefaults() error {
+	for _, limits := range []RateLimits{c.User, c.APIKey, c.Cluster} {
+		if err := limits.Check(); err != nil {
+			return trace.Wrap(err)
+		}
+	}
+	if c.Clock == nil {
+		c.Clock = clockwork.NewRealClock()
+	}
+	return nil
+}
+
+func (c RateLimiterConfig) limit(subject RateSubject, class RequestClass) RateLimit {
+	switch subject.Kind {
+	case SubjectUser:
+		return c.User.Get(class)
+	case SubjectAPIKey:
+		return c.APIKey.Get(class)
+	case SubjectCluster:
+		return c.Cluster.Get(class)
+	}
+	return RateLimit{}
+}
+
+// NewRateLimiter returns a new rate limiter with the specified configuration
+func NewRateLimiter(config RateLimiterConfig) (*RateLimiter, error) {
+	if err := config.CheckAndSetDefaults(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return &RateLimiter{
+		RateLimiterConfig: config,
+		buckets:           make(map[bucketKey]*bucket),
+		lastPruned:        config.Clock.Now(),
+	}, nil
+}
+
+// RateLimiter accounts requests against the budgets of their subjects
+type RateLimiter struct {
+	// RateLimiterConfig is the limiter configuration
+	RateLimiterConfig
+	mu         sync.Mutex
+	buckets    map[bucketKey]*bucket
+	lastPruned time.Time
+}
+
+// Limit invokes fn if the request of the specified class fits the budgets of all
+// subjects. Otherwise, it sets the Retry-After header with the number of seconds
+// after which the request can be retried and returns a RateLimitError
+func (l *RateLimiter) Limit(w http.ResponseWriter, class RequestClass, subjects []RateSubject, fn func() error) error {
+	release, err := l.Acquire(class, subjects...)
+	if err != nil {
+		if rateErr, ok := trace.Unwrap(err).(*RateLimitError); ok && rateErr.RetryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(rateErr.RetryAfter.Seconds()))))
+		}
+		return trace.Wrap(err)
+	}
+	defer release()
+	return fn()
+}
+
+// Acquire accounts the request of the specified class against the budgets of
+// all subjects. The request is accounted against either all or none of the budgets.
+// Returns the function to call once the request has been served
+func (l *RateLimiter) Acquire(class RequestClass, subjects ...RateSubject) (release func(), err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.Clock.Now()
+	l.prune(now)
+	var acquired []*bucket
+	var reservations []*rate.Reservation
+	rollback := func() {
+		for _, reservation := range reservations {
+			reservation.CancelAt(now)
+		}
+		for _, bucket := range acquired {
+			bucket.inFlight--
+		}
+	}
+	for _, subject := range subjects {
+		limit := l.limit(subject, class)
+		if limit.IsEmpty() {
+			continue
+		}
+		bucket := l.bucket(subject, class, limit)
+		bucket.lastUsed = now
+		if limit.MaxConcurrent != 0 && bucket.inFlight >= limit.MaxConcurrent {
+			rollback()
+			throttledRequests.WithLabelValues(string(class), subject.Kind, throttledConcurrency).Inc()
+			return nil, trace.Wrap(&RateLimitError{
+				Subject:    subject,
+				Class:      class,
+				RetryAfter: defaults.RateLimitConcurrentRetryAfter,
+				Message: fmt.Sprintf("too many concurrent %v requests by %v, at most %v allowed",
+					class, subject, limit.MaxConcurrent),
+			})
+		}
+		if bucket.limiter != nil {
+			reservation := bucket.limiter.ReserveN(now, 1)
+			if delay := reservation.DelayFrom(now); !reservation.OK() || delay > 0 {
+				reservation.CancelAt(now)
+				rollback()
+				throttledRequests.WithLabelValues(string(class), subject.Kind, throttledRate).Inc()
+				return nil, trace.Wrap(&RateLimitError{
+					Subject:    subject,
+					Class:      class,
+					RetryAfter: delay,
+					Message: fmt.Sprintf("%v request rate limit of %v per second exceeded by %v",
+						class, limit.Rate, subject),
+				})
+			}
+			reservations = append(reservations, reservation)
+		}
+		bucket.inFlight++
+		acquired = append(acquired, bucket)
+	}
+	requestsInFlight.WithLabelValues(string(class)).Inc()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			for _, bucket := range acquired {
+				bucket.inFlight--
+			}
+			requestsInFlight.WithLabelValues(string(class)).Dec()
+		})
+	}, nil
+}
+
+func (l *RateLimiter) bucket(subject RateSubject, class RequestClass, limit RateLimit) *bucket {
+	key := bucketKey{subject: subject, class: class}
+	if b, ok := l.buckets[key]; ok {
+		return b
+	}
+	b := &bucket{}
+	if limit.Rate != 0 {
+		burst := limit.Burst
+		if burst == 0 {
+			burst = int(math.Ceil(limit.Rate))
+		}
+		b.limiter = rate.NewLimiter(rate.Limit(limit.Rate), burst)
+	}
+	l.buckets[key] = b
+	return b
+}
+
+// prune removes the buckets of the subjects that have been idle long enough
+// for their budgets to be replenished
+func (l *RateLimiter) prune(now time.Time) {
+	if now.Sub(l.lastPruned) < defaults.RateLimitPruneInterval {
+		return
+	}
+	l.lastPruned = now
+	for key, bucket := range l.buckets {
+		if bucket.inFlight == 0 && now.Sub(bucket.lastUsed) > defaults.RateLimitIdleTimeout {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+type bucketKey struct {
+	subject RateSubject
+	class   RequestClass
+}
+
+type bucket struct {
+	// limiter limits the request rate, nil if the rate is unlimited
+	limiter *rate.Limiter
+	// inFlight is the number of requests being served
+	inFlight int
+	// lastUsed is the time of the last request
+	lastUsed time.Time
+}
+
+// RateLimitError is returned when a request exceeds the budget of its subject
+type RateLimitError struct {
+	// Subject is the subject whose budget has been exhausted
+	Subject RateSubject
+	// Class is the request class
+	Class RequestClass
+	// RetryAfter is the duration after which the request can be retried
+	RetryAfter time.Duration
+	// Message is the error message
+	Message string
+}
+
+// Error returns the error message
+func (e *RateLimitError) Error() string {
+	return e.Message
+}
+
+// IsLimitExceededError indicates that this error is of LimitExceeded type,
+// so it is returned to clients as 429 Too Many Requests
+func (e *RateLimitError) IsLimitExceededError() bool {
+	return true
+}
+
+func hasSessionCookie(r *http.Request) bool {
+	cookie, err := r.Cookie(constants.SessionCookie)
+	return err == nil && cookie != nil && cookie.Value != ""
+}
+
+var (
+	throttledRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "gravity_http_throttled_requests_total",
+			Help: "Number of HTTP requests rejected because the budget of their user, API key or cluster has been exhausted",
+		},
+		[]string{"class", "subject", "reason"},
+	)
+	requestsInFlight = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "gravity_http_rate_limited_requests_in_flight",
+			Help: "Number of HTTP requests subject to rate limiting currently being served",
+		},
+		[]string{"class"},
+	)
+)
+
+const (
+	// throttledRate is the reason of requests rejected due to the exceeded rate
+	throttledRate = "rate"
+	// throttledConcurrency is the reason of requests rejected due to too many concurrent requests
+	throttledConcurrency = "concurrency"
+)
+
+func init() {
+	prometheus.MustRegister(throttledRequests, requestsInFlight)
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package httplib
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/gravitational/gravity/lib/constants"
+
+	"github.com/gravitational/trace"
+	"github.com/jonboulle/clockwork"
+	. "gopkg.in/check.v1"
+)
+
+type RateLimiterSuite struct{}
+
+var _ = Suite(&RateLimiterSuite{})
+
+var (
+	alice   = RateSubject{Kind: SubjectUser, Name: "alice@example.com"}
+	cluster = RateSubject{Kind: SubjectCluster, Name: "example.com"}
+)
+
+func (s *RateLimiterSuite) TestLimitsRate(c *C) {
+	clock := clockwork.NewFakeClock()
+	limiter, err := NewRateLimiter(RateLimiterConfig{
+		User:  RateLimits{Read: RateLimit{Rate: 1, Burst: 2}},
+		Clock: clock,
+	})
+	c.Assert(err, IsNil)
+
+	for i := 0; i < 2; i++ {
+		release, err := limiter.Acquire(RequestRead, alice)
+		c.Assert(err, IsNil)
+		release()
+	}
+	_, err = limiter.Acquire(RequestRead, alice)
+	c.Assert(trace.IsLimitExceeded(err), Equals, true, Commentf("%v", err))
+	rateErr, ok := trace.Unwrap(err).(*RateLimitError)
+	c.Assert(ok, Equals, true)
+	c.Assert(rateErr.Subject, Equals, alice)
+	c.Assert(rateErr.RetryAfter, Equals, time.Second)
+
+	// writes have a separate budget
+	release, err := limiter.Acquire(RequestWrite, alice)
+	c.Assert(err, IsNil)
+	release()
+
+	clock.Advance(time.Second)
+	release, err = limiter.Acquire(RequestRead, alice)
+	c.Assert(err, IsNil)
+	release()
+}
+
+func (s *RateLimiterSuite) TestLimitsConcurrentTransfers(c *C) {
+	limiter, err := NewRateLimiter(RateLimiterConfig{
+		User:  RateLimits{Transfer: RateLimit{MaxConcurrent: 1}},
+		Clock: clockwork.NewFakeClock(),
+	})
+	c.Assert(err, IsNil)
+
+	release, err := limiter.Acquire(RequestTransfer, alice)
+	c.Assert(err, IsNil)
+	_, err = limiter.Acquire(RequestTransfer, alice)
+	c.Assert(trace.IsLimitExceeded(err), Equals, true, Commentf("%v", err))
+
+	// other users are not affected
+	bob := RateSubject{Kind: SubjectUser, Name: "bob@example.com"}
+	releaseBob, err := limiter.Acquire(RequestTransfer, bob)
+	c.Assert(err, IsNil)
+	releaseBob()
+
+	release()
+	// releasing twice has no effect
+	release()
+	release, err = limiter.Acquire(RequestTransfer, alice)
+	c.Assert(err, IsNil)
+	release()
+}
+
+func (s *RateLimiterSuite) TestStreamsHaveSeparateBudget(c *C) {
+	limiter, err := NewRateLimiter(RateLimiterConfig{
+		User: RateLimits{
+			Read:   RateLimit{MaxConcurrent: 1},
+			Stream: RateLimit{MaxConcurrent: 1},
+		},
+		Clock: clockwork.NewFakeClock(),
+	})
+	c.Assert(err, IsNil)
+
+	releaseStream, err := limiter.Acquire(RequestStream, alice)
+	c.Assert(err, IsNil)
+	defer releaseStream()
+
+	// an open stream does not hold the read slot
+	release, err := limiter.Acquire(RequestRead, alice)
+	c.Assert(err, IsNil)
+	release()
+
+	_, err = limiter.Acquire(RequestStream, alice)
+	c.Assert(trace.IsLimitExceeded(err), Equals, true, Commentf("%v", err))
+}
+
+func (s *RateLimiterSuite) TestAccountsAgainstAllSubjects(c *C) {
+	limiter, err := NewRateLimiter(RateLimiterConfig{
+		User:    RateLimits{Write: RateLimit{Rate: 1, Burst: 1}},
+		Cluster: RateLimits{Write: RateLimit{MaxConcurrent: 1}},
+		Clock:   clockwork.NewFakeClock(),
+	})
+	c.Assert(err, IsNil)
+
+	bob := RateSubject{Kind: SubjectUser, Name: "bob@example.com"}
+	release, err := limiter.Acquire(RequestWrite, bob, cluster)
+	c.Assert(err, IsNil)
+	defer release()
+
+	_, err = limiter.Acquire(RequestWrite, alice, cluster)
+	c.Assert(trace.IsLimitExceeded(err), Equals, true, Commentf("%v", err))
+	c.Assert(trace.Unwrap(err).(*RateLimitError).Subject, Equals, cluster)
+
+	// the rejected request has not used up the user budget
+	releaseAlice, err := limiter.Acquire(RequestWrite, alice)
+	c.Assert(err, IsNil)
+	releaseAlice()
+}
+
+func (s *RateLimiterSuite) TestWritesRetryHint(c *C) {
+	limiter, err := NewRateLimiter(RateLimiterConfig{
+		Cluster: RateLimits{Read: RateLimit{Rate: 0.5}},
+		Clock:   clockwork.NewFakeClock(),
+	})
+	c.Assert(err, IsNil)
+	handle := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		err := limiter.Limit(w, RequestRead, []RateSubject{cluster}, func() error {
+			w.WriteHeader(http.StatusOK)
+			return nil
+		})
+		if err != nil {
+			trace.WriteError(w, err)
+		}
+		return w
+	}
+
+	c.Assert(handle().Code, Equals, http.StatusOK)
+	w := handle()
+	c.Assert(w.Code, Equals, http.StatusTooManyRequests)
+	c.Assert(w.Header().Get("Retry-After"), Equals, "2")
+}
+
+func (s *RateLimiterSuite) TestRequestSubjects(c *C) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Authorization", "Bearer secret-key")
+	subjects := RequestSubjects(r, "alice@example.com", "example.com")
+	c.Assert(subjects, HasLen, 3)
+	c.Assert(subjects[0], Equals, alice)
+	c.Assert(subjects[1].Kind, Equals, SubjectAPIKey)
+	c.Assert(subjects[1].Name, Not(Equals), "secret-key")
+	c.Assert(subjects[2], Equals, cluster)
+
+	// bearer token of a web session is not an API key
+	r.AddCookie(&http.Cookie{Name: constants.SessionCookie, Value: "session"})
+	c.Assert(RequestSubjects(r, "alice@example.com", ""), DeepEquals, []RateSubject{alice})
+}
@@ -68,11 +68,13 @@ func (c *Config) RunLocalChecks(ctx context.Context) error {
 		Role:     c.Role,
 		Docker:   c.Docker,
 		Options: &validationpb.ValidateOptions{
-			VxlanPort: int32(c.VxlanPort),
-			DnsAddrs:  c.DNSConfig.Addrs,
-			DnsPort:   int32(c.DNSConfig.Port),
+			VxlanPort:     int32(c.VxlanPort),
+			DnsAddrs:      c.DNSConfig.Addrs,
+			DnsPort:       int32(c.DNSConfig.Port),
+			AdvertiseAddr: c.AdvertiseAddr,
 		},
 		AutoFix: true,
+		FixHost: c.FixHost,
 	}))
 }
 
@@ -119,6 +121,8 @@ type Config struct {
 	SystemDevice string
 	// DockerDevice is a device for docker
 	DockerDevice string
+	// FixHost enables fixing host environment problems found by pre-flight checks
+	FixHost bool
 	// Mounts is a list of mount points (name -> source pairs)
 	Mounts map[string]string
 	// DNSOverrides contains installer node DNS overrides
@@ -31,6 +31,7 @@ import (
 	"github.com/gravitational/gravity/lib/fsm"
 	"github.com/gravitational/gravity/lib/ops"
 	"github.com/gravitational/gravity/lib/ops/opsservice"
+	"github.com/gravitational/gravity/lib/overlay"
 	"github.com/gravitational/gravity/lib/schema"
 	"github.com/gravitational/gravity/lib/state"
 	"github.com/gravitational/gravity/lib/storage"
@@ -120,11 +121,17 @@ func (p *bootstrapExecutor) Execute(ctx context.Context) error {
 	if err != nil {
 		return trace.Wrap(err)
 	}
-	if dockerConfig.StorageDriver == constants.DockerStorageDriverDevicemapper {
+	switch dockerConfig.StorageDriver {
+	case constants.DockerStorageDriverDevicemapper:
 		err := p.configureDeviceMapper()
 		if err != nil {
 			return trace.Wrap(err)
 		}
+	case constants.DockerStorageDriverOverlay2:
+		err := p.configureOverlay()
+		if err != nil {
+			return trace.Wrap(err)
+		}
 	}
 	err = p.configureSystemDirectories()
 	if err != nil {
@@ -177,6 +184,26 @@ func (p *bootstrapExecutor) configureDeviceMapper() error {
 	return nil
 }
 
+// configureOverlay formats and mounts the device for Docker overlay2 storage driver
+func (p *bootstrapExecutor) configureOverlay() error {
+	node := p.Phase.Data.Server
+	if node.Docker.Device.Path() == "" {
+		// overlay2 does not require a dedicated device
+		return nil
+	}
+	p.Progress.NextStep("Configuring device for Docker overlay2 storage driver")
+	p.Info("Configuring device for Docker overlay2 storage driver.")
+	err := overlay.Mount(overlay.Config{
+		FieldLogger: p.FieldLogger,
+		Disk:        node.Docker.Device.Path(),
+		Out:         os.Stderr,
+	})
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	return nil
+}
+
 // configureSystemDirectories creates necessary system directories with
 // proper permissions
 func (p *bootstrapExecutor) configureSystemDirectories() error {
@@ -32,6 +32,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/kubernetes"
 	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
@@ -39,23 +40,83 @@ import (
 
 // drainPods removes pods according to the specified configuration
 func (d *drain) drainPods(ctx context.Context) error {
+	if d.timeout != 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d.timeout)
+		defer cancel()
+	}
+
 	pods, err := d.getPodsForDeletion()
 	if err != nil {
 		return trace.Wrap(err)
 	}
 
+	blockedPods, err := d.getPodsBlockedByDisruptionBudget(pods)
+	if err != nil {
+		log.Warnf("Failed to query pod disruption budgets: %v.", trace.DebugReport(err))
+	}
+	for _, pod := range blockedPods {
+		log.WithFields(podFields(pod)).Warn("Pod disruption budget does not currently allow eviction.")
+	}
+
 	err = d.deleteOrEvictPods(ctx, pods)
 	if err != nil {
-		pendingPods, err := d.getPodsForDeletion()
-		if err != nil {
-			return trace.Wrap(err)
+		pendingPods, errPending := d.getPodsForDeletion()
+		if errPending != nil {
+			return trace.Wrap(errPending)
 		}
 		log.Warningf("error deleting pods: %v\npending pods: %v",
 			trace.DebugReport(err), formatPodList(pendingPods))
+		if ctx.Err() == context.DeadlineExceeded {
+			return trace.LimitExceeded("failed to drain node %v in %v, pending pods: %v "+
+				"(pods blocked by disruption budgets: %v)", d.nodeName, d.timeout,
+				formatPodList(pendingPods), formatPodList(blockedPods))
+		}
 	}
 	return trace.Wrap(err)
 }
 
+// getPodsBlockedByDisruptionBudget returns the subset of the specified pods
+// that are covered by a pod disruption budget which does not currently allow
+// any disruptions.
+// Evictions of such pods will be retried until the budget allows them
+func (d *drain) getPodsBlockedByDisruptionBudget(pods []v1.Pod) (blocked []v1.Pod, err error) {
+	budgets := make(map[string][]policy.PodDisruptionBudget)
+	for _, pod := range pods {
+		namespaceBudgets, ok := budgets[pod.Namespace]
+		if !ok {
+			list, err := d.client.PolicyV1beta1().PodDisruptionBudgets(pod.Namespace).List(metav1.ListOptions{})
+			if err != nil {
+				return nil, rigging.ConvertError(err)
+			}
+			namespaceBudgets = list.Items
+			budgets[pod.Namespace] = namespaceBudgets
+		}
+		if isBlockedByDisruptionBudget(pod, namespaceBudgets) {
+			blocked = append(blocked, pod)
+		}
+	}
+	return blocked, nil
+}
+
+// isBlockedByDisruptionBudget returns true if the specified pod is matched
+// by one of the budgets that does not allow disruptions
+func isBlockedByDisruptionBudget(pod v1.Pod, budgets []policy.PodDisruptionBudget) bool {
+	for _, budget := range budgets {
+		if budget.Status.PodDisruptionsAllowed > 0 {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(budget.Spec.Selector)
+		if err != nil || selector.Empty() {
+			continue
+		}
+		if selector.Matches(labels.Set(pod.Labels)) {
+			return true
+		}
+	}
+	return false
+}
+
 // deleteOrEvictPods evicts the pods depending if the api server supports Eviction API
 // and deletes them otherwise
 func (d *drain) deleteOrEvictPods(ctx context.Context, pods []v1.Pod) error {
@@ -102,6 +163,8 @@ func (d *drain) evictPodAndWait(ctx context.Context, pod v1.Pod, policyGroupVers
 			if errors.IsNotFound(trace.Unwrap(err)) {
 				return nil
 			} else if errors.IsTooManyRequests(trace.Unwrap(err)) {
+				// Eviction is rejected while it would violate the pod's disruption budget
+				log.WithFields(podFields(pod)).Info("Eviction blocked by disruption budget, will retry.")
 				return trace.Retry(err, "too many requests")
 			}
 			return &backoff.PermanentError{Err: rigging.ConvertError(err)}
@@ -0,0 +1,72 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"k8s.io/api/core/v1"
+	policy "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "gopkg.in/check.v1"
+)
+
+type DrainSuite struct{}
+
+var _ = Suite(&DrainSuite{})
+
+func (*DrainSuite) TestDetectsPodsBlockedByDisruptionBudget(c *C) {
+	budgets := []policy.PodDisruptionBudget{
+		newDisruptionBudget(map[string]string{"app": "db"}, 0),
+		newDisruptionBudget(map[string]string{"app": "web"}, 1),
+	}
+	testCases := []struct {
+		labels  map[string]string
+		blocked bool
+		comment string
+	}{
+		{
+			labels:  map[string]string{"app": "db", "tier": "backend"},
+			blocked: true,
+			comment: "budget does not allow disruptions",
+		},
+		{
+			labels:  map[string]string{"app": "web"},
+			blocked: false,
+			comment: "budget allows disruptions",
+		},
+		{
+			labels:  map[string]string{"app": "cache"},
+			blocked: false,
+			comment: "pod is not covered by a budget",
+		},
+	}
+	for _, tc := range testCases {
+		pod := v1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: tc.labels}}
+		c.Assert(isBlockedByDisruptionBudget(pod, budgets), Equals, tc.blocked, Commentf(tc.comment))
+	}
+}
+
+func newDisruptionBudget(labels map[string]string, disruptionsAllowed int32) policy.PodDisruptionBudget {
+	return policy.PodDisruptionBudget{
+		Spec: policy.PodDisruptionBudgetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+		},
+		Status: policy.PodDisruptionBudgetStatus{
+			PodDisruptionsAllowed: disruptionsAllowed,
+		},
+	}
+}
@@ -19,6 +19,7 @@ package kubernetes
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/gravitational/gravity/lib/defaults"
 	"github.com/gravitational/gravity/lib/storage"
@@ -50,6 +51,25 @@ func Drain(ctx context.Context, client *kubernetes.Clientset, nodeName string) e
 	return trace.Wrap(err)
 }
 
+// DrainWithTimeout drains the specified node like Drain but fails if the node
+// has not been drained within the specified timeout.
+// Pods that cannot be evicted due to their pod disruption budgets are reported
+func DrainWithTimeout(ctx context.Context, client *kubernetes.Clientset, nodeName string, timeout time.Duration) error {
+	err := SetUnschedulable(ctx, client.CoreV1().Nodes(), nodeName, true)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	d := drain{
+		client:             client,
+		nodeName:           nodeName,
+		gracePeriodSeconds: defaults.ResourceGracePeriod,
+		timeout:            timeout,
+	}
+	err = d.drainPods(ctx)
+	return trace.Wrap(err)
+}
+
 // SetUnschedulable marks the specified node as unschedulable depending on the value of the specified flag.
 // Retries the operation internally on update conflicts.
 func SetUnschedulable(ctx context.Context, client corev1.NodeInterface, nodeName string, unschedulable bool) error {
@@ -103,7 +123,8 @@ func UpdateTaints(ctx context.Context, client corev1.NodeInterface, nodeName str
 	return rigging.ConvertError(err)
 }
 
-// UpdateLabels adds labels on the node specified with nodeName
+// UpdateLabels adds labels on the node specified with nodeName.
+// The node is not updated if it already has the labels
 func UpdateLabels(ctx context.Context, client corev1.NodeInterface, nodeName string, labels map[string]string) error {
 	err := Retry(ctx, func() error {
 		return trace.Wrap(updateLabels(client, nodeName, labels))
@@ -112,6 +133,15 @@ func UpdateLabels(ctx context.Context, client corev1.NodeInterface, nodeName str
 	return rigging.ConvertError(err)
 }
 
+// RemoveLabels removes the specified labels from the node specified with nodeName
+func RemoveLabels(ctx context.Context, client corev1.NodeInterface, nodeName string, labels ...string) error {
+	err := Retry(ctx, func() error {
+		return trace.Wrap(removeLabels(client, nodeName, labels))
+	})
+
+	return rigging.ConvertError(err)
+}
+
 // GetNode returns Kubernetes node corresponding to the provided server
 func GetNode(client *kubernetes.Clientset, server storage.Server) (*v1.Node, error) {
 	nodes, err := client.CoreV1().Nodes().List(metav1.ListOptions{
@@ -168,6 +198,13 @@ func updateLabels(client corev1.NodeInterface, nodeName string, labels map[strin
 		return trace.Wrap(err)
 	}
 
+	if hasLabels(node.Labels, labels) {
+		return nil
+	}
+
+	if node.Labels == nil {
+		node.Labels = make(map[string]string, len(labels))
+	}
 	for name, value := range labels {
 		node.Labels[name] = value
 	}
@@ -176,6 +213,31 @@ func updateLabels(client corev1.NodeInterface, nodeName string, labels map[strin
 	return rigging.ConvertError(err)
 }
 
+// hasLabels returns true if nodeLabels already include all of the specified labels
+func hasLabels(nodeLabels, labels map[string]string) bool {
+	for name, value := range labels {
+		if existing, ok := nodeLabels[name]; !ok || existing != value {
+			return false
+		}
+	}
+	return true
+}
+
+// removeLabels removes labels from the node specified with nodeName
+func removeLabels(client corev1.NodeInterface, nodeName string, labels []string) error {
+	node, err := client.Get(nodeName, metav1.GetOptions{})
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	for _, name := range labels {
+		delete(node.Labels, name)
+	}
+
+	_, err = client.Update(node)
+	return rigging.ConvertError(err)
+}
+
 // deleteTaints deletes the given taints from the node's list of taints
 func deleteTaints(taintsToDelete []v1.Taint, newTaints *[]v1.Taint) (deleted bool, err error) {
 	var errors []error
@@ -0,0 +1,65 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	. "gopkg.in/check.v1"
+)
+
+type NodesSuite struct{}
+
+var _ = Suite(&NodesSuite{})
+
+func (*NodesSuite) TestUpdatesLabelsOnlyIfChanged(c *C) {
+	nodes := &nodeClient{node: v1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name:   "node-1",
+		Labels: map[string]string{"pool": "workers"},
+	}}}
+
+	c.Assert(updateLabels(nodes, "node-1", map[string]string{"pool": "workers"}), IsNil)
+	c.Assert(nodes.updates, Equals, 0)
+
+	c.Assert(updateLabels(nodes, "node-1", map[string]string{"pool": "workers", "rack": "r1"}), IsNil)
+	c.Assert(nodes.updates, Equals, 1)
+	c.Assert(nodes.node.Labels, DeepEquals, map[string]string{"pool": "workers", "rack": "r1"})
+
+	c.Assert(updateLabels(nodes, "node-1", map[string]string{"rack": "r2"}), IsNil)
+	c.Assert(nodes.updates, Equals, 2)
+	c.Assert(nodes.node.Labels["rack"], Equals, "r2")
+}
+
+// nodeClient is the nodes client that serves a single node
+type nodeClient struct {
+	corev1.NodeInterface
+	node    v1.Node
+	updates int
+}
+
+func (r *nodeClient) Get(name string, options metav1.GetOptions) (*v1.Node, error) {
+	node := r.node.DeepCopy()
+	return node, nil
+}
+
+func (r *nodeClient) Update(node *v1.Node) (*v1.Node, error) {
+	r.updates++
+	r.node = *node.DeepCopy()
+	return node, nil
+}
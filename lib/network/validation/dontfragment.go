@@ -0,0 +1,32 @@
+// +build !linux
+
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"net"
+
+	"github.com/gravitational/trace"
+)
+
+// setDontFragment sets the DF bit on the packets sent over the specified
+// connection so that packets exceeding the path MTU are dropped instead
+// of being fragmented
+func setDontFragment(conn *net.UDPConn) error {
+	return trace.NotImplemented("API is not supported")
+}
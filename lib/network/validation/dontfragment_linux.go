@@ -0,0 +1,46 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"net"
+
+	"github.com/gravitational/trace"
+	"golang.org/x/sys/unix"
+)
+
+// setDontFragment sets the DF bit on the packets sent over the specified
+// connection so that packets exceeding the path MTU are dropped instead
+// of being fragmented
+func setDontFragment(conn *net.UDPConn) error {
+	rawConn, err := conn.SyscallConn()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	var sockErr error
+	err = rawConn.Control(func(fd uintptr) {
+		sockErr = unix.SetsockoptInt(int(fd), unix.IPPROTO_IP,
+			unix.IP_MTU_DISCOVER, unix.IP_PMTUDISC_DO)
+	})
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if sockErr != nil {
+		return trace.ConvertSystemError(sockErr)
+	}
+	return nil
+}
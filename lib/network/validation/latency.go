@@ -0,0 +1,115 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"sync"
+
+	pb "github.com/gravitational/gravity/lib/network/validation/proto"
+
+	"github.com/gravitational/trace"
+	"golang.org/x/net/context"
+)
+
+// CheckLatency measures the round-trip time and jitter to the servers
+// specified in the request while acknowledging probes from them
+func (r *Server) CheckLatency(ctx context.Context, req *pb.CheckLatencyRequest) (*pb.CheckLatencyResponse, error) {
+	if err := req.Check(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	duration, err := pb.DurationFromProto(req.Duration)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	results := make([]*pb.LatencyResult, len(req.Ping))
+	err = withProbeResponder(ctx, probeAddr(req.Listen), duration, func(ctx context.Context) {
+		var wg sync.WaitGroup
+		for i, server := range req.Ping {
+			wg.Add(1)
+			go func(i int, server *pb.Addr) {
+				defer wg.Done()
+				results[i] = checkLatency(ctx, server, int(req.Count))
+			}(i, server)
+		}
+		wg.Wait()
+	})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	return &pb.CheckLatencyResponse{Results: results}, nil
+}
+
+// CheckPacketLoss measures the UDP packet loss to the servers specified
+// in the request while acknowledging probes from them
+func (r *Server) CheckPacketLoss(ctx context.Context, req *pb.CheckPacketLossRequest) (*pb.CheckPacketLossResponse, error) {
+	if err := req.Check(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	duration, err := pb.DurationFromProto(req.Duration)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	results := make([]*pb.PacketLossResult, len(req.Ping))
+	err = withProbeResponder(ctx, probeAddr(req.Listen), duration, func(ctx context.Context) {
+		var wg sync.WaitGroup
+		for i, server := range req.Ping {
+			wg.Add(1)
+			go func(i int, server *pb.Addr) {
+				defer wg.Done()
+				results[i] = checkPacketLoss(ctx, server, int(req.Count))
+			}(i, server)
+		}
+		wg.Wait()
+	})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	return &pb.CheckPacketLossResponse{Results: results}, nil
+}
+
+func checkLatency(ctx context.Context, server *pb.Addr, count int) *pb.LatencyResult {
+	stats, err := measure(ctx, probeAddr(server), count)
+	if err != nil {
+		return &pb.LatencyResult{Server: server, Code: 1, Error: err.Error()}
+	}
+	if len(stats.rtts) == 0 {
+		return &pb.LatencyResult{Server: server, Code: 1, Error: "no probes were acknowledged"}
+	}
+	return &pb.LatencyResult{
+		Server: server,
+		Rtt:    percentiles(stats.rtts),
+		Jitter: percentiles(stats.jitter()),
+	}
+}
+
+func checkPacketLoss(ctx context.Context, server *pb.Addr, count int) *pb.PacketLossResult {
+	stats, err := measure(ctx, probeAddr(server), count)
+	if err != nil {
+		return &pb.PacketLossResult{Server: server, Code: 1, Error: err.Error()}
+	}
+	return &pb.PacketLossResult{
+		Server:   server,
+		Sent:     uint32(stats.sent),
+		Received: uint32(len(stats.rtts)),
+	}
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/gravitational/gravity/lib/defaults"
+	pb "github.com/gravitational/gravity/lib/network/validation/proto"
+
+	"github.com/gravitational/trace"
+	"golang.org/x/net/context"
+)
+
+// CheckPathMTU discovers the path MTU to the servers specified in the request
+// while acknowledging probes from them
+func (r *Server) CheckPathMTU(ctx context.Context, req *pb.CheckPathMTURequest) (*pb.CheckPathMTUResponse, error) {
+	if err := req.Check(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	duration, err := pb.DurationFromProto(req.Duration)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	results := make([]*pb.PathMTUResult, len(req.Ping))
+	err = withProbeResponder(ctx, probeAddr(req.Listen), duration, func(ctx context.Context) {
+		var wg sync.WaitGroup
+		for i, server := range req.Ping {
+			wg.Add(1)
+			go func(i int, server *pb.Addr) {
+				defer wg.Done()
+				results[i] = checkPathMTU(ctx, server, int(req.MaxMtu))
+			}(i, server)
+		}
+		wg.Wait()
+	})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	return &pb.CheckPathMTUResponse{Results: results}, nil
+}
+
+func checkPathMTU(ctx context.Context, server *pb.Addr, maxMTU int) *pb.PathMTUResult {
+	mtu, err := discoverPathMTU(ctx, probeAddr(server), maxMTU)
+	if err != nil {
+		return &pb.PathMTUResult{Server: server, Code: 1, Error: err.Error()}
+	}
+	return &pb.PathMTUResult{Server: server, Mtu: uint32(mtu)}
+}
+
+// discoverPathMTU performs a binary search for the largest IP packet
+// that reaches the responder at the specified address without fragmentation
+func discoverPathMTU(ctx context.Context, addr string, maxMTU int) (int, error) {
+	prober, err := dialProber(addr)
+	if err != nil {
+		return 0, trace.Wrap(err)
+	}
+	defer prober.Close()
+	if err := setDontFragment(prober.UDPConn); err != nil {
+		return 0, trace.Wrap(err)
+	}
+	if err := prober.waitForResponder(ctx); err != nil {
+		return 0, trace.Wrap(err)
+	}
+	seq := uint64(1)
+	low, high := minPathMTU, maxMTU
+	ok, err := prober.probeMTU(ctx, seq, low)
+	if err != nil {
+		return 0, trace.Wrap(err)
+	}
+	if !ok {
+		return 0, trace.BadParameter("packets of %v bytes do not reach %v", low, addr)
+	}
+	for low < high {
+		mid := (low + high + 1) / 2
+		seq++
+		ok, err := prober.probeMTU(ctx, seq, mid)
+		if err != nil {
+			return 0, trace.Wrap(err)
+		}
+		if ok {
+			low = mid
+		} else {
+			high = mid - 1
+		}
+	}
+	return low, nil
+}
+
+// probeMTU returns true if an IP packet of the specified size
+// reaches the responder
+func (r *prober) probeMTU(ctx context.Context, seq uint64, mtu int) (bool, error) {
+	size := mtu - ipUDPHeaderSize
+	for attempt := 0; attempt < mtuProbeAttempts; attempt++ {
+		err := r.send(seq, size)
+		if err != nil {
+			// the packet exceeds the MTU of the local interface or the
+			// path MTU already learned from an ICMP "fragmentation needed"
+			if isErrno(err, syscall.EMSGSIZE) {
+				return false, nil
+			}
+			if !isConnectionRefused(err) {
+				return false, trace.Wrap(err)
+			}
+		}
+		deadline := time.Now().Add(defaults.NetworkProbeTimeout)
+		for {
+			ackSeq, ackSize, err := r.receive(deadline)
+			if err != nil {
+				if isTimeout(err) {
+					break
+				}
+				return false, trace.Wrap(err)
+			}
+			if ackSeq == seq && ackSize == size {
+				return true, nil
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return false, trace.LimitExceeded("timeout discovering path MTU to %v", r.RemoteAddr())
+		default:
+		}
+	}
+	return false, nil
+}
+
+const (
+	// minPathMTU is the smallest path MTU the discovery starts with
+	minPathMTU = 576
+	// ipUDPHeaderSize is the combined size of the IPv4 and UDP headers
+	ipUDPHeaderSize = 28
+	// mtuProbeAttempts is the number of times a probe is retried
+	// before the packet size is considered too large
+	mtuProbeAttempts = 2
+)
@@ -0,0 +1,306 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"context"
+	"encoding/binary"
+	"math"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/gravitational/gravity/lib/defaults"
+	pb "github.com/gravitational/gravity/lib/network/validation/proto"
+	"github.com/gravitational/gravity/lib/utils"
+
+	"github.com/gravitational/trace"
+	log "github.com/sirupsen/logrus"
+)
+
+// withProbeResponder starts a UDP responder on the specified address and
+// invokes fn while the responder is serving.
+// The responder keeps serving for the whole duration so that peers that
+// start probing later still get their probes acknowledged
+func withProbeResponder(ctx context.Context, addr string, duration time.Duration, fn func(context.Context)) error {
+	conn, err := net.ListenPacket("udp", addr)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	log.Debugf("started probe responder: %v", addr)
+	ctx, cancel := context.WithTimeout(ctx, duration)
+	defer cancel()
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+	doneCh := make(chan struct{})
+	go func() {
+		serveProbes(conn)
+		close(doneCh)
+	}()
+	fn(ctx)
+	<-doneCh
+	log.Debugf("stopped probe responder: %v", addr)
+	return nil
+}
+
+// serveProbes acknowledges incoming probes until the connection is closed.
+// The acknowledgement contains the sequence number and the size of the
+// received probe and is kept small so that the reverse path MTU does not
+// affect the results
+func serveProbes(conn net.PacketConn) {
+	buf := make([]byte, math.MaxUint16)
+	ack := make([]byte, probeAckSize)
+	for {
+		n, raddr, err := conn.ReadFrom(buf)
+		if err != nil {
+			if !utils.IsClosedConnectionError(err) {
+				log.Warnf("Failed to read probe: %v.", trace.DebugReport(err))
+			}
+			return
+		}
+		if n < probeHeaderSize {
+			continue
+		}
+		copy(ack, buf[:probeHeaderSize])
+		binary.BigEndian.PutUint32(ack[probeHeaderSize:], uint32(n))
+		_, err = conn.WriteTo(ack, raddr)
+		if err != nil {
+			log.Warnf("Failed to acknowledge probe from %v: %v.", raddr, err)
+		}
+	}
+}
+
+// prober sends probes to a remote responder
+type prober struct {
+	*net.UDPConn
+}
+
+// dialProber creates a new prober for the responder at the specified address
+func dialProber(addr string) (*prober, error) {
+	raddr, err := net.ResolveUDPAddr("udp", addr)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	conn, err := net.DialUDP("udp", nil, raddr)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return &prober{UDPConn: conn}, nil
+}
+
+// send sends a probe with the specified sequence number and total size
+func (r *prober) send(seq uint64, size int) error {
+	buf := make([]byte, utils.Max(size, probeHeaderSize))
+	binary.BigEndian.PutUint64(buf, seq)
+	_, err := r.Write(buf)
+	return trace.Wrap(err)
+}
+
+// receive waits for the next acknowledgement until the specified deadline.
+// Returns the sequence number and the size of the acknowledged probe
+func (r *prober) receive(deadline time.Time) (seq uint64, size int, err error) {
+	if err := r.SetReadDeadline(deadline); err != nil {
+		return 0, 0, trace.Wrap(err)
+	}
+	buf := make([]byte, probeAckSize)
+	for {
+		n, err := r.Read(buf)
+		if err != nil {
+			if isConnectionRefused(err) {
+				// the responder has not started yet
+				continue
+			}
+			return 0, 0, trace.Wrap(err)
+		}
+		if n != probeAckSize {
+			continue
+		}
+		return binary.BigEndian.Uint64(buf), int(binary.BigEndian.Uint32(buf[probeHeaderSize:])), nil
+	}
+}
+
+// waitForResponder sends a warm-up probe until the responder acknowledges it
+// so the measurement is not affected by peers still starting up
+func (r *prober) waitForResponder(ctx context.Context) error {
+	for {
+		if err := r.send(warmupSeq, probeHeaderSize); err != nil && !isConnectionRefused(err) {
+			return trace.Wrap(err)
+		}
+		seq, _, err := r.receive(time.Now().Add(defaults.NetworkProbeTimeout))
+		if err == nil && seq == warmupSeq {
+			return nil
+		}
+		if err != nil && !isTimeout(err) {
+			return trace.Wrap(err)
+		}
+		select {
+		case <-ctx.Done():
+			return trace.LimitExceeded("%v did not respond to probes", r.RemoteAddr())
+		default:
+		}
+	}
+}
+
+// probeStats describes the results of a probe series
+type probeStats struct {
+	// sent is the number of sent probes
+	sent int
+	// rtts lists the round-trip times of acknowledged probes
+	// in the order the probes were sent
+	rtts []time.Duration
+}
+
+// measure sends count probes to the responder at the specified address
+// spread evenly over the time remaining until the context deadline
+// and collects the acknowledgements
+func measure(ctx context.Context, addr string, count int) (*probeStats, error) {
+	prober, err := dialProber(addr)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	defer prober.Close()
+	if err := prober.waitForResponder(ctx); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(defaults.NetworkProbeDuration)
+	}
+	// send probes during the first half of the remaining time and
+	// leave the rest for the acknowledgements to arrive
+	interval := time.Until(deadline) / time.Duration(2*count)
+	var mu sync.Mutex
+	sentAt := make([]time.Time, count)
+	rtts := make([]time.Duration, count)
+	errCh := make(chan error, 1)
+	go func() {
+		for i := 0; i < count; i++ {
+			mu.Lock()
+			sentAt[i] = time.Now()
+			mu.Unlock()
+			if err := prober.send(uint64(i+1), probeHeaderSize); err != nil && !isConnectionRefused(err) {
+				errCh <- trace.Wrap(err)
+				return
+			}
+			time.Sleep(interval)
+		}
+		errCh <- nil
+	}()
+	var received int
+	for received < count {
+		seq, _, err := prober.receive(deadline)
+		if err != nil {
+			if isTimeout(err) {
+				break
+			}
+			return nil, trace.Wrap(err)
+		}
+		if seq == warmupSeq || seq > uint64(count) || rtts[seq-1] != 0 {
+			continue
+		}
+		mu.Lock()
+		rtts[seq-1] = time.Since(sentAt[seq-1])
+		mu.Unlock()
+		received++
+	}
+	if err := <-errCh; err != nil {
+		return nil, trace.Wrap(err)
+	}
+	stats := &probeStats{sent: count}
+	for _, rtt := range rtts {
+		if rtt != 0 {
+			stats.rtts = append(stats.rtts, rtt)
+		}
+	}
+	return stats, nil
+}
+
+// jitter returns the differences between consecutive round-trip times
+func (r probeStats) jitter() (result []time.Duration) {
+	for i := 1; i < len(r.rtts); i++ {
+		diff := r.rtts[i] - r.rtts[i-1]
+		if diff < 0 {
+			diff = -diff
+		}
+		result = append(result, diff)
+	}
+	return result
+}
+
+// percentiles computes the latency distribution of the specified values
+func percentiles(values []time.Duration) *pb.LatencyPercentiles {
+	sorted := make([]time.Duration, len(values))
+	copy(sorted, values)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return &pb.LatencyPercentiles{
+		P50: pb.DurationProto(percentile(sorted, 0.5)),
+		P90: pb.DurationProto(percentile(sorted, 0.9)),
+		P99: pb.DurationProto(percentile(sorted, 0.99)),
+		Max: pb.DurationProto(percentile(sorted, 1)),
+	}
+}
+
+// percentile returns the nearest-rank percentile p of the sorted values
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
+
+// probeAddr returns the address of the probe responder for the specified endpoint
+func probeAddr(addr *pb.Addr) string {
+	return utils.EnsurePort(addr.Addr, strconv.Itoa(defaults.NetworkProbePort))
+}
+
+func isTimeout(err error) bool {
+	netErr, ok := trace.Unwrap(err).(net.Error)
+	return ok && netErr.Timeout()
+}
+
+func isConnectionRefused(err error) bool {
+	return isErrno(err, syscall.ECONNREFUSED)
+}
+
+func isErrno(err error, errno syscall.Errno) bool {
+	opErr, ok := trace.Unwrap(err).(*net.OpError)
+	if !ok {
+		return false
+	}
+	sysErr, ok := opErr.Err.(*os.SyscallError)
+	return ok && sysErr.Err == errno
+}
+
+const (
+	// probeHeaderSize is the size of the probe header with the sequence number
+	probeHeaderSize = 8
+	// probeAckSize is the size of the probe acknowledgement with the
+	// sequence number and the size of the acknowledged probe
+	probeAckSize = probeHeaderSize + 4
+	// warmupSeq is the sequence number of the warm-up probes
+	warmupSeq = 0
+)
@@ -19,6 +19,7 @@ package proto
 import (
 	"fmt"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/gravitational/gravity/lib/constants"
@@ -58,6 +59,70 @@ func (r CheckBandwidthRequest) Check() error {
 	return nil
 }
 
+// Check makes sure the request is correct
+func (r CheckPathMTURequest) Check() error {
+	if r.Listen == nil {
+		return trace.BadParameter("listen address should be provided: %v", r)
+	}
+	if len(r.Ping) < 1 {
+		return trace.BadParameter("at least one ping address should be provided: %v", r)
+	}
+	if r.MaxMtu == 0 {
+		return trace.BadParameter("maximum MTU should be provided: %v", r)
+	}
+	return nil
+}
+
+// Check makes sure the request is correct
+func (r CheckLatencyRequest) Check() error {
+	if r.Listen == nil {
+		return trace.BadParameter("listen address should be provided: %v", r)
+	}
+	if len(r.Ping) < 1 {
+		return trace.BadParameter("at least one ping address should be provided: %v", r)
+	}
+	if r.Count == 0 {
+		return trace.BadParameter("probe count should be provided: %v", r)
+	}
+	return nil
+}
+
+// Check makes sure the request is correct
+func (r CheckPacketLossRequest) Check() error {
+	if r.Listen == nil {
+		return trace.BadParameter("listen address should be provided: %v", r)
+	}
+	if len(r.Ping) < 1 {
+		return trace.BadParameter("at least one ping address should be provided: %v", r)
+	}
+	if r.Count == 0 {
+		return trace.BadParameter("packet count should be provided: %v", r)
+	}
+	return nil
+}
+
+// Check makes sure the request is correct
+func (r CheckVXLANRequest) Check() error {
+	if r.Listen == nil {
+		return trace.BadParameter("listen address should be provided: %v", r)
+	}
+	if len(r.Ping) < 1 {
+		return trace.BadParameter("at least one ping address should be provided: %v", r)
+	}
+	if r.Port == 0 {
+		return trace.BadParameter("VXLAN port should be provided: %v", r)
+	}
+	return nil
+}
+
+// LossPercent returns the percentage of packets that were not acknowledged
+func (r PacketLossResult) LossPercent() float64 {
+	if r.Sent == 0 {
+		return 0
+	}
+	return float64(r.Sent-r.Received) * 100 / float64(r.Sent)
+}
+
 // CheckAndSetDefaults validates the request and sets defaults.
 func (r *CheckDisksRequest) CheckAndSetDefaults() error {
 	for _, job := range r.Jobs {
@@ -108,23 +173,68 @@ func (r FioJobSpec) Flags() (flags []string) {
 	if r.Filename != "" {
 		flags = append(flags, fmt.Sprint("--filename=", r.Filename))
 	}
+	if r.BlockSize != "" {
+		flags = append(flags, fmt.Sprint("--bs=", r.BlockSize))
+	}
 	if r.Size_ != "" {
 		flags = append(flags, fmt.Sprint("--size=", r.Size_))
 	}
 	if r.Runtime != nil {
 		flags = append(flags, fmt.Sprintf("--runtime=%vs", r.Runtime.GetSeconds()))
 	}
+	if r.Direct {
+		flags = append(flags, "--direct=1")
+	}
+	if r.IoDepth != 0 {
+		flags = append(flags, fmt.Sprint("--iodepth=", r.IoDepth))
+	}
+	if len(r.Percentiles) != 0 {
+		percentiles := make([]string, 0, len(r.Percentiles))
+		for _, percentile := range r.Percentiles {
+			percentiles = append(percentiles, percentileBucket(percentile))
+		}
+		flags = append(flags, fmt.Sprint("--percentile_list=", strings.Join(percentiles, ":")))
+	}
 	return flags
 }
 
+// GetReadIOPS returns number of read iops.
+func (r FioJobResult) GetReadIOPS() float64 {
+	if r.Read == nil {
+		return 0
+	}
+	return r.Read.Iops
+}
+
 // GetWriteIOPS returns number of write iops.
 func (r FioJobResult) GetWriteIOPS() float64 {
+	if r.Write == nil {
+		return 0
+	}
 	return r.Write.Iops
 }
 
 // GetFsyncLatency returns 99th percentile of fsync latency in milliseconds.
 func (r FioJobResult) GetFsyncLatency() int64 {
-	return r.Sync.Latency.Percentile[bucket99] / 1000000
+	return r.GetFsyncLatencyPercentile(99) / 1000000
+}
+
+// GetFsyncLatencyPercentile returns the specified percentile
+// of fsync latency in nanoseconds.
+func (r FioJobResult) GetFsyncLatencyPercentile(percentile float64) int64 {
+	latency, _ := r.LookupFsyncLatencyPercentile(percentile)
+	return latency
+}
+
+// LookupFsyncLatencyPercentile returns the specified percentile
+// of fsync latency in nanoseconds.
+// Returns false if the result does not have the percentile
+func (r FioJobResult) LookupFsyncLatencyPercentile(percentile float64) (latency int64, ok bool) {
+	if r.Sync == nil || r.Sync.Latency == nil {
+		return 0, false
+	}
+	latency, ok = r.Sync.Latency.Percentile[percentileBucket(percentile)]
+	return latency, ok
 }
 
 // Address returns a text representation of this server
@@ -167,5 +277,8 @@ func DurationProto(d time.Duration) *types.Duration {
 	return types.DurationProto(d)
 }
 
-// bucket99 is the name of the fio's 99th percentile bucket.
-const bucket99 = "99.000000"
+// percentileBucket returns the name of the fio's bucket
+// for the specified percentile, e.g. "99.000000".
+func percentileBucket(percentile float64) string {
+	return fmt.Sprintf("%f", percentile)
+}
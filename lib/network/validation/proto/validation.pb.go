@@ -465,7 +465,9 @@ type ValidateOptions struct {
 	// DnsAddrs specifies the list of listen IP addresses for coredns
 	DnsAddrs []string `protobuf:"bytes,2,rep,name=dns_addrs,json=dnsAddrs,proto3" json:"dns_addrs,omitempty"`
 	// DnsPort specifies the DNS port for coredns
-	DnsPort              int32    `protobuf:"varint,3,opt,name=dns_port,json=dnsPort,proto3" json:"dns_port,omitempty"`
+	DnsPort int32 `protobuf:"varint,3,opt,name=dns_port,json=dnsPort,proto3" json:"dns_port,omitempty"`
+	// AdvertiseAddr is the advertise address of the node
+	AdvertiseAddr        string   `protobuf:"bytes,4,opt,name=advertise_addr,json=advertiseAddr,proto3" json:"advertise_addr,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -516,6 +518,13 @@ func (m *ValidateOptions) GetDnsPort() int32 {
 	return 0
 }
 
+func (m *ValidateOptions) GetAdvertiseAddr() string {
+	if m != nil {
+		return m.AdvertiseAddr
+	}
+	return ""
+}
+
 // Docker groups Docker-relevant attributes to validate
 type Docker struct {
 	// StorageDriver specifies the Docker storage driver
@@ -622,10 +631,16 @@ type FioJobSpec struct {
 	// Size is the total test file size.
 	Size_ string `protobuf:"bytes,7,opt,name=size,proto3" json:"size,omitempty"`
 	// Runtime limits the maximum test runtime.
-	Runtime              *types.Duration `protobuf:"bytes,8,opt,name=runtime,proto3" json:"runtime,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
-	XXX_unrecognized     []byte          `json:"-"`
-	XXX_sizecache        int32           `json:"-"`
+	Runtime *types.Duration `protobuf:"bytes,8,opt,name=runtime,proto3" json:"runtime,omitempty"`
+	// Direct specifies whether to use non-buffered I/O.
+	Direct bool `protobuf:"varint,9,opt,name=direct,proto3" json:"direct,omitempty"`
+	// IODepth is the number of I/O units to keep in flight.
+	IoDepth int32 `protobuf:"varint,10,opt,name=io_depth,json=ioDepth,proto3" json:"io_depth,omitempty"`
+	// Percentiles lists latency percentiles to report.
+	Percentiles          []float64 `protobuf:"fixed64,11,rep,packed,name=percentiles,proto3" json:"percentiles,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
 }
 
 func (m *FioJobSpec) Reset()         { *m = FioJobSpec{} }
@@ -708,6 +723,27 @@ func (m *FioJobSpec) GetRuntime() *types.Duration {
 	return nil
 }
 
+func (m *FioJobSpec) GetDirect() bool {
+	if m != nil {
+		return m.Direct
+	}
+	return false
+}
+
+func (m *FioJobSpec) GetIoDepth() int32 {
+	if m != nil {
+		return m.IoDepth
+	}
+	return 0
+}
+
+func (m *FioJobSpec) GetPercentiles() []float64 {
+	if m != nil {
+		return m.Percentiles
+	}
+	return nil
+}
+
 // CheckDisksResponse is the result of the disk performance test.
 type CheckDisksResponse struct {
 	// Jobs is a list of executed fio jobs.
@@ -975,262 +1011,1210 @@ func (m *FioSyncLatency) GetPercentile() map[string]int64 {
 	return nil
 }
 
-func init() {
-	proto.RegisterType((*CheckPortsRequest)(nil), "proto.CheckPortsRequest")
-	proto.RegisterType((*CheckPortsResponse)(nil), "proto.CheckPortsResponse")
-	proto.RegisterType((*CheckBandwidthRequest)(nil), "proto.CheckBandwidthRequest")
-	proto.RegisterType((*CheckBandwidthResponse)(nil), "proto.CheckBandwidthResponse")
-	proto.RegisterType((*ServerResult)(nil), "proto.ServerResult")
-	proto.RegisterType((*Addr)(nil), "proto.Addr")
-	proto.RegisterType((*ValidateRequest)(nil), "proto.ValidateRequest")
-	proto.RegisterType((*ValidateResponse)(nil), "proto.ValidateResponse")
-	proto.RegisterType((*ValidateOptions)(nil), "proto.ValidateOptions")
-	proto.RegisterType((*Docker)(nil), "proto.Docker")
-	proto.RegisterType((*CheckDisksRequest)(nil), "proto.CheckDisksRequest")
-	proto.RegisterType((*FioJobSpec)(nil), "proto.FioJobSpec")
-	proto.RegisterType((*CheckDisksResponse)(nil), "proto.CheckDisksResponse")
-	proto.RegisterType((*FioJobResult)(nil), "proto.FioJobResult")
-	proto.RegisterType((*FioReadResult)(nil), "proto.FioReadResult")
-	proto.RegisterType((*FioWriteResult)(nil), "proto.FioWriteResult")
-	proto.RegisterType((*FioSyncResult)(nil), "proto.FioSyncResult")
-	proto.RegisterType((*FioSyncLatency)(nil), "proto.FioSyncLatency")
-	proto.RegisterMapType((map[string]int64)(nil), "proto.FioSyncLatency.PercentileEntry")
+// CheckPathMTURequest describes a path MTU discovery network test
+type CheckPathMTURequest struct {
+	// Listen specifies the listen endpoint
+	Listen *Addr `protobuf:"bytes,1,opt,name=listen,proto3" json:"listen,omitempty"`
+	// Ping specifies the endpoints to discover the path MTU to
+	Ping []*Addr `protobuf:"bytes,2,rep,name=ping,proto3" json:"ping,omitempty"`
+	// Duration specifies the maximum duration for the request
+	Duration *types.Duration `protobuf:"bytes,3,opt,name=duration,proto3" json:"duration,omitempty"`
+	// MaxMTU specifies the upper bound for the MTU search
+	MaxMtu               uint32   `protobuf:"varint,4,opt,name=max_mtu,json=maxMtu,proto3" json:"max_mtu,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func init() { proto.RegisterFile("validation.proto", fileDescriptor_bfc2ab0b60b7792f) }
+func (m *CheckPathMTURequest) Reset()         { *m = CheckPathMTURequest{} }
+func (m *CheckPathMTURequest) String() string { return proto.CompactTextString(m) }
+func (*CheckPathMTURequest) ProtoMessage()    {}
+func (*CheckPathMTURequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bfc2ab0b60b7792f, []int{18}
+}
+func (m *CheckPathMTURequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CheckPathMTURequest.Unmarshal(m, b)
+}
+func (m *CheckPathMTURequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CheckPathMTURequest.Marshal(b, m, deterministic)
+}
+func (m *CheckPathMTURequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CheckPathMTURequest.Merge(m, src)
+}
+func (m *CheckPathMTURequest) XXX_Size() int {
+	return xxx_messageInfo_CheckPathMTURequest.Size(m)
+}
+func (m *CheckPathMTURequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CheckPathMTURequest.DiscardUnknown(m)
+}
 
-var fileDescriptor_bfc2ab0b60b7792f = []byte{
-	// 1047 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x56, 0xcd, 0x6e, 0x1b, 0xb7,
-	0x13, 0xc7, 0xda, 0xfa, 0x1c, 0x7f, 0xc4, 0xa6, 0xe3, 0xfc, 0x65, 0xfd, 0x9d, 0xca, 0xd8, 0xc2,
-	0x89, 0x81, 0x20, 0x72, 0xe1, 0xb4, 0x41, 0x91, 0x20, 0x87, 0xb8, 0x4e, 0x0f, 0x45, 0xda, 0x1a,
-	0x34, 0x92, 0x9e, 0x0a, 0x61, 0x57, 0x4b, 0x49, 0xb4, 0xd6, 0xe4, 0x86, 0xa4, 0xec, 0x3a, 0x4f,
-	0xd1, 0x43, 0xcf, 0x3d, 0xf6, 0x6d, 0x7a, 0xed, 0xd1, 0x0f, 0xe0, 0x87, 0x28, 0x0a, 0x0e, 0xb9,
-	0xda, 0x5d, 0xdb, 0x45, 0x6e, 0xbd, 0x68, 0x39, 0x33, 0xbf, 0xe1, 0xfc, 0x66, 0x86, 0x1c, 0x0a,
-	0xd6, 0xce, 0xa3, 0x94, 0x27, 0x91, 0xe1, 0x52, 0xf4, 0x33, 0x25, 0x8d, 0x24, 0x75, 0xfc, 0x74,
-	0x9f, 0x8e, 0xb9, 0x99, 0xcc, 0xe2, 0xfe, 0x50, 0x9e, 0xed, 0x8f, 0xe5, 0x58, 0xee, 0xa3, 0x3a,
-	0x9e, 0x8d, 0x50, 0x42, 0x01, 0x57, 0xce, 0xab, 0xfb, 0xd9, 0x58, 0xca, 0x71, 0xca, 0x0a, 0x54,
-	0x32, 0x53, 0xa5, 0x5d, 0xbb, 0x1b, 0xd1, 0x98, 0x09, 0x93, 0xc5, 0xfb, 0xf8, 0x75, 0xca, 0xf0,
-	0xd7, 0x00, 0xd6, 0xbf, 0x99, 0xb0, 0xe1, 0xf4, 0x58, 0x2a, 0xa3, 0x29, 0xfb, 0x30, 0x63, 0xda,
-	0x90, 0xcf, 0xa1, 0x91, 0x72, 0x6d, 0x98, 0xe8, 0x04, 0x3b, 0x8b, 0x7b, 0x4b, 0x07, 0x4b, 0x0e,
-	0xdd, 0x7f, 0x9d, 0x24, 0x8a, 0x7a, 0x13, 0xe9, 0x41, 0x2d, 0xe3, 0x62, 0xdc, 0x59, 0xb8, 0x0d,
-	0x41, 0x03, 0xf9, 0x0a, 0x5a, 0x39, 0x85, 0xce, 0xe2, 0x4e, 0xb0, 0xb7, 0x74, 0xb0, 0xd5, 0x77,
-	0x1c, 0xfb, 0x39, 0xc7, 0xfe, 0x91, 0x07, 0xd0, 0x39, 0x34, 0x3c, 0x05, 0x52, 0x66, 0xa4, 0x33,
-	0x29, 0x34, 0x23, 0x4f, 0x6e, 0x50, 0xda, 0xf0, 0xf1, 0x4e, 0x98, 0x3a, 0x67, 0x8a, 0x32, 0x3d,
-	0x4b, 0xcd, 0x9c, 0xda, 0xe3, 0x0a, 0xb5, 0x3b, 0xa1, 0x08, 0x08, 0x7f, 0x0b, 0x60, 0x13, 0x83,
-	0x1d, 0x46, 0x22, 0xb9, 0xe0, 0x89, 0x99, 0xdc, 0x55, 0x82, 0xe0, 0xbf, 0x2e, 0xc1, 0x73, 0x78,
-	0x70, 0x93, 0x95, 0x2f, 0xc3, 0x36, 0xb4, 0xe3, 0x5c, 0x89, 0xcc, 0x6a, 0xb4, 0x50, 0x84, 0x3f,
-	0xc3, 0x72, 0x39, 0x49, 0x42, 0xa0, 0x36, 0x94, 0x09, 0x43, 0x60, 0x9d, 0xe2, 0x9a, 0xdc, 0x87,
-	0x3a, 0x53, 0x4a, 0xaa, 0xce, 0xc2, 0x4e, 0xb0, 0xd7, 0xa6, 0x4e, 0xb0, 0xe9, 0x6a, 0xf4, 0xf4,
-	0x34, 0xab, 0xe9, 0x3a, 0x53, 0xf8, 0x25, 0xd4, 0xac, 0x4c, 0x3a, 0xd0, 0x14, 0xcc, 0x5c, 0x48,
-	0x35, 0xc5, 0x9d, 0xdb, 0x34, 0x17, 0x6d, 0xc0, 0x28, 0x49, 0xf2, 0xbd, 0x71, 0x1d, 0xfe, 0x19,
-	0xc0, 0xbd, 0xf7, 0xee, 0x88, 0xb3, 0xbc, 0xba, 0x5d, 0x68, 0x9d, 0x45, 0x82, 0x8f, 0x98, 0x36,
-	0xb8, 0xc5, 0x32, 0x9d, 0xcb, 0x76, 0xf7, 0x4c, 0xc9, 0x11, 0x4f, 0x99, 0xdf, 0x26, 0x17, 0xc9,
-	0x13, 0x58, 0x1f, 0xcd, 0xd2, 0x74, 0xa0, 0xd8, 0x87, 0x19, 0x57, 0xec, 0x8c, 0x09, 0xa3, 0x91,
-	0x6f, 0x8b, 0xae, 0x59, 0x03, 0x2d, 0xe9, 0xc9, 0x17, 0xd0, 0x94, 0x99, 0xad, 0xa6, 0xee, 0xd4,
-	0x30, 0xa5, 0x07, 0x3e, 0xa5, 0x9c, 0xcb, 0x8f, 0xce, 0x4a, 0x73, 0x18, 0xd9, 0x85, 0x46, 0x22,
-	0x87, 0x53, 0xa6, 0x3a, 0x75, 0x74, 0x58, 0xf1, 0x0e, 0x47, 0xa8, 0xa4, 0xde, 0x18, 0xbe, 0x80,
-	0xb5, 0x22, 0x1d, 0xdf, 0x96, 0x47, 0xd0, 0x18, 0x45, 0x3c, 0x65, 0x89, 0x3f, 0x9d, 0xab, 0x7d,
-	0x7f, 0xd9, 0xfa, 0xc7, 0x4a, 0xc6, 0x8c, 0x7a, 0x6b, 0x38, 0x29, 0x4a, 0xe1, 0xc3, 0x93, 0x87,
-	0x00, 0xe7, 0xbf, 0xa4, 0x91, 0x18, 0x64, 0x52, 0x19, 0xdf, 0xa9, 0x36, 0x6a, 0xec, 0x05, 0x20,
-	0xff, 0x87, 0x76, 0x22, 0xf4, 0xc0, 0x56, 0x52, 0xe3, 0x39, 0x6b, 0xd3, 0x56, 0x22, 0xb4, 0xed,
-	0x83, 0x26, 0x5b, 0x60, 0xd7, 0xce, 0x73, 0x11, 0x3d, 0x9b, 0x89, 0xd0, 0xd6, 0x2f, 0xdc, 0x87,
-	0x86, 0xe3, 0x4d, 0x76, 0x61, 0x55, 0x1b, 0xa9, 0xa2, 0x31, 0x1b, 0x24, 0x8a, 0xdb, 0x16, 0xbb,
-	0xa6, 0xad, 0x78, 0xed, 0x11, 0x2a, 0xc3, 0x77, 0x7e, 0x10, 0x1c, 0x71, 0x3d, 0x9d, 0x0f, 0x82,
-	0x5d, 0xa8, 0x9d, 0xca, 0x58, 0xfb, 0xac, 0xd6, 0x7d, 0x41, 0xbe, 0xe5, 0xf2, 0x3b, 0x19, 0x9f,
-	0x64, 0x6c, 0x48, 0xd1, 0x6c, 0x79, 0x8c, 0xb8, 0x1c, 0x64, 0x91, 0x99, 0xe4, 0x3d, 0x1b, 0x71,
-	0x79, 0x1c, 0x99, 0x49, 0xf8, 0x77, 0x00, 0x50, 0xe0, 0xed, 0x01, 0x11, 0xd1, 0x19, 0xf3, 0x14,
-	0x70, 0x6d, 0x2b, 0xa0, 0x58, 0x94, 0x0c, 0x2e, 0x14, 0x37, 0x79, 0xcf, 0xdb, 0x56, 0xf3, 0x93,
-	0x55, 0xd8, 0x0a, 0x70, 0x39, 0x60, 0x62, 0xcc, 0x05, 0xc3, 0x2c, 0xdb, 0xb4, 0xc5, 0xe5, 0x1b,
-	0x94, 0xed, 0x7d, 0x18, 0x25, 0x91, 0x89, 0xf4, 0xa5, 0x18, 0x62, 0x9f, 0x5b, 0xb4, 0x50, 0xd8,
-	0x63, 0x66, 0x0f, 0x0e, 0x46, 0xac, 0x3b, 0xcf, 0x5c, 0xb6, 0x51, 0xe3, 0x54, 0x0e, 0xa7, 0x03,
-	0xcd, 0x3f, 0xb2, 0x4e, 0xc3, 0x45, 0x45, 0xcd, 0x09, 0xff, 0xc8, 0x2c, 0x51, 0x34, 0x34, 0x1d,
-	0x51, 0xbb, 0x26, 0xcf, 0xa0, 0xa9, 0x66, 0xc2, 0xf0, 0x33, 0xd6, 0x69, 0x7d, 0xea, 0x32, 0xe7,
-	0xc8, 0xf0, 0x95, 0x1f, 0x67, 0xbe, 0xae, 0xfe, 0xc0, 0x3c, 0xae, 0x14, 0x76, 0xa3, 0x52, 0xd8,
-	0x7c, 0x42, 0x59, 0x40, 0xf8, 0x57, 0x00, 0xcb, 0x65, 0x35, 0x79, 0x04, 0xad, 0x53, 0x19, 0x0f,
-	0x8a, 0x2a, 0x1e, 0x2e, 0x5d, 0x5f, 0xf5, 0x9a, 0xa7, 0x32, 0xb6, 0x2a, 0x6a, 0x17, 0x3f, 0xd8,
-	0xfc, 0x0e, 0xa0, 0x66, 0x6b, 0x88, 0xf5, 0x5c, 0x3a, 0xb8, 0x5f, 0x44, 0xa0, 0x2c, 0x4a, 0xdc,
-	0x5e, 0x87, 0xad, 0xeb, 0xab, 0x1e, 0xa2, 0x28, 0xfe, 0x92, 0xe7, 0x50, 0x77, 0x4d, 0x70, 0x43,
-	0x60, 0xb3, 0x70, 0xc2, 0x56, 0x78, 0xaf, 0xf6, 0xf5, 0x55, 0xcf, 0xe1, 0xa8, 0xfb, 0xd8, 0x58,
-	0xf3, 0x06, 0x54, 0x62, 0x9d, 0x5c, 0x8a, 0x61, 0x39, 0x96, 0x45, 0x51, 0xfc, 0x0d, 0x9f, 0xc2,
-	0x4a, 0x85, 0x0c, 0xd9, 0x86, 0x1a, 0x97, 0x99, 0xc6, 0xa4, 0x02, 0x07, 0xb7, 0x32, 0xc5, 0xdf,
-	0xb0, 0x0f, 0xab, 0x55, 0x1a, 0x9f, 0xc0, 0xbf, 0xc5, 0xed, 0x8b, 0xf8, 0xe4, 0x25, 0x34, 0xd3,
-	0xc8, 0x30, 0x31, 0xbc, 0xf4, 0x13, 0x7d, 0xb3, 0x4a, 0xf3, 0xad, 0x33, 0x1e, 0xc2, 0xf5, 0x55,
-	0xaf, 0x91, 0x46, 0x66, 0x60, 0x47, 0x83, 0xf7, 0x08, 0xff, 0x08, 0x30, 0x7c, 0x09, 0x47, 0xde,
-	0x01, 0x64, 0x4c, 0x0d, 0x99, 0x30, 0x76, 0x52, 0xb9, 0x3e, 0xee, 0xde, 0xb9, 0x65, 0xff, 0x78,
-	0x8e, 0x7b, 0x23, 0x8c, 0xba, 0x3c, 0x5c, 0xbd, 0xbe, 0xea, 0x95, 0x9c, 0x69, 0x69, 0xdd, 0x7d,
-	0x05, 0xf7, 0x6e, 0xc0, 0xc9, 0x1a, 0x2c, 0x4e, 0xd9, 0xa5, 0xbf, 0x32, 0x76, 0x69, 0x67, 0xf8,
-	0x79, 0x94, 0xce, 0xdc, 0x65, 0x59, 0xa4, 0x4e, 0x78, 0xb1, 0xf0, 0x75, 0x70, 0xf0, 0xfb, 0x02,
-	0xc0, 0xfb, 0xf9, 0xff, 0x09, 0xf2, 0x1a, 0xa0, 0x78, 0x4b, 0x49, 0xc7, 0xd3, 0xbb, 0xf5, 0xe0,
-	0x77, 0xb7, 0xee, 0xb0, 0xf8, 0x93, 0xfa, 0x3d, 0xac, 0x56, 0xdf, 0x22, 0xb2, 0x5d, 0x06, 0xdf,
-	0x7c, 0x38, 0xbb, 0x0f, 0xff, 0xc5, 0xea, 0xb7, 0xcb, 0x19, 0xe1, 0x75, 0xa8, 0x32, 0x2a, 0x4f,
-	0x9e, 0x2a, 0xa3, 0xea, 0xdd, 0x79, 0x09, 0xad, 0x7c, 0x88, 0x92, 0x9b, 0x43, 0x3d, 0x77, 0xff,
-	0xdf, 0x2d, 0xbd, 0x73, 0x8e, 0x1b, 0xa8, 0x7f, 0xf6, 0x4f, 0x00, 0x00, 0x00, 0xff, 0xff, 0x50,
-	0x92, 0xcd, 0x81, 0x76, 0x09, 0x00, 0x00,
+var xxx_messageInfo_CheckPathMTURequest proto.InternalMessageInfo
+
+func (m *CheckPathMTURequest) GetListen() *Addr {
+	if m != nil {
+		return m.Listen
+	}
+	return nil
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
+func (m *CheckPathMTURequest) GetPing() []*Addr {
+	if m != nil {
+		return m.Ping
+	}
+	return nil
+}
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+func (m *CheckPathMTURequest) GetDuration() *types.Duration {
+	if m != nil {
+		return m.Duration
+	}
+	return nil
+}
 
-// ValidationClient is the client API for Validation service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type ValidationClient interface {
-	// CheckPorts executes a ports network test
-	CheckPorts(ctx context.Context, in *CheckPortsRequest, opts ...grpc.CallOption) (*CheckPortsResponse, error)
-	// CheckBandwidth executes a bandwidth network test
-	CheckBandwidth(ctx context.Context, in *CheckBandwidthRequest, opts ...grpc.CallOption) (*CheckBandwidthResponse, error)
-	// CheckDisks executes performance test for the specified disks
-	CheckDisks(ctx context.Context, in *CheckDisksRequest, opts ...grpc.CallOption) (*CheckDisksResponse, error)
-	// Validate validatest this node against the requirements
-	// from a manifest.
-	Validate(ctx context.Context, in *ValidateRequest, opts ...grpc.CallOption) (*ValidateResponse, error)
+func (m *CheckPathMTURequest) GetMaxMtu() uint32 {
+	if m != nil {
+		return m.MaxMtu
+	}
+	return 0
 }
 
-type validationClient struct {
-	cc *grpc.ClientConn
+// CheckPathMTUResponse describes the results of a path MTU discovery test
+type CheckPathMTUResponse struct {
+	// Results lists path MTU results for each endpoint
+	Results              []*PathMTUResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
 }
 
-func NewValidationClient(cc *grpc.ClientConn) ValidationClient {
-	return &validationClient{cc}
+func (m *CheckPathMTUResponse) Reset()         { *m = CheckPathMTUResponse{} }
+func (m *CheckPathMTUResponse) String() string { return proto.CompactTextString(m) }
+func (*CheckPathMTUResponse) ProtoMessage()    {}
+func (*CheckPathMTUResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bfc2ab0b60b7792f, []int{19}
+}
+func (m *CheckPathMTUResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CheckPathMTUResponse.Unmarshal(m, b)
+}
+func (m *CheckPathMTUResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CheckPathMTUResponse.Marshal(b, m, deterministic)
+}
+func (m *CheckPathMTUResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CheckPathMTUResponse.Merge(m, src)
+}
+func (m *CheckPathMTUResponse) XXX_Size() int {
+	return xxx_messageInfo_CheckPathMTUResponse.Size(m)
+}
+func (m *CheckPathMTUResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CheckPathMTUResponse.DiscardUnknown(m)
 }
 
-func (c *validationClient) CheckPorts(ctx context.Context, in *CheckPortsRequest, opts ...grpc.CallOption) (*CheckPortsResponse, error) {
-	out := new(CheckPortsResponse)
-	err := c.cc.Invoke(ctx, "/proto.Validation/CheckPorts", in, out, opts...)
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_CheckPathMTUResponse proto.InternalMessageInfo
+
+func (m *CheckPathMTUResponse) GetResults() []*PathMTUResult {
+	if m != nil {
+		return m.Results
 	}
-	return out, nil
+	return nil
 }
 
-func (c *validationClient) CheckBandwidth(ctx context.Context, in *CheckBandwidthRequest, opts ...grpc.CallOption) (*CheckBandwidthResponse, error) {
-	out := new(CheckBandwidthResponse)
-	err := c.cc.Invoke(ctx, "/proto.Validation/CheckBandwidth", in, out, opts...)
-	if err != nil {
-		return nil, err
+// PathMTUResult describes the path MTU discovered for a single endpoint
+type PathMTUResult struct {
+	// Server specifies the endpoint the result is for
+	Server *Addr `protobuf:"bytes,1,opt,name=server,proto3" json:"server,omitempty"`
+	// Code specifies the result, with 0 for success
+	Code int32 `protobuf:"varint,2,opt,name=code,proto3" json:"code,omitempty"`
+	// Error specifies an error message
+	Error string `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	// MTU is the largest IP packet size that reached the endpoint
+	Mtu                  uint32   `protobuf:"varint,4,opt,name=mtu,proto3" json:"mtu,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PathMTUResult) Reset()         { *m = PathMTUResult{} }
+func (m *PathMTUResult) String() string { return proto.CompactTextString(m) }
+func (*PathMTUResult) ProtoMessage()    {}
+func (*PathMTUResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bfc2ab0b60b7792f, []int{20}
+}
+func (m *PathMTUResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PathMTUResult.Unmarshal(m, b)
+}
+func (m *PathMTUResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PathMTUResult.Marshal(b, m, deterministic)
+}
+func (m *PathMTUResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PathMTUResult.Merge(m, src)
+}
+func (m *PathMTUResult) XXX_Size() int {
+	return xxx_messageInfo_PathMTUResult.Size(m)
+}
+func (m *PathMTUResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_PathMTUResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PathMTUResult proto.InternalMessageInfo
+
+func (m *PathMTUResult) GetServer() *Addr {
+	if m != nil {
+		return m.Server
 	}
-	return out, nil
+	return nil
 }
 
-func (c *validationClient) CheckDisks(ctx context.Context, in *CheckDisksRequest, opts ...grpc.CallOption) (*CheckDisksResponse, error) {
-	out := new(CheckDisksResponse)
-	err := c.cc.Invoke(ctx, "/proto.Validation/CheckDisks", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *PathMTUResult) GetCode() int32 {
+	if m != nil {
+		return m.Code
 	}
-	return out, nil
+	return 0
 }
 
-func (c *validationClient) Validate(ctx context.Context, in *ValidateRequest, opts ...grpc.CallOption) (*ValidateResponse, error) {
-	out := new(ValidateResponse)
-	err := c.cc.Invoke(ctx, "/proto.Validation/Validate", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *PathMTUResult) GetError() string {
+	if m != nil {
+		return m.Error
 	}
-	return out, nil
+	return ""
 }
 
-// ValidationServer is the server API for Validation service.
-type ValidationServer interface {
-	// CheckPorts executes a ports network test
-	CheckPorts(context.Context, *CheckPortsRequest) (*CheckPortsResponse, error)
-	// CheckBandwidth executes a bandwidth network test
-	CheckBandwidth(context.Context, *CheckBandwidthRequest) (*CheckBandwidthResponse, error)
-	// CheckDisks executes performance test for the specified disks
-	CheckDisks(context.Context, *CheckDisksRequest) (*CheckDisksResponse, error)
-	// Validate validatest this node against the requirements
-	// from a manifest.
-	Validate(context.Context, *ValidateRequest) (*ValidateResponse, error)
+func (m *PathMTUResult) GetMtu() uint32 {
+	if m != nil {
+		return m.Mtu
+	}
+	return 0
 }
 
-// UnimplementedValidationServer can be embedded to have forward compatible implementations.
-type UnimplementedValidationServer struct {
+// CheckLatencyRequest describes a network latency test
+type CheckLatencyRequest struct {
+	// Listen specifies the listen endpoint
+	Listen *Addr `protobuf:"bytes,1,opt,name=listen,proto3" json:"listen,omitempty"`
+	// Ping specifies the endpoints to measure the latency to
+	Ping []*Addr `protobuf:"bytes,2,rep,name=ping,proto3" json:"ping,omitempty"`
+	// Duration specifies the maximum duration for the request
+	Duration *types.Duration `protobuf:"bytes,3,opt,name=duration,proto3" json:"duration,omitempty"`
+	// Count specifies the number of probes to send to each endpoint
+	Count                uint32   `protobuf:"varint,4,opt,name=count,proto3" json:"count,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (*UnimplementedValidationServer) CheckPorts(ctx context.Context, req *CheckPortsRequest) (*CheckPortsResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method CheckPorts not implemented")
+func (m *CheckLatencyRequest) Reset()         { *m = CheckLatencyRequest{} }
+func (m *CheckLatencyRequest) String() string { return proto.CompactTextString(m) }
+func (*CheckLatencyRequest) ProtoMessage()    {}
+func (*CheckLatencyRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bfc2ab0b60b7792f, []int{21}
 }
-func (*UnimplementedValidationServer) CheckBandwidth(ctx context.Context, req *CheckBandwidthRequest) (*CheckBandwidthResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method CheckBandwidth not implemented")
+func (m *CheckLatencyRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CheckLatencyRequest.Unmarshal(m, b)
 }
-func (*UnimplementedValidationServer) CheckDisks(ctx context.Context, req *CheckDisksRequest) (*CheckDisksResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method CheckDisks not implemented")
+func (m *CheckLatencyRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CheckLatencyRequest.Marshal(b, m, deterministic)
 }
-func (*UnimplementedValidationServer) Validate(ctx context.Context, req *ValidateRequest) (*ValidateResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Validate not implemented")
+func (m *CheckLatencyRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CheckLatencyRequest.Merge(m, src)
 }
-
-func RegisterValidationServer(s *grpc.Server, srv ValidationServer) {
-	s.RegisterService(&_Validation_serviceDesc, srv)
+func (m *CheckLatencyRequest) XXX_Size() int {
+	return xxx_messageInfo_CheckLatencyRequest.Size(m)
+}
+func (m *CheckLatencyRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CheckLatencyRequest.DiscardUnknown(m)
 }
 
-func _Validation_CheckPorts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CheckPortsRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(ValidationServer).CheckPorts(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/proto.Validation/CheckPorts",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ValidationServer).CheckPorts(ctx, req.(*CheckPortsRequest))
+var xxx_messageInfo_CheckLatencyRequest proto.InternalMessageInfo
+
+func (m *CheckLatencyRequest) GetListen() *Addr {
+	if m != nil {
+		return m.Listen
 	}
-	return interceptor(ctx, in, info, handler)
+	return nil
 }
 
-func _Validation_CheckBandwidth_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CheckBandwidthRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(ValidationServer).CheckBandwidth(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/proto.Validation/CheckBandwidth",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ValidationServer).CheckBandwidth(ctx, req.(*CheckBandwidthRequest))
+func (m *CheckLatencyRequest) GetPing() []*Addr {
+	if m != nil {
+		return m.Ping
 	}
-	return interceptor(ctx, in, info, handler)
+	return nil
 }
 
-func _Validation_CheckDisks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CheckDisksRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(ValidationServer).CheckDisks(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/proto.Validation/CheckDisks",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ValidationServer).CheckDisks(ctx, req.(*CheckDisksRequest))
+func (m *CheckLatencyRequest) GetDuration() *types.Duration {
+	if m != nil {
+		return m.Duration
 	}
-	return interceptor(ctx, in, info, handler)
+	return nil
 }
 
-func _Validation_Validate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ValidateRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(ValidationServer).Validate(ctx, in)
+func (m *CheckLatencyRequest) GetCount() uint32 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+// CheckLatencyResponse describes the results of a network latency test
+type CheckLatencyResponse struct {
+	// Results lists latency results for each endpoint
+	Results              []*LatencyResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *CheckLatencyResponse) Reset()         { *m = CheckLatencyResponse{} }
+func (m *CheckLatencyResponse) String() string { return proto.CompactTextString(m) }
+func (*CheckLatencyResponse) ProtoMessage()    {}
+func (*CheckLatencyResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bfc2ab0b60b7792f, []int{22}
+}
+func (m *CheckLatencyResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CheckLatencyResponse.Unmarshal(m, b)
+}
+func (m *CheckLatencyResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CheckLatencyResponse.Marshal(b, m, deterministic)
+}
+func (m *CheckLatencyResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CheckLatencyResponse.Merge(m, src)
+}
+func (m *CheckLatencyResponse) XXX_Size() int {
+	return xxx_messageInfo_CheckLatencyResponse.Size(m)
+}
+func (m *CheckLatencyResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CheckLatencyResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CheckLatencyResponse proto.InternalMessageInfo
+
+func (m *CheckLatencyResponse) GetResults() []*LatencyResult {
+	if m != nil {
+		return m.Results
+	}
+	return nil
+}
+
+// LatencyResult describes the latency measured for a single endpoint
+type LatencyResult struct {
+	// Server specifies the endpoint the result is for
+	Server *Addr `protobuf:"bytes,1,opt,name=server,proto3" json:"server,omitempty"`
+	// Code specifies the result, with 0 for success
+	Code int32 `protobuf:"varint,2,opt,name=code,proto3" json:"code,omitempty"`
+	// Error specifies an error message
+	Error string `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	// RTT is the round-trip time distribution
+	Rtt *LatencyPercentiles `protobuf:"bytes,4,opt,name=rtt,proto3" json:"rtt,omitempty"`
+	// Jitter is the distribution of differences between consecutive round-trip times
+	Jitter               *LatencyPercentiles `protobuf:"bytes,5,opt,name=jitter,proto3" json:"jitter,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *LatencyResult) Reset()         { *m = LatencyResult{} }
+func (m *LatencyResult) String() string { return proto.CompactTextString(m) }
+func (*LatencyResult) ProtoMessage()    {}
+func (*LatencyResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bfc2ab0b60b7792f, []int{23}
+}
+func (m *LatencyResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LatencyResult.Unmarshal(m, b)
+}
+func (m *LatencyResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LatencyResult.Marshal(b, m, deterministic)
+}
+func (m *LatencyResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LatencyResult.Merge(m, src)
+}
+func (m *LatencyResult) XXX_Size() int {
+	return xxx_messageInfo_LatencyResult.Size(m)
+}
+func (m *LatencyResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_LatencyResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LatencyResult proto.InternalMessageInfo
+
+func (m *LatencyResult) GetServer() *Addr {
+	if m != nil {
+		return m.Server
+	}
+	return nil
+}
+
+func (m *LatencyResult) GetCode() int32 {
+	if m != nil {
+		return m.Code
+	}
+	return 0
+}
+
+func (m *LatencyResult) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *LatencyResult) GetRtt() *LatencyPercentiles {
+	if m != nil {
+		return m.Rtt
+	}
+	return nil
+}
+
+func (m *LatencyResult) GetJitter() *LatencyPercentiles {
+	if m != nil {
+		return m.Jitter
+	}
+	return nil
+}
+
+// LatencyPercentiles describes a latency distribution
+type LatencyPercentiles struct {
+	// P50 is the 50th percentile
+	P50 *types.Duration `protobuf:"bytes,1,opt,name=p50,proto3" json:"p50,omitempty"`
+	// P90 is the 90th percentile
+	P90 *types.Duration `protobuf:"bytes,2,opt,name=p90,proto3" json:"p90,omitempty"`
+	// P99 is the 99th percentile
+	P99 *types.Duration `protobuf:"bytes,3,opt,name=p99,proto3" json:"p99,omitempty"`
+	// Max is the maximum observed value
+	Max                  *types.Duration `protobuf:"bytes,4,opt,name=max,proto3" json:"max,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *LatencyPercentiles) Reset()         { *m = LatencyPercentiles{} }
+func (m *LatencyPercentiles) String() string { return proto.CompactTextString(m) }
+func (*LatencyPercentiles) ProtoMessage()    {}
+func (*LatencyPercentiles) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bfc2ab0b60b7792f, []int{24}
+}
+func (m *LatencyPercentiles) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LatencyPercentiles.Unmarshal(m, b)
+}
+func (m *LatencyPercentiles) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LatencyPercentiles.Marshal(b, m, deterministic)
+}
+func (m *LatencyPercentiles) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LatencyPercentiles.Merge(m, src)
+}
+func (m *LatencyPercentiles) XXX_Size() int {
+	return xxx_messageInfo_LatencyPercentiles.Size(m)
+}
+func (m *LatencyPercentiles) XXX_DiscardUnknown() {
+	xxx_messageInfo_LatencyPercentiles.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LatencyPercentiles proto.InternalMessageInfo
+
+func (m *LatencyPercentiles) GetP50() *types.Duration {
+	if m != nil {
+		return m.P50
+	}
+	return nil
+}
+
+func (m *LatencyPercentiles) GetP90() *types.Duration {
+	if m != nil {
+		return m.P90
+	}
+	return nil
+}
+
+func (m *LatencyPercentiles) GetP99() *types.Duration {
+	if m != nil {
+		return m.P99
+	}
+	return nil
+}
+
+func (m *LatencyPercentiles) GetMax() *types.Duration {
+	if m != nil {
+		return m.Max
+	}
+	return nil
+}
+
+// CheckPacketLossRequest describes a UDP packet loss network test
+type CheckPacketLossRequest struct {
+	// Listen specifies the listen endpoint
+	Listen *Addr `protobuf:"bytes,1,opt,name=listen,proto3" json:"listen,omitempty"`
+	// Ping specifies the endpoints to measure the packet loss to
+	Ping []*Addr `protobuf:"bytes,2,rep,name=ping,proto3" json:"ping,omitempty"`
+	// Duration specifies the maximum duration for the request
+	Duration *types.Duration `protobuf:"bytes,3,opt,name=duration,proto3" json:"duration,omitempty"`
+	// Count specifies the number of packets to send to each endpoint
+	Count                uint32   `protobuf:"varint,4,opt,name=count,proto3" json:"count,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CheckPacketLossRequest) Reset()         { *m = CheckPacketLossRequest{} }
+func (m *CheckPacketLossRequest) String() string { return proto.CompactTextString(m) }
+func (*CheckPacketLossRequest) ProtoMessage()    {}
+func (*CheckPacketLossRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bfc2ab0b60b7792f, []int{25}
+}
+func (m *CheckPacketLossRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CheckPacketLossRequest.Unmarshal(m, b)
+}
+func (m *CheckPacketLossRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CheckPacketLossRequest.Marshal(b, m, deterministic)
+}
+func (m *CheckPacketLossRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CheckPacketLossRequest.Merge(m, src)
+}
+func (m *CheckPacketLossRequest) XXX_Size() int {
+	return xxx_messageInfo_CheckPacketLossRequest.Size(m)
+}
+func (m *CheckPacketLossRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CheckPacketLossRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CheckPacketLossRequest proto.InternalMessageInfo
+
+func (m *CheckPacketLossRequest) GetListen() *Addr {
+	if m != nil {
+		return m.Listen
+	}
+	return nil
+}
+
+func (m *CheckPacketLossRequest) GetPing() []*Addr {
+	if m != nil {
+		return m.Ping
+	}
+	return nil
+}
+
+func (m *CheckPacketLossRequest) GetDuration() *types.Duration {
+	if m != nil {
+		return m.Duration
+	}
+	return nil
+}
+
+func (m *CheckPacketLossRequest) GetCount() uint32 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+// CheckPacketLossResponse describes the results of a UDP packet loss test
+type CheckPacketLossResponse struct {
+	// Results lists packet loss results for each endpoint
+	Results              []*PacketLossResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *CheckPacketLossResponse) Reset()         { *m = CheckPacketLossResponse{} }
+func (m *CheckPacketLossResponse) String() string { return proto.CompactTextString(m) }
+func (*CheckPacketLossResponse) ProtoMessage()    {}
+func (*CheckPacketLossResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bfc2ab0b60b7792f, []int{26}
+}
+func (m *CheckPacketLossResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CheckPacketLossResponse.Unmarshal(m, b)
+}
+func (m *CheckPacketLossResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CheckPacketLossResponse.Marshal(b, m, deterministic)
+}
+func (m *CheckPacketLossResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CheckPacketLossResponse.Merge(m, src)
+}
+func (m *CheckPacketLossResponse) XXX_Size() int {
+	return xxx_messageInfo_CheckPacketLossResponse.Size(m)
+}
+func (m *CheckPacketLossResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CheckPacketLossResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CheckPacketLossResponse proto.InternalMessageInfo
+
+func (m *CheckPacketLossResponse) GetResults() []*PacketLossResult {
+	if m != nil {
+		return m.Results
+	}
+	return nil
+}
+
+// PacketLossResult describes the packet loss measured for a single endpoint
+type PacketLossResult struct {
+	// Server specifies the endpoint the result is for
+	Server *Addr `protobuf:"bytes,1,opt,name=server,proto3" json:"server,omitempty"`
+	// Code specifies the result, with 0 for success
+	Code int32 `protobuf:"varint,2,opt,name=code,proto3" json:"code,omitempty"`
+	// Error specifies an error message
+	Error string `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	// Sent is the number of packets sent
+	Sent uint32 `protobuf:"varint,4,opt,name=sent,proto3" json:"sent,omitempty"`
+	// Received is the number of packets acknowledged by the endpoint
+	Received             uint32   `protobuf:"varint,5,opt,name=received,proto3" json:"received,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PacketLossResult) Reset()         { *m = PacketLossResult{} }
+func (m *PacketLossResult) String() string { return proto.CompactTextString(m) }
+func (*PacketLossResult) ProtoMessage()    {}
+func (*PacketLossResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bfc2ab0b60b7792f, []int{27}
+}
+func (m *PacketLossResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PacketLossResult.Unmarshal(m, b)
+}
+func (m *PacketLossResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PacketLossResult.Marshal(b, m, deterministic)
+}
+func (m *PacketLossResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PacketLossResult.Merge(m, src)
+}
+func (m *PacketLossResult) XXX_Size() int {
+	return xxx_messageInfo_PacketLossResult.Size(m)
+}
+func (m *PacketLossResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_PacketLossResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PacketLossResult proto.InternalMessageInfo
+
+func (m *PacketLossResult) GetServer() *Addr {
+	if m != nil {
+		return m.Server
+	}
+	return nil
+}
+
+func (m *PacketLossResult) GetCode() int32 {
+	if m != nil {
+		return m.Code
+	}
+	return 0
+}
+
+func (m *PacketLossResult) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *PacketLossResult) GetSent() uint32 {
+	if m != nil {
+		return m.Sent
+	}
+	return 0
+}
+
+func (m *PacketLossResult) GetReceived() uint32 {
+	if m != nil {
+		return m.Received
+	}
+	return 0
+}
+
+// CheckVXLANRequest describes an overlay network data path test
+type CheckVXLANRequest struct {
+	// Listen specifies the local endpoint to terminate the VXLAN tunnel on
+	Listen *Addr `protobuf:"bytes,1,opt,name=listen,proto3" json:"listen,omitempty"`
+	// Ping specifies the endpoints to send VXLAN-encapsulated traffic to
+	Ping []*Addr `protobuf:"bytes,2,rep,name=ping,proto3" json:"ping,omitempty"`
+	// Duration specifies the maximum duration for the request
+	Duration *types.Duration `protobuf:"bytes,3,opt,name=duration,proto3" json:"duration,omitempty"`
+	// Port specifies the UDP port for VXLAN traffic
+	Port                 int32    `protobuf:"varint,4,opt,name=port,proto3" json:"port,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CheckVXLANRequest) Reset()         { *m = CheckVXLANRequest{} }
+func (m *CheckVXLANRequest) String() string { return proto.CompactTextString(m) }
+func (*CheckVXLANRequest) ProtoMessage()    {}
+func (*CheckVXLANRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bfc2ab0b60b7792f, []int{28}
+}
+func (m *CheckVXLANRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CheckVXLANRequest.Unmarshal(m, b)
+}
+func (m *CheckVXLANRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CheckVXLANRequest.Marshal(b, m, deterministic)
+}
+func (m *CheckVXLANRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CheckVXLANRequest.Merge(m, src)
+}
+func (m *CheckVXLANRequest) XXX_Size() int {
+	return xxx_messageInfo_CheckVXLANRequest.Size(m)
+}
+func (m *CheckVXLANRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CheckVXLANRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CheckVXLANRequest proto.InternalMessageInfo
+
+func (m *CheckVXLANRequest) GetListen() *Addr {
+	if m != nil {
+		return m.Listen
+	}
+	return nil
+}
+
+func (m *CheckVXLANRequest) GetPing() []*Addr {
+	if m != nil {
+		return m.Ping
+	}
+	return nil
+}
+
+func (m *CheckVXLANRequest) GetDuration() *types.Duration {
+	if m != nil {
+		return m.Duration
+	}
+	return nil
+}
+
+func (m *CheckVXLANRequest) GetPort() int32 {
+	if m != nil {
+		return m.Port
+	}
+	return 0
+}
+
+// CheckVXLANResponse describes the results of an overlay network data path test
+type CheckVXLANResponse struct {
+	// Results lists data path results for each endpoint
+	Results              []*VXLANResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *CheckVXLANResponse) Reset()         { *m = CheckVXLANResponse{} }
+func (m *CheckVXLANResponse) String() string { return proto.CompactTextString(m) }
+func (*CheckVXLANResponse) ProtoMessage()    {}
+func (*CheckVXLANResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bfc2ab0b60b7792f, []int{29}
+}
+func (m *CheckVXLANResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CheckVXLANResponse.Unmarshal(m, b)
+}
+func (m *CheckVXLANResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CheckVXLANResponse.Marshal(b, m, deterministic)
+}
+func (m *CheckVXLANResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CheckVXLANResponse.Merge(m, src)
+}
+func (m *CheckVXLANResponse) XXX_Size() int {
+	return xxx_messageInfo_CheckVXLANResponse.Size(m)
+}
+func (m *CheckVXLANResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CheckVXLANResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CheckVXLANResponse proto.InternalMessageInfo
+
+func (m *CheckVXLANResponse) GetResults() []*VXLANResult {
+	if m != nil {
+		return m.Results
+	}
+	return nil
+}
+
+// VXLANResult describes the overlay network data path test result for a single endpoint
+type VXLANResult struct {
+	// Server specifies the endpoint the result is for
+	Server *Addr `protobuf:"bytes,1,opt,name=server,proto3" json:"server,omitempty"`
+	// Code specifies the result, with 0 for success
+	Code int32 `protobuf:"varint,2,opt,name=code,proto3" json:"code,omitempty"`
+	// Error specifies an error message
+	Error                string   `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *VXLANResult) Reset()         { *m = VXLANResult{} }
+func (m *VXLANResult) String() string { return proto.CompactTextString(m) }
+func (*VXLANResult) ProtoMessage()    {}
+func (*VXLANResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bfc2ab0b60b7792f, []int{30}
+}
+func (m *VXLANResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_VXLANResult.Unmarshal(m, b)
+}
+func (m *VXLANResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_VXLANResult.Marshal(b, m, deterministic)
+}
+func (m *VXLANResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VXLANResult.Merge(m, src)
+}
+func (m *VXLANResult) XXX_Size() int {
+	return xxx_messageInfo_VXLANResult.Size(m)
+}
+func (m *VXLANResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_VXLANResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_VXLANResult proto.InternalMessageInfo
+
+func (m *VXLANResult) GetServer() *Addr {
+	if m != nil {
+		return m.Server
+	}
+	return nil
+}
+
+func (m *VXLANResult) GetCode() int32 {
+	if m != nil {
+		return m.Code
+	}
+	return 0
+}
+
+func (m *VXLANResult) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*CheckPortsRequest)(nil), "proto.CheckPortsRequest")
+	proto.RegisterType((*CheckPortsResponse)(nil), "proto.CheckPortsResponse")
+	proto.RegisterType((*CheckBandwidthRequest)(nil), "proto.CheckBandwidthRequest")
+	proto.RegisterType((*CheckBandwidthResponse)(nil), "proto.CheckBandwidthResponse")
+	proto.RegisterType((*ServerResult)(nil), "proto.ServerResult")
+	proto.RegisterType((*Addr)(nil), "proto.Addr")
+	proto.RegisterType((*ValidateRequest)(nil), "proto.ValidateRequest")
+	proto.RegisterType((*ValidateResponse)(nil), "proto.ValidateResponse")
+	proto.RegisterType((*ValidateOptions)(nil), "proto.ValidateOptions")
+	proto.RegisterType((*Docker)(nil), "proto.Docker")
+	proto.RegisterType((*CheckDisksRequest)(nil), "proto.CheckDisksRequest")
+	proto.RegisterType((*FioJobSpec)(nil), "proto.FioJobSpec")
+	proto.RegisterType((*CheckDisksResponse)(nil), "proto.CheckDisksResponse")
+	proto.RegisterType((*FioJobResult)(nil), "proto.FioJobResult")
+	proto.RegisterType((*FioReadResult)(nil), "proto.FioReadResult")
+	proto.RegisterType((*FioWriteResult)(nil), "proto.FioWriteResult")
+	proto.RegisterType((*FioSyncResult)(nil), "proto.FioSyncResult")
+	proto.RegisterType((*FioSyncLatency)(nil), "proto.FioSyncLatency")
+	proto.RegisterMapType((map[string]int64)(nil), "proto.FioSyncLatency.PercentileEntry")
+	proto.RegisterType((*CheckPathMTURequest)(nil), "proto.CheckPathMTURequest")
+	proto.RegisterType((*CheckPathMTUResponse)(nil), "proto.CheckPathMTUResponse")
+	proto.RegisterType((*PathMTUResult)(nil), "proto.PathMTUResult")
+	proto.RegisterType((*CheckLatencyRequest)(nil), "proto.CheckLatencyRequest")
+	proto.RegisterType((*CheckLatencyResponse)(nil), "proto.CheckLatencyResponse")
+	proto.RegisterType((*LatencyResult)(nil), "proto.LatencyResult")
+	proto.RegisterType((*LatencyPercentiles)(nil), "proto.LatencyPercentiles")
+	proto.RegisterType((*CheckPacketLossRequest)(nil), "proto.CheckPacketLossRequest")
+	proto.RegisterType((*CheckPacketLossResponse)(nil), "proto.CheckPacketLossResponse")
+	proto.RegisterType((*PacketLossResult)(nil), "proto.PacketLossResult")
+	proto.RegisterType((*CheckVXLANRequest)(nil), "proto.CheckVXLANRequest")
+	proto.RegisterType((*CheckVXLANResponse)(nil), "proto.CheckVXLANResponse")
+	proto.RegisterType((*VXLANResult)(nil), "proto.VXLANResult")
+}
+
+func init() { proto.RegisterFile("validation.proto", fileDescriptor_bfc2ab0b60b7792f) }
+
+var fileDescriptor_bfc2ab0b60b7792f = []byte{
+	// 1481 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x57, 0xcb, 0x6e, 0x1b, 0x37,
+	0x17, 0xc6, 0x58, 0xb2, 0x2e, 0x47, 0x96, 0xe3, 0xd0, 0x8e, 0x3d, 0x56, 0x2e, 0x36, 0xe6, 0x87,
+	0x13, 0x03, 0xf9, 0x23, 0x27, 0xce, 0x9f, 0xe0, 0x4f, 0x82, 0x2c, 0xe2, 0x3a, 0x29, 0x50, 0x38,
+	0xa9, 0x41, 0x37, 0x69, 0x17, 0x2d, 0x84, 0xd1, 0x0c, 0x25, 0xd3, 0x1e, 0x0d, 0x27, 0x1c, 0xca,
+	0xb1, 0xf3, 0x12, 0x6d, 0x81, 0x3e, 0x42, 0x51, 0x14, 0xe8, 0x3b, 0x74, 0x57, 0xa0, 0x9b, 0xae,
+	0x0a, 0x74, 0xe9, 0x07, 0xf0, 0x53, 0x14, 0xbc, 0xcc, 0x4d, 0x56, 0xaa, 0x4d, 0x50, 0x78, 0x23,
+	0x91, 0xe7, 0x7c, 0xe7, 0xf0, 0xe3, 0xb9, 0x0c, 0x49, 0x98, 0x3b, 0x72, 0x03, 0xea, 0xbb, 0x82,
+	0xb2, 0xb0, 0x1d, 0x71, 0x26, 0x18, 0x9a, 0x56, 0x7f, 0xad, 0x3b, 0x7d, 0x2a, 0xf6, 0x87, 0xdd,
+	0xb6, 0xc7, 0x06, 0x1b, 0x7d, 0xd6, 0x67, 0x1b, 0x4a, 0xdc, 0x1d, 0xf6, 0xd4, 0x4c, 0x4d, 0xd4,
+	0x48, 0x5b, 0xb5, 0x6e, 0xf4, 0x19, 0xeb, 0x07, 0x24, 0x43, 0xf9, 0x43, 0x9e, 0xf3, 0xda, 0x9a,
+	0x77, 0xfb, 0x24, 0x14, 0x51, 0x77, 0x43, 0xfd, 0x6b, 0xa1, 0xf3, 0x9d, 0x05, 0x97, 0x3f, 0xd9,
+	0x27, 0xde, 0xe1, 0x2e, 0xe3, 0x22, 0xc6, 0xe4, 0xed, 0x90, 0xc4, 0x02, 0xfd, 0x07, 0x2a, 0x01,
+	0x8d, 0x05, 0x09, 0x6d, 0x6b, 0xb5, 0xb4, 0xde, 0xd8, 0x6c, 0x68, 0x74, 0xfb, 0x99, 0xef, 0x73,
+	0x6c, 0x54, 0x68, 0x05, 0xca, 0x11, 0x0d, 0xfb, 0xf6, 0xd4, 0x79, 0x88, 0x52, 0xa0, 0x07, 0x50,
+	0x4b, 0x28, 0xd8, 0xa5, 0x55, 0x6b, 0xbd, 0xb1, 0xb9, 0xdc, 0xd6, 0x1c, 0xdb, 0x09, 0xc7, 0xf6,
+	0xb6, 0x01, 0xe0, 0x14, 0xea, 0x1c, 0x00, 0xca, 0x33, 0x8a, 0x23, 0x16, 0xc6, 0x04, 0xdd, 0x1e,
+	0xa1, 0x34, 0x6f, 0xd6, 0xdb, 0x23, 0xfc, 0x88, 0x70, 0x4c, 0xe2, 0x61, 0x20, 0x52, 0x6a, 0xb7,
+	0x0a, 0xd4, 0xc6, 0x42, 0x15, 0xc0, 0xf9, 0xc1, 0x82, 0x2b, 0x6a, 0xb1, 0x2d, 0x37, 0xf4, 0xdf,
+	0x51, 0x5f, 0xec, 0x8f, 0x0b, 0x81, 0xf5, 0x6f, 0x87, 0xe0, 0x21, 0x2c, 0x8e, 0xb2, 0x32, 0x61,
+	0xb8, 0x06, 0xf5, 0x6e, 0x22, 0x54, 0xcc, 0xca, 0x38, 0x13, 0x38, 0xdf, 0xc0, 0x4c, 0x7e, 0x93,
+	0x08, 0x41, 0xd9, 0x63, 0x3e, 0x51, 0xc0, 0x69, 0xac, 0xc6, 0x68, 0x01, 0xa6, 0x09, 0xe7, 0x8c,
+	0xdb, 0x53, 0xab, 0xd6, 0x7a, 0x1d, 0xeb, 0x89, 0xdc, 0x6e, 0xac, 0x2c, 0x0d, 0xcd, 0xe2, 0x76,
+	0xb5, 0xca, 0xf9, 0x1f, 0x94, 0xe5, 0x1c, 0xd9, 0x50, 0x0d, 0x89, 0x78, 0xc7, 0xf8, 0xa1, 0xf2,
+	0x5c, 0xc7, 0xc9, 0x54, 0x2e, 0xe8, 0xfa, 0x7e, 0xe2, 0x5b, 0x8d, 0x9d, 0x3f, 0x2c, 0xb8, 0xf4,
+	0x46, 0x97, 0x38, 0x49, 0xa2, 0xdb, 0x82, 0xda, 0xc0, 0x0d, 0x69, 0x8f, 0xc4, 0x42, 0xb9, 0x98,
+	0xc1, 0xe9, 0x5c, 0x7a, 0x8f, 0x38, 0xeb, 0xd1, 0x80, 0x18, 0x37, 0xc9, 0x14, 0xdd, 0x86, 0xcb,
+	0xbd, 0x61, 0x10, 0x74, 0x38, 0x79, 0x3b, 0xa4, 0x9c, 0x0c, 0x48, 0x28, 0x62, 0xc5, 0xb7, 0x86,
+	0xe7, 0xa4, 0x02, 0xe7, 0xe4, 0xe8, 0x2e, 0x54, 0x59, 0x24, 0xa3, 0x19, 0xdb, 0x65, 0xb5, 0xa5,
+	0x45, 0xb3, 0xa5, 0x84, 0xcb, 0xe7, 0x5a, 0x8b, 0x13, 0x18, 0x5a, 0x83, 0x8a, 0xcf, 0xbc, 0x43,
+	0xc2, 0xed, 0x69, 0x65, 0xd0, 0x34, 0x06, 0xdb, 0x4a, 0x88, 0x8d, 0xd2, 0x79, 0x0c, 0x73, 0xd9,
+	0x76, 0x4c, 0x5a, 0x6e, 0x42, 0xa5, 0xe7, 0xd2, 0x80, 0xf8, 0xa6, 0x3a, 0x67, 0xdb, 0xa6, 0xd9,
+	0xda, 0xbb, 0x9c, 0x75, 0x09, 0x36, 0x5a, 0xe7, 0xdb, 0x5c, 0x2c, 0xcc, 0xfa, 0xe8, 0x3a, 0xc0,
+	0xd1, 0x71, 0xe0, 0x86, 0x9d, 0x88, 0x71, 0x61, 0x52, 0x55, 0x57, 0x12, 0xd9, 0x01, 0xe8, 0x2a,
+	0xd4, 0xfd, 0x30, 0xee, 0xc8, 0x50, 0xc6, 0xaa, 0xd0, 0xea, 0xb8, 0xe6, 0x87, 0xb1, 0x4c, 0x44,
+	0x8c, 0x96, 0x41, 0x8e, 0xb5, 0x65, 0x49, 0x59, 0x56, 0xfd, 0x30, 0x56, 0x76, 0x6b, 0x30, 0xeb,
+	0xfa, 0x47, 0x84, 0x0b, 0x1a, 0x13, 0x65, 0xad, 0xc2, 0x50, 0xc7, 0xcd, 0x54, 0x2a, 0x5d, 0x38,
+	0x1b, 0x50, 0xd1, 0xfb, 0x93, 0x06, 0xb1, 0x60, 0xdc, 0xed, 0x93, 0x8e, 0xcf, 0xa9, 0x2c, 0x05,
+	0x9d, 0xdc, 0xa6, 0x91, 0x6e, 0x2b, 0xa1, 0xf3, 0xda, 0x7c, 0x30, 0xb6, 0x69, 0x7c, 0x98, 0x7e,
+	0x30, 0xd6, 0xa0, 0x7c, 0xc0, 0xba, 0xb1, 0xd9, 0xfd, 0x65, 0x13, 0xb8, 0x17, 0x94, 0x7d, 0xc6,
+	0xba, 0x7b, 0x11, 0xf1, 0xb0, 0x52, 0x4b, 0xba, 0x3d, 0xca, 0x3a, 0x91, 0x2b, 0xf6, 0x93, 0xdc,
+	0xf6, 0x28, 0xdb, 0x75, 0xc5, 0xbe, 0xf3, 0xe7, 0x14, 0x40, 0x86, 0x97, 0x85, 0x14, 0xba, 0x03,
+	0x62, 0x28, 0xa8, 0xb1, 0x0c, 0x14, 0x27, 0xae, 0xdf, 0x79, 0xc7, 0xa9, 0x48, 0x6a, 0xa3, 0x2e,
+	0x25, 0x5f, 0x4a, 0x81, 0x0c, 0x14, 0x65, 0x1d, 0x12, 0xf6, 0x69, 0x48, 0x54, 0x30, 0xea, 0xb8,
+	0x46, 0xd9, 0x73, 0x35, 0x97, 0x7d, 0xd3, 0xf3, 0x5d, 0xe1, 0xc6, 0x27, 0xa1, 0xa7, 0x02, 0x51,
+	0xc3, 0x99, 0x40, 0x96, 0xa3, 0x2c, 0x30, 0xb5, 0xe2, 0xb4, 0xb6, 0x4c, 0xe6, 0x72, 0xd5, 0x6e,
+	0xc0, 0xbc, 0xc3, 0x4e, 0x4c, 0xdf, 0x13, 0xbb, 0xa2, 0x57, 0x55, 0x92, 0x3d, 0xfa, 0x9e, 0x48,
+	0xa2, 0x4a, 0x51, 0xd5, 0x44, 0xe5, 0x18, 0xdd, 0x87, 0x2a, 0x1f, 0x86, 0x82, 0x0e, 0x88, 0x5d,
+	0x9b, 0xd4, 0xf4, 0x09, 0x12, 0x2d, 0x42, 0xc5, 0xa7, 0x9c, 0x78, 0xc2, 0xae, 0x2b, 0x7a, 0x66,
+	0x26, 0x63, 0x46, 0x59, 0xc7, 0x27, 0x91, 0xd8, 0xb7, 0x41, 0xa7, 0x98, 0xb2, 0x6d, 0x39, 0x45,
+	0xab, 0xd0, 0x88, 0x08, 0xf7, 0x48, 0x28, 0x68, 0x40, 0x62, 0xbb, 0xb1, 0x5a, 0x5a, 0xb7, 0x70,
+	0x5e, 0xe4, 0x3c, 0x05, 0x94, 0x4f, 0x96, 0xa9, 0xd6, 0x5b, 0x85, 0x6c, 0xcd, 0x17, 0xb2, 0x95,
+	0x7c, 0x1e, 0x25, 0xc0, 0xf9, 0xcb, 0x82, 0x99, 0xbc, 0x18, 0xdd, 0x84, 0xda, 0x01, 0xeb, 0x76,
+	0xb2, 0xd4, 0x6c, 0x35, 0xce, 0x4e, 0x57, 0xaa, 0x07, 0xac, 0x2b, 0x45, 0x58, 0x0e, 0x5e, 0xc9,
+	0xa0, 0x6d, 0x42, 0x59, 0x26, 0x46, 0x25, 0xa9, 0xb1, 0xb9, 0x90, 0xad, 0x80, 0x89, 0xeb, 0x6b,
+	0x5f, 0x5b, 0xb5, 0xb3, 0xd3, 0x15, 0x85, 0xc2, 0xea, 0x17, 0x3d, 0x84, 0x69, 0x9d, 0x59, 0xfd,
+	0x05, 0xba, 0x92, 0x19, 0xa9, 0xfc, 0x1a, 0xab, 0xfa, 0xd9, 0xe9, 0x8a, 0xc6, 0x61, 0xfd, 0x27,
+	0xd7, 0x4a, 0xb3, 0x5a, 0x58, 0x6b, 0xef, 0x24, 0xf4, 0xf2, 0x6b, 0x49, 0x14, 0x56, 0xbf, 0xce,
+	0x1d, 0x68, 0x16, 0xc8, 0xa0, 0x6b, 0x50, 0xa6, 0x2c, 0x8a, 0xd5, 0xa6, 0x2c, 0x0d, 0x97, 0x73,
+	0xac, 0x7e, 0x9d, 0x36, 0xcc, 0x16, 0x69, 0x4c, 0xc0, 0xef, 0x40, 0xb3, 0xb0, 0x3e, 0x7a, 0x02,
+	0xd5, 0xc0, 0x15, 0x24, 0xf4, 0x4e, 0x6c, 0x6b, 0x74, 0x77, 0x12, 0xb6, 0xa3, 0x95, 0x5b, 0x70,
+	0x76, 0xba, 0x52, 0x09, 0x5c, 0xd1, 0x91, 0xdf, 0x25, 0x63, 0xe1, 0xfc, 0x64, 0xc1, 0x6c, 0x11,
+	0x87, 0x5e, 0x03, 0x64, 0x69, 0x36, 0x79, 0x5c, 0x1b, 0xeb, 0xb2, 0xbd, 0x9b, 0xe2, 0x9e, 0x87,
+	0x82, 0x9f, 0x6c, 0xcd, 0x9e, 0x9d, 0xae, 0xe4, 0x8c, 0x71, 0x6e, 0xdc, 0x7a, 0x0a, 0x97, 0x46,
+	0xe0, 0x68, 0x0e, 0x4a, 0x87, 0xe4, 0xc4, 0xf4, 0xa1, 0x1c, 0xca, 0x03, 0xe4, 0xc8, 0x0d, 0x86,
+	0xba, 0x03, 0x4b, 0x58, 0x4f, 0x1e, 0x4f, 0xfd, 0xdf, 0x72, 0x7e, 0xb1, 0x60, 0x5e, 0x1f, 0xdd,
+	0xae, 0xd8, 0x7f, 0xf9, 0xc5, 0xeb, 0x8b, 0x70, 0x96, 0xa2, 0x25, 0xa8, 0x0e, 0xdc, 0xe3, 0xce,
+	0x40, 0x0c, 0x55, 0x85, 0x34, 0x71, 0x65, 0xe0, 0x1e, 0xbf, 0x14, 0x43, 0xe7, 0x05, 0x2c, 0x14,
+	0xc9, 0x9a, 0xee, 0x68, 0x43, 0x95, 0xab, 0xac, 0x25, 0x0d, 0x92, 0x94, 0x54, 0x06, 0x94, 0x1d,
+	0x92, 0x80, 0x9c, 0x08, 0x9a, 0x05, 0x4d, 0xee, 0x2c, 0xb5, 0x3e, 0x78, 0x96, 0xa6, 0x47, 0xf3,
+	0xd4, 0xb8, 0xa3, 0xb9, 0x94, 0x3f, 0x9a, 0xe7, 0xa0, 0x94, 0x91, 0x97, 0x43, 0xe7, 0xe7, 0x24,
+	0xce, 0x26, 0xc7, 0x17, 0x22, 0xce, 0x0b, 0x30, 0xed, 0xb1, 0x61, 0x28, 0x0c, 0x51, 0x3d, 0x49,
+	0x83, 0x9c, 0x32, 0x9d, 0x14, 0xe4, 0x0c, 0x58, 0x08, 0xf2, 0xaf, 0x16, 0x34, 0x0b, 0xaa, 0x8f,
+	0x1d, 0xe5, 0xdb, 0x50, 0xe2, 0x42, 0x98, 0x8f, 0xc8, 0x72, 0x91, 0x4c, 0xd6, 0x13, 0x31, 0x96,
+	0x28, 0x74, 0x0f, 0x2a, 0x07, 0x54, 0x88, 0xf4, 0xa6, 0xf0, 0x0f, 0x78, 0x03, 0x74, 0x7e, 0xb7,
+	0x00, 0x9d, 0x57, 0xcb, 0x65, 0xa3, 0x07, 0x77, 0x6d, 0x6b, 0x52, 0x9c, 0x25, 0x4a, 0x81, 0x1f,
+	0xdd, 0xb5, 0xa7, 0x26, 0x83, 0x1f, 0x19, 0xf0, 0xa3, 0xc9, 0x19, 0x94, 0x28, 0x09, 0x1e, 0xb8,
+	0xc7, 0x76, 0x79, 0x22, 0x78, 0xe0, 0x1e, 0xcb, 0x36, 0x5f, 0x34, 0x9d, 0xe3, 0x1d, 0x12, 0xb1,
+	0xc3, 0xe2, 0xf8, 0x02, 0x57, 0xe0, 0x0e, 0x2c, 0x9d, 0x23, 0x6b, 0x8a, 0xf0, 0xde, 0x68, 0x11,
+	0x2e, 0xa5, 0x9d, 0x9e, 0xc3, 0x16, 0xea, 0xf0, 0x7b, 0x0b, 0xe6, 0x46, 0xb5, 0x1f, 0xbb, 0x14,
+	0xe5, 0x95, 0x82, 0xa4, 0xdb, 0x50, 0x63, 0x79, 0x43, 0xe1, 0xc4, 0x23, 0xf4, 0x88, 0xf8, 0xaa,
+	0xe6, 0x9a, 0x38, 0x9d, 0x3b, 0x3f, 0x26, 0x6f, 0xb8, 0x37, 0x5f, 0xed, 0x3c, 0x7b, 0x75, 0x21,
+	0x52, 0x81, 0xa0, 0xac, 0xee, 0xa4, 0x65, 0xbd, 0x59, 0x39, 0x76, 0xb6, 0x00, 0xe5, 0x59, 0x9a,
+	0x1c, 0xfc, 0x77, 0x34, 0x07, 0x28, 0xb9, 0xa6, 0x1b, 0x58, 0x21, 0xfc, 0x5f, 0x43, 0x23, 0x27,
+	0xff, 0xc8, 0x81, 0xdf, 0xfc, 0xad, 0x0c, 0xf0, 0x26, 0x7d, 0x8c, 0xa3, 0x67, 0x00, 0xd9, 0x43,
+	0x14, 0xd9, 0xc6, 0xf7, 0xb9, 0xd7, 0x72, 0x6b, 0x79, 0x8c, 0xc6, 0xec, 0xee, 0x25, 0xcc, 0x16,
+	0x1f, 0x72, 0xe8, 0x5a, 0x1e, 0x3c, 0xfa, 0xea, 0x6c, 0x5d, 0xff, 0x80, 0xd6, 0xb8, 0x4b, 0x18,
+	0xa9, 0xeb, 0x5c, 0x91, 0x51, 0xfe, 0x3a, 0xde, 0x5a, 0x1e, 0xa3, 0x31, 0x2e, 0x3e, 0x85, 0x99,
+	0xfc, 0xa9, 0x87, 0x5a, 0x05, 0xf2, 0x85, 0x73, 0xbb, 0x75, 0x75, 0xac, 0x6e, 0xc4, 0x51, 0x72,
+	0x25, 0x29, 0x38, 0x2a, 0x1e, 0x4c, 0xad, 0xab, 0x63, 0x75, 0xc6, 0xd1, 0x2e, 0x5c, 0x1a, 0x69,
+	0x50, 0x74, 0xbd, 0xb8, 0xf0, 0xc8, 0x57, 0xa6, 0x75, 0xe3, 0x43, 0xea, 0x91, 0x30, 0xa9, 0x52,
+	0x29, 0x86, 0x29, 0xdf, 0x22, 0xad, 0xe5, 0x31, 0x1a, 0xe3, 0xe2, 0x09, 0xd4, 0x92, 0x77, 0x1a,
+	0x1a, 0x7d, 0x38, 0x26, 0xe6, 0x4b, 0xe7, 0xe4, 0xda, 0xb8, 0x5b, 0x51, 0xf2, 0xfb, 0x7f, 0x0f,
+	0x00, 0xc2, 0x03, 0xf1, 0xea, 0xda, 0x11, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// ValidationClient is the client API for Validation service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type ValidationClient interface {
+	// CheckPorts executes a ports network test
+	CheckPorts(ctx context.Context, in *CheckPortsRequest, opts ...grpc.CallOption) (*CheckPortsResponse, error)
+	// CheckBandwidth executes a bandwidth network test
+	CheckBandwidth(ctx context.Context, in *CheckBandwidthRequest, opts ...grpc.CallOption) (*CheckBandwidthResponse, error)
+	// CheckDisks executes performance test for the specified disks
+	CheckDisks(ctx context.Context, in *CheckDisksRequest, opts ...grpc.CallOption) (*CheckDisksResponse, error)
+	// CheckPathMTU discovers the path MTU to the specified endpoints
+	CheckPathMTU(ctx context.Context, in *CheckPathMTURequest, opts ...grpc.CallOption) (*CheckPathMTUResponse, error)
+	// CheckLatency measures round-trip time and jitter to the specified endpoints
+	CheckLatency(ctx context.Context, in *CheckLatencyRequest, opts ...grpc.CallOption) (*CheckLatencyResponse, error)
+	// CheckPacketLoss measures UDP packet loss to the specified endpoints
+	CheckPacketLoss(ctx context.Context, in *CheckPacketLossRequest, opts ...grpc.CallOption) (*CheckPacketLossResponse, error)
+	// CheckVXLAN validates that VXLAN-encapsulated traffic reaches the specified endpoints
+	CheckVXLAN(ctx context.Context, in *CheckVXLANRequest, opts ...grpc.CallOption) (*CheckVXLANResponse, error)
+	// Validate validatest this node against the requirements
+	// from a manifest.
+	Validate(ctx context.Context, in *ValidateRequest, opts ...grpc.CallOption) (*ValidateResponse, error)
+}
+
+type validationClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewValidationClient(cc *grpc.ClientConn) ValidationClient {
+	return &validationClient{cc}
+}
+
+func (c *validationClient) CheckPorts(ctx context.Context, in *CheckPortsRequest, opts ...grpc.CallOption) (*CheckPortsResponse, error) {
+	out := new(CheckPortsResponse)
+	err := c.cc.Invoke(ctx, "/proto.Validation/CheckPorts", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *validationClient) CheckBandwidth(ctx context.Context, in *CheckBandwidthRequest, opts ...grpc.CallOption) (*CheckBandwidthResponse, error) {
+	out := new(CheckBandwidthResponse)
+	err := c.cc.Invoke(ctx, "/proto.Validation/CheckBandwidth", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *validationClient) CheckDisks(ctx context.Context, in *CheckDisksRequest, opts ...grpc.CallOption) (*CheckDisksResponse, error) {
+	out := new(CheckDisksResponse)
+	err := c.cc.Invoke(ctx, "/proto.Validation/CheckDisks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *validationClient) CheckPathMTU(ctx context.Context, in *CheckPathMTURequest, opts ...grpc.CallOption) (*CheckPathMTUResponse, error) {
+	out := new(CheckPathMTUResponse)
+	err := c.cc.Invoke(ctx, "/proto.Validation/CheckPathMTU", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *validationClient) CheckLatency(ctx context.Context, in *CheckLatencyRequest, opts ...grpc.CallOption) (*CheckLatencyResponse, error) {
+	out := new(CheckLatencyResponse)
+	err := c.cc.Invoke(ctx, "/proto.Validation/CheckLatency", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *validationClient) CheckPacketLoss(ctx context.Context, in *CheckPacketLossRequest, opts ...grpc.CallOption) (*CheckPacketLossResponse, error) {
+	out := new(CheckPacketLossResponse)
+	err := c.cc.Invoke(ctx, "/proto.Validation/CheckPacketLoss", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *validationClient) CheckVXLAN(ctx context.Context, in *CheckVXLANRequest, opts ...grpc.CallOption) (*CheckVXLANResponse, error) {
+	out := new(CheckVXLANResponse)
+	err := c.cc.Invoke(ctx, "/proto.Validation/CheckVXLAN", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *validationClient) Validate(ctx context.Context, in *ValidateRequest, opts ...grpc.CallOption) (*ValidateResponse, error) {
+	out := new(ValidateResponse)
+	err := c.cc.Invoke(ctx, "/proto.Validation/Validate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ValidationServer is the server API for Validation service.
+type ValidationServer interface {
+	// CheckPorts executes a ports network test
+	CheckPorts(context.Context, *CheckPortsRequest) (*CheckPortsResponse, error)
+	// CheckBandwidth executes a bandwidth network test
+	CheckBandwidth(context.Context, *CheckBandwidthRequest) (*CheckBandwidthResponse, error)
+	// CheckDisks executes performance test for the specified disks
+	CheckDisks(context.Context, *CheckDisksRequest) (*CheckDisksResponse, error)
+	// CheckPathMTU discovers the path MTU to the specified endpoints
+	CheckPathMTU(context.Context, *CheckPathMTURequest) (*CheckPathMTUResponse, error)
+	// CheckLatency measures round-trip time and jitter to the specified endpoints
+	CheckLatency(context.Context, *CheckLatencyRequest) (*CheckLatencyResponse, error)
+	// CheckPacketLoss measures UDP packet loss to the specified endpoints
+	CheckPacketLoss(context.Context, *CheckPacketLossRequest) (*CheckPacketLossResponse, error)
+	// CheckVXLAN validates that VXLAN-encapsulated traffic reaches the specified endpoints
+	CheckVXLAN(context.Context, *CheckVXLANRequest) (*CheckVXLANResponse, error)
+	// Validate validatest this node against the requirements
+	// from a manifest.
+	Validate(context.Context, *ValidateRequest) (*ValidateResponse, error)
+}
+
+// UnimplementedValidationServer can be embedded to have forward compatible implementations.
+type UnimplementedValidationServer struct {
+}
+
+func (*UnimplementedValidationServer) CheckPorts(ctx context.Context, req *CheckPortsRequest) (*CheckPortsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckPorts not implemented")
+}
+func (*UnimplementedValidationServer) CheckBandwidth(ctx context.Context, req *CheckBandwidthRequest) (*CheckBandwidthResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckBandwidth not implemented")
+}
+func (*UnimplementedValidationServer) CheckDisks(ctx context.Context, req *CheckDisksRequest) (*CheckDisksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckDisks not implemented")
+}
+func (*UnimplementedValidationServer) CheckPathMTU(ctx context.Context, req *CheckPathMTURequest) (*CheckPathMTUResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckPathMTU not implemented")
+}
+func (*UnimplementedValidationServer) CheckLatency(ctx context.Context, req *CheckLatencyRequest) (*CheckLatencyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckLatency not implemented")
+}
+func (*UnimplementedValidationServer) CheckPacketLoss(ctx context.Context, req *CheckPacketLossRequest) (*CheckPacketLossResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckPacketLoss not implemented")
+}
+func (*UnimplementedValidationServer) CheckVXLAN(ctx context.Context, req *CheckVXLANRequest) (*CheckVXLANResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckVXLAN not implemented")
+}
+func (*UnimplementedValidationServer) Validate(ctx context.Context, req *ValidateRequest) (*ValidateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Validate not implemented")
+}
+
+func RegisterValidationServer(s *grpc.Server, srv ValidationServer) {
+	s.RegisterService(&_Validation_serviceDesc, srv)
+}
+
+func _Validation_CheckPorts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckPortsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ValidationServer).CheckPorts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Validation/CheckPorts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ValidationServer).CheckPorts(ctx, req.(*CheckPortsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Validation_CheckBandwidth_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckBandwidthRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ValidationServer).CheckBandwidth(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Validation/CheckBandwidth",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ValidationServer).CheckBandwidth(ctx, req.(*CheckBandwidthRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Validation_CheckDisks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckDisksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ValidationServer).CheckDisks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Validation/CheckDisks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ValidationServer).CheckDisks(ctx, req.(*CheckDisksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Validation_CheckPathMTU_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckPathMTURequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ValidationServer).CheckPathMTU(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Validation/CheckPathMTU",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ValidationServer).CheckPathMTU(ctx, req.(*CheckPathMTURequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Validation_CheckLatency_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckLatencyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ValidationServer).CheckLatency(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Validation/CheckLatency",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ValidationServer).CheckLatency(ctx, req.(*CheckLatencyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Validation_CheckPacketLoss_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckPacketLossRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ValidationServer).CheckPacketLoss(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Validation/CheckPacketLoss",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ValidationServer).CheckPacketLoss(ctx, req.(*CheckPacketLossRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Validation_CheckVXLAN_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckVXLANRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ValidationServer).CheckVXLAN(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Validation/CheckVXLAN",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ValidationServer).CheckVXLAN(ctx, req.(*CheckVXLANRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Validation_Validate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ValidateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ValidationServer).Validate(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
@@ -1258,6 +2242,22 @@ var _Validation_serviceDesc = grpc.ServiceDesc{
 			MethodName: "CheckDisks",
 			Handler:    _Validation_CheckDisks_Handler,
 		},
+		{
+			MethodName: "CheckPathMTU",
+			Handler:    _Validation_CheckPathMTU_Handler,
+		},
+		{
+			MethodName: "CheckLatency",
+			Handler:    _Validation_CheckLatency_Handler,
+		},
+		{
+			MethodName: "CheckPacketLoss",
+			Handler:    _Validation_CheckPacketLoss_Handler,
+		},
+		{
+			MethodName: "CheckVXLAN",
+			Handler:    _Validation_CheckVXLAN_Handler,
+		},
 		{
 			MethodName: "Validate",
 			Handler:    _Validation_Validate_Handler,
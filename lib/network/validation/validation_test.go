@@ -17,11 +17,15 @@ limitations under the License.
 package validation
 
 import (
+	"context"
+	"net"
 	"sort"
 	"testing"
+	"time"
 
 	pb "github.com/gravitational/gravity/lib/network/validation/proto"
 
+	"github.com/sirupsen/logrus"
 	"gopkg.in/check.v1"
 )
 
@@ -61,6 +65,83 @@ func (r *ValidationSuite) TestComputesDiff(c *check.C) {
 	}
 }
 
+func (r *ValidationSuite) TestDiscoversPathMTU(c *check.C) {
+	addr := &pb.Addr{Network: "udp", Addr: freeUDPAddr(c)}
+	resp, err := NewServer(logrus.StandardLogger()).CheckPathMTU(context.TODO(), &pb.CheckPathMTURequest{
+		Listen:   addr,
+		Ping:     []*pb.Addr{addr},
+		Duration: pb.DurationProto(5 * time.Second),
+		MaxMtu:   1500,
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(resp.Results, check.HasLen, 1)
+	c.Assert(resp.Results[0].Error, check.Equals, "")
+	c.Assert(resp.Results[0].Mtu, check.Equals, uint32(1500))
+}
+
+func (r *ValidationSuite) TestMeasuresLatencyAndPacketLoss(c *check.C) {
+	addr := &pb.Addr{Network: "udp", Addr: freeUDPAddr(c)}
+	server := NewServer(logrus.StandardLogger())
+	latency, err := server.CheckLatency(context.TODO(), &pb.CheckLatencyRequest{
+		Listen:   addr,
+		Ping:     []*pb.Addr{addr},
+		Duration: pb.DurationProto(time.Second),
+		Count:    10,
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(latency.Results, check.HasLen, 1)
+	c.Assert(latency.Results[0].Error, check.Equals, "")
+	c.Assert(latency.Results[0].Rtt, check.NotNil)
+	c.Assert(latency.Results[0].Jitter, check.NotNil)
+
+	loss, err := server.CheckPacketLoss(context.TODO(), &pb.CheckPacketLossRequest{
+		Listen:   addr,
+		Ping:     []*pb.Addr{addr},
+		Duration: pb.DurationProto(time.Second),
+		Count:    10,
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(loss.Results, check.HasLen, 1)
+	c.Assert(loss.Results[0].Sent, check.Equals, uint32(10))
+	c.Assert(loss.Results[0].Received, check.Equals, uint32(10))
+	c.Assert(loss.Results[0].LossPercent(), check.Equals, float64(0))
+}
+
+func (r *ValidationSuite) TestComputesPercentiles(c *check.C) {
+	var values []time.Duration
+	for i := 100; i > 0; i-- {
+		values = append(values, time.Duration(i)*time.Millisecond)
+	}
+	c.Assert(percentiles(values), check.DeepEquals, &pb.LatencyPercentiles{
+		P50: pb.DurationProto(50 * time.Millisecond),
+		P90: pb.DurationProto(90 * time.Millisecond),
+		P99: pb.DurationProto(99 * time.Millisecond),
+		Max: pb.DurationProto(100 * time.Millisecond),
+	})
+	stats := probeStats{rtts: []time.Duration{time.Millisecond, 3 * time.Millisecond, 2 * time.Millisecond}}
+	c.Assert(stats.jitter(), check.DeepEquals, []time.Duration{2 * time.Millisecond, time.Millisecond})
+}
+
+func (r *ValidationSuite) TestComputesOverlayAddress(c *check.C) {
+	ip, err := overlayIP("10.0.3.4")
+	c.Assert(err, check.IsNil)
+	c.Assert(ip, check.Equals, "198.18.3.4")
+
+	ip, err = overlayIP("192.168.1.20:3012")
+	c.Assert(err, check.IsNil)
+	c.Assert(ip, check.Equals, "198.18.1.20")
+
+	_, err = overlayIP("fd00::1")
+	c.Assert(err, check.NotNil)
+}
+
+func freeUDPAddr(c *check.C) string {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	c.Assert(err, check.IsNil)
+	defer conn.Close()
+	return conn.LocalAddr().String()
+}
+
 func sorted(servers []*pb.Addr) []*pb.Addr {
 	sort.Sort(byIPPort(servers))
 	return servers
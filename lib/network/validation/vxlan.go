@@ -0,0 +1,169 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+
+	"github.com/gravitational/gravity/lib/defaults"
+	pb "github.com/gravitational/gravity/lib/network/validation/proto"
+	"github.com/gravitational/gravity/lib/utils"
+
+	"github.com/gravitational/trace"
+	"golang.org/x/net/context"
+)
+
+// CheckVXLAN sets up a temporary VXLAN interface peered with the servers
+// specified in the request and verifies that the traffic sent through it
+// reaches them.
+// The interface is removed when the test completes
+func (r *Server) CheckVXLAN(ctx context.Context, req *pb.CheckVXLANRequest) (*pb.CheckVXLANResponse, error) {
+	if err := req.Check(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	duration, err := pb.DurationFromProto(req.Duration)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	local, err := overlayIP(req.Listen.Addr)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	peers := make([]string, 0, len(req.Ping))
+	for _, server := range req.Ping {
+		peer, err := overlayIP(server.Addr)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		if peer == local {
+			return nil, trace.BadParameter("servers %v and %v map to the same overlay address %v",
+				req.Listen.Addr, server.Addr, peer)
+		}
+		peers = append(peers, peer)
+	}
+
+	err = r.setupVXLAN(ctx, req, local)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	defer func() {
+		if err := r.teardownVXLAN(context.Background()); err != nil {
+			r.Warnf("Failed to remove VXLAN interface: %v.", trace.DebugReport(err))
+		}
+	}()
+
+	results := make([]*pb.VXLANResult, len(req.Ping))
+	addr := net.JoinHostPort(local, strconv.Itoa(defaults.NetworkProbePort))
+	err = withProbeResponder(ctx, addr, duration, func(ctx context.Context) {
+		var wg sync.WaitGroup
+		for i, server := range req.Ping {
+			wg.Add(1)
+			go func(i int, server *pb.Addr, peer string) {
+				defer wg.Done()
+				results[i] = checkVXLAN(ctx, req, server, peer)
+			}(i, server, peers[i])
+		}
+		wg.Wait()
+	})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	return &pb.CheckVXLANResponse{Results: results}, nil
+}
+
+func checkVXLAN(ctx context.Context, req *pb.CheckVXLANRequest, server *pb.Addr, peer string) *pb.VXLANResult {
+	prober, err := dialProber(net.JoinHostPort(peer, strconv.Itoa(defaults.NetworkProbePort)))
+	if err == nil {
+		defer prober.Close()
+		err = prober.waitForResponder(ctx)
+	}
+	if err != nil {
+		return &pb.VXLANResult{
+			Server: server,
+			Code:   1,
+			Error: fmt.Sprintf("VXLAN traffic from %v to %v on UDP port %v is blocked: %v",
+				req.Listen.Addr, server.Addr, req.Port, err),
+		}
+	}
+	return &pb.VXLANResult{Server: server}
+}
+
+// setupVXLAN creates the test VXLAN interface with the specified overlay
+// address and adds forwarding entries for each peer
+func (r *Server) setupVXLAN(ctx context.Context, req *pb.CheckVXLANRequest, local string) error {
+	// remove the interface left over from an interrupted test
+	r.teardownVXLAN(ctx)
+	commands := [][]string{
+		{"ip", "link", "add", defaults.VXLANTestInterface, "type", "vxlan",
+			"id", strconv.Itoa(defaults.VXLANTestVNI),
+			"dstport", strconv.Itoa(int(req.Port)),
+			"local", req.Listen.Addr, "nolearning"},
+		{"ip", "addr", "add", fmt.Sprintf("%v/%v", local, overlayPrefixLength),
+			"dev", defaults.VXLANTestInterface},
+		{"ip", "link", "set", defaults.VXLANTestInterface, "up"},
+	}
+	for _, server := range req.Ping {
+		// with learning disabled, broadcasts (and thus ARP requests)
+		// are replicated to all peers with the all-zeros entries
+		commands = append(commands, []string{"bridge", "fdb", "append",
+			"00:00:00:00:00:00", "dev", defaults.VXLANTestInterface, "dst", server.Addr})
+	}
+	for _, command := range commands {
+		out, err := utils.RunCommand(ctx, r.FieldLogger, command...)
+		if err != nil {
+			r.teardownVXLAN(ctx)
+			return trace.Wrap(err, "failed to set up VXLAN interface: %s", out)
+		}
+	}
+	return nil
+}
+
+// teardownVXLAN removes the test VXLAN interface
+func (r *Server) teardownVXLAN(ctx context.Context) error {
+	out, err := utils.RunCommand(ctx, r.FieldLogger, "ip", "link", "del", defaults.VXLANTestInterface)
+	if err != nil {
+		return trace.Wrap(err, "failed to remove VXLAN interface: %s", out)
+	}
+	return nil
+}
+
+// overlayIP returns the address of the server with the specified address
+// on the test overlay network.
+// The address is derived from the last two octets of the server address
+// so that every server can compute addresses of its peers
+func overlayIP(addr string) (string, error) {
+	host, _ := utils.SplitHostPort(addr, "")
+	ip := net.ParseIP(host).To4()
+	if ip == nil {
+		return "", trace.BadParameter("expected IPv4 address, got %q", addr)
+	}
+	_, subnet, err := net.ParseCIDR(defaults.VXLANTestSubnet)
+	if err != nil {
+		return "", trace.Wrap(err)
+	}
+	overlay := subnet.IP.To4()
+	return net.IPv4(overlay[0], overlay[1], ip[2], ip[3]).String(), nil
+}
+
+// overlayPrefixLength is the prefix length of the test overlay network
+const overlayPrefixLength = 16
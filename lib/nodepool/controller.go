@@ -0,0 +1,308 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package nodepool implements the controller that converges the cluster
+// on the node pools configured for it.
+//
+// A node pool lists candidate hosts and the number of them that should be
+// cluster members. The controller expands the cluster by running the join
+// instructions on a candidate host over SSH through the cluster's proxy and
+// shrinks it by removing pool nodes. Progress is reported by the regular
+// expand and shrink operations.
+//
+// The candidate hosts need to be reachable from the cluster and accept SSH
+// sessions authenticated with the cluster's certificate authority.
+package nodepool
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gravitational/gravity/lib/defaults"
+	kubeutils "github.com/gravitational/gravity/lib/kubernetes"
+	"github.com/gravitational/gravity/lib/ops"
+	"github.com/gravitational/gravity/lib/schema"
+	"github.com/gravitational/gravity/lib/storage"
+
+	"github.com/gravitational/trace"
+	"github.com/jonboulle/clockwork"
+	"github.com/sirupsen/logrus"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Operator is the subset of the cluster operator used by the controller
+type Operator interface {
+	// GetLocalSite returns the local cluster
+	GetLocalSite() (*ops.Site, error)
+	// GetNodePools returns the list of configured node pools
+	GetNodePools(ops.SiteKey) ([]storage.NodePool, error)
+	// GetExpandToken returns the cluster's expand token
+	GetExpandToken(ops.SiteKey) (*storage.ProvisioningToken, error)
+	// GetSiteInstructions returns the join instructions for the specified token
+	GetSiteInstructions(token string, serverProfile string, params url.Values) (string, error)
+	// CreateSiteShrinkOperation starts the operation to remove a node from the cluster
+	CreateSiteShrinkOperation(context.Context, ops.CreateSiteShrinkOperationRequest) (*ops.SiteOperationKey, error)
+}
+
+// Executor executes scripts on remote hosts
+type Executor interface {
+	// ExecuteScript executes the script on the host with the specified SSH address.
+	// The script is not logged as it contains the join token
+	ExecuteScript(ctx context.Context, domainName, nodeAddr string, script io.Reader, out io.Writer) error
+}
+
+// Config is the node pool controller configuration
+type Config struct {
+	// Operator is the cluster operator
+	Operator Operator
+	// Executor executes join instructions on candidate hosts
+	Executor Executor
+	// Client is an optional Kubernetes client used to label pool nodes
+	Client kubernetes.Interface
+	// Interval is the reconcile interval
+	Interval time.Duration
+	// JoinTimeout specifies how long a candidate host is given to join
+	// the cluster before the next host is tried
+	JoinTimeout time.Duration
+	// Clock is used to track join attempts
+	Clock clockwork.Clock
+	// FieldLogger is used for logging
+	logrus.FieldLogger
+}
+
+// CheckAndSetDefaults validates the configuration and sets defaults
+func (c *Config) CheckAndSetDefaults() error {
+	if c.Operator == nil {
+		return trace.BadParameter("missing Operator")
+	}
+	if c.Executor == nil {
+		return trace.BadParameter("missing Executor")
+	}
+	if c.Interval == 0 {
+		c.Interval = defaults.NodePoolReconcileInterval
+	}
+	if c.JoinTimeout == 0 {
+		c.JoinTimeout = defaults.NodePoolJoinTimeout
+	}
+	if c.Clock == nil {
+		c.Clock = clockwork.NewRealClock()
+	}
+	if c.FieldLogger == nil {
+		c.FieldLogger = logrus.WithField(trace.Component, "nodepool")
+	}
+	return nil
+}
+
+// New returns a new node pool controller
+func New(config Config) (*Controller, error) {
+	if err := config.CheckAndSetDefaults(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return &Controller{
+		Config:   config,
+		attempts: make(map[string]time.Time),
+	}, nil
+}
+
+// Controller converges the cluster on the configured node pools
+type Controller struct {
+	// Config is the controller configuration
+	Config
+	// attempts maps the address of a candidate host to the time
+	// its join was last attempted
+	attempts map[string]time.Time
+}
+
+// Run reconciles node pools periodically until the context is cancelled
+func (c *Controller) Run(ctx context.Context) {
+	c.Info("Starting node pool controller.")
+	ticker := c.Clock.NewTicker(c.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.Chan():
+			if err := c.Reconcile(ctx); err != nil {
+				c.WithError(err).Warn("Failed to reconcile node pools.")
+			}
+		case <-ctx.Done():
+			c.Info("Stopping node pool controller.")
+			return
+		}
+	}
+}
+
+// Reconcile starts at most one operation to bring the cluster closer
+// to the configured node pools.
+// Nothing is done while another cluster operation is in progress
+func (c *Controller) Reconcile(ctx context.Context) error {
+	cluster, err := c.Operator.GetLocalSite()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	pools, err := c.Operator.GetNodePools(cluster.Key())
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	var errors []error
+	for _, pool := range pools {
+		if err := c.labelNodes(ctx, *cluster, pool); err != nil {
+			errors = append(errors, err)
+		}
+	}
+	if cluster.State != ops.SiteStateActive {
+		c.WithField("state", cluster.State).Debug("Cluster is not active, will retry.")
+		return trace.NewAggregate(errors...)
+	}
+	if c.hasPendingJoin(*cluster) {
+		c.Debug("Waiting for the host to join the cluster.")
+		return trace.NewAggregate(errors...)
+	}
+	for _, pool := range pools {
+		started, err := c.reconcilePool(ctx, *cluster, pool)
+		if err != nil {
+			errors = append(errors, trace.Wrap(err, "failed to reconcile node pool %v", pool.GetName()))
+			continue
+		}
+		if started {
+			break
+		}
+	}
+	return trace.NewAggregate(errors...)
+}
+
+// reconcilePool starts the operation to converge the cluster on the
+// specified node pool if required.
+// Returns true if an operation has been started
+func (c *Controller) reconcilePool(ctx context.Context, cluster ops.Site, pool storage.NodePool) (started bool, err error) {
+	members, candidates := c.partition(cluster, pool)
+	logger := c.WithFields(logrus.Fields{
+		"pool":    pool.GetName(),
+		"members": len(members),
+		"count":   pool.GetCount(),
+	})
+	switch {
+	case len(members) < pool.GetCount():
+		if len(candidates) == 0 {
+			logger.Warn("No candidate hosts left to join.")
+			return false, nil
+		}
+		host := candidates[0]
+		logger.WithField("host", host.Addr).Info("Joining host to the cluster.")
+		return true, trace.Wrap(c.join(ctx, cluster, host))
+	case len(members) > pool.GetCount():
+		server := members[len(members)-1]
+		logger.WithField("node", server.Hostname).Info("Removing node from the cluster.")
+		_, err := c.Operator.CreateSiteShrinkOperation(ctx, ops.CreateSiteShrinkOperationRequest{
+			AccountID:  cluster.AccountID,
+			SiteDomain: cluster.Domain,
+			Servers:    []string{server.Hostname},
+		})
+		return true, trace.Wrap(err)
+	}
+	return false, nil
+}
+
+// partition returns the cluster servers that belong to the specified pool
+// and the pool hosts that are not cluster members yet.
+// Candidates that have not been attempted recently go first
+func (c *Controller) partition(cluster ops.Site, pool storage.NodePool) (members []storage.Server, candidates []storage.NodePoolHost) {
+	for _, host := range pool.GetHosts() {
+		server, err := cluster.ClusterState.FindServerByIP(host.AdvertiseIP())
+		if err == nil {
+			members = append(members, *server)
+			continue
+		}
+		candidates = append(candidates, host)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return c.attempts[candidates[i].Addr].Before(c.attempts[candidates[j].Addr])
+	})
+	return members, candidates
+}
+
+// hasPendingJoin returns true if a host has recently been asked to join
+// the cluster but is not a cluster member yet
+func (c *Controller) hasPendingJoin(cluster ops.Site) bool {
+	for addr, attempted := range c.attempts {
+		host := storage.NodePoolHost{Addr: addr}
+		if _, err := cluster.ClusterState.FindServerByIP(host.AdvertiseIP()); err == nil {
+			delete(c.attempts, addr)
+			continue
+		}
+		if c.Clock.Now().Sub(attempted) < c.JoinTimeout {
+			return true
+		}
+	}
+	return false
+}
+
+// join runs the join instructions on the specified host.
+// The agent is started in the background and creates the expand operation
+func (c *Controller) join(ctx context.Context, cluster ops.Site, host storage.NodePoolHost) error {
+	c.attempts[host.Addr] = c.Clock.Now()
+	token, err := c.Operator.GetExpandToken(cluster.Key())
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	instructions, err := c.Operator.GetSiteInstructions(token.Token, host.Role, url.Values{
+		schema.AdvertiseAddr: []string{host.AdvertiseIP()},
+		"bg":                 []string{"true"},
+	})
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	var out bytes.Buffer
+	err = c.Executor.ExecuteScript(ctx, cluster.Domain, host.Addr, strings.NewReader(instructions), &out)
+	if err != nil {
+		return trace.Wrap(err, "failed to run join instructions on %v: %s", host.Addr, out.Bytes())
+	}
+	return nil
+}
+
+// labelNodes applies the configured labels to the nodes of the specified pool
+func (c *Controller) labelNodes(ctx context.Context, cluster ops.Site, pool storage.NodePool) error {
+	if c.Client == nil {
+		return nil
+	}
+	var errors []error
+	for _, host := range pool.GetHosts() {
+		server, err := cluster.ClusterState.FindServerByIP(host.AdvertiseIP())
+		if err != nil {
+			continue
+		}
+		err = kubeutils.UpdateLabels(ctx, c.Client.CoreV1().Nodes(), server.KubeNodeID(),
+			nodeLabels(pool, host))
+		if err != nil {
+			errors = append(errors, trace.Wrap(err, "failed to label node %v", server.Hostname))
+		}
+	}
+	return trace.NewAggregate(errors...)
+}
+
+// nodeLabels returns the Kubernetes labels for the node provisioned from
+// the specified pool host
+func nodeLabels(pool storage.NodePool, host storage.NodePoolHost) map[string]string {
+	labels := map[string]string{defaults.NodePoolLabel: pool.GetName()}
+	for key, value := range host.Labels {
+		labels[key] = value
+	}
+	return labels
+}
@@ -0,0 +1,171 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodepool
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gravitational/gravity/lib/ops"
+	"github.com/gravitational/gravity/lib/storage"
+
+	"github.com/jonboulle/clockwork"
+	"gopkg.in/check.v1"
+)
+
+func TestNodePool(t *testing.T) { check.TestingT(t) }
+
+type ControllerSuite struct{}
+
+var _ = check.Suite(&ControllerSuite{})
+
+func (s *ControllerSuite) TestJoinsCandidateHost(c *check.C) {
+	operator := newMockOperator(newPool(2,
+		"192.168.1.2:22", "192.168.1.3:22", "192.168.1.4:22"),
+		storage.Server{Hostname: "node-2", AdvertiseIP: "192.168.1.2"})
+	executor := &mockExecutor{}
+	clock := clockwork.NewFakeClock()
+	controller := newController(c, operator, executor, clock)
+	ctx := context.TODO()
+
+	c.Assert(controller.Reconcile(ctx), check.IsNil)
+	c.Assert(executor.commands, check.HasLen, 1)
+	c.Assert(executor.commands[0].addr, check.Equals, "192.168.1.3:22")
+	c.Assert(executor.commands[0].script, check.Equals,
+		"join --token=token --role=worker --advertise-addr=192.168.1.3")
+
+	// No other host is joined while the first one is joining
+	c.Assert(controller.Reconcile(ctx), check.IsNil)
+	c.Assert(executor.commands, check.HasLen, 1)
+
+	// The next host is tried after the join timeout
+	clock.Advance(time.Hour)
+	c.Assert(controller.Reconcile(ctx), check.IsNil)
+	c.Assert(executor.commands, check.HasLen, 2)
+	c.Assert(executor.commands[1].addr, check.Equals, "192.168.1.4:22")
+}
+
+func (s *ControllerSuite) TestShrinksPool(c *check.C) {
+	operator := newMockOperator(newPool(1, "192.168.1.2:22", "192.168.1.3:22"),
+		storage.Server{Hostname: "node-2", AdvertiseIP: "192.168.1.2"},
+		storage.Server{Hostname: "node-3", AdvertiseIP: "192.168.1.3"})
+	executor := &mockExecutor{}
+	controller := newController(c, operator, executor, clockwork.NewFakeClock())
+
+	c.Assert(controller.Reconcile(context.TODO()), check.IsNil)
+	c.Assert(operator.shrinks, check.DeepEquals, []string{"node-3"})
+	c.Assert(executor.commands, check.HasLen, 0)
+}
+
+func (s *ControllerSuite) TestWaitsForActiveCluster(c *check.C) {
+	operator := newMockOperator(newPool(1, "192.168.1.2:22"))
+	operator.site.State = ops.SiteStateExpanding
+	executor := &mockExecutor{}
+	controller := newController(c, operator, executor, clockwork.NewFakeClock())
+
+	c.Assert(controller.Reconcile(context.TODO()), check.IsNil)
+	c.Assert(executor.commands, check.HasLen, 0)
+}
+
+func newController(c *check.C, operator Operator, executor Executor, clock clockwork.Clock) *Controller {
+	controller, err := New(Config{
+		Operator: operator,
+		Executor: executor,
+		Clock:    clock,
+	})
+	c.Assert(err, check.IsNil)
+	return controller
+}
+
+func newPool(count int, addrs ...string) storage.NodePool {
+	var hosts []storage.NodePoolHost
+	for _, addr := range addrs {
+		hosts = append(hosts, storage.NodePoolHost{Addr: addr, Role: "worker"})
+	}
+	return storage.NewNodePool("workers", storage.NodePoolSpecV1{
+		Hosts: hosts,
+		Count: count,
+	})
+}
+
+func newMockOperator(pool storage.NodePool, servers ...storage.Server) *mockOperator {
+	return &mockOperator{
+		site: ops.Site{
+			AccountID: "1",
+			Domain:    "example.com",
+			State:     ops.SiteStateActive,
+			ClusterState: storage.ClusterState{
+				Servers: servers,
+			},
+		},
+		pools: []storage.NodePool{pool},
+	}
+}
+
+type mockOperator struct {
+	site    ops.Site
+	pools   []storage.NodePool
+	shrinks []string
+}
+
+func (o *mockOperator) GetLocalSite() (*ops.Site, error) {
+	return &o.site, nil
+}
+
+func (o *mockOperator) GetNodePools(ops.SiteKey) ([]storage.NodePool, error) {
+	return o.pools, nil
+}
+
+func (o *mockOperator) GetExpandToken(ops.SiteKey) (*storage.ProvisioningToken, error) {
+	return &storage.ProvisioningToken{Token: "token"}, nil
+}
+
+func (o *mockOperator) GetSiteInstructions(token string, serverProfile string, params url.Values) (string, error) {
+	return "join --token=" + token + " --role=" + serverProfile +
+		" --advertise-addr=" + params.Get("advertise_addr"), nil
+}
+
+func (o *mockOperator) CreateSiteShrinkOperation(ctx context.Context, req ops.CreateSiteShrinkOperationRequest) (*ops.SiteOperationKey, error) {
+	o.shrinks = append(o.shrinks, req.Servers...)
+	return &ops.SiteOperationKey{
+		AccountID:   o.site.AccountID,
+		SiteDomain:  o.site.Domain,
+		OperationID: "op-1",
+	}, nil
+}
+
+type mockExecutor struct {
+	commands []command
+}
+
+type command struct {
+	addr   string
+	script string
+}
+
+func (e *mockExecutor) ExecuteScript(ctx context.Context, domainName, nodeAddr string, script io.Reader, out io.Writer) error {
+	bytes, err := ioutil.ReadAll(script)
+	if err != nil {
+		return err
+	}
+	e.commands = append(e.commands, command{addr: nodeAddr, script: string(bytes)})
+	return nil
+}
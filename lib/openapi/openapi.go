@@ -0,0 +1,458 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package openapi loads the OpenAPI specification of the Gravity HTTP APIs
+// (openapi.yaml in this directory) and validates it against the API routers
+package openapi
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/gravitational/trace"
+	"github.com/julienschmidt/httprouter"
+)
+
+const (
+	// APIOps is the tag of the cluster and operations management API
+	APIOps = "ops"
+	// APIApp is the tag of the application management API
+	APIApp = "app"
+	// APIPack is the tag of the package management API
+	APIPack = "pack"
+	// APIWeb is the tag of the web UI API
+	APIWeb = "webapi"
+
+	// WebAPIPrefix is the path prefix the web UI API is served under
+	WebAPIPrefix = "/portalapi/v1"
+)
+
+// Document is the subset of the OpenAPI 3 document used
+// to validate the specification against the API handlers
+type Document struct {
+	// OpenAPI is the OpenAPI specification version
+	OpenAPI string `json:"openapi"`
+	// Tags lists the tags used to group the API operations
+	Tags []Tag `json:"tags"`
+	// Paths maps the API paths to the operations
+	Paths map[string]PathItem `json:"paths"`
+	// Components lists the reusable objects referenced by the operations
+	Components Components `json:"components"`
+}
+
+// Tag describes an operation tag
+type Tag struct {
+	// Name is the tag name
+	Name string `json:"name"`
+	// Description is the tag description
+	Description string `json:"description"`
+}
+
+// PathItem maps the lower case HTTP method to the operation
+type PathItem map[string]Operation
+
+// Operation describes a single API operation
+type Operation struct {
+	// OperationID is the unique operation identifier
+	OperationID string `json:"operationId"`
+	// Summary is the short operation summary
+	Summary string `json:"summary"`
+	// Tags lists the operation tags
+	Tags []string `json:"tags"`
+	// Parameters lists the operation parameters
+	Parameters []Parameter `json:"parameters"`
+	// RequestBody describes the request payload
+	RequestBody *RequestBody `json:"requestBody,omitempty"`
+	// Responses maps the status codes to the operation responses
+	Responses map[string]*Response `json:"responses"`
+}
+
+// RequestBody describes the request payload
+type RequestBody struct {
+	// Required specifies whether the payload is mandatory
+	Required bool `json:"required,omitempty"`
+	// Content maps the media types to the payload schemas
+	Content map[string]MediaType `json:"content"`
+}
+
+// Response describes an operation response or a reference to one
+type Response struct {
+	// Ref references the response from components
+	Ref string `json:"$ref,omitempty"`
+	// Description is the response description
+	Description string `json:"description,omitempty"`
+	// Content maps the media types to the response schemas
+	Content map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType describes the payload of a specific media type
+type MediaType struct {
+	// Schema is the payload schema
+	Schema *Schema `json:"schema,omitempty"`
+}
+
+// Parameter describes an operation parameter or a reference to one
+type Parameter struct {
+	// Ref references the parameter from components
+	Ref string `json:"$ref,omitempty"`
+	// Name is the parameter name
+	Name string `json:"name,omitempty"`
+	// In is the parameter location
+	In string `json:"in,omitempty"`
+	// Required specifies whether the parameter is mandatory
+	Required bool `json:"required,omitempty"`
+}
+
+// Components lists the reusable specification objects
+type Components struct {
+	// Parameters maps the names to the shared parameters
+	Parameters map[string]Parameter `json:"parameters"`
+	// Responses maps the names to the shared responses
+	Responses map[string]*Response `json:"responses"`
+	// Schemas maps the names to the shared payload schemas
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+// Route is an HTTP route in the httprouter syntax
+type Route struct {
+	// Method is the HTTP method
+	Method string
+	// Path is the route path, e.g. /sites/:domain
+	Path string
+}
+
+// String returns the route description
+func (r Route) String() string {
+	return fmt.Sprintf("%v %v", r.Method, r.Path)
+}
+
+// Load reads the specification from the specified file
+func Load(path string) (*Document, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, trace.ConvertSystemError(err)
+	}
+	return Parse(data)
+}
+
+// Parse parses the specification from the YAML or JSON data
+func Parse(data []byte) (*Document, error) {
+	var doc Document
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return &doc, nil
+}
+
+// Check validates the specification: each operation has a unique ID,
+// belongs to a declared API and declares all of its path parameters
+func (d *Document) Check() error {
+	if !strings.HasPrefix(d.OpenAPI, "3.") {
+		return trace.BadParameter("unsupported OpenAPI version %q", d.OpenAPI)
+	}
+	tags := make(map[string]bool)
+	for _, tag := range d.Tags {
+		tags[tag.Name] = true
+	}
+	var errors []error
+	ids := make(map[string]string)
+	for path, item := range d.Paths {
+		for method, operation := range item {
+			route := fmt.Sprintf("%v %v", strings.ToUpper(method), path)
+			if !isMethod(method) {
+				errors = append(errors, trace.BadParameter("%v: unsupported method", route))
+				continue
+			}
+			if operation.OperationID == "" {
+				errors = append(errors, trace.BadParameter("%v: missing operationId", route))
+			} else if !operationID.MatchString(operation.OperationID) {
+				errors = append(errors, trace.BadParameter("%v: operationId %v is not a valid identifier",
+					route, operation.OperationID))
+			} else if other, ok := ids[operation.OperationID]; ok {
+				errors = append(errors, trace.BadParameter("%v: operationId %v is already used by %v",
+					route, operation.OperationID, other))
+			}
+			ids[operation.OperationID] = route
+			if len(operation.Tags) != 1 || !tags[operation.Tags[0]] {
+				errors = append(errors, trace.BadParameter("%v: expected a single declared tag, got %v",
+					route, operation.Tags))
+			}
+			if len(operation.Responses) == 0 {
+				errors = append(errors, trace.BadParameter("%v: missing responses", route))
+			}
+			if err := d.checkPathParameters(path, operation); err != nil {
+				errors = append(errors, trace.BadParameter("%v: %v", route, err))
+			}
+			if err := d.checkPayloads(operation); err != nil {
+				errors = append(errors, trace.BadParameter("%v: %v", route, err))
+			}
+		}
+	}
+	for name, response := range d.Components.Responses {
+		if err := d.checkResponse(response); err != nil {
+			errors = append(errors, trace.BadParameter("response %v: %v", name, err))
+		}
+	}
+	for name, schema := range d.Components.Schemas {
+		if err := d.checkSchema(schema); err != nil {
+			errors = append(errors, trace.BadParameter("schema %v: %v", name, err))
+		}
+	}
+	return trace.NewAggregate(errors...)
+}
+
+// Routes returns the routes of the API with the specified tag, sorted by path
+func (d *Document) Routes(api string) (routes []Route) {
+	for path, item := range d.Paths {
+		for method, operation := range item {
+			if len(operation.Tags) != 0 && operation.Tags[0] == api {
+				routes = append(routes, Route{
+					Method: strings.ToUpper(method),
+					Path:   RouterPath(path),
+				})
+			}
+		}
+	}
+	SortRoutes(routes)
+	return routes
+}
+
+// Router resolves the handler for the HTTP method and path.
+// It is implemented by httprouter.Router
+type Router interface {
+	// Lookup returns the handle and the path parameters for the method and path
+	Lookup(method, path string) (httprouter.Handle, httprouter.Params, bool)
+}
+
+// CheckRouter sends a sample request for each route through the router and verifies
+// that the request is served by the same route with the same path parameters
+func CheckRouter(router Router, routes []Route) error {
+	var errors []error
+	for _, route := range routes {
+		path, expected := samplePath(route.Path)
+		handle, params, _ := router.Lookup(route.Method, path)
+		if handle == nil {
+			errors = append(errors, trace.NotFound("%v: not served by the router", route))
+			continue
+		}
+		if !paramsEqual(params, expected) {
+			errors = append(errors, trace.BadParameter("%v: request %v is routed with parameters %v",
+				route, path, params))
+		}
+	}
+	return trace.NewAggregate(errors...)
+}
+
+// Diff returns the routes that are only present in the first
+// or only present in the second list, respectively
+func Diff(a, b []Route) (onlyA, onlyB []Route) {
+	set := func(routes []Route) map[Route]bool {
+		result := make(map[Route]bool, len(routes))
+		for _, route := range routes {
+			result[route] = true
+		}
+		return result
+	}
+	setA, setB := set(a), set(b)
+	for _, route := range a {
+		if !setB[route] {
+			onlyA = append(onlyA, route)
+		}
+	}
+	for _, route := range b {
+		if !setA[route] {
+			onlyB = append(onlyB, route)
+		}
+	}
+	return onlyA, onlyB
+}
+
+// AddPrefix returns the routes with the path prefix added
+func AddPrefix(routes []Route, prefix string) []Route {
+	result := make([]Route, 0, len(routes))
+	for _, route := range routes {
+		result = append(result, Route{Method: route.Method, Path: prefix + route.Path})
+	}
+	return result
+}
+
+// TrimPrefix returns the routes with the path prefix removed
+func TrimPrefix(routes []Route, prefix string) []Route {
+	result := make([]Route, 0, len(routes))
+	for _, route := range routes {
+		result = append(result, Route{Method: route.Method, Path: strings.TrimPrefix(route.Path, prefix)})
+	}
+	return result
+}
+
+// SortRoutes sorts the routes by path and method
+func SortRoutes(routes []Route) {
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return routes[i].Method < routes[j].Method
+	})
+}
+
+// RouterPath converts the OpenAPI path template to the httprouter syntax,
+// e.g. /sites/{domain} to /sites/:domain
+func RouterPath(path string) string {
+	return templateParam.ReplaceAllString(path, ":$1")
+}
+
+// SpecPath converts the httprouter path to the OpenAPI path template,
+// e.g. /sites/:domain to /sites/{domain}
+func SpecPath(path string) string {
+	return routerParam.ReplaceAllString(path, "{$1}")
+}
+
+func (d *Document) checkPathParameters(path string, operation Operation) error {
+	declared := make(map[string]bool)
+	for _, param := range operation.Parameters {
+		if param.Ref != "" {
+			name := strings.TrimPrefix(param.Ref, "#/components/parameters/")
+			ref, ok := d.Components.Parameters[name]
+			if !ok {
+				return trace.NotFound("parameter %v is not defined", param.Ref)
+			}
+			param = ref
+		}
+		if param.In != "path" {
+			continue
+		}
+		if !param.Required {
+			return trace.BadParameter("path parameter %v must be required", param.Name)
+		}
+		declared[param.Name] = true
+	}
+	var missing []string
+	for _, match := range templateParam.FindAllStringSubmatch(path, -1) {
+		if !declared[match[1]] {
+			missing = append(missing, match[1])
+		}
+		delete(declared, match[1])
+	}
+	if len(missing) != 0 {
+		return trace.BadParameter("path parameters %v are not declared", missing)
+	}
+	if len(declared) != 0 {
+		return trace.BadParameter("declared parameters %v are not in the path", declared)
+	}
+	return nil
+}
+
+// checkPayloads verifies that the operation request and response
+// payloads only reference the defined components
+func (d *Document) checkPayloads(operation Operation) error {
+	var errors []error
+	if operation.RequestBody != nil {
+		for _, media := range operation.RequestBody.Content {
+			if err := d.checkSchema(media.Schema); err != nil {
+				errors = append(errors, err)
+			}
+		}
+	}
+	for _, response := range operation.Responses {
+		if err := d.checkResponse(response); err != nil {
+			errors = append(errors, err)
+		}
+	}
+	return trace.NewAggregate(errors...)
+}
+
+func (d *Document) checkResponse(response *Response) error {
+	response, err := d.resolveResponse(response)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	var errors []error
+	for _, media := range response.Content {
+		if err := d.checkSchema(media.Schema); err != nil {
+			errors = append(errors, err)
+		}
+	}
+	return trace.NewAggregate(errors...)
+}
+
+// checkSchema verifies that the schema only references the defined schemas
+func (d *Document) checkSchema(schema *Schema) error {
+	if schema == nil {
+		return nil
+	}
+	if schema.Ref != "" {
+		_, err := d.resolve(schema)
+		return trace.Wrap(err)
+	}
+	var errors []error
+	nested := append([]*Schema{schema.AdditionalProperties, schema.Items}, schema.AllOf...)
+	for _, property := range schema.Properties {
+		nested = append(nested, property)
+	}
+	for _, other := range nested {
+		if err := d.checkSchema(other); err != nil {
+			errors = append(errors, err)
+		}
+	}
+	return trace.NewAggregate(errors...)
+}
+
+// samplePath substitutes the route path parameters with sample
+// values and returns the expected router parameters
+func samplePath(path string) (string, httprouter.Params) {
+	var params httprouter.Params
+	sample := routerParam.ReplaceAllStringFunc(path, func(param string) string {
+		name := param[1:]
+		value := fmt.Sprintf("sample-%v", strings.Replace(name, "_", "-", -1))
+		params = append(params, httprouter.Param{Key: name, Value: value})
+		return value
+	})
+	return sample, params
+}
+
+func paramsEqual(a, b httprouter.Params) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func isMethod(method string) bool {
+	switch strings.ToUpper(method) {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut,
+		http.MethodPatch, http.MethodDelete, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+var (
+	templateParam = regexp.MustCompile(`\{([a-zA-Z0-9_]+)\}`)
+	routerParam   = regexp.MustCompile(`:([a-zA-Z0-9_]+)`)
+	// operationID matches the operation IDs that the client generators
+	// can use as method names as is
+	operationID = regexp.MustCompile(`^[a-z][a-zA-Z0-9]*$`)
+)
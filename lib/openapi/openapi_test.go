@@ -0,0 +1,246 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openapi
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ghodss/yaml"
+	"github.com/gravitational/trace"
+	"github.com/julienschmidt/httprouter"
+	"gopkg.in/check.v1"
+)
+
+func TestOpenAPI(t *testing.T) { check.TestingT(t) }
+
+var update = flag.Bool("update", false, "update the schemas generated from the payload types in openapi.yaml")
+
+type OpenAPISuite struct {
+	doc *Document
+}
+
+var _ = check.Suite(&OpenAPISuite{})
+
+// handlers lists the source files registering the routes of each API
+var handlers = []struct {
+	api    string
+	path   string
+	prefix string
+}{
+	{api: APIOps, path: "../ops/opshandler/opshandler.go"},
+	{api: APIApp, path: "../app/handler/handler.go"},
+	{api: APIPack, path: "../pack/webpack/webpack.go"},
+	{api: APIWeb, path: "../webapi/webapi.go", prefix: WebAPIPrefix},
+}
+
+// clients lists the generated API clients and the commands that compile them
+var clients = []struct {
+	lang    string
+	compile []string
+}{
+	{lang: "go", compile: []string{"go", "build", "./..."}},
+	{lang: "python", compile: []string{"python3", "-m", "compileall", "-q", "."}},
+}
+
+func (s *OpenAPISuite) SetUpSuite(c *check.C) {
+	var err error
+	s.doc, err = Load("openapi.yaml")
+	c.Assert(err, check.IsNil)
+}
+
+func (s *OpenAPISuite) TestSpecIsValid(c *check.C) {
+	c.Assert(s.doc.Check(), check.IsNil)
+}
+
+// TestSpecMatchesHandlers verifies that every route registered by
+// the API handlers is described by the specification and vice versa
+func (s *OpenAPISuite) TestSpecMatchesHandlers(c *check.C) {
+	for _, handler := range handlers {
+		routes, err := ParseRoutes(handler.path)
+		c.Assert(err, check.IsNil)
+		c.Assert(routes, check.Not(check.HasLen), 0)
+		notInSpec, notInHandler := Diff(AddPrefix(routes, handler.prefix), s.doc.Routes(handler.api))
+		comment := check.Commentf("%v: routes missing from the specification %v, routes missing from the handler %v",
+			handler.path, notInSpec, notInHandler)
+		c.Assert(notInSpec, check.HasLen, 0, comment)
+		c.Assert(notInHandler, check.HasLen, 0, comment)
+	}
+}
+
+// TestRoutesServedThroughSpec sends a request for every route in the specification
+// through the router with the handler routes and verifies it is served by the same route
+func (s *OpenAPISuite) TestRoutesServedThroughSpec(c *check.C) {
+	for _, handler := range handlers {
+		routes, err := ParseRoutes(handler.path)
+		c.Assert(err, check.IsNil)
+		router := httprouter.New()
+		for _, route := range routes {
+			router.Handle(route.Method, route.Path, func(http.ResponseWriter, *http.Request, httprouter.Params) {})
+		}
+		err = CheckRouter(router, TrimPrefix(s.doc.Routes(handler.api), handler.prefix))
+		c.Assert(err, check.IsNil, check.Commentf(handler.path))
+	}
+}
+
+// TestSchemasMatchPayloads verifies that the component schemas
+// in the specification describe the current payload types
+func (s *OpenAPISuite) TestSchemasMatchPayloads(c *check.C) {
+	generator := NewGenerator()
+	for _, payload := range payloads {
+		_, err := generator.Add(payload)
+		c.Assert(err, check.IsNil)
+	}
+	if *update {
+		c.Assert(writeSchemas("openapi.yaml", generator.Schemas), check.IsNil)
+		s.SetUpSuite(c)
+	}
+	for name, schema := range generator.Schemas {
+		c.Assert(s.doc.Components.Schemas[name], check.DeepEquals, schema, check.Commentf(
+			"%v: run \"go test ./lib/openapi -update\" to update the generated schemas", name))
+	}
+	for name := range s.doc.Components.Schemas {
+		if _, ok := generator.Schemas[name]; !ok && !isHandWritten(name) {
+			c.Errorf("schema %v does not describe a payload type", name)
+		}
+	}
+}
+
+// TestGeneratesClients generates the Go and Python clients from the specification
+// and verifies that they compile. It runs only if OPENAPI_GENERATOR is set to the
+// openapi-generator command, see the openapi-clients-test target in the Makefile
+func (s *OpenAPISuite) TestGeneratesClients(c *check.C) {
+	generator := os.Getenv("OPENAPI_GENERATOR")
+	if generator == "" {
+		c.Skip("OPENAPI_GENERATOR is not set")
+	}
+	spec, err := filepath.Abs("openapi.yaml")
+	c.Assert(err, check.IsNil)
+	for _, client := range clients {
+		dir := c.MkDir()
+		out, err := exec.Command("sh", "-c", fmt.Sprintf(
+			"%v generate -i %v -g %v -o %v --additional-properties=packageName=gravity",
+			generator, spec, client.lang, dir)).CombinedOutput()
+		c.Assert(err, check.IsNil, check.Commentf("failed to generate %v client: %s", client.lang, out))
+		cmd := exec.Command(client.compile[0], client.compile[1:]...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GO111MODULE=on")
+		out, err = cmd.CombinedOutput()
+		c.Assert(err, check.IsNil, check.Commentf("failed to compile %v client: %s", client.lang, out))
+	}
+}
+
+func (s *OpenAPISuite) TestConvertsPaths(c *check.C) {
+	c.Assert(RouterPath("/sites/{domain}/operations/{operation_id}"), check.Equals,
+		"/sites/:domain/operations/:operation_id")
+	c.Assert(SpecPath("/sites/:domain/operations/:operation_id"), check.Equals,
+		"/sites/{domain}/operations/{operation_id}")
+}
+
+func (s *OpenAPISuite) TestDetectsInvalidSpec(c *check.C) {
+	doc, err := Parse([]byte(`
+openapi: 3.0.2
+tags:
+  - name: ops
+paths:
+  /sites/{domain}/operations/{operation_id}:
+    get:
+      tags: [ops]
+      operationId: getOperation
+      parameters:
+        - $ref: '#/components/parameters/domain'
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Operation'
+  /sites:
+    get:
+      tags: [app]
+      operationId: getOperation
+    post:
+      tags: [ops]
+      operationId: create-site
+      responses:
+        default:
+          $ref: '#/components/responses/Error'
+components:
+  parameters:
+    domain:
+      name: domain
+      in: path
+      required: true
+`))
+	c.Assert(err, check.IsNil)
+	err = doc.Check()
+	c.Assert(err, check.NotNil)
+	c.Assert(err, check.ErrorMatches, `(?s).*operation_id.*not declared.*`)
+	c.Assert(err, check.ErrorMatches, `(?s).*operationId getOperation is already used.*`)
+	c.Assert(err, check.ErrorMatches, `(?s).*expected a single declared tag.*`)
+	c.Assert(err, check.ErrorMatches, `(?s).*missing responses.*`)
+	c.Assert(err, check.ErrorMatches, `(?s).*operationId create-site is not a valid identifier.*`)
+	c.Assert(err, check.ErrorMatches, `(?s).*schema #/components/schemas/Operation is not defined.*`)
+	c.Assert(err, check.ErrorMatches, `(?s).*response #/components/responses/Error is not defined.*`)
+}
+
+// writeSchemas replaces the generated schemas at the end of the specification
+func writeSchemas(path string, schemas map[string]*Schema) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	i := bytes.Index(data, []byte(generatedMarker))
+	if i == -1 {
+		return trace.NotFound("%v does not contain the generated schemas marker", path)
+	}
+	generated, err := yaml.Marshal(schemas)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	buf.Write(data[:i+len(generatedMarker)])
+	for _, line := range strings.SplitAfter(string(generated), "\n") {
+		if line != "" {
+			buf.WriteString("    " + line)
+		}
+	}
+	return ioutil.WriteFile(path, buf.Bytes(), 0644)
+}
+
+func isHandWritten(name string) bool {
+	for _, schema := range schemas {
+		if schema == name {
+			return true
+		}
+	}
+	return false
+}
+
+// generatedMarker precedes the generated schemas in the specification
+const generatedMarker = `    # The schemas below are generated from the payload types listed in
+    # payloads_test.go with "go test ./lib/openapi -update", do not edit them.
+`
@@ -0,0 +1,132 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openapi
+
+import (
+	"github.com/gravitational/gravity/lib/app"
+	appapi "github.com/gravitational/gravity/lib/app/api"
+	"github.com/gravitational/gravity/lib/ops"
+	"github.com/gravitational/gravity/lib/ops/opsclient"
+	"github.com/gravitational/gravity/lib/pack"
+	"github.com/gravitational/gravity/lib/storage"
+	"github.com/gravitational/gravity/lib/storage/clusterconfig"
+	"github.com/gravitational/gravity/lib/users"
+)
+
+// payloads lists the Go types of the ops, app and pack API payloads.
+// The component schemas of these types are generated into the specification
+var payloads = []interface{}{
+	// ops
+	ops.Account{},
+	ops.ActivateSiteRequest{},
+	ops.AgentStatus{},
+	ops.AppInstallerRequest{},
+	ops.AuditEventRequest{},
+	ops.CheckUserAccessRequest{},
+	ops.ClusterCertificate{},
+	ops.ClusterMetricsResponse{},
+	ops.CompleteFinalInstallStepRequest{},
+	ops.ConfigurePackagesRequest{},
+	ops.CreateAccessRequestRequest{},
+	ops.CreateClusterGarbageCollectOperationRequest{},
+	ops.CreateSiteAppUpdateOperationRequest{},
+	ops.CreateSiteExpandOperationRequest{},
+	ops.CreateSiteInstallOperationRequest{},
+	ops.CreateSiteShrinkOperationRequest{},
+	ops.CreateSiteUninstallOperationRequest{},
+	ops.CreateUpdateConfigOperationRequest{},
+	ops.CreateUpdateEnvarsOperationRequest{},
+	ops.CreateUserInviteRequest{},
+	ops.CreateUserResetRequest{},
+	ops.DeactivateSiteRequest{},
+	ops.Endpoint{},
+	ops.LogEntry{},
+	ops.NewAPIKeyRequest{},
+	ops.NewAccountRequest{},
+	ops.NewInstallTokenRequest{},
+	ops.NewSiteRequest{},
+	ops.NewUserRequest{},
+	ops.Node{},
+	ops.OperationUpdateRequest{},
+	ops.ProgressEntry{},
+	ops.RPCCertificateAuthority{},
+	ops.RawAgentReport{},
+	ops.ResetUserPasswordRequest{},
+	ops.ReviewAccessRequestRequest{},
+	ops.SSHSignRequest{},
+	ops.SSHSignResponseRaw{},
+	ops.SetOperationStateRequest{},
+	ops.Site{},
+	ops.SiteOperation{},
+	ops.SiteOperationKey{},
+	ops.TLSSignRequest{},
+	ops.TLSSignResponse{},
+	ops.UnlockUserRequest{},
+	ops.UpdateCertificateRequest{},
+	ops.UpdateClusterConfigRequest{},
+	ops.UpdateClusterEnvironRequest{},
+	ops.UpdateRPCCredentialsRequest{},
+	ops.UpdateUserRequest{},
+	ops.UserInfoRaw{},
+	ops.ValidateRemoteAccessRequest{},
+	ops.ValidateRemoteAccessResponse{},
+	ops.ValidateServersRequest{},
+	opsclient.UpsertResourceRawReq{},
+	storage.APIKey{},
+	storage.AccessRequest{},
+	storage.AlertTargetV2{},
+	storage.AlertV2{},
+	storage.AuditRecord{},
+	storage.AuthGatewayV1{},
+	storage.DiskBenchmark{},
+	storage.EnvironmentV1{},
+	storage.InstallToken{},
+	storage.LogForwarderV2{},
+	storage.LoginEntry{},
+	storage.MFAPolicyV1{},
+	storage.MaintenanceWindowV1{},
+	storage.NodePoolV1{},
+	storage.OperationPlan{},
+	storage.PasswordPolicyV1{},
+	storage.PersistentStorageV1{},
+	storage.PlanChange{},
+	storage.ProvisioningToken{},
+	storage.ReleaseV1{},
+	storage.SMTPConfigV2{},
+	storage.SiteOperation{},
+	storage.UserInvite{},
+	storage.UserToken{},
+	storage.UserV1{},
+	clusterconfig.Resource{},
+	users.AccessDecision{},
+	// app
+	app.Application{},
+	app.HookRef{},
+	app.HookRunRequest{},
+	app.ImportRequest{},
+	app.InstallerRequestRaw{},
+	app.ProgressEntry{},
+	app.Status{},
+	appapi.ExportConfig{},
+	storage.AppOperation{},
+	// pack
+	pack.PackageEnvelope{},
+}
+
+// schemas lists the hand-written component schemas
+// that do not describe a Go type
+var schemas = []string{"Error", "Message", "Resource", "StatusMessage"}
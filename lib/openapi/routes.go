@@ -0,0 +1,85 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openapi
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gravitational/trace"
+)
+
+// ParseRoutes returns the routes registered on the httprouter router
+// in the specified Go source file, e.g.
+//
+//	h.GET("/sites/:domain", h.needsAuth(h.getCluster))
+//	h.Handle("GET", "/web", h.rootHandler)
+//
+// Only the registrations with literal methods and paths are returned.
+// The OPTIONS routes serve CORS preflight requests and are not part of the API
+func ParseRoutes(path string) (routes []Route, err error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, nil, 0)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	ast.Inspect(file, func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		selector, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		args := call.Args
+		method := selector.Sel.Name
+		if method == "Handle" && len(args) != 0 {
+			method, ok = stringLiteral(args[0])
+			if !ok {
+				return true
+			}
+			args = args[1:]
+		}
+		// only consider the upper case router methods, e.g. GET but not Get
+		if method != strings.ToUpper(method) || !isMethod(method) || method == http.MethodOptions || len(args) < 2 {
+			return true
+		}
+		if path, ok := stringLiteral(args[0]); ok {
+			routes = append(routes, Route{Method: method, Path: path})
+		}
+		return true
+	})
+	SortRoutes(routes)
+	return routes, nil
+}
+
+func stringLiteral(expr ast.Expr) (string, bool) {
+	literal, ok := expr.(*ast.BasicLit)
+	if !ok || literal.Kind != token.STRING {
+		return "", false
+	}
+	value, err := strconv.Unquote(literal.Value)
+	if err != nil {
+		return "", false
+	}
+	return value, true
+}
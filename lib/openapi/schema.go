@@ -0,0 +1,421 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openapi
+
+import (
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gravitational/trace"
+)
+
+// Schema is the subset of the OpenAPI schema object used
+// to describe the request and response payloads
+type Schema struct {
+	// Ref references the schema from components
+	Ref string `json:"$ref,omitempty"`
+	// Type is the value type
+	Type string `json:"type,omitempty"`
+	// Format refines the value type, e.g. date-time for strings
+	Format string `json:"format,omitempty"`
+	// Description describes the value
+	Description string `json:"description,omitempty"`
+	// Nullable specifies whether the value can be null
+	Nullable bool `json:"nullable,omitempty"`
+	// Properties maps the object property names to their schemas
+	Properties map[string]*Schema `json:"properties,omitempty"`
+	// Required lists the mandatory object properties
+	Required []string `json:"required,omitempty"`
+	// AdditionalProperties is the schema of the map values
+	AdditionalProperties *Schema `json:"additionalProperties,omitempty"`
+	// Items is the schema of the array items
+	Items *Schema `json:"items,omitempty"`
+	// AllOf lists the schemas the value must conform to
+	AllOf []*Schema `json:"allOf,omitempty"`
+}
+
+// SchemaRef returns the schema referencing the named component schema
+func SchemaRef(name string) *Schema {
+	return &Schema{Ref: schemaRefPrefix + name}
+}
+
+// Generator generates the component schemas of the Go payload types.
+//
+// The schemas follow the encoding/json rules: exported struct fields are
+// object properties named after their json tags, embedded structs are
+// flattened and nil pointers, slices, maps and interfaces are encoded as null.
+// Named struct types of the gravity packages become component schemas,
+// the types of other packages and the types with custom JSON encoding
+// are described as free-form values
+type Generator struct {
+	// Schemas maps the component names to the generated schemas
+	Schemas map[string]*Schema
+	types   map[reflect.Type]string
+}
+
+// NewGenerator returns a new schema generator
+func NewGenerator() *Generator {
+	return &Generator{
+		Schemas: make(map[string]*Schema),
+		types:   make(map[reflect.Type]string),
+	}
+}
+
+// Add generates the schema of the provided value type with all the
+// schemas it references and returns the name of its component schema
+func (g *Generator) Add(value interface{}) (string, error) {
+	t := reflect.TypeOf(value)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if !isComponent(t) {
+		return "", trace.BadParameter("%v is not a named struct type of the gravity packages", t)
+	}
+	schema := g.schemaOf(t)
+	return strings.TrimPrefix(schema.Ref, schemaRefPrefix), nil
+}
+
+// SchemaName returns the name of the component schema of the specified type,
+// e.g. OpsSiteOperation for ops.SiteOperation
+func SchemaName(t reflect.Type) string {
+	path := strings.TrimPrefix(t.PkgPath(), gravityPackages)
+	var name string
+	for _, part := range strings.Split(path, "/") {
+		name += strings.Title(part)
+	}
+	return name + t.Name()
+}
+
+func (g *Generator) schemaOf(t reflect.Type) *Schema {
+	if schema := knownSchema(t); schema != nil {
+		return schema
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		schema := g.schemaOf(t.Elem())
+		return nullable(schema)
+	case reflect.Interface:
+		return &Schema{}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64, reflect.Uintptr:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte", Nullable: true}
+		}
+		return &Schema{Type: "array", Items: g.schemaOf(t.Elem()), Nullable: true}
+	case reflect.Array:
+		return &Schema{Type: "array", Items: g.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schemaOf(t.Elem()), Nullable: true}
+	case reflect.Struct:
+		if !isComponent(t) {
+			return g.structSchema(t)
+		}
+		if name, ok := g.types[t]; ok {
+			return SchemaRef(name)
+		}
+		name := SchemaName(t)
+		g.types[t] = name
+		g.Schemas[name] = g.structSchema(t)
+		return SchemaRef(name)
+	}
+	// channels and functions cannot be encoded
+	return &Schema{}
+}
+
+func (g *Generator) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	g.addFields(schema, t)
+	if len(schema.Properties) == 0 {
+		schema.Properties = nil
+	}
+	return schema
+}
+
+func (g *Generator) addFields(schema *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts := parseTag(tag)
+		fieldType := field.Type
+		if field.Anonymous && name == "" {
+			for fieldType.Kind() == reflect.Ptr {
+				fieldType = fieldType.Elem()
+			}
+			// embedded structs are flattened even if they are not
+			// described by a component, e.g. the Kubernetes type metadata
+			if fieldType.Kind() == reflect.Struct && !isMarshaler(fieldType) {
+				g.addFields(schema, fieldType)
+				continue
+			}
+		}
+		if field.PkgPath != "" {
+			// unexported field
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fieldSchema := g.schemaOf(field.Type)
+		if opts.contains("string") {
+			fieldSchema = &Schema{Type: "string"}
+		}
+		schema.Properties[name] = fieldSchema
+	}
+}
+
+// isMarshaler returns true if the type controls its own encoding
+func isMarshaler(t reflect.Type) bool {
+	return t == timeType || implements(t, jsonMarshalerType) || implements(t, textMarshalerType)
+}
+
+// knownSchema returns the schema of the types that are not
+// encoded according to their kind, or nil for other types
+func knownSchema(t reflect.Type) *Schema {
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t == durationType:
+		return &Schema{Type: "integer", Format: "int64"}
+	case t == rawMessageType:
+		return &Schema{}
+	case implements(t, jsonMarshalerType):
+		return &Schema{}
+	case implements(t, textMarshalerType):
+		return &Schema{Type: "string"}
+	case t.Kind() == reflect.Struct && !isComponent(t):
+		if t.Name() == "" {
+			// anonymous structs are described inline
+			return nil
+		}
+		return &Schema{Type: "object"}
+	}
+	return nil
+}
+
+// Validate verifies that the value decoded from JSON with json.Decoder.UseNumber
+// conforms to the schema. Objects with declared properties may not have properties
+// missing from the schema so that the specification describes all the data
+// returned by the API
+func (d *Document) Validate(schema *Schema, value interface{}) error {
+	return d.validate("$", schema, value)
+}
+
+func (d *Document) validate(path string, schema *Schema, value interface{}) error {
+	schema, err := d.resolve(schema)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if value == nil {
+		if schema.Nullable || (schema.Type == "" && len(schema.AllOf) == 0) {
+			return nil
+		}
+		return trace.BadParameter("%v: expected %v, got null", path, schema.Type)
+	}
+	if len(schema.AllOf) != 0 {
+		var errors []error
+		for _, other := range schema.AllOf {
+			if err := d.validate(path, other, value); err != nil {
+				errors = append(errors, err)
+			}
+		}
+		return trace.NewAggregate(errors...)
+	}
+	switch schema.Type {
+	case "":
+		return nil
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return mismatch(path, schema, value)
+		}
+		var errors []error
+		for _, name := range schema.Required {
+			if _, ok := object[name]; !ok {
+				errors = append(errors, trace.BadParameter("%v: missing property %q", path, name))
+			}
+		}
+		for _, name := range sortedKeys(object) {
+			property, ok := schema.Properties[name]
+			if !ok {
+				property = schema.AdditionalProperties
+			}
+			if property == nil {
+				if len(schema.Properties) != 0 {
+					errors = append(errors, trace.BadParameter("%v: unexpected property %q", path, name))
+				}
+				continue
+			}
+			if err := d.validate(path+"."+name, property, object[name]); err != nil {
+				errors = append(errors, err)
+			}
+		}
+		return trace.NewAggregate(errors...)
+	case "array":
+		array, ok := value.([]interface{})
+		if !ok {
+			return mismatch(path, schema, value)
+		}
+		var errors []error
+		for i, item := range array {
+			if err := d.validate(fmt.Sprintf("%v[%v]", path, i), schema.Items, item); err != nil {
+				errors = append(errors, err)
+			}
+		}
+		return trace.NewAggregate(errors...)
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return mismatch(path, schema, value)
+		}
+		return trace.Wrap(checkFormat(path, schema.Format, s))
+	case "integer":
+		number, ok := value.(json.Number)
+		if !ok || strings.ContainsAny(number.String(), ".eE") {
+			return mismatch(path, schema, value)
+		}
+	case "number":
+		if _, ok := value.(json.Number); !ok {
+			return mismatch(path, schema, value)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return mismatch(path, schema, value)
+		}
+	default:
+		return trace.BadParameter("%v: unsupported schema type %q", path, schema.Type)
+	}
+	return nil
+}
+
+// resolve returns the component schema referenced by the schema
+// or the schema itself if it is not a reference
+func (d *Document) resolve(schema *Schema) (*Schema, error) {
+	if schema == nil {
+		return &Schema{}, nil
+	}
+	if schema.Ref == "" {
+		return schema, nil
+	}
+	name := strings.TrimPrefix(schema.Ref, schemaRefPrefix)
+	resolved, ok := d.Components.Schemas[name]
+	if !ok {
+		return nil, trace.NotFound("schema %v is not defined", schema.Ref)
+	}
+	return resolved, nil
+}
+
+func checkFormat(path, format, value string) error {
+	switch format {
+	case "date-time":
+		if _, err := time.Parse(time.RFC3339Nano, value); err != nil {
+			return trace.BadParameter("%v: %q is not a date-time", path, value)
+		}
+	case "byte":
+		if _, err := base64.StdEncoding.DecodeString(value); err != nil {
+			return trace.BadParameter("%v: %q is not base64 encoded", path, value)
+		}
+	}
+	return nil
+}
+
+func mismatch(path string, schema *Schema, value interface{}) error {
+	return trace.BadParameter("%v: expected %v, got %T", path, schema.Type, value)
+}
+
+func nullable(schema *Schema) *Schema {
+	if schema.Ref != "" {
+		// properties next to $ref are ignored, so wrap the reference
+		return &Schema{Nullable: true, AllOf: []*Schema{schema}}
+	}
+	result := *schema
+	result.Nullable = true
+	return &result
+}
+
+func isComponent(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t.Name() != "" &&
+		strings.HasPrefix(t.PkgPath(), gravityPackages) && !strings.Contains(t.PkgPath(), "/vendor/")
+}
+
+func implements(t, iface reflect.Type) bool {
+	return t.Implements(iface) || reflect.PtrTo(t).Implements(iface)
+}
+
+func sortedKeys(object map[string]interface{}) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+type tagOptions string
+
+func parseTag(tag string) (string, tagOptions) {
+	if i := strings.Index(tag, ","); i != -1 {
+		return tag[:i], tagOptions(tag[i+1:])
+	}
+	return tag, ""
+}
+
+func (o tagOptions) contains(option string) bool {
+	for _, opt := range strings.Split(string(o), ",") {
+		if opt == option {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	// gravityPackages is the import path prefix of the packages
+	// whose types are described by the component schemas
+	gravityPackages = "github.com/gravitational/gravity/lib/"
+	// schemaRefPrefix is the prefix of the component schema references
+	schemaRefPrefix = "#/components/schemas/"
+	// responseRefPrefix is the prefix of the component response references
+	responseRefPrefix = "#/components/responses/"
+)
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	durationType      = reflect.TypeOf(time.Duration(0))
+	rawMessageType    = reflect.TypeOf(json.RawMessage{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
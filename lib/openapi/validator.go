@@ -0,0 +1,283 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openapi
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"mime"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gravitational/trace"
+)
+
+// Validator is the HTTP middleware used in tests to verify that the requests
+// served by an API handler and its responses conform to the specification
+type Validator struct {
+	doc  *Document
+	api  string
+	next http.Handler
+
+	mu         sync.Mutex
+	errors     []error
+	operations map[string]int
+}
+
+// NewValidator returns a new validator of the requests of the API with
+// the specified tag that are served by the provided handler
+func NewValidator(doc *Document, api string, next http.Handler) *Validator {
+	return &Validator{
+		doc:        doc,
+		api:        api,
+		next:       next,
+		operations: make(map[string]int),
+	}
+}
+
+// ServeHTTP validates the request, passes it to the handler
+// and validates the response
+func (v *Validator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	route := Route{Method: r.Method, Path: r.URL.Path}
+	operation, ok := v.doc.Lookup(v.api, r.Method, r.URL.Path)
+	if !ok {
+		v.addError(trace.NotFound("%v: not described by the specification", route))
+		v.next.ServeHTTP(w, r)
+		return
+	}
+	if err := v.validateRequest(operation, r); err != nil {
+		v.addError(trace.BadParameter("%v request: %v", operation.OperationID, err))
+	}
+	recorder := &responseRecorder{ResponseWriter: w}
+	v.next.ServeHTTP(recorder, r)
+	if recorder.status == 0 {
+		recorder.status = http.StatusOK
+	}
+	if recorder.hijacked {
+		recorder.status = http.StatusSwitchingProtocols
+	}
+	if err := v.validateResponse(operation, recorder); err != nil {
+		v.addError(trace.BadParameter("%v response: %v", operation.OperationID, err))
+	}
+	v.mu.Lock()
+	v.operations[operation.OperationID]++
+	v.mu.Unlock()
+}
+
+// Error returns the validation errors of the requests served so far
+func (v *Validator) Error() error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return trace.NewAggregate(v.errors...)
+}
+
+// Operations returns the sorted IDs of the operations served so far
+func (v *Validator) Operations() []string {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	operations := make([]string, 0, len(v.operations))
+	for id := range v.operations {
+		operations = append(operations, id)
+	}
+	sort.Strings(operations)
+	return operations
+}
+
+func (v *Validator) addError(err error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.errors = append(v.errors, err)
+}
+
+func (v *Validator) validateRequest(operation *Operation, r *http.Request) error {
+	if operation.RequestBody == nil || r.Body == nil {
+		return nil
+	}
+	media, ok := operation.RequestBody.Content[mediaType(r.Header.Get("Content-Type"))]
+	if !ok || media.Schema == nil || !isJSON(r.Header.Get("Content-Type")) {
+		return nil
+	}
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return trace.Wrap(v.validateJSON(media.Schema, body))
+}
+
+func (v *Validator) validateResponse(operation *Operation, recorder *responseRecorder) error {
+	response, ok := operation.Responses[strconv.Itoa(recorder.status)]
+	if !ok && recorder.status >= http.StatusBadRequest {
+		response, ok = operation.Responses["default"]
+	}
+	if !ok {
+		return trace.BadParameter("status %v is not described", recorder.status)
+	}
+	response, err := v.doc.resolveResponse(response)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	contentType := recorder.Header().Get("Content-Type")
+	if !isJSON(contentType) || recorder.body.Len() == 0 {
+		return nil
+	}
+	media, ok := response.Content[mediaType(contentType)]
+	if !ok {
+		return trace.BadParameter("status %v with %v content is not described", recorder.status, contentType)
+	}
+	return trace.Wrap(v.validateJSON(media.Schema, recorder.body.Bytes()))
+}
+
+func (v *Validator) validateJSON(schema *Schema, data []byte) error {
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return trace.Wrap(err)
+	}
+	return trace.Wrap(v.doc.Validate(schema, value))
+}
+
+// Lookup returns the operation of the API with the specified tag that serves
+// the request with the specified method and path. Like the router, it prefers
+// the paths with static segments over the paths with parameters
+func (d *Document) Lookup(api, method, path string) (*Operation, bool) {
+	segments := strings.Split(path, "/")
+	var result *Operation
+	var resultScore []bool
+	for template, item := range d.Paths {
+		operation, ok := item[strings.ToLower(method)]
+		if !ok || len(operation.Tags) == 0 || operation.Tags[0] != api {
+			continue
+		}
+		score, ok := matchPath(strings.Split(template, "/"), segments)
+		if !ok || (result != nil && !preferred(score, resultScore)) {
+			continue
+		}
+		matched := operation
+		result, resultScore = &matched, score
+	}
+	return result, result != nil
+}
+
+// matchPath matches the path segments against the template segments
+// and returns whether each matched segment is static
+func matchPath(template, segments []string) ([]bool, bool) {
+	if len(template) != len(segments) {
+		return nil, false
+	}
+	static := make([]bool, len(template))
+	for i, segment := range template {
+		if templateParam.MatchString(segment) {
+			if segments[i] == "" {
+				return nil, false
+			}
+			continue
+		}
+		if segment != segments[i] {
+			return nil, false
+		}
+		static[i] = true
+	}
+	return static, true
+}
+
+// preferred returns true if the path with the static segments a
+// is preferred over the path with the static segments b
+func preferred(a, b []bool) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i]
+		}
+	}
+	return false
+}
+
+// resolveResponse returns the component response referenced by the
+// response or the response itself if it is not a reference
+func (d *Document) resolveResponse(response *Response) (*Response, error) {
+	if response.Ref == "" {
+		return response, nil
+	}
+	name := strings.TrimPrefix(response.Ref, responseRefPrefix)
+	resolved, ok := d.Components.Responses[name]
+	if !ok {
+		return nil, trace.NotFound("response %v is not defined", response.Ref)
+	}
+	return resolved, nil
+}
+
+// responseRecorder passes the response through to the client
+// and keeps a copy of the status code and the body
+type responseRecorder struct {
+	http.ResponseWriter
+	status   int
+	body     bytes.Buffer
+	hijacked bool
+}
+
+// WriteHeader records the status code and writes it to the client
+func (r *responseRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Write records the data and writes it to the client
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	r.body.Write(data)
+	return r.ResponseWriter.Write(data)
+}
+
+// Flush sends the buffered data to the client
+func (r *responseRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack lets the handler take over the connection, e.g. for websockets
+func (r *responseRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, trace.BadParameter("response writer does not support hijacking")
+	}
+	r.hijacked = true
+	return hijacker.Hijack()
+}
+
+func mediaType(contentType string) string {
+	media, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return contentType
+	}
+	return media
+}
+
+func isJSON(contentType string) bool {
+	return mediaType(contentType) == "application/json"
+}
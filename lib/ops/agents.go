@@ -18,6 +18,7 @@ package ops
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/gravitational/gravity/lib/checks"
 	"github.com/gravitational/gravity/lib/schema"
@@ -36,6 +37,25 @@ type AgentReport struct {
 	Servers []checks.ServerInfo `json:"servers"`
 }
 
+// AgentStatus describes the connection status of an agent
+// participating in an operation
+type AgentStatus struct {
+	// Addr is the address of the agent
+	Addr string `json:"addr"`
+	// Hostname is the hostname of the node the agent is running on
+	Hostname string `json:"hostname"`
+	// Version is the version of the agent
+	Version string `json:"version"`
+	// Connected specifies whether the agent is currently connected
+	Connected bool `json:"connected"`
+	// LastHeartbeat is the time of the last successful health check
+	LastHeartbeat time.Time `json:"last_heartbeat"`
+	// RTT is the round-trip time of the last successful health check
+	RTT time.Duration `json:"rtt"`
+	// Reconnects is the number of times the agent has reconnected
+	Reconnects int `json:"reconnects"`
+}
+
 // RawAgentReport is a transport-friendly agent report representation
 type RawAgentReport struct {
 	// Message is a human readable message presented to the user
@@ -36,20 +36,21 @@ import (
 // agentService is the access point to the agent cluster for running remote
 // commands.
 // manifest specifies the application manifest with requirements.
+// Returns the results of disk benchmarks executed during the checks
 func CheckServers(ctx context.Context,
 	opKey SiteOperationKey,
 	infos checks.ServerInfos,
 	servers []storage.Server,
 	agentService AgentService,
 	manifest schema.Manifest,
-) error {
+) (benchmarks []storage.DiskBenchmark, err error) {
 	nodes, err := mergeServers(infos, servers)
 	if err != nil {
-		return trace.Wrap(err)
+		return nil, trace.Wrap(err)
 	}
 	requirements, err := checks.RequirementsFromManifest(manifest)
 	if err != nil {
-		return trace.Wrap(err)
+		return nil, trace.Wrap(err)
 	}
 	c, err := checks.New(checks.Config{
 		Remote:       &remoteCommands{key: opKey, AgentService: agentService},
@@ -61,12 +62,18 @@ func CheckServers(ctx context.Context,
 			TestPorts:        true,
 			TestDockerDevice: true,
 			TestEtcdDisk:     true,
+			TestNetworkPaths: true,
+			TestVXLAN:        true,
+			TestDiskProfiles: true,
+		},
+		OnDiskBenchmark: func(benchmark storage.DiskBenchmark) {
+			benchmarks = append(benchmarks, benchmark)
 		},
 	})
 	if err != nil {
-		return trace.Wrap(err)
+		return nil, trace.Wrap(err)
 	}
-	return trace.Wrap(c.Run(ctx))
+	return benchmarks, trace.Wrap(c.Run(ctx))
 }
 
 // FormatValidationError formats validation error as a human-readable text
@@ -106,6 +113,42 @@ func (r *remoteCommands) CheckBandwidth(ctx context.Context, req checks.PingPong
 	return resp, nil
 }
 
+// CheckPathMTU discovers the path MTU between cluster nodes
+func (r *remoteCommands) CheckPathMTU(ctx context.Context, req checks.PingPongGame) (checks.PingPongGameResults, error) {
+	resp, err := r.AgentService.CheckPathMTU(ctx, r.key, req)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return resp, nil
+}
+
+// CheckLatency validates the network latency between cluster nodes
+func (r *remoteCommands) CheckLatency(ctx context.Context, req checks.PingPongGame) (checks.PingPongGameResults, error) {
+	resp, err := r.AgentService.CheckLatency(ctx, r.key, req)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return resp, nil
+}
+
+// CheckPacketLoss validates the UDP packet loss between cluster nodes
+func (r *remoteCommands) CheckPacketLoss(ctx context.Context, req checks.PingPongGame) (checks.PingPongGameResults, error) {
+	resp, err := r.AgentService.CheckPacketLoss(ctx, r.key, req)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return resp, nil
+}
+
+// CheckVXLAN validates the overlay network data path between cluster nodes
+func (r *remoteCommands) CheckVXLAN(ctx context.Context, req checks.PingPongGame) (checks.PingPongGameResults, error) {
+	resp, err := r.AgentService.CheckVXLAN(ctx, r.key, req)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return resp, nil
+}
+
 // CheckDisks executes disk performance test on the specified node.
 func (r *remoteCommands) CheckDisks(ctx context.Context, addr string, req *proto.CheckDisksRequest) (*proto.CheckDisksResponse, error) {
 	res, err := r.AgentService.CheckDisks(ctx, r.key, addr, req)
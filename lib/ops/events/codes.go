@@ -239,6 +239,86 @@ var (
 		Name: PersistentStorageUpdatedEvent,
 		Code: PersistentStorageUpdatedCode,
 	}
+	// MaintenanceWindowUpdated is emitted when cluster maintenance window is updated.
+	MaintenanceWindowUpdated = events.Event{
+		Name: MaintenanceWindowUpdatedEvent,
+		Code: MaintenanceWindowUpdatedCode,
+	}
+	// MaintenanceWindowDeleted is emitted when cluster maintenance window is deleted.
+	MaintenanceWindowDeleted = events.Event{
+		Name: MaintenanceWindowDeletedEvent,
+		Code: MaintenanceWindowDeletedCode,
+	}
+	// NodePoolUpdated is emitted when a node pool is created or updated.
+	NodePoolUpdated = events.Event{
+		Name: NodePoolUpdatedEvent,
+		Code: NodePoolUpdatedCode,
+	}
+	// NodePoolDeleted is emitted when a node pool is deleted.
+	NodePoolDeleted = events.Event{
+		Name: NodePoolDeletedEvent,
+		Code: NodePoolDeletedCode,
+	}
+	// OIDCConnectorCreated is emitted when an OIDC connector is created/updated.
+	OIDCConnectorCreated = events.Event{
+		Name: OIDCConnectorCreatedEvent,
+		Code: OIDCConnectorCreatedCode,
+	}
+	// OIDCConnectorDeleted is emitted when an OIDC connector is deleted.
+	OIDCConnectorDeleted = events.Event{
+		Name: OIDCConnectorDeletedEvent,
+		Code: OIDCConnectorDeletedCode,
+	}
+	// MFAPolicyUpdated is emitted when cluster second factor policy is updated.
+	MFAPolicyUpdated = events.Event{
+		Name: MFAPolicyUpdatedEvent,
+		Code: MFAPolicyUpdatedCode,
+	}
+	// MFAPolicyDeleted is emitted when cluster second factor policy is deleted.
+	MFAPolicyDeleted = events.Event{
+		Name: MFAPolicyDeletedEvent,
+		Code: MFAPolicyDeletedCode,
+	}
+	// AccessRequestCreated is emitted when a user requests temporary access to roles.
+	AccessRequestCreated = events.Event{
+		Name: AccessRequestCreatedEvent,
+		Code: AccessRequestCreatedCode,
+	}
+	// AccessRequestApproved is emitted when an access request is approved.
+	AccessRequestApproved = events.Event{
+		Name: AccessRequestApprovedEvent,
+		Code: AccessRequestApprovedCode,
+	}
+	// AccessRequestDenied is emitted when an access request is denied.
+	AccessRequestDenied = events.Event{
+		Name: AccessRequestDeniedEvent,
+		Code: AccessRequestDeniedCode,
+	}
+	// AccessRequestExpired is emitted when the access granted by a request expires.
+	AccessRequestExpired = events.Event{
+		Name: AccessRequestExpiredEvent,
+		Code: AccessRequestExpiredCode,
+	}
+	// PasswordPolicyUpdated is emitted when cluster password policy is updated.
+	PasswordPolicyUpdated = events.Event{
+		Name: PasswordPolicyUpdatedEvent,
+		Code: PasswordPolicyUpdatedCode,
+	}
+	// PasswordPolicyDeleted is emitted when cluster password policy is deleted.
+	PasswordPolicyDeleted = events.Event{
+		Name: PasswordPolicyDeletedEvent,
+		Code: PasswordPolicyDeletedCode,
+	}
+	// UserLocked is emitted when a user is locked after too many failed logins.
+	UserLocked = events.Event{
+		Name: UserLockedEvent,
+		Code: UserLockedCode,
+	}
+	// UserUnlocked is emitted when a locked user is unlocked by an administrator.
+	UserUnlocked = events.Event{
+		Name: UserUnlockedEvent,
+		Code: UserUnlockedCode,
+	}
 	// ClusterUnhealthy is emitted when cluster becomes unhealthy.
 	ClusterUnhealthy = events.Event{
 		Name: ClusterDegradedEvent,
@@ -375,6 +455,38 @@ const (
 	UserInviteCreatedCode = "G1010I"
 	// PersistentStorageUpdatedCode is the persistent storage updated event code.
 	PersistentStorageUpdatedCode = "G1011I"
+	// MaintenanceWindowUpdatedCode is the maintenance window updated event code.
+	MaintenanceWindowUpdatedCode = "G1012I"
+	// MaintenanceWindowDeletedCode is the maintenance window deleted event code.
+	MaintenanceWindowDeletedCode = "G2012I"
+	// NodePoolUpdatedCode is the node pool updated event code.
+	NodePoolUpdatedCode = "G1013I"
+	// NodePoolDeletedCode is the node pool deleted event code.
+	NodePoolDeletedCode = "G2013I"
+	// OIDCConnectorCreatedCode is the OIDC connector created event code.
+	OIDCConnectorCreatedCode = "G1014I"
+	// OIDCConnectorDeletedCode is the OIDC connector deleted event code.
+	OIDCConnectorDeletedCode = "G2014I"
+	// MFAPolicyUpdatedCode is the second factor policy updated event code.
+	MFAPolicyUpdatedCode = "G1015I"
+	// MFAPolicyDeletedCode is the second factor policy deleted event code.
+	MFAPolicyDeletedCode = "G2015I"
+	// AccessRequestCreatedCode is the access request created event code.
+	AccessRequestCreatedCode = "G1016I"
+	// AccessRequestApprovedCode is the access request approved event code.
+	AccessRequestApprovedCode = "G1017I"
+	// AccessRequestDeniedCode is the access request denied event code.
+	AccessRequestDeniedCode = "G1018I"
+	// AccessRequestExpiredCode is the access request expired event code.
+	AccessRequestExpiredCode = "G2016I"
+	// PasswordPolicyUpdatedCode is the password policy updated event code.
+	PasswordPolicyUpdatedCode = "G1019I"
+	// PasswordPolicyDeletedCode is the password policy deleted event code.
+	PasswordPolicyDeletedCode = "G2017I"
+	// UserUnlockedCode is the user unlocked event code.
+	UserUnlockedCode = "G1020I"
+	// UserLockedCode is the user locked event code.
+	UserLockedCode = "G3002W"
 	// ClusterUnhealthyCode is the cluster goes unhealthy event code.
 	ClusterUnhealthyCode = "G3000W"
 	// ClusterHealthyCode is the cluster goes healthy event code.
@@ -446,6 +558,38 @@ const (
 	InviteCreatedEvent = "invite.created"
 	// PersistentStorageUpdatedEvent fires when persistent storage configuration is updated.
 	PersistentStorageUpdatedEvent = "persistentstorage.updated"
+	// MaintenanceWindowUpdatedEvent fires when maintenance window is updated.
+	MaintenanceWindowUpdatedEvent = "maintenancewindow.updated"
+	// MaintenanceWindowDeletedEvent fires when maintenance window is deleted.
+	MaintenanceWindowDeletedEvent = "maintenancewindow.deleted"
+	// NodePoolUpdatedEvent fires when node pool is created or updated.
+	NodePoolUpdatedEvent = "nodepool.updated"
+	// NodePoolDeletedEvent fires when node pool is deleted.
+	NodePoolDeletedEvent = "nodepool.deleted"
+	// OIDCConnectorCreatedEvent fires when an OIDC connector is created/updated.
+	OIDCConnectorCreatedEvent = "oidc.created"
+	// OIDCConnectorDeletedEvent fires when an OIDC connector is deleted.
+	OIDCConnectorDeletedEvent = "oidc.deleted"
+	// MFAPolicyUpdatedEvent fires when second factor policy is updated.
+	MFAPolicyUpdatedEvent = "mfapolicy.updated"
+	// MFAPolicyDeletedEvent fires when second factor policy is deleted.
+	MFAPolicyDeletedEvent = "mfapolicy.deleted"
+	// AccessRequestCreatedEvent fires when an access request is created.
+	AccessRequestCreatedEvent = "accessrequest.created"
+	// AccessRequestApprovedEvent fires when an access request is approved.
+	AccessRequestApprovedEvent = "accessrequest.approved"
+	// AccessRequestDeniedEvent fires when an access request is denied.
+	AccessRequestDeniedEvent = "accessrequest.denied"
+	// AccessRequestExpiredEvent fires when the access granted by a request expires.
+	AccessRequestExpiredEvent = "accessrequest.expired"
+	// PasswordPolicyUpdatedEvent fires when password policy is updated.
+	PasswordPolicyUpdatedEvent = "passwordpolicy.updated"
+	// PasswordPolicyDeletedEvent fires when password policy is deleted.
+	PasswordPolicyDeletedEvent = "passwordpolicy.deleted"
+	// UserLockedEvent fires when a user is locked after too many failed logins.
+	UserLockedEvent = "user.locked"
+	// UserUnlockedEvent fires when a locked user is unlocked.
+	UserUnlockedEvent = "user.unlocked"
 
 	// ClusterDegradedEvent fires when cluster health check fails.
 	ClusterDegradedEvent = "cluster.degraded"
@@ -196,4 +196,8 @@ const (
 	FieldTime = "time"
 	// FieldRoles contains roles of a new user.
 	FieldRoles = "roles"
+	// FieldAccessRequestID contains ID of an access request.
+	FieldAccessRequestID = "accessRequestID"
+	// FieldExpires contains expiration time of granted access.
+	FieldExpires = "expires"
 )
@@ -17,6 +17,7 @@ limitations under the License.
 package ops
 
 import (
+	"bytes"
 	"context"
 	"crypto/x509"
 	"encoding/pem"
@@ -27,6 +28,7 @@ import (
 	"github.com/gravitational/gravity/lib/defaults"
 	"github.com/gravitational/gravity/lib/loc"
 	"github.com/gravitational/gravity/lib/modules"
+	pb "github.com/gravitational/gravity/lib/rpc/proto"
 	"github.com/gravitational/gravity/lib/storage"
 	"github.com/gravitational/gravity/lib/storage/clusterconfig"
 	"github.com/gravitational/gravity/lib/users"
@@ -117,6 +119,23 @@ func (o *OperatorACL) ClusterAction(clusterName, resourceKind, action string) er
 	return o.checker.CheckAccessToRule(ctx, cluster.GetMetadata().Namespace, resourceKind, action, false)
 }
 
+// clusterOperationAction checks access to start the specified operation on
+// the cluster, see users.CheckAccess for how the operation rules are evaluated
+func (o *OperatorACL) clusterOperationAction(clusterName, operation string) error {
+	return o.clusterRuleAction(clusterName, storage.KindOperation, operation)
+}
+
+// clusterRuleAction checks access to the specified action on the resource kind
+// that falls back to a coarse-grained cluster permission if the user roles
+// do not define rules for the kind
+func (o *OperatorACL) clusterRuleAction(clusterName, resourceKind, action string) error {
+	ctx, cluster, err := o.clusterContext(clusterName)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	return users.CheckAccess(o.checker, ctx, cluster.GetMetadata().Namespace, resourceKind, action)
+}
+
 func (o *OperatorACL) repoContext(repoName string) *users.Context {
 	return o.resourceContext(storage.NewRepository(repoName))
 }
@@ -209,6 +228,13 @@ func (o *OperatorACL) UpdateUser(ctx context.Context, req UpdateUserRequest) err
 	return o.operator.UpdateUser(ctx, req)
 }
 
+func (o *OperatorACL) UnlockUser(ctx context.Context, req UnlockUserRequest) error {
+	if err := o.Action(teleservices.KindUser, teleservices.VerbUpdate); err != nil {
+		return trace.Wrap(err)
+	}
+	return o.operator.UnlockUser(ctx, req)
+}
+
 func (o *OperatorACL) DeleteLocalUser(name string) error {
 	if err := o.Action(teleservices.KindUser, teleservices.VerbDelete); err != nil {
 		return trace.Wrap(err)
@@ -246,6 +272,11 @@ func (o *OperatorACL) ResetUserPassword(req ResetUserPasswordRequest) (string, e
 }
 
 func (o *OperatorACL) CreateAPIKey(ctx context.Context, req NewAPIKeyRequest) (*storage.APIKey, error) {
+	// a scoped key could otherwise be used to create a key with
+	// all permissions of its user
+	if users.IsScopedAPIKey(o.checker) {
+		return nil, trace.AccessDenied("API keys cannot be created with a scoped API key")
+	}
 	if err := o.currentUserActions(req.UserEmail, teleservices.VerbCreate); err != nil {
 		return nil, trace.Wrap(err)
 	}
@@ -353,11 +384,14 @@ func (o *OperatorACL) GetLocalSite() (*Site, error) {
 	return o.operator.GetLocalSite()
 }
 
-func (o *OperatorACL) DeleteSite(siteKey SiteKey) error {
+func (o *OperatorACL) DeleteSite(ctx context.Context, siteKey SiteKey) error {
 	if err := o.ClusterAction(siteKey.SiteDomain, storage.KindCluster, teleservices.VerbDelete); err != nil {
 		return trace.Wrap(err)
 	}
-	return o.operator.DeleteSite(siteKey)
+	if err := o.checkSecondFactor(ctx, siteKey, storage.MFAActionUninstall); err != nil {
+		return trace.Wrap(err)
+	}
+	return o.operator.DeleteSite(ctx, siteKey)
 }
 
 func (o *OperatorACL) GetSiteByDomain(domainName string) (*Site, error) {
@@ -441,35 +475,72 @@ func (o *OperatorACL) GetSiteOperation(key SiteOperationKey) (*SiteOperation, er
 }
 
 func (o *OperatorACL) CreateSiteInstallOperation(ctx context.Context, req CreateSiteInstallOperationRequest) (*SiteOperationKey, error) {
-	if err := o.ClusterAction(req.SiteDomain, storage.KindCluster, teleservices.VerbUpdate); err != nil {
+	if err := o.clusterOperationAction(req.SiteDomain, storage.OperationVerbInstall); err != nil {
 		return nil, trace.Wrap(err)
 	}
 	return o.operator.CreateSiteInstallOperation(ctx, req)
 }
 
 func (o *OperatorACL) ResumeShrink(key SiteKey) (*SiteOperationKey, error) {
-	if err := o.ClusterAction(key.SiteDomain, storage.KindCluster, teleservices.VerbUpdate); err != nil {
+	if err := o.clusterOperationAction(key.SiteDomain, storage.OperationVerbShrink); err != nil {
 		return nil, trace.Wrap(err)
 	}
 	return o.operator.ResumeShrink(key)
 }
 
 func (o *OperatorACL) CreateSiteExpandOperation(ctx context.Context, req CreateSiteExpandOperationRequest) (*SiteOperationKey, error) {
-	if err := o.ClusterAction(req.SiteDomain, storage.KindCluster, teleservices.VerbUpdate); err != nil {
+	if err := o.clusterOperationAction(req.SiteDomain, storage.OperationVerbExpand); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	if err := o.checkSecondFactor(ctx, SiteKey{AccountID: req.AccountID, SiteDomain: req.SiteDomain}, storage.MFAActionExpand); err != nil {
 		return nil, trace.Wrap(err)
 	}
 	return o.operator.CreateSiteExpandOperation(ctx, req)
 }
 
 func (o *OperatorACL) CreateSiteShrinkOperation(ctx context.Context, req CreateSiteShrinkOperationRequest) (*SiteOperationKey, error) {
-	if err := o.ClusterAction(req.SiteDomain, storage.KindCluster, teleservices.VerbUpdate); err != nil {
+	if err := o.clusterOperationAction(req.SiteDomain, storage.OperationVerbShrink); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	if o.username == storage.ClusterAgent(req.SiteDomain) {
+		if err := o.checkAgentShrink(req); err != nil {
+			return nil, trace.Wrap(err)
+		}
+	}
+	if err := o.checkSecondFactor(ctx, SiteKey{AccountID: req.AccountID, SiteDomain: req.SiteDomain}, storage.MFAActionShrink); err != nil {
 		return nil, trace.Wrap(err)
 	}
 	return o.operator.CreateSiteShrinkOperation(ctx, req)
 }
 
+// checkAgentShrink makes sure the cluster agent only removes a node to replace it.
+// Nodes joining with a join token authenticate as the cluster agent, so the agent
+// may only remove a single node that has gone offline
+func (o *OperatorACL) checkAgentShrink(req CreateSiteShrinkOperationRequest) error {
+	if !req.Replace || len(req.Servers) != 1 {
+		return trace.AccessDenied("cluster agent can only remove a node being replaced")
+	}
+	nodes, err := o.operator.GetClusterNodes(SiteKey{
+		AccountID:  req.AccountID,
+		SiteDomain: req.SiteDomain,
+	})
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	for _, node := range nodes {
+		if node.Hostname == req.Servers[0] || node.AdvertiseIP == req.Servers[0] {
+			return trace.AccessDenied("node %v is online, only offline nodes "+
+				"can be replaced by a joining node", req.Servers[0])
+		}
+	}
+	return nil
+}
+
 func (o *OperatorACL) CreateSiteAppUpdateOperation(ctx context.Context, req CreateSiteAppUpdateOperationRequest) (*SiteOperationKey, error) {
-	if err := o.ClusterAction(req.SiteDomain, storage.KindCluster, teleservices.VerbUpdate); err != nil {
+	if err := o.clusterOperationAction(req.SiteDomain, storage.OperationVerbUpdate); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	if err := o.checkSecondFactor(ctx, SiteKey{AccountID: req.AccountID, SiteDomain: req.SiteDomain}, storage.MFAActionUpdate); err != nil {
 		return nil, trace.Wrap(err)
 	}
 	return o.operator.CreateSiteAppUpdateOperation(ctx, req)
@@ -490,7 +561,10 @@ func (o *OperatorACL) SiteInstallOperationStart(key SiteOperationKey) error {
 }
 
 func (o *OperatorACL) CreateSiteUninstallOperation(ctx context.Context, req CreateSiteUninstallOperationRequest) (*SiteOperationKey, error) {
-	if err := o.ClusterAction(req.SiteDomain, storage.KindCluster, teleservices.VerbUpdate); err != nil {
+	if err := o.clusterOperationAction(req.SiteDomain, storage.OperationVerbUninstall); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	if err := o.checkSecondFactor(ctx, SiteKey{AccountID: req.AccountID, SiteDomain: req.SiteDomain}, storage.MFAActionUninstall); err != nil {
 		return nil, trace.Wrap(err)
 	}
 	return o.operator.CreateSiteUninstallOperation(ctx, req)
@@ -498,7 +572,10 @@ func (o *OperatorACL) CreateSiteUninstallOperation(ctx context.Context, req Crea
 
 // CreateClusterGarbageCollectOperation creates a new garbage collection operation in the cluster
 func (o *OperatorACL) CreateClusterGarbageCollectOperation(ctx context.Context, req CreateClusterGarbageCollectOperationRequest) (*SiteOperationKey, error) {
-	if err := o.ClusterAction(req.ClusterName, storage.KindCluster, teleservices.VerbUpdate); err != nil {
+	if err := o.clusterOperationAction(req.ClusterName, storage.OperationVerbGarbageCollect); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	if err := o.checkSecondFactor(ctx, SiteKey{AccountID: req.AccountID, SiteDomain: req.ClusterName}, storage.MFAActionGarbageCollect); err != nil {
 		return nil, trace.Wrap(err)
 	}
 	return o.operator.CreateClusterGarbageCollectOperation(ctx, req)
@@ -506,7 +583,13 @@ func (o *OperatorACL) CreateClusterGarbageCollectOperation(ctx context.Context,
 
 // CreateUpdateEnvarsOperation creates a new operation to update cluster environment variables
 func (o *OperatorACL) CreateUpdateEnvarsOperation(ctx context.Context, req CreateUpdateEnvarsOperationRequest) (*SiteOperationKey, error) {
-	if err := o.ClusterAction(req.ClusterKey.SiteDomain, storage.KindCluster, teleservices.VerbUpdate); err != nil {
+	if err := o.clusterOperationAction(req.ClusterKey.SiteDomain, storage.OperationVerbUpdateEnviron); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	if err := o.clusterRuleAction(req.ClusterKey.SiteDomain, storage.KindRuntimeEnvironment, teleservices.VerbUpdate); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	if err := o.checkSecondFactor(ctx, req.ClusterKey, storage.MFAActionUpdateEnviron); err != nil {
 		return nil, trace.Wrap(err)
 	}
 	return o.operator.CreateUpdateEnvarsOperation(ctx, req)
@@ -514,7 +597,13 @@ func (o *OperatorACL) CreateUpdateEnvarsOperation(ctx context.Context, req Creat
 
 // CreateUpdateConfigOperation creates a new operation to update cluster configuration
 func (o *OperatorACL) CreateUpdateConfigOperation(ctx context.Context, req CreateUpdateConfigOperationRequest) (*SiteOperationKey, error) {
-	if err := o.ClusterAction(req.ClusterKey.SiteDomain, storage.KindCluster, teleservices.VerbUpdate); err != nil {
+	if err := o.clusterOperationAction(req.ClusterKey.SiteDomain, storage.OperationVerbUpdateConfig); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	if err := o.clusterRuleAction(req.ClusterKey.SiteDomain, storage.KindClusterConfiguration, teleservices.VerbUpdate); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	if err := o.checkSecondFactor(ctx, req.ClusterKey, storage.MFAActionUpdateConfig); err != nil {
 		return nil, trace.Wrap(err)
 	}
 	return o.operator.CreateUpdateConfigOperation(ctx, req)
@@ -564,6 +653,20 @@ func (o *OperatorACL) GetSiteOperationProgress(key SiteOperationKey) (*ProgressE
 	return o.operator.GetSiteOperationProgress(key)
 }
 
+func (o *OperatorACL) GetOperationAgents(key SiteOperationKey) ([]AgentStatus, error) {
+	if err := o.ClusterAction(key.SiteDomain, storage.KindCluster, teleservices.VerbRead); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return o.operator.GetOperationAgents(key)
+}
+
+func (o *OperatorACL) EvictOperationAgent(key SiteOperationKey, addr string) error {
+	if err := o.ClusterAction(key.SiteDomain, storage.KindCluster, teleservices.VerbUpdate); err != nil {
+		return trace.Wrap(err)
+	}
+	return o.operator.EvictOperationAgent(key, addr)
+}
+
 func (o *OperatorACL) CreateProgressEntry(key SiteOperationKey, entry ProgressEntry) error {
 	if err := o.ClusterAction(key.SiteDomain, storage.KindCluster, teleservices.VerbUpdate); err != nil {
 		return trace.Wrap(err)
@@ -618,6 +721,13 @@ func (o *OperatorACL) DeleteSiteOperation(key SiteOperationKey) error {
 	return o.operator.DeleteSiteOperation(key)
 }
 
+func (o *OperatorACL) SaveDiskBenchmarks(key SiteOperationKey, benchmarks []storage.DiskBenchmark) error {
+	if err := o.ClusterAction(key.SiteDomain, storage.KindCluster, teleservices.VerbUpdate); err != nil {
+		return trace.Wrap(err)
+	}
+	return o.operator.SaveDiskBenchmarks(key, benchmarks)
+}
+
 func (o *OperatorACL) SetOperationState(key SiteOperationKey, req SetOperationStateRequest) error {
 	if err := o.ClusterAction(key.SiteDomain, storage.KindCluster, teleservices.VerbUpdate); err != nil {
 		return trace.Wrap(err)
@@ -746,6 +856,174 @@ func (o *OperatorACL) DeleteSMTPConfig(ctx context.Context, key SiteKey) error {
 	return o.operator.DeleteSMTPConfig(ctx, key)
 }
 
+func (o *OperatorACL) GetMaintenanceWindow(key SiteKey) (storage.MaintenanceWindow, error) {
+	if err := o.ClusterAction(key.SiteDomain, storage.KindMaintenanceWindow, teleservices.VerbRead); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return o.operator.GetMaintenanceWindow(key)
+}
+
+func (o *OperatorACL) UpdateMaintenanceWindow(ctx context.Context, key SiteKey, window storage.MaintenanceWindow) error {
+	if err := o.ClusterAction(key.SiteDomain, storage.KindMaintenanceWindow, teleservices.VerbUpdate); err != nil {
+		return trace.Wrap(err)
+	}
+	return o.operator.UpdateMaintenanceWindow(ctx, key, window)
+}
+
+func (o *OperatorACL) DeleteMaintenanceWindow(ctx context.Context, key SiteKey) error {
+	if err := o.ClusterAction(key.SiteDomain, storage.KindMaintenanceWindow, teleservices.VerbUpdate); err != nil {
+		return trace.Wrap(err)
+	}
+	return o.operator.DeleteMaintenanceWindow(ctx, key)
+}
+
+func (o *OperatorACL) GetNodePools(key SiteKey) ([]storage.NodePool, error) {
+	if err := o.ClusterAction(key.SiteDomain, storage.KindNodePool, teleservices.VerbList); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return o.operator.GetNodePools(key)
+}
+
+func (o *OperatorACL) UpdateNodePool(ctx context.Context, key SiteKey, pool storage.NodePool) error {
+	if err := o.ClusterAction(key.SiteDomain, storage.KindNodePool, teleservices.VerbUpdate); err != nil {
+		return trace.Wrap(err)
+	}
+	return o.operator.UpdateNodePool(ctx, key, pool)
+}
+
+func (o *OperatorACL) DeleteNodePool(ctx context.Context, key SiteKey, name string) error {
+	if err := o.ClusterAction(key.SiteDomain, storage.KindNodePool, teleservices.VerbDelete); err != nil {
+		return trace.Wrap(err)
+	}
+	return o.operator.DeleteNodePool(ctx, key, name)
+}
+
+// CreateAccessRequest creates a request for temporary access to roles.
+//
+// Users can request access for themselves, creating requests on behalf
+// of other users requires access to update users.
+// Each of the requested roles must be allowed by the access request rules,
+// see requestableRoleAction
+func (o *OperatorACL) CreateAccessRequest(ctx context.Context, req CreateAccessRequestRequest) (*storage.AccessRequest, error) {
+	if req.User == "" {
+		req.User = o.username
+	}
+	if err := o.currentUserActions(req.User, teleservices.VerbUpdate); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	if err := o.ClusterAction(req.SiteDomain, storage.KindCluster, teleservices.VerbRead); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	for _, role := range req.Roles {
+		if err := o.requestableRoleAction(role); err != nil {
+			return nil, trace.Wrap(err)
+		}
+	}
+	return o.operator.CreateAccessRequest(ctx, req)
+}
+
+// requestableRoleAction checks whether the current user can request
+// the role with the specified name.
+//
+// The role is the resource of the access request create rule, so the rule
+// lists the requestable roles with a where clause, for example:
+//
+//	resources: [accessrequest]
+//	verbs: [create]
+//	where: equals(resource.metadata.name, "@teleadmin")
+func (o *OperatorACL) requestableRoleAction(name string) error {
+	role, err := o.users.GetRole(name)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	err = users.CheckAccess(o.checker, o.resourceContext(role), defaults.Namespace,
+		storage.KindAccessRequest, teleservices.VerbCreate)
+	if err != nil {
+		return trace.AccessDenied("role %v cannot be requested", name)
+	}
+	return nil
+}
+
+// GetAccessRequests returns the access requests in the cluster.
+//
+// Users that cannot list access requests only see their own requests.
+func (o *OperatorACL) GetAccessRequests(key SiteKey) ([]storage.AccessRequest, error) {
+	requests, err := o.operator.GetAccessRequests(key)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	if err := o.clusterRuleAction(key.SiteDomain, storage.KindAccessRequest, teleservices.VerbList); err == nil {
+		return requests, nil
+	}
+	if err := o.ClusterAction(key.SiteDomain, storage.KindCluster, teleservices.VerbRead); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var own []storage.AccessRequest
+	for _, req := range requests {
+		if req.User == o.username {
+			own = append(own, req)
+		}
+	}
+	return own, nil
+}
+
+// ReviewAccessRequest approves or denies a pending access request
+func (o *OperatorACL) ReviewAccessRequest(ctx context.Context, req ReviewAccessRequestRequest) (*storage.AccessRequest, error) {
+	if err := o.clusterRuleAction(req.SiteDomain, storage.KindAccessRequest, teleservices.VerbUpdate); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	req.Reviewer = o.username
+	return o.operator.ReviewAccessRequest(ctx, req)
+}
+
+func (o *OperatorACL) GetMFAPolicy(key SiteKey) (storage.MFAPolicy, error) {
+	if err := o.ClusterAction(key.SiteDomain, storage.KindMFAPolicy, teleservices.VerbRead); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return o.operator.GetMFAPolicy(key)
+}
+
+func (o *OperatorACL) UpdateMFAPolicy(ctx context.Context, key SiteKey, policy storage.MFAPolicy) error {
+	if err := o.ClusterAction(key.SiteDomain, storage.KindMFAPolicy, teleservices.VerbUpdate); err != nil {
+		return trace.Wrap(err)
+	}
+	if err := o.checkSecondFactorForPolicy(ctx, key); err != nil {
+		return trace.Wrap(err)
+	}
+	return o.operator.UpdateMFAPolicy(ctx, key, policy)
+}
+
+func (o *OperatorACL) DeleteMFAPolicy(ctx context.Context, key SiteKey) error {
+	if err := o.ClusterAction(key.SiteDomain, storage.KindMFAPolicy, teleservices.VerbUpdate); err != nil {
+		return trace.Wrap(err)
+	}
+	if err := o.checkSecondFactorForPolicy(ctx, key); err != nil {
+		return trace.Wrap(err)
+	}
+	return o.operator.DeleteMFAPolicy(ctx, key)
+}
+
+func (o *OperatorACL) GetPasswordPolicy(key SiteKey) (storage.PasswordPolicy, error) {
+	if err := o.ClusterAction(key.SiteDomain, storage.KindPasswordPolicy, teleservices.VerbRead); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return o.operator.GetPasswordPolicy(key)
+}
+
+func (o *OperatorACL) UpdatePasswordPolicy(ctx context.Context, key SiteKey, policy storage.PasswordPolicy) error {
+	if err := o.ClusterAction(key.SiteDomain, storage.KindPasswordPolicy, teleservices.VerbUpdate); err != nil {
+		return trace.Wrap(err)
+	}
+	return o.operator.UpdatePasswordPolicy(ctx, key, policy)
+}
+
+func (o *OperatorACL) DeletePasswordPolicy(ctx context.Context, key SiteKey) error {
+	if err := o.ClusterAction(key.SiteDomain, storage.KindPasswordPolicy, teleservices.VerbUpdate); err != nil {
+		return trace.Wrap(err)
+	}
+	return o.operator.DeletePasswordPolicy(ctx, key)
+}
+
 func (o *OperatorACL) GetAlerts(key SiteKey) ([]storage.Alert, error) {
 	if err := o.ClusterAction(key.SiteDomain, storage.KindAlert, teleservices.VerbList); err != nil {
 		return nil, trace.Wrap(err)
@@ -931,6 +1209,9 @@ func (o *OperatorACL) UpdateClusterCertificate(ctx context.Context, req UpdateCe
 	if err := o.ClusterAction(req.SiteDomain, storage.KindCluster, teleservices.VerbUpdate); err != nil {
 		return nil, trace.Wrap(err)
 	}
+	if err := o.checkSecondFactor(ctx, SiteKey{AccountID: req.AccountID, SiteDomain: req.SiteDomain}, storage.MFAActionUpdateCertificate); err != nil {
+		return nil, trace.Wrap(err)
+	}
 	return o.operator.UpdateClusterCertificate(ctx, req)
 }
 
@@ -940,9 +1221,41 @@ func (o *OperatorACL) DeleteClusterCertificate(ctx context.Context, key SiteKey)
 			return trace.Wrap(err)
 		}
 	}
+	if err := o.checkSecondFactor(ctx, key, storage.MFAActionUpdateCertificate); err != nil {
+		return trace.Wrap(err)
+	}
 	return o.operator.DeleteClusterCertificate(ctx, key)
 }
 
+func (o *OperatorACL) GetRPCCertificateAuthority(key SiteKey) (*RPCCertificateAuthority, error) {
+	if err := o.ClusterAction(key.SiteDomain, storage.KindCluster, teleservices.VerbRead); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return o.operator.GetRPCCertificateAuthority(key)
+}
+
+// UpdateRPCCredentials replaces the cluster RPC agent credentials.
+//
+// Changing the trusted CA bundle requires a second factor. Replacing
+// the certificates while keeping the bundle does not so the rotation only
+// asks for the second factor once
+func (o *OperatorACL) UpdateRPCCredentials(ctx context.Context, req UpdateRPCCredentialsRequest) error {
+	if err := o.ClusterAction(req.ClusterKey.SiteDomain, storage.KindCluster, teleservices.VerbUpdate); err != nil {
+		return trace.Wrap(err)
+	}
+	ca, err := o.operator.GetRPCCertificateAuthority(req.ClusterKey)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	newCA, ok := req.Credentials[pb.CA]
+	if !ok || !bytes.Equal(ca.Certificate, newCA.CertPEM) {
+		if err := o.checkSecondFactor(ctx, req.ClusterKey, storage.MFAActionRotateCredentials); err != nil {
+			return trace.Wrap(err)
+		}
+	}
+	return o.operator.UpdateRPCCredentials(ctx, req)
+}
+
 // StepDown asks the process to pause its leader election heartbeat so it can
 // give up its leadership
 func (o *OperatorACL) StepDown(key SiteKey) error {
@@ -1045,6 +1358,50 @@ func (o *OperatorACL) DeleteGithubConnector(ctx context.Context, key SiteKey, na
 	return o.operator.DeleteGithubConnector(ctx, key, name)
 }
 
+// UpsertOIDCConnector creates or updates an OIDC connector
+func (o *OperatorACL) UpsertOIDCConnector(ctx context.Context, key SiteKey, connector teleservices.OIDCConnector) error {
+	if err := o.ClusterAction(key.SiteDomain, storage.KindCluster, teleservices.VerbUpdate); err != nil {
+		if err := o.AuthConnectorActions(teleservices.KindOIDCConnector, teleservices.VerbCreate, teleservices.VerbUpdate); err != nil {
+			return trace.Wrap(err)
+		}
+	}
+	return o.operator.UpsertOIDCConnector(ctx, key, connector)
+}
+
+// GetOIDCConnector returns an OIDC connector by name
+//
+// Returned connector exclude client secret unless withSecrets is true.
+func (o *OperatorACL) GetOIDCConnector(key SiteKey, name string, withSecrets bool) (teleservices.OIDCConnector, error) {
+	if err := o.ClusterAction(key.SiteDomain, storage.KindCluster, teleservices.VerbRead); err != nil {
+		if err := o.AuthConnectorActions(teleservices.KindOIDCConnector, teleservices.VerbRead); err != nil {
+			return nil, trace.Wrap(err)
+		}
+	}
+	return o.operator.GetOIDCConnector(key, name, withSecrets)
+}
+
+// GetOIDCConnectors returns all OIDC connectors
+//
+// Returned connectors exclude client secret unless withSecrets is true.
+func (o *OperatorACL) GetOIDCConnectors(key SiteKey, withSecrets bool) ([]teleservices.OIDCConnector, error) {
+	if err := o.ClusterAction(key.SiteDomain, storage.KindCluster, teleservices.VerbRead); err != nil {
+		if err := o.AuthConnectorActions(teleservices.KindOIDCConnector, teleservices.VerbList, teleservices.VerbRead); err != nil {
+			return nil, trace.Wrap(err)
+		}
+	}
+	return o.operator.GetOIDCConnectors(key, withSecrets)
+}
+
+// DeleteOIDCConnector deletes an OIDC connector by name
+func (o *OperatorACL) DeleteOIDCConnector(ctx context.Context, key SiteKey, name string) error {
+	if err := o.ClusterAction(key.SiteDomain, storage.KindCluster, teleservices.VerbUpdate); err != nil {
+		if err := o.AuthConnectorActions(teleservices.KindOIDCConnector, teleservices.VerbDelete); err != nil {
+			return trace.Wrap(err)
+		}
+	}
+	return o.operator.DeleteOIDCConnector(ctx, key, name)
+}
+
 // UpsertAuthGateway updates auth gateway configuration.
 func (o *OperatorACL) UpsertAuthGateway(ctx context.Context, key SiteKey, gw storage.AuthGateway) error {
 	if err := o.ClusterAction(key.SiteDomain, storage.KindCluster, teleservices.VerbUpdate); err != nil {
@@ -1086,6 +1443,14 @@ func (o *OperatorACL) EmitAuditEvent(ctx context.Context, req AuditEventRequest)
 	return o.operator.EmitAuditEvent(ctx, req)
 }
 
+// GetAuditRecords returns the hash-chained audit log records.
+func (o *OperatorACL) GetAuditRecords(ctx context.Context, req GetAuditRecordsRequest) ([]storage.AuditRecord, error) {
+	if err := o.ClusterAction(req.SiteDomain, teleservices.KindEvent, teleservices.VerbList); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return o.operator.GetAuditRecords(ctx, req)
+}
+
 // CreateUserInvite creates a new invite token for a user.
 func (o *OperatorACL) CreateUserInvite(ctx context.Context, req CreateUserInviteRequest) (*storage.UserToken, error) {
 	if err := o.ClusterAction(req.SiteDomain, storage.KindCluster, teleservices.VerbUpdate); err != nil {
@@ -1116,6 +1481,24 @@ func (o *OperatorACL) DeleteUserInvite(ctx context.Context, req DeleteUserInvite
 	return o.operator.DeleteUserInvite(ctx, req)
 }
 
+// CheckUserAccess explains whether the user can perform the specified action
+// on the resource kind.
+//
+// Users can check their own permissions, checking the permissions of
+// other users requires access to read users.
+func (o *OperatorACL) CheckUserAccess(ctx context.Context, req CheckUserAccessRequest) (*users.AccessDecision, error) {
+	if req.Name == "" {
+		req.Name = o.username
+	}
+	if err := o.currentUserActions(req.Name, teleservices.VerbRead); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	if err := o.ClusterAction(req.SiteDomain, storage.KindCluster, teleservices.VerbRead); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return o.operator.CheckUserAccess(ctx, req)
+}
+
 // CreateUserReset creates a new reset token for a user.
 func (o *OperatorACL) CreateUserReset(ctx context.Context, req CreateUserResetRequest) (*storage.UserToken, error) {
 	if err := o.ClusterAction(req.SiteDomain, storage.KindCluster, teleservices.VerbUpdate); err != nil {
@@ -0,0 +1,253 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ops
+
+import (
+	"context"
+
+	pb "github.com/gravitational/gravity/lib/rpc/proto"
+	"github.com/gravitational/gravity/lib/storage"
+	"github.com/gravitational/gravity/lib/users"
+	"github.com/gravitational/gravity/lib/utils"
+
+	"github.com/gravitational/license/authority"
+	teleservices "github.com/gravitational/teleport/lib/services"
+	"github.com/gravitational/trace"
+	check "gopkg.in/check.v1"
+)
+
+type OperatorACLSuite struct{}
+
+var _ = check.Suite(&OperatorACLSuite{})
+
+func (s *OperatorACLSuite) TestAgentOnlyRemovesReplacedOfflineNodes(c *check.C) {
+	acl := &OperatorACL{operator: nodesOperator{
+		nodes: []Node{{Hostname: "node-1", AdvertiseIP: "10.0.0.1"}},
+	}}
+	request := func(server string, replace bool) CreateSiteShrinkOperationRequest {
+		return CreateSiteShrinkOperationRequest{
+			AccountID:  "account",
+			SiteDomain: "example.com",
+			Servers:    []string{server},
+			Replace:    replace,
+		}
+	}
+	c.Assert(acl.checkAgentShrink(request("node-2", true)), check.IsNil)
+
+	err := acl.checkAgentShrink(request("node-2", false))
+	c.Assert(trace.IsAccessDenied(err), check.Equals, true, check.Commentf("%v", err))
+
+	err = acl.checkAgentShrink(request("node-1", true))
+	c.Assert(trace.IsAccessDenied(err), check.Equals, true, check.Commentf("%v", err))
+
+	err = acl.checkAgentShrink(request("10.0.0.1", true))
+	c.Assert(trace.IsAccessDenied(err), check.Equals, true, check.Commentf("%v", err))
+}
+
+func (s *OperatorACLSuite) TestDeleteSiteRequiresSecondFactor(c *check.C) {
+	operator := &secondFactorOperator{}
+	acl := newSecondFactorACL(c, operator)
+	key := SiteKey{AccountID: "account", SiteDomain: "example.com"}
+
+	err := acl.DeleteSite(context.TODO(), key)
+	c.Assert(IsSecondFactorRequiredError(err), check.Equals, true, check.Commentf("%v", err))
+	c.Assert(operator.deleted, check.Equals, false)
+
+	err = acl.DeleteSite(WithSecondFactor(context.TODO(), testToken), key)
+	c.Assert(err, check.IsNil)
+	c.Assert(operator.deleted, check.Equals, true)
+}
+
+func (s *OperatorACLSuite) TestUpdatingRPCCredentialsRequiresSecondFactorForNewCA(c *check.C) {
+	operator := &secondFactorOperator{ca: []byte("ca")}
+	acl := newSecondFactorACL(c, operator)
+	request := func(ca string) UpdateRPCCredentialsRequest {
+		return UpdateRPCCredentialsRequest{
+			ClusterKey: SiteKey{AccountID: "account", SiteDomain: "example.com"},
+			Credentials: utils.TLSArchive{
+				pb.CA: &authority.TLSKeyPair{CertPEM: []byte(ca)},
+			},
+		}
+	}
+
+	// adding a new CA to the trusted bundle
+	err := acl.UpdateRPCCredentials(context.TODO(), request("new-ca"))
+	c.Assert(IsSecondFactorRequiredError(err), check.Equals, true, check.Commentf("%v", err))
+	c.Assert(operator.updated, check.Equals, 0)
+	err = acl.UpdateRPCCredentials(WithSecondFactor(context.TODO(), testToken), request("new-ca"))
+	c.Assert(err, check.IsNil)
+	c.Assert(operator.updated, check.Equals, 1)
+
+	// replacing the certificates with the same trusted bundle
+	err = acl.UpdateRPCCredentials(context.TODO(), request("ca"))
+	c.Assert(err, check.IsNil)
+	c.Assert(operator.updated, check.Equals, 2)
+}
+
+func (s *OperatorACLSuite) TestCreateAccessRequestChecksRequestableRoles(c *check.C) {
+	support, err := teleservices.NewRole("support", teleservices.RoleSpecV3{
+		Allow: teleservices.RoleConditions{
+			Namespaces: []string{teleservices.Wildcard},
+			Rules: []teleservices.Rule{
+				teleservices.NewRule(storage.KindCluster, teleservices.RO()),
+				{
+					Resources: []string{storage.KindAccessRequest},
+					Verbs:     []string{teleservices.VerbCreate},
+					Where: storage.EqualsExpr{
+						Left:  storage.ResourceNameExpr,
+						Right: storage.StringExpr("@teleadmin"),
+					}.String(),
+				},
+			},
+		},
+	})
+	c.Assert(err, check.IsNil)
+	reader, err := teleservices.NewRole("reader", teleservices.RoleSpecV3{
+		Allow: teleservices.RoleConditions{
+			Namespaces: []string{teleservices.Wildcard},
+			Rules: []teleservices.Rule{
+				teleservices.NewRule(storage.KindCluster, teleservices.RO()),
+			},
+		},
+	})
+	c.Assert(err, check.IsNil)
+	identity := rolesIdentity{roles: map[string]teleservices.Role{}}
+	for _, name := range []string{"@teleadmin", "admin"} {
+		role, err := teleservices.NewRole(name, teleservices.RoleSpecV3{})
+		c.Assert(err, check.IsNil)
+		identity.roles[name] = role
+	}
+	request := func(roles ...string) CreateAccessRequestRequest {
+		return CreateAccessRequestRequest{
+			SiteKey: SiteKey{AccountID: "account", SiteDomain: "example.com"},
+			Roles:   roles,
+		}
+	}
+	user := storage.NewUser("alice@example.com", storage.UserSpecV2{Type: storage.AdminUser})
+
+	operator := &accessRequestOperator{}
+	acl := OperatorWithACL(operator, identity, user, teleservices.NewRoleSet(support))
+	_, err = acl.CreateAccessRequest(context.TODO(), request("@teleadmin"))
+	c.Assert(err, check.IsNil)
+	c.Assert(operator.created, check.Equals, 1)
+
+	_, err = acl.CreateAccessRequest(context.TODO(), request("@teleadmin", "admin"))
+	c.Assert(trace.IsAccessDenied(err), check.Equals, true, check.Commentf("%v", err))
+	c.Assert(operator.created, check.Equals, 1)
+
+	// roles without access request rules cannot request any roles
+	acl = OperatorWithACL(operator, identity, user, teleservices.NewRoleSet(reader))
+	_, err = acl.CreateAccessRequest(context.TODO(), request("@teleadmin"))
+	c.Assert(trace.IsAccessDenied(err), check.Equals, true, check.Commentf("%v", err))
+	c.Assert(operator.created, check.Equals, 1)
+}
+
+func newSecondFactorACL(c *check.C, operator Operator) *OperatorACL {
+	role, err := users.NewAdminRole()
+	c.Assert(err, check.IsNil)
+	user := storage.NewUser("alice@example.com", storage.UserSpecV2{
+		Type:  storage.AdminUser,
+		Roles: []string{role.GetName()},
+	})
+	return OperatorWithACL(operator, secondFactorIdentity{}, user, teleservices.NewRoleSet(role))
+}
+
+// secondFactorOperator is the operator for a cluster that requires
+// a second factor for all actions
+type secondFactorOperator struct {
+	Operator
+	ca      []byte
+	deleted bool
+	updated int
+}
+
+func (r *secondFactorOperator) GetSiteByDomain(domain string) (*Site, error) {
+	return &Site{Domain: domain}, nil
+}
+
+func (r *secondFactorOperator) GetMFAPolicy(SiteKey) (storage.MFAPolicy, error) {
+	return storage.NewMFAPolicy(storage.MFAPolicySpecV1{
+		Rules: []storage.MFARule{{Actions: []string{teleservices.Wildcard}}},
+	}), nil
+}
+
+func (r *secondFactorOperator) DeleteSite(context.Context, SiteKey) error {
+	r.deleted = true
+	return nil
+}
+
+func (r *secondFactorOperator) GetRPCCertificateAuthority(SiteKey) (*RPCCertificateAuthority, error) {
+	return &RPCCertificateAuthority{Certificate: r.ca}, nil
+}
+
+func (r *secondFactorOperator) UpdateRPCCredentials(context.Context, UpdateRPCCredentialsRequest) error {
+	r.updated++
+	return nil
+}
+
+// secondFactorIdentity accepts testToken as the second factor
+type secondFactorIdentity struct {
+	users.Identity
+}
+
+func (secondFactorIdentity) CheckSecondFactor(username, token string) error {
+	if token != testToken {
+		return trace.AccessDenied("invalid token")
+	}
+	return nil
+}
+
+const testToken = "123456"
+
+// accessRequestOperator counts the created access requests
+type accessRequestOperator struct {
+	Operator
+	created int
+}
+
+func (r *accessRequestOperator) GetSiteByDomain(domain string) (*Site, error) {
+	return &Site{Domain: domain}, nil
+}
+
+func (r *accessRequestOperator) CreateAccessRequest(context.Context, CreateAccessRequestRequest) (*storage.AccessRequest, error) {
+	r.created++
+	return &storage.AccessRequest{}, nil
+}
+
+// rolesIdentity returns the specified roles by name
+type rolesIdentity struct {
+	users.Identity
+	roles map[string]teleservices.Role
+}
+
+func (r rolesIdentity) GetRole(name string) (teleservices.Role, error) {
+	role, ok := r.roles[name]
+	if !ok {
+		return nil, trace.NotFound("role %v not found", name)
+	}
+	return role, nil
+}
+
+// nodesOperator is the operator that returns the specified online nodes
+type nodesOperator struct {
+	Operator
+	nodes []Node
+}
+
+func (r nodesOperator) GetClusterNodes(SiteKey) ([]Node, error) {
+	return r.nodes, nil
+}
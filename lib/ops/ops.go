@@ -36,6 +36,7 @@ import (
 	"github.com/gravitational/gravity/lib/schema"
 	"github.com/gravitational/gravity/lib/storage"
 	"github.com/gravitational/gravity/lib/storage/clusterconfig"
+	"github.com/gravitational/gravity/lib/users"
 	"github.com/gravitational/gravity/lib/utils"
 
 	"github.com/cloudflare/cfssl/csr"
@@ -85,6 +86,11 @@ type TeleportProxyService interface {
 	// for a given site domain
 	ExecuteCommand(ctx context.Context, domainName, nodeAddr, command string, out io.Writer) error
 
+	// ExecuteScript executes a shell script on a remote node address
+	// for a given site domain. The script is streamed to the shell over
+	// the session input so it does not appear on the command line or in logs
+	ExecuteScript(ctx context.Context, domainName, nodeAddr string, script io.Reader, out io.Writer) error
+
 	// GetClient returns admin client to local proxy
 	GetClient() teleauth.ClientI
 
@@ -114,6 +120,7 @@ type Operator interface {
 	Applications
 	Users
 	APIKeys
+	AccessRequests
 	Sites
 	Status
 	Operations
@@ -121,6 +128,10 @@ type Operator interface {
 	LogForwarders
 	Monitoring
 	SMTP
+	MaintenanceWindows
+	MFAPolicies
+	PasswordPolicies
+	NodePools
 	Endpoints
 	Tokens
 	Certificates
@@ -234,6 +245,11 @@ type Users interface {
 	GetUserInvites(context.Context, SiteKey) ([]storage.UserInvite, error)
 	// DeleteUserInvite deletes the specified user invite.
 	DeleteUserInvite(context.Context, DeleteUserInviteRequest) error
+	// CheckUserAccess explains whether the user can perform the specified
+	// action on the resource kind.
+	CheckUserAccess(context.Context, CheckUserAccessRequest) (*users.AccessDecision, error)
+	// UnlockUser unlocks the user locked after too many failed login attempts.
+	UnlockUser(context.Context, UnlockUserRequest) error
 }
 
 // UpdateUserRequest is a request to update existing user information.
@@ -334,6 +350,55 @@ func (r *DeleteUserInviteRequest) Check() error {
 	return nil
 }
 
+// UnlockUserRequest is a request to unlock a user account.
+type UnlockUserRequest struct {
+	// SiteKey is the key of the cluster to route request to.
+	SiteKey
+	// Name is the name of the user to unlock.
+	Name string `json:"name"`
+}
+
+// Check validates the request.
+func (r *UnlockUserRequest) Check() error {
+	if err := r.SiteKey.Check(); err != nil {
+		return trace.Wrap(err)
+	}
+	if r.Name == "" {
+		return trace.BadParameter("user name can't be empty")
+	}
+	return nil
+}
+
+// CheckUserAccessRequest is a request to check user permissions.
+type CheckUserAccessRequest struct {
+	// SiteKey is the key of the cluster to route request to.
+	SiteKey
+	// Name is the name of the user to check permissions for.
+	// Defaults to the current user if unspecified.
+	Name string `json:"name"`
+	// Verb is the action to check.
+	Verb string `json:"verb"`
+	// Kind is the resource kind to check.
+	Kind string `json:"kind"`
+}
+
+// Check validates the request.
+func (r *CheckUserAccessRequest) Check() error {
+	if err := r.SiteKey.Check(); err != nil {
+		return trace.Wrap(err)
+	}
+	if r.Name == "" {
+		return trace.BadParameter("user name can't be empty")
+	}
+	if r.Verb == "" {
+		return trace.BadParameter("verb can't be empty")
+	}
+	if r.Kind == "" {
+		return trace.BadParameter("resource kind can't be empty")
+	}
+	return nil
+}
+
 // ResetUserPasswordRequest is a request to reset gravity site user password
 type ResetUserPasswordRequest struct {
 	// AccountID is the ID of the account the site belongs to
@@ -354,6 +419,79 @@ type ClusterAgentRequest struct {
 	Admin bool `json:"admin"`
 }
 
+// AccessRequests manages requests for temporary access to privileged roles
+type AccessRequests interface {
+	// CreateAccessRequest creates a request for temporary access to roles
+	CreateAccessRequest(context.Context, CreateAccessRequestRequest) (*storage.AccessRequest, error)
+	// GetAccessRequests returns the access requests in the cluster
+	GetAccessRequests(SiteKey) ([]storage.AccessRequest, error)
+	// ReviewAccessRequest approves or denies a pending access request
+	ReviewAccessRequest(context.Context, ReviewAccessRequestRequest) (*storage.AccessRequest, error)
+}
+
+// CreateAccessRequestRequest is a request for temporary access to roles.
+type CreateAccessRequestRequest struct {
+	// SiteKey is the key of the cluster to route request to.
+	SiteKey
+	// User is the name of the user requesting access.
+	// Defaults to the current user if unspecified.
+	User string `json:"user"`
+	// Roles lists the requested roles.
+	Roles []string `json:"roles"`
+	// Reason is the justification for the request.
+	Reason string `json:"reason"`
+	// Duration is how long the access is requested for.
+	Duration time.Duration `json:"duration"`
+}
+
+// CheckAndSetDefaults validates the request and sets defaults.
+func (r *CreateAccessRequestRequest) CheckAndSetDefaults() error {
+	if err := r.SiteKey.Check(); err != nil {
+		return trace.Wrap(err)
+	}
+	if r.User == "" {
+		return trace.BadParameter("user name can't be empty")
+	}
+	if len(r.Roles) == 0 {
+		return trace.BadParameter("role list can't be empty")
+	}
+	if r.Duration == 0 {
+		r.Duration = defaults.AccessRequestDuration
+	}
+	if r.Duration < 0 || r.Duration > defaults.MaxAccessRequestDuration {
+		return trace.BadParameter("duration must be positive and not exceed %v",
+			defaults.MaxAccessRequestDuration)
+	}
+	return nil
+}
+
+// ReviewAccessRequestRequest is a request to approve or deny an access request.
+type ReviewAccessRequestRequest struct {
+	// SiteKey is the key of the cluster to route request to.
+	SiteKey
+	// ID is the ID of the access request to review.
+	ID string `json:"id"`
+	// Approve is whether to approve or deny the access request.
+	Approve bool `json:"approve"`
+	// Reviewer is the name of the user reviewing the access request.
+	// Defaults to the current user if unspecified.
+	Reviewer string `json:"reviewer"`
+}
+
+// Check validates the request.
+func (r *ReviewAccessRequestRequest) Check() error {
+	if err := r.SiteKey.Check(); err != nil {
+		return trace.Wrap(err)
+	}
+	if r.ID == "" {
+		return trace.BadParameter("access request ID can't be empty")
+	}
+	if r.Reviewer == "" {
+		return trace.BadParameter("reviewer can't be empty")
+	}
+	return nil
+}
+
 // APIKeys represents a collection of user API keys
 type APIKeys interface {
 	// CreateAPIKey creates a new API key for a user
@@ -388,7 +526,7 @@ type Sites interface {
 	// uninstalling actual resources, the site must be
 	// explicitly uninstalled for resources to be freed,
 	// see SiteUninstallOperation methods
-	DeleteSite(SiteKey) error
+	DeleteSite(ctx context.Context, key SiteKey) error
 
 	// GetSiteByDomain returns site record by it's domain name for a given
 	// account
@@ -591,6 +729,11 @@ type Certificates interface {
 	UpdateClusterCertificate(context.Context, UpdateCertificateRequest) (*ClusterCertificate, error)
 	// DeleteClusterCertificate deletes the cluster TLS certificate
 	DeleteClusterCertificate(context.Context, SiteKey) error
+	// GetRPCCertificateAuthority returns the CA certificate bundle
+	// trusted by the cluster RPC agents
+	GetRPCCertificateAuthority(SiteKey) (*RPCCertificateAuthority, error)
+	// UpdateRPCCredentials replaces the cluster RPC agent credentials
+	UpdateRPCCredentials(context.Context, UpdateRPCCredentialsRequest) error
 }
 
 // RuntimeEnvironment manages runtime environment variables in cluster
@@ -638,6 +781,20 @@ type ClusterCertificate struct {
 	PrivateKey []byte `json:"private_key"`
 }
 
+// RPCCertificateAuthority is the CA certificate bundle trusted by the cluster RPC agents
+type RPCCertificateAuthority struct {
+	// Certificate is the PEM-encoded CA certificate bundle
+	Certificate []byte `json:"certificate"`
+}
+
+// UpdateRPCCredentialsRequest is the request to replace the cluster RPC agent credentials
+type UpdateRPCCredentialsRequest struct {
+	// ClusterKey identifies the cluster
+	ClusterKey SiteKey `json:"cluster_key"`
+	// Credentials is the new credentials archive
+	Credentials utils.TLSArchive `json:"credentials"`
+}
+
 // UpdateCertificateRequest is the request to update the cluster certificate
 type UpdateCertificateRequest struct {
 	// AccountID is the cluster's account ID
@@ -780,6 +937,14 @@ type Operations interface {
 	// operation
 	CreateProgressEntry(SiteOperationKey, ProgressEntry) error
 
+	// GetOperationAgents returns the status of agents connected
+	// for the specified operation
+	GetOperationAgents(SiteOperationKey) ([]AgentStatus, error)
+
+	// EvictOperationAgent disconnects the agent specified with addr
+	// from the operation without aborting it
+	EvictOperationAgent(key SiteOperationKey, addr string) error
+
 	// CreateSiteExpandOperation initiates operation that adds nodes
 	// to the cluster
 	//
@@ -817,6 +982,10 @@ type Operations interface {
 	// UpdateExpandOperationState updates the state of an expand operation
 	UpdateExpandOperationState(key SiteOperationKey, req OperationUpdateRequest) error
 
+	// SaveDiskBenchmarks stores the results of disk benchmarks executed
+	// during the specified install or expand operation
+	SaveDiskBenchmarks(key SiteOperationKey, benchmarks []storage.DiskBenchmark) error
+
 	// DeleteSiteOperation removes an unstarted operation
 	DeleteSiteOperation(SiteOperationKey) error
 
@@ -1321,6 +1490,9 @@ type CreateSiteShrinkOperationRequest struct {
 	// Used in cases where we recieve an event where the node is being terminated, but may
 	// not have disconnected from the cluster yet.
 	NodeRemoved bool `json:"node_removed"`
+	// Replace indicates that the node is removed to be replaced by a joining node.
+	// The cluster verifies that etcd keeps quorum before and after removing a master node
+	Replace bool `json:"replace,omitempty"`
 }
 
 // CheckAndSetDefaults makes sure the request is correct and fills in some unset
@@ -1352,6 +1524,10 @@ type CreateSiteAppUpdateOperationRequest struct {
 	App string `json:"package"`
 	// StartAgents specifies whether the operation will automatically start the update agents
 	StartAgents bool `json:"start_agents"`
+	// Automatic specifies whether the update has been started by the system
+	// rather than by a user. Automatic updates are only allowed within
+	// the cluster maintenance window
+	Automatic bool `json:"automatic,omitempty"`
 	// Vars are variables specific to this operation
 	Vars storage.OperationVariables `json:"vars"`
 }
@@ -1374,6 +1550,8 @@ type CreateClusterGarbageCollectOperationRequest struct {
 	AccountID string `json:"account_id"`
 	// ClusterName is the name of the cluster
 	ClusterName string `json:"cluster_name"`
+	// Force allows to run garbage collection outside of the cluster maintenance window
+	Force bool `json:"force,omitempty"`
 }
 
 // CreateUpdateEnvarsOperationRequest is a request
@@ -1410,6 +1588,9 @@ type UpdateClusterConfigRequest struct {
 	ClusterKey SiteKey `json:"cluster_key"`
 	// Config specifies the new configuration as JSON-encoded payload
 	Config []byte `json:"config,omitempty"`
+	// DockerStorageDriver optionally specifies the Docker storage driver
+	// to persist in the cluster state after the storage has been migrated
+	DockerStorageDriver string `json:"docker_storage_driver,omitempty"`
 }
 
 // AgentService coordinates install agents that are started on every server
@@ -1447,6 +1628,18 @@ type AgentService interface {
 	// CheckBandwidth executes bandwidth network test in agent cluster
 	CheckBandwidth(context.Context, SiteOperationKey, checks.PingPongGame) (checks.PingPongGameResults, error)
 
+	// CheckPathMTU executes path MTU discovery network test in agent cluster
+	CheckPathMTU(context.Context, SiteOperationKey, checks.PingPongGame) (checks.PingPongGameResults, error)
+
+	// CheckLatency executes latency network test in agent cluster
+	CheckLatency(context.Context, SiteOperationKey, checks.PingPongGame) (checks.PingPongGameResults, error)
+
+	// CheckPacketLoss executes UDP packet loss network test in agent cluster
+	CheckPacketLoss(context.Context, SiteOperationKey, checks.PingPongGame) (checks.PingPongGameResults, error)
+
+	// CheckVXLAN executes overlay network data path test in agent cluster
+	CheckVXLAN(context.Context, SiteOperationKey, checks.PingPongGame) (checks.PingPongGameResults, error)
+
 	// CheckDisks executes disk performance test on the specified node
 	CheckDisks(ctx context.Context, key SiteOperationKey, addr string, req *proto.CheckDisksRequest) (*proto.CheckDisksResponse, error)
 
@@ -1462,6 +1655,14 @@ type AgentService interface {
 	// CompleteAgents sends an operation completed notification to all remote
 	// agents
 	CompleteAgents(context.Context, SiteOperationKey) error
+
+	// GetAgentStatus returns the connection status of agents
+	// for the specified operation
+	GetAgentStatus(context.Context, SiteOperationKey) ([]AgentStatus, error)
+
+	// EvictAgent disconnects the agent specified with addr and removes it
+	// from the specified operation
+	EvictAgent(ctx context.Context, key SiteOperationKey, addr string) error
 }
 
 // NewAccountRequest is a request to create a new account
@@ -1505,6 +1706,12 @@ type NewAPIKeyRequest struct {
 	Token string `json:"token"`
 	// Upsert controls whether existing key should be updated
 	Upsert bool `json:"upsert"`
+	// Roles optionally restricts the key to the subset of the user's roles
+	Roles []string `json:"roles,omitempty"`
+	// Rules optionally restricts the resources and verbs the key can access
+	Rules []teleservices.Rule `json:"rules,omitempty"`
+	// Clusters optionally restricts the key to the specified clusters
+	Clusters []string `json:"clusters,omitempty"`
 }
 
 // NewInstallTokenRequest is a request to generate a one-time install token
@@ -1929,6 +2136,46 @@ type SMTP interface {
 	DeleteSMTPConfig(context.Context, SiteKey) error
 }
 
+// MaintenanceWindows defines the interface to manage cluster maintenance window
+type MaintenanceWindows interface {
+	// GetMaintenanceWindow returns the cluster maintenance window
+	GetMaintenanceWindow(SiteKey) (storage.MaintenanceWindow, error)
+	// UpdateMaintenanceWindow updates the cluster maintenance window
+	UpdateMaintenanceWindow(context.Context, SiteKey, storage.MaintenanceWindow) error
+	// DeleteMaintenanceWindow deletes the cluster maintenance window
+	DeleteMaintenanceWindow(context.Context, SiteKey) error
+}
+
+// NodePools defines the interface to manage cluster node pools
+type NodePools interface {
+	// GetNodePools returns the list of configured node pools
+	GetNodePools(SiteKey) ([]storage.NodePool, error)
+	// UpdateNodePool creates or updates the specified node pool
+	UpdateNodePool(context.Context, SiteKey, storage.NodePool) error
+	// DeleteNodePool deletes the node pool specified with name
+	DeleteNodePool(ctx context.Context, key SiteKey, name string) error
+}
+
+// MFAPolicies defines the interface to manage the cluster second factor policy
+type MFAPolicies interface {
+	// GetMFAPolicy returns the cluster second factor policy
+	GetMFAPolicy(SiteKey) (storage.MFAPolicy, error)
+	// UpdateMFAPolicy updates the cluster second factor policy
+	UpdateMFAPolicy(context.Context, SiteKey, storage.MFAPolicy) error
+	// DeleteMFAPolicy deletes the cluster second factor policy
+	DeleteMFAPolicy(context.Context, SiteKey) error
+}
+
+// PasswordPolicies defines the interface to manage the cluster password policy
+type PasswordPolicies interface {
+	// GetPasswordPolicy returns the cluster password policy
+	GetPasswordPolicy(SiteKey) (storage.PasswordPolicy, error)
+	// UpdatePasswordPolicy updates the cluster password policy
+	UpdatePasswordPolicy(context.Context, SiteKey, storage.PasswordPolicy) error
+	// DeletePasswordPolicy deletes the cluster password policy
+	DeletePasswordPolicy(context.Context, SiteKey) error
+}
+
 // Monitoring defines the interface to manage monitoring and metrics
 type Monitoring interface {
 	// GetAlerts returns the list of configured monitoring alerts
@@ -2080,6 +2327,14 @@ type Identity interface {
 	GetGithubConnectors(key SiteKey, withSecrets bool) ([]teleservices.GithubConnector, error)
 	// DeleteGithubConnector deletes a Github connector by name
 	DeleteGithubConnector(ctx context.Context, key SiteKey, name string) error
+	// UpsertOIDCConnector creates or updates an OIDC connector
+	UpsertOIDCConnector(ctx context.Context, key SiteKey, conn teleservices.OIDCConnector) error
+	// GetOIDCConnector returns an OIDC connector by its name
+	GetOIDCConnector(key SiteKey, name string, withSecrets bool) (teleservices.OIDCConnector, error)
+	// GetOIDCConnectors returns all OIDC connectors
+	GetOIDCConnectors(key SiteKey, withSecrets bool) ([]teleservices.OIDCConnector, error)
+	// DeleteOIDCConnector deletes an OIDC connector by name
+	DeleteOIDCConnector(ctx context.Context, key SiteKey, name string) error
 	// UpsertAuthGateway updates auth gateway configuration
 	UpsertAuthGateway(context.Context, SiteKey, storage.AuthGateway) error
 	// GetAuthGateway returns auth gateway configuration
@@ -2112,8 +2367,42 @@ func (r AuditEventRequest) String() string {
 	return fmt.Sprintf("AuditEvent(Event=%v, Fields=%v)", r.Event, r.Fields)
 }
 
+// GetAuditRecordsRequest describes a request to export audit log records.
+type GetAuditRecordsRequest struct {
+	// SiteKey is the ID of the cluster the request is for.
+	SiteKey
+	// FromIndex, if set, excludes records with lower indexes.
+	FromIndex uint64 `json:"from_index,omitempty"`
+	// Since, if set, excludes records created before it.
+	Since time.Time `json:"since,omitempty"`
+	// Until, if set, excludes records created after it.
+	Until time.Time `json:"until,omitempty"`
+}
+
+// Check validates the audit records request.
+func (r GetAuditRecordsRequest) Check() error {
+	if err := r.SiteKey.Check(); err != nil {
+		return trace.Wrap(err)
+	}
+	if !r.Since.IsZero() && !r.Until.IsZero() && r.Until.Before(r.Since) {
+		return trace.BadParameter("until %v is before since %v", r.Until, r.Since)
+	}
+	return nil
+}
+
+// Filter returns the storage filter for the request.
+func (r GetAuditRecordsRequest) Filter() storage.AuditRecordsFilter {
+	return storage.AuditRecordsFilter{
+		FromIndex: r.FromIndex,
+		Since:     r.Since,
+		Until:     r.Until,
+	}
+}
+
 // Audit provides interface for emitting audit log events.
 type Audit interface {
 	// EmitAuditEvent saves the provided event in the audit log.
 	EmitAuditEvent(context.Context, AuditEventRequest) error
+	// GetAuditRecords returns the hash-chained audit log records.
+	GetAuditRecords(context.Context, GetAuditRecordsRequest) ([]storage.AuditRecord, error)
 }
@@ -35,6 +35,7 @@ import (
 	"github.com/gravitational/gravity/lib/pack"
 	"github.com/gravitational/gravity/lib/storage"
 	"github.com/gravitational/gravity/lib/storage/clusterconfig"
+	"github.com/gravitational/gravity/lib/users"
 
 	"github.com/gravitational/roundtrip"
 	telehttplib "github.com/gravitational/teleport/lib/httplib"
@@ -71,9 +72,36 @@ func NewClient(addr string, params ...ClientParam) (*Client, error) {
 	for _, param := range params {
 		param(client)
 	}
+	// Copy the HTTP client so the transport of a shared client is not modified
+	httpClient := *client.HTTPClient()
+	httpClient.Transport = &secondFactorTransport{next: httpClient.Transport}
+	if err := roundtrip.HTTPClient(&httpClient)(&client.Client); err != nil {
+		return nil, trace.Wrap(err)
+	}
 	return client, nil
 }
 
+// secondFactorTransport passes the second factor token carried by
+// the request context along with the request
+type secondFactorTransport struct {
+	next http.RoundTripper
+}
+
+// RoundTrip executes a single HTTP transaction
+func (t *secondFactorTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := t.next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	token := ops.SecondFactorFromContext(req.Context())
+	if token == "" {
+		return next.RoundTrip(req)
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set(constants.SecondFactorHeader, token)
+	return next.RoundTrip(req)
+}
+
 // BasicAuth sets username and password for HTTP client
 func BasicAuth(username, password string) ClientParam {
 	return func(c *Client) error {
@@ -161,6 +189,15 @@ func (c *Client) UpdateUser(ctx context.Context, req ops.UpdateUserRequest) erro
 	return nil
 }
 
+// UnlockUser unlocks the user locked after too many failed login attempts.
+func (c *Client) UnlockUser(ctx context.Context, req ops.UnlockUserRequest) error {
+	_, err := c.PostJSONWithContext(ctx, c.Endpoint("accounts", req.AccountID, "sites", req.SiteDomain, "users", "unlock"), req)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	return nil
+}
+
 func (c *Client) DeleteLocalUser(name string) error {
 	_, err := c.Delete(c.Endpoint("users", name))
 	if err != nil {
@@ -260,8 +297,8 @@ func (c *Client) CreateSite(req ops.NewSiteRequest) (*ops.Site, error) {
 	return &site, nil
 }
 
-func (c *Client) DeleteSite(siteKey ops.SiteKey) error {
-	_, err := c.Delete(
+func (c *Client) DeleteSite(ctx context.Context, siteKey ops.SiteKey) error {
+	_, err := c.DeleteWithContext(ctx,
 		c.Endpoint(
 			"accounts", siteKey.AccountID, "sites", siteKey.SiteDomain))
 	if err != nil {
@@ -535,7 +572,7 @@ func (c *Client) SiteExpandOperationStart(key ops.SiteOperationKey) error {
 }
 
 func (c *Client) CreateSiteUninstallOperation(ctx context.Context, req ops.CreateSiteUninstallOperationRequest) (*ops.SiteOperationKey, error) {
-	out, err := c.PostJSON(c.Endpoint("accounts", req.AccountID, "sites", req.SiteDomain, "operations", "uninstall"), req)
+	out, err := c.PostJSONWithContext(ctx, c.Endpoint("accounts", req.AccountID, "sites", req.SiteDomain, "operations", "uninstall"), req)
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
@@ -548,7 +585,7 @@ func (c *Client) CreateSiteUninstallOperation(ctx context.Context, req ops.Creat
 
 // CreateClusterGarbageCollectOperation creates a new garbage collection operation in the cluster
 func (c *Client) CreateClusterGarbageCollectOperation(ctx context.Context, req ops.CreateClusterGarbageCollectOperationRequest) (*ops.SiteOperationKey, error) {
-	out, err := c.PostJSON(c.Endpoint("accounts", req.AccountID, "sites", req.ClusterName, "operations", "gc"), req)
+	out, err := c.PostJSONWithContext(ctx, c.Endpoint("accounts", req.AccountID, "sites", req.ClusterName, "operations", "gc"), req)
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
@@ -562,7 +599,7 @@ func (c *Client) CreateClusterGarbageCollectOperation(ctx context.Context, req o
 
 // CreateUpdateEnvarsOperation creates a new operation to update cluster runtime environment variables
 func (c *Client) CreateUpdateEnvarsOperation(ctx context.Context, req ops.CreateUpdateEnvarsOperationRequest) (*ops.SiteOperationKey, error) {
-	out, err := c.PostJSON(c.Endpoint("accounts", req.ClusterKey.AccountID, "sites", req.ClusterKey.SiteDomain, "operations", "envars"), req)
+	out, err := c.PostJSONWithContext(ctx, c.Endpoint("accounts", req.ClusterKey.AccountID, "sites", req.ClusterKey.SiteDomain, "operations", "envars"), req)
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
@@ -576,7 +613,7 @@ func (c *Client) CreateUpdateEnvarsOperation(ctx context.Context, req ops.Create
 
 // CreateUpdateConfigOperation creates a new operation to update cluster configuration
 func (c *Client) CreateUpdateConfigOperation(ctx context.Context, req ops.CreateUpdateConfigOperationRequest) (*ops.SiteOperationKey, error) {
-	out, err := c.PostJSON(c.Endpoint("accounts", req.ClusterKey.AccountID, "sites", req.ClusterKey.SiteDomain, "operations", "config"), req)
+	out, err := c.PostJSONWithContext(ctx, c.Endpoint("accounts", req.ClusterKey.AccountID, "sites", req.ClusterKey.SiteDomain, "operations", "config"), req)
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
@@ -597,7 +634,7 @@ func (c *Client) SiteUninstallOperationStart(req ops.SiteOperationKey) error {
 }
 
 func (c *Client) CreateSiteExpandOperation(ctx context.Context, req ops.CreateSiteExpandOperationRequest) (*ops.SiteOperationKey, error) {
-	out, err := c.PostJSON(c.Endpoint("accounts", req.AccountID, "sites", req.SiteDomain, "operations", "expand"), req)
+	out, err := c.PostJSONWithContext(ctx, c.Endpoint("accounts", req.AccountID, "sites", req.SiteDomain, "operations", "expand"), req)
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
@@ -609,7 +646,7 @@ func (c *Client) CreateSiteExpandOperation(ctx context.Context, req ops.CreateSi
 }
 
 func (c *Client) CreateSiteShrinkOperation(ctx context.Context, req ops.CreateSiteShrinkOperationRequest) (*ops.SiteOperationKey, error) {
-	out, err := c.PostJSON(c.Endpoint("accounts", req.AccountID, "sites", req.SiteDomain, "operations", "shrink"), req)
+	out, err := c.PostJSONWithContext(ctx, c.Endpoint("accounts", req.AccountID, "sites", req.SiteDomain, "operations", "shrink"), req)
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
@@ -621,7 +658,7 @@ func (c *Client) CreateSiteShrinkOperation(ctx context.Context, req ops.CreateSi
 }
 
 func (c *Client) CreateSiteAppUpdateOperation(ctx context.Context, req ops.CreateSiteAppUpdateOperationRequest) (*ops.SiteOperationKey, error) {
-	out, err := c.PostJSON(c.Endpoint("accounts", req.AccountID, "sites", req.SiteDomain, "operations", "update"), req)
+	out, err := c.PostJSONWithContext(ctx, c.Endpoint("accounts", req.AccountID, "sites", req.SiteDomain, "operations", "update"), req)
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
@@ -667,6 +704,26 @@ func (c *Client) GetSiteOperationProgress(key ops.SiteOperationKey) (*ops.Progre
 	return &progressEntry, nil
 }
 
+func (c *Client) GetOperationAgents(key ops.SiteOperationKey) ([]ops.AgentStatus, error) {
+	out, err := c.Get(c.Endpoint("accounts", key.AccountID, "sites", key.SiteDomain, "operations", "common", key.OperationID, "agents"), url.Values{})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var agents []ops.AgentStatus
+	if err := json.Unmarshal(out.Bytes(), &agents); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return agents, nil
+}
+
+func (c *Client) EvictOperationAgent(key ops.SiteOperationKey, addr string) error {
+	_, err := c.Delete(c.Endpoint("accounts", key.AccountID, "sites", key.SiteDomain, "operations", "common", key.OperationID, "agents", addr))
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	return nil
+}
+
 func (c *Client) CreateProgressEntry(key ops.SiteOperationKey, entry ops.ProgressEntry) error {
 	_, err := c.PostJSON(c.Endpoint("accounts", key.AccountID, "sites", key.SiteDomain, "operations", "common", key.OperationID, "progress"), entry)
 	if err != nil {
@@ -842,6 +899,17 @@ func (c *Client) DeleteSiteOperation(key ops.SiteOperationKey) error {
 	return nil
 }
 
+// SaveDiskBenchmarks stores the results of disk benchmarks executed
+// during the specified install or expand operation
+func (c *Client) SaveDiskBenchmarks(key ops.SiteOperationKey, benchmarks []storage.DiskBenchmark) error {
+	_, err := c.PutJSON(c.Endpoint(
+		"accounts", key.AccountID, "sites", key.SiteDomain, "operations", "common", key.OperationID, "benchmarks"), benchmarks)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	return nil
+}
+
 // SetOperationState moves operation into specified state
 func (c *Client) SetOperationState(key ops.SiteOperationKey, req ops.SetOperationStateRequest) error {
 	_, err := c.PutJSON(c.Endpoint(
@@ -1043,6 +1111,166 @@ func (c *Client) DeleteSMTPConfig(ctx context.Context, key ops.SiteKey) error {
 	return trace.Wrap(err)
 }
 
+// GetMaintenanceWindow returns the cluster maintenance window
+func (c *Client) GetMaintenanceWindow(key ops.SiteKey) (storage.MaintenanceWindow, error) {
+	response, err := c.Get(c.Endpoint(
+		"accounts", key.AccountID, "sites", key.SiteDomain, "maintenancewindow"), url.Values{})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return storage.UnmarshalMaintenanceWindow(response.Bytes())
+}
+
+// UpdateMaintenanceWindow updates the cluster maintenance window
+func (c *Client) UpdateMaintenanceWindow(ctx context.Context, key ops.SiteKey, window storage.MaintenanceWindow) error {
+	bytes, err := storage.MarshalMaintenanceWindow(window)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	_, err = c.PutJSON(c.Endpoint("accounts", key.AccountID, "sites", key.SiteDomain, "maintenancewindow"),
+		&UpsertResourceRawReq{Resource: bytes})
+	return trace.Wrap(err)
+}
+
+// DeleteMaintenanceWindow deletes the cluster maintenance window
+func (c *Client) DeleteMaintenanceWindow(ctx context.Context, key ops.SiteKey) error {
+	_, err := c.Delete(c.Endpoint("accounts", key.AccountID, "sites", key.SiteDomain, "maintenancewindow"))
+	return trace.Wrap(err)
+}
+
+// CreateAccessRequest creates a request for temporary access to roles
+func (c *Client) CreateAccessRequest(ctx context.Context, req ops.CreateAccessRequestRequest) (*storage.AccessRequest, error) {
+	out, err := c.PostJSON(c.Endpoint("accounts", req.AccountID, "sites", req.SiteDomain, "accessrequests"), req)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var request storage.AccessRequest
+	if err := json.Unmarshal(out.Bytes(), &request); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return &request, nil
+}
+
+// GetAccessRequests returns the access requests in the cluster
+func (c *Client) GetAccessRequests(key ops.SiteKey) ([]storage.AccessRequest, error) {
+	out, err := c.Get(c.Endpoint("accounts", key.AccountID, "sites", key.SiteDomain, "accessrequests"), url.Values{})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var requests []storage.AccessRequest
+	if err := json.Unmarshal(out.Bytes(), &requests); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return requests, nil
+}
+
+// ReviewAccessRequest approves or denies a pending access request
+func (c *Client) ReviewAccessRequest(ctx context.Context, req ops.ReviewAccessRequestRequest) (*storage.AccessRequest, error) {
+	out, err := c.PutJSON(c.Endpoint("accounts", req.AccountID, "sites", req.SiteDomain, "accessrequests", req.ID), req)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var request storage.AccessRequest
+	if err := json.Unmarshal(out.Bytes(), &request); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return &request, nil
+}
+
+// GetMFAPolicy returns the cluster second factor policy
+func (c *Client) GetMFAPolicy(key ops.SiteKey) (storage.MFAPolicy, error) {
+	response, err := c.Get(c.Endpoint(
+		"accounts", key.AccountID, "sites", key.SiteDomain, "mfapolicy"), url.Values{})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return storage.UnmarshalMFAPolicy(response.Bytes())
+}
+
+// UpdateMFAPolicy updates the cluster second factor policy
+func (c *Client) UpdateMFAPolicy(ctx context.Context, key ops.SiteKey, policy storage.MFAPolicy) error {
+	bytes, err := storage.MarshalMFAPolicy(policy)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	_, err = c.PutJSONWithContext(ctx, c.Endpoint("accounts", key.AccountID, "sites", key.SiteDomain, "mfapolicy"),
+		&UpsertResourceRawReq{Resource: bytes})
+	return trace.Wrap(err)
+}
+
+// DeleteMFAPolicy deletes the cluster second factor policy
+func (c *Client) DeleteMFAPolicy(ctx context.Context, key ops.SiteKey) error {
+	_, err := c.DeleteWithContext(ctx, c.Endpoint("accounts", key.AccountID, "sites", key.SiteDomain, "mfapolicy"))
+	return trace.Wrap(err)
+}
+
+// GetPasswordPolicy returns the cluster password policy
+func (c *Client) GetPasswordPolicy(key ops.SiteKey) (storage.PasswordPolicy, error) {
+	response, err := c.Get(c.Endpoint(
+		"accounts", key.AccountID, "sites", key.SiteDomain, "passwordpolicy"), url.Values{})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return storage.UnmarshalPasswordPolicy(response.Bytes())
+}
+
+// UpdatePasswordPolicy updates the cluster password policy
+func (c *Client) UpdatePasswordPolicy(ctx context.Context, key ops.SiteKey, policy storage.PasswordPolicy) error {
+	bytes, err := storage.MarshalPasswordPolicy(policy)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	_, err = c.PutJSONWithContext(ctx, c.Endpoint("accounts", key.AccountID, "sites", key.SiteDomain, "passwordpolicy"),
+		&UpsertResourceRawReq{Resource: bytes})
+	return trace.Wrap(err)
+}
+
+// DeletePasswordPolicy deletes the cluster password policy
+func (c *Client) DeletePasswordPolicy(ctx context.Context, key ops.SiteKey) error {
+	_, err := c.DeleteWithContext(ctx, c.Endpoint("accounts", key.AccountID, "sites", key.SiteDomain, "passwordpolicy"))
+	return trace.Wrap(err)
+}
+
+// GetNodePools returns the list of configured node pools
+func (c *Client) GetNodePools(key ops.SiteKey) ([]storage.NodePool, error) {
+	response, err := c.Get(c.Endpoint(
+		"accounts", key.AccountID, "sites", key.SiteDomain, "nodepools"), url.Values{})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var items []json.RawMessage
+	if err = json.Unmarshal(response.Bytes(), &items); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	pools := make([]storage.NodePool, len(items))
+	for i, item := range items {
+		pool, err := storage.UnmarshalNodePool(item)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		pools[i] = pool
+	}
+	return pools, nil
+}
+
+// UpdateNodePool creates or updates the specified node pool
+func (c *Client) UpdateNodePool(ctx context.Context, key ops.SiteKey, pool storage.NodePool) error {
+	bytes, err := storage.MarshalNodePool(pool)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	_, err = c.PutJSON(c.Endpoint("accounts", key.AccountID, "sites", key.SiteDomain,
+		"nodepools", pool.GetName()),
+		&UpsertResourceRawReq{Resource: bytes})
+	return trace.Wrap(err)
+}
+
+// DeleteNodePool deletes the node pool specified with name
+func (c *Client) DeleteNodePool(ctx context.Context, key ops.SiteKey, name string) error {
+	_, err := c.Delete(c.Endpoint("accounts", key.AccountID, "sites", key.SiteDomain, "nodepools", name))
+	return trace.Wrap(err)
+}
+
 // GetAlerts returns a list of monitoring alerts for the cluster
 func (c *Client) GetAlerts(key ops.SiteKey) ([]storage.Alert, error) {
 	response, err := c.Get(c.Endpoint(
@@ -1323,7 +1551,7 @@ func (c *Client) GetClusterCertificate(key ops.SiteKey, withSecrets bool) (*ops.
 
 // UpdateClusterCertificate updates the cluster certificate
 func (c *Client) UpdateClusterCertificate(ctx context.Context, req ops.UpdateCertificateRequest) (*ops.ClusterCertificate, error) {
-	out, err := c.PostJSON(c.Endpoint(
+	out, err := c.PostJSONWithContext(ctx, c.Endpoint(
 		"accounts", req.AccountID, "sites", req.SiteDomain, "certificate"), req)
 	if err != nil {
 		return nil, trace.Wrap(err)
@@ -1337,11 +1565,32 @@ func (c *Client) UpdateClusterCertificate(ctx context.Context, req ops.UpdateCer
 
 // DeleteClusterCertificate deletes the cluster certificate
 func (c *Client) DeleteClusterCertificate(ctx context.Context, key ops.SiteKey) error {
-	_, err := c.Delete(c.Endpoint(
+	_, err := c.DeleteWithContext(ctx, c.Endpoint(
 		"accounts", key.AccountID, "sites", key.SiteDomain, "certificate"))
 	return trace.Wrap(err)
 }
 
+// GetRPCCertificateAuthority returns the CA certificate bundle trusted by the cluster RPC agents
+func (c *Client) GetRPCCertificateAuthority(key ops.SiteKey) (*ops.RPCCertificateAuthority, error) {
+	out, err := c.Get(c.Endpoint(
+		"accounts", key.AccountID, "sites", key.SiteDomain, "rpc", "ca"), url.Values{})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var ca ops.RPCCertificateAuthority
+	if err := json.Unmarshal(out.Bytes(), &ca); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return &ca, nil
+}
+
+// UpdateRPCCredentials replaces the cluster RPC agent credentials
+func (c *Client) UpdateRPCCredentials(ctx context.Context, req ops.UpdateRPCCredentialsRequest) error {
+	_, err := c.PutJSONWithContext(ctx, c.Endpoint(
+		"accounts", req.ClusterKey.AccountID, "sites", req.ClusterKey.SiteDomain, "rpc", "credentials"), req)
+	return trace.Wrap(err)
+}
+
 // StepDown asks the process to pause its leader election heartbeat so it can
 // give up its leadership
 func (c *Client) StepDown(key ops.SiteKey) error {
@@ -1466,6 +1715,20 @@ func (c *Client) CreateUserReset(ctx context.Context, req ops.CreateUserResetReq
 	return &resetToken, nil
 }
 
+// CheckUserAccess explains whether the user can perform the specified action
+// on the resource kind.
+func (c *Client) CheckUserAccess(ctx context.Context, req ops.CheckUserAccessRequest) (*users.AccessDecision, error) {
+	out, err := c.PostJSON(c.Endpoint("accounts", req.AccountID, "sites", req.SiteDomain, "users", "access"), req)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var decision users.AccessDecision
+	if err := json.Unmarshal(out.Bytes(), &decision); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return &decision, nil
+}
+
 // UpsertGithubConnector creates or updates a Github connector
 func (c *Client) UpsertGithubConnector(ctx context.Context, key ops.SiteKey, connector teleservices.GithubConnector) error {
 	data, err := teleservices.GetGithubConnectorMarshaler().Marshal(connector)
@@ -1530,6 +1793,70 @@ func (c *Client) DeleteGithubConnector(ctx context.Context, key ops.SiteKey, nam
 	return trace.Wrap(err)
 }
 
+// UpsertOIDCConnector creates or updates an OIDC connector
+func (c *Client) UpsertOIDCConnector(ctx context.Context, key ops.SiteKey, connector teleservices.OIDCConnector) error {
+	data, err := teleservices.GetOIDCConnectorMarshaler().MarshalOIDCConnector(connector)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	_, err = c.PostJSON(c.Endpoint("accounts", key.AccountID, "sites", key.SiteDomain, "oidc", "connectors"),
+		&UpsertResourceRawReq{
+			Resource: data,
+		})
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	return nil
+}
+
+// GetOIDCConnector returns an OIDC connector by name
+//
+// Returned connector exclude client secret unless withSecrets is true.
+func (c *Client) GetOIDCConnector(key ops.SiteKey, name string, withSecrets bool) (teleservices.OIDCConnector, error) {
+	if name == "" {
+		return nil, trace.BadParameter("missing connector name")
+	}
+	out, err := c.Get(c.Endpoint("accounts", key.AccountID, "sites", key.SiteDomain, "oidc", "connectors", name),
+		url.Values{constants.WithSecretsParam: []string{fmt.Sprintf("%t", withSecrets)}})
+	if err != nil {
+		return nil, err
+	}
+	return teleservices.GetOIDCConnectorMarshaler().UnmarshalOIDCConnector(out.Bytes())
+}
+
+// GetOIDCConnectors returns all OIDC connectors
+//
+// Returned connectors exclude client secret unless withSecrets is true.
+func (c *Client) GetOIDCConnectors(key ops.SiteKey, withSecrets bool) ([]teleservices.OIDCConnector, error) {
+	out, err := c.Get(c.Endpoint("accounts", key.AccountID, "sites", key.SiteDomain, "oidc", "connectors"),
+		url.Values{constants.WithSecretsParam: []string{fmt.Sprintf("%t", withSecrets)}})
+	if err != nil {
+		return nil, err
+	}
+	var items []json.RawMessage
+	if err := json.Unmarshal(out.Bytes(), &items); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	connectors := make([]teleservices.OIDCConnector, len(items))
+	for i, raw := range items {
+		connector, err := teleservices.GetOIDCConnectorMarshaler().UnmarshalOIDCConnector(raw)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		connectors[i] = connector
+	}
+	return connectors, nil
+}
+
+// DeleteOIDCConnector deletes an OIDC connector by name
+func (c *Client) DeleteOIDCConnector(ctx context.Context, key ops.SiteKey, name string) error {
+	if name == "" {
+		return trace.BadParameter("missing connector name")
+	}
+	_, err := c.Delete(c.Endpoint("accounts", key.AccountID, "sites", key.SiteDomain, "oidc", "connectors", name))
+	return trace.Wrap(err)
+}
+
 // UpsertAuthGateway updates auth gateway configuration.
 func (c *Client) UpsertAuthGateway(ctx context.Context, key ops.SiteKey, gw storage.AuthGateway) error {
 	bytes, err := storage.MarshalAuthGateway(gw)
@@ -1587,6 +1914,29 @@ func (c *Client) EmitAuditEvent(ctx context.Context, req ops.AuditEventRequest)
 	return nil
 }
 
+// GetAuditRecords returns the hash-chained audit log records.
+func (c *Client) GetAuditRecords(ctx context.Context, req ops.GetAuditRecordsRequest) ([]storage.AuditRecord, error) {
+	query := url.Values{}
+	if req.FromIndex != 0 {
+		query.Set("from_index", strconv.FormatUint(req.FromIndex, 10))
+	}
+	if !req.Since.IsZero() {
+		query.Set("since", req.Since.Format(time.RFC3339Nano))
+	}
+	if !req.Until.IsZero() {
+		query.Set("until", req.Until.Format(time.RFC3339Nano))
+	}
+	out, err := c.Get(c.Endpoint("accounts", req.AccountID, "sites", req.SiteDomain, "audit", "records"), query)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var records []storage.AuditRecord
+	if err := json.Unmarshal(out.Bytes(), &records); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return records, nil
+}
+
 // PostJSON issues HTTP POST request to the server with the provided JSON data
 func (c *Client) PostJSON(endpoint string, data interface{}) (*roundtrip.Response, error) {
 	return telehttplib.ConvertResponse(c.Client.PostJSON(context.TODO(), endpoint, data))
@@ -1603,6 +1953,12 @@ func (c *Client) PutJSON(endpoint string, data interface{}) (*roundtrip.Response
 	return telehttplib.ConvertResponse(c.Client.PutJSON(context.TODO(), endpoint, data))
 }
 
+// PutJSONWithContext issues HTTP PUT request to the server with the provided JSON data
+// bounded by the specified context
+func (c *Client) PutJSONWithContext(ctx context.Context, endpoint string, data interface{}) (*roundtrip.Response, error) {
+	return telehttplib.ConvertResponse(c.Client.PutJSON(ctx, endpoint, data))
+}
+
 // Get issues HTTP GET request to the server
 func (c *Client) Get(endpoint string, params url.Values) (*roundtrip.Response, error) {
 	return telehttplib.ConvertResponse(c.Client.Get(context.TODO(), endpoint, params))
@@ -1632,6 +1988,12 @@ func (c *Client) Delete(endpoint string) (*roundtrip.Response, error) {
 	return telehttplib.ConvertResponse(c.Client.Delete(context.TODO(), endpoint))
 }
 
+// DeleteWithContext issues HTTP DELETE request to the server
+// bounded by the specified context
+func (c *Client) DeleteWithContext(ctx context.Context, endpoint string) (*roundtrip.Response, error) {
+	return telehttplib.ConvertResponse(c.Client.Delete(ctx, endpoint))
+}
+
 // DeleteWithParams issues HTTP DELETE request to the server
 func (c *Client) DeleteWithParams(endpoint string, params url.Values) (*roundtrip.Response, error) {
 	return telehttplib.ConvertResponse(c.Client.DeleteWithParams(context.TODO(),
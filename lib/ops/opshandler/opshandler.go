@@ -34,6 +34,7 @@ import (
 	"github.com/gravitational/gravity/lib/ops"
 	"github.com/gravitational/gravity/lib/ops/events"
 	"github.com/gravitational/gravity/lib/ops/opsclient"
+	"github.com/gravitational/gravity/lib/ops/watch"
 	"github.com/gravitational/gravity/lib/pack"
 	"github.com/gravitational/gravity/lib/schema"
 	"github.com/gravitational/gravity/lib/storage"
@@ -69,6 +70,8 @@ type WebHandlerConfig struct {
 	Devmode bool
 	// PublicAdvertiseAddr is the process public advertise address
 	PublicAdvertiseAddr teleutils.NetAddr
+	// RateLimiter optionally limits the requests of each user, API key and cluster
+	RateLimiter *httplib.RateLimiter
 }
 
 // CheckAndSetDefaults validates the config and sets some defaults.
@@ -126,7 +129,7 @@ func NewWebHandler(cfg WebHandlerConfig) (*WebHandler, error) {
 
 	// Applications API
 	h.GET("/portal/v1/apps", h.needsAuth(h.getApps))
-	h.GET("/portal/v1/gravity", h.needsAuth(h.getGravityBinary))
+	h.GET("/portal/v1/gravity", h.needsAuthTransfer(h.getGravityBinary))
 
 	// Accounts API
 	h.POST("/portal/v1/accounts", h.needsAuth(h.createAccount))
@@ -153,6 +156,8 @@ func NewWebHandler(cfg WebHandlerConfig) (*WebHandler, error) {
 	// Tokens API
 	h.POST("/portal/v1/tokens/install", h.needsAuth(h.createInstallToken))
 	h.POST("/portal/v1/accounts/:account_id/sites/:site_domain/tokens/userresets", h.needsAuth(h.resetUser))
+	h.POST("/portal/v1/accounts/:account_id/sites/:site_domain/users/access", h.needsAuth(h.checkUserAccess))
+	h.POST("/portal/v1/accounts/:account_id/sites/:site_domain/users/unlock", h.needsAuth(h.unlockUser))
 	h.POST("/portal/v1/accounts/:account_id/sites/:site_domain/tokens/provision", h.needsAuth(h.createProvisioningToken))
 	h.GET("/portal/v1/accounts/:account_id/sites/:site_domain/tokens/expand", h.needsAuth(h.getExpandToken))
 	h.GET("/portal/v1/accounts/:account_id/sites/:site_domain/tokens/trustedcluster", h.needsAuth(h.getTrustedClusterToken))
@@ -163,7 +168,7 @@ func NewWebHandler(cfg WebHandlerConfig) (*WebHandler, error) {
 	h.DELETE("/portal/v1/accounts/:account_id/sites/:site_domain", h.needsAuth(h.deleteSite))
 	h.GET("/portal/v1/accounts/:account_id/sites/:site_domain", h.needsAuth(h.getSite))
 	h.GET("/portal/v1/accounts/:account_id/sites", h.needsAuth(h.getSites))
-	h.GET("/portal/v1/accounts/:account_id/sites/:site_domain/report", h.needsAuth(h.getSiteReport))
+	h.GET("/portal/v1/accounts/:account_id/sites/:site_domain/report", h.needsAuthTransfer(h.getSiteReport))
 	h.POST("/portal/v1/accounts/:account_id/sites/:site_domain/deactivate", h.needsAuth(h.deactivateSite))
 	h.POST("/portal/v1/accounts/:account_id/sites/:site_domain/activate", h.needsAuth(h.activateSite))
 	h.POST("/portal/v1/accounts/:account_id/sites/:site_domain/complete", h.needsAuth(h.completeFinalInstallStep))
@@ -191,6 +196,10 @@ func NewWebHandler(cfg WebHandlerConfig) (*WebHandler, error) {
 	h.POST("/portal/v1/accounts/:account_id/sites/:site_domain/certificate", h.needsAuth(h.updateClusterCert))
 	h.DELETE("/portal/v1/accounts/:account_id/sites/:site_domain/certificate", h.needsAuth(h.deleteClusterCert))
 
+	// RPC credentials API
+	h.GET("/portal/v1/accounts/:account_id/sites/:site_domain/rpc/ca", h.needsAuth(h.getRPCCertificateAuthority))
+	h.PUT("/portal/v1/accounts/:account_id/sites/:site_domain/rpc/credentials", h.needsAuth(h.updateRPCCredentials))
+
 	// Prechecks API
 	h.POST("/portal/v1/accounts/:account_id/sites/:site_domain/prechecks", h.needsAuth(h.validateServers))
 
@@ -230,13 +239,17 @@ func NewWebHandler(cfg WebHandlerConfig) (*WebHandler, error) {
 	// update install/expand operation state
 	h.GET("/portal/v1/accounts/:account_id/sites/:site_domain/operations/common/:operation_id", h.needsAuth(h.getSiteOperation))
 	h.DELETE("/portal/v1/accounts/:account_id/sites/:site_domain/operations/common/:operation_id", h.needsAuth(h.deleteOperation))
-	h.GET("/portal/v1/accounts/:account_id/sites/:site_domain/operations/common/:operation_id/logs", h.needsAuth(h.getSiteOperationLogs))
+	h.GET("/portal/v1/accounts/:account_id/sites/:site_domain/operations/common/:operation_id/logs", h.needsAuthStream(h.getSiteOperationLogs))
 	h.POST("/portal/v1/accounts/:account_id/sites/:site_domain/operations/common/:operation_id/logs/entry", h.needsAuth(h.createLogEntry))
-	h.POST("/portal/v1/accounts/:account_id/sites/:site_domain/operations/common/:operation_id/logs", h.needsAuth(h.streamOperationLogs))
+	h.POST("/portal/v1/accounts/:account_id/sites/:site_domain/operations/common/:operation_id/logs", h.needsAuthStream(h.streamOperationLogs))
 	h.GET("/portal/v1/accounts/:account_id/sites/:site_domain/operations/common/:operation_id/progress", h.needsAuth(h.getSiteOperationProgress))
+	h.GET("/portal/v1/accounts/:account_id/sites/:site_domain/events", h.needsAuthStream(h.getClusterEvents))
 	h.POST("/portal/v1/accounts/:account_id/sites/:site_domain/operations/common/:operation_id/progress", h.needsAuth(h.createProgressEntry))
-	h.GET("/portal/v1/accounts/:account_id/sites/:site_domain/operations/common/:operation_id/crash-report", h.needsAuth(h.getSiteOperationCrashReport))
+	h.GET("/portal/v1/accounts/:account_id/sites/:site_domain/operations/common/:operation_id/crash-report", h.needsAuthTransfer(h.getSiteOperationCrashReport))
+	h.GET("/portal/v1/accounts/:account_id/sites/:site_domain/operations/common/:operation_id/agents", h.needsAuth(h.getOperationAgents))
+	h.DELETE("/portal/v1/accounts/:account_id/sites/:site_domain/operations/common/:operation_id/agents/:addr", h.needsAuth(h.evictOperationAgent))
 	h.PUT("/portal/v1/accounts/:account_id/sites/:site_domain/operations/common/:operation_id/complete", h.needsAuth(h.completeSiteOperation))
+	h.PUT("/portal/v1/accounts/:account_id/sites/:site_domain/operations/common/:operation_id/benchmarks", h.needsAuth(h.saveDiskBenchmarks))
 	h.POST("/portal/v1/accounts/:account_id/sites/:site_domain/operations/common/:operation_id/plan", h.needsAuth(h.createOperationPlan))
 	h.POST("/portal/v1/accounts/:account_id/sites/:site_domain/operations/common/:operation_id/plan/changelog", h.needsAuth(h.createOperationPlanChange))
 	h.GET("/portal/v1/accounts/:account_id/sites/:site_domain/operations/common/:operation_id/plan", h.needsAuth(h.getOperationPlan))
@@ -254,6 +267,29 @@ func NewWebHandler(cfg WebHandlerConfig) (*WebHandler, error) {
 	h.PUT("/portal/v1/accounts/:account_id/sites/:site_domain/smtp", h.needsAuth(h.updateSMTPConfig))
 	h.DELETE("/portal/v1/accounts/:account_id/sites/:site_domain/smtp", h.needsAuth(h.deleteSMTPConfig))
 
+	// maintenance window
+	h.GET("/portal/v1/accounts/:account_id/sites/:site_domain/maintenancewindow", h.needsAuth(h.getMaintenanceWindow))
+	h.PUT("/portal/v1/accounts/:account_id/sites/:site_domain/maintenancewindow", h.needsAuth(h.updateMaintenanceWindow))
+	h.DELETE("/portal/v1/accounts/:account_id/sites/:site_domain/maintenancewindow", h.needsAuth(h.deleteMaintenanceWindow))
+
+	// second factor policy
+	// access requests
+	h.POST("/portal/v1/accounts/:account_id/sites/:site_domain/accessrequests", h.needsAuth(h.createAccessRequest))
+	h.GET("/portal/v1/accounts/:account_id/sites/:site_domain/accessrequests", h.needsAuth(h.getAccessRequests))
+	h.PUT("/portal/v1/accounts/:account_id/sites/:site_domain/accessrequests/:id", h.needsAuth(h.reviewAccessRequest))
+
+	h.GET("/portal/v1/accounts/:account_id/sites/:site_domain/mfapolicy", h.needsAuth(h.getMFAPolicy))
+	h.PUT("/portal/v1/accounts/:account_id/sites/:site_domain/mfapolicy", h.needsAuth(h.updateMFAPolicy))
+	h.DELETE("/portal/v1/accounts/:account_id/sites/:site_domain/mfapolicy", h.needsAuth(h.deleteMFAPolicy))
+	h.GET("/portal/v1/accounts/:account_id/sites/:site_domain/passwordpolicy", h.needsAuth(h.getPasswordPolicy))
+	h.PUT("/portal/v1/accounts/:account_id/sites/:site_domain/passwordpolicy", h.needsAuth(h.updatePasswordPolicy))
+	h.DELETE("/portal/v1/accounts/:account_id/sites/:site_domain/passwordpolicy", h.needsAuth(h.deletePasswordPolicy))
+
+	// node pools
+	h.GET("/portal/v1/accounts/:account_id/sites/:site_domain/nodepools", h.needsAuth(h.getNodePools))
+	h.PUT("/portal/v1/accounts/:account_id/sites/:site_domain/nodepools/:name", h.needsAuth(h.updateNodePool))
+	h.DELETE("/portal/v1/accounts/:account_id/sites/:site_domain/nodepools/:name", h.needsAuth(h.deleteNodePool))
+
 	// monitoring
 	h.GET("/portal/v1/accounts/:account_id/sites/:site_domain/monitoring/alerts", h.needsAuth(h.getAlerts))
 	h.PUT("/portal/v1/accounts/:account_id/sites/:site_domain/monitoring/alerts/:name", h.needsAuth(h.updateAlert))
@@ -284,7 +320,7 @@ func NewWebHandler(cfg WebHandlerConfig) (*WebHandler, error) {
 	h.GET("/portal/v1/accounts/:account_id/sites/:site_domain/endpoints", h.needsAuth(h.getApplicationEndpoints))
 
 	// app installer
-	h.GET("/portal/v1/accounts/:account_id/apps/:repository_id/:package_name/:version/installer", h.needsAuth(h.getAppInstaller))
+	h.GET("/portal/v1/accounts/:account_id/apps/:repository_id/:package_name/:version/installer", h.needsAuthTransfer(h.getAppInstaller))
 
 	// web helpers - special functions for the UI
 	h.GET("/portal/v1/webhelpers/accounts/:account_id/sites/:site_domain/operations/last/:operation_type", h.needsAuth(h.getLastOperation))
@@ -299,6 +335,16 @@ func NewWebHandler(cfg WebHandlerConfig) (*WebHandler, error) {
 	h.DELETE("/portal/v1/accounts/:account_id/sites/:site_domain/github/connectors/:id",
 		h.needsAuth(h.deleteGithubConnector))
 
+	// OIDC connector handlers
+	h.POST("/portal/v1/accounts/:account_id/sites/:site_domain/oidc/connectors",
+		h.needsAuth(h.upsertOIDCConnector))
+	h.GET("/portal/v1/accounts/:account_id/sites/:site_domain/oidc/connectors/:id",
+		h.needsAuth(h.getOIDCConnector))
+	h.GET("/portal/v1/accounts/:account_id/sites/:site_domain/oidc/connectors",
+		h.needsAuth(h.getOIDCConnectors))
+	h.DELETE("/portal/v1/accounts/:account_id/sites/:site_domain/oidc/connectors/:id",
+		h.needsAuth(h.deleteOIDCConnector))
+
 	// user handlers
 	h.POST("/portal/v1/accounts/:account_id/sites/:site_domain/users",
 		h.needsAuth(h.upsertUser))
@@ -328,6 +374,8 @@ func NewWebHandler(cfg WebHandlerConfig) (*WebHandler, error) {
 	// audit log events
 	h.POST("/portal/v1/accounts/:account_id/sites/:site_domain/events",
 		h.needsAuth(h.emitAuditEvent))
+	h.GET("/portal/v1/accounts/:account_id/sites/:site_domain/audit/records",
+		h.needsAuth(h.getAuditRecords))
 
 	return h, nil
 }
@@ -451,6 +499,27 @@ func (h *WebHandler) resetUser(w http.ResponseWriter, r *http.Request, p httprou
 	return nil
 }
 
+/*  checkUserAccess explains whether the user can perform the specified action
+
+    POST /portal/v1/accounts/:account_id/sites/:site_domain/users/access
+
+    Success Response:
+
+      users.AccessDecision
+*/
+func (h *WebHandler) checkUserAccess(w http.ResponseWriter, r *http.Request, p httprouter.Params, context *HandlerContext) error {
+	var req ops.CheckUserAccessRequest
+	if err := telehttplib.ReadJSON(r, &req); err != nil {
+		return trace.Wrap(err)
+	}
+	decision, err := context.Operator.CheckUserAccess(r.Context(), req)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	roundtrip.ReplyJSON(w, http.StatusOK, decision)
+	return nil
+}
+
 /*  createUserInvite creates a new invite token for a user.
 
     POST /portal/v1/accounts/:account_id/sites/:site_domain/usertokens/invites
@@ -657,6 +726,23 @@ func (h *WebHandler) updateUser(w http.ResponseWriter, r *http.Request, p httpro
 	return nil
 }
 
+/* unlockUser unlocks the user locked after too many failed login attempts.
+
+   POST /portal/v1/accounts/:account_id/sites/:site_domain/users/unlock
+*/
+func (h *WebHandler) unlockUser(w http.ResponseWriter, r *http.Request, p httprouter.Params, context *HandlerContext) error {
+	var req ops.UnlockUserRequest
+	if err := telehttplib.ReadJSON(r, &req); err != nil {
+		return trace.Wrap(err)
+	}
+	err := context.Operator.UnlockUser(r.Context(), req)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	roundtrip.ReplyJSON(w, http.StatusOK, statusOK("user unlocked"))
+	return nil
+}
+
 /* deleteUser deletes a user by name
 
    DELETE /portal/v1/users/:user_name
@@ -901,7 +987,7 @@ func (h *WebHandler) createSite(w http.ResponseWriter, r *http.Request, p httpro
    DELETE /portal/v1/accounts/<account-id>/sites/<site-domain>
 */
 func (h *WebHandler) deleteSite(w http.ResponseWriter, r *http.Request, p httprouter.Params, context *HandlerContext) error {
-	err := context.Operator.DeleteSite(siteKey(p))
+	err := context.Operator.DeleteSite(context.Context, siteKey(p))
 	if err != nil {
 		return trace.Wrap(err)
 	}
@@ -1514,6 +1600,32 @@ func (h *WebHandler) completeSiteOperation(w http.ResponseWriter, r *http.Reques
 	return nil
 }
 
+/* saveDiskBenchmarks stores the results of disk benchmarks executed during the operation
+
+   PUT /portal/v1/accounts/:account_id/sites/:site_domain/operations/common/:operation_id/benchmarks
+
+   [storage.DiskBenchmark]
+
+Success response:
+
+   {
+      "status": "ok",
+      "message": "disk benchmarks saved"
+   }
+*/
+func (h *WebHandler) saveDiskBenchmarks(w http.ResponseWriter, r *http.Request, p httprouter.Params, context *HandlerContext) error {
+	var benchmarks []storage.DiskBenchmark
+	if err := telehttplib.ReadJSON(r, &benchmarks); err != nil {
+		return trace.Wrap(err)
+	}
+	err := context.Operator.SaveDiskBenchmarks(siteOperationKey(p), benchmarks)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	roundtrip.ReplyJSON(w, http.StatusOK, statusOK("disk benchmarks saved"))
+	return nil
+}
+
 /* createOperationPlan saves the provided operation plan
 
    POST /portal/v1/accos/:account_id/sites/:site_domain/operations/common/:operation_id/plan
@@ -1853,6 +1965,26 @@ func (h *WebHandler) getSiteOperationCrashReport(w http.ResponseWriter, r *http.
 	return err
 }
 
+/* getClusterEvents streams the cluster state changes: operations, their progress
+   and plan phases, node status and alerts. The events are sent as server-sent events
+   or as JSON messages if the client requests a web socket upgrade
+
+     GET /portal/v1/accounts/:account_id/sites/:site_domain/events
+
+   Events:
+
+     watch.Event
+*/
+func (h *WebHandler) getClusterEvents(w http.ResponseWriter, r *http.Request, p httprouter.Params, context *HandlerContext) error {
+	key := siteKey(p)
+	return watch.ServeCluster(w, r, watch.Config{
+		Operator: context.Operator,
+		Key:      key,
+		Nodes:    watch.ClusterNodes(context.Operator, key),
+		Alerts:   watch.ClusterAlerts(context.Operator, key),
+	})
+}
+
 /*getSiteOperationProgress returns a progress report for this operation
 
   GET /portal/v1/accounts/:account_id/sites/:site_domain/operations/common/:operation_id/progress
@@ -1877,6 +2009,50 @@ func (h *WebHandler) getSiteOperationProgress(w http.ResponseWriter, r *http.Req
 	return nil
 }
 
+/* getOperationAgents returns the status of agents connected for the operation
+
+  GET /portal/v1/accounts/:account_id/sites/:site_domain/operations/common/:operation_id/agents
+
+Success Response:
+
+  [{
+    "addr": "192.168.1.1:61009",
+    "hostname": "node-1",
+    "version": "5.5.0",
+    "connected": true,
+    "last_heartbeat": "timestamp RFC 3339",
+    "rtt": 1000000,
+    "reconnects": 0
+  }]
+*/
+func (h *WebHandler) getOperationAgents(w http.ResponseWriter, r *http.Request, p httprouter.Params, context *HandlerContext) error {
+	agents, err := context.Operator.GetOperationAgents(siteOperationKey(p))
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	roundtrip.ReplyJSON(w, http.StatusOK, agents)
+	return nil
+}
+
+/* evictOperationAgent disconnects the agent from the operation
+
+  DELETE /portal/v1/accounts/:account_id/sites/:site_domain/operations/common/:operation_id/agents/:addr
+
+Success response:
+
+  {
+     "message": "agent evicted"
+  }
+*/
+func (h *WebHandler) evictOperationAgent(w http.ResponseWriter, r *http.Request, p httprouter.Params, context *HandlerContext) error {
+	err := context.Operator.EvictOperationAgent(siteOperationKey(p), p.ByName("addr"))
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	roundtrip.ReplyJSON(w, http.StatusOK, statusOK("agent evicted"))
+	return nil
+}
+
 /* createProgressEntry creates a new operation progress entry
 
    POST /portal/v1/accounts/:account_id/sites/:site_domain/operations/common/:operation_id/progress
@@ -2185,6 +2361,319 @@ func (h *WebHandler) deleteSMTPConfig(w http.ResponseWriter, r *http.Request, p
 	return nil
 }
 
+/* getMaintenanceWindow returns the cluster maintenance window
+
+     GET /portal/v1/accounts/:account_id/sites/:site_domain/maintenancewindow
+
+   Success Response:
+
+     storage.MaintenanceWindow
+*/
+func (h *WebHandler) getMaintenanceWindow(w http.ResponseWriter, r *http.Request, p httprouter.Params, context *HandlerContext) error {
+	window, err := context.Operator.GetMaintenanceWindow(siteKey(p))
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	roundtrip.ReplyJSON(w, http.StatusOK, window)
+	return nil
+}
+
+/* updateMaintenanceWindow updates the cluster maintenance window
+
+     PUT /portal/v1/accounts/:account_id/sites/:site_domain/maintenancewindow
+
+   Success Response:
+
+     {
+       "message": "maintenance window updated"
+     }
+*/
+func (h *WebHandler) updateMaintenanceWindow(w http.ResponseWriter, r *http.Request, p httprouter.Params, context *HandlerContext) error {
+	var req opsclient.UpsertResourceRawReq
+	if err := telehttplib.ReadJSON(r, &req); err != nil {
+		return trace.Wrap(err)
+	}
+	window, err := storage.UnmarshalMaintenanceWindow(req.Resource)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	err = context.Operator.UpdateMaintenanceWindow(r.Context(), siteKey(p), window)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	roundtrip.ReplyJSON(w, http.StatusOK, statusOK("maintenance window updated"))
+	return nil
+}
+
+/* deleteMaintenanceWindow deletes the cluster maintenance window
+
+   DELETE /portal/v1/accounts/:account_id/sites/:site_domain/maintenancewindow
+
+   Success Response:
+
+     {
+       "message": "maintenance window deleted"
+     }
+*/
+func (h *WebHandler) deleteMaintenanceWindow(w http.ResponseWriter, r *http.Request, p httprouter.Params, context *HandlerContext) error {
+	err := context.Operator.DeleteMaintenanceWindow(r.Context(), siteKey(p))
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	roundtrip.ReplyJSON(w, http.StatusOK, statusOK("maintenance window deleted"))
+	return nil
+}
+
+/* createAccessRequest creates a request for temporary access to roles
+
+     POST /portal/v1/accounts/:account_id/sites/:site_domain/accessrequests
+
+   Success Response:
+
+     storage.AccessRequest
+*/
+func (h *WebHandler) createAccessRequest(w http.ResponseWriter, r *http.Request, p httprouter.Params, context *HandlerContext) error {
+	var req ops.CreateAccessRequestRequest
+	if err := telehttplib.ReadJSON(r, &req); err != nil {
+		return trace.Wrap(err)
+	}
+	request, err := context.Operator.CreateAccessRequest(r.Context(), req)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	roundtrip.ReplyJSON(w, http.StatusOK, request)
+	return nil
+}
+
+/* getAccessRequests returns the access requests in the cluster
+
+     GET /portal/v1/accounts/:account_id/sites/:site_domain/accessrequests
+
+   Success Response:
+
+     []storage.AccessRequest
+*/
+func (h *WebHandler) getAccessRequests(w http.ResponseWriter, r *http.Request, p httprouter.Params, context *HandlerContext) error {
+	requests, err := context.Operator.GetAccessRequests(siteKey(p))
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	roundtrip.ReplyJSON(w, http.StatusOK, requests)
+	return nil
+}
+
+/* reviewAccessRequest approves or denies a pending access request
+
+     PUT /portal/v1/accounts/:account_id/sites/:site_domain/accessrequests/:id
+
+   Success Response:
+
+     storage.AccessRequest
+*/
+func (h *WebHandler) reviewAccessRequest(w http.ResponseWriter, r *http.Request, p httprouter.Params, context *HandlerContext) error {
+	var req ops.ReviewAccessRequestRequest
+	if err := telehttplib.ReadJSON(r, &req); err != nil {
+		return trace.Wrap(err)
+	}
+	req.SiteKey = siteKey(p)
+	req.ID = p.ByName("id")
+	request, err := context.Operator.ReviewAccessRequest(r.Context(), req)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	roundtrip.ReplyJSON(w, http.StatusOK, request)
+	return nil
+}
+
+/* getMFAPolicy returns the cluster second factor policy
+
+     GET /portal/v1/accounts/:account_id/sites/:site_domain/mfapolicy
+
+   Success Response:
+
+     storage.MFAPolicy
+*/
+func (h *WebHandler) getMFAPolicy(w http.ResponseWriter, r *http.Request, p httprouter.Params, context *HandlerContext) error {
+	policy, err := context.Operator.GetMFAPolicy(siteKey(p))
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	roundtrip.ReplyJSON(w, http.StatusOK, policy)
+	return nil
+}
+
+/* updateMFAPolicy updates the cluster second factor policy
+
+     PUT /portal/v1/accounts/:account_id/sites/:site_domain/mfapolicy
+
+   Success Response:
+
+     {
+       "message": "second factor policy updated"
+     }
+*/
+func (h *WebHandler) updateMFAPolicy(w http.ResponseWriter, r *http.Request, p httprouter.Params, context *HandlerContext) error {
+	var req opsclient.UpsertResourceRawReq
+	if err := telehttplib.ReadJSON(r, &req); err != nil {
+		return trace.Wrap(err)
+	}
+	policy, err := storage.UnmarshalMFAPolicy(req.Resource)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	err = context.Operator.UpdateMFAPolicy(r.Context(), siteKey(p), policy)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	roundtrip.ReplyJSON(w, http.StatusOK, statusOK("second factor policy updated"))
+	return nil
+}
+
+/* deleteMFAPolicy deletes the cluster second factor policy
+
+   DELETE /portal/v1/accounts/:account_id/sites/:site_domain/mfapolicy
+
+   Success Response:
+
+     {
+       "message": "second factor policy deleted"
+     }
+*/
+func (h *WebHandler) deleteMFAPolicy(w http.ResponseWriter, r *http.Request, p httprouter.Params, context *HandlerContext) error {
+	err := context.Operator.DeleteMFAPolicy(r.Context(), siteKey(p))
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	roundtrip.ReplyJSON(w, http.StatusOK, statusOK("second factor policy deleted"))
+	return nil
+}
+
+/* getPasswordPolicy returns the cluster password policy
+
+     GET /portal/v1/accounts/:account_id/sites/:site_domain/passwordpolicy
+
+   Success Response:
+
+     storage.PasswordPolicy
+*/
+func (h *WebHandler) getPasswordPolicy(w http.ResponseWriter, r *http.Request, p httprouter.Params, context *HandlerContext) error {
+	policy, err := context.Operator.GetPasswordPolicy(siteKey(p))
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	roundtrip.ReplyJSON(w, http.StatusOK, policy)
+	return nil
+}
+
+/* updatePasswordPolicy updates the cluster password policy
+
+     PUT /portal/v1/accounts/:account_id/sites/:site_domain/passwordpolicy
+
+   Success Response:
+
+     {
+       "message": "password policy updated"
+     }
+*/
+func (h *WebHandler) updatePasswordPolicy(w http.ResponseWriter, r *http.Request, p httprouter.Params, context *HandlerContext) error {
+	var req opsclient.UpsertResourceRawReq
+	if err := telehttplib.ReadJSON(r, &req); err != nil {
+		return trace.Wrap(err)
+	}
+	policy, err := storage.UnmarshalPasswordPolicy(req.Resource)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	err = context.Operator.UpdatePasswordPolicy(r.Context(), siteKey(p), policy)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	roundtrip.ReplyJSON(w, http.StatusOK, statusOK("password policy updated"))
+	return nil
+}
+
+/* deletePasswordPolicy deletes the cluster password policy
+
+   DELETE /portal/v1/accounts/:account_id/sites/:site_domain/passwordpolicy
+
+   Success Response:
+
+     {
+       "message": "password policy deleted"
+     }
+*/
+func (h *WebHandler) deletePasswordPolicy(w http.ResponseWriter, r *http.Request, p httprouter.Params, context *HandlerContext) error {
+	err := context.Operator.DeletePasswordPolicy(r.Context(), siteKey(p))
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	roundtrip.ReplyJSON(w, http.StatusOK, statusOK("password policy deleted"))
+	return nil
+}
+
+/* getNodePools returns the list of configured node pools
+
+     GET /portal/v1/accounts/:account_id/sites/:site_domain/nodepools
+
+   Success Response:
+
+     []storage.NodePool
+*/
+func (h *WebHandler) getNodePools(w http.ResponseWriter, r *http.Request, p httprouter.Params, context *HandlerContext) error {
+	pools, err := context.Operator.GetNodePools(siteKey(p))
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	roundtrip.ReplyJSON(w, http.StatusOK, pools)
+	return nil
+}
+
+/* updateNodePool creates or updates the specified node pool
+
+     PUT /portal/v1/accounts/:account_id/sites/:site_domain/nodepools/:name
+
+   Success Response:
+
+     {
+       "message": "node pool updated"
+     }
+*/
+func (h *WebHandler) updateNodePool(w http.ResponseWriter, r *http.Request, p httprouter.Params, context *HandlerContext) error {
+	var req opsclient.UpsertResourceRawReq
+	if err := telehttplib.ReadJSON(r, &req); err != nil {
+		return trace.Wrap(err)
+	}
+	pool, err := storage.UnmarshalNodePool(req.Resource)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	err = context.Operator.UpdateNodePool(r.Context(), siteKey(p), pool)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	roundtrip.ReplyJSON(w, http.StatusOK, statusOK("node pool updated"))
+	return nil
+}
+
+/* deleteNodePool deletes the specified node pool
+
+   DELETE /portal/v1/accounts/:account_id/sites/:site_domain/nodepools/:name
+
+   Success Response:
+
+     {
+       "message": "node pool deleted"
+     }
+*/
+func (h *WebHandler) deleteNodePool(w http.ResponseWriter, r *http.Request, p httprouter.Params, context *HandlerContext) error {
+	err := context.Operator.DeleteNodePool(r.Context(), siteKey(p), p.ByName("name"))
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	roundtrip.ReplyJSON(w, http.StatusOK, statusOK("node pool deleted"))
+	return nil
+}
+
 /* getApplicationEndpoints returns application endpoints for a deployed cluster
 
      GET /portal/v1/accounts/:account_id/sites/:site_domain/endpoints
@@ -2307,6 +2796,46 @@ func (h *WebHandler) deleteClusterCert(w http.ResponseWriter, r *http.Request, p
 	return nil
 }
 
+/* getRPCCertificateAuthority returns the CA certificate bundle trusted by the cluster RPC agents
+
+     GET /portal/v1/accounts/:account_id/sites/:site_domain/rpc/ca
+
+   Success Response:
+
+     ops.RPCCertificateAuthority
+*/
+func (h *WebHandler) getRPCCertificateAuthority(w http.ResponseWriter, r *http.Request, p httprouter.Params, context *HandlerContext) error {
+	ca, err := context.Operator.GetRPCCertificateAuthority(siteKey(p))
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	roundtrip.ReplyJSON(w, http.StatusOK, ca)
+	return nil
+}
+
+/* updateRPCCredentials replaces the cluster RPC agent credentials
+
+     PUT /portal/v1/accounts/:account_id/sites/:site_domain/rpc/credentials
+
+   Input: ops.UpdateRPCCredentialsRequest
+
+   Success Response:
+
+     200 credentials updated
+*/
+func (h *WebHandler) updateRPCCredentials(w http.ResponseWriter, r *http.Request, p httprouter.Params, context *HandlerContext) error {
+	var req ops.UpdateRPCCredentialsRequest
+	if err := telehttplib.ReadJSON(r, &req); err != nil {
+		return trace.Wrap(err)
+	}
+	req.ClusterKey = siteKey(p)
+	if err := context.Operator.UpdateRPCCredentials(context.Context, req); err != nil {
+		return trace.Wrap(err)
+	}
+	roundtrip.ReplyJSON(w, http.StatusOK, message("credentials updated"))
+	return nil
+}
+
 /* emitAuditEvent saves the provided event in the audit log.
 
      POST /portal/v1/accounts/:account_id/sites/:site_domain/events
@@ -2326,6 +2855,41 @@ func (h *WebHandler) emitAuditEvent(w http.ResponseWriter, r *http.Request, p ht
 	return nil
 }
 
+/* getAuditRecords returns the hash-chained audit log records.
+
+     GET /portal/v1/accounts/:account_id/sites/:site_domain/audit/records?from_index=<index>&since=<time>&until=<time>
+
+   Success response:
+
+     []storage.AuditRecord
+*/
+func (h *WebHandler) getAuditRecords(w http.ResponseWriter, r *http.Request, p httprouter.Params, context *HandlerContext) error {
+	req := ops.GetAuditRecordsRequest{SiteKey: siteKey(p)}
+	query := r.URL.Query()
+	var err error
+	if index := query.Get("from_index"); index != "" {
+		if req.FromIndex, err = strconv.ParseUint(index, 10, 64); err != nil {
+			return trace.BadParameter("invalid from_index %q", index)
+		}
+	}
+	if since := query.Get("since"); since != "" {
+		if req.Since, err = time.Parse(time.RFC3339Nano, since); err != nil {
+			return trace.BadParameter("invalid since %q", since)
+		}
+	}
+	if until := query.Get("until"); until != "" {
+		if req.Until, err = time.Parse(time.RFC3339Nano, until); err != nil {
+			return trace.BadParameter("invalid until %q", until)
+		}
+	}
+	records, err := context.Operator.GetAuditRecords(r.Context(), req)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	roundtrip.ReplyJSON(w, http.StatusOK, records)
+	return nil
+}
+
 func (s *WebHandler) wrap(fn func(w http.ResponseWriter, r *http.Request, p httprouter.Params) error) httprouter.Handle {
 	return func(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
 		if err := fn(w, r, p); err != nil {
@@ -2334,8 +2898,44 @@ func (s *WebHandler) wrap(fn func(w http.ResponseWriter, r *http.Request, p http
 	}
 }
 
+// needsAuth authenticates the request and accounts it against the read
+// or write budget depending on the request method
 func (s *WebHandler) needsAuth(fn ServiceHandle) httprouter.Handle {
-	return NeedsAuth(s.cfg.Devmode, s.cfg.Backend, s.cfg.Operator, s.cfg.Authenticator, s.cfg.Users, fn)
+	return NeedsAuth(s.cfg.Devmode, s.cfg.Backend, s.cfg.Operator, s.cfg.Authenticator, s.cfg.Users,
+		s.rateLimited("", fn))
+}
+
+// needsAuthTransfer authenticates the request and accounts it against the
+// transfer budget, used for handlers that upload or download large blobs
+func (s *WebHandler) needsAuthTransfer(fn ServiceHandle) httprouter.Handle {
+	return NeedsAuth(s.cfg.Devmode, s.cfg.Backend, s.cfg.Operator, s.cfg.Authenticator, s.cfg.Users,
+		s.rateLimited(httplib.RequestTransfer, fn))
+}
+
+// needsAuthStream authenticates the request and accounts it against the
+// stream budget, used for long-lived handlers that stream events or logs
+func (s *WebHandler) needsAuthStream(fn ServiceHandle) httprouter.Handle {
+	return NeedsAuth(s.cfg.Devmode, s.cfg.Backend, s.cfg.Operator, s.cfg.Authenticator, s.cfg.Users,
+		s.rateLimited(httplib.RequestStream, fn))
+}
+
+// rateLimited wraps the handler to account requests against the budgets of
+// the authenticated user, the API key and the cluster from the request path.
+// The class is derived from the request method if unspecified
+func (s *WebHandler) rateLimited(class httplib.RequestClass, fn ServiceHandle) ServiceHandle {
+	if s.cfg.RateLimiter == nil {
+		return fn
+	}
+	return func(w http.ResponseWriter, r *http.Request, p httprouter.Params, context *HandlerContext) error {
+		requestClass := class
+		if requestClass == "" {
+			requestClass = httplib.RequestClassFor(r)
+		}
+		subjects := httplib.RequestSubjects(r, context.User.GetName(), p.ByName("site_domain"))
+		return s.cfg.RateLimiter.Limit(w, requestClass, subjects, func() error {
+			return fn(w, r, p, context)
+		})
+	}
 }
 
 // GetHandlerContext authenticates the user that made the request and returns
@@ -2352,6 +2952,9 @@ func GetHandlerContext(w http.ResponseWriter, r *http.Request, backend storage.B
 	// Enrich the request context with additional auth info.
 	ctx := r.Context()
 	ctx = context.WithValue(ctx, constants.UserContext, authResult.User.GetName())
+	if token := r.Header.Get(constants.SecondFactorHeader); token != "" {
+		ctx = ops.WithSecondFactor(ctx, token)
+	}
 	if authResult.Session != nil {
 		ctx = context.WithValue(ctx, constants.WebSessionContext, authResult.Session.GetWebSession())
 	}
@@ -25,13 +25,17 @@ import (
 	"time"
 
 	"github.com/gravitational/gravity/lib/compare"
+	"github.com/gravitational/gravity/lib/constants"
 	"github.com/gravitational/gravity/lib/defaults"
 	"github.com/gravitational/gravity/lib/loc"
+	"github.com/gravitational/gravity/lib/openapi"
 	"github.com/gravitational/gravity/lib/ops"
 	"github.com/gravitational/gravity/lib/ops/opsclient"
 	"github.com/gravitational/gravity/lib/ops/opsservice"
 	"github.com/gravitational/gravity/lib/ops/suite"
+	"github.com/gravitational/gravity/lib/schema"
 	"github.com/gravitational/gravity/lib/storage"
+	"github.com/gravitational/gravity/lib/testutils"
 	"github.com/gravitational/gravity/lib/users"
 
 	"github.com/gravitational/teleport"
@@ -39,6 +43,7 @@ import (
 
 	"github.com/gravitational/trace"
 	"github.com/mailgun/timetools"
+	"github.com/pquerna/otp/totp"
 	log "github.com/sirupsen/logrus"
 	. "gopkg.in/check.v1"
 )
@@ -49,6 +54,8 @@ type OpsHandlerSuite struct {
 	backend     storage.Backend
 	suite       suite.OpsSuite
 	webServer   *httptest.Server
+	handler     *WebHandler
+	validator   *openapi.Validator
 	users       users.Identity
 	clock       *timetools.FreezedTime
 	adminUser   string
@@ -89,8 +96,9 @@ func (s *OpsHandlerSuite) SetUpTest(c *C) {
 	}))
 	c.Assert(err, IsNil)
 
-	_, err = s.suite.SetUpTestPackage(services.Apps, services.Packages, c)
+	testApp, err := s.suite.SetUpTestPackage(services.Apps, services.Packages, c)
 	c.Assert(err, IsNil)
+	s.testApp = *testApp
 
 	handler, err := NewWebHandler(WebHandlerConfig{
 		Users:        s.users,
@@ -99,10 +107,16 @@ func (s *OpsHandlerSuite) SetUpTest(c *C) {
 		Packages:     services.Packages,
 	})
 	c.Assert(err, IsNil)
+	s.handler = handler
+
+	// Validate the requests and the responses against the specification
+	spec, err := openapi.Load("../../openapi/openapi.yaml")
+	c.Assert(err, IsNil)
+	s.validator = openapi.NewValidator(spec, openapi.APIOps, handler)
 
 	// It is important that we launch TLS server as authentication
 	// middleware on the handler expects TLS connections.
-	s.webServer = httptest.NewTLSServer(handler)
+	s.webServer = httptest.NewTLSServer(s.validator)
 
 	// for regular test, let's be admins, so tests
 	// won't be affected by auth issues
@@ -118,6 +132,9 @@ func (s *OpsHandlerSuite) TearDownTest(c *C) {
 	if s.webServer != nil {
 		s.webServer.Close()
 	}
+	if s.validator != nil {
+		c.Assert(s.validator.Error(), IsNil)
+	}
 	if s.backend != nil {
 		c.Assert(s.backend.Close(), IsNil)
 	}
@@ -180,6 +197,131 @@ func (s *OpsHandlerSuite) TestGithubConnector(c *C) {
 	c.Assert(trace.IsNotFound(err), Equals, true)
 }
 
+func (s *OpsHandlerSuite) TestOIDCConnector(c *C) {
+	key := ops.SiteKey{AccountID: "a", SiteDomain: "b"}
+	issuer := testutils.NewOIDCIssuer()
+	defer issuer.Close()
+
+	connectors, err := s.client.GetOIDCConnectors(key, true)
+	c.Assert(err, IsNil)
+	compare.DeepCompare(c, connectors, []teleservices.OIDCConnector{})
+
+	newConnector := func(issuerURL, clientSecret string, roles ...string) teleservices.OIDCConnector {
+		return teleservices.NewOIDCConnector("keycloak", teleservices.OIDCConnectorSpecV2{
+			IssuerURL:    issuerURL,
+			ClientID:     "gravity",
+			ClientSecret: clientSecret,
+			RedirectURL:  "https://gravity/portalapi/v1/oidc/callback",
+			ClaimsToRoles: []teleservices.ClaimMapping{{
+				Claim: "groups",
+				Value: "admins",
+				Roles: roles,
+			}},
+		})
+	}
+
+	withSecrets := true
+	connector := newConnector(issuer.URL, "secret", constants.RoleAdmin)
+	err = s.client.UpsertOIDCConnector(context.TODO(), key, connector)
+	c.Assert(err, IsNil)
+
+	out, err := s.client.GetOIDCConnector(key, connector.GetName(), withSecrets)
+	c.Assert(err, IsNil)
+	compare.DeepCompare(c, out, connector)
+
+	connectors, err = s.client.GetOIDCConnectors(key, withSecrets)
+	c.Assert(err, IsNil)
+	compare.DeepCompare(c, connectors, []teleservices.OIDCConnector{connector})
+
+	out, err = s.client.GetOIDCConnector(key, connector.GetName(), !withSecrets)
+	c.Assert(err, IsNil)
+	compare.DeepCompare(c, out, newConnector(issuer.URL, "", constants.RoleAdmin))
+
+	// connectors that map claims to unknown roles are rejected
+	err = s.client.UpsertOIDCConnector(context.TODO(), key, newConnector(issuer.URL, "secret", "unknown"))
+	c.Assert(trace.IsNotFound(err), Equals, true, Commentf("%v", err))
+
+	// connectors with an issuer that does not publish provider configuration are rejected
+	err = s.client.UpsertOIDCConnector(context.TODO(), key, newConnector(issuer.URL+"/realms/other", "secret", constants.RoleAdmin))
+	c.Assert(err, NotNil)
+
+	err = s.client.DeleteOIDCConnector(context.TODO(), key, connector.GetName())
+	c.Assert(err, IsNil)
+
+	_, err = s.client.GetOIDCConnector(key, connector.GetName(), withSecrets)
+	c.Assert(trace.IsNotFound(err), Equals, true)
+}
+
+func (s *OpsHandlerSuite) TestMFAPolicy(c *C) {
+	account, err := s.client.CreateAccount(ops.NewAccountRequest{Org: "example.com"})
+	c.Assert(err, IsNil)
+	cluster, err := s.client.CreateSite(ops.NewSiteRequest{
+		AppPackage: s.testApp.String(),
+		AccountID:  account.ID,
+		Provider:   schema.ProviderOnPrem,
+		DomainName: "example.com",
+	})
+	c.Assert(err, IsNil)
+	key := cluster.Key()
+
+	_, err = s.client.GetMFAPolicy(key)
+	c.Assert(trace.IsNotFound(err), Equals, true, Commentf("%v", err))
+
+	policy := storage.NewMFAPolicy(storage.MFAPolicySpecV1{
+		Rules: []storage.MFARule{{Actions: []string{storage.MFAActionShrink}}},
+	})
+	err = s.client.UpdateMFAPolicy(context.TODO(), key, policy)
+	c.Assert(err, IsNil)
+
+	out, err := s.client.GetMFAPolicy(key)
+	c.Assert(err, IsNil)
+	compare.DeepCompare(c, out, policy)
+
+	// actions covered by the policy require a second factor
+	_, err = s.client.CreateSiteShrinkOperation(context.TODO(), ops.CreateSiteShrinkOperationRequest{
+		AccountID:  key.AccountID,
+		SiteDomain: key.SiteDomain,
+		Servers:    []string{"node-1"},
+	})
+	c.Assert(ops.IsSecondFactorRequiredError(err), Equals, true, Commentf("%v", err))
+
+	// so does lifting the policy
+	err = s.client.DeleteMFAPolicy(context.TODO(), key)
+	c.Assert(ops.IsSecondFactorRequiredError(err), Equals, true, Commentf("%v", err))
+
+	err = s.client.DeleteMFAPolicy(ops.WithSecondFactor(context.TODO(), "123456"), key)
+	c.Assert(trace.IsAccessDenied(err), Equals, true, Commentf("%v", err))
+	c.Assert(ops.IsSecondFactorRequiredError(err), Equals, false, Commentf("%v", err))
+
+	// users with a second factor cannot use basic auth so switch to an API key
+	apiKey, err := s.users.CreateAPIKey(storage.APIKey{UserEmail: s.adminUser}, false)
+	c.Assert(err, IsNil)
+	client, err := opsclient.NewBearerClient(s.webServer.URL, apiKey.Token,
+		opsclient.HTTPClient(s.webServer.Client()))
+	c.Assert(err, IsNil)
+
+	otpKey, err := totp.Generate(totp.GenerateOpts{Issuer: "gravity", AccountName: s.adminUser})
+	c.Assert(err, IsNil)
+	c.Assert(s.users.UpsertTOTP(s.adminUser, otpKey.Secret()), IsNil)
+	token, err := totp.GenerateCode(otpKey.Secret(), time.Now())
+	c.Assert(err, IsNil)
+
+	err = client.UpdateMFAPolicy(ops.WithSecondFactor(context.TODO(), token), key, policy)
+	c.Assert(err, IsNil)
+
+	// tokens cannot be reused
+	err = client.DeleteMFAPolicy(ops.WithSecondFactor(context.TODO(), token), key)
+	c.Assert(trace.IsAccessDenied(err), Equals, true, Commentf("%v", err))
+
+	token, err = totp.GenerateCode(otpKey.Secret(), time.Now().Add(30*time.Second))
+	c.Assert(err, IsNil)
+	err = client.DeleteMFAPolicy(ops.WithSecondFactor(context.TODO(), token), key)
+	c.Assert(err, IsNil)
+
+	_, err = client.GetMFAPolicy(key)
+	c.Assert(trace.IsNotFound(err), Equals, true, Commentf("%v", err))
+}
+
 func (s *OpsHandlerSuite) TestUser(c *C) {
 	key := ops.SiteKey{AccountID: "a", SiteDomain: "b"}
 
@@ -359,3 +501,9 @@ func (s *OpsHandlerSuite) TestClusterAuthConfiguration(c *C) {
 	c.Assert(actual.GetType(), Equals, cap.GetType())
 	c.Assert(actual.GetSecondFactor(), Equals, cap.GetSecondFactor())
 }
+
+func (s *OpsHandlerSuite) TestRoutesMatchSpec(c *C) {
+	spec, err := openapi.Load("../../openapi/openapi.yaml")
+	c.Assert(err, IsNil)
+	c.Assert(openapi.CheckRouter(s.handler, spec.Routes(openapi.APIOps)), IsNil)
+}
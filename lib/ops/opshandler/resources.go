@@ -316,6 +316,89 @@ func (h *WebHandler) deleteGithubConnector(w http.ResponseWriter, r *http.Reques
 	return nil
 }
 
+/* upsertOIDCConnector creates or updates an OIDC connector
+
+   POST /portal/v1/accounts/:account_id/sites/:site_domain/oidc/connectors
+*/
+func (h *WebHandler) upsertOIDCConnector(w http.ResponseWriter, r *http.Request, p httprouter.Params, ctx *HandlerContext) error {
+	var req *opsclient.UpsertResourceRawReq
+	if err := telehttplib.ReadJSON(r, &req); err != nil {
+		return trace.Wrap(err)
+	}
+	connector, err := teleservices.GetOIDCConnectorMarshaler().UnmarshalOIDCConnector(req.Resource)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if req.TTL != 0 {
+		connector.SetTTL(clockwork.NewRealClock(), req.TTL)
+	}
+	err = ctx.Operator.UpsertOIDCConnector(r.Context(), siteKey(p), connector)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	roundtrip.ReplyJSON(w, http.StatusOK, message("upserted OIDC connector"))
+	return nil
+}
+
+/* getOIDCConnector returns an OIDC connector by name
+
+   GET /portal/v1/accounts/:account_id/sites/:site_domain/oidc/connectors/:id
+*/
+func (h *WebHandler) getOIDCConnector(w http.ResponseWriter, r *http.Request, p httprouter.Params, ctx *HandlerContext) error {
+	withSecrets, _, err := telehttplib.ParseBool(r.URL.Query(), constants.WithSecretsParam)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	connector, err := ctx.Operator.GetOIDCConnector(siteKey(p), p.ByName("id"), withSecrets)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	out, err := teleservices.GetOIDCConnectorMarshaler().MarshalOIDCConnector(connector)
+	return rawMessage(w, out, err)
+}
+
+/* getOIDCConnectors returns all OIDC connectors
+
+   GET /portal/v1/accounts/:account_id/sites/:site_domain/oidc/connectors
+*/
+func (h *WebHandler) getOIDCConnectors(w http.ResponseWriter, r *http.Request, p httprouter.Params, ctx *HandlerContext) error {
+	withSecrets, _, err := telehttplib.ParseBool(r.URL.Query(), constants.WithSecretsParam)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	connectors, err := ctx.Operator.GetOIDCConnectors(siteKey(p), withSecrets)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	items := make([]json.RawMessage, len(connectors))
+	for i, connector := range connectors {
+		data, err := teleservices.GetOIDCConnectorMarshaler().MarshalOIDCConnector(connector)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		items[i] = data
+	}
+	roundtrip.ReplyJSON(w, http.StatusOK, items)
+	return nil
+}
+
+/* deleteOIDCConnector deletes a connector by its name
+
+   DELETE /portal/v1/accounts/:account_id/sites/:site_domain/oidc/connectors/:id
+*/
+func (h *WebHandler) deleteOIDCConnector(w http.ResponseWriter, r *http.Request, p httprouter.Params, ctx *HandlerContext) error {
+	name := p.ByName("id")
+	err := ctx.Operator.DeleteOIDCConnector(r.Context(), siteKey(p), name)
+	if err != nil {
+		if trace.IsNotFound(err) {
+			return trace.NotFound("OIDC connector %q not found", name)
+		}
+		return trace.Wrap(err)
+	}
+	roundtrip.ReplyJSON(w, http.StatusOK, message("OIDC connector deleted"))
+	return nil
+}
+
 /* getPersistentStorage retrieves cluster persistent storage configuration.
 
      GET /portal/v1/accounts/:account_id/sites/:site_domain/persistentstorage
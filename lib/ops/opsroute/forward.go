@@ -28,6 +28,7 @@ import (
 	"github.com/gravitational/gravity/lib/ops/opsservice"
 	"github.com/gravitational/gravity/lib/storage"
 	"github.com/gravitational/gravity/lib/storage/clusterconfig"
+	"github.com/gravitational/gravity/lib/users"
 
 	teleservices "github.com/gravitational/teleport/lib/services"
 	"github.com/gravitational/trace"
@@ -210,8 +211,8 @@ func (r *Router) GetSites(accountID string) ([]ops.Site, error) {
 	return r.Local.GetSites(accountID)
 }
 
-func (r *Router) DeleteSite(siteKey ops.SiteKey) error {
-	return r.Local.DeleteSite(siteKey)
+func (r *Router) DeleteSite(ctx context.Context, siteKey ops.SiteKey) error {
+	return r.Local.DeleteSite(ctx, siteKey)
 }
 
 func (r *Router) GetSiteByDomain(domainName string) (*ops.Site, error) {
@@ -408,6 +409,22 @@ func (r *Router) GetSiteOperationProgress(key ops.SiteOperationKey) (*ops.Progre
 	return client.GetSiteOperationProgress(key)
 }
 
+func (r *Router) GetOperationAgents(key ops.SiteOperationKey) ([]ops.AgentStatus, error) {
+	client, err := r.PickOperationClient(key.SiteDomain)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return client.GetOperationAgents(key)
+}
+
+func (r *Router) EvictOperationAgent(key ops.SiteOperationKey, addr string) error {
+	client, err := r.PickOperationClient(key.SiteDomain)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	return client.EvictOperationAgent(key, addr)
+}
+
 func (r *Router) CreateProgressEntry(key ops.SiteOperationKey, entry ops.ProgressEntry) error {
 	client, err := r.PickOperationClient(key.SiteDomain)
 	if err != nil {
@@ -471,6 +488,14 @@ func (r *Router) DeleteSiteOperation(key ops.SiteOperationKey) error {
 	return client.DeleteSiteOperation(key)
 }
 
+func (r *Router) SaveDiskBenchmarks(key ops.SiteOperationKey, benchmarks []storage.DiskBenchmark) error {
+	client, err := r.PickOperationClient(key.SiteDomain)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	return client.SaveDiskBenchmarks(key, benchmarks)
+}
+
 func (r *Router) SetOperationState(key ops.SiteOperationKey, req ops.SetOperationStateRequest) error {
 	client, err := r.PickOperationClient(key.SiteDomain)
 	if err != nil {
@@ -603,6 +628,141 @@ func (r *Router) DeleteSMTPConfig(ctx context.Context, key ops.SiteKey) error {
 	return client.DeleteSMTPConfig(ctx, key)
 }
 
+// GetMaintenanceWindow returns the cluster maintenance window
+func (r *Router) GetMaintenanceWindow(key ops.SiteKey) (storage.MaintenanceWindow, error) {
+	client, err := r.RemoteClient(key.SiteDomain)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return client.GetMaintenanceWindow(key)
+}
+
+// UpdateMaintenanceWindow updates the cluster maintenance window
+func (r *Router) UpdateMaintenanceWindow(ctx context.Context, key ops.SiteKey, window storage.MaintenanceWindow) error {
+	client, err := r.RemoteClient(key.SiteDomain)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	return client.UpdateMaintenanceWindow(ctx, key, window)
+}
+
+// DeleteMaintenanceWindow deletes the cluster maintenance window
+func (r *Router) DeleteMaintenanceWindow(ctx context.Context, key ops.SiteKey) error {
+	client, err := r.RemoteClient(key.SiteDomain)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	return client.DeleteMaintenanceWindow(ctx, key)
+}
+
+// GetNodePools returns the list of configured node pools
+func (r *Router) GetNodePools(key ops.SiteKey) ([]storage.NodePool, error) {
+	client, err := r.RemoteClient(key.SiteDomain)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return client.GetNodePools(key)
+}
+
+// UpdateNodePool creates or updates the specified node pool
+func (r *Router) UpdateNodePool(ctx context.Context, key ops.SiteKey, pool storage.NodePool) error {
+	client, err := r.RemoteClient(key.SiteDomain)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	return client.UpdateNodePool(ctx, key, pool)
+}
+
+// DeleteNodePool deletes the node pool specified with name
+func (r *Router) DeleteNodePool(ctx context.Context, key ops.SiteKey, name string) error {
+	client, err := r.RemoteClient(key.SiteDomain)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	return client.DeleteNodePool(ctx, key, name)
+}
+
+// CreateAccessRequest creates a request for temporary access to roles
+func (r *Router) CreateAccessRequest(ctx context.Context, req ops.CreateAccessRequestRequest) (*storage.AccessRequest, error) {
+	client, err := r.PickClient(req.SiteDomain)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return client.CreateAccessRequest(ctx, req)
+}
+
+// GetAccessRequests returns the access requests in the cluster
+func (r *Router) GetAccessRequests(key ops.SiteKey) ([]storage.AccessRequest, error) {
+	client, err := r.PickClient(key.SiteDomain)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return client.GetAccessRequests(key)
+}
+
+// ReviewAccessRequest approves or denies a pending access request
+func (r *Router) ReviewAccessRequest(ctx context.Context, req ops.ReviewAccessRequestRequest) (*storage.AccessRequest, error) {
+	client, err := r.PickClient(req.SiteDomain)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return client.ReviewAccessRequest(ctx, req)
+}
+
+// GetMFAPolicy returns the cluster second factor policy
+func (r *Router) GetMFAPolicy(key ops.SiteKey) (storage.MFAPolicy, error) {
+	client, err := r.PickClient(key.SiteDomain)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return client.GetMFAPolicy(key)
+}
+
+// UpdateMFAPolicy updates the cluster second factor policy
+func (r *Router) UpdateMFAPolicy(ctx context.Context, key ops.SiteKey, policy storage.MFAPolicy) error {
+	client, err := r.PickClient(key.SiteDomain)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	return client.UpdateMFAPolicy(ctx, key, policy)
+}
+
+// DeleteMFAPolicy deletes the cluster second factor policy
+func (r *Router) DeleteMFAPolicy(ctx context.Context, key ops.SiteKey) error {
+	client, err := r.PickClient(key.SiteDomain)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	return client.DeleteMFAPolicy(ctx, key)
+}
+
+// GetPasswordPolicy returns the cluster password policy
+func (r *Router) GetPasswordPolicy(key ops.SiteKey) (storage.PasswordPolicy, error) {
+	client, err := r.PickClient(key.SiteDomain)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return client.GetPasswordPolicy(key)
+}
+
+// UpdatePasswordPolicy updates the cluster password policy
+func (r *Router) UpdatePasswordPolicy(ctx context.Context, key ops.SiteKey, policy storage.PasswordPolicy) error {
+	client, err := r.PickClient(key.SiteDomain)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	return client.UpdatePasswordPolicy(ctx, key, policy)
+}
+
+// DeletePasswordPolicy deletes the cluster password policy
+func (r *Router) DeletePasswordPolicy(ctx context.Context, key ops.SiteKey) error {
+	client, err := r.PickClient(key.SiteDomain)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	return client.DeletePasswordPolicy(ctx, key)
+}
+
 // GetAlerts returns a list of monitoring alerts
 func (r *Router) GetAlerts(key ops.SiteKey) ([]storage.Alert, error) {
 	client, err := r.RemoteClient(key.SiteDomain)
@@ -781,6 +941,24 @@ func (r *Router) DeleteClusterCertificate(ctx context.Context, key ops.SiteKey)
 	return client.DeleteClusterCertificate(ctx, key)
 }
 
+// GetRPCCertificateAuthority returns the CA certificate bundle trusted by the cluster RPC agents
+func (r *Router) GetRPCCertificateAuthority(key ops.SiteKey) (*ops.RPCCertificateAuthority, error) {
+	client, err := r.RemoteClient(key.SiteDomain)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return client.GetRPCCertificateAuthority(key)
+}
+
+// UpdateRPCCredentials replaces the cluster RPC agent credentials
+func (r *Router) UpdateRPCCredentials(ctx context.Context, req ops.UpdateRPCCredentialsRequest) error {
+	client, err := r.RemoteClient(req.ClusterKey.SiteDomain)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	return client.UpdateRPCCredentials(ctx, req)
+}
+
 // StepDown asks the process to pause its leader election heartbeat so it can
 // give up its leadership
 func (r *Router) StepDown(key ops.SiteKey) error {
@@ -881,6 +1059,46 @@ func (r *Router) DeleteGithubConnector(ctx context.Context, key ops.SiteKey, nam
 	return client.DeleteGithubConnector(ctx, key, name)
 }
 
+// UpsertOIDCConnector creates or updates an OIDC connector
+func (r *Router) UpsertOIDCConnector(ctx context.Context, key ops.SiteKey, connector teleservices.OIDCConnector) error {
+	client, err := r.PickClient(key.SiteDomain)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	return client.UpsertOIDCConnector(ctx, key, connector)
+}
+
+// GetOIDCConnector returns an OIDC connector by name
+//
+// Returned connector exclude client secret unless withSecrets is true.
+func (r *Router) GetOIDCConnector(key ops.SiteKey, name string, withSecrets bool) (teleservices.OIDCConnector, error) {
+	client, err := r.PickClient(key.SiteDomain)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return client.GetOIDCConnector(key, name, withSecrets)
+}
+
+// GetOIDCConnectors returns all OIDC connectors
+//
+// Returned connectors exclude client secret unless withSecrets is true.
+func (r *Router) GetOIDCConnectors(key ops.SiteKey, withSecrets bool) ([]teleservices.OIDCConnector, error) {
+	client, err := r.PickClient(key.SiteDomain)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return client.GetOIDCConnectors(key, withSecrets)
+}
+
+// DeleteOIDCConnector deletes an OIDC connector by name
+func (r *Router) DeleteOIDCConnector(ctx context.Context, key ops.SiteKey, name string) error {
+	client, err := r.PickClient(key.SiteDomain)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	return client.DeleteOIDCConnector(ctx, key, name)
+}
+
 // UpsertAuthGateway updates auth gateway configuration.
 func (r *Router) UpsertAuthGateway(ctx context.Context, key ops.SiteKey, gw storage.AuthGateway) error {
 	return r.Local.UpsertAuthGateway(ctx, key, gw)
@@ -909,6 +1127,15 @@ func (r *Router) EmitAuditEvent(ctx context.Context, req ops.AuditEventRequest)
 	return r.Local.EmitAuditEvent(ctx, req)
 }
 
+// GetAuditRecords returns the hash-chained audit log records.
+func (r *Router) GetAuditRecords(ctx context.Context, req ops.GetAuditRecordsRequest) ([]storage.AuditRecord, error) {
+	client, err := r.PickClient(req.SiteDomain)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return client.GetAuditRecords(ctx, req)
+}
+
 // CreateUserInvite creates a new invite token for a user.
 func (r *Router) CreateUserInvite(ctx context.Context, req ops.CreateUserInviteRequest) (*storage.UserToken, error) {
 	client, err := r.PickClient(req.SiteDomain)
@@ -936,6 +1163,25 @@ func (r *Router) DeleteUserInvite(ctx context.Context, req ops.DeleteUserInviteR
 	return client.DeleteUserInvite(ctx, req)
 }
 
+// CheckUserAccess explains whether the user can perform the specified action
+// on the resource kind.
+func (r *Router) CheckUserAccess(ctx context.Context, req ops.CheckUserAccessRequest) (*users.AccessDecision, error) {
+	client, err := r.PickClient(req.SiteDomain)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return client.CheckUserAccess(ctx, req)
+}
+
+// UnlockUser unlocks the user locked after too many failed login attempts.
+func (r *Router) UnlockUser(ctx context.Context, req ops.UnlockUserRequest) error {
+	client, err := r.PickClient(req.SiteDomain)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	return client.UnlockUser(ctx, req)
+}
+
 // CreateUserInvite creates a new reset token for a user.
 func (r *Router) CreateUserReset(ctx context.Context, req ops.CreateUserResetRequest) (*storage.UserToken, error) {
 	client, err := r.PickClient(req.SiteDomain)
@@ -0,0 +1,98 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package opsservice
+
+import (
+	"context"
+
+	"github.com/gravitational/gravity/lib/ops"
+	"github.com/gravitational/gravity/lib/ops/events"
+	"github.com/gravitational/gravity/lib/storage"
+	"github.com/gravitational/gravity/lib/users"
+
+	"github.com/gravitational/trace"
+	"github.com/pborman/uuid"
+)
+
+// CreateAccessRequest creates a request for temporary access to roles
+func (o *Operator) CreateAccessRequest(ctx context.Context, req ops.CreateAccessRequestRequest) (*storage.AccessRequest, error) {
+	if err := req.CheckAndSetDefaults(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	if _, err := o.backend().GetUser(req.User); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	for _, role := range req.Roles {
+		if _, err := o.backend().GetRole(role); err != nil {
+			return nil, trace.Wrap(err)
+		}
+	}
+	request, err := o.backend().CreateAccessRequest(storage.AccessRequest{
+		ID:       uuid.New(),
+		User:     req.User,
+		Roles:    req.Roles,
+		Reason:   req.Reason,
+		Duration: req.Duration,
+		State:    storage.AccessRequestStatePending,
+		Created:  o.backend().Now().UTC(),
+	})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	events.Emit(ctx, o, events.AccessRequestCreated, events.Fields{
+		events.FieldAccessRequestID: request.ID,
+		events.FieldOwner:           request.User,
+		events.FieldRoles:           request.Roles,
+		events.FieldReason:          request.Reason,
+	})
+	return request, nil
+}
+
+// GetAccessRequests returns the access requests in the cluster
+func (o *Operator) GetAccessRequests(key ops.SiteKey) ([]storage.AccessRequest, error) {
+	requests, err := o.backend().GetAccessRequests()
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return requests, nil
+}
+
+// ReviewAccessRequest approves or denies a pending access request
+func (o *Operator) ReviewAccessRequest(ctx context.Context, req ops.ReviewAccessRequestRequest) (*storage.AccessRequest, error) {
+	if err := req.Check(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	request, err := users.ReviewAccessRequest(o.backend(), o.backend(), req.ID, req.Reviewer, req.Approve)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	if !req.Approve {
+		events.Emit(ctx, o, events.AccessRequestDenied, events.Fields{
+			events.FieldAccessRequestID: request.ID,
+			events.FieldOwner:           request.User,
+			events.FieldRoles:           request.Roles,
+		})
+		return request, nil
+	}
+	events.Emit(ctx, o, events.AccessRequestApproved, events.Fields{
+		events.FieldAccessRequestID: request.ID,
+		events.FieldOwner:           request.User,
+		events.FieldRoles:           request.GrantedRoles,
+		events.FieldExpires:         request.Expires,
+	})
+	return request, nil
+}
@@ -194,9 +194,10 @@ func (r *AgentService) Validate(ctx context.Context, key ops.SiteOperationKey, a
 		// Verify full requirements from the manifest
 		FullRequirements: true,
 		Options: &validationpb.ValidateOptions{
-			VxlanPort: int32(operation.Vars().OnPrem.VxlanPort),
-			DnsAddrs:  cluster.DNSConfig.Addrs,
-			DnsPort:   int32(cluster.DNSConfig.Port),
+			VxlanPort:     int32(operation.Vars().OnPrem.VxlanPort),
+			DnsAddrs:      cluster.DNSConfig.Addrs,
+			DnsPort:       int32(cluster.DNSConfig.Port),
+			AdvertiseAddr: addr,
 		},
 		Docker: &validationpb.Docker{
 			StorageDriver: cluster.ClusterState.Docker.StorageDriver,
@@ -251,6 +252,72 @@ func (r *AgentService) CheckBandwidth(ctx context.Context, key ops.SiteOperation
 	return results, nil
 }
 
+// CheckPathMTU executes the path MTU discovery test in the agent cluster
+func (r *AgentService) CheckPathMTU(ctx context.Context, key ops.SiteOperationKey, game checks.PingPongGame) (checks.PingPongGameResults, error) {
+	group, err := r.peerStore.getOrCreateGroup(key)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	results, err := pingPong(ctx, group.AgentGroup, game, pathMTU)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	return results, nil
+}
+
+// CheckLatency executes the latency test in the agent cluster
+func (r *AgentService) CheckLatency(ctx context.Context, key ops.SiteOperationKey, game checks.PingPongGame) (checks.PingPongGameResults, error) {
+	group, err := r.peerStore.getOrCreateGroup(key)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	results, err := pingPong(ctx, group.AgentGroup, game, latency)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	return results, nil
+}
+
+// CheckPacketLoss executes the UDP packet loss test in the agent cluster
+func (r *AgentService) CheckPacketLoss(ctx context.Context, key ops.SiteOperationKey, game checks.PingPongGame) (checks.PingPongGameResults, error) {
+	group, err := r.peerStore.getOrCreateGroup(key)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	results, err := pingPong(ctx, group.AgentGroup, game, packetLoss)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	return results, nil
+}
+
+// CheckVXLAN executes the overlay network data path test in the agent cluster
+// using the overlay network port configured for the operation
+func (r *AgentService) CheckVXLAN(ctx context.Context, key ops.SiteOperationKey, game checks.PingPongGame) (checks.PingPongGameResults, error) {
+	group, err := r.peerStore.getOrCreateGroup(key)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	operation, err := r.peerStore.backend.GetSiteOperation(key.SiteDomain, key.OperationID)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	results, err := pingPong(ctx, group.AgentGroup, game, vxlan(operation.Vars().OnPrem.VxlanPort))
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	return results, nil
+}
+
 // Wait blocks until the specified number of agents have connected for the
 // the given operation. Context can be used for canceling the operation.
 func (r *AgentService) Wait(ctx context.Context, key ops.SiteOperationKey, numAgents int) error {
@@ -330,6 +397,25 @@ func (r *AgentService) stopAgents(ctx context.Context, key ops.SiteOperationKey,
 	return nil
 }
 
+// GetAgentStatus returns the connection status of agents for the specified operation
+func (r *AgentService) GetAgentStatus(ctx context.Context, key ops.SiteOperationKey) ([]ops.AgentStatus, error) {
+	group, err := r.peerStore.getGroup(key)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return group.getStatus(), nil
+}
+
+// EvictAgent disconnects the agent specified with addr from the specified operation.
+// The operation itself is not affected
+func (r *AgentService) EvictAgent(ctx context.Context, key ops.SiteOperationKey, addr string) error {
+	group, err := r.peerStore.getGroup(key)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	return trace.Wrap(group.evict(addr))
+}
+
 // AgentService is a controller for install agents.
 // Implements ops.AgentService
 type AgentService struct {
@@ -341,13 +427,15 @@ type AgentService struct {
 
 // NewAgentPeerStore creates a new instance of this agent peer store
 func NewAgentPeerStore(backend storage.Backend, users users.Users,
-	teleport ops.TeleportProxyService, log log.FieldLogger) *AgentPeerStore {
+	teleport ops.TeleportProxyService, reconnectPolicy rpcserver.ReconnectPolicy,
+	log log.FieldLogger) *AgentPeerStore {
 	return &AgentPeerStore{
-		FieldLogger: log,
-		teleport:    teleport,
-		groups:      make(map[ops.SiteOperationKey]*agentGroup),
-		backend:     backend,
-		users:       users,
+		FieldLogger:     log,
+		teleport:        teleport,
+		groups:          make(map[ops.SiteOperationKey]*agentGroup),
+		backend:         backend,
+		users:           users,
+		reconnectPolicy: reconnectPolicy,
 	}
 }
 
@@ -381,7 +469,7 @@ func (r *AgentPeerStore) NewPeer(ctx netcontext.Context, req pb.PeerJoinRequest,
 		}
 	}
 
-	group.add(peer, info.GetHostname())
+	group.add(peer, info.GetHostname(), req.Version)
 	select {
 	case group.watchCh <- peer:
 		// Notify about a new peer
@@ -551,7 +639,8 @@ func (r *AgentPeerStore) removeGroup(ctx context.Context, key ops.SiteOperationK
 // Requires r.mu to be held.
 func (r *AgentPeerStore) addGroup(key ops.SiteOperationKey) (*agentGroup, error) {
 	config := rpcserver.AgentGroupConfig{
-		FieldLogger: log.StandardLogger(),
+		FieldLogger:     log.StandardLogger(),
+		ReconnectPolicy: r.reconnectPolicy,
 	}
 	group, err := rpcserver.NewAgentGroup(config, nil)
 	if err != nil {
@@ -562,6 +651,7 @@ func (r *AgentPeerStore) addGroup(key ops.SiteOperationKey) (*agentGroup, error)
 		AgentGroup: *group,
 		watchCh:    make(chan rpcserver.Peer),
 		hostnames:  make(map[string]string),
+		versions:   make(map[string]string),
 	}
 	r.WithField("key", key).Debug("Added group.")
 	r.groups[key] = agentGroup
@@ -577,13 +667,16 @@ type AgentPeerStore struct {
 	teleport ops.TeleportProxyService
 	mu       sync.Mutex
 	groups   map[ops.SiteOperationKey]*agentGroup
+	// reconnectPolicy configures the backoff for agent reconnects
+	reconnectPolicy rpcserver.ReconnectPolicy
 }
 
-func (r *agentGroup) add(p rpcserver.Peer, hostname string) {
+func (r *agentGroup) add(p rpcserver.Peer, hostname, version string) {
 	r.AgentGroup.Add(p)
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	r.hostnames[p.Addr()] = hostname
+	r.versions[p.Addr()] = version
 }
 
 func (r *agentGroup) remove(ctx netcontext.Context, p rpcserver.Peer, hostname string) {
@@ -591,6 +684,35 @@ func (r *agentGroup) remove(ctx netcontext.Context, p rpcserver.Peer, hostname s
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	delete(r.hostnames, p.Addr())
+	delete(r.versions, p.Addr())
+}
+
+func (r *agentGroup) evict(addr string) error {
+	if err := r.AgentGroup.Evict(addr); err != nil {
+		return trace.Wrap(err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.hostnames, addr)
+	delete(r.versions, addr)
+	return nil
+}
+
+func (r *agentGroup) getStatus() (agents []ops.AgentStatus) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, status := range r.AgentGroup.GetPeerStatus() {
+		agents = append(agents, ops.AgentStatus{
+			Addr:          status.Addr,
+			Hostname:      r.hostnames[status.Addr],
+			Version:       r.versions[status.Addr],
+			Connected:     status.Connected,
+			LastHeartbeat: status.LastHeartbeat,
+			RTT:           status.RTT,
+			Reconnects:    status.Reconnects,
+		})
+	}
+	return agents
 }
 
 // hasPeer determines whether the group already has a peer with the specified
@@ -617,6 +739,8 @@ type agentGroup struct {
 	mu      sync.Mutex
 	// hostnames maps peer address to a hostname
 	hostnames map[string]string
+	// versions maps peer address to the agent version
+	versions map[string]string
 }
 
 func pingPong(ctx context.Context, group rpcserver.AgentGroup, game checks.PingPongGame, fn pingpongHandler) (checks.PingPongGameResults, error) {
@@ -659,6 +783,44 @@ func bandwidth(ctx context.Context, group rpcserver.AgentGroup, addr string, req
 	resultsCh <- pingpongResult{addr: addr, resp: checks.ResultFromBandwidthProto(resp, nil)}
 }
 
+func pathMTU(ctx context.Context, group rpcserver.AgentGroup, addr string, req checks.PingPongRequest, resultsCh chan<- pingpongResult) {
+	resp, err := group.WithContext(ctx, addr).CheckPathMTU(ctx, req.PathMTUProto())
+	if err != nil {
+		resultsCh <- pingpongResult{addr: addr, err: err}
+		return
+	}
+	resultsCh <- pingpongResult{addr: addr, resp: checks.ResultFromPathMTUProto(resp, nil)}
+}
+
+func latency(ctx context.Context, group rpcserver.AgentGroup, addr string, req checks.PingPongRequest, resultsCh chan<- pingpongResult) {
+	resp, err := group.WithContext(ctx, addr).CheckLatency(ctx, req.LatencyProto())
+	if err != nil {
+		resultsCh <- pingpongResult{addr: addr, err: err}
+		return
+	}
+	resultsCh <- pingpongResult{addr: addr, resp: checks.ResultFromLatencyProto(resp, nil)}
+}
+
+func packetLoss(ctx context.Context, group rpcserver.AgentGroup, addr string, req checks.PingPongRequest, resultsCh chan<- pingpongResult) {
+	resp, err := group.WithContext(ctx, addr).CheckPacketLoss(ctx, req.PacketLossProto())
+	if err != nil {
+		resultsCh <- pingpongResult{addr: addr, err: err}
+		return
+	}
+	resultsCh <- pingpongResult{addr: addr, resp: checks.ResultFromPacketLossProto(resp, nil)}
+}
+
+func vxlan(port int) pingpongHandler {
+	return func(ctx context.Context, group rpcserver.AgentGroup, addr string, req checks.PingPongRequest, resultsCh chan<- pingpongResult) {
+		resp, err := group.WithContext(ctx, addr).CheckVXLAN(ctx, req.VXLANProto(port))
+		if err != nil {
+			resultsCh <- pingpongResult{addr: addr, err: err}
+			return
+		}
+		resultsCh <- pingpongResult{addr: addr, resp: checks.ResultFromVXLANProto(resp, nil)}
+	}
+}
+
 type pingpongHandler func(ctx context.Context, group rpcserver.AgentGroup, addr string, req checks.PingPongRequest, resultsCh chan<- pingpongResult)
 
 type pingpongResult struct {
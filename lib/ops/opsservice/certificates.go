@@ -21,8 +21,11 @@ import (
 
 	"github.com/gravitational/gravity/lib/constants"
 	"github.com/gravitational/gravity/lib/defaults"
+	"github.com/gravitational/gravity/lib/loc"
 	"github.com/gravitational/gravity/lib/ops"
 	"github.com/gravitational/gravity/lib/ops/events"
+	"github.com/gravitational/gravity/lib/rpc"
+	pb "github.com/gravitational/gravity/lib/rpc/proto"
 	"github.com/gravitational/rigging"
 	"github.com/gravitational/trace"
 
@@ -154,3 +157,24 @@ func UpdateClusterCertificate(client *kubernetes.Clientset, req ops.UpdateCertif
 
 	return nil
 }
+
+// GetRPCCertificateAuthority returns the CA certificate bundle trusted by the cluster RPC agents
+func (o *Operator) GetRPCCertificateAuthority(key ops.SiteKey) (*ops.RPCCertificateAuthority, error) {
+	archive, err := rpc.CredentialsFromPackage(o.packages(), loc.RPCSecrets)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	ca, ok := archive[pb.CA]
+	if !ok {
+		return nil, trace.NotFound("RPC credentials have no CA certificate")
+	}
+	return &ops.RPCCertificateAuthority{Certificate: ca.CertPEM}, nil
+}
+
+// UpdateRPCCredentials replaces the cluster RPC agent credentials
+func (o *Operator) UpdateRPCCredentials(ctx context.Context, req ops.UpdateRPCCredentialsRequest) error {
+	if err := rpc.VerifyCredentials(req.Credentials, o.cfg.Clock.UtcNow()); err != nil {
+		return trace.Wrap(err)
+	}
+	return trace.Wrap(rpc.UpsertCredentialsPackage(o.packages(), loc.RPCSecrets, req.Credentials))
+}
@@ -44,8 +44,14 @@ func (o *Operator) ValidateServers(ctx context.Context, req ops.ValidateServersR
 		return trace.Wrap(err)
 	}
 
-	err = ops.CheckServers(ctx, op.Key(), infos, req.Servers,
+	benchmarks, err := ops.CheckServers(ctx, op.Key(), infos, req.Servers,
 		cluster.agentService(), cluster.app.Manifest)
+	if len(benchmarks) != 0 && op.InstallExpand != nil {
+		op.InstallExpand.DiskBenchmarks = benchmarks
+		if _, err := cluster.updateSiteOperation(op); err != nil {
+			log.WithError(err).Warn("Failed to save disk benchmarks.")
+		}
+	}
 	if err != nil {
 		return trace.Wrap(ops.FormatValidationError(err))
 	}
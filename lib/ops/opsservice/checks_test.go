@@ -0,0 +1,82 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package opsservice
+
+import (
+	"time"
+
+	"github.com/gravitational/gravity/lib/ops"
+	"github.com/gravitational/gravity/lib/ops/suite"
+	"github.com/gravitational/gravity/lib/schema"
+	"github.com/gravitational/gravity/lib/storage"
+
+	"gopkg.in/check.v1"
+)
+
+type ChecksSuite struct {
+	operator *Operator
+	cluster  *ops.Site
+}
+
+var _ = check.Suite(&ChecksSuite{})
+
+func (s *ChecksSuite) SetUpTest(c *check.C) {
+	services := SetupTestServices(c)
+	s.operator = services.Operator
+
+	suite := &suite.OpsSuite{}
+	app, err := suite.SetUpTestPackage(services.Apps, services.Packages, c)
+	c.Assert(err, check.IsNil)
+
+	account, err := s.operator.CreateAccount(ops.NewAccountRequest{
+		Org: "checks.test",
+	})
+	c.Assert(err, check.IsNil)
+
+	s.cluster, err = s.operator.CreateSite(ops.NewSiteRequest{
+		AccountID:  account.ID,
+		AppPackage: app.String(),
+		Provider:   schema.ProvisionerOnPrem,
+		DomainName: "checks.test",
+	})
+	c.Assert(err, check.IsNil)
+}
+
+func (s *ChecksSuite) TestSavesDiskBenchmarks(c *check.C) {
+	key, err := s.operator.getOperationGroup(s.cluster.Key()).createSiteOperation(ops.SiteOperation{
+		AccountID:     s.cluster.AccountID,
+		SiteDomain:    s.cluster.Domain,
+		Type:          ops.OperationInstall,
+		State:         ops.OperationStateInstallInitiated,
+		InstallExpand: &storage.InstallExpandOperationState{},
+	})
+	c.Assert(err, check.IsNil)
+
+	benchmarks := []storage.DiskBenchmark{{
+		Hostname:     "node-1",
+		AdvertiseIP:  "10.0.0.1",
+		Path:         "/var/lib/gravity/planet/etcd",
+		Profile:      schema.DiskProfileEtcd,
+		WriteIOPS:    100,
+		FsyncLatency: map[string]time.Duration{"99": time.Millisecond},
+	}}
+	c.Assert(s.operator.SaveDiskBenchmarks(*key, benchmarks), check.IsNil)
+
+	operation, err := s.operator.GetSiteOperation(*key)
+	c.Assert(err, check.IsNil)
+	c.Assert(operation.InstallExpand.DiskBenchmarks, check.DeepEquals, benchmarks)
+}
@@ -119,6 +119,36 @@ func (o *Operator) UpdateClusterConfiguration(req ops.UpdateClusterConfigRequest
 		_, err := configmaps.Update(configmap)
 		return trace.Wrap(err)
 	})
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if req.DockerStorageDriver == "" {
+		return nil
+	}
+	return trace.Wrap(o.setDockerStorageDriver(req.ClusterKey, req.DockerStorageDriver))
+}
+
+// setDockerStorageDriver persists the specified Docker storage driver in the cluster state
+// and the install operation so that the subsequent expand and upgrade operations
+// configure new nodes with the driver the cluster is actually using
+func (o *Operator) setDockerStorageDriver(key ops.SiteKey, driver string) error {
+	cluster, err := o.backend().GetSite(key.SiteDomain)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	cluster.ClusterState.Docker.StorageDriver = driver
+	if _, err := o.backend().UpdateSite(*cluster); err != nil {
+		return trace.Wrap(err)
+	}
+	operation, err := ops.GetCompletedInstallOperation(key, o)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if operation.InstallExpand == nil {
+		return trace.NotFound("install operation %v has no state", operation.ID)
+	}
+	operation.InstallExpand.Vars.System.Docker.StorageDriver = driver
+	_, err = o.backend().UpdateSiteOperation((storage.SiteOperation)(*operation))
 	return trace.Wrap(err)
 }
 
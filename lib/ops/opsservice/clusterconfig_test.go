@@ -0,0 +1,109 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package opsservice
+
+import (
+	"time"
+
+	"github.com/gravitational/gravity/lib/constants"
+	"github.com/gravitational/gravity/lib/ops"
+	"github.com/gravitational/gravity/lib/ops/suite"
+	"github.com/gravitational/gravity/lib/schema"
+	"github.com/gravitational/gravity/lib/storage"
+
+	"gopkg.in/check.v1"
+)
+
+type ClusterConfigSuite struct {
+	operator *Operator
+	cluster  *ops.Site
+}
+
+var _ = check.Suite(&ClusterConfigSuite{})
+
+func (s *ClusterConfigSuite) SetUpTest(c *check.C) {
+	services := SetupTestServices(c)
+	s.operator = services.Operator
+
+	suite := &suite.OpsSuite{}
+	app, err := suite.SetUpTestPackage(services.Apps, services.Packages, c)
+	c.Assert(err, check.IsNil)
+
+	account, err := s.operator.CreateAccount(ops.NewAccountRequest{
+		Org: "clusterconfig.test",
+	})
+	c.Assert(err, check.IsNil)
+
+	s.cluster, err = s.operator.CreateSite(ops.NewSiteRequest{
+		AccountID:  account.ID,
+		AppPackage: app.String(),
+		Provider:   schema.ProvisionerOnPrem,
+		DomainName: "clusterconfig.test",
+	})
+	c.Assert(err, check.IsNil)
+}
+
+// TestExpandAfterStorageMigration verifies that the Docker storage driver
+// the expand operation configures new nodes with follows the storage migration
+// and its rollback
+func (s *ClusterConfigSuite) TestExpandAfterStorageMigration(c *check.C) {
+	cluster, err := s.operator.backend().GetSite(s.cluster.Domain)
+	c.Assert(err, check.IsNil)
+	cluster.ClusterState.Docker.StorageDriver = constants.DockerStorageDriverDevicemapper
+	_, err = s.operator.backend().UpdateSite(*cluster)
+	c.Assert(err, check.IsNil)
+
+	var vars storage.OperationVariables
+	vars.System.Docker.StorageDriver = constants.DockerStorageDriverDevicemapper
+	key, err := s.operator.getOperationGroup(s.cluster.Key()).createSiteOperation(ops.SiteOperation{
+		AccountID:     s.cluster.AccountID,
+		SiteDomain:    s.cluster.Domain,
+		Type:          ops.OperationInstall,
+		State:         ops.OperationStateCompleted,
+		InstallExpand: &storage.InstallExpandOperationState{Vars: vars},
+	})
+	c.Assert(err, check.IsNil)
+	_, err = s.operator.backend().CreateProgressEntry(storage.ProgressEntry{
+		SiteDomain:  key.SiteDomain,
+		OperationID: key.OperationID,
+		Completion:  constants.Completed,
+		State:       ops.ProgressStateCompleted,
+		Created:     time.Now(),
+	})
+	c.Assert(err, check.IsNil)
+
+	err = s.operator.setDockerStorageDriver(s.cluster.Key(), constants.DockerStorageDriverOverlay2)
+	c.Assert(err, check.IsNil)
+	s.assertStorageDriver(c, constants.DockerStorageDriverOverlay2)
+
+	// rollback
+	err = s.operator.setDockerStorageDriver(s.cluster.Key(), constants.DockerStorageDriverDevicemapper)
+	c.Assert(err, check.IsNil)
+	s.assertStorageDriver(c, constants.DockerStorageDriverDevicemapper)
+}
+
+func (s *ClusterConfigSuite) assertStorageDriver(c *check.C, driver string) {
+	// expand validation uses the cluster state
+	cluster, err := s.operator.backend().GetSite(s.cluster.Domain)
+	c.Assert(err, check.IsNil)
+	c.Assert(cluster.ClusterState.Docker.StorageDriver, check.Equals, driver)
+
+	// node bootstrap uses the install operation variables
+	operation, err := ops.GetCompletedInstallOperation(s.cluster.Key(), s.operator)
+	c.Assert(err, check.IsNil)
+	c.Assert(operation.InstallExpand.Vars.System.Docker.StorageDriver, check.Equals, driver)
+}
@@ -959,7 +959,7 @@ func (s *site) getPlanetConfig(config planetConfig) (args []string, err error) {
 	args = append(args, fmt.Sprintf("--dns-port=%v", dnsConfig.Port))
 
 	dockerArgs, err := configureDockerOptions(config.installExpand, node,
-		config.docker, config.dockerRuntime)
+		overrideDockerConfig(config.docker, config.config), config.dockerRuntime)
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
@@ -1439,6 +1439,18 @@ func (s *site) addClusterConfig(config clusterconfig.Interface, overrideArgs map
 	return args
 }
 
+// overrideDockerConfig returns the Docker configuration with the storage driver
+// overridden by the cluster configuration if specified
+func overrideDockerConfig(docker storage.DockerConfig, config clusterconfig.Interface) storage.DockerConfig {
+	if config == nil {
+		return docker
+	}
+	if dockerConfig := config.GetDockerConfig(); dockerConfig != nil && dockerConfig.StorageDriver != "" {
+		docker.StorageDriver = dockerConfig.StorageDriver
+	}
+	return docker
+}
+
 // configureDockerOptions creates a set of Docker-specific command line arguments to Planet on the specified node
 // based on the operation op and docker manifest configuration block.
 func configureDockerOptions(
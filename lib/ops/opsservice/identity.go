@@ -19,8 +19,11 @@ package opsservice
 import (
 	"context"
 
+	"github.com/gravitational/gravity/lib/defaults"
+	"github.com/gravitational/gravity/lib/httplib"
 	"github.com/gravitational/gravity/lib/ops"
 	"github.com/gravitational/gravity/lib/ops/events"
+	"github.com/gravitational/gravity/lib/users"
 
 	teleservices "github.com/gravitational/teleport/lib/services"
 	"github.com/gravitational/trace"
@@ -107,3 +110,47 @@ func (o *Operator) DeleteGithubConnector(ctx context.Context, key ops.SiteKey, n
 	})
 	return nil
 }
+
+// UpsertOIDCConnector creates or updates an OIDC connector
+//
+// The connector is verified against the identity provider before it is saved.
+func (o *Operator) UpsertOIDCConnector(ctx context.Context, key ops.SiteKey, connector teleservices.OIDCConnector) error {
+	checkCtx, cancel := context.WithTimeout(ctx, defaults.OIDCDiscoveryTimeout)
+	defer cancel()
+	err := users.CheckOIDCConnector(checkCtx, httplib.GetClient(false), connector, o.cfg.Users)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if err := o.cfg.Users.UpsertOIDCConnector(connector); err != nil {
+		return trace.Wrap(err)
+	}
+	events.Emit(ctx, o, events.OIDCConnectorCreated, events.Fields{
+		events.FieldName: connector.GetName(),
+	})
+	return nil
+}
+
+// GetOIDCConnector returns an OIDC connector by name
+//
+// Returned connector exclude client secret unless withSecrets is true.
+func (o *Operator) GetOIDCConnector(key ops.SiteKey, name string, withSecrets bool) (teleservices.OIDCConnector, error) {
+	return o.cfg.Users.GetOIDCConnector(name, withSecrets)
+}
+
+// GetOIDCConnectors returns all OIDC connectors
+//
+// Returned connectors exclude client secret unless withSecrets is true.
+func (o *Operator) GetOIDCConnectors(key ops.SiteKey, withSecrets bool) ([]teleservices.OIDCConnector, error) {
+	return o.cfg.Users.GetOIDCConnectors(withSecrets)
+}
+
+// DeleteOIDCConnector deletes an OIDC connector by name
+func (o *Operator) DeleteOIDCConnector(ctx context.Context, key ops.SiteKey, name string) error {
+	if err := o.cfg.Users.DeleteOIDCConnector(name); err != nil {
+		return trace.Wrap(err)
+	}
+	events.Emit(ctx, o, events.OIDCConnectorDeleted, events.Fields{
+		events.FieldName: name,
+	})
+	return nil
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package opsservice
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/gravitational/gravity/lib/constants"
+	"github.com/gravitational/gravity/lib/defaults"
+	"github.com/gravitational/gravity/lib/ops"
+	"github.com/gravitational/gravity/lib/ops/events"
+	"github.com/gravitational/gravity/lib/storage"
+
+	"github.com/gravitational/rigging"
+	"github.com/gravitational/trace"
+)
+
+// GetMaintenanceWindow returns the cluster maintenance window
+func (o *Operator) GetMaintenanceWindow(key ops.SiteKey) (storage.MaintenanceWindow, error) {
+	client, err := o.GetKubeClient()
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	data, err := getConfigMap(client.CoreV1().ConfigMaps(defaults.KubeSystemNamespace),
+		constants.MaintenanceWindowConfigMap)
+	if err != nil {
+		if trace.IsNotFound(err) {
+			return nil, trace.NotFound("no maintenance window configured")
+		}
+		return nil, trace.Wrap(err)
+	}
+	window, err := storage.UnmarshalMaintenanceWindow([]byte(data))
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return window, nil
+}
+
+// UpdateMaintenanceWindow updates the cluster maintenance window
+func (o *Operator) UpdateMaintenanceWindow(ctx context.Context, key ops.SiteKey, window storage.MaintenanceWindow) error {
+	if err := window.CheckAndSetDefaults(); err != nil {
+		return trace.Wrap(err)
+	}
+	client, err := o.GetKubeClient()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	data, err := storage.MarshalMaintenanceWindow(window)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	err = updateConfigMap(client.CoreV1().ConfigMaps(defaults.KubeSystemNamespace),
+		constants.MaintenanceWindowConfigMap, defaults.KubeSystemNamespace, string(data), nil)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	events.Emit(ctx, o, events.MaintenanceWindowUpdated)
+	return nil
+}
+
+// DeleteMaintenanceWindow deletes the cluster maintenance window
+func (o *Operator) DeleteMaintenanceWindow(ctx context.Context, key ops.SiteKey) error {
+	client, err := o.GetKubeClient()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	err = rigging.ConvertError(client.CoreV1().ConfigMaps(defaults.KubeSystemNamespace).
+		Delete(constants.MaintenanceWindowConfigMap, nil))
+	if err != nil {
+		if trace.IsNotFound(err) {
+			return trace.NotFound("no maintenance window configured")
+		}
+		return trace.Wrap(err)
+	}
+	events.Emit(ctx, o, events.MaintenanceWindowDeleted)
+	return nil
+}
+
+// checkMaintenanceWindow returns an error if the cluster has a maintenance
+// window configured and the current time is outside of it.
+//
+// The window is only enforced by the cluster's own operator since it is
+// stored in the cluster's Kubernetes
+func (o *Operator) checkMaintenanceWindow(key ops.SiteKey, action string) error {
+	if !o.cfg.Local {
+		return nil
+	}
+	window, err := o.GetMaintenanceWindow(key)
+	if err != nil {
+		if trace.IsNotFound(err) {
+			return nil
+		}
+		return trace.Wrap(err)
+	}
+	return trace.Wrap(checkMaintenanceWindow(window, o.clock().UtcNow(), action))
+}
+
+func checkMaintenanceWindow(window storage.MaintenanceWindow, now time.Time, action string) error {
+	if window.IsOpen(now) {
+		return nil
+	}
+	var windows []string
+	for _, w := range window.GetWindows() {
+		windows = append(windows, w.String())
+	}
+	return trace.CompareFailed("%v is only allowed during the cluster maintenance window (%v)",
+		action, strings.Join(windows, ", "))
+}
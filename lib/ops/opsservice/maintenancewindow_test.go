@@ -0,0 +1,49 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package opsservice
+
+import (
+	"time"
+
+	"github.com/gravitational/gravity/lib/storage"
+
+	teleservices "github.com/gravitational/teleport/lib/services"
+	"github.com/gravitational/trace"
+	"gopkg.in/check.v1"
+)
+
+type MaintenanceWindowSuite struct{}
+
+var _ = check.Suite(&MaintenanceWindowSuite{})
+
+func (s *MaintenanceWindowSuite) TestEnforcesWindow(c *check.C) {
+	window := storage.NewMaintenanceWindow(storage.MaintenanceWindowSpecV1{
+		Windows: []storage.TimeWindow{
+			{
+				Days:     []string{"Sat"},
+				Start:    "02:00",
+				Duration: teleservices.NewDuration(2 * time.Hour),
+			},
+		},
+	})
+	// Saturday, 03:00 UTC
+	err := checkMaintenanceWindow(window, time.Date(2019, time.June, 1, 3, 0, 0, 0, time.UTC), "update")
+	c.Assert(err, check.IsNil)
+	// Saturday, 05:00 UTC
+	err = checkMaintenanceWindow(window, time.Date(2019, time.June, 1, 5, 0, 0, 0, time.UTC), "update")
+	c.Assert(trace.IsCompareFailed(err), check.Equals, true, check.Commentf("%v", err))
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package opsservice
+
+import (
+	"context"
+
+	"github.com/gravitational/gravity/lib/ops"
+	"github.com/gravitational/gravity/lib/ops/events"
+	"github.com/gravitational/gravity/lib/storage"
+
+	"github.com/gravitational/trace"
+)
+
+// GetMFAPolicy returns the cluster second factor policy
+func (o *Operator) GetMFAPolicy(key ops.SiteKey) (storage.MFAPolicy, error) {
+	policy, err := o.backend().GetMFAPolicy()
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return policy, nil
+}
+
+// UpdateMFAPolicy updates the cluster second factor policy
+func (o *Operator) UpdateMFAPolicy(ctx context.Context, key ops.SiteKey, policy storage.MFAPolicy) error {
+	if err := policy.CheckAndSetDefaults(); err != nil {
+		return trace.Wrap(err)
+	}
+	if err := o.backend().UpsertMFAPolicy(policy); err != nil {
+		return trace.Wrap(err)
+	}
+	events.Emit(ctx, o, events.MFAPolicyUpdated)
+	return nil
+}
+
+// DeleteMFAPolicy deletes the cluster second factor policy
+func (o *Operator) DeleteMFAPolicy(ctx context.Context, key ops.SiteKey) error {
+	if err := o.backend().DeleteMFAPolicy(); err != nil {
+		return trace.Wrap(err)
+	}
+	events.Emit(ctx, o, events.MFAPolicyDeleted)
+	return nil
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package opsservice
+
+import (
+	"context"
+
+	"github.com/gravitational/gravity/lib/constants"
+	"github.com/gravitational/gravity/lib/defaults"
+	"github.com/gravitational/gravity/lib/ops"
+	"github.com/gravitational/gravity/lib/ops/events"
+	"github.com/gravitational/gravity/lib/storage"
+
+	"github.com/gravitational/rigging"
+	"github.com/gravitational/trace"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GetNodePools returns the list of configured node pools
+func (o *Operator) GetNodePools(key ops.SiteKey) ([]storage.NodePool, error) {
+	client, err := o.GetKubeClient()
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	configmaps, err := client.CoreV1().ConfigMaps(defaults.KubeSystemNamespace).List(metav1.ListOptions{
+		LabelSelector: defaults.NodePoolLabel,
+	})
+	if err != nil {
+		return nil, trace.Wrap(rigging.ConvertError(err))
+	}
+	var errors []error
+	pools := make([]storage.NodePool, 0, len(configmaps.Items))
+	for _, config := range configmaps.Items {
+		data, ok := config.Data[constants.ResourceSpecKey]
+		if !ok {
+			continue
+		}
+		pool, err := storage.UnmarshalNodePool([]byte(data))
+		if err != nil {
+			errors = append(errors, err)
+			continue
+		}
+		pools = append(pools, pool)
+	}
+	if len(errors) != 0 {
+		return nil, trace.NewAggregate(errors...)
+	}
+	return pools, nil
+}
+
+// UpdateNodePool creates or updates the specified node pool
+func (o *Operator) UpdateNodePool(ctx context.Context, key ops.SiteKey, pool storage.NodePool) error {
+	if err := pool.CheckAndSetDefaults(); err != nil {
+		return trace.Wrap(err)
+	}
+	client, err := o.GetKubeClient()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	data, err := storage.MarshalNodePool(pool)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	err = updateConfigMap(client.CoreV1().ConfigMaps(defaults.KubeSystemNamespace),
+		nodePoolConfigMapName(pool.GetName()), defaults.KubeSystemNamespace, string(data),
+		map[string]string{defaults.NodePoolLabel: pool.GetName()})
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	events.Emit(ctx, o, events.NodePoolUpdated, events.Fields{
+		events.FieldName: pool.GetName(),
+	})
+	return nil
+}
+
+// DeleteNodePool deletes the node pool specified with name.
+// Nodes provisioned from the pool remain in the cluster
+func (o *Operator) DeleteNodePool(ctx context.Context, key ops.SiteKey, name string) error {
+	client, err := o.GetKubeClient()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	err = rigging.ConvertError(client.CoreV1().ConfigMaps(defaults.KubeSystemNamespace).
+		Delete(nodePoolConfigMapName(name), nil))
+	if err != nil {
+		if trace.IsNotFound(err) {
+			return trace.NotFound("node pool %q not found", name)
+		}
+		return trace.Wrap(err)
+	}
+	events.Emit(ctx, o, events.NodePoolDeleted, events.Fields{
+		events.FieldName: name,
+	})
+	return nil
+}
+
+func nodePoolConfigMapName(name string) string {
+	return constants.NodePoolConfigMapPrefix + name
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package opsservice
+
+import (
+	"context"
+
+	"github.com/gravitational/gravity/lib/ops"
+	"github.com/gravitational/gravity/lib/ops/events"
+	"github.com/gravitational/gravity/lib/storage"
+
+	"github.com/gravitational/trace"
+)
+
+// GetPasswordPolicy returns the cluster password policy
+func (o *Operator) GetPasswordPolicy(key ops.SiteKey) (storage.PasswordPolicy, error) {
+	policy, err := o.backend().GetPasswordPolicy()
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return policy, nil
+}
+
+// UpdatePasswordPolicy updates the cluster password policy
+func (o *Operator) UpdatePasswordPolicy(ctx context.Context, key ops.SiteKey, policy storage.PasswordPolicy) error {
+	if err := policy.CheckAndSetDefaults(); err != nil {
+		return trace.Wrap(err)
+	}
+	if err := o.backend().UpsertPasswordPolicy(policy); err != nil {
+		return trace.Wrap(err)
+	}
+	events.Emit(ctx, o, events.PasswordPolicyUpdated)
+	return nil
+}
+
+// DeletePasswordPolicy deletes the cluster password policy
+func (o *Operator) DeletePasswordPolicy(ctx context.Context, key ops.SiteKey) error {
+	if err := o.backend().DeletePasswordPolicy(); err != nil {
+		return trace.Wrap(err)
+	}
+	events.Emit(ctx, o, events.PasswordPolicyDeleted)
+	return nil
+}
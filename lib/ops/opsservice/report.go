@@ -246,6 +246,7 @@ func runCollectors(site site, dir string) error {
 	collectors := []collectorFn{
 		collectSiteInfo(*storageSite),
 		collectDumpHook,
+		collectDiskBenchmarks,
 	}
 	reportWriter := report.NewFileWriter(dir)
 
@@ -321,6 +322,35 @@ func collectDumpHook(reportWriter report.FileWriter, site site) error {
 	return trace.Wrap(err)
 }
 
+// collectDiskBenchmarks returns JSON-formatted results of disk benchmarks
+// executed during install and expand operations
+func collectDiskBenchmarks(reportWriter report.FileWriter, site site) error {
+	operations, err := site.service.GetSiteOperations(site.key)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	for _, op := range operations {
+		if op.InstallExpand == nil || len(op.InstallExpand.DiskBenchmarks) == 0 {
+			continue
+		}
+		err := collectOperationDiskBenchmarks(reportWriter, ops.SiteOperation(op))
+		if err != nil {
+			return trace.Wrap(err)
+		}
+	}
+	return nil
+}
+
+func collectOperationDiskBenchmarks(reportWriter report.FileWriter, operation ops.SiteOperation) error {
+	w, err := reportWriter.NewWriter(fmt.Sprintf(diskBenchmarksFilename, operation.Type, operation.ID))
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	defer w.Close()
+	enc := json.NewEncoder(w)
+	return trace.Wrap(enc.Encode(operation.InstallExpand.DiskBenchmarks))
+}
+
 // collectOperationLogs streams logs of the specified operation using the specified writer
 func collectOperationLogs(site site, operation ops.SiteOperation, reportWriter report.FileWriter) error {
 	w, err := reportWriter.NewWriter(fmt.Sprintf(opLogsFilename, operation.Type, operation.ID))
@@ -360,4 +390,7 @@ const (
 	// opLogsFilename defines the file pattern that stores operation log for a particular
 	// cluster operation
 	opLogsFilename = "%v.%v"
+	// diskBenchmarksFilename defines the file pattern that stores disk benchmark
+	// results of a particular cluster operation
+	diskBenchmarksFilename = "disk-benchmarks-%v.%v.json"
 )
@@ -361,6 +361,9 @@ func (o *Operator) CreateAPIKey(ctx context.Context, req ops.NewAPIKeyRequest) (
 		UserEmail: req.UserEmail,
 		Expires:   req.Expires,
 		Token:     req.Token,
+		Roles:     req.Roles,
+		Rules:     req.Rules,
+		Clusters:  req.Clusters,
 	}, req.Upsert)
 	if err != nil {
 		return nil, trace.Wrap(err)
@@ -670,7 +673,7 @@ func (o *Operator) CreateSite(r ops.NewSiteRequest) (*ops.Site, error) {
 			OpsCenter: opsCenter,
 		}))
 	if err != nil {
-		defer o.DeleteSite(siteKey)
+		defer o.DeleteSite(context.TODO(), siteKey)
 		return nil, trace.Wrap(err)
 	}
 
@@ -680,7 +683,7 @@ func (o *Operator) CreateSite(r ops.NewSiteRequest) (*ops.Site, error) {
 			UserEmail: agent.GetName(),
 		}, false)
 		if err != nil {
-			if errDelete := o.DeleteSite(siteKey); errDelete != nil {
+			if errDelete := o.DeleteSite(context.TODO(), siteKey); errDelete != nil {
 				log.Errorf("Failed to remove cluster %v: %v.", siteKey, trace.DebugReport(errDelete))
 			}
 			return nil, trace.Wrap(err)
@@ -813,6 +816,14 @@ func (o *Operator) SignSSHKey(req ops.SSHSignRequest) (*ops.SSHSignResponse, err
 	if req.TTL <= 0 || req.TTL > constants.MaxInteractiveSessionTTL {
 		req.TTL = constants.MaxInteractiveSessionTTL
 	}
+	// certificates must not outlive the temporary access granted to the user
+	expires, ok, err := users.AccessExpires(o.backend(), o.backend(), req.User)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	if ttl := expires.Sub(o.backend().Now()); ok && ttl < req.TTL {
+		req.TTL = ttl
+	}
 	proxy := o.cfg.TeleportProxy
 	cert, err := proxy.GenerateUserCert(req.PublicKey, req.User, req.TTL)
 	if err != nil {
@@ -909,6 +920,25 @@ func (o *Operator) UpdateExpandOperationState(key ops.SiteOperationKey, req ops.
 	return trace.Wrap(site.updateOperationState(op, req))
 }
 
+// SaveDiskBenchmarks stores the results of disk benchmarks executed
+// during the specified install or expand operation
+func (o *Operator) SaveDiskBenchmarks(key ops.SiteOperationKey, benchmarks []storage.DiskBenchmark) error {
+	site, err := o.openSite(key.SiteKey())
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	op, err := site.getSiteOperation(key.OperationID)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if op.InstallExpand == nil {
+		return trace.BadParameter("expected install or expand operation, got: %v", op)
+	}
+	op.InstallExpand.DiskBenchmarks = append(op.InstallExpand.DiskBenchmarks, benchmarks...)
+	_, err = site.updateSiteOperation(op)
+	return trace.Wrap(err)
+}
+
 // DeleteSiteOperationState removes an unstarted operation and resets site state to active
 func (o *Operator) DeleteSiteOperation(key ops.SiteOperationKey) (err error) {
 	cluster, err := o.openSite(ops.SiteKey{AccountID: key.AccountID, SiteDomain: key.SiteDomain})
@@ -984,6 +1014,12 @@ func (o *Operator) CreateSiteAppUpdateOperation(ctx context.Context, r ops.Creat
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
+	if r.Automatic {
+		err = o.checkMaintenanceWindow(site.key, "automatic update")
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+	}
 	key, err := site.createUpdateOperation(ctx, r)
 	if err != nil {
 		return nil, trace.Wrap(err)
@@ -1019,6 +1055,13 @@ func (o *Operator) CreateClusterGarbageCollectOperation(ctx context.Context, r o
 		return nil, trace.Wrap(err)
 	}
 
+	if !r.Force {
+		err = o.checkMaintenanceWindow(cluster.key, "garbage collection")
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+	}
+
 	key, err := cluster.createGarbageCollectOperation(ctx, r)
 	if err != nil {
 		return nil, trace.Wrap(err)
@@ -1137,6 +1180,20 @@ func (o *Operator) GetSiteOperationProgress(key ops.SiteOperationKey) (*ops.Prog
 	return &progressEntry, nil
 }
 
+// GetOperationAgents returns the status of agents connected for the specified operation
+func (o *Operator) GetOperationAgents(key ops.SiteOperationKey) ([]ops.AgentStatus, error) {
+	agents, err := o.cfg.Agents.GetAgentStatus(context.TODO(), key)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return agents, nil
+}
+
+// EvictOperationAgent disconnects the agent specified with addr from the operation
+func (o *Operator) EvictOperationAgent(key ops.SiteOperationKey, addr string) error {
+	return trace.Wrap(o.cfg.Agents.EvictAgent(context.TODO(), key, addr))
+}
+
 func (o *Operator) CreateProgressEntry(key ops.SiteOperationKey, entry ops.ProgressEntry) error {
 	_, err := o.backend().CreateProgressEntry(storage.ProgressEntry(entry))
 	if err != nil {
@@ -1146,7 +1203,7 @@ func (o *Operator) CreateProgressEntry(key ops.SiteOperationKey, entry ops.Progr
 	return nil
 }
 
-func (o *Operator) DeleteSite(key ops.SiteKey) error {
+func (o *Operator) DeleteSite(ctx context.Context, key ops.SiteKey) error {
 	st, err := o.openSite(key)
 	if err != nil {
 		return trace.Wrap(err)
@@ -1401,19 +1458,48 @@ func (o *Operator) GetClusterNodes(key ops.SiteKey) ([]ops.Node, error) {
 }
 
 // EmitAuditEvent saves the provided event in the audit log.
+//
+// The event is appended to the hash-chained audit log first, so every
+// event in the Teleport audit log is also in the chain. If the event cannot
+// be emitted to the Teleport audit log, the record is marked pending and
+// the event is emitted again later, see storage.AuditRecords
 func (o *Operator) EmitAuditEvent(ctx context.Context, req ops.AuditEventRequest) error {
 	err := req.Check()
 	if err != nil {
 		return trace.Wrap(err)
 	}
 	o.Infof("%s.", req)
-	err = o.cfg.AuditLog.EmitAuditEvent(req.Event, req.Fields)
+	record, err := storage.NewAuditRecord(req.Event.Name, req.Event.Code, req.Fields, o.backend().Now())
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	record, err = o.backend().AppendAuditRecord(*record)
 	if err != nil {
 		return trace.Wrap(err)
 	}
+	emitErr := o.cfg.AuditLog.EmitAuditEvent(req.Event, req.Fields)
+	if emitErr == nil {
+		return nil
+	}
+	o.WithError(emitErr).Warnf("Failed to emit audit event %v, will retry.", record.Index)
+	if err := o.backend().AddPendingAuditRecord(record.Index); err != nil {
+		return trace.NewAggregate(emitErr, err)
+	}
 	return nil
 }
 
+// GetAuditRecords returns the hash-chained audit log records.
+func (o *Operator) GetAuditRecords(ctx context.Context, req ops.GetAuditRecordsRequest) ([]storage.AuditRecord, error) {
+	if err := req.Check(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	records, err := o.backend().GetAuditRecords(req.Filter())
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return records, nil
+}
+
 func (o *Operator) openSite(key ops.SiteKey) (*site, error) {
 	site, err := o.backend().GetSite(key.SiteDomain)
 	if err != nil {
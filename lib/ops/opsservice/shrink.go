@@ -18,9 +18,12 @@ package opsservice
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 
+	"github.com/gravitational/gravity/lib/clients"
 	"github.com/gravitational/gravity/lib/constants"
 	"github.com/gravitational/gravity/lib/defaults"
 	"github.com/gravitational/gravity/lib/loc"
@@ -31,6 +34,7 @@ import (
 	"github.com/gravitational/gravity/lib/users"
 	"github.com/gravitational/gravity/lib/utils"
 
+	etcd "github.com/coreos/etcd/client"
 	teleservices "github.com/gravitational/teleport/lib/services"
 	"github.com/gravitational/trace"
 	"github.com/pborman/uuid"
@@ -52,6 +56,12 @@ func (s *site) createShrinkOperation(context context.Context, req ops.CreateSite
 		return nil, trace.Wrap(err)
 	}
 
+	if req.Replace && server.IsMaster() {
+		if err := s.checkEtcdQuorum(context, *server); err != nil {
+			return nil, trace.Wrap(err)
+		}
+	}
+
 	op := &ops.SiteOperation{
 		ID:          uuid.New(),
 		AccountID:   s.key.AccountID,
@@ -85,6 +95,7 @@ func (s *site) createShrinkOperation(context context.Context, req ops.CreateSite
 		Force:       req.Force,
 		Vars:        req.Variables,
 		NodeRemoved: req.NodeRemoved,
+		Replace:     req.Replace,
 	}
 	op.Shrink.Vars.System.ClusterName = s.key.SiteDomain
 
@@ -263,6 +274,10 @@ func (s *site) shrinkOperationStart(ctx *operationContext) (err error) {
 		Message:    "unregistering the node",
 	})
 
+	if err = s.saveNodeLabels(ctx, *server, masterRunner); err != nil {
+		ctx.Warningf("failed to save node labels: %v", trace.DebugReport(err))
+	}
+
 	if err = s.unlabelNode(*server, masterRunner); err != nil {
 		if !force {
 			return trace.Wrap(err, "failed to unregister the node")
@@ -328,6 +343,14 @@ func (s *site) shrinkOperationStart(ctx *operationContext) (err error) {
 		ctx.Warningf("failed to remove the node from the database, force continue: %v", trace.DebugReport(err))
 	}
 
+	// the replacement node joins as a new etcd member, so the remaining
+	// members must still have quorum
+	if state.Replace && server.IsMaster() {
+		if err = s.checkEtcdQuorum(context.TODO(), *server); err != nil {
+			return trace.Wrap(err)
+		}
+	}
+
 	if online {
 		s.reportProgress(ctx, ops.ProgressEntry{
 			State:      ops.ProgressStateInProgress,
@@ -477,6 +500,71 @@ func (s *site) removeFromEtcd(ctx *operationContext, runner *serverRunner, serve
 	return nil
 }
 
+// checkEtcdQuorum verifies that the etcd cluster has quorum and keeps it
+// without the member of the specified server
+func (s *site) checkEtcdQuorum(ctx context.Context, server storage.Server) error {
+	membersAPI, err := clients.DefaultEtcdMembers()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	members, err := membersAPI.List(ctx)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	healthy := make(map[string]bool, len(members))
+	for _, member := range members {
+		healthy[member.ID] = isEtcdMemberHealthy(ctx, member)
+	}
+	provisionedServer := ProvisionedServer{Server: server}
+	return trace.Wrap(checkEtcdQuorum(members, healthy,
+		provisionedServer.EtcdMemberName(s.domainName)))
+}
+
+// checkEtcdQuorum verifies that the majority of the specified etcd members is healthy,
+// both with and without the member with the specified name
+func checkEtcdQuorum(members []etcd.Member, healthy map[string]bool, removedMember string) error {
+	var total, healthyTotal, remaining, healthyRemaining int
+	for _, member := range members {
+		total++
+		if healthy[member.ID] {
+			healthyTotal++
+		}
+		if member.Name == removedMember {
+			continue
+		}
+		remaining++
+		if healthy[member.ID] {
+			healthyRemaining++
+		}
+	}
+	if healthyTotal <= total/2 {
+		return trace.CompareFailed("etcd cluster does not have quorum: "+
+			"%v out of %v members are healthy", healthyTotal, total)
+	}
+	if healthyRemaining <= remaining/2 {
+		return trace.CompareFailed("etcd cluster would lose quorum without member %v: "+
+			"%v out of the remaining %v members are healthy", removedMember, healthyRemaining, remaining)
+	}
+	return nil
+}
+
+// isEtcdMemberHealthy returns true if the specified etcd member is started and serves requests
+func isEtcdMemberHealthy(ctx context.Context, member etcd.Member) bool {
+	if len(member.ClientURLs) == 0 {
+		return false
+	}
+	client, err := clients.Etcd(&clients.EtcdConfig{Endpoints: member.ClientURLs})
+	if err != nil {
+		log.Warnf("Failed to create etcd client for %v: %v.", member.Name, trace.DebugReport(err))
+		return false
+	}
+	if _, err := client.GetVersion(ctx); err != nil {
+		log.Warnf("Etcd member %v is unhealthy: %v.", member.Name, trace.DebugReport(err))
+		return false
+	}
+	return true
+}
+
 func (s *site) uninstallSystem(ctx *operationContext, runner *serverRunner) error {
 	commands := [][]string{
 		s.gravityCommand("system", "uninstall", "--confirm"),
@@ -598,6 +686,71 @@ func (s *site) serverPackages(server *ProvisionedServer) ([]loc.Locator, error)
 	return packages, nil
 }
 
+// saveNodeLabels records the user-defined labels of the k8s node being removed
+// in the operation state so they can be restored on a replacement node
+func (s *site) saveNodeLabels(ctx *operationContext, server storage.Server, runner *serverRunner) error {
+	if ctx.operation.Shrink.NodeLabels != nil {
+		// Labels have already been saved by a previous attempt
+		return nil
+	}
+
+	command := s.planetEnterCommand(defaults.KubectlBin, "get", "nodes",
+		fmt.Sprintf("-l=%v=%v", v1.LabelHostname, server.KubeNodeID()), "-o", "json")
+
+	var out []byte
+	err := utils.Retry(defaults.RetryInterval, defaults.RetryAttempts, func() (err error) {
+		out, err = runner.Run(command...)
+		return trace.Wrap(err)
+	})
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	var nodes v1.NodeList
+	if err := json.Unmarshal(out, &nodes); err != nil {
+		return trace.Wrap(err, "failed to parse node list: %s", out)
+	}
+	if len(nodes.Items) == 0 {
+		return trace.NotFound("no Kubernetes node found for %v", server)
+	}
+
+	operation, err := s.getSiteOperation(ctx.operation.ID)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	operation.Shrink.NodeLabels = userNodeLabels(nodes.Items[0].Labels)
+	if _, err = s.updateSiteOperation(operation); err != nil {
+		return trace.Wrap(err)
+	}
+	ctx.operation.Shrink.NodeLabels = operation.Shrink.NodeLabels
+	return nil
+}
+
+// userNodeLabels returns the subset of the specified node labels
+// that are not managed by Kubernetes or the cluster itself
+func userNodeLabels(labels map[string]string) map[string]string {
+	result := make(map[string]string)
+	for name, value := range labels {
+		if isSystemNodeLabel(name) {
+			continue
+		}
+		result[name] = value
+	}
+	return result
+}
+
+func isSystemNodeLabel(name string) bool {
+	if name == defaults.KubernetesAdvertiseIPLabel {
+		return true
+	}
+	parts := strings.SplitN(name, "/", 2)
+	if len(parts) != 2 {
+		return false
+	}
+	domain := parts[0]
+	return domain == "kubernetes.io" || strings.HasSuffix(domain, ".kubernetes.io")
+}
+
 // unlabelNode deletes server profile labels from k8s node
 func (s *site) unlabelNode(server storage.Server, runner *serverRunner) error {
 	profile, err := s.app.Manifest.NodeProfiles.ByName(server.Role)
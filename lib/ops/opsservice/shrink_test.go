@@ -0,0 +1,79 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package opsservice
+
+import (
+	etcd "github.com/coreos/etcd/client"
+	"github.com/gravitational/trace"
+	. "gopkg.in/check.v1"
+)
+
+type ShrinkSuite struct{}
+
+var _ = Suite(&ShrinkSuite{})
+
+func (s *ShrinkSuite) TestChecksEtcdQuorum(c *C) {
+	members := []etcd.Member{
+		{ID: "1", Name: "node-1"},
+		{ID: "2", Name: "node-2"},
+		{ID: "3", Name: "node-3"},
+	}
+	testCases := []struct {
+		healthy map[string]bool
+		removed string
+		quorum  bool
+		comment string
+	}{
+		{
+			healthy: map[string]bool{"1": true, "2": true, "3": true},
+			removed: "node-3",
+			quorum:  true,
+			comment: "all members are healthy",
+		},
+		{
+			healthy: map[string]bool{"1": true, "2": true},
+			removed: "node-3",
+			quorum:  true,
+			comment: "removed member is unhealthy",
+		},
+		{
+			healthy: map[string]bool{"1": true, "3": true},
+			removed: "node-3",
+			quorum:  false,
+			comment: "remaining members lose quorum",
+		},
+		{
+			healthy: map[string]bool{"1": true},
+			removed: "node-3",
+			quorum:  false,
+			comment: "cluster has no quorum",
+		},
+	}
+	for _, tc := range testCases {
+		comment := Commentf(tc.comment)
+		err := checkEtcdQuorum(members, tc.healthy, tc.removed)
+		if tc.quorum {
+			c.Assert(err, IsNil, comment)
+		} else {
+			c.Assert(trace.IsCompareFailed(err), Equals, true, comment)
+		}
+	}
+
+	// the member has already been removed
+	err := checkEtcdQuorum(members[:2], map[string]bool{"1": true, "2": true}, "node-3")
+	c.Assert(err, IsNil)
+}
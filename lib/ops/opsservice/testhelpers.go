@@ -104,7 +104,7 @@ func SetupTestServices(c *check.C) TestServices {
 
 	proxy := &suite.TestProxy{}
 	log := log.WithField("from", "test")
-	peerStore := NewAgentPeerStore(backend, usersService, proxy, log)
+	peerStore := NewAgentPeerStore(backend, usersService, proxy, rpcserver.ReconnectPolicy{}, log)
 	agentServer, err := rpcserver.New(rpcserver.Config{
 		FieldLogger: log,
 		Listener:    listener,
@@ -22,7 +22,9 @@ import (
 	"github.com/gravitational/gravity/lib/ops"
 	"github.com/gravitational/gravity/lib/ops/events"
 	"github.com/gravitational/gravity/lib/storage"
+	"github.com/gravitational/gravity/lib/users"
 
+	teleservices "github.com/gravitational/teleport/lib/services"
 	"github.com/gravitational/trace"
 )
 
@@ -42,6 +44,22 @@ func (o *Operator) UpdateUser(ctx context.Context, req ops.UpdateUserRequest) er
 	return nil
 }
 
+// UnlockUser unlocks the user locked after too many failed login attempts.
+func (o *Operator) UnlockUser(ctx context.Context, req ops.UnlockUserRequest) error {
+	err := req.Check()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	err = o.users().UnlockUser(req.Name)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	events.Emit(ctx, o, events.UserUnlocked, events.Fields{
+		events.FieldName: req.Name,
+	})
+	return nil
+}
+
 // CreateUserInvite creates a new invite token for a user.
 func (o *Operator) CreateUserInvite(ctx context.Context, req ops.CreateUserInviteRequest) (*storage.UserToken, error) {
 	err := req.Check()
@@ -98,3 +116,35 @@ func (o *Operator) DeleteUserInvite(ctx context.Context, req ops.DeleteUserInvit
 	}
 	return nil
 }
+
+// CheckUserAccess explains whether the user can perform the specified action
+// on the resource kind.
+func (o *Operator) CheckUserAccess(ctx context.Context, req ops.CheckUserAccessRequest) (*users.AccessDecision, error) {
+	err := req.Check()
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	user, err := o.users().GetTelekubeUser(req.Name)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	checker, err := o.users().GetAccessChecker(user)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	site, err := o.backend().GetSite(req.SiteDomain)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	cluster := storage.NewClusterFromSite(site)
+	decision, err := users.ExplainAccess(checker, &users.Context{
+		Context: teleservices.Context{
+			User:     user,
+			Resource: cluster,
+		},
+	}, cluster.GetMetadata().Namespace, storage.CanonicalKind(req.Kind), req.Verb)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return decision, nil
+}
@@ -93,6 +93,67 @@ func (c *githubCollection) WriteYAML(w io.Writer) error {
 	return utils.WriteYAML(c, w)
 }
 
+type oidcCollection struct {
+	connectors []teleservices.OIDCConnector
+}
+
+// Resources returns the resources collection in the generic format
+func (c *oidcCollection) Resources() (resources []teleservices.UnknownResource, err error) {
+	for _, item := range c.connectors {
+		resource, err := utils.ToUnknownResource(item)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		resources = append(resources, *resource)
+	}
+	return resources, nil
+}
+
+// WriteText serializes collection in human-friendly text format
+func (c *oidcCollection) WriteText(w io.Writer) error {
+	t := goterm.NewTable(0, 10, 5, ' ', 0)
+	common.PrintTableHeader(t, []string{"Name", "Issuer URL", "Client ID", "Mapping"})
+	for _, conn := range c.connectors {
+		fmt.Fprintf(t, "%v\t%v\t%v\t%v\n",
+			conn.GetName(),
+			conn.GetIssuerURL(),
+			conn.GetClientID(),
+			formatOIDCMapping(conn.GetClaimsToRoles()))
+	}
+	_, err := io.WriteString(w, t.String())
+	return trace.Wrap(err)
+}
+
+func formatOIDCMapping(mappings []teleservices.ClaimMapping) string {
+	var formatted []string
+	for _, m := range mappings {
+		roles := append([]string{}, m.Roles...)
+		if m.RoleTemplate != nil {
+			roles = append(roles, fmt.Sprintf("template(%v)", m.RoleTemplate.GetName()))
+		}
+		formatted = append(formatted, fmt.Sprintf("%v=%v -> %v",
+			m.Claim, m.Value, strings.Join(roles, ",")))
+	}
+	return strings.Join(formatted, "\n")
+}
+
+// WriteJSON serializes collection into JSON format
+func (c *oidcCollection) WriteJSON(w io.Writer) error {
+	return utils.WriteJSON(c, w)
+}
+
+func (c *oidcCollection) ToMarshal() interface{} {
+	if len(c.connectors) == 1 {
+		return c.connectors[0]
+	}
+	return c.connectors
+}
+
+// WriteYAML serializes collection into YAML format
+func (c *oidcCollection) WriteYAML(w io.Writer) error {
+	return utils.WriteYAML(c, w)
+}
+
 type userCollection struct {
 	users []teleservices.User
 }
@@ -430,6 +491,203 @@ func (r smtpConfigCollection) ToMarshal() interface{} {
 
 type smtpConfigCollection []storage.SMTPConfig
 
+type maintenanceWindowCollection []storage.MaintenanceWindow
+
+// Resources returns the resources collection in the generic format
+func (c maintenanceWindowCollection) Resources() (resources []teleservices.UnknownResource, err error) {
+	for _, item := range c {
+		resource, err := utils.ToUnknownResource(item)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		resources = append(resources, *resource)
+	}
+	return resources, nil
+}
+
+// WriteText serializes collection in human-friendly text format
+func (c maintenanceWindowCollection) WriteText(w io.Writer) error {
+	t := goterm.NewTable(0, 10, 5, ' ', 0)
+	common.PrintTableHeader(t, []string{"Days", "Start (UTC)", "Duration"})
+	for _, window := range c {
+		for _, w := range window.GetWindows() {
+			days := "daily"
+			if len(w.Days) != 0 {
+				days = strings.Join(w.Days, ",")
+			}
+			fmt.Fprintf(t, "%v\t%v\t%v\n", days, w.Start, w.Duration.Value())
+		}
+	}
+	_, err := io.WriteString(w, t.String())
+	return trace.Wrap(err)
+}
+
+// WriteJSON serializes collection into JSON format
+func (c maintenanceWindowCollection) WriteJSON(w io.Writer) error {
+	return utils.WriteJSON(c, w)
+}
+
+// WriteYAML serializes collection into YAML format
+func (c maintenanceWindowCollection) WriteYAML(w io.Writer) error {
+	return utils.WriteYAML(c, w)
+}
+
+func (c maintenanceWindowCollection) ToMarshal() interface{} {
+	if len(c) == 1 {
+		return c[0]
+	}
+	return c
+}
+
+type mfaPolicyCollection []storage.MFAPolicy
+
+// Resources returns the resources collection in the generic format
+func (c mfaPolicyCollection) Resources() (resources []teleservices.UnknownResource, err error) {
+	for _, item := range c {
+		resource, err := utils.ToUnknownResource(item)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		resources = append(resources, *resource)
+	}
+	return resources, nil
+}
+
+// WriteText serializes collection in human-friendly text format
+func (c mfaPolicyCollection) WriteText(w io.Writer) error {
+	t := goterm.NewTable(0, 10, 5, ' ', 0)
+	common.PrintTableHeader(t, []string{"Actions", "Roles"})
+	for _, policy := range c {
+		for _, rule := range policy.GetRules() {
+			roles := "all users"
+			if len(rule.Roles) != 0 {
+				roles = strings.Join(rule.Roles, ",")
+			}
+			fmt.Fprintf(t, "%v\t%v\n", strings.Join(rule.Actions, ","), roles)
+		}
+	}
+	_, err := io.WriteString(w, t.String())
+	return trace.Wrap(err)
+}
+
+// WriteJSON serializes collection into JSON format
+func (c mfaPolicyCollection) WriteJSON(w io.Writer) error {
+	return utils.WriteJSON(c, w)
+}
+
+// WriteYAML serializes collection into YAML format
+func (c mfaPolicyCollection) WriteYAML(w io.Writer) error {
+	return utils.WriteYAML(c, w)
+}
+
+func (c mfaPolicyCollection) ToMarshal() interface{} {
+	if len(c) == 1 {
+		return c[0]
+	}
+	return c
+}
+
+type passwordPolicyCollection []storage.PasswordPolicy
+
+// Resources returns the resources collection in the generic format
+func (c passwordPolicyCollection) Resources() (resources []teleservices.UnknownResource, err error) {
+	for _, item := range c {
+		resource, err := utils.ToUnknownResource(item)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		resources = append(resources, *resource)
+	}
+	return resources, nil
+}
+
+// WriteText serializes collection in human-friendly text format
+func (c passwordPolicyCollection) WriteText(w io.Writer) error {
+	t := goterm.NewTable(0, 10, 5, ' ', 0)
+	common.PrintTableHeader(t, []string{"Min Length", "Character Classes", "History", "Max Age", "Max Failed Attempts", "Lockout"})
+	for _, policy := range c {
+		classes := "-"
+		if len(policy.GetCharacterClasses()) != 0 {
+			classes = strings.Join(policy.GetCharacterClasses(), ",")
+		}
+		maxAge := "-"
+		if policy.GetMaxAge() != 0 {
+			maxAge = policy.GetMaxAge().String()
+		}
+		lockout := "-"
+		if policy.GetMaxFailedAttempts() != 0 {
+			lockout = policy.GetLockoutDuration().String()
+		}
+		fmt.Fprintf(t, "%v\t%v\t%v\t%v\t%v\t%v\n", policy.GetMinLength(), classes,
+			policy.GetHistoryDepth(), maxAge, policy.GetMaxFailedAttempts(), lockout)
+	}
+	_, err := io.WriteString(w, t.String())
+	return trace.Wrap(err)
+}
+
+// WriteJSON serializes collection into JSON format
+func (c passwordPolicyCollection) WriteJSON(w io.Writer) error {
+	return utils.WriteJSON(c, w)
+}
+
+// WriteYAML serializes collection into YAML format
+func (c passwordPolicyCollection) WriteYAML(w io.Writer) error {
+	return utils.WriteYAML(c, w)
+}
+
+func (c passwordPolicyCollection) ToMarshal() interface{} {
+	if len(c) == 1 {
+		return c[0]
+	}
+	return c
+}
+
+type nodePoolCollection []storage.NodePool
+
+// Resources returns the resources collection in the generic format
+func (c nodePoolCollection) Resources() (resources []teleservices.UnknownResource, err error) {
+	for _, item := range c {
+		resource, err := utils.ToUnknownResource(item)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		resources = append(resources, *resource)
+	}
+	return resources, nil
+}
+
+// WriteText serializes collection in human-friendly text format
+func (c nodePoolCollection) WriteText(w io.Writer) error {
+	t := goterm.NewTable(0, 10, 5, ' ', 0)
+	common.PrintTableHeader(t, []string{"Name", "Count", "Hosts"})
+	for _, pool := range c {
+		var hosts []string
+		for _, host := range pool.GetHosts() {
+			hosts = append(hosts, fmt.Sprintf("%v (%v)", host.Addr, host.Role))
+		}
+		fmt.Fprintf(t, "%v\t%v\t%v\n", pool.GetName(), pool.GetCount(), strings.Join(hosts, ", "))
+	}
+	_, err := io.WriteString(w, t.String())
+	return trace.Wrap(err)
+}
+
+// WriteJSON serializes collection into JSON format
+func (c nodePoolCollection) WriteJSON(w io.Writer) error {
+	return utils.WriteJSON(c, w)
+}
+
+// WriteYAML serializes collection into YAML format
+func (c nodePoolCollection) WriteYAML(w io.Writer) error {
+	return utils.WriteYAML(c, w)
+}
+
+func (c nodePoolCollection) ToMarshal() interface{} {
+	if len(c) == 1 {
+		return c[0]
+	}
+	return c
+}
+
 // WriteText serializes collection in human-friendly text format
 func (r alertCollection) WriteText(w io.Writer) error {
 	t := goterm.NewTable(0, 10, 5, ' ', 0)
@@ -89,6 +89,15 @@ func (r *Resources) Create(ctx context.Context, req resources.CreateRequest) err
 			return trace.Wrap(err)
 		}
 		r.Printf("Created Github connector %q\n", conn.GetName())
+	case teleservices.KindOIDCConnector:
+		conn, err := teleservices.GetOIDCConnectorMarshaler().UnmarshalOIDCConnector(req.Resource.Raw)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		if err := r.Operator.UpsertOIDCConnector(ctx, req.SiteKey, conn); err != nil {
+			return trace.Wrap(err)
+		}
+		r.Printf("Created OIDC connector %q\n", conn.GetName())
 	case teleservices.KindUser:
 		user, err := teleservices.GetUserMarshaler().UnmarshalUser(req.Resource.Raw)
 		if err != nil {
@@ -191,6 +200,46 @@ func (r *Resources) Create(ctx context.Context, req resources.CreateRequest) err
 			return trace.Wrap(err)
 		}
 		r.Println("Updated cluster SMTP configuration")
+	case storage.KindMaintenanceWindow:
+		window, err := storage.UnmarshalMaintenanceWindow(req.Resource.Raw)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		err = r.Operator.UpdateMaintenanceWindow(ctx, req.SiteKey, window)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		r.Println("Updated cluster maintenance window")
+	case storage.KindMFAPolicy:
+		policy, err := storage.UnmarshalMFAPolicy(req.Resource.Raw)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		err = r.Operator.UpdateMFAPolicy(ctx, req.SiteKey, policy)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		r.Println("Updated cluster second factor policy")
+	case storage.KindPasswordPolicy:
+		policy, err := storage.UnmarshalPasswordPolicy(req.Resource.Raw)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		err = r.Operator.UpdatePasswordPolicy(ctx, req.SiteKey, policy)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		r.Println("Updated cluster password policy")
+	case storage.KindNodePool:
+		pool, err := storage.UnmarshalNodePool(req.Resource.Raw)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		err = r.Operator.UpdateNodePool(ctx, req.SiteKey, pool)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		r.Printf("Updated node pool %q\n", pool.GetName())
 	case storage.KindAlert:
 		alert, err := storage.UnmarshalAlert(req.Resource.Raw)
 		if err != nil {
@@ -284,6 +333,19 @@ func (r *Resources) GetCollection(req resources.ListRequest) (resources.Collecti
 			return nil, trace.Wrap(err)
 		}
 		return &githubCollection{connectors: connectors}, nil
+	case teleservices.KindOIDCConnector:
+		if req.Name != "" {
+			connector, err := r.Operator.GetOIDCConnector(req.SiteKey, req.Name, req.WithSecrets)
+			if err != nil {
+				return nil, trace.Wrap(err)
+			}
+			return &oidcCollection{connectors: []teleservices.OIDCConnector{connector}}, nil
+		}
+		connectors, err := r.Operator.GetOIDCConnectors(req.SiteKey, req.WithSecrets)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		return &oidcCollection{connectors: connectors}, nil
 	case teleservices.KindUser:
 		if req.Name != "" {
 			user, err := r.Operator.GetUser(req.SiteKey, req.Name)
@@ -370,6 +432,38 @@ func (r *Resources) GetCollection(req resources.ListRequest) (resources.Collecti
 			return nil, trace.Wrap(err)
 		}
 		return smtpConfigCollection{config}, nil
+	case storage.KindMaintenanceWindow:
+		window, err := r.Operator.GetMaintenanceWindow(req.SiteKey)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		return maintenanceWindowCollection{window}, nil
+	case storage.KindMFAPolicy:
+		policy, err := r.Operator.GetMFAPolicy(req.SiteKey)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		return mfaPolicyCollection{policy}, nil
+	case storage.KindPasswordPolicy:
+		policy, err := r.Operator.GetPasswordPolicy(req.SiteKey)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		return passwordPolicyCollection{policy}, nil
+	case storage.KindNodePool:
+		pools, err := r.Operator.GetNodePools(req.SiteKey)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		if req.Name == "" {
+			return nodePoolCollection(pools), nil
+		}
+		for _, pool := range pools {
+			if pool.GetName() == req.Name {
+				return nodePoolCollection{pool}, nil
+			}
+		}
+		return nil, trace.NotFound("node pool %q is not found", req.Name)
 	case storage.KindAlert:
 		alerts, err := r.Operator.GetAlerts(req.SiteKey)
 		if err != nil {
@@ -436,6 +530,14 @@ func (r *Resources) Remove(ctx context.Context, req resources.RemoveRequest) err
 			return trace.Wrap(err)
 		}
 		r.Printf("Github connector %q has been deleted\n", req.Name)
+	case teleservices.KindOIDCConnector:
+		if err := r.Operator.DeleteOIDCConnector(ctx, req.SiteKey, req.Name); err != nil {
+			if trace.IsNotFound(err) && req.Force {
+				return nil
+			}
+			return trace.Wrap(err)
+		}
+		r.Printf("OIDC connector %q has been deleted\n", req.Name)
 	case teleservices.KindUser:
 		if err := r.Operator.DeleteUser(ctx, req.SiteKey, req.Name); err != nil {
 			if trace.IsNotFound(err) && req.Force {
@@ -495,6 +597,38 @@ func (r *Resources) Remove(ctx context.Context, req resources.RemoveRequest) err
 			return trace.Wrap(err)
 		}
 		r.Println("SMTP configuration has been deleted")
+	case storage.KindMaintenanceWindow:
+		if err := r.Operator.DeleteMaintenanceWindow(ctx, req.SiteKey); err != nil {
+			if trace.IsNotFound(err) && req.Force {
+				return nil
+			}
+			return trace.Wrap(err)
+		}
+		r.Println("Maintenance window has been deleted")
+	case storage.KindMFAPolicy:
+		if err := r.Operator.DeleteMFAPolicy(ctx, req.SiteKey); err != nil {
+			if trace.IsNotFound(err) && req.Force {
+				return nil
+			}
+			return trace.Wrap(err)
+		}
+		r.Println("Second factor policy has been deleted")
+	case storage.KindPasswordPolicy:
+		if err := r.Operator.DeletePasswordPolicy(ctx, req.SiteKey); err != nil {
+			if trace.IsNotFound(err) && req.Force {
+				return nil
+			}
+			return trace.Wrap(err)
+		}
+		r.Println("Password policy has been deleted")
+	case storage.KindNodePool:
+		if err := r.Operator.DeleteNodePool(ctx, req.SiteKey, req.Name); err != nil {
+			if trace.IsNotFound(err) && req.Force {
+				return nil
+			}
+			return trace.Wrap(err)
+		}
+		r.Printf("Node pool %q has been deleted\n", req.Name)
 	case storage.KindAlert:
 		if err := r.Operator.DeleteAlert(ctx, req.SiteKey, req.Name); err != nil {
 			if trace.IsNotFound(err) && req.Force {
@@ -537,6 +671,8 @@ func Validate(resource storage.UnknownResource) (err error) {
 	switch resource.Kind {
 	case teleservices.KindGithubConnector:
 		_, err = teleservices.GetGithubConnectorMarshaler().Unmarshal(resource.Raw)
+	case teleservices.KindOIDCConnector:
+		_, err = teleservices.GetOIDCConnectorMarshaler().UnmarshalOIDCConnector(resource.Raw)
 	case teleservices.KindUser:
 		_, err = teleservices.GetUserMarshaler().UnmarshalUser(resource.Raw)
 	case storage.KindToken:
@@ -549,6 +685,14 @@ func Validate(resource storage.UnknownResource) (err error) {
 		_, err = teleservices.GetAuthPreferenceMarshaler().Unmarshal(resource.Raw)
 	case storage.KindSMTPConfig:
 		_, err = storage.UnmarshalSMTPConfig(resource.Raw)
+	case storage.KindMaintenanceWindow:
+		_, err = storage.UnmarshalMaintenanceWindow(resource.Raw)
+	case storage.KindMFAPolicy:
+		_, err = storage.UnmarshalMFAPolicy(resource.Raw)
+	case storage.KindPasswordPolicy:
+		_, err = storage.UnmarshalPasswordPolicy(resource.Raw)
+	case storage.KindNodePool:
+		_, err = storage.UnmarshalNodePool(resource.Raw)
 	case storage.KindAlert:
 		_, err = storage.UnmarshalAlert(resource.Raw)
 	case storage.KindAlertTarget:
@@ -28,6 +28,7 @@ import (
 	"github.com/gravitational/gravity/lib/ops/opsclient"
 	"github.com/gravitational/gravity/lib/ops/resources"
 	"github.com/gravitational/gravity/lib/storage"
+	"github.com/gravitational/gravity/lib/testutils"
 	"github.com/gravitational/gravity/lib/utils"
 
 	teleservices "github.com/gravitational/teleport/lib/services"
@@ -89,6 +90,38 @@ func (s *GravityResourcesSuite) TestGithubConnectorResource(c *check.C) {
 	compare.DeepCompare(c, collection, &githubCollection{[]teleservices.GithubConnector{}})
 }
 
+func (s *GravityResourcesSuite) TestOIDCConnectorResource(c *check.C) {
+	issuer := testutils.NewOIDCIssuer()
+	defer issuer.Close()
+	oidcConnector := teleservices.NewOIDCConnector("keycloak", teleservices.OIDCConnectorSpecV2{
+		IssuerURL:    issuer.URL,
+		RedirectURL:  "https://ops.example.com/portalapi/v1/oidc/callback",
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+		ClaimsToRoles: []teleservices.ClaimMapping{
+			{
+				Claim: "groups",
+				Value: "admins",
+				Roles: []string{"@teleadmin"},
+			},
+		},
+	})
+
+	err := s.r.Create(context.TODO(), resources.CreateRequest{SiteKey: s.cluster.Key(), Resource: toUnknown(c, oidcConnector)})
+	c.Assert(err, check.IsNil)
+
+	collection, err := s.r.GetCollection(resources.ListRequest{SiteKey: s.cluster.Key(), Kind: teleservices.KindOIDCConnector, WithSecrets: true})
+	c.Assert(err, check.IsNil)
+	compare.DeepCompare(c, collection, &oidcCollection{[]teleservices.OIDCConnector{oidcConnector}})
+
+	err = s.r.Remove(context.TODO(), resources.RemoveRequest{SiteKey: s.cluster.Key(), Kind: teleservices.KindOIDCConnector, Name: "keycloak"})
+	c.Assert(err, check.IsNil)
+
+	collection, err = s.r.GetCollection(resources.ListRequest{SiteKey: s.cluster.Key(), Kind: teleservices.KindOIDCConnector})
+	c.Assert(err, check.IsNil)
+	compare.DeepCompare(c, collection, &oidcCollection{[]teleservices.OIDCConnector{}})
+}
+
 func (s *GravityResourcesSuite) TestUser(c *check.C) {
 	err := s.r.Create(context.TODO(), resources.CreateRequest{SiteKey: s.cluster.Key(), Resource: toUnknown(c, user)})
 	c.Assert(err, check.IsNil)
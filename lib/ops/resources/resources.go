@@ -194,6 +194,9 @@ func (r *RemoveRequest) Check() error {
 	switch kind {
 	case storage.KindAlertTarget:
 	case storage.KindSMTPConfig:
+	case storage.KindMaintenanceWindow:
+	case storage.KindMFAPolicy:
+	case storage.KindPasswordPolicy:
 	case storage.KindRuntimeEnvironment:
 	case storage.KindClusterConfiguration:
 	case storage.KindPersistentStorage:
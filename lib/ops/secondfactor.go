@@ -0,0 +1,106 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ops
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gravitational/gravity/lib/constants"
+	"github.com/gravitational/gravity/lib/storage"
+
+	"github.com/gravitational/trace"
+)
+
+// WithSecondFactor returns a copy of the provided context that carries
+// the specified second factor token
+func WithSecondFactor(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, constants.SecondFactorContext, token)
+}
+
+// SecondFactorFromContext returns the second factor token carried
+// by the provided context
+func SecondFactorFromContext(ctx context.Context) string {
+	token, _ := ctx.Value(constants.SecondFactorContext).(string)
+	return token
+}
+
+// IsSecondFactorRequiredError returns true if the provided error indicates
+// that the action requires a second factor token
+func IsSecondFactorRequiredError(err error) bool {
+	return trace.IsAccessDenied(err) && strings.Contains(err.Error(), secondFactorRequired)
+}
+
+// checkSecondFactor verifies the second factor token passed with the context
+// if the cluster second factor policy requires one for the specified action
+func (o *OperatorACL) checkSecondFactor(ctx context.Context, key SiteKey, action string) error {
+	policy, err := o.getMFAPolicy(key)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if policy == nil || !policy.RequiresSecondFactor(action, o.user.GetRoles()) {
+		return nil
+	}
+	return trace.Wrap(o.verifySecondFactor(ctx, action))
+}
+
+// checkSecondFactorForPolicy verifies the second factor token passed with
+// the context if the current second factor policy applies to the user so
+// the policy cannot be lifted without providing one
+func (o *OperatorACL) checkSecondFactorForPolicy(ctx context.Context, key SiteKey) error {
+	policy, err := o.getMFAPolicy(key)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if policy == nil || !policy.AppliesTo(o.user.GetRoles()) {
+		return nil
+	}
+	return trace.Wrap(o.verifySecondFactor(ctx, storage.KindMFAPolicy))
+}
+
+// getMFAPolicy returns the second factor policy for the specified cluster
+// or nil if the policy does not apply to the current user
+func (o *OperatorACL) getMFAPolicy(key SiteKey) (storage.MFAPolicy, error) {
+	// Agents authenticate with API keys and cannot present a second factor
+	if o.user.GetType() == storage.AgentUser {
+		return nil, nil
+	}
+	policy, err := o.operator.GetMFAPolicy(key)
+	if err != nil {
+		if trace.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, trace.Wrap(err)
+	}
+	return policy, nil
+}
+
+func (o *OperatorACL) verifySecondFactor(ctx context.Context, action string) error {
+	token := SecondFactorFromContext(ctx)
+	if token == "" {
+		return trace.AccessDenied("%v to perform %v", secondFactorRequired, action)
+	}
+	if err := o.users.CheckSecondFactor(o.username, token); err != nil {
+		o.WithError(err).Warnf("Second factor check failed for %v.", action)
+		return trace.AccessDenied("invalid second factor token")
+	}
+	return nil
+}
+
+// secondFactorRequired is the error message returned when an action
+// requires a second factor token which has not been provided
+const secondFactorRequired = "second factor token is required"
@@ -115,6 +115,12 @@ func (t *TestProxy) ExecuteCommand(ctx context.Context, domainName, nodeAddr, co
 	panic("not implemented")
 }
 
+// ExecuteScript executes a shell script on a remote node addrress
+// for a given site domain
+func (t *TestProxy) ExecuteScript(ctx context.Context, domainName, nodeAddr string, script io.Reader, out io.Writer) error {
+	panic("not implemented")
+}
+
 func (t *TestProxy) GetPlanetLeaderIP() string {
 	panic("")
 }
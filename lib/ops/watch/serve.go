@@ -0,0 +1,139 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package watch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gravitational/gravity/lib/defaults"
+
+	"github.com/gravitational/trace"
+	"golang.org/x/net/websocket"
+)
+
+// ServeCluster watches the cluster with the specified configuration and
+// streams the changes to the client until it disconnects
+func ServeCluster(w http.ResponseWriter, r *http.Request, config Config) error {
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	events, err := Watch(ctx, config)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	return trace.Wrap(Serve(w, r, events))
+}
+
+// Serve streams the events to the client as server-sent events or
+// over a web socket if the client requested the protocol upgrade.
+// It returns when the events channel is closed or the client disconnects
+func Serve(w http.ResponseWriter, r *http.Request, events <-chan Event) error {
+	if strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+		serveWebSocket(w, r, events)
+		return nil
+	}
+	return trace.Wrap(serveSSE(w, r, events))
+}
+
+// serveSSE streams the events in the text/event-stream format
+func serveSSE(w http.ResponseWriter, r *http.Request, events <-chan Event) error {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return trace.BadParameter("streaming is not supported")
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	// disable response buffering in nginx-like proxies
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	keepAlive := time.NewTicker(defaults.ClusterEventsKeepAlive)
+	defer keepAlive.Stop()
+	var id int
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+			id++
+			if err := writeSSE(w, id, event); err != nil {
+				return trace.Wrap(err)
+			}
+		case <-keepAlive.C:
+			if _, err := io.WriteString(w, ": keep-alive\n\n"); err != nil {
+				return trace.Wrap(err)
+			}
+		case <-r.Context().Done():
+			return nil
+		}
+		flusher.Flush()
+	}
+}
+
+// writeSSE writes a single event in the text/event-stream format
+func writeSSE(w io.Writer, id int, event Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	_, err = fmt.Fprintf(w, "id: %v\nevent: %v\ndata: %s\n\n", id, event.Type, data)
+	return trace.Wrap(err)
+}
+
+// serveWebSocket streams the events as JSON messages over a web socket
+func serveWebSocket(w http.ResponseWriter, r *http.Request, events <-chan Event) {
+	handler := func(ws *websocket.Conn) {
+		defer ws.Close()
+		closeC := make(chan struct{})
+		go func() {
+			// the stream is one-way, read only to detect when the client goes away
+			io.Copy(ioutil.Discard, ws)
+			close(closeC)
+		}()
+		keepAlive := time.NewTicker(defaults.ClusterEventsKeepAlive)
+		defer keepAlive.Stop()
+		for {
+			select {
+			case event, ok := <-events:
+				if !ok {
+					return
+				}
+				if err := websocket.JSON.Send(ws, event); err != nil {
+					return
+				}
+			case <-keepAlive.C:
+				if err := websocket.Message.Send(ws, ""); err != nil {
+					return
+				}
+			case <-closeC:
+				return
+			}
+		}
+	}
+	// instantiate the server explicitly instead of using websocket.Handler
+	// to skip the origin check, similar to httplib.WebSocketReader
+	server := &websocket.Server{Handler: handler}
+	server.ServeHTTP(w, r)
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package watch
+
+import (
+	"context"
+
+	"github.com/gravitational/gravity/lib/ops"
+	"github.com/gravitational/gravity/lib/status"
+	"github.com/gravitational/gravity/lib/storage"
+
+	"github.com/gravitational/trace"
+)
+
+// StatusOperator is the subset of the cluster operator used to collect
+// the node status and alerts on behalf of the subscriber
+type StatusOperator interface {
+	// GetSite returns the cluster by its key
+	GetSite(ops.SiteKey) (*ops.Site, error)
+	// GetAlerts returns the list of configured monitoring alerts
+	GetAlerts(ops.SiteKey) ([]storage.Alert, error)
+}
+
+// ClusterNodes returns the function that collects the status
+// of the cluster nodes from the planet agents
+func ClusterNodes(operator StatusOperator, key ops.SiteKey) NodesFunc {
+	return func(ctx context.Context) ([]Node, error) {
+		// servers can change as the cluster is expanded or shrunk
+		cluster, err := operator.GetSite(key)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		agent, err := status.FromPlanetAgent(ctx, cluster.ClusterState.Servers)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		nodes := make([]Node, 0, len(agent.Nodes))
+		for _, node := range agent.Nodes {
+			nodes = append(nodes, Node{
+				Hostname:     node.Hostname,
+				AdvertiseIP:  node.AdvertiseIP,
+				Status:       node.Status,
+				FailedProbes: node.FailedProbes,
+			})
+		}
+		return nodes, nil
+	}
+}
+
+// ClusterAlerts returns the function that collects the firing alerts
+// from the cluster alertmanager if the subscriber is allowed to see alerts
+func ClusterAlerts(operator StatusOperator, key ops.SiteKey) AlertsFunc {
+	return func(ctx context.Context) ([]Alert, error) {
+		if _, err := operator.GetAlerts(key); err != nil {
+			return nil, trace.Wrap(err)
+		}
+		cluster, err := operator.GetSite(key)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		firing, err := status.FromAlertManager(ctx, *cluster)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		alerts := make([]Alert, 0, len(firing))
+		for _, alert := range firing {
+			var state string
+			if alert.Status != nil && alert.Status.State != nil {
+				state = *alert.Status.State
+			}
+			alerts = append(alerts, Alert{
+				Name:    alert.Labels["alertname"],
+				State:   state,
+				Message: alert.Annotations["message"],
+				Labels:  alert.Labels,
+			})
+		}
+		return alerts, nil
+	}
+}
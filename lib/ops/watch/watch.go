@@ -0,0 +1,475 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package watch implements streaming of the cluster state changes, such as
+// operation progress, plan phases, node status and alerts, to API subscribers
+package watch
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gravitational/gravity/lib/defaults"
+	"github.com/gravitational/gravity/lib/ops"
+	"github.com/gravitational/gravity/lib/storage"
+	"github.com/gravitational/gravity/lib/utils"
+
+	"github.com/gravitational/trace"
+	"github.com/jonboulle/clockwork"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// EventOperation is sent when an operation is started or changes state
+	EventOperation = "operation"
+	// EventProgress is sent when a new operation progress entry is created
+	EventProgress = "progress"
+	// EventPhase is sent when an operation plan phase changes state
+	EventPhase = "phase"
+	// EventNode is sent when a node status changes
+	EventNode = "node"
+	// EventAlert is sent when an alert fires or is resolved
+	EventAlert = "alert"
+
+	// NodeStatusRemoved is the status of a node that has left the cluster
+	NodeStatusRemoved = "removed"
+	// AlertStateResolved is the state of an alert that is no longer firing
+	AlertStateResolved = "resolved"
+)
+
+// Event describes a change of the cluster state
+type Event struct {
+	// Type is the event type
+	Type string `json:"type"`
+	// Time is the time the change has been detected
+	Time time.Time `json:"time"`
+	// Operation is set for operation events
+	Operation *Operation `json:"operation,omitempty"`
+	// Progress is set for progress events
+	Progress *ops.ProgressEntry `json:"progress,omitempty"`
+	// Phase is set for phase events
+	Phase *Phase `json:"phase,omitempty"`
+	// Node is set for node events
+	Node *Node `json:"node,omitempty"`
+	// Alert is set for alert events
+	Alert *Alert `json:"alert,omitempty"`
+}
+
+// Operation describes the state of a cluster operation
+type Operation struct {
+	// ID is the operation ID
+	ID string `json:"id"`
+	// Type is the operation type
+	Type string `json:"type"`
+	// State is the operation state
+	State string `json:"state"`
+	// Created is the operation creation time
+	Created time.Time `json:"created"`
+}
+
+// Phase describes the state of an operation plan phase
+type Phase struct {
+	// OperationID is the ID of the operation the phase belongs to
+	OperationID string `json:"operation_id"`
+	// ID is the phase ID
+	ID string `json:"id"`
+	// Description is the phase description
+	Description string `json:"description,omitempty"`
+	// State is the phase state
+	State string `json:"state"`
+	// Error is the phase error if the phase has failed
+	Error string `json:"error,omitempty"`
+}
+
+// Node describes the status of a cluster node
+type Node struct {
+	// Hostname is the node hostname
+	Hostname string `json:"hostname"`
+	// AdvertiseIP is the node advertise IP
+	AdvertiseIP string `json:"advertise_ip"`
+	// Status is the node status
+	Status string `json:"status"`
+	// FailedProbes lists the failed health probes
+	FailedProbes []string `json:"failed_probes,omitempty"`
+}
+
+// Alert describes a monitoring alert
+type Alert struct {
+	// Name is the alert name
+	Name string `json:"name"`
+	// State is the alert state
+	State string `json:"state"`
+	// Message is the alert message
+	Message string `json:"message,omitempty"`
+	// Labels are the alert labels
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// Operator is the subset of the cluster operator used to watch the cluster state
+type Operator interface {
+	// GetSiteOperations returns the list of cluster operations
+	GetSiteOperations(ops.SiteKey) (ops.SiteOperations, error)
+	// GetSiteOperationProgress returns the last progress entry of the operation
+	GetSiteOperationProgress(ops.SiteOperationKey) (*ops.ProgressEntry, error)
+	// GetOperationPlan returns the operation plan
+	GetOperationPlan(ops.SiteOperationKey) (*storage.OperationPlan, error)
+}
+
+// NodesFunc returns the status of the cluster nodes
+type NodesFunc func(context.Context) ([]Node, error)
+
+// AlertsFunc returns the firing cluster alerts
+type AlertsFunc func(context.Context) ([]Alert, error)
+
+// Config is the cluster watcher configuration
+type Config struct {
+	// Operator is the cluster operator acting on behalf of the subscriber,
+	// so that only the resources the subscriber has access to are streamed
+	Operator Operator
+	// Key is the key of the cluster to watch
+	Key ops.SiteKey
+	// Nodes returns the status of the cluster nodes, optional
+	Nodes NodesFunc
+	// Alerts returns the firing cluster alerts, optional
+	Alerts AlertsFunc
+	// PollInterval is how often operations are checked for changes
+	PollInterval time.Duration
+	// StatusInterval is how often nodes and alerts are checked for changes
+	StatusInterval time.Duration
+	// Clock is used to timestamp events
+	Clock clockwork.Clock
+	// FieldLogger is used for logging
+	logrus.FieldLogger
+}
+
+func (c *Config) checkAndSetDefaults() error {
+	if c.Operator == nil {
+		return trace.BadParameter("missing Operator")
+	}
+	if err := c.Key.Check(); err != nil {
+		return trace.Wrap(err)
+	}
+	if c.PollInterval == 0 {
+		c.PollInterval = defaults.ClusterEventsPollInterval
+	}
+	if c.StatusInterval == 0 {
+		c.StatusInterval = defaults.ClusterEventsStatusInterval
+	}
+	if c.Clock == nil {
+		c.Clock = clockwork.NewRealClock()
+	}
+	if c.FieldLogger == nil {
+		c.FieldLogger = logrus.WithField(trace.Component, "watch")
+	}
+	return nil
+}
+
+// Watch starts watching the cluster state and returns the channel with
+// the changes. The current state of active operations, nodes and alerts
+// is sent first. The channel is closed when the context is canceled.
+func Watch(ctx context.Context, config Config) (<-chan Event, error) {
+	if err := config.checkAndSetDefaults(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	w := newWatcher(config)
+	// poll once upfront to fail early if the subscriber cannot read the cluster
+	events, err := w.pollOperations()
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	events = append(events, w.pollStatus(ctx)...)
+	eventsC := make(chan Event, len(events)+defaults.ClusterEventsBuffer)
+	for _, event := range events {
+		eventsC <- event
+	}
+	go w.run(ctx, eventsC)
+	return eventsC, nil
+}
+
+func newWatcher(config Config) *watcher {
+	return &watcher{
+		Config:     config,
+		operations: make(map[string]string),
+		progress:   make(map[string]string),
+		phases:     make(map[string]map[string]string),
+		nodes:      make(map[string]Node),
+		alerts:     make(map[string]Alert),
+	}
+}
+
+// watcher tracks the last observed cluster state and generates
+// events for the changes
+type watcher struct {
+	Config
+	// initialized is set after the first poll
+	initialized bool
+	// operations maps operation ID to its state
+	operations map[string]string
+	// progress maps operation ID to its last progress entry ID
+	progress map[string]string
+	// phases maps operation ID to the states of its plan phases
+	phases map[string]map[string]string
+	// nodes maps node advertise IP to its last status
+	nodes map[string]Node
+	// alerts maps alert key to the firing alert
+	alerts map[string]Alert
+}
+
+func (w *watcher) run(ctx context.Context, eventsC chan<- Event) {
+	defer close(eventsC)
+	pollTicker := w.Clock.NewTicker(w.PollInterval)
+	defer pollTicker.Stop()
+	statusTicker := w.Clock.NewTicker(w.StatusInterval)
+	defer statusTicker.Stop()
+	for {
+		var events []Event
+		select {
+		case <-pollTicker.Chan():
+			var err error
+			events, err = w.pollOperations()
+			if err != nil {
+				w.WithError(err).Warn("Failed to poll cluster operations.")
+			}
+		case <-statusTicker.Chan():
+			events = w.pollStatus(ctx)
+		case <-ctx.Done():
+			return
+		}
+		for _, event := range events {
+			select {
+			case eventsC <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// pollOperations returns events for operations that have started or changed
+// state since the last poll and for the progress and plans of active operations
+func (w *watcher) pollOperations() (events []Event, err error) {
+	operations, err := w.Operator.GetSiteOperations(w.Key)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	// operations are sorted from the most recent one, report them in order of creation
+	for i := len(operations) - 1; i >= 0; i-- {
+		operation := ops.SiteOperation(operations[i])
+		prevState, seen := w.operations[operation.ID]
+		w.operations[operation.ID] = operation.State
+		if seen && prevState == operation.State && operation.IsFinished() {
+			continue
+		}
+		if !seen && !w.initialized && operation.IsFinished() {
+			continue
+		}
+		if !seen || prevState != operation.State {
+			events = append(events, w.newEvent(EventOperation, func(e *Event) {
+				e.Operation = &Operation{
+					ID:      operation.ID,
+					Type:    operation.Type,
+					State:   operation.State,
+					Created: operation.Created,
+				}
+			}))
+		}
+		operationEvents, err := w.pollOperation(operation.Key())
+		if err != nil {
+			w.WithError(err).Warnf("Failed to poll operation %v.", operation.ID)
+		}
+		events = append(events, operationEvents...)
+		if operation.IsFinished() {
+			delete(w.progress, operation.ID)
+			delete(w.phases, operation.ID)
+		}
+	}
+	w.initialized = true
+	return events, nil
+}
+
+// pollOperation returns events for the new progress entry and
+// the changed plan phases of the specified operation
+func (w *watcher) pollOperation(key ops.SiteOperationKey) (events []Event, err error) {
+	progress, err := w.Operator.GetSiteOperationProgress(key)
+	if err != nil && !trace.IsNotFound(err) {
+		return nil, trace.Wrap(err)
+	}
+	if progress != nil && w.progress[key.OperationID] != progress.ID {
+		w.progress[key.OperationID] = progress.ID
+		events = append(events, w.newEvent(EventProgress, func(e *Event) {
+			e.Progress = progress
+		}))
+	}
+	plan, err := w.Operator.GetOperationPlan(key)
+	if err != nil {
+		if trace.IsNotFound(err) {
+			return events, nil
+		}
+		return events, trace.Wrap(err)
+	}
+	states, ok := w.phases[key.OperationID]
+	if !ok {
+		states = make(map[string]string)
+		w.phases[key.OperationID] = states
+	}
+	for _, phase := range flattenPhases(plan.Phases) {
+		prevState, seen := states[phase.ID]
+		states[phase.ID] = phase.State
+		if prevState == phase.State {
+			continue
+		}
+		if !seen && (phase.State == "" || phase.State == storage.OperationPhaseStateUnstarted) {
+			continue
+		}
+		events = append(events, w.newEvent(EventPhase, func(e *Event) {
+			e.Phase = &Phase{
+				OperationID: key.OperationID,
+				ID:          phase.ID,
+				Description: phase.Description,
+				State:       phase.State,
+			}
+			if phase.Error != nil {
+				var phaseErr trace.TraceErr
+				if err := utils.UnmarshalError(phase.Error.Err, &phaseErr); err == nil && phaseErr.Err != nil {
+					e.Phase.Error = phaseErr.Err.Error()
+				}
+			}
+		}))
+	}
+	return events, nil
+}
+
+// pollStatus returns events for the nodes and alerts that have changed since the last poll.
+// Sources the subscriber has no access to are disabled
+func (w *watcher) pollStatus(ctx context.Context) (events []Event) {
+	if w.Nodes != nil {
+		nodes, err := w.Nodes(ctx)
+		if err != nil {
+			if trace.IsAccessDenied(err) {
+				w.Nodes = nil
+			}
+			w.WithError(err).Warn("Failed to collect node status.")
+		} else {
+			events = append(events, w.diffNodes(nodes)...)
+		}
+	}
+	if w.Alerts != nil {
+		alerts, err := w.Alerts(ctx)
+		if err != nil {
+			if trace.IsAccessDenied(err) {
+				w.Alerts = nil
+			}
+			w.WithError(err).Warn("Failed to collect alerts.")
+		} else {
+			events = append(events, w.diffAlerts(alerts)...)
+		}
+	}
+	return events
+}
+
+func (w *watcher) diffNodes(nodes []Node) (events []Event) {
+	current := make(map[string]Node)
+	for _, node := range nodes {
+		current[node.AdvertiseIP] = node
+		prev, ok := w.nodes[node.AdvertiseIP]
+		if ok && prev.Status == node.Status &&
+			strings.Join(prev.FailedProbes, ",") == strings.Join(node.FailedProbes, ",") {
+			continue
+		}
+		node := node
+		events = append(events, w.newEvent(EventNode, func(e *Event) {
+			e.Node = &node
+		}))
+	}
+	var removed []string
+	for ip := range w.nodes {
+		if _, ok := current[ip]; !ok {
+			removed = append(removed, ip)
+		}
+	}
+	sort.Strings(removed)
+	for _, ip := range removed {
+		node := w.nodes[ip]
+		node.Status = NodeStatusRemoved
+		node.FailedProbes = nil
+		events = append(events, w.newEvent(EventNode, func(e *Event) {
+			e.Node = &node
+		}))
+	}
+	w.nodes = current
+	return events
+}
+
+func (w *watcher) diffAlerts(alerts []Alert) (events []Event) {
+	current := make(map[string]Alert)
+	for _, alert := range alerts {
+		key := alertKey(alert)
+		current[key] = alert
+		if prev, ok := w.alerts[key]; ok && prev.State == alert.State {
+			continue
+		}
+		alert := alert
+		events = append(events, w.newEvent(EventAlert, func(e *Event) {
+			e.Alert = &alert
+		}))
+	}
+	var resolved []string
+	for key := range w.alerts {
+		if _, ok := current[key]; !ok {
+			resolved = append(resolved, key)
+		}
+	}
+	sort.Strings(resolved)
+	for _, key := range resolved {
+		alert := w.alerts[key]
+		alert.State = AlertStateResolved
+		events = append(events, w.newEvent(EventAlert, func(e *Event) {
+			e.Alert = &alert
+		}))
+	}
+	w.alerts = current
+	return events
+}
+
+func (w *watcher) newEvent(eventType string, fill func(*Event)) Event {
+	event := Event{
+		Type: eventType,
+		Time: w.Clock.Now().UTC(),
+	}
+	fill(&event)
+	return event
+}
+
+// alertKey identifies an alert by its name and labels
+func alertKey(alert Alert) string {
+	var labels []string
+	for name, value := range alert.Labels {
+		labels = append(labels, name+"="+value)
+	}
+	sort.Strings(labels)
+	return strings.Join(append([]string{alert.Name}, labels...), ",")
+}
+
+// flattenPhases returns all phases of the plan in the depth-first order
+func flattenPhases(phases []storage.OperationPhase) (result []storage.OperationPhase) {
+	for _, phase := range phases {
+		result = append(result, phase)
+		result = append(result, flattenPhases(phase.Phases)...)
+	}
+	return result
+}
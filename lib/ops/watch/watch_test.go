@@ -0,0 +1,182 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package watch
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gravitational/gravity/lib/ops"
+	"github.com/gravitational/gravity/lib/storage"
+
+	"github.com/gravitational/trace"
+	"github.com/jonboulle/clockwork"
+	"gopkg.in/check.v1"
+)
+
+func TestWatch(t *testing.T) { check.TestingT(t) }
+
+type WatchSuite struct{}
+
+var _ = check.Suite(&WatchSuite{})
+
+func (s *WatchSuite) TestOperationEvents(c *check.C) {
+	operator := &testOperator{
+		operations: ops.SiteOperations{
+			{ID: "op2", Type: ops.OperationUpdate, State: ops.OperationStateUpdateInProgress},
+			{ID: "op1", Type: ops.OperationInstall, State: ops.OperationStateCompleted},
+		},
+		progress: map[string]*ops.ProgressEntry{
+			"op2": {ID: "p1", OperationID: "op2", Message: "Updating"},
+		},
+		plans: map[string]*storage.OperationPlan{
+			"op2": {Phases: []storage.OperationPhase{
+				{ID: "/init", State: storage.OperationPhaseStateCompleted},
+				{ID: "/masters", Phases: []storage.OperationPhase{
+					{ID: "/masters/node-1", State: storage.OperationPhaseStateInProgress},
+					{ID: "/masters/node-2", State: storage.OperationPhaseStateUnstarted},
+				}},
+			}},
+		},
+	}
+	w := newTestWatcher(c, operator)
+
+	// finished operations are not reported on the initial poll
+	events, err := w.pollOperations()
+	c.Assert(err, check.IsNil)
+	c.Assert(eventTypes(events), check.DeepEquals, []string{
+		EventOperation, EventProgress, EventPhase, EventPhase,
+	})
+	c.Assert(events[0].Operation.ID, check.Equals, "op2")
+	c.Assert(events[2].Phase.ID, check.Equals, "/init")
+	c.Assert(events[3].Phase.ID, check.Equals, "/masters/node-1")
+
+	// nothing has changed
+	events, err = w.pollOperations()
+	c.Assert(err, check.IsNil)
+	c.Assert(events, check.HasLen, 0)
+
+	operator.progress["op2"] = &ops.ProgressEntry{ID: "p2", OperationID: "op2", Message: "Done"}
+	phases := operator.plans["op2"].Phases[1].Phases
+	phases[0].State = storage.OperationPhaseStateCompleted
+	phases[1].State = storage.OperationPhaseStateFailed
+	phases[1].Error = &trace.RawTrace{Err: []byte(`{"message":"node is unreachable"}`)}
+	operator.operations[0].State = ops.OperationStateFailed
+	operator.operations = append(ops.SiteOperations{
+		{ID: "op3", Type: ops.OperationExpand, State: ops.OperationStateExpandInitiated},
+	}, operator.operations...)
+
+	events, err = w.pollOperations()
+	c.Assert(err, check.IsNil)
+	c.Assert(eventTypes(events), check.DeepEquals, []string{
+		EventOperation, EventProgress, EventPhase, EventPhase, EventOperation,
+	})
+	c.Assert(events[0].Operation.State, check.Equals, ops.OperationStateFailed)
+	c.Assert(events[1].Progress.ID, check.Equals, "p2")
+	c.Assert(events[3].Phase.Error, check.Equals, "node is unreachable")
+	c.Assert(events[4].Operation.ID, check.Equals, "op3")
+
+	// finished operation is no longer polled
+	events, err = w.pollOperations()
+	c.Assert(err, check.IsNil)
+	c.Assert(events, check.HasLen, 0)
+}
+
+func (s *WatchSuite) TestStatusEvents(c *check.C) {
+	nodes := []Node{
+		{AdvertiseIP: "10.0.0.1", Status: "healthy"},
+		{AdvertiseIP: "10.0.0.2", Status: "healthy"},
+	}
+	alerts := []Alert{{Name: "CPUHigh", State: "active", Labels: map[string]string{"node": "10.0.0.1"}}}
+	w := newTestWatcher(c, &testOperator{})
+	w.Nodes = func(context.Context) ([]Node, error) { return nodes, nil }
+	w.Alerts = func(context.Context) ([]Alert, error) { return alerts, nil }
+
+	events := w.pollStatus(context.TODO())
+	c.Assert(eventTypes(events), check.DeepEquals, []string{EventNode, EventNode, EventAlert})
+
+	nodes = []Node{{AdvertiseIP: "10.0.0.1", Status: "degraded", FailedProbes: []string{"etcd-healthz"}}}
+	alerts = nil
+	events = w.pollStatus(context.TODO())
+	c.Assert(eventTypes(events), check.DeepEquals, []string{EventNode, EventNode, EventAlert})
+	c.Assert(events[0].Node.Status, check.Equals, "degraded")
+	c.Assert(events[1].Node.AdvertiseIP, check.Equals, "10.0.0.2")
+	c.Assert(events[1].Node.Status, check.Equals, NodeStatusRemoved)
+	c.Assert(events[2].Alert.State, check.Equals, AlertStateResolved)
+
+	// sources the subscriber has no access to are disabled
+	w.Alerts = func(context.Context) ([]Alert, error) { return nil, trace.AccessDenied("denied") }
+	events = w.pollStatus(context.TODO())
+	c.Assert(events, check.HasLen, 0)
+	c.Assert(w.Alerts, check.IsNil)
+}
+
+func (s *WatchSuite) TestWritesServerSentEvents(c *check.C) {
+	var buf bytes.Buffer
+	err := writeSSE(&buf, 1, Event{
+		Type: EventNode,
+		Time: time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC),
+		Node: &Node{AdvertiseIP: "10.0.0.1", Status: "healthy"},
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(buf.String(), check.Equals, "id: 1\nevent: node\n"+
+		`data: {"type":"node","time":"2019-01-01T00:00:00Z","node":{"hostname":"","advertise_ip":"10.0.0.1","status":"healthy"}}`+
+		"\n\n")
+}
+
+func newTestWatcher(c *check.C, operator Operator) *watcher {
+	config := Config{
+		Operator: operator,
+		Key:      ops.SiteKey{AccountID: "account", SiteDomain: "example.com"},
+		Clock:    clockwork.NewFakeClock(),
+	}
+	c.Assert(config.checkAndSetDefaults(), check.IsNil)
+	return newWatcher(config)
+}
+
+func eventTypes(events []Event) (types []string) {
+	for _, event := range events {
+		types = append(types, event.Type)
+	}
+	return types
+}
+
+type testOperator struct {
+	operations ops.SiteOperations
+	progress   map[string]*ops.ProgressEntry
+	plans      map[string]*storage.OperationPlan
+}
+
+func (o *testOperator) GetSiteOperations(ops.SiteKey) (ops.SiteOperations, error) {
+	return o.operations, nil
+}
+
+func (o *testOperator) GetSiteOperationProgress(key ops.SiteOperationKey) (*ops.ProgressEntry, error) {
+	if progress, ok := o.progress[key.OperationID]; ok {
+		return progress, nil
+	}
+	return nil, trace.NotFound("no progress for %v", key.OperationID)
+}
+
+func (o *testOperator) GetOperationPlan(key ops.SiteOperationKey) (*storage.OperationPlan, error) {
+	if plan, ok := o.plans[key.OperationID]; ok {
+		return plan, nil
+	}
+	return nil, trace.NotFound("no plan for %v", key.OperationID)
+}
@@ -0,0 +1,388 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package overlay
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/gravitational/gravity/lib/defaults"
+	"github.com/gravitational/gravity/lib/devicemapper"
+	"github.com/gravitational/gravity/lib/state"
+	"github.com/gravitational/gravity/lib/systemservice"
+	"github.com/gravitational/gravity/lib/utils"
+
+	"github.com/docker/docker/pkg/mount"
+	"github.com/gravitational/trace"
+	log "github.com/sirupsen/logrus"
+)
+
+// Mount formats the disk specified with config with a filesystem suitable
+// for the overlay2 storage driver and mounts it on the docker data directory.
+// The mount is persisted either as a systemd mount unit or as an fstab entry
+func Mount(config Config) error {
+	if err := config.CheckAndSetDefaults(); err != nil {
+		return trace.Wrap(err)
+	}
+	if config.Disk == "" {
+		return trace.BadParameter("disk is required")
+	}
+	if err := config.format(); err != nil {
+		return trace.Wrap(err)
+	}
+	uuid, err := config.queryUUID()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if err := os.MkdirAll(config.Path, defaults.SharedDirMask); err != nil {
+		return trace.ConvertSystemError(err)
+	}
+	what := filepath.Join(diskByUUIDDir, uuid)
+	if config.Fstab {
+		err = config.mountWithFstab(what)
+	} else {
+		err = config.mountWithUnit(what)
+	}
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	return trace.Wrap(config.verify())
+}
+
+// Unmount unmounts the docker data directory and removes the persisted
+// mount configuration.
+// If the disk has been specified, the filesystem signatures are wiped from it
+func Unmount(config Config) error {
+	if err := config.CheckAndSetDefaults(); err != nil {
+		return trace.Wrap(err)
+	}
+	name := UnitName(config.Path)
+	if _, err := os.Stat(systemservice.DefaultUnitPath(name)); err == nil {
+		config.Infof("Removing mount unit %v.", name)
+		if err := config.uninstallUnit(name); err != nil {
+			return trace.Wrap(err)
+		}
+	}
+	if err := config.removeFstabEntry(); err != nil {
+		return trace.Wrap(err)
+	}
+	if mounted, _ := mount.Mounted(config.Path); mounted {
+		if err := config.exec(exec.Command("umount", config.Path)); err != nil {
+			return trace.Wrap(err, "failed to unmount %v", config.Path)
+		}
+	}
+	if config.Disk == "" {
+		return nil
+	}
+	if err := config.exec(exec.Command("wipefs", "-a", config.Disk)); err != nil {
+		return trace.Wrap(err, "failed to wipe filesystem signatures on %v", config.Disk)
+	}
+	return nil
+}
+
+// Migrate moves the docker storage on this node from the devicemapper thin pool
+// to a disk provisioned for overlay2.
+// If no disk has been specified, the disk of the devicemapper physical volume is reused.
+// If there's no such disk either (devicemapper in loop-lvm mode), only the docker data
+// directory is cleaned up and overlay2 will use the filesystem it resides on.
+// The runtime container is expected to be stopped
+func Migrate(config Config) error {
+	if err := config.CheckAndSetDefaults(); err != nil {
+		return trace.Wrap(err)
+	}
+	if config.Disk == "" {
+		disk, err := devicemapper.QueryPhysicalVolume(config.FieldLogger)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		config.Disk = disk
+	}
+	config.Info("Removing devicemapper configuration.")
+	if err := devicemapper.Unmount(config.Out, config.FieldLogger); err != nil {
+		return trace.Wrap(err)
+	}
+	config.Infof("Cleaning up docker data directory %v.", config.Path)
+	if err := removeContents(config.Path); err != nil {
+		return trace.Wrap(err)
+	}
+	if config.Disk == "" {
+		config.Warnf("No disk found for Docker, overlay2 will use the filesystem of %v.", config.Path)
+		return nil
+	}
+	return trace.Wrap(Mount(config))
+}
+
+// UnitName returns the name of the systemd mount unit for the specified mount point
+func UnitName(path string) string {
+	parts := strings.Split(strings.Trim(filepath.Clean(path), "/"), "/")
+	for i, part := range parts {
+		parts[i] = systemservice.SystemdNameEscape(strings.Replace(part, "-", `\x2d`, -1))
+	}
+	return fmt.Sprintf("%v.mount", strings.Join(parts, "-"))
+}
+
+// CheckAndSetDefaults validates this configuration and sets defaults
+func (r *Config) CheckAndSetDefaults() error {
+	switch r.Filesystem {
+	case "":
+		r.Filesystem = FilesystemXFS
+	case FilesystemXFS, FilesystemExt4:
+	default:
+		return trace.BadParameter("unsupported filesystem %q, expected one of %q",
+			r.Filesystem, []string{FilesystemXFS, FilesystemExt4})
+	}
+	if r.Path == "" {
+		stateDir, err := state.GetStateDir()
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		r.Path = state.DockerDir(stateDir)
+	}
+	if r.FieldLogger == nil {
+		r.FieldLogger = log.WithField(trace.Component, "overlay")
+	}
+	if r.Out == nil {
+		r.Out = ioutil.Discard
+	}
+	return nil
+}
+
+// Config describes the configuration of a disk for the overlay2 storage driver
+type Config struct {
+	// FieldLogger specifies the logger
+	log.FieldLogger
+	// Disk specifies the block device to provision
+	Disk string
+	// Filesystem specifies the filesystem to format the disk with.
+	// Either xfs (default) or ext4
+	Filesystem string
+	// Path specifies the mount point.
+	// Defaults to the docker data directory of the runtime container
+	Path string
+	// Quota specifies whether to enable project quotas on the filesystem.
+	// Project quotas are required to limit the size of container writable layers
+	Quota bool
+	// Fstab specifies whether to persist the mount as an /etc/fstab entry
+	// instead of a systemd mount unit
+	Fstab bool
+	// Out specifies the output sink for commands
+	Out io.Writer
+}
+
+func (r *Config) format() error {
+	r.Infof("Formatting disk %v with %v.", r.Disk, r.Filesystem)
+	args := mkfsArgs(r.Filesystem, r.Disk, r.Quota)
+	if err := r.exec(exec.Command(args[0], args[1:]...)); err != nil {
+		return trace.Wrap(err, "failed to format disk %v", r.Disk)
+	}
+	return nil
+}
+
+func (r *Config) queryUUID() (uuid string, err error) {
+	var out bytes.Buffer
+	cmd := exec.Command("blkid", "-s", "UUID", "-o", "value", r.Disk)
+	if err := utils.ExecL(cmd, &out, r.FieldLogger); err != nil {
+		return "", trace.Wrap(err, "failed to query filesystem UUID of %v", r.Disk)
+	}
+	uuid = strings.TrimSpace(out.String())
+	if uuid == "" {
+		return "", trace.NotFound("no filesystem UUID found for %v", r.Disk)
+	}
+	return uuid, nil
+}
+
+func (r *Config) mountWithUnit(what string) error {
+	services, err := systemservice.New()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	name := UnitName(r.Path)
+	r.Infof("Installing mount unit %v.", name)
+	err = services.InstallMountService(systemservice.NewMountServiceRequest{
+		ServiceSpec: systemservice.MountServiceSpec{
+			What:    what,
+			Where:   r.Path,
+			Type:    r.Filesystem,
+			Options: mountOptions(r.Filesystem, r.Quota),
+		},
+		Name: name,
+	})
+	if err != nil {
+		return trace.Wrap(err, "failed to install mount unit %v", name)
+	}
+	return nil
+}
+
+func (r *Config) uninstallUnit(name string) error {
+	services, err := systemservice.New()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	err = services.UninstallService(systemservice.UninstallServiceRequest{Name: name})
+	if err != nil {
+		return trace.Wrap(err, "failed to uninstall mount unit %v", name)
+	}
+	return nil
+}
+
+func (r *Config) mountWithFstab(what string) error {
+	r.Infof("Adding %v entry to %v.", r.Path, fstabPath)
+	entry := fstabEntry(what, r.Path, r.Filesystem, mountOptions(r.Filesystem, r.Quota))
+	if err := r.updateFstab(entry); err != nil {
+		return trace.Wrap(err)
+	}
+	if err := r.exec(exec.Command("mount", r.Path)); err != nil {
+		return trace.Wrap(err, "failed to mount %v", r.Path)
+	}
+	return nil
+}
+
+func (r *Config) removeFstabEntry() error {
+	return r.updateFstab("")
+}
+
+func (r *Config) updateFstab(entry string) error {
+	f, err := os.Open(fstabPath)
+	if err != nil {
+		return trace.ConvertSystemError(err)
+	}
+	contents, err := updateFstab(f, r.Path, entry)
+	f.Close()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	err = ioutil.WriteFile(fstabPath, []byte(contents), defaults.SharedReadMask)
+	return trace.ConvertSystemError(err)
+}
+
+// verify makes sure the mounted filesystem supports d_type which is required
+// by the overlay2 storage driver
+func (r *Config) verify() error {
+	if r.Filesystem != FilesystemXFS {
+		return nil
+	}
+	var out bytes.Buffer
+	if err := utils.ExecL(exec.Command("xfs_info", r.Path), &out, r.FieldLogger); err != nil {
+		return trace.Wrap(err, "failed to query filesystem information for %v", r.Path)
+	}
+	if !hasFtype(out.String()) {
+		return trace.BadParameter("filesystem on %v is not formatted with ftype=1 "+
+			"which is required for overlay2", r.Disk)
+	}
+	return nil
+}
+
+func (r *Config) exec(cmd *exec.Cmd) error {
+	return utils.ExecL(cmd, r.Out, r.FieldLogger)
+}
+
+func mkfsArgs(filesystem, disk string, quota bool) []string {
+	if filesystem == FilesystemExt4 {
+		args := []string{"mkfs.ext4", "-F"}
+		if quota {
+			args = append(args, "-O", "quota,project")
+		}
+		return append(args, disk)
+	}
+	return []string{"mkfs.xfs", "-f", "-n", "ftype=1", disk}
+}
+
+func mountOptions(filesystem string, quota bool) []string {
+	options := []string{"defaults"}
+	if !quota {
+		return options
+	}
+	if filesystem == FilesystemExt4 {
+		return append(options, "prjquota")
+	}
+	return append(options, "pquota")
+}
+
+func fstabEntry(what, where, filesystem string, options []string) string {
+	return fmt.Sprintf("%v %v %v %v 0 0", what, where, filesystem, strings.Join(options, ","))
+}
+
+// updateFstab returns the fstab contents read from r with the entry for the mount point
+// path replaced with entry. If entry is empty, the existing entry is removed
+func updateFstab(r io.Reader, path, entry string) (string, error) {
+	var buf bytes.Buffer
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := s.Text()
+		fields := strings.Fields(line)
+		if len(fields) > 1 && !strings.HasPrefix(fields[0], "#") && filepath.Clean(fields[1]) == filepath.Clean(path) {
+			continue
+		}
+		buf.WriteString(line)
+		buf.WriteByte('\n')
+	}
+	if err := s.Err(); err != nil {
+		return "", trace.Wrap(err)
+	}
+	if entry != "" {
+		buf.WriteString(entry)
+		buf.WriteByte('\n')
+	}
+	return buf.String(), nil
+}
+
+// hasFtype returns true if the xfs_info output indicates that
+// the filesystem has been formatted with ftype=1
+func hasFtype(info string) bool {
+	for _, field := range strings.Fields(info) {
+		if field == "ftype=1" {
+			return true
+		}
+	}
+	return false
+}
+
+func removeContents(dir string) error {
+	names, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return trace.ConvertSystemError(err)
+	}
+	for _, name := range names {
+		if err := os.RemoveAll(filepath.Join(dir, name.Name())); err != nil {
+			return trace.ConvertSystemError(err)
+		}
+	}
+	return nil
+}
+
+const (
+	// FilesystemXFS names the XFS filesystem
+	FilesystemXFS = "xfs"
+	// FilesystemExt4 names the ext4 filesystem
+	FilesystemExt4 = "ext4"
+)
+
+// fstabPath specifies the location of the filesystem table
+const fstabPath = "/etc/fstab"
+
+// diskByUUIDDir is the directory with device links named after filesystem UUIDs
+const diskByUUIDDir = "/dev/disk/by-uuid"
@@ -0,0 +1,82 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package overlay
+
+import (
+	"strings"
+	"testing"
+
+	. "gopkg.in/check.v1"
+)
+
+func TestOverlay(t *testing.T) { TestingT(t) }
+
+type OverlaySuite struct{}
+
+var _ = Suite(&OverlaySuite{})
+
+func (r *OverlaySuite) TestUnitName(c *C) {
+	c.Assert(UnitName("/var/lib/gravity/planet/docker"), Equals, "var-lib-gravity-planet-docker.mount")
+	c.Assert(UnitName("/mnt/docker-data/"), Equals, `mnt-docker\x2ddata.mount`)
+}
+
+func (r *OverlaySuite) TestBuildsFormatCommand(c *C) {
+	c.Assert(mkfsArgs(FilesystemXFS, "/dev/sdb", true), DeepEquals,
+		[]string{"mkfs.xfs", "-f", "-n", "ftype=1", "/dev/sdb"})
+	c.Assert(mkfsArgs(FilesystemExt4, "/dev/sdb", false), DeepEquals,
+		[]string{"mkfs.ext4", "-F", "/dev/sdb"})
+	c.Assert(mkfsArgs(FilesystemExt4, "/dev/sdb", true), DeepEquals,
+		[]string{"mkfs.ext4", "-F", "-O", "quota,project", "/dev/sdb"})
+}
+
+func (r *OverlaySuite) TestMountOptions(c *C) {
+	c.Assert(mountOptions(FilesystemXFS, false), DeepEquals, []string{"defaults"})
+	c.Assert(mountOptions(FilesystemXFS, true), DeepEquals, []string{"defaults", "pquota"})
+	c.Assert(mountOptions(FilesystemExt4, true), DeepEquals, []string{"defaults", "prjquota"})
+}
+
+func (r *OverlaySuite) TestUpdatesFstab(c *C) {
+	const fstab = `# /etc/fstab
+UUID=1234 /                       xfs     defaults        0 0
+/dev/sdb /var/lib/gravity/planet/docker ext4 defaults 0 0
+`
+	entry := fstabEntry("/dev/disk/by-uuid/5678", "/var/lib/gravity/planet/docker",
+		FilesystemXFS, mountOptions(FilesystemXFS, true))
+	out, err := updateFstab(strings.NewReader(fstab), "/var/lib/gravity/planet/docker/", entry)
+	c.Assert(err, IsNil)
+	c.Assert(out, Equals, `# /etc/fstab
+UUID=1234 /                       xfs     defaults        0 0
+/dev/disk/by-uuid/5678 /var/lib/gravity/planet/docker xfs defaults,pquota 0 0
+`)
+
+	out, err = updateFstab(strings.NewReader(out), "/var/lib/gravity/planet/docker", "")
+	c.Assert(err, IsNil)
+	c.Assert(out, Equals, `# /etc/fstab
+UUID=1234 /                       xfs     defaults        0 0
+`)
+}
+
+func (r *OverlaySuite) TestDetectsFtype(c *C) {
+	const info = `meta-data=/dev/sdb               isize=512    agcount=4, agsize=655360 blks
+         =                       sectsz=512   attr=2, projid32bit=1
+data     =                       bsize=4096   blocks=2621440, imaxpct=25
+naming   =version 2              bsize=4096   ascii-ci=0 ftype=%v
+log      =internal               bsize=4096   blocks=2560, version=2
+`
+	c.Assert(hasFtype(strings.Replace(info, "%v", "1", 1)), Equals, true)
+	c.Assert(hasFtype(strings.Replace(info, "%v", "0", 1)), Equals, false)
+}
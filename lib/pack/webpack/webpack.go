@@ -25,6 +25,8 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/gravitational/gravity/lib/defaults"
+	"github.com/gravitational/gravity/lib/httplib"
 	"github.com/gravitational/gravity/lib/loc"
 	"github.com/gravitational/gravity/lib/pack"
 	"github.com/gravitational/gravity/lib/storage"
@@ -47,6 +49,8 @@ type Config struct {
 	Users users.Identity
 	// Authenticator is used to authenticate requests.
 	Authenticator users.Authenticator
+	// RateLimiter optionally limits the requests of each user and API key.
+	RateLimiter *httplib.RateLimiter
 }
 
 // CheckAndSetDefaults validates the request and sets some defaults.
@@ -90,10 +94,10 @@ func NewHandler(cfg Config) (*Server, error) {
 	h.DELETE("/pack/v1/repositories/:repository", h.needsAuth(h.deleteRepository))
 	h.GET("/pack/v1/repositories", h.needsAuth(h.getRepositories))
 	h.GET("/pack/v1/repositories/:repository", h.needsAuth(h.getRepository))
-	h.POST("/pack/v1/repositories/:repository/packages", h.needsAuth(h.createPackage))
+	h.POST("/pack/v1/repositories/:repository/packages", h.needsAuthTransfer(h.createPackage))
 	h.GET("/pack/v1/repositories/:repository/packages", h.needsAuth(h.getPackages))
-	h.GET("/pack/v1/repositories/:repository/packages/:package_name/:package_version/file", h.needsAuth(h.getPackageFile))
-	h.HEAD("/pack/v1/repositories/:repository/packages/:package_name/:package_version/file", h.needsAuth(h.getPackageFile))
+	h.GET("/pack/v1/repositories/:repository/packages/:package_name/:package_version/file", h.needsAuthTransfer(h.getPackageFile))
+	h.HEAD("/pack/v1/repositories/:repository/packages/:package_name/:package_version/file", h.needsAuthTransfer(h.getPackageFile))
 	h.GET("/pack/v1/repositories/:repository/packages/:package_name/:package_version/envelope", h.needsAuth(h.getPackageEnvelope))
 	h.POST("/pack/v1/repositories/:repository/packages/:package_name/:package_version", h.needsAuth(h.updatePackageLabels))
 	h.DELETE("/pack/v1/repositories/:repository/packages/:package_name/:package_version", h.needsAuth(h.deletePackage))
@@ -313,7 +317,19 @@ func (s *Server) updatePackageLabels(w http.ResponseWriter, r *http.Request, p h
 	return nil
 }
 
+// needsAuth authenticates the request and accounts it against the read
+// or write budget depending on the request method.
 func (s *Server) needsAuth(fn authHandle) httprouter.Handle {
+	return s.authenticate("", fn)
+}
+
+// needsAuthTransfer authenticates the request and accounts it against
+// the transfer budget.
+func (s *Server) needsAuthTransfer(fn authHandle) httprouter.Handle {
+	return s.authenticate(httplib.RequestTransfer, fn)
+}
+
+func (s *Server) authenticate(class httplib.RequestClass, fn authHandle) httprouter.Handle {
 	return func(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
 		logger := log.WithFields(fields.FromRequest(r))
 
@@ -328,8 +344,13 @@ func (s *Server) needsAuth(fn authHandle) httprouter.Handle {
 		// and pass it to the handlers, so every action will be automatically
 		// checked against current user
 		service := pack.PackagesWithACL(s.cfg.Packages, s.cfg.Users, authResult.User, authResult.Checker)
-		if err := fn(w, r, p, service); err != nil {
-			if trace.IsAccessDenied(err) {
+		err = s.rateLimit(w, r, class, authResult.User, requestCluster(authResult.User, p), func() error {
+			return fn(w, r, p, service)
+		})
+		if err != nil {
+			if trace.IsLimitExceeded(err) {
+				logger.WithError(err).Debug("Rate limit exceeded.")
+			} else if trace.IsAccessDenied(err) {
 				logger.WithError(err).Warn("Access denied.")
 			} else if !trace.IsNotFound(err) && !trace.IsAlreadyExists(err) {
 				logger.WithError(err).Error("Handler error.")
@@ -339,6 +360,32 @@ func (s *Server) needsAuth(fn authHandle) httprouter.Handle {
 	}
 }
 
+// rateLimit invokes fn if the request fits the budgets of the user, the API key
+// and the cluster. The class is derived from the request method if unspecified.
+func (s *Server) rateLimit(w http.ResponseWriter, r *http.Request, class httplib.RequestClass, user storage.User, cluster string, fn func() error) error {
+	if s.cfg.RateLimiter == nil {
+		return fn()
+	}
+	if class == "" {
+		class = httplib.RequestClassFor(r)
+	}
+	return s.cfg.RateLimiter.Limit(w, class, httplib.RequestSubjects(r, user.GetName(), cluster), fn)
+}
+
+// requestCluster returns the name of the cluster the request is accounted against:
+// the cluster of the agent user or the cluster the requested repository belongs to.
+// Requests to the system repository are not accounted against any cluster
+func requestCluster(user storage.User, p httprouter.Params) string {
+	if user.GetType() == storage.AgentUser && user.GetClusterName() != "" {
+		return user.GetClusterName()
+	}
+	// cluster packages are kept in the repository named after the cluster
+	if repository := p.ByName("repository"); repository != defaults.SystemAccountOrg {
+		return repository
+	}
+	return ""
+}
+
 type authHandle func(
 	http.ResponseWriter, *http.Request, httprouter.Params, pack.PackageService) error
 
@@ -30,6 +30,7 @@ import (
 	"github.com/gravitational/gravity/lib/blob/fs"
 	"github.com/gravitational/gravity/lib/defaults"
 	"github.com/gravitational/gravity/lib/loc"
+	"github.com/gravitational/gravity/lib/openapi"
 	"github.com/gravitational/gravity/lib/pack/localpack"
 	"github.com/gravitational/gravity/lib/pack/suite"
 	"github.com/gravitational/gravity/lib/storage"
@@ -40,6 +41,7 @@ import (
 	"github.com/gravitational/roundtrip"
 	teleservices "github.com/gravitational/teleport/lib/services"
 	"github.com/gravitational/trace"
+	"github.com/julienschmidt/httprouter"
 	"github.com/mailgun/timetools"
 	log "github.com/sirupsen/logrus"
 	. "gopkg.in/check.v1"
@@ -52,6 +54,7 @@ type WebpackSuite struct {
 	backend   storage.Backend
 	suite     suite.PackageSuite
 	webServer *httptest.Server
+	validator *openapi.Validator
 	users     users.Identity
 	clock     *timetools.FreezedTime
 
@@ -110,8 +113,12 @@ func (s *WebpackSuite) SetUpTest(c *C) {
 		Packages: service,
 	})
 	c.Assert(err, IsNil)
+	// Validate the requests and the responses against the specification
+	spec, err := openapi.Load("../../openapi/openapi.yaml")
+	c.Assert(err, IsNil)
+	s.validator = openapi.NewValidator(spec, openapi.APIPack, webHandler)
 	mux := http.NewServeMux()
-	mux.Handle("/pack/", webHandler)
+	mux.Handle("/pack/", s.validator)
 
 	// It is important that we launch TLS server as authentication
 	// middleware on the handler expects TLS connections.
@@ -273,9 +280,24 @@ func (s *WebpackSuite) TestPermissionsCRUD(c *C) {
 
 func (s *WebpackSuite) TearDownTest(c *C) {
 	s.webServer.Close()
+	c.Assert(s.validator.Error(), IsNil)
 	c.Assert(s.backend.Close(), IsNil)
 }
 
+func (s *WebpackSuite) TestRequestCluster(c *C) {
+	agent := storage.NewUser("agent@example.com", storage.UserSpecV2{
+		Type:        storage.AgentUser,
+		ClusterName: "example.com",
+	})
+	params := func(repository string) httprouter.Params {
+		return httprouter.Params{{Key: "repository", Value: repository}}
+	}
+	c.Assert(requestCluster(agent, params(defaults.SystemAccountOrg)), Equals, "example.com")
+	c.Assert(requestCluster(s.adminUser, params("example.com")), Equals, "example.com")
+	c.Assert(requestCluster(s.adminUser, params(defaults.SystemAccountOrg)), Equals, "")
+	c.Assert(requestCluster(s.adminUser, nil), Equals, "")
+}
+
 func (s *WebpackSuite) TestRepositoriesCRUD(c *C) {
 	s.suite.RepositoriesCRUD(c)
 }
@@ -0,0 +1,293 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package process
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"time"
+
+	"github.com/gravitational/gravity/lib/defaults"
+	"github.com/gravitational/gravity/lib/storage"
+
+	teleevents "github.com/gravitational/teleport/lib/events"
+	"github.com/gravitational/trace"
+	"github.com/sirupsen/logrus"
+)
+
+// runAuditForwarder runs a service that periodically sends the new
+// hash-chained audit log records to the configured log forwarders and
+// emits the records that have failed to be emitted to the Teleport audit log
+func (p *Process) runAuditForwarder(ctx context.Context) {
+	p.Info("Starting audit log forwarder.")
+	forwarder, err := newAuditForwarder(p.backend, func() ([]storage.LogForwarder, error) {
+		cluster, err := p.operator.GetLocalSite()
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		return p.operator.GetLogForwarders(cluster.Key())
+	}, p.proxy.authClient)
+	if err != nil {
+		p.WithError(err).Error("Failed to start audit log forwarder.")
+		return
+	}
+	ticker := time.NewTicker(defaults.AuditForwardInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := forwarder.emitPending(); err != nil {
+				p.WithError(err).Warn("Failed to emit pending audit events.")
+			}
+			if err := forwarder.forward(); err != nil {
+				p.WithError(err).Warn("Failed to forward audit log records.")
+			}
+		case <-ctx.Done():
+			p.Info("Stopping audit log forwarder.")
+			return
+		}
+	}
+}
+
+// runAuditRecordsPruner runs a service that periodically removes
+// the audit log records older than the configured retention period
+func (p *Process) runAuditRecordsPruner(ctx context.Context) {
+	retention := p.cfg.Audit.GetRetention()
+	p.Infof("Starting audit log pruner, keeping records for %v.", retention)
+	ticker := time.NewTicker(defaults.AuditRecordsPruneInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			pruned, err := p.backend.PruneAuditRecords(p.backend.Now().Add(-retention))
+			if err != nil {
+				p.WithError(err).Warn("Failed to prune audit log records.")
+				continue
+			}
+			if pruned != 0 {
+				p.Infof("Pruned %v audit log records.", pruned)
+			}
+		case <-ctx.Done():
+			p.Info("Stopping audit log pruner.")
+			return
+		}
+	}
+}
+
+// newAuditForwarder returns a forwarder that sends records appended
+// to the audit log to the log forwarders.
+//
+// The index of the last record sent to each log forwarder is saved in the
+// backend so forwarding resumes where it has stopped after the active master
+// changes. Log forwarders that have been added since the forwarder has been
+// created only receive the records appended after that
+func newAuditForwarder(backend storage.Backend, getForwarders func() ([]storage.LogForwarder, error), auditLog teleevents.IAuditLog) (*auditForwarder, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return nil, trace.ConvertSystemError(err)
+	}
+	forwarder := &auditForwarder{
+		FieldLogger:   logrus.WithField(trace.Component, "audit"),
+		backend:       backend,
+		getForwarders: getForwarders,
+		auditLog:      auditLog,
+		hostname:      hostname,
+	}
+	last, err := backend.GetLastAuditRecord()
+	if err != nil && !trace.IsNotFound(err) {
+		return nil, trace.Wrap(err)
+	}
+	if last != nil {
+		forwarder.last = last.Index
+	}
+	return forwarder, nil
+}
+
+// auditForwarder sends audit log records to log forwarders as syslog messages
+type auditForwarder struct {
+	logrus.FieldLogger
+	backend       storage.Backend
+	getForwarders func() ([]storage.LogForwarder, error)
+	auditLog      teleevents.IAuditLog
+	hostname      string
+	// last is the index of the last known record in the audit log
+	last uint64
+}
+
+// forward sends the records that have not been sent yet to each log forwarder.
+// Forwarders that have been added since the last call only receive new records
+func (f *auditForwarder) forward() error {
+	forwarders, err := f.getForwarders()
+	if err != nil {
+		if trace.IsBadParameter(err) {
+			// log forwarders are not supported by the operator
+			return nil
+		}
+		return trace.Wrap(err)
+	}
+	cursors, err := f.backend.GetAuditCursors()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	var errors []error
+	names := make(map[string]bool, len(forwarders))
+	for _, forwarder := range forwarders {
+		names[forwarder.GetName()] = true
+		cursor, ok := cursors[forwarder.GetName()]
+		if !ok {
+			cursor = f.last
+			if err := f.backend.UpsertAuditCursor(forwarder.GetName(), cursor); err != nil {
+				return trace.Wrap(err)
+			}
+		}
+		if err := f.forwardTo(forwarder, cursor); err != nil {
+			errors = append(errors, trace.Wrap(err, "failed to forward audit records to %v",
+				forwarder.GetAddress()))
+		}
+	}
+	for name := range cursors {
+		if names[name] {
+			continue
+		}
+		// the log forwarder has been removed
+		if err := f.backend.DeleteAuditCursor(name); err != nil && !trace.IsNotFound(err) {
+			errors = append(errors, trace.Wrap(err))
+		}
+	}
+	return trace.NewAggregate(errors...)
+}
+
+// forwardTo sends the records after the cursor to the log forwarder in batches
+// and saves the index of the last record that has been sent
+func (f *auditForwarder) forwardTo(forwarder storage.LogForwarder, cursor uint64) error {
+	for {
+		records, err := f.backend.GetAuditRecords(storage.AuditRecordsFilter{
+			FromIndex: cursor + 1,
+			Limit:     defaults.AuditForwardBatchSize,
+		})
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		if len(records) != 0 && records[len(records)-1].Index > f.last {
+			f.last = records[len(records)-1].Index
+		}
+		next, err := f.send(forwarder, records, cursor)
+		if next != cursor {
+			if err := f.backend.UpsertAuditCursor(forwarder.GetName(), next); err != nil {
+				return trace.Wrap(err)
+			}
+			cursor = next
+		}
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		if len(records) < defaults.AuditForwardBatchSize {
+			return nil
+		}
+	}
+}
+
+// send sends the records to the log forwarder and returns the index
+// of the last record that has been sent
+func (f *auditForwarder) send(forwarder storage.LogForwarder, records []storage.AuditRecord, cursor uint64) (uint64, error) {
+	if len(records) == 0 {
+		return cursor, nil
+	}
+	conn, err := net.DialTimeout(forwarder.GetProtocol(), forwarder.GetAddress(), defaults.DialTimeout)
+	if err != nil {
+		return cursor, trace.ConvertSystemError(err)
+	}
+	defer conn.Close()
+	for _, record := range records {
+		message, err := formatSyslogRecord(record, f.hostname)
+		if err != nil {
+			return cursor, trace.Wrap(err)
+		}
+		if _, err := conn.Write(message); err != nil {
+			return cursor, trace.ConvertSystemError(err)
+		}
+		cursor = record.Index
+	}
+	f.Debugf("Forwarded audit records up to %v to %v.", cursor, forwarder.GetAddress())
+	return cursor, nil
+}
+
+// emitPending emits the audit records that have failed to be emitted
+// to the Teleport audit log, in order
+func (f *auditForwarder) emitPending() error {
+	indexes, err := f.backend.GetPendingAuditRecords()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	for _, index := range indexes {
+		records, err := f.backend.GetAuditRecords(storage.AuditRecordsFilter{
+			FromIndex: index,
+			Limit:     1,
+		})
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		if len(records) != 0 && records[0].Index == index {
+			if err := f.emit(records[0]); err != nil {
+				return trace.Wrap(err)
+			}
+		} else {
+			f.Warnf("Audit record %v has been pruned before it was emitted.", index)
+		}
+		if err := f.backend.DeletePendingAuditRecord(index); err != nil && !trace.IsNotFound(err) {
+			return trace.Wrap(err)
+		}
+	}
+	return nil
+}
+
+// emit emits the audit record to the Teleport audit log with its original time
+func (f *auditForwarder) emit(record storage.AuditRecord) error {
+	fields := make(teleevents.EventFields, len(record.Fields)+1)
+	for name, value := range record.Fields {
+		fields[name] = value
+	}
+	fields[teleevents.EventTime] = record.Time
+	err := f.auditLog.EmitAuditEvent(teleevents.Event{
+		Name: record.Event,
+		Code: record.Code,
+	}, fields)
+	return trace.Wrap(err)
+}
+
+// formatSyslogRecord formats the audit record as RFC 5424 syslog message
+// with the JSON-encoded record as the message body
+func formatSyslogRecord(record storage.AuditRecord, hostname string) ([]byte, error) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	msgID := record.Code
+	if msgID == "" {
+		msgID = "-"
+	}
+	return []byte(fmt.Sprintf("<%v>1 %v %v %v - %v - %s\n",
+		syslogAuditPriority, record.Time.Format(time.RFC3339Nano), hostname,
+		defaults.AuditSyslogTag, msgID, data)), nil
+}
+
+// syslogAuditPriority is the syslog priority of the audit records:
+// facility "log audit" (13) and severity "informational" (6)
+const syslogAuditPriority = 13*8 + 6
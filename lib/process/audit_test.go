@@ -0,0 +1,156 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package process
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gravitational/gravity/lib/storage"
+	"github.com/gravitational/gravity/lib/storage/keyval"
+
+	teleevents "github.com/gravitational/teleport/lib/events"
+	"gopkg.in/check.v1"
+)
+
+type AuditSuite struct{}
+
+var _ = check.Suite(&AuditSuite{})
+
+func (s *AuditSuite) TestForwardsNewRecords(c *check.C) {
+	backend, err := keyval.NewBolt(keyval.BoltConfig{
+		Path: filepath.Join(c.MkDir(), "test.db"),
+	})
+	c.Assert(err, check.IsNil)
+	defer backend.Close()
+	appendRecord := func(event string) {
+		record, err := storage.NewAuditRecord(event, "T0000I", nil, time.Now())
+		c.Assert(err, check.IsNil)
+		_, err = backend.AppendAuditRecord(*record)
+		c.Assert(err, check.IsNil)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, check.IsNil)
+	defer listener.Close()
+	lines := make(chan string, 10)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			scanner := bufio.NewScanner(conn)
+			for scanner.Scan() {
+				lines <- scanner.Text()
+			}
+			conn.Close()
+		}
+	}()
+
+	appendRecord("before.start")
+	getForwarders := func() ([]storage.LogForwarder, error) {
+		return []storage.LogForwarder{
+			storage.NewLogForwarder("test", listener.Addr().String(), "tcp"),
+		}, nil
+	}
+	forwarder, err := newAuditForwarder(backend, getForwarders, nil)
+	c.Assert(err, check.IsNil)
+
+	appendRecord("after.start")
+	c.Assert(forwarder.forward(), check.IsNil)
+	c.Assert(receiveAuditRecord(c, lines).Event, check.Equals, "after.start")
+
+	// already forwarded records are not sent again
+	appendRecord("next")
+	c.Assert(forwarder.forward(), check.IsNil)
+	record := receiveAuditRecord(c, lines)
+	c.Assert(record.Event, check.Equals, "next")
+	c.Assert(record.Index, check.Equals, uint64(3))
+
+	// a new forwarder resumes from the saved cursor, e.g. after
+	// the active master has changed
+	appendRecord("after.restart")
+	forwarder, err = newAuditForwarder(backend, getForwarders, nil)
+	c.Assert(err, check.IsNil)
+	c.Assert(forwarder.forward(), check.IsNil)
+	record = receiveAuditRecord(c, lines)
+	c.Assert(record.Event, check.Equals, "after.restart")
+	c.Assert(record.Index, check.Equals, uint64(4))
+
+	// cursors of removed forwarders are deleted
+	forwarder.getForwarders = func() ([]storage.LogForwarder, error) { return nil, nil }
+	c.Assert(forwarder.forward(), check.IsNil)
+	cursors, err := backend.GetAuditCursors()
+	c.Assert(err, check.IsNil)
+	c.Assert(cursors, check.HasLen, 0)
+}
+
+func (s *AuditSuite) TestEmitsPendingRecords(c *check.C) {
+	backend, err := keyval.NewBolt(keyval.BoltConfig{
+		Path: filepath.Join(c.MkDir(), "test.db"),
+	})
+	c.Assert(err, check.IsNil)
+	defer backend.Close()
+	for _, event := range []string{"first", "second"} {
+		record, err := storage.NewAuditRecord(event, "T0000I", map[string]interface{}{"user": "alice"}, time.Now())
+		c.Assert(err, check.IsNil)
+		record, err = backend.AppendAuditRecord(*record)
+		c.Assert(err, check.IsNil)
+		c.Assert(backend.AddPendingAuditRecord(record.Index), check.IsNil)
+	}
+	// the record has been pruned before it could be emitted
+	c.Assert(backend.AddPendingAuditRecord(10), check.IsNil)
+
+	auditLog := &recordingAuditLog{}
+	forwarder, err := newAuditForwarder(backend, nil, auditLog)
+	c.Assert(err, check.IsNil)
+	c.Assert(forwarder.emitPending(), check.IsNil)
+	c.Assert(auditLog.events, check.DeepEquals, []string{"first", "second"})
+
+	pending, err := backend.GetPendingAuditRecords()
+	c.Assert(err, check.IsNil)
+	c.Assert(pending, check.HasLen, 0)
+}
+
+// recordingAuditLog records the names of the emitted events
+type recordingAuditLog struct {
+	teleevents.IAuditLog
+	events []string
+}
+
+func (r *recordingAuditLog) EmitAuditEvent(event teleevents.Event, fields teleevents.EventFields) error {
+	r.events = append(r.events, event.Name)
+	return nil
+}
+
+func receiveAuditRecord(c *check.C, lines chan string) storage.AuditRecord {
+	select {
+	case line := <-lines:
+		c.Assert(strings.HasPrefix(line, "<110>1 "), check.Equals, true, check.Commentf(line))
+		var record storage.AuditRecord
+		c.Assert(json.Unmarshal([]byte(line[strings.Index(line, "{"):]), &record), check.IsNil)
+		return record
+	case <-time.After(5 * time.Second):
+		c.Fatal("timeout waiting for audit record")
+	}
+	return storage.AuditRecord{}
+}
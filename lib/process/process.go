@@ -35,12 +35,15 @@ import (
 	"github.com/gravitational/gravity/lib/app"
 	apphandler "github.com/gravitational/gravity/lib/app/handler"
 	appservice "github.com/gravitational/gravity/lib/app/service"
+	"github.com/gravitational/gravity/lib/autoscale"
 	"github.com/gravitational/gravity/lib/autoscale/aws"
+	"github.com/gravitational/gravity/lib/autoscale/gce"
 	"github.com/gravitational/gravity/lib/blob"
 	blobclient "github.com/gravitational/gravity/lib/blob/client"
 	blobcluster "github.com/gravitational/gravity/lib/blob/cluster"
 	blobfs "github.com/gravitational/gravity/lib/blob/fs"
 	blobhandler "github.com/gravitational/gravity/lib/blob/handler"
+	"github.com/gravitational/gravity/lib/checks/background"
 	"github.com/gravitational/gravity/lib/clients"
 	cloudaws "github.com/gravitational/gravity/lib/cloudprovider/aws"
 	"github.com/gravitational/gravity/lib/constants"
@@ -50,7 +53,9 @@ import (
 	"github.com/gravitational/gravity/lib/httplib"
 	"github.com/gravitational/gravity/lib/loc"
 	"github.com/gravitational/gravity/lib/modules"
+	"github.com/gravitational/gravity/lib/nodepool"
 	"github.com/gravitational/gravity/lib/ops"
+	"github.com/gravitational/gravity/lib/ops/events"
 	"github.com/gravitational/gravity/lib/ops/monitoring"
 	"github.com/gravitational/gravity/lib/ops/opshandler"
 	"github.com/gravitational/gravity/lib/ops/opsroute"
@@ -89,7 +94,9 @@ import (
 	"github.com/gravitational/roundtrip"
 	"github.com/gravitational/teleport"
 	"github.com/gravitational/trace"
+	"github.com/jonboulle/clockwork"
 	"github.com/julienschmidt/httprouter"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/sirupsen/logrus"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/version"
@@ -132,7 +139,7 @@ type Process struct {
 	// handlers contains all initialized web handlers
 	handlers Handlers
 	// rpcCreds holds generated RPC agents credentials
-	rpcCreds rpcCredentials
+	rpcCreds *rpcCredentials
 	// authGatewayConfig is the current auth gateway configuration (basically,
 	// a config that gets applied on top of teleport's config the process
 	// was started with)
@@ -165,11 +172,38 @@ type Handlers struct {
 	Registry http.Handler
 }
 
-// rpcCredentials holds generated RPC agents credentials
+// rpcCredentials holds generated RPC agents credentials.
+// The credentials are updated when the credentials package changes
 type rpcCredentials struct {
+	sync.RWMutex
 	ca     *authority.TLSKeyPair
 	client *authority.TLSKeyPair
 	server *authority.TLSKeyPair
+	// serverCert is the server certificate for gRPC connections
+	// served on the web listener
+	serverCert tls.Certificate
+	// clientCreds specifies the client transport credentials
+	clientCreds *rpc.ReloadableCredentials
+}
+
+func (r *rpcCredentials) update(archive utils.TLSArchive) error {
+	serverCert, err := tls.X509KeyPair(archive[pb.Server].CertPEM, archive[pb.Server].KeyPEM)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	r.Lock()
+	defer r.Unlock()
+	r.ca = archive[pb.CA]
+	r.client = archive[pb.Client]
+	r.server = archive[pb.Server]
+	r.serverCert = serverCert
+	return nil
+}
+
+func (r *rpcCredentials) getServerCert() *tls.Certificate {
+	r.RLock()
+	defer r.RUnlock()
+	return &r.serverCert
 }
 
 // ServiceStartedEvent defines the payload of the gravity service start event.
@@ -509,48 +543,206 @@ func (p *Process) leaderStatus() (string, bool) {
 }
 
 func (p *Process) startAutoscale(ctx context.Context) error {
-	_, err := cloudaws.NewLocalInstance()
+	site, err := p.operator.GetLocalSite()
 	if err != nil {
-		p.Info("Not on AWS, skip autoscaler start.")
+		return trace.Wrap(err)
+	}
+	var autoscaler autoscale.Autoscaler
+	_, awsErr := cloudaws.NewLocalInstance()
+	switch {
+	case awsErr == nil:
+		autoscaler, err = p.newAWSAutoscaler(ctx, *site)
+	case site.Provider == schema.ProviderGCE:
+		autoscaler, err = p.newGCEAutoscaler(*site)
+	default:
+		p.Info("Not on AWS or GCE, skip autoscaler start.")
 		return nil
 	}
-	p.Info("Starting AWS autoscaler.")
-	site, err := p.operator.GetLocalSite()
 	if err != nil {
 		return trace.Wrap(err)
 	}
+	if autoscaler == nil {
+		return nil
+	}
+
+	// remove nodes deleted from the instance group
+	p.RegisterClusterService(func(ctx context.Context) {
+		localCtx := context.WithValue(ctx, constants.UserContext,
+			constants.ServiceAutoscaler)
+		autoscaler.ProcessEvents(localCtx, p.operator)
+	})
+	// publish discovery information about this cluster
+	p.RegisterClusterService(func(ctx context.Context) {
+		localCtx := context.WithValue(ctx, constants.UserContext,
+			constants.ServiceAutoscaler)
+		autoscaler.PublishDiscovery(localCtx, p.operator)
+	})
+	return nil
+}
+
+// newAWSAutoscaler returns a new autoscaler that receives events from SQS
+// notification service.
+// Returns nil if the cluster has no autoscale queue
+func (p *Process) newAWSAutoscaler(ctx context.Context, site ops.Site) (autoscale.Autoscaler, error) {
+	p.Info("Starting AWS autoscaler.")
 	client, err := tryGetPrivilegedKubeClient()
 	if err != nil {
-		return trace.Wrap(err)
+		return nil, trace.Wrap(err)
 	}
 	autoscaler, err := aws.New(aws.Config{
 		ClusterName: site.Domain,
 		Client:      client,
 	})
 	if err != nil {
-		return trace.Wrap(err)
+		return nil, trace.Wrap(err)
 	}
-	queueURL, err := autoscaler.GetQueueURL(ctx)
+	autoscaler.QueueURL, err = autoscaler.GetQueueURL(ctx)
 	if err != nil {
 		p.Warningf("Failed to get Autoscale Queue URL: %v. Cluster will continue without autoscaling support. Fix the problem and restart the process.", trace.DebugReport(err))
-		return nil
+		return nil, nil
 	}
+	return autoscaler, nil
+}
 
-	// receive and process events from SQS notification service
+// newGCEAutoscaler returns a new autoscaler for the managed instance group
+// configured in the instance metadata.
+// Returns nil if no instance group has been configured
+func (p *Process) newGCEAutoscaler(site ops.Site) (autoscale.Autoscaler, error) {
+	group, err := gce.GetInstanceGroup()
+	if err != nil {
+		if trace.IsNotFound(err) {
+			p.Info("No managed instance group configured, skip autoscaler start.")
+			return nil, nil
+		}
+		p.Warningf("Failed to get managed instance group: %v. Cluster will continue without autoscaling support. Fix the problem and restart the process.", trace.DebugReport(err))
+		return nil, nil
+	}
+	p.WithField("group", group).Info("Starting GCE autoscaler.")
+	client, err := tryGetPrivilegedKubeClient()
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	autoscaler, err := gce.New(gce.Config{
+		ClusterName:   site.Domain,
+		InstanceGroup: gce.InstanceGroup{Name: group},
+		Client:        client,
+	})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return autoscaler, nil
+}
+
+// startNodePoolController starts the service that expands or shrinks
+// the cluster to converge on the configured node pools
+func (p *Process) startNodePoolController(client *kubernetes.Clientset) error {
+	controller, err := nodepool.New(nodepool.Config{
+		Operator:    p.operator,
+		Executor:    p.proxy,
+		Client:      client,
+		FieldLogger: p.WithField(trace.Component, "nodepool"),
+	})
+	if err != nil {
+		return trace.Wrap(err)
+	}
 	p.RegisterClusterService(func(ctx context.Context) {
 		localCtx := context.WithValue(ctx, constants.UserContext,
-			constants.ServiceAutoscaler)
-		autoscaler.ProcessEvents(localCtx, queueURL, p.operator)
+			constants.ServiceNodePoolController)
+		controller.Run(localCtx)
 	})
-	// publish discovery information about this cluster
+	return nil
+}
+
+// startBackgroundChecker registers a cluster service that periodically
+// executes the configured checks on cluster nodes
+func (p *Process) startBackgroundChecker(client *kubernetes.Clientset) error {
+	if !p.cfg.BackgroundChecks.Enabled {
+		p.Info("Background checks are disabled.")
+		return nil
+	}
+	checker, err := background.New(background.Config{
+		Operator:    p.operator,
+		Client:      client,
+		Credentials: p.rpcCreds.clientCreds,
+		Checks:      p.cfg.BackgroundChecks.Checks,
+		Interval:    p.cfg.BackgroundChecks.Interval,
+		FieldLogger: p.WithField(trace.Component, "checks"),
+	})
+	if err != nil {
+		return trace.Wrap(err)
+	}
 	p.RegisterClusterService(func(ctx context.Context) {
 		localCtx := context.WithValue(ctx, constants.UserContext,
-			constants.ServiceAutoscaler)
-		autoscaler.PublishDiscovery(localCtx, p.operator)
+			constants.ServiceBackgroundChecker)
+		checker.Run(localCtx)
 	})
 	return nil
 }
 
+// runAPIKeyRevocation runs a service that periodically revokes API keys
+// that have not been used for longer than the configured period
+func (p *Process) runAPIKeyRevocation(ctx context.Context) {
+	p.Infof("Starting API key revocation, revoking keys unused for %v.",
+		p.cfg.APIKeys.RevokeUnusedAfter)
+	ticker := time.NewTicker(defaults.APIKeyRevocationInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			revoked, err := users.RevokeUnusedAPIKeys(p.backend, clockwork.NewRealClock(),
+				p.cfg.APIKeys.RevokeUnusedAfter)
+			if err != nil {
+				p.WithError(err).Warn("Failed to revoke unused API keys.")
+				continue
+			}
+			for _, key := range revoked {
+				events.Emit(ctx, p.operator, events.TokenDeleted, events.Fields{
+					events.FieldOwner: key.UserEmail,
+				})
+			}
+		case <-ctx.Done():
+			p.Info("Stopping API key revocation.")
+			return
+		}
+	}
+}
+
+// emitUserLocked emits an audit event for a user locked by the password policy
+func (p *Process) emitUserLocked(username string, until time.Time) {
+	events.Emit(p.context, p.operator, events.UserLocked, events.Fields{
+		events.FieldName:    username,
+		events.FieldExpires: until,
+	})
+}
+
+// runAccessRequestExpiration runs a service that periodically marks
+// the approved access requests that have expired and records their expiration
+func (p *Process) runAccessRequestExpiration(ctx context.Context) {
+	p.Info("Starting access request expiration.")
+	ticker := time.NewTicker(defaults.AccessRequestExpirationInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			expired, err := users.ExpireAccessRequests(p.backend, clockwork.NewRealClock())
+			if err != nil {
+				p.WithError(err).Warn("Failed to expire access requests.")
+				continue
+			}
+			for _, req := range expired {
+				events.Emit(ctx, p.operator, events.AccessRequestExpired, events.Fields{
+					events.FieldAccessRequestID: req.ID,
+					events.FieldOwner:           req.User,
+					events.FieldRoles:           req.GrantedRoles,
+				})
+			}
+		case <-ctx.Done():
+			p.Info("Stopping access request expiration.")
+			return
+		}
+	}
+}
+
 // runApplicationsSynchronizer runs a service that periodically exports
 // Docker images of the cluster's application images to the local Docker
 // registry.
@@ -1169,17 +1361,29 @@ func (p *Process) initService(ctx context.Context) (err error) {
 	if err != nil {
 		return trace.Wrap(err, "failed to load RPC credentials")
 	}
-	p.rpcCreds = rpcCredentials{
-		ca:     tlsArchive[pb.CA],
-		client: tlsArchive[pb.Client],
-		server: tlsArchive[pb.Server],
+	p.rpcCreds = &rpcCredentials{clientCreds: creds.client}
+	if err := p.rpcCreds.update(tlsArchive); err != nil {
+		return trace.Wrap(err)
 	}
+	p.RegisterFunc("gravity.rpccreds", func() error {
+		return trace.Wrap(rpc.WatchCredentials(p.context, rpc.WatchConfig{
+			Source:      rpc.NewPackageSource(p.packages, loc.RPCSecrets),
+			Server:      creds.server,
+			Client:      creds.client,
+			OnReload:    p.onRPCCredentialsReload,
+			FieldLogger: p.WithField(trace.Component, "rpc:creds"),
+		}))
+	})
 
-	peerStore := opsservice.NewAgentPeerStore(p.backend, p.identity, p.proxy, p.WithField("process", p.id))
+	peerStore := opsservice.NewAgentPeerStore(p.backend, p.identity, p.proxy,
+		p.cfg.Agents.ReconnectPolicy(), p.WithField("process", p.id))
 	p.agentServer, err = rpcserver.New(rpcserver.Config{
 		FieldLogger: p.WithField(trace.Component, "agent-server"),
-		Credentials: *creds,
-		PeerStore:   peerStore,
+		Credentials: rpcserver.Credentials{
+			Server: creds.server,
+			Client: creds.client,
+		},
+		PeerStore: peerStore,
 	})
 	if err != nil {
 		return trace.Wrap(err)
@@ -1228,10 +1432,19 @@ func (p *Process) initService(ctx context.Context) (err error) {
 		return trace.Wrap(err)
 	}
 
+	var rateLimiter *httplib.RateLimiter
+	if !p.cfg.RateLimits.IsEmpty() {
+		rateLimiter, err = httplib.NewRateLimiter(p.cfg.RateLimits)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+	}
+
 	p.handlers.Packages, err = webpack.NewHandler(webpack.Config{
 		Packages:      p.packages,
 		Users:         p.identity,
 		Authenticator: authenticator,
+		RateLimiter:   rateLimiter,
 	})
 	if err != nil {
 		return trace.Wrap(err)
@@ -1296,6 +1509,7 @@ func (p *Process) initService(ctx context.Context) (err error) {
 		Packages:      p.packages,
 		Charts:        charts,
 		Authenticator: authenticator,
+		RateLimiter:   rateLimiter,
 	})
 
 	proxy := opsroute.NewClientPool(opsroute.ClientPoolConfig{
@@ -1382,6 +1596,7 @@ func (p *Process) initService(ctx context.Context) (err error) {
 	} else {
 		p.operator = operator
 	}
+	p.identity.SetLockoutHook(p.emitUserLocked)
 
 	p.handlers.Operator, err = opshandler.NewWebHandler(opshandler.WebHandlerConfig{
 		Users:               p.identity,
@@ -1391,6 +1606,7 @@ func (p *Process) initService(ctx context.Context) (err error) {
 		Authenticator:       authenticator,
 		Backend:             p.backend,
 		PublicAdvertiseAddr: p.cfg.Pack.GetPublicAddr(),
+		RateLimiter:         rateLimiter,
 	})
 	if err != nil {
 		return trace.Wrap(err)
@@ -1399,6 +1615,14 @@ func (p *Process) initService(ctx context.Context) (err error) {
 	// site status checker executes status hook periodically
 	p.RegisterClusterService(p.runSiteStatusChecker)
 
+	if p.cfg.APIKeys.RevokeUnusedAfter != 0 {
+		p.RegisterClusterService(p.runAPIKeyRevocation)
+	}
+
+	p.RegisterClusterService(p.runAccessRequestExpiration)
+	p.RegisterClusterService(p.runAuditForwarder)
+	p.RegisterClusterService(p.runAuditRecordsPruner)
+
 	// a few services that are running only when gravity is started in
 	// local site mode
 	if p.inKubernetes() {
@@ -1424,6 +1648,14 @@ func (p *Process) initService(ctx context.Context) (err error) {
 			return trace.Wrap(err)
 		}
 
+		if err := p.startNodePoolController(client); err != nil {
+			return trace.Wrap(err)
+		}
+
+		if err := p.startBackgroundChecker(client); err != nil {
+			return trace.Wrap(err)
+		}
+
 		if err := p.startElection(); err != nil {
 			return trace.Wrap(err)
 		}
@@ -1571,6 +1803,7 @@ func (p *Process) ServeHealth() error {
 	healthMux := &httprouter.Router{}
 	healthMux.HandlerFunc("GET", "/readyz", p.ReportReadiness)
 	healthMux.HandlerFunc("GET", "/healthz", p.ReportHealth)
+	healthMux.Handler("GET", "/metrics", promhttp.Handler())
 	p.healthServer = &http.Server{
 		Addr:    p.cfg.HealthAddr.Addr,
 		Handler: healthMux,
@@ -1796,16 +2029,11 @@ func (p *Process) newTLSConfig(certPEM, keyPEM []byte) (*tls.Config, error) {
 		return nil, trace.Wrap(err)
 	}
 
-	grpcCert, err := tls.X509KeyPair(p.rpcCreds.server.CertPEM, p.rpcCreds.server.KeyPEM)
-	if err != nil {
-		return nil, trace.Wrap(err)
-	}
-
 	config := &tls.Config{}
 
 	config.GetCertificate = func(chi *tls.ClientHelloInfo) (*tls.Certificate, error) {
 		if chi.ServerName == pb.ServerName {
-			return &grpcCert, nil
+			return p.rpcCreds.getServerCert(), nil
 		}
 		return &httpCert, nil
 	}
@@ -2279,7 +2507,14 @@ func (p *Process) loginWithToken(tokenID string, w http.ResponseWriter, r *http.
 	}
 }
 
-func (p *Process) loadRPCCredentials() (*rpcserver.Credentials, utils.TLSArchive, error) {
+// onRPCCredentialsReload updates the RPC credentials after the credentials package has changed
+func (p *Process) onRPCCredentialsReload(archive utils.TLSArchive) {
+	if err := p.rpcCreds.update(archive); err != nil {
+		p.WithError(err).Warn("Failed to update RPC server certificate.")
+	}
+}
+
+func (p *Process) loadRPCCredentials() (*reloadableCredentials, utils.TLSArchive, error) {
 	// In case of multi-node install, a gravity-site process may need to
 	// fetch a package blob from the leader which may not be fully
 	// initialized yet so retry a few times.
@@ -2314,7 +2549,16 @@ func (p *Process) loadRPCCredentials() (*rpcserver.Credentials, utils.TLSArchive
 		return nil, nil, trace.Wrap(err)
 	}
 
-	return &rpcserver.Credentials{Client: clientCreds, Server: serverCreds}, tlsArchive, nil
+	return &reloadableCredentials{
+		client: rpc.NewReloadableCredentials(clientCreds),
+		server: rpc.NewReloadableCredentials(serverCreds),
+	}, tlsArchive, nil
+}
+
+// reloadableCredentials groups the RPC server and client transport credentials
+type reloadableCredentials struct {
+	client *rpc.ReloadableCredentials
+	server *rpc.ReloadableCredentials
 }
 
 // initSelfSignedHTTPSCert generates and self-signs a TLS key+cert pair for HTTPS connection
@@ -371,6 +371,18 @@ func (t *teleportProxyService) GetServerCount(ctx context.Context, siteName stri
 
 func (t *teleportProxyService) ExecuteCommand(ctx context.Context, siteName, nodeAddr, command string, out io.Writer) error {
 	t.Infof("ExecuteCommand(%v, %v, %v)", siteName, nodeAddr, command)
+	return trace.Wrap(t.execute(ctx, siteName, nodeAddr, command, nil, out))
+}
+
+// ExecuteScript executes the script with the shell on the specified node.
+// The script may contain secrets, so it is streamed over the session input
+// and is never logged
+func (t *teleportProxyService) ExecuteScript(ctx context.Context, siteName, nodeAddr string, script io.Reader, out io.Writer) error {
+	t.Infof("ExecuteScript(%v, %v)", siteName, nodeAddr)
+	return trace.Wrap(t.execute(ctx, siteName, nodeAddr, "bash -s", script, out))
+}
+
+func (t *teleportProxyService) execute(ctx context.Context, siteName, nodeAddr, command string, in io.Reader, out io.Writer) error {
 	hostChecker, err := t.hostCertChecker()
 	if err != nil {
 		return trace.Wrap(err)
@@ -396,6 +408,7 @@ func (t *teleportProxyService) ExecuteCommand(ctx context.Context, siteName, nod
 		SSHProxyAddr:    t.cfg.SSHProxyAddr,
 		HostPort:        targetPort,
 		Host:            targetHost,
+		Stdin:           in,
 		Stdout:          out,
 		SiteName:        siteName,
 		HostKeyCallback: hostChecker,
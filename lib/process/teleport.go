@@ -22,6 +22,7 @@ import (
 	"github.com/gravitational/gravity/lib/ops/opsservice"
 	"github.com/gravitational/gravity/lib/processconfig"
 	"github.com/gravitational/gravity/lib/storage"
+	"github.com/gravitational/gravity/lib/users"
 
 	"github.com/gravitational/teleport/lib/client"
 	"github.com/gravitational/teleport/lib/config"
@@ -68,7 +69,8 @@ func (p *Process) buildTeleportConfig(authGatewayConfig storage.AuthGateway) (*s
 		serviceConfig.AuthServers = append(serviceConfig.AuthServers, serviceConfig.Auth.SSHAddr)
 	}
 	// Teleport will be using Gravity backend implementation.
-	serviceConfig.Identity = p.identity
+	// Certificates issued by teleport carry the roles granted by access requests
+	serviceConfig.Identity = users.NewSessionIdentity(p.identity, p.backend, p.backend)
 	serviceConfig.Trust = p.identity
 	serviceConfig.Presence = p.backend
 	serviceConfig.Provisioner = p.identity
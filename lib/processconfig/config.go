@@ -23,13 +23,17 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/gravitational/gravity/lib/checks/background"
 	"github.com/gravitational/gravity/lib/constants"
 	"github.com/gravitational/gravity/lib/defaults"
 	"github.com/gravitational/gravity/lib/helm"
+	"github.com/gravitational/gravity/lib/httplib"
 	"github.com/gravitational/gravity/lib/loc"
 	"github.com/gravitational/gravity/lib/modules"
 	"github.com/gravitational/gravity/lib/ops"
+	rpcserver "github.com/gravitational/gravity/lib/rpc/server"
 	"github.com/gravitational/gravity/lib/storage"
 	"github.com/gravitational/gravity/lib/storage/keyval"
 	"github.com/gravitational/gravity/lib/systeminfo"
@@ -158,6 +162,23 @@ type Config struct {
 	// facing diagnostic logs
 	InstallLogFiles []string `yaml:"install_log_files"`
 
+	// BackgroundChecks configures checks executed periodically
+	// on cluster nodes
+	BackgroundChecks BackgroundChecksConfig `yaml:"background_checks"`
+
+	// Agents configures the behavior of operation agents
+	Agents AgentsConfig `yaml:"agents"`
+
+	// APIKeys configures the API key policy
+	APIKeys APIKeysConfig `yaml:"api_keys"`
+
+	// Audit configures the hash-chained audit log
+	Audit AuditConfig `yaml:"audit"`
+
+	// RateLimits configures the request budgets of each user, API key
+	// and cluster. Requests are not limited if unspecified
+	RateLimits httplib.RateLimiterConfig `yaml:"rate_limits"`
+
 	// ImportDir specifies optional directory with bootstrap data.
 	//
 	// An instance of gravity working in site mode will use this location
@@ -223,6 +244,14 @@ func (cfg *Config) CheckAndSetDefaults() error {
 		return trace.Wrap(err)
 	}
 
+	if err := cfg.BackgroundChecks.CheckAndSetDefaults(); err != nil {
+		return trace.Wrap(err)
+	}
+
+	if err := cfg.RateLimits.CheckAndSetDefaults(); err != nil {
+		return trace.Wrap(err)
+	}
+
 	return nil
 }
 
@@ -350,6 +379,75 @@ func (p *PackageServiceConfig) GetPublicAddr() teleutils.NetAddr {
 	return p.PublicAdvertiseAddr
 }
 
+// BackgroundChecksConfig defines configuration of checks executed
+// periodically on cluster nodes.
+type BackgroundChecksConfig struct {
+	// Enabled turns the background checks on
+	Enabled bool `yaml:"enabled"`
+	// Interval specifies how often the checks are executed
+	Interval time.Duration `yaml:"interval"`
+	// Checks lists the checks to execute.
+	// The default checks are executed if unspecified
+	Checks []string `yaml:"checks"`
+}
+
+// CheckAndSetDefaults validates background checks configuration.
+func (c *BackgroundChecksConfig) CheckAndSetDefaults() error {
+	if err := background.ValidateChecks(c.Checks); err != nil {
+		return trace.Wrap(err)
+	}
+	if c.Interval == 0 {
+		c.Interval = defaults.BackgroundChecksInterval
+	}
+	return nil
+}
+
+// AgentsConfig defines configuration of agents that take part in operations
+type AgentsConfig struct {
+	// ReconnectInitialInterval specifies the interval before the first attempt
+	// to reconnect to an agent
+	ReconnectInitialInterval time.Duration `yaml:"reconnect_initial_interval"`
+	// ReconnectMaxInterval specifies the maximum interval between attempts
+	// to reconnect to an agent
+	ReconnectMaxInterval time.Duration `yaml:"reconnect_max_interval"`
+	// ReconnectTimeout specifies how long to keep reconnecting to an agent
+	// before removing it from the operation.
+	// Agents are reconnected indefinitely if unspecified
+	ReconnectTimeout time.Duration `yaml:"reconnect_timeout"`
+}
+
+// ReconnectPolicy returns the agent reconnect policy for this configuration
+func (c AgentsConfig) ReconnectPolicy() rpcserver.ReconnectPolicy {
+	return rpcserver.ReconnectPolicy{
+		InitialInterval: c.ReconnectInitialInterval,
+		MaxInterval:     c.ReconnectMaxInterval,
+		Timeout:         c.ReconnectTimeout,
+	}
+}
+
+// APIKeysConfig defines the API key policy
+type APIKeysConfig struct {
+	// RevokeUnusedAfter specifies the period after which API keys
+	// that have not been used are revoked.
+	// Unused keys are not revoked if unspecified
+	RevokeUnusedAfter time.Duration `yaml:"revoke_unused_after"`
+}
+
+// AuditConfig defines the hash-chained audit log configuration
+type AuditConfig struct {
+	// Retention specifies how long the audit log records are kept.
+	// Defaults to defaults.AuditRecordsRetention if unspecified
+	Retention time.Duration `yaml:"retention"`
+}
+
+// GetRetention returns the audit log records retention period
+func (c AuditConfig) GetRetention() time.Duration {
+	if c.Retention == 0 {
+		return defaults.AuditRecordsRetention
+	}
+	return c.Retention
+}
+
 // Charts defines Helm charts repository configuration.
 type ChartsConfig struct {
 	// Backend is the chart repository backend.
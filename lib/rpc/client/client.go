@@ -40,6 +40,8 @@ type Client interface {
 	Command(ctx context.Context, log logrus.FieldLogger, out io.Writer, args ...string) error
 	// GravityCommand executes the gravity command specified with args remotely
 	GravityCommand(ctx context.Context, log logrus.FieldLogger, out io.Writer, args ...string) error
+	// Exec executes the command specified with req remotely and returns its result
+	Exec(ctx context.Context, req ExecRequest) (*ExecResult, error)
 	// Validate validates the node against the specified manifest and profile.
 	// Returns the list of failed probes
 	Validate(ctx context.Context, req *validationpb.ValidateRequest) ([]*agentpb.Probe, error)
@@ -55,6 +57,16 @@ type Client interface {
 	CheckBandwidth(context.Context, *validationpb.CheckBandwidthRequest) (*validationpb.CheckBandwidthResponse, error)
 	// CheckDisks executes disk performance test
 	CheckDisks(context.Context, *validationpb.CheckDisksRequest) (*validationpb.CheckDisksResponse, error)
+	// CheckPathMTU executes a path MTU discovery network test
+	CheckPathMTU(context.Context, *validationpb.CheckPathMTURequest) (*validationpb.CheckPathMTUResponse, error)
+	// CheckLatency executes a network latency test
+	CheckLatency(context.Context, *validationpb.CheckLatencyRequest) (*validationpb.CheckLatencyResponse, error)
+	// CheckPacketLoss executes a UDP packet loss network test
+	CheckPacketLoss(context.Context, *validationpb.CheckPacketLossRequest) (*validationpb.CheckPacketLossResponse, error)
+	// CheckVXLAN executes an overlay network data path test
+	CheckVXLAN(context.Context, *validationpb.CheckVXLANRequest) (*validationpb.CheckVXLANResponse, error)
+	// Transfer copies a local file to the remote node
+	Transfer(context.Context, TransferRequest) error
 	// Shutdown requests remote agent to shut down
 	Shutdown(context.Context, *pb.ShutdownRequest) error
 	// Abort requests remote agent to uninstall
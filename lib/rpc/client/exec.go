@@ -0,0 +1,176 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	pb "github.com/gravitational/gravity/lib/rpc/proto"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/gravitational/trace"
+)
+
+// ExecRequest describes a command to execute on a remote node
+type ExecRequest struct {
+	// Args specifies the command to execute
+	Args []string
+	// SelfCommand specifies whether the command should be executed
+	// with the same gravity binary that runs the agent
+	SelfCommand bool
+	// Env specifies additional environment variables for the command
+	Env map[string]string
+	// WorkDir specifies the working directory of the command
+	WorkDir string
+	// Timeout specifies the maximum duration of the command.
+	// The command is killed on the remote node once the timeout expires
+	Timeout time.Duration
+	// Stdout optionally specifies the writer for the command's standard output
+	Stdout io.Writer
+	// Stderr optionally specifies the writer for the command's standard error
+	Stderr io.Writer
+}
+
+// ExecResult describes the result of a command executed on a remote node
+type ExecResult struct {
+	// Args specifies the executed command
+	Args []string
+	// ExitCode specifies the command's exit code
+	ExitCode int
+	// TimedOut indicates that the command was killed after exceeding its timeout
+	TimedOut bool
+	// Started specifies the time the command started execution
+	Started time.Time
+	// Duration specifies the command's running time
+	Duration time.Duration
+	// UserTime specifies the user CPU time used by the command
+	UserTime time.Duration
+	// SystemTime specifies the system CPU time used by the command
+	SystemTime time.Duration
+	// MaxRSS specifies the maximum resident set size of the command in bytes
+	MaxRSS int64
+	// Error specifies the error if the command could not be executed
+	Error error
+}
+
+// Check returns an error if the command did not complete successfully.
+// A command that has exceeded its timeout results in trace.LimitExceeded error
+func (r ExecResult) Check() error {
+	command := strings.Join(r.Args, " ")
+	switch {
+	case r.TimedOut:
+		return trace.LimitExceeded("command %q timed out after %v", command, r.Duration)
+	case r.Error != nil:
+		return trace.Wrap(r.Error, "command %q failed with exit code %v", command, r.ExitCode)
+	case r.ExitCode != 0:
+		return trace.BadParameter("command %q failed with exit code %v", command, r.ExitCode)
+	}
+	return nil
+}
+
+// Exec executes the command specified with req on the remote node.
+// The command's output is streamed to the writers specified in req.
+// Returns an error only if the command could not be requested - use ExecResult.Check
+// to determine whether the command has completed successfully
+func (c *client) Exec(ctx context.Context, req ExecRequest) (*ExecResult, error) {
+	if len(req.Args) == 0 {
+		return nil, trace.BadParameter("at least one argument is required")
+	}
+	pbReq := &pb.ExecRequest{
+		Args:        req.Args,
+		SelfCommand: req.SelfCommand,
+		Env:         req.Env,
+		WorkDir:     req.WorkDir,
+	}
+	if req.Timeout != 0 {
+		pbReq.Timeout = types.DurationProto(req.Timeout)
+	}
+	stream, err := c.agent.Exec(ctx, pbReq)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	stdout, stderr := req.Stdout, req.Stderr
+	if stdout == nil {
+		stdout = ioutil.Discard
+	}
+	if stderr == nil {
+		stderr = ioutil.Discard
+	}
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return nil, trace.ConnectionProblem(nil, "stream closed before command %v completed", req.Args)
+		}
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		switch elem := resp.Element.(type) {
+		case *pb.ExecResponse_Output:
+			w := stdout
+			if elem.Output.Fd == pb.ExecOutput_STDERR {
+				w = stderr
+			}
+			if _, err := w.Write(elem.Output.Data); err != nil {
+				return nil, trace.ConvertSystemError(err)
+			}
+		case *pb.ExecResponse_Result:
+			return newExecResult(req.Args, *elem.Result)
+		default:
+			return nil, trace.BadParameter("unexpected message %+v", resp.Element)
+		}
+	}
+}
+
+func newExecResult(args []string, result pb.ExecResult) (*ExecResult, error) {
+	r := &ExecResult{
+		Args:     args,
+		ExitCode: int(result.ExitCode),
+		TimedOut: result.TimedOut,
+	}
+	var err error
+	if result.Started != nil {
+		if r.Started, err = types.TimestampFromProto(result.Started); err != nil {
+			return nil, trace.Wrap(err)
+		}
+	}
+	if result.Duration != nil {
+		if r.Duration, err = types.DurationFromProto(result.Duration); err != nil {
+			return nil, trace.Wrap(err)
+		}
+	}
+	if usage := result.Usage; usage != nil {
+		if usage.UserTime != nil {
+			if r.UserTime, err = types.DurationFromProto(usage.UserTime); err != nil {
+				return nil, trace.Wrap(err)
+			}
+		}
+		if usage.SystemTime != nil {
+			if r.SystemTime, err = types.DurationFromProto(usage.SystemTime); err != nil {
+				return nil, trace.Wrap(err)
+			}
+		}
+		r.MaxRSS = usage.MaxRss
+	}
+	if result.Error != nil {
+		r.Error = pb.DecodeError(result.Error)
+	}
+	return r, nil
+}
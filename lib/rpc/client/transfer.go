@@ -0,0 +1,150 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash/crc32"
+	"io"
+	"os"
+
+	"github.com/gravitational/gravity/lib/defaults"
+	pb "github.com/gravitational/gravity/lib/rpc/proto"
+	"github.com/gravitational/gravity/lib/utils"
+
+	"github.com/cenkalti/backoff"
+	"github.com/gravitational/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TransferRequest describes a file transfer to a remote agent
+type TransferRequest struct {
+	// Path specifies the path of the local file to transfer
+	Path string
+	// TargetPath specifies the absolute path of the file on the remote node.
+	// Defaults to Path
+	TargetPath string
+}
+
+// Transfer copies the file specified with req to the remote agent.
+// An interrupted transfer is resumed from the last chunk received by the agent
+func (c *client) Transfer(ctx context.Context, req TransferRequest) error {
+	header, err := NewTransferHeader(req)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	return trace.Wrap(utils.RetryWithInterval(ctx, utils.NewExponentialBackOff(defaults.RPCTransferTimeout), func() error {
+		err := c.transfer(ctx, req.Path, *header)
+		if err != nil && status.Code(trace.Unwrap(err)) != codes.Unavailable {
+			return &backoff.PermanentError{Err: err}
+		}
+		return trace.Wrap(err)
+	}))
+}
+
+func (c *client) transfer(ctx context.Context, path string, header pb.TransferHeader) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return trace.ConvertSystemError(err)
+	}
+	defer f.Close()
+
+	stream, err := c.agent.Transfer(ctx)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	err = stream.Send(&pb.TransferRequest{
+		Element: &pb.TransferRequest_Header{Header: &header},
+	})
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	resp, err := stream.Recv()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if _, err := f.Seek(resp.Offset, io.SeekStart); err != nil {
+		return trace.ConvertSystemError(err)
+	}
+	buf := make([]byte, defaults.RPCTransferChunkSize)
+	offset := resp.Offset
+	for offset < header.Size_ {
+		n, err := io.ReadFull(f, buf)
+		if err != nil && err != io.ErrUnexpectedEOF {
+			return trace.ConvertSystemError(err)
+		}
+		err = stream.Send(&pb.TransferRequest{
+			Element: &pb.TransferRequest_Chunk{Chunk: &pb.TransferChunk{
+				Offset: offset,
+				Data:   buf[:n],
+				Crc32:  crc32.Checksum(buf[:n], CRC32Table),
+			}},
+		})
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		offset += int64(n)
+	}
+	if err := stream.CloseSend(); err != nil {
+		return trace.Wrap(err)
+	}
+	resp, err = stream.Recv()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if !resp.Completed {
+		return trace.CompareFailed("transfer of %v incomplete: %v of %v bytes received",
+			path, resp.Offset, header.Size_)
+	}
+	return nil
+}
+
+// NewTransferHeader returns a new transfer header for the file specified with req
+func NewTransferHeader(req TransferRequest) (*pb.TransferHeader, error) {
+	f, err := os.Open(req.Path)
+	if err != nil {
+		return nil, trace.ConvertSystemError(err)
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, trace.ConvertSystemError(err)
+	}
+	if !fi.Mode().IsRegular() {
+		return nil, trace.BadParameter("%v is not a regular file", req.Path)
+	}
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return nil, trace.ConvertSystemError(err)
+	}
+	targetPath := req.TargetPath
+	if targetPath == "" {
+		targetPath = req.Path
+	}
+	return &pb.TransferHeader{
+		Path:     targetPath,
+		Size_:    fi.Size(),
+		Sha256:   hex.EncodeToString(hash.Sum(nil)),
+		FileMode: uint32(fi.Mode().Perm()),
+	}, nil
+}
+
+// CRC32Table is the table used to compute checksums of transferred file chunks
+var CRC32Table = crc32.MakeTable(crc32.Castagnoli)
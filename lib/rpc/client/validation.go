@@ -49,3 +49,39 @@ func (c *client) CheckDisks(ctx context.Context, req *validationpb.CheckDisksReq
 	}
 	return resp, nil
 }
+
+// CheckPathMTU executes a path MTU discovery network test
+func (c *client) CheckPathMTU(ctx context.Context, req *validationpb.CheckPathMTURequest) (*validationpb.CheckPathMTUResponse, error) {
+	resp, err := c.validation.CheckPathMTU(ctx, req)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return resp, nil
+}
+
+// CheckLatency executes a network latency test
+func (c *client) CheckLatency(ctx context.Context, req *validationpb.CheckLatencyRequest) (*validationpb.CheckLatencyResponse, error) {
+	resp, err := c.validation.CheckLatency(ctx, req)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return resp, nil
+}
+
+// CheckPacketLoss executes a UDP packet loss network test
+func (c *client) CheckPacketLoss(ctx context.Context, req *validationpb.CheckPacketLossRequest) (*validationpb.CheckPacketLossResponse, error) {
+	resp, err := c.validation.CheckPacketLoss(ctx, req)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return resp, nil
+}
+
+// CheckVXLAN executes an overlay network data path test
+func (c *client) CheckVXLAN(ctx context.Context, req *validationpb.CheckVXLANRequest) (*validationpb.CheckVXLANResponse, error) {
+	resp, err := c.validation.CheckVXLAN(ctx, req)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return resp, nil
+}
@@ -48,7 +48,7 @@ func (x ExecOutput_FD) String() string {
 }
 
 func (ExecOutput_FD) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_56ede974c0020f77, []int{6, 0}
+	return fileDescriptor_56ede974c0020f77, []int{10, 0}
 }
 
 type LogEntry_Level int32
@@ -79,7 +79,7 @@ func (x LogEntry_Level) String() string {
 }
 
 func (LogEntry_Level) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_56ede974c0020f77, []int{7, 0}
+	return fileDescriptor_56ede974c0020f77, []int{11, 0}
 }
 
 // ShutdownRequest describes a request to shut down a report RPC agent
@@ -299,6 +299,305 @@ func (*Message) XXX_OneofWrappers() []interface{} {
 	}
 }
 
+// ExecRequest describes a command to execute
+type ExecRequest struct {
+	// Args specify the command to run
+	Args []string `protobuf:"bytes,1,rep,name=args,proto3" json:"args,omitempty"`
+	// SelfCommand specifies whether the agent's binary
+	// should execute the command given with args
+	SelfCommand bool `protobuf:"varint,2,opt,name=self_command,json=selfCommand,proto3" json:"self_command,omitempty"`
+	// Env specifies additional environment variables for the command
+	Env map[string]string `protobuf:"bytes,3,rep,name=env,proto3" json:"env,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// WorkDir specifies the working directory of the command
+	WorkDir string `protobuf:"bytes,4,opt,name=work_dir,json=workDir,proto3" json:"work_dir,omitempty"`
+	// Timeout specifies the maximum duration of the command.
+	// The command is killed once the timeout expires
+	Timeout              *types.Duration `protobuf:"bytes,5,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *ExecRequest) Reset()         { *m = ExecRequest{} }
+func (m *ExecRequest) String() string { return proto.CompactTextString(m) }
+func (*ExecRequest) ProtoMessage()    {}
+func (*ExecRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_56ede974c0020f77, []int{3}
+}
+func (m *ExecRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExecRequest.Unmarshal(m, b)
+}
+func (m *ExecRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExecRequest.Marshal(b, m, deterministic)
+}
+func (m *ExecRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExecRequest.Merge(m, src)
+}
+func (m *ExecRequest) XXX_Size() int {
+	return xxx_messageInfo_ExecRequest.Size(m)
+}
+func (m *ExecRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExecRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExecRequest proto.InternalMessageInfo
+
+func (m *ExecRequest) GetArgs() []string {
+	if m != nil {
+		return m.Args
+	}
+	return nil
+}
+
+func (m *ExecRequest) GetSelfCommand() bool {
+	if m != nil {
+		return m.SelfCommand
+	}
+	return false
+}
+
+func (m *ExecRequest) GetEnv() map[string]string {
+	if m != nil {
+		return m.Env
+	}
+	return nil
+}
+
+func (m *ExecRequest) GetWorkDir() string {
+	if m != nil {
+		return m.WorkDir
+	}
+	return ""
+}
+
+func (m *ExecRequest) GetTimeout() *types.Duration {
+	if m != nil {
+		return m.Timeout
+	}
+	return nil
+}
+
+// ExecResponse is a union of messages streamed while executing a command
+type ExecResponse struct {
+	// Types that are valid to be assigned to Element:
+	//	*ExecResponse_Output
+	//	*ExecResponse_Result
+	Element              isExecResponse_Element `protobuf_oneof:"element"`
+	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
+	XXX_unrecognized     []byte                 `json:"-"`
+	XXX_sizecache        int32                  `json:"-"`
+}
+
+func (m *ExecResponse) Reset()         { *m = ExecResponse{} }
+func (m *ExecResponse) String() string { return proto.CompactTextString(m) }
+func (*ExecResponse) ProtoMessage()    {}
+func (*ExecResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_56ede974c0020f77, []int{4}
+}
+func (m *ExecResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExecResponse.Unmarshal(m, b)
+}
+func (m *ExecResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExecResponse.Marshal(b, m, deterministic)
+}
+func (m *ExecResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExecResponse.Merge(m, src)
+}
+func (m *ExecResponse) XXX_Size() int {
+	return xxx_messageInfo_ExecResponse.Size(m)
+}
+func (m *ExecResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExecResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExecResponse proto.InternalMessageInfo
+
+type isExecResponse_Element interface {
+	isExecResponse_Element()
+}
+
+type ExecResponse_Output struct {
+	Output *ExecOutput `protobuf:"bytes,1,opt,name=output,proto3,oneof"`
+}
+type ExecResponse_Result struct {
+	Result *ExecResult `protobuf:"bytes,2,opt,name=result,proto3,oneof"`
+}
+
+func (*ExecResponse_Output) isExecResponse_Element() {}
+func (*ExecResponse_Result) isExecResponse_Element() {}
+
+func (m *ExecResponse) GetElement() isExecResponse_Element {
+	if m != nil {
+		return m.Element
+	}
+	return nil
+}
+
+func (m *ExecResponse) GetOutput() *ExecOutput {
+	if x, ok := m.GetElement().(*ExecResponse_Output); ok {
+		return x.Output
+	}
+	return nil
+}
+
+func (m *ExecResponse) GetResult() *ExecResult {
+	if x, ok := m.GetElement().(*ExecResponse_Result); ok {
+		return x.Result
+	}
+	return nil
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*ExecResponse) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*ExecResponse_Output)(nil),
+		(*ExecResponse_Result)(nil),
+	}
+}
+
+// ExecResult describes the result of a completed command
+type ExecResult struct {
+	// ExitCode is the exit code command exited with
+	ExitCode int32 `protobuf:"varint,1,opt,name=exit_code,json=exitCode,proto3" json:"exit_code,omitempty"`
+	// TimedOut indicates that the command was killed after exceeding its timeout
+	TimedOut bool `protobuf:"varint,2,opt,name=timed_out,json=timedOut,proto3" json:"timed_out,omitempty"`
+	// Started specifies the time the command started execution
+	Started *types.Timestamp `protobuf:"bytes,3,opt,name=started,proto3" json:"started,omitempty"`
+	// Duration specifies the command's wall clock running time
+	Duration *types.Duration `protobuf:"bytes,4,opt,name=duration,proto3" json:"duration,omitempty"`
+	// Usage describes the resources used by the command
+	Usage *ResourceUsage `protobuf:"bytes,5,opt,name=usage,proto3" json:"usage,omitempty"`
+	// Error specifies the error if the command could not be executed
+	Error                *Error   `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ExecResult) Reset()         { *m = ExecResult{} }
+func (m *ExecResult) String() string { return proto.CompactTextString(m) }
+func (*ExecResult) ProtoMessage()    {}
+func (*ExecResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_56ede974c0020f77, []int{5}
+}
+func (m *ExecResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExecResult.Unmarshal(m, b)
+}
+func (m *ExecResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExecResult.Marshal(b, m, deterministic)
+}
+func (m *ExecResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExecResult.Merge(m, src)
+}
+func (m *ExecResult) XXX_Size() int {
+	return xxx_messageInfo_ExecResult.Size(m)
+}
+func (m *ExecResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExecResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExecResult proto.InternalMessageInfo
+
+func (m *ExecResult) GetExitCode() int32 {
+	if m != nil {
+		return m.ExitCode
+	}
+	return 0
+}
+
+func (m *ExecResult) GetTimedOut() bool {
+	if m != nil {
+		return m.TimedOut
+	}
+	return false
+}
+
+func (m *ExecResult) GetStarted() *types.Timestamp {
+	if m != nil {
+		return m.Started
+	}
+	return nil
+}
+
+func (m *ExecResult) GetDuration() *types.Duration {
+	if m != nil {
+		return m.Duration
+	}
+	return nil
+}
+
+func (m *ExecResult) GetUsage() *ResourceUsage {
+	if m != nil {
+		return m.Usage
+	}
+	return nil
+}
+
+func (m *ExecResult) GetError() *Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+// ResourceUsage describes resources used by a command
+type ResourceUsage struct {
+	// UserTime specifies the user CPU time
+	UserTime *types.Duration `protobuf:"bytes,1,opt,name=user_time,json=userTime,proto3" json:"user_time,omitempty"`
+	// SystemTime specifies the system CPU time
+	SystemTime *types.Duration `protobuf:"bytes,2,opt,name=system_time,json=systemTime,proto3" json:"system_time,omitempty"`
+	// MaxRSS specifies the maximum resident set size in bytes
+	MaxRss               int64    `protobuf:"varint,3,opt,name=max_rss,json=maxRss,proto3" json:"max_rss,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ResourceUsage) Reset()         { *m = ResourceUsage{} }
+func (m *ResourceUsage) String() string { return proto.CompactTextString(m) }
+func (*ResourceUsage) ProtoMessage()    {}
+func (*ResourceUsage) Descriptor() ([]byte, []int) {
+	return fileDescriptor_56ede974c0020f77, []int{6}
+}
+func (m *ResourceUsage) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ResourceUsage.Unmarshal(m, b)
+}
+func (m *ResourceUsage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ResourceUsage.Marshal(b, m, deterministic)
+}
+func (m *ResourceUsage) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ResourceUsage.Merge(m, src)
+}
+func (m *ResourceUsage) XXX_Size() int {
+	return xxx_messageInfo_ResourceUsage.Size(m)
+}
+func (m *ResourceUsage) XXX_DiscardUnknown() {
+	xxx_messageInfo_ResourceUsage.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ResourceUsage proto.InternalMessageInfo
+
+func (m *ResourceUsage) GetUserTime() *types.Duration {
+	if m != nil {
+		return m.UserTime
+	}
+	return nil
+}
+
+func (m *ResourceUsage) GetSystemTime() *types.Duration {
+	if m != nil {
+		return m.SystemTime
+	}
+	return nil
+}
+
+func (m *ResourceUsage) GetMaxRss() int64 {
+	if m != nil {
+		return m.MaxRss
+	}
+	return 0
+}
+
 // ExecStarted is sent when local command starts to execute
 type ExecStarted struct {
 	// Seq specifies the command ID. Unique only in the current call scope
@@ -316,7 +615,7 @@ func (m *ExecStarted) Reset()         { *m = ExecStarted{} }
 func (m *ExecStarted) String() string { return proto.CompactTextString(m) }
 func (*ExecStarted) ProtoMessage()    {}
 func (*ExecStarted) Descriptor() ([]byte, []int) {
-	return fileDescriptor_56ede974c0020f77, []int{3}
+	return fileDescriptor_56ede974c0020f77, []int{7}
 }
 func (m *ExecStarted) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ExecStarted.Unmarshal(m, b)
@@ -374,7 +673,7 @@ func (m *ExecCompleted) Reset()         { *m = ExecCompleted{} }
 func (m *ExecCompleted) String() string { return proto.CompactTextString(m) }
 func (*ExecCompleted) ProtoMessage()    {}
 func (*ExecCompleted) Descriptor() ([]byte, []int) {
-	return fileDescriptor_56ede974c0020f77, []int{4}
+	return fileDescriptor_56ede974c0020f77, []int{8}
 }
 func (m *ExecCompleted) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ExecCompleted.Unmarshal(m, b)
@@ -430,7 +729,7 @@ func (m *Error) Reset()         { *m = Error{} }
 func (m *Error) String() string { return proto.CompactTextString(m) }
 func (*Error) ProtoMessage()    {}
 func (*Error) Descriptor() ([]byte, []int) {
-	return fileDescriptor_56ede974c0020f77, []int{5}
+	return fileDescriptor_56ede974c0020f77, []int{9}
 }
 func (m *Error) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Error.Unmarshal(m, b)
@@ -478,7 +777,7 @@ func (m *ExecOutput) Reset()         { *m = ExecOutput{} }
 func (m *ExecOutput) String() string { return proto.CompactTextString(m) }
 func (*ExecOutput) ProtoMessage()    {}
 func (*ExecOutput) Descriptor() ([]byte, []int) {
-	return fileDescriptor_56ede974c0020f77, []int{6}
+	return fileDescriptor_56ede974c0020f77, []int{10}
 }
 func (m *ExecOutput) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ExecOutput.Unmarshal(m, b)
@@ -533,7 +832,7 @@ func (m *LogEntry) Reset()         { *m = LogEntry{} }
 func (m *LogEntry) String() string { return proto.CompactTextString(m) }
 func (*LogEntry) ProtoMessage()    {}
 func (*LogEntry) Descriptor() ([]byte, []int) {
-	return fileDescriptor_56ede974c0020f77, []int{7}
+	return fileDescriptor_56ede974c0020f77, []int{11}
 }
 func (m *LogEntry) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_LogEntry.Unmarshal(m, b)
@@ -592,7 +891,7 @@ func (m *UninstallRequest) Reset()         { *m = UninstallRequest{} }
 func (m *UninstallRequest) String() string { return proto.CompactTextString(m) }
 func (*UninstallRequest) ProtoMessage()    {}
 func (*UninstallRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_56ede974c0020f77, []int{8}
+	return fileDescriptor_56ede974c0020f77, []int{12}
 }
 func (m *UninstallRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UninstallRequest.Unmarshal(m, b)
@@ -619,7 +918,9 @@ type PeerJoinRequest struct {
 	// Config specifies the peer's runtime configuration
 	Config *RuntimeConfig `protobuf:"bytes,2,opt,name=config,proto3" json:"config,omitempty"`
 	// SystemInfo describes the peer's environment
-	SystemInfo           []byte   `protobuf:"bytes,3,opt,name=system_info,json=systemInfo,proto3" json:"system_info,omitempty"`
+	SystemInfo []byte `protobuf:"bytes,3,opt,name=system_info,json=systemInfo,proto3" json:"system_info,omitempty"`
+	// Version specifies the version of the peer's agent
+	Version              string   `protobuf:"bytes,4,opt,name=version,proto3" json:"version,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -628,7 +929,7 @@ type PeerJoinRequest struct {
 func (m *PeerJoinRequest) Reset()      { *m = PeerJoinRequest{} }
 func (*PeerJoinRequest) ProtoMessage() {}
 func (*PeerJoinRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_56ede974c0020f77, []int{9}
+	return fileDescriptor_56ede974c0020f77, []int{13}
 }
 func (m *PeerJoinRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PeerJoinRequest.Unmarshal(m, b)
@@ -669,6 +970,13 @@ func (m *PeerJoinRequest) GetSystemInfo() []byte {
 	return nil
 }
 
+func (m *PeerJoinRequest) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
 // PeerLeaveRequest is a request a peer sends when it wants to leave the cluster
 type PeerLeaveRequest struct {
 	// Addr is the peer address as host:port
@@ -685,7 +993,7 @@ type PeerLeaveRequest struct {
 func (m *PeerLeaveRequest) Reset()      { *m = PeerLeaveRequest{} }
 func (*PeerLeaveRequest) ProtoMessage() {}
 func (*PeerLeaveRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_56ede974c0020f77, []int{10}
+	return fileDescriptor_56ede974c0020f77, []int{14}
 }
 func (m *PeerLeaveRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PeerLeaveRequest.Unmarshal(m, b)
@@ -726,6 +1034,258 @@ func (m *PeerLeaveRequest) GetSystemInfo() []byte {
 	return nil
 }
 
+// TransferRequest is a union of messages a client sends during file transfer
+type TransferRequest struct {
+	// Types that are valid to be assigned to Element:
+	//	*TransferRequest_Header
+	//	*TransferRequest_Chunk
+	Element              isTransferRequest_Element `protobuf_oneof:"element"`
+	XXX_NoUnkeyedLiteral struct{}                  `json:"-"`
+	XXX_unrecognized     []byte                    `json:"-"`
+	XXX_sizecache        int32                     `json:"-"`
+}
+
+func (m *TransferRequest) Reset()         { *m = TransferRequest{} }
+func (m *TransferRequest) String() string { return proto.CompactTextString(m) }
+func (*TransferRequest) ProtoMessage()    {}
+func (*TransferRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_56ede974c0020f77, []int{15}
+}
+func (m *TransferRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TransferRequest.Unmarshal(m, b)
+}
+func (m *TransferRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TransferRequest.Marshal(b, m, deterministic)
+}
+func (m *TransferRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TransferRequest.Merge(m, src)
+}
+func (m *TransferRequest) XXX_Size() int {
+	return xxx_messageInfo_TransferRequest.Size(m)
+}
+func (m *TransferRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TransferRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TransferRequest proto.InternalMessageInfo
+
+type isTransferRequest_Element interface {
+	isTransferRequest_Element()
+}
+
+type TransferRequest_Header struct {
+	Header *TransferHeader `protobuf:"bytes,1,opt,name=header,proto3,oneof"`
+}
+type TransferRequest_Chunk struct {
+	Chunk *TransferChunk `protobuf:"bytes,2,opt,name=chunk,proto3,oneof"`
+}
+
+func (*TransferRequest_Header) isTransferRequest_Element() {}
+func (*TransferRequest_Chunk) isTransferRequest_Element()  {}
+
+func (m *TransferRequest) GetElement() isTransferRequest_Element {
+	if m != nil {
+		return m.Element
+	}
+	return nil
+}
+
+func (m *TransferRequest) GetHeader() *TransferHeader {
+	if x, ok := m.GetElement().(*TransferRequest_Header); ok {
+		return x.Header
+	}
+	return nil
+}
+
+func (m *TransferRequest) GetChunk() *TransferChunk {
+	if x, ok := m.GetElement().(*TransferRequest_Chunk); ok {
+		return x.Chunk
+	}
+	return nil
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*TransferRequest) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*TransferRequest_Header)(nil),
+		(*TransferRequest_Chunk)(nil),
+	}
+}
+
+// TransferHeader describes a file to transfer
+type TransferHeader struct {
+	// Path specifies the absolute path of the file on the receiving node
+	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// Size specifies the size of the file in bytes
+	Size_ int64 `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	// SHA256 specifies the hex-encoded SHA256 checksum of the file
+	Sha256 string `protobuf:"bytes,3,opt,name=sha256,proto3" json:"sha256,omitempty"`
+	// FileMode specifies the file permission bits
+	FileMode             uint32   `protobuf:"varint,4,opt,name=file_mode,json=fileMode,proto3" json:"file_mode,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TransferHeader) Reset()         { *m = TransferHeader{} }
+func (m *TransferHeader) String() string { return proto.CompactTextString(m) }
+func (*TransferHeader) ProtoMessage()    {}
+func (*TransferHeader) Descriptor() ([]byte, []int) {
+	return fileDescriptor_56ede974c0020f77, []int{16}
+}
+func (m *TransferHeader) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TransferHeader.Unmarshal(m, b)
+}
+func (m *TransferHeader) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TransferHeader.Marshal(b, m, deterministic)
+}
+func (m *TransferHeader) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TransferHeader.Merge(m, src)
+}
+func (m *TransferHeader) XXX_Size() int {
+	return xxx_messageInfo_TransferHeader.Size(m)
+}
+func (m *TransferHeader) XXX_DiscardUnknown() {
+	xxx_messageInfo_TransferHeader.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TransferHeader proto.InternalMessageInfo
+
+func (m *TransferHeader) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *TransferHeader) GetSize_() int64 {
+	if m != nil {
+		return m.Size_
+	}
+	return 0
+}
+
+func (m *TransferHeader) GetSha256() string {
+	if m != nil {
+		return m.Sha256
+	}
+	return ""
+}
+
+func (m *TransferHeader) GetFileMode() uint32 {
+	if m != nil {
+		return m.FileMode
+	}
+	return 0
+}
+
+// TransferChunk is a part of the file's contents
+type TransferChunk struct {
+	// Offset specifies the offset of this chunk in the file
+	Offset int64 `protobuf:"varint,1,opt,name=offset,proto3" json:"offset,omitempty"`
+	// Data specifies the chunk contents
+	Data []byte `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	// CRC32 specifies the CRC-32 (Castagnoli) checksum of data
+	Crc32                uint32   `protobuf:"varint,3,opt,name=crc32,proto3" json:"crc32,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TransferChunk) Reset()         { *m = TransferChunk{} }
+func (m *TransferChunk) String() string { return proto.CompactTextString(m) }
+func (*TransferChunk) ProtoMessage()    {}
+func (*TransferChunk) Descriptor() ([]byte, []int) {
+	return fileDescriptor_56ede974c0020f77, []int{17}
+}
+func (m *TransferChunk) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TransferChunk.Unmarshal(m, b)
+}
+func (m *TransferChunk) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TransferChunk.Marshal(b, m, deterministic)
+}
+func (m *TransferChunk) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TransferChunk.Merge(m, src)
+}
+func (m *TransferChunk) XXX_Size() int {
+	return xxx_messageInfo_TransferChunk.Size(m)
+}
+func (m *TransferChunk) XXX_DiscardUnknown() {
+	xxx_messageInfo_TransferChunk.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TransferChunk proto.InternalMessageInfo
+
+func (m *TransferChunk) GetOffset() int64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+func (m *TransferChunk) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (m *TransferChunk) GetCrc32() uint32 {
+	if m != nil {
+		return m.Crc32
+	}
+	return 0
+}
+
+// TransferResponse describes the state of the file transfer on the receiving agent
+type TransferResponse struct {
+	// Offset specifies the number of bytes the agent has already received
+	Offset int64 `protobuf:"varint,1,opt,name=offset,proto3" json:"offset,omitempty"`
+	// Completed indicates that the file has been received and verified
+	Completed            bool     `protobuf:"varint,2,opt,name=completed,proto3" json:"completed,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TransferResponse) Reset()         { *m = TransferResponse{} }
+func (m *TransferResponse) String() string { return proto.CompactTextString(m) }
+func (*TransferResponse) ProtoMessage()    {}
+func (*TransferResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_56ede974c0020f77, []int{18}
+}
+func (m *TransferResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TransferResponse.Unmarshal(m, b)
+}
+func (m *TransferResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TransferResponse.Marshal(b, m, deterministic)
+}
+func (m *TransferResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TransferResponse.Merge(m, src)
+}
+func (m *TransferResponse) XXX_Size() int {
+	return xxx_messageInfo_TransferResponse.Size(m)
+}
+func (m *TransferResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_TransferResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TransferResponse proto.InternalMessageInfo
+
+func (m *TransferResponse) GetOffset() int64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+func (m *TransferResponse) GetCompleted() bool {
+	if m != nil {
+		return m.Completed
+	}
+	return false
+}
+
 func init() {
 	proto.RegisterEnum("proto.ExecOutput_FD", ExecOutput_FD_name, ExecOutput_FD_value)
 	proto.RegisterEnum("proto.LogEntry_Level", LogEntry_Level_name, LogEntry_Level_value)
@@ -733,6 +1293,11 @@ func init() {
 	proto.RegisterType((*CommandArgs)(nil), "proto.CommandArgs")
 	proto.RegisterMapType((map[string]string)(nil), "proto.CommandArgs.EnvEntry")
 	proto.RegisterType((*Message)(nil), "proto.Message")
+	proto.RegisterType((*ExecRequest)(nil), "proto.ExecRequest")
+	proto.RegisterMapType((map[string]string)(nil), "proto.ExecRequest.EnvEntry")
+	proto.RegisterType((*ExecResponse)(nil), "proto.ExecResponse")
+	proto.RegisterType((*ExecResult)(nil), "proto.ExecResult")
+	proto.RegisterType((*ResourceUsage)(nil), "proto.ResourceUsage")
 	proto.RegisterType((*ExecStarted)(nil), "proto.ExecStarted")
 	proto.RegisterType((*ExecCompleted)(nil), "proto.ExecCompleted")
 	proto.RegisterType((*Error)(nil), "proto.Error")
@@ -742,62 +1307,95 @@ func init() {
 	proto.RegisterType((*UninstallRequest)(nil), "proto.UninstallRequest")
 	proto.RegisterType((*PeerJoinRequest)(nil), "proto.PeerJoinRequest")
 	proto.RegisterType((*PeerLeaveRequest)(nil), "proto.PeerLeaveRequest")
+	proto.RegisterType((*TransferRequest)(nil), "proto.TransferRequest")
+	proto.RegisterType((*TransferHeader)(nil), "proto.TransferHeader")
+	proto.RegisterType((*TransferChunk)(nil), "proto.TransferChunk")
+	proto.RegisterType((*TransferResponse)(nil), "proto.TransferResponse")
 }
 
 func init() { proto.RegisterFile("agent.proto", fileDescriptor_56ede974c0020f77) }
 
 var fileDescriptor_56ede974c0020f77 = []byte{
-	// 800 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x54, 0xcd, 0x6e, 0xdc, 0x54,
-	0x14, 0x1e, 0xdb, 0xe3, 0xc9, 0xf8, 0x38, 0x4d, 0xcc, 0x55, 0x09, 0xd6, 0xa4, 0x52, 0x83, 0xd5,
-	0x45, 0x24, 0xc0, 0x81, 0x14, 0x51, 0x1a, 0xc1, 0x22, 0x24, 0x13, 0x0d, 0x28, 0xa8, 0xe8, 0xa6,
-	0x15, 0xcb, 0x91, 0xc7, 0x3e, 0x36, 0x16, 0xb6, 0x6f, 0x6a, 0x5f, 0x0f, 0x09, 0xbc, 0x02, 0x0f,
-	0xd0, 0x25, 0x3b, 0x16, 0xbc, 0x24, 0xba, 0x3f, 0x9e, 0x71, 0xd3, 0x0c, 0x12, 0x9b, 0xae, 0xe6,
-	0xfc, 0x7d, 0xe7, 0x7c, 0x3e, 0xdf, 0xdc, 0x03, 0x6e, 0x94, 0x61, 0xc5, 0xc3, 0xeb, 0x9a, 0x71,
-	0x46, 0x6c, 0xf9, 0x33, 0xd9, 0xcf, 0x18, 0xcb, 0x0a, 0x3c, 0x92, 0xde, 0xa2, 0x4d, 0x8f, 0xb0,
-	0xbc, 0xe6, 0xb7, 0xaa, 0x66, 0xb2, 0x9b, 0xe4, 0x4d, 0xcc, 0x96, 0x58, 0x77, 0x01, 0xc8, 0x58,
-	0xc6, 0x94, 0x1d, 0x1c, 0xc1, 0xee, 0xd5, 0x2f, 0x2d, 0x4f, 0xd8, 0x6f, 0x15, 0xc5, 0xd7, 0x2d,
-	0x36, 0x9c, 0x3c, 0x02, 0x27, 0x66, 0xe5, 0x75, 0x81, 0x1c, 0x13, 0xdf, 0x38, 0x30, 0x0e, 0xc7,
-	0x74, 0x1d, 0x08, 0xfe, 0x31, 0xc0, 0x3d, 0x63, 0x65, 0x19, 0x55, 0xc9, 0x69, 0x9d, 0x35, 0x84,
-	0xc0, 0x30, 0xaa, 0xb3, 0xc6, 0x37, 0x0e, 0xac, 0x43, 0x87, 0x4a, 0x9b, 0x7c, 0x0c, 0xdb, 0x0d,
-	0x16, 0xe9, 0x3c, 0x56, 0x75, 0xbe, 0x29, 0x9b, 0xb8, 0x22, 0xa6, 0xa1, 0xe4, 0x33, 0xb0, 0xb0,
-	0x5a, 0xfa, 0xd6, 0x81, 0x75, 0xe8, 0x1e, 0xef, 0x2b, 0x32, 0x61, 0xaf, 0x6f, 0x38, 0xad, 0x96,
-	0xd3, 0x8a, 0xd7, 0xb7, 0x54, 0xd4, 0x4d, 0xbe, 0x82, 0x71, 0x17, 0x20, 0x1e, 0x58, 0xbf, 0xe2,
-	0xad, 0x64, 0xe6, 0x50, 0x61, 0x92, 0x87, 0x60, 0x2f, 0xa3, 0xa2, 0x45, 0x39, 0xc8, 0xa1, 0xca,
-	0x39, 0x31, 0xbf, 0x36, 0x82, 0x37, 0x26, 0x6c, 0xfd, 0x88, 0x4d, 0x13, 0x65, 0x48, 0x9e, 0xc1,
-	0x36, 0xde, 0x60, 0x3c, 0x6f, 0x78, 0x54, 0x77, 0x9f, 0xe6, 0x1e, 0x13, 0x3d, 0x7b, 0x7a, 0x83,
-	0xf1, 0x95, 0xca, 0xcc, 0x06, 0xd4, 0xc5, 0xb5, 0x4b, 0xbe, 0x85, 0x1d, 0x09, 0x5c, 0x6f, 0xc5,
-	0x94, 0xd0, 0x87, 0x3d, 0xe8, 0x59, 0x97, 0x9b, 0x0d, 0xe8, 0x03, 0xec, 0x07, 0xc8, 0x97, 0x20,
-	0xbb, 0xcd, 0x59, 0xcb, 0xaf, 0x5b, 0xee, 0x5b, 0x12, 0xfb, 0x41, 0x0f, 0xfb, 0x42, 0x26, 0x66,
-	0x03, 0x0a, 0xb8, 0xf2, 0x48, 0x08, 0x4e, 0xc1, 0xb2, 0x39, 0x8a, 0x4f, 0xf6, 0x87, 0x12, 0xb3,
-	0xab, 0x31, 0x97, 0x2c, 0x93, 0x9b, 0x98, 0x0d, 0xe8, 0xb8, 0xd0, 0x36, 0x79, 0x02, 0x36, 0xd6,
-	0x35, 0xab, 0x7d, 0x5b, 0xd6, 0x6e, 0x77, 0xfd, 0x45, 0x6c, 0x36, 0xa0, 0x2a, 0xf9, 0x9d, 0x03,
-	0x5b, 0x58, 0x60, 0x89, 0x15, 0x0f, 0xa6, 0xe0, 0xf6, 0xbe, 0x59, 0x6c, 0xb5, 0xc1, 0xd7, 0x72,
-	0x29, 0x36, 0x15, 0xe6, 0x4a, 0x59, 0xb3, 0xa7, 0xac, 0xb7, 0x96, 0xcd, 0x91, 0xca, 0x04, 0x0b,
-	0x78, 0xf0, 0xd6, 0xf7, 0xdf, 0xd3, 0x68, 0x1f, 0x1c, 0xbc, 0xc9, 0xf9, 0x3c, 0x66, 0x89, 0x92,
-	0xc8, 0xa6, 0x63, 0x11, 0x38, 0x63, 0x09, 0x92, 0xa0, 0xe3, 0x6d, 0xbd, 0xcb, 0x5b, 0xb3, 0x0e,
-	0x9e, 0x83, 0x2d, 0x7d, 0xe2, 0xc3, 0x56, 0xa9, 0xd4, 0xd4, 0xf2, 0x77, 0x2e, 0xd9, 0x83, 0x11,
-	0xaf, 0xa3, 0x18, 0x3b, 0xba, 0xda, 0x0b, 0x96, 0x00, 0xeb, 0x15, 0xdf, 0xc3, 0xed, 0x09, 0x98,
-	0xa9, 0xd2, 0x73, 0xe7, 0x2d, 0x3d, 0x15, 0x20, 0xbc, 0x38, 0xa7, 0x66, 0x9a, 0x88, 0x55, 0x24,
-	0x11, 0x8f, 0x24, 0xc7, 0x6d, 0x2a, 0xed, 0xe0, 0x11, 0x98, 0x17, 0xe7, 0x04, 0x60, 0x74, 0xf5,
-	0xf2, 0xfc, 0xc5, 0xab, 0x97, 0xde, 0x40, 0xdb, 0x53, 0x4a, 0x3d, 0x23, 0xf8, 0xd3, 0x84, 0x71,
-	0xa7, 0xd3, 0x7f, 0xd0, 0x7e, 0x0a, 0xa3, 0x34, 0xc7, 0x22, 0x51, 0xb4, 0xd7, 0x2f, 0xa1, 0x83,
-	0x86, 0x17, 0x32, 0xab, 0x5e, 0x82, 0x2e, 0x25, 0x9f, 0x80, 0x5d, 0xe0, 0x12, 0x0b, 0x49, 0x67,
-	0xe7, 0xf8, 0xc3, 0xbb, 0x98, 0x4b, 0x91, 0xa4, 0xaa, 0xa6, 0xb7, 0x98, 0x61, 0x7f, 0x31, 0x93,
-	0xe7, 0xe0, 0xf6, 0x7a, 0xff, 0xaf, 0x47, 0xf5, 0x05, 0xd8, 0x72, 0x04, 0x71, 0xc0, 0x3e, 0xc7,
-	0x45, 0x9b, 0x79, 0x03, 0x32, 0x86, 0xe1, 0xf7, 0x55, 0xca, 0x3c, 0x43, 0x58, 0x3f, 0x47, 0x75,
-	0xe5, 0x99, 0x22, 0x2d, 0x65, 0xf3, 0xac, 0x80, 0x80, 0xf7, 0xaa, 0xca, 0xab, 0x86, 0x47, 0x45,
-	0xa1, 0xef, 0x4c, 0xf0, 0x3b, 0xec, 0xfe, 0x84, 0x58, 0xff, 0xc0, 0xf2, 0xd5, 0xe9, 0x11, 0x7f,
-	0xb9, 0x24, 0xa9, 0x35, 0x0d, 0x69, 0x93, 0x4f, 0x61, 0x14, 0xb3, 0x2a, 0xcd, 0xb3, 0x3b, 0xaf,
-	0x8e, 0xb6, 0x15, 0xcf, 0x4b, 0x3c, 0x93, 0x39, 0xaa, 0x6b, 0xc8, 0x63, 0x70, 0x9b, 0xdb, 0x86,
-	0x63, 0x39, 0xcf, 0xab, 0x94, 0x69, 0xc1, 0x40, 0x85, 0x04, 0xc1, 0x93, 0xe1, 0x9b, 0xbf, 0x1e,
-	0x0f, 0x82, 0x3f, 0xc0, 0x13, 0xb3, 0x2f, 0x31, 0x5a, 0xe2, 0xfb, 0x1e, 0x7e, 0xfc, 0xb7, 0x09,
-	0xf6, 0xa9, 0x38, 0xe2, 0xe4, 0x04, 0xc6, 0xdd, 0xf5, 0x25, 0x7b, 0xba, 0xf5, 0x9d, 0x73, 0x3c,
-	0xd9, 0x0b, 0xd5, 0x71, 0x0f, 0xbb, 0xe3, 0x1e, 0x4e, 0xc5, 0x71, 0x27, 0xcf, 0xc0, 0x3e, 0x5d,
-	0xb0, 0x9a, 0x93, 0x0d, 0x05, 0x1b, 0x81, 0x47, 0xb0, 0xd5, 0x5d, 0x61, 0xf2, 0xee, 0xe1, 0x9d,
-	0xec, 0xe8, 0x98, 0x3e, 0x9b, 0x9f, 0x1b, 0x82, 0x65, 0x27, 0xd4, 0x8a, 0xe5, 0x1d, 0xe5, 0x36,
-	0x0e, 0xfb, 0x06, 0x9c, 0xd5, 0xa2, 0xc9, 0x47, 0x3d, 0x70, 0x7f, 0xf5, 0x9b, 0xd0, 0x8b, 0x91,
-	0xf4, 0x9f, 0xfe, 0x1b, 0x00, 0x00, 0xff, 0xff, 0xaf, 0xe6, 0x08, 0x92, 0xf4, 0x06, 0x00, 0x00,
+	// 1251 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x56, 0x4b, 0x6f, 0xdb, 0xc6,
+	0x16, 0x16, 0x29, 0x51, 0x12, 0x8f, 0xfc, 0xd0, 0x9d, 0x9b, 0x9b, 0x28, 0x4a, 0x70, 0x93, 0x4b,
+	0x64, 0x61, 0xdc, 0xa4, 0x72, 0x62, 0xe7, 0xd1, 0x18, 0x2d, 0x8a, 0xd4, 0x76, 0xa0, 0x16, 0x09,
+	0xd2, 0x4e, 0x1c, 0x74, 0x29, 0xd0, 0xe2, 0x91, 0x4c, 0x84, 0xe4, 0x28, 0x33, 0xa4, 0x62, 0xb7,
+	0xcb, 0x6e, 0xbb, 0xec, 0x22, 0x40, 0xbb, 0xe8, 0xbe, 0x3f, 0xb1, 0x9b, 0x62, 0x5e, 0x12, 0x25,
+	0x45, 0x0d, 0x5a, 0x14, 0x5d, 0xf1, 0x3c, 0xbe, 0x33, 0x73, 0xde, 0x1c, 0x68, 0x85, 0x63, 0xcc,
+	0xf2, 0xde, 0x84, 0xb3, 0x9c, 0x11, 0x4f, 0x7d, 0xba, 0xd7, 0xc6, 0x8c, 0x8d, 0x13, 0xdc, 0x55,
+	0xdc, 0x69, 0x31, 0xda, 0xc5, 0x74, 0x92, 0x5f, 0x68, 0x4c, 0xf7, 0xbf, 0xcb, 0xca, 0xa8, 0xe0,
+	0x61, 0x1e, 0xb3, 0xcc, 0xe8, 0x6f, 0x2c, 0xeb, 0xf3, 0x38, 0x45, 0x91, 0x87, 0xe9, 0xc4, 0x00,
+	0xb6, 0xa3, 0x58, 0x0c, 0xd9, 0x14, 0xb9, 0x3d, 0x11, 0xc6, 0x6c, 0xcc, 0x34, 0x1d, 0xec, 0xc2,
+	0xf6, 0xcb, 0xb3, 0x22, 0x8f, 0xd8, 0xdb, 0x8c, 0xe2, 0x9b, 0x02, 0x45, 0x4e, 0xae, 0x83, 0x3f,
+	0x64, 0xe9, 0x24, 0xc1, 0x1c, 0xa3, 0x8e, 0x73, 0xd3, 0xd9, 0x69, 0xd2, 0xb9, 0x20, 0xf8, 0xd5,
+	0x81, 0xd6, 0x21, 0x4b, 0xd3, 0x30, 0x8b, 0x9e, 0xf0, 0xb1, 0x20, 0x04, 0x6a, 0x21, 0x1f, 0x8b,
+	0x8e, 0x73, 0xb3, 0xba, 0xe3, 0x53, 0x45, 0x93, 0xff, 0xc1, 0x86, 0xc0, 0x64, 0x34, 0x18, 0x6a,
+	0x5c, 0xc7, 0x55, 0x87, 0xb4, 0xa4, 0xcc, 0x98, 0x92, 0x8f, 0xa0, 0x8a, 0xd9, 0xb4, 0x53, 0xbd,
+	0x59, 0xdd, 0x69, 0xed, 0x5d, 0xd3, 0xce, 0xf4, 0x4a, 0xe7, 0xf6, 0x8e, 0xb3, 0xe9, 0x71, 0x96,
+	0xf3, 0x0b, 0x2a, 0x71, 0xdd, 0x87, 0xd0, 0xb4, 0x02, 0xd2, 0x86, 0xea, 0x6b, 0xbc, 0x50, 0x9e,
+	0xf9, 0x54, 0x92, 0xe4, 0x12, 0x78, 0xd3, 0x30, 0x29, 0x50, 0x5d, 0xe4, 0x53, 0xcd, 0x1c, 0xb8,
+	0x1f, 0x3b, 0xc1, 0x3b, 0x17, 0x1a, 0xcf, 0x51, 0x88, 0x70, 0x8c, 0xe4, 0x11, 0x6c, 0xe0, 0x39,
+	0x0e, 0x07, 0x22, 0x0f, 0xb9, 0x0d, 0xad, 0xb5, 0x47, 0xcc, 0xdd, 0xc7, 0xe7, 0x38, 0x7c, 0xa9,
+	0x35, 0xfd, 0x0a, 0x6d, 0xe1, 0x9c, 0x25, 0x9f, 0xc2, 0x96, 0x32, 0x9c, 0x67, 0xc5, 0x55, 0xa6,
+	0x97, 0x4a, 0xa6, 0x87, 0x56, 0xd7, 0xaf, 0xd0, 0x4d, 0x2c, 0x0b, 0xc8, 0x7d, 0x50, 0xa7, 0x0d,
+	0x58, 0x91, 0x4f, 0x8a, 0xbc, 0x53, 0x55, 0xb6, 0xff, 0x2a, 0xd9, 0xbe, 0x50, 0x8a, 0x7e, 0x85,
+	0x02, 0xce, 0x38, 0xd2, 0x03, 0x3f, 0x61, 0xe3, 0x01, 0xca, 0x90, 0x3b, 0x35, 0x65, 0xb3, 0x6d,
+	0x6c, 0x9e, 0xb1, 0xb1, 0xca, 0x44, 0xbf, 0x42, 0x9b, 0x89, 0xa1, 0xc9, 0x2d, 0xf0, 0x90, 0x73,
+	0xc6, 0x3b, 0x9e, 0xc2, 0x6e, 0xd8, 0xf3, 0xa5, 0xac, 0x5f, 0xa1, 0x5a, 0xf9, 0xb9, 0x0f, 0x0d,
+	0x4c, 0x30, 0xc5, 0x2c, 0x0f, 0x7e, 0x73, 0xa0, 0x25, 0x6f, 0xb7, 0x65, 0xff, 0x3b, 0x0b, 0x59,
+	0x3a, 0x77, 0xb1, 0x90, 0xe4, 0x2a, 0x34, 0xdf, 0x32, 0xfe, 0x7a, 0x10, 0xc5, 0x5c, 0x45, 0xe5,
+	0xd3, 0x86, 0xe4, 0x8f, 0x62, 0x4e, 0xf6, 0xa1, 0x21, 0x5b, 0x97, 0x15, 0xb9, 0x89, 0xe1, 0x6a,
+	0x4f, 0xb7, 0x76, 0xcf, 0xb6, 0x76, 0xef, 0xc8, 0xb4, 0x3e, 0xb5, 0xc8, 0xbf, 0xdc, 0x18, 0x02,
+	0x36, 0xb4, 0x93, 0x62, 0xc2, 0x32, 0x81, 0xe4, 0x36, 0xd4, 0x4d, 0x7d, 0x9c, 0xf5, 0xf5, 0x31,
+	0x10, 0x09, 0xe6, 0x28, 0x8a, 0x24, 0xef, 0xb8, 0x2b, 0x60, 0xaa, 0x14, 0x12, 0xac, 0x21, 0xe5,
+	0x94, 0x7f, 0xef, 0x02, 0xcc, 0x31, 0xe4, 0x1a, 0xf8, 0x78, 0x1e, 0xe7, 0x83, 0x21, 0x8b, 0x50,
+	0x5d, 0xeb, 0xd1, 0xa6, 0x14, 0x1c, 0xb2, 0x08, 0xa5, 0x52, 0xc6, 0x18, 0xc9, 0xb6, 0x31, 0x79,
+	0x6f, 0x2a, 0xc1, 0x8b, 0x22, 0x27, 0xf7, 0xa1, 0x61, 0xbb, 0x58, 0xb7, 0x53, 0x77, 0x25, 0x55,
+	0x27, 0x76, 0x0b, 0x50, 0x0b, 0x25, 0x0f, 0xa0, 0x69, 0x77, 0x47, 0xa7, 0xf6, 0xa1, 0x0c, 0xcf,
+	0xa0, 0xe4, 0xff, 0xe0, 0x15, 0x72, 0x80, 0x3a, 0xde, 0x42, 0xd7, 0x53, 0x14, 0xac, 0xe0, 0x43,
+	0x7c, 0x25, 0x75, 0x54, 0x43, 0x48, 0x60, 0xbb, 0xb0, 0xbe, 0xda, 0x85, 0xa6, 0x07, 0x83, 0x9f,
+	0x1d, 0xd8, 0x5c, 0x30, 0x26, 0x0f, 0xc1, 0x2f, 0x04, 0xf2, 0x81, 0x8c, 0xaf, 0xe3, 0x7c, 0xd0,
+	0x33, 0x89, 0x95, 0xe1, 0x91, 0x03, 0x68, 0x89, 0x0b, 0x91, 0x63, 0xaa, 0x2d, 0xdd, 0x0f, 0x59,
+	0x82, 0x46, 0x2b, 0xdb, 0x2b, 0xd0, 0x48, 0xc3, 0xf3, 0x01, 0x17, 0x42, 0xa5, 0xb0, 0x4a, 0xeb,
+	0x69, 0x78, 0x4e, 0x85, 0x08, 0x8e, 0xf5, 0x58, 0xd8, 0xe1, 0x6f, 0x43, 0x55, 0xe0, 0x1b, 0x53,
+	0x1e, 0x49, 0xce, 0x06, 0xc5, 0x2d, 0x0d, 0x4a, 0x7b, 0x3e, 0x05, 0xbe, 0x6a, 0xf4, 0xe0, 0x14,
+	0x36, 0x17, 0xf6, 0xc2, 0x7b, 0x0e, 0x5a, 0xa8, 0xbf, 0xbb, 0x54, 0xff, 0x59, 0x26, 0xab, 0xeb,
+	0x33, 0xf9, 0x18, 0x3c, 0xc5, 0x93, 0x0e, 0x34, 0x52, 0xbd, 0xe5, 0x4c, 0xf7, 0x5b, 0x96, 0x5c,
+	0x86, 0x7a, 0xce, 0xc3, 0x21, 0x5a, 0x77, 0x0d, 0x17, 0x4c, 0x01, 0xe6, 0xad, 0xfd, 0x1e, 0xdf,
+	0x6e, 0x81, 0x3b, 0xd2, 0xf3, 0xbe, 0xb5, 0xb0, 0xe7, 0xb4, 0x41, 0xef, 0xe9, 0x11, 0x75, 0x47,
+	0x91, 0x4c, 0x45, 0x14, 0xe6, 0xa1, 0xf2, 0x71, 0x83, 0x2a, 0x3a, 0xb8, 0x0e, 0xee, 0xd3, 0x23,
+	0x02, 0x50, 0x7f, 0x79, 0x72, 0xf4, 0xe2, 0xd5, 0x49, 0xbb, 0x62, 0xe8, 0x63, 0x4a, 0xdb, 0x4e,
+	0xf0, 0x83, 0x0b, 0x4d, 0xbb, 0xbf, 0xfe, 0xc0, 0xed, 0x7d, 0xa8, 0x8f, 0x62, 0x4c, 0x22, 0xed,
+	0xf6, 0x7c, 0xb1, 0x58, 0xd3, 0xde, 0x53, 0xa5, 0x55, 0x34, 0x35, 0x50, 0x72, 0x1b, 0xbc, 0x04,
+	0xa7, 0x98, 0x28, 0x77, 0xb6, 0xf6, 0xfe, 0xb3, 0x6c, 0xf3, 0x4c, 0x2a, 0xa9, 0xc6, 0x94, 0x12,
+	0x53, 0x2b, 0x27, 0xa6, 0xfb, 0x18, 0x5a, 0xa5, 0xb3, 0xff, 0xd4, 0x4e, 0xb9, 0x07, 0x9e, 0xba,
+	0x82, 0xf8, 0xe0, 0x1d, 0xe1, 0x69, 0x31, 0x6e, 0x57, 0x48, 0x13, 0x6a, 0x5f, 0x64, 0x23, 0xd6,
+	0x76, 0x24, 0xf5, 0x4d, 0xc8, 0xb3, 0xb6, 0x4b, 0x7c, 0x53, 0xb6, 0x76, 0x35, 0x20, 0xd0, 0x7e,
+	0x95, 0xc5, 0x99, 0xc8, 0xc3, 0x24, 0x31, 0x0b, 0x33, 0xf8, 0xd1, 0x81, 0xed, 0xaf, 0x10, 0xf9,
+	0x97, 0x2c, 0xce, 0xca, 0xcb, 0x39, 0x8a, 0xb8, 0xf1, 0x43, 0xd1, 0xe4, 0x0e, 0xd4, 0x87, 0x2c,
+	0x1b, 0xc5, 0xe3, 0xa5, 0xdf, 0x11, 0x2d, 0x32, 0x39, 0x0e, 0x87, 0x4a, 0x47, 0x0d, 0x86, 0xdc,
+	0x98, 0xcd, 0x4a, 0x9c, 0x8d, 0x98, 0xa9, 0x98, 0x19, 0x08, 0xe9, 0xa1, 0x2c, 0xc6, 0x14, 0xb9,
+	0xb0, 0xcb, 0xc1, 0xa7, 0x96, 0x3d, 0xa8, 0xbd, 0xfb, 0xe5, 0x46, 0x25, 0xf8, 0x0e, 0xda, 0xd2,
+	0xab, 0x67, 0x18, 0x4e, 0xf1, 0x9f, 0x73, 0x6b, 0x76, 0xf9, 0xf6, 0x09, 0x0f, 0x33, 0x31, 0x42,
+	0x6e, 0xef, 0xde, 0x85, 0xfa, 0x19, 0x86, 0x11, 0x72, 0xb3, 0x31, 0x6c, 0xb9, 0x2d, 0xae, 0xaf,
+	0x94, 0x72, 0x11, 0x6b, 0x18, 0xb9, 0x03, 0xde, 0xf0, 0xac, 0xc8, 0x5e, 0x2f, 0xf9, 0x65, 0xf1,
+	0x87, 0x52, 0x27, 0xff, 0x94, 0x0a, 0x54, 0x5e, 0xdb, 0x29, 0x6c, 0x2d, 0x1e, 0x2a, 0xe3, 0x9e,
+	0x84, 0xf9, 0x99, 0x8d, 0x5b, 0xd2, 0x52, 0x26, 0xe2, 0x6f, 0x75, 0x5b, 0x54, 0xa9, 0xa2, 0x65,
+	0x93, 0x89, 0xb3, 0x70, 0xef, 0xc1, 0x43, 0x15, 0x98, 0x4f, 0x0d, 0x27, 0x27, 0x7f, 0x14, 0x27,
+	0x38, 0x48, 0xe5, 0xe4, 0xcb, 0x6c, 0x6f, 0xd2, 0xa6, 0x14, 0x3c, 0x67, 0x11, 0x06, 0x5f, 0xc3,
+	0xe6, 0x82, 0x4f, 0xf2, 0x14, 0x36, 0x1a, 0x09, 0xd4, 0xff, 0xa6, 0x2a, 0x35, 0xdc, 0x6c, 0xfa,
+	0xdc, 0xf9, 0xf4, 0xc9, 0xee, 0x1c, 0xf2, 0xe1, 0xfe, 0x9e, 0xba, 0x70, 0x93, 0x6a, 0x26, 0xe8,
+	0x43, 0x7b, 0x9e, 0x3e, 0xf3, 0xc7, 0x5b, 0x77, 0xea, 0xc2, 0xf3, 0xcf, 0x5d, 0x7a, 0xfe, 0xed,
+	0xfd, 0x54, 0x05, 0xef, 0x89, 0x7c, 0xc1, 0x92, 0x03, 0x68, 0xda, 0x97, 0x23, 0xb9, 0x6c, 0x72,
+	0xb9, 0xf4, 0x94, 0xec, 0x5e, 0x5e, 0xd9, 0xc5, 0xc7, 0xf2, 0x65, 0x4b, 0x1e, 0x81, 0xf7, 0xe4,
+	0x94, 0xf1, 0x9c, 0xac, 0x01, 0xac, 0x35, 0xdc, 0x85, 0x86, 0x7d, 0x78, 0x90, 0xd5, 0x47, 0x63,
+	0x77, 0xcb, 0xc8, 0xcc, 0x93, 0xef, 0xae, 0x43, 0xee, 0x41, 0x4d, 0xae, 0x2d, 0x42, 0x56, 0x5f,
+	0x26, 0xdd, 0x7f, 0x2f, 0xc8, 0x74, 0x5a, 0xee, 0x3a, 0x32, 0x30, 0x3b, 0x7e, 0xb3, 0xc0, 0x96,
+	0xe6, 0x71, 0xad, 0x7f, 0x9f, 0x80, 0x3f, 0x1b, 0x12, 0x72, 0xa5, 0x64, 0x5c, 0x1e, 0x9b, 0xb5,
+	0xd6, 0x9f, 0x41, 0xd3, 0x96, 0x69, 0x76, 0xf3, 0x52, 0xdb, 0x77, 0xaf, 0xac, 0xc8, 0xb5, 0xe3,
+	0x3b, 0xce, 0x5d, 0xe7, 0xb4, 0xae, 0x74, 0xfb, 0xbf, 0x0f, 0x00, 0xe3, 0x90, 0xb6, 0xb1, 0x65,
+	0x0c, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -819,12 +1417,23 @@ type AgentClient interface {
 	// Command executes a command specified with CommandArgs.
 	// The output of the command is streamed as a result.
 	Command(ctx context.Context, in *CommandArgs, opts ...grpc.CallOption) (Agent_CommandClient, error)
+	// Exec executes a command specified with ExecRequest.
+	// The output of the command is streamed as a result followed
+	// by the command's ExecResult
+	Exec(ctx context.Context, in *ExecRequest, opts ...grpc.CallOption) (Agent_ExecClient, error)
 	// PeerJoin receives a connection from a peer.
 	// The peer configuration allows this agent to establish a reverse
 	// connection to the remote peer to execute remote commands
 	PeerJoin(ctx context.Context, in *PeerJoinRequest, opts ...grpc.CallOption) (*types.Empty, error)
 	// PeerLeave receives a "leave" request from a peer and initiates its shutdown
 	PeerLeave(ctx context.Context, in *PeerLeaveRequest, opts ...grpc.CallOption) (*types.Empty, error)
+	// Transfer copies a file to this agent.
+	// The client starts with a header describing the file and the agent replies
+	// with the offset to resume the transfer from. The client then streams
+	// the file contents in chunks starting at this offset.
+	// Once the file has been received and its checksum verified, the agent
+	// replies with the final status
+	Transfer(ctx context.Context, opts ...grpc.CallOption) (Agent_TransferClient, error)
 }
 
 type agentClient struct {
@@ -885,6 +1494,38 @@ func (x *agentCommandClient) Recv() (*Message, error) {
 	return m, nil
 }
 
+func (c *agentClient) Exec(ctx context.Context, in *ExecRequest, opts ...grpc.CallOption) (Agent_ExecClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Agent_serviceDesc.Streams[1], "/proto.Agent/Exec", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &agentExecClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Agent_ExecClient interface {
+	Recv() (*ExecResponse, error)
+	grpc.ClientStream
+}
+
+type agentExecClient struct {
+	grpc.ClientStream
+}
+
+func (x *agentExecClient) Recv() (*ExecResponse, error) {
+	m := new(ExecResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *agentClient) PeerJoin(ctx context.Context, in *PeerJoinRequest, opts ...grpc.CallOption) (*types.Empty, error) {
 	out := new(types.Empty)
 	err := c.cc.Invoke(ctx, "/proto.Agent/PeerJoin", in, out, opts...)
@@ -903,6 +1544,37 @@ func (c *agentClient) PeerLeave(ctx context.Context, in *PeerLeaveRequest, opts
 	return out, nil
 }
 
+func (c *agentClient) Transfer(ctx context.Context, opts ...grpc.CallOption) (Agent_TransferClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Agent_serviceDesc.Streams[2], "/proto.Agent/Transfer", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &agentTransferClient{stream}
+	return x, nil
+}
+
+type Agent_TransferClient interface {
+	Send(*TransferRequest) error
+	Recv() (*TransferResponse, error)
+	grpc.ClientStream
+}
+
+type agentTransferClient struct {
+	grpc.ClientStream
+}
+
+func (x *agentTransferClient) Send(m *TransferRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *agentTransferClient) Recv() (*TransferResponse, error) {
+	m := new(TransferResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // AgentServer is the server API for Agent service.
 type AgentServer interface {
 	// Shutdown requests the agent to shut down
@@ -912,12 +1584,23 @@ type AgentServer interface {
 	// Command executes a command specified with CommandArgs.
 	// The output of the command is streamed as a result.
 	Command(*CommandArgs, Agent_CommandServer) error
+	// Exec executes a command specified with ExecRequest.
+	// The output of the command is streamed as a result followed
+	// by the command's ExecResult
+	Exec(*ExecRequest, Agent_ExecServer) error
 	// PeerJoin receives a connection from a peer.
 	// The peer configuration allows this agent to establish a reverse
 	// connection to the remote peer to execute remote commands
 	PeerJoin(context.Context, *PeerJoinRequest) (*types.Empty, error)
 	// PeerLeave receives a "leave" request from a peer and initiates its shutdown
 	PeerLeave(context.Context, *PeerLeaveRequest) (*types.Empty, error)
+	// Transfer copies a file to this agent.
+	// The client starts with a header describing the file and the agent replies
+	// with the offset to resume the transfer from. The client then streams
+	// the file contents in chunks starting at this offset.
+	// Once the file has been received and its checksum verified, the agent
+	// replies with the final status
+	Transfer(Agent_TransferServer) error
 }
 
 // UnimplementedAgentServer can be embedded to have forward compatible implementations.
@@ -933,12 +1616,18 @@ func (*UnimplementedAgentServer) Abort(ctx context.Context, req *types.Empty) (*
 func (*UnimplementedAgentServer) Command(req *CommandArgs, srv Agent_CommandServer) error {
 	return status.Errorf(codes.Unimplemented, "method Command not implemented")
 }
+func (*UnimplementedAgentServer) Exec(req *ExecRequest, srv Agent_ExecServer) error {
+	return status.Errorf(codes.Unimplemented, "method Exec not implemented")
+}
 func (*UnimplementedAgentServer) PeerJoin(ctx context.Context, req *PeerJoinRequest) (*types.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method PeerJoin not implemented")
 }
 func (*UnimplementedAgentServer) PeerLeave(ctx context.Context, req *PeerLeaveRequest) (*types.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method PeerLeave not implemented")
 }
+func (*UnimplementedAgentServer) Transfer(srv Agent_TransferServer) error {
+	return status.Errorf(codes.Unimplemented, "method Transfer not implemented")
+}
 
 func RegisterAgentServer(s *grpc.Server, srv AgentServer) {
 	s.RegisterService(&_Agent_serviceDesc, srv)
@@ -1001,6 +1690,27 @@ func (x *agentCommandServer) Send(m *Message) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _Agent_Exec_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExecRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AgentServer).Exec(m, &agentExecServer{stream})
+}
+
+type Agent_ExecServer interface {
+	Send(*ExecResponse) error
+	grpc.ServerStream
+}
+
+type agentExecServer struct {
+	grpc.ServerStream
+}
+
+func (x *agentExecServer) Send(m *ExecResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _Agent_PeerJoin_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(PeerJoinRequest)
 	if err := dec(in); err != nil {
@@ -1037,6 +1747,32 @@ func _Agent_PeerLeave_Handler(srv interface{}, ctx context.Context, dec func(int
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Agent_Transfer_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(AgentServer).Transfer(&agentTransferServer{stream})
+}
+
+type Agent_TransferServer interface {
+	Send(*TransferResponse) error
+	Recv() (*TransferRequest, error)
+	grpc.ServerStream
+}
+
+type agentTransferServer struct {
+	grpc.ServerStream
+}
+
+func (x *agentTransferServer) Send(m *TransferResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *agentTransferServer) Recv() (*TransferRequest, error) {
+	m := new(TransferRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 var _Agent_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "proto.Agent",
 	HandlerType: (*AgentServer)(nil),
@@ -1064,6 +1800,17 @@ var _Agent_serviceDesc = grpc.ServiceDesc{
 			Handler:       _Agent_Command_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "Exec",
+			Handler:       _Agent_Exec_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Transfer",
+			Handler:       _Agent_Transfer_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "agent.proto",
 }
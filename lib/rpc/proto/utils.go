@@ -17,6 +17,7 @@ limitations under the License.
 package proto
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
@@ -71,6 +72,11 @@ func EncodeError(err error) *Error {
 	}
 }
 
+// DecodeError converts error err from protobuf wire-friendly format
+func DecodeError(err *Error) error {
+	return trace.Wrap(errors.New(err.Message))
+}
+
 // ErrorToMessage returns a new message using the specified error
 func ErrorToMessage(err error) *Message {
 	return &Message{Element: &Message_Error{EncodeError(err)}}
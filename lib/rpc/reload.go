@@ -0,0 +1,313 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rpc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gravitational/gravity/lib/defaults"
+	"github.com/gravitational/gravity/lib/loc"
+	"github.com/gravitational/gravity/lib/pack"
+	pb "github.com/gravitational/gravity/lib/rpc/proto"
+	"github.com/gravitational/gravity/lib/utils"
+
+	"github.com/gravitational/license/authority"
+	"github.com/gravitational/trace"
+	"github.com/jonboulle/clockwork"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/credentials"
+)
+
+// NewReloadableCredentials returns transport credentials that delegate
+// to the specified credentials until replaced with Reload.
+// Connections established before the reload keep using the credentials
+// they have been created with
+func NewReloadableCredentials(creds credentials.TransportCredentials) *ReloadableCredentials {
+	return &ReloadableCredentials{creds: creds}
+}
+
+// ReloadableCredentials is transport credentials that can be replaced at runtime.
+// Implements credentials.TransportCredentials
+type ReloadableCredentials struct {
+	mu    sync.RWMutex
+	creds credentials.TransportCredentials
+	// source specifies the credentials these have been cloned from.
+	// If set, the current credentials of the source are used instead of creds
+	source *ReloadableCredentials
+	// serverName optionally overrides the server name of the credentials
+	serverName string
+}
+
+// Reload replaces the underlying credentials.
+// Reloading a clone detaches it from the credentials it has been cloned from
+func (r *ReloadableCredentials) Reload(creds credentials.TransportCredentials) {
+	r.mu.Lock()
+	r.creds = creds
+	r.source = nil
+	r.mu.Unlock()
+}
+
+// ClientHandshake does the authentication handshake specified by the current credentials
+func (r *ReloadableCredentials) ClientHandshake(ctx context.Context, authority string, conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	return r.get().ClientHandshake(ctx, authority, conn)
+}
+
+// ServerHandshake does the authentication handshake for servers specified by the current credentials
+func (r *ReloadableCredentials) ServerHandshake(conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	return r.get().ServerHandshake(conn)
+}
+
+// Info provides the ProtocolInfo of the current credentials
+func (r *ReloadableCredentials) Info() credentials.ProtocolInfo {
+	return r.get().Info()
+}
+
+// Clone returns a copy of these credentials.
+// The copy delegates to the current credentials of the original so it observes
+// reloads while changes to the copy do not affect the original
+func (r *ReloadableCredentials) Clone() credentials.TransportCredentials {
+	return &ReloadableCredentials{source: r}
+}
+
+// OverrideServerName overrides the server name of these credentials.
+// The override is kept across reloads
+func (r *ReloadableCredentials) OverrideServerName(name string) error {
+	r.mu.Lock()
+	r.serverName = name
+	r.mu.Unlock()
+	return nil
+}
+
+func (r *ReloadableCredentials) get() credentials.TransportCredentials {
+	r.mu.RLock()
+	creds, source, serverName := r.creds, r.source, r.serverName
+	r.mu.RUnlock()
+	if source != nil {
+		creds = source.get()
+	}
+	if serverName == "" {
+		return creds
+	}
+	creds = creds.Clone()
+	if err := creds.OverrideServerName(serverName); err != nil {
+		log.WithError(err).Warn("Failed to override server name.")
+	}
+	return creds
+}
+
+// CredentialsSource is a source of RPC credentials
+type CredentialsSource interface {
+	// Digest returns a value that changes whenever the credentials change
+	Digest() (string, error)
+	// Credentials returns the credentials archive
+	Credentials() (utils.TLSArchive, error)
+}
+
+// NewDirectorySource returns a credentials source that reads credentials
+// from files in the specified directory
+func NewDirectorySource(secretsDir string) CredentialsSource {
+	return directorySource(secretsDir)
+}
+
+// Digest returns the checksum of the credential files
+func (r directorySource) Digest() (string, error) {
+	archive, err := r.Credentials()
+	if err != nil {
+		return "", trace.Wrap(err)
+	}
+	return archiveDigest(archive), nil
+}
+
+// Credentials reads the credentials archive from the directory
+func (r directorySource) Credentials() (utils.TLSArchive, error) {
+	return CredentialsFromDir(string(r))
+}
+
+type directorySource string
+
+// NewPackageSource returns a credentials source that reads credentials
+// from the specified package
+func NewPackageSource(packages pack.PackageService, secretsPackage loc.Locator) CredentialsSource {
+	return &packageSource{packages: packages, secretsPackage: secretsPackage}
+}
+
+// Digest returns the checksum of the credentials package
+func (r *packageSource) Digest() (string, error) {
+	envelope, err := r.packages.ReadPackageEnvelope(r.secretsPackage)
+	if err != nil {
+		return "", trace.Wrap(err)
+	}
+	return envelope.SHA512, nil
+}
+
+// Credentials reads the credentials archive from the package
+func (r *packageSource) Credentials() (utils.TLSArchive, error) {
+	return CredentialsFromPackage(r.packages, r.secretsPackage)
+}
+
+type packageSource struct {
+	packages       pack.PackageService
+	secretsPackage loc.Locator
+}
+
+// CredentialsFromDir reads the credentials archive from the files in the specified directory
+func CredentialsFromDir(secretsDir string) (utils.TLSArchive, error) {
+	archive := make(utils.TLSArchive)
+	for _, name := range []string{pb.Client, pb.Server, pb.CA} {
+		var keyPair authority.TLSKeyPair
+		certPEM, err := ioutil.ReadFile(filepath.Join(secretsDir, fmt.Sprintf("%s.%s", name, pb.Cert)))
+		if err != nil {
+			return nil, trace.ConvertSystemError(err)
+		}
+		keyPair.CertPEM = certPEM
+		if name != pb.CA {
+			keyPEM, err := ioutil.ReadFile(filepath.Join(secretsDir, fmt.Sprintf("%s.%s", name, pb.Key)))
+			if err != nil {
+				return nil, trace.ConvertSystemError(err)
+			}
+			keyPair.KeyPEM = keyPEM
+		}
+		archive[name] = &keyPair
+	}
+	return archive, nil
+}
+
+// WatchCredentials periodically checks the credentials source for changes and
+// reloads the server and client credentials specified with config.
+// Blocks until the context is canceled
+func WatchCredentials(ctx context.Context, config WatchConfig) error {
+	if err := config.checkAndSetDefaults(); err != nil {
+		return trace.Wrap(err)
+	}
+	digest, err := config.Source.Digest()
+	if err != nil {
+		config.WithError(err).Warn("Failed to query credentials.")
+	}
+	w := &watcher{WatchConfig: config, digest: digest}
+	ticker := config.Clock.NewTicker(config.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.Chan():
+			if err := w.reloadIfChanged(); err != nil {
+				config.WithError(err).Warn("Failed to reload credentials.")
+			}
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// WatchConfig defines the configuration for watching credentials
+type WatchConfig struct {
+	// Source specifies the source of credentials
+	Source CredentialsSource
+	// Server specifies the server credentials to reload
+	Server *ReloadableCredentials
+	// Client specifies the client credentials to reload
+	Client *ReloadableCredentials
+	// OnReload is an optional callback invoked after the credentials have been reloaded
+	OnReload func(utils.TLSArchive)
+	// Interval specifies how often to check for changes
+	Interval time.Duration
+	// Clock specifies the time source
+	Clock clockwork.Clock
+	// FieldLogger specifies the logger
+	log.FieldLogger
+}
+
+func (r *WatchConfig) checkAndSetDefaults() error {
+	if r.Source == nil {
+		return trace.BadParameter("credentials source is required")
+	}
+	if r.Interval == 0 {
+		r.Interval = defaults.RPCCredentialsReloadInterval
+	}
+	if r.Clock == nil {
+		r.Clock = clockwork.NewRealClock()
+	}
+	if r.FieldLogger == nil {
+		r.FieldLogger = log.WithField(trace.Component, "rpc:creds")
+	}
+	return nil
+}
+
+func (r *watcher) reloadIfChanged() error {
+	digest, err := r.Source.Digest()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if digest == r.digest {
+		return nil
+	}
+	archive, err := r.Source.Credentials()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if err := ValidateCredentials(archive, r.Clock.Now()); err != nil {
+		return trace.Wrap(err)
+	}
+	if r.Server != nil {
+		creds, err := ServerCredentialsFromKeyPairs(*archive[pb.Server], *archive[pb.CA])
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		r.Server.Reload(creds)
+	}
+	if r.Client != nil {
+		creds, err := ClientCredentialsFromKeyPairs(*archive[pb.Client], *archive[pb.CA])
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		r.Client.Reload(creds)
+	}
+	if r.OnReload != nil {
+		r.OnReload(archive)
+	}
+	r.digest = digest
+	r.Info("Reloaded RPC credentials.")
+	return nil
+}
+
+type watcher struct {
+	WatchConfig
+	digest string
+}
+
+func archiveDigest(archive utils.TLSArchive) string {
+	names := make([]string, 0, len(archive))
+	for name := range archive {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	hash := sha256.New()
+	for _, name := range names {
+		hash.Write([]byte(name))
+		hash.Write(archive[name].CertPEM)
+		hash.Write(archive[name].KeyPEM)
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rpc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	pb "github.com/gravitational/gravity/lib/rpc/proto"
+	"github.com/gravitational/gravity/lib/utils"
+
+	"github.com/gravitational/trace"
+	"google.golang.org/grpc/credentials"
+	"gopkg.in/check.v1"
+)
+
+func TestRPC(t *testing.T) { check.TestingT(t) }
+
+type ReloadSuite struct{}
+
+var _ = check.Suite(&ReloadSuite{})
+
+func (r *ReloadSuite) TestRotationKeepsAgentsConnected(c *check.C) {
+	longLivedClient := true
+	archive, err := GenerateAgentCredentials(nil, "cluster", longLivedClient)
+	c.Assert(err, check.IsNil)
+	trusted, rotated, err := RotateCredentials(archive, nil, "cluster", longLivedClient, time.Now())
+	c.Assert(err, check.IsNil)
+	c.Assert(ValidateCredentials(trusted, time.Now()), check.IsNil)
+	c.Assert(ValidateCredentials(rotated, time.Now()), check.IsNil)
+
+	// Every combination of adjacent rotation steps must be able to connect
+	steps := []utils.TLSArchive{archive, trusted, rotated}
+	for i := 0; i < len(steps)-1; i++ {
+		for _, pair := range [][2]utils.TLSArchive{
+			{steps[i], steps[i+1]},
+			{steps[i+1], steps[i]},
+		} {
+			c.Assert(handshake(c, pair[0], pair[1]), check.IsNil, check.Commentf("step %v", i))
+		}
+	}
+	c.Assert(handshake(c, archive, rotated), check.NotNil)
+}
+
+func (r *ReloadSuite) TestReloadsOnChange(c *check.C) {
+	longLivedClient := true
+	archive, err := GenerateAgentCredentials(nil, "cluster", longLivedClient)
+	c.Assert(err, check.IsNil)
+	_, rotated, err := RotateCredentials(archive, nil, "cluster", longLivedClient, time.Now())
+	c.Assert(err, check.IsNil)
+
+	serverCreds, err := ServerCredentialsFromKeyPairs(*archive[pb.Server], *archive[pb.CA])
+	c.Assert(err, check.IsNil)
+	server := NewReloadableCredentials(serverCreds)
+	source := &testSource{archive: archive}
+	var reloaded int
+	w := &watcher{
+		WatchConfig: WatchConfig{
+			Source:   source,
+			Server:   server,
+			OnReload: func(utils.TLSArchive) { reloaded++ },
+		},
+		digest: archiveDigest(archive),
+	}
+	c.Assert(w.checkAndSetDefaults(), check.IsNil)
+
+	c.Assert(w.reloadIfChanged(), check.IsNil)
+	c.Assert(reloaded, check.Equals, 0)
+
+	source.archive = rotated
+	c.Assert(w.reloadIfChanged(), check.IsNil)
+	c.Assert(reloaded, check.Equals, 1)
+	c.Assert(handshakeWith(c, server, rotated), check.IsNil)
+}
+
+func (r *ReloadSuite) TestClonesObserveReloads(c *check.C) {
+	longLivedClient := true
+	archive, err := GenerateAgentCredentials(nil, "cluster", longLivedClient)
+	c.Assert(err, check.IsNil)
+	_, rotated, err := RotateCredentials(archive, nil, "cluster", longLivedClient, time.Now())
+	c.Assert(err, check.IsNil)
+
+	serverCreds, err := ServerCredentialsFromKeyPairs(*archive[pb.Server], *archive[pb.CA])
+	c.Assert(err, check.IsNil)
+	server := NewReloadableCredentials(serverCreds)
+	clone := server.Clone()
+	c.Assert(clone, check.Not(check.Equals), server)
+
+	// Overriding the server name of the clone does not affect the original
+	c.Assert(clone.OverrideServerName("other"), check.IsNil)
+	c.Assert(server.serverName, check.Equals, "")
+
+	rotatedCreds, err := ServerCredentialsFromKeyPairs(*rotated[pb.Server], *rotated[pb.CA])
+	c.Assert(err, check.IsNil)
+	server.Reload(rotatedCreds)
+	c.Assert(handshakeWith(c, clone, rotated), check.IsNil)
+}
+
+func (r *ReloadSuite) TestVerifiesCredentials(c *check.C) {
+	longLivedClient := true
+	archive, err := GenerateAgentCredentials(nil, "cluster", longLivedClient)
+	c.Assert(err, check.IsNil)
+	trusted, rotated, err := RotateCredentials(archive, nil, "cluster", longLivedClient, time.Now())
+	c.Assert(err, check.IsNil)
+	for _, archive := range []utils.TLSArchive{archive, trusted, rotated} {
+		c.Assert(VerifyCredentials(archive, time.Now()), check.IsNil)
+	}
+
+	other, err := GenerateAgentCredentials(nil, "other", longLivedClient)
+	c.Assert(err, check.IsNil)
+	forged := utils.TLSArchive{
+		pb.Server: other[pb.Server],
+		pb.Client: other[pb.Client],
+		pb.CA:     rotated[pb.CA],
+	}
+	c.Assert(VerifyCredentials(forged, time.Now()), check.NotNil)
+	c.Assert(VerifyCredentials(utils.TLSArchive{pb.CA: rotated[pb.CA]}, time.Now()), check.NotNil)
+}
+
+func handshake(c *check.C, serverArchive, clientArchive utils.TLSArchive) error {
+	serverCreds, err := ServerCredentialsFromKeyPairs(*serverArchive[pb.Server], *serverArchive[pb.CA])
+	c.Assert(err, check.IsNil)
+	return handshakeWith(c, serverCreds, clientArchive)
+}
+
+func handshakeWith(c *check.C, serverCreds credentials.TransportCredentials, clientArchive utils.TLSArchive) error {
+	clientCreds, err := ClientCredentialsFromKeyPairs(*clientArchive[pb.Client], *clientArchive[pb.CA])
+	c.Assert(err, check.IsNil)
+	listener, err := net.Listen("tcp4", "127.0.0.1:0")
+	c.Assert(err, check.IsNil)
+	defer listener.Close()
+	errC := make(chan error, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			errC <- err
+			return
+		}
+		defer conn.Close()
+		conn.SetDeadline(time.Now().Add(5 * time.Second))
+		_, _, err = serverCreds.ServerHandshake(conn)
+		errC <- err
+	}()
+	conn, err := net.Dial("tcp4", listener.Addr().String())
+	c.Assert(err, check.IsNil)
+	defer conn.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, _, err = clientCreds.ClientHandshake(ctx, pb.ServerName, conn)
+	return trace.NewAggregate(err, <-errC)
+}
+
+func (r *testSource) Digest() (string, error) {
+	return archiveDigest(r.archive), nil
+}
+
+func (r *testSource) Credentials() (utils.TLSArchive, error) {
+	return r.archive, nil
+}
+
+type testSource struct {
+	archive utils.TLSArchive
+}
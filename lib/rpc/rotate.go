@@ -0,0 +1,169 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rpc
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+
+	"github.com/gravitational/gravity/lib/constants"
+	"github.com/gravitational/gravity/lib/defaults"
+	"github.com/gravitational/gravity/lib/loc"
+	"github.com/gravitational/gravity/lib/pack"
+	"github.com/gravitational/gravity/lib/utils"
+
+	teleclient "github.com/gravitational/teleport/lib/client"
+	"github.com/gravitational/trace"
+	"github.com/jonboulle/clockwork"
+	"github.com/sirupsen/logrus"
+)
+
+// RotateCredentialsRequest describes a request to rotate the RPC credentials
+// stored in a secrets package
+type RotateCredentialsRequest struct {
+	// Packages specifies the package service with the secrets package
+	Packages pack.PackageService
+	// SecretsPackage specifies the package with RPC credentials to rotate
+	SecretsPackage loc.Locator
+	// Hosts lists additional hosts to add to the server certificate
+	Hosts []string
+	// CommonName specifies the common name for the new CA
+	CommonName string
+	// LongLivedClient specifies whether the client certificate is long-lived
+	LongLivedClient bool
+	// Servers optionally lists the nodes to push the updated credentials to.
+	// Agents running on these nodes pick up the credentials without restart
+	Servers []DeployServer
+	// Proxy is the teleport proxy client used to push credentials to Servers
+	Proxy *teleclient.ProxyClient
+	// ReloadTimeout specifies how long to wait for the running processes
+	// to pick up the intermediate credentials before replacing the certificates
+	ReloadTimeout time.Duration
+	// Updater optionally specifies how to update the secrets package.
+	// If unspecified, the package is updated in Packages directly
+	Updater CredentialsUpdater
+	// Clock specifies the time source
+	Clock clockwork.Clock
+	// FieldLogger specifies the logger
+	logrus.FieldLogger
+}
+
+// CredentialsUpdater updates the secrets package with new credentials
+type CredentialsUpdater interface {
+	// UpdateCredentials replaces the credentials in the secrets package
+	UpdateCredentials(context.Context, utils.TLSArchive) error
+}
+
+func (r *RotateCredentialsRequest) checkAndSetDefaults() error {
+	if r.Packages == nil {
+		return trace.BadParameter("package service is required")
+	}
+	if r.SecretsPackage.IsEmpty() {
+		return trace.BadParameter("secrets package is required")
+	}
+	if len(r.Servers) != 0 && r.Proxy == nil {
+		return trace.BadParameter("proxy is required to push credentials to nodes")
+	}
+	if r.ReloadTimeout == 0 {
+		r.ReloadTimeout = 2 * defaults.RPCCredentialsReloadInterval
+	}
+	if r.Clock == nil {
+		r.Clock = clockwork.NewRealClock()
+	}
+	if r.FieldLogger == nil {
+		r.FieldLogger = logrus.WithField(trace.Component, "rpc:rotate")
+	}
+	return nil
+}
+
+// RotatePackageCredentials replaces the credentials in the specified secrets package
+// with newly generated ones.
+//
+// The package is updated twice: first, the new CA is added to the trusted bundle while
+// the existing certificates are kept, then, after the running processes have had a chance
+// to reload the bundle, the certificates are replaced with the ones signed by the new CA.
+// This way, processes that reload credentials at different times can still connect
+// to each other
+func RotatePackageCredentials(ctx context.Context, req RotateCredentialsRequest) error {
+	if err := req.checkAndSetDefaults(); err != nil {
+		return trace.Wrap(err)
+	}
+	archive, err := CredentialsFromPackage(req.Packages, req.SecretsPackage)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	trusted, rotated, err := RotateCredentials(archive, req.Hosts, req.CommonName,
+		req.LongLivedClient, req.Clock.Now())
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	req.Infof("Add new CA to the trusted bundle in %v.", req.SecretsPackage)
+	if err := req.update(ctx, trusted); err != nil {
+		return trace.Wrap(err)
+	}
+	select {
+	case <-req.Clock.After(req.ReloadTimeout):
+	case <-ctx.Done():
+		return trace.Wrap(ctx.Err())
+	}
+	req.Infof("Replace certificates in %v.", req.SecretsPackage)
+	if err := req.update(ctx, rotated); err != nil {
+		return trace.Wrap(err)
+	}
+	return nil
+}
+
+func (r *RotateCredentialsRequest) update(ctx context.Context, archive utils.TLSArchive) error {
+	if err := r.updatePackage(ctx, archive); err != nil {
+		return trace.Wrap(err)
+	}
+	errors := make(chan error, len(r.Servers))
+	for _, server := range r.Servers {
+		go func(node string) {
+			err := trace.Wrap(r.pushToNode(ctx, node))
+			if err != nil {
+				r.WithError(err).WithField("node", node).Warn("Failed to push credentials.")
+			}
+			errors <- err
+		}(server.NodeAddr)
+	}
+	return trace.Wrap(utils.CollectErrors(ctx, errors))
+}
+
+func (r *RotateCredentialsRequest) updatePackage(ctx context.Context, archive utils.TLSArchive) error {
+	if r.Updater != nil {
+		return trace.Wrap(r.Updater.UpdateCredentials(ctx, archive))
+	}
+	return trace.Wrap(UpsertCredentialsPackage(r.Packages, r.SecretsPackage, archive))
+}
+
+// pushToNode unpacks the secrets package into the agent secrets directory on the specified node
+func (r *RotateCredentialsRequest) pushToNode(ctx context.Context, node string) error {
+	nodeClient, err := r.Proxy.ConnectToNode(ctx, node, defaults.SSHUser, false)
+	if err != nil {
+		return trace.Wrap(err, node)
+	}
+	defer nodeClient.Close()
+
+	secretsPlanetDir := filepath.Join(defaults.GravityRPCAgentDir, defaults.SecretsDir)
+	return trace.Wrap(utils.NewSSHCommands(nodeClient.Client).
+		WithRetries("%s enter -- --notty %s -- package unpack %s %s --debug --ops-url=%s --insecure",
+			constants.GravityBin, defaults.GravityBin, r.SecretsPackage, secretsPlanetDir, defaults.GravityServiceURL).
+		WithLogger(r.WithField("node", node)).
+		Run(ctx))
+}
@@ -71,6 +71,9 @@ type AgentGroupConfig struct {
 	log.FieldLogger
 	// ReconnectStrategy configures the strategy for peer reconnects
 	ReconnectStrategy
+	// ReconnectPolicy configures the backoff for peer reconnects.
+	// Only used if ReconnectStrategy does not specify the backoff
+	ReconnectPolicy ReconnectPolicy
 	// HealthCheckTimeout overrides timeout between health check attempts.
 	// Defaults to defaults.AgentHealthCheckTimeout
 	HealthCheckTimeout time.Duration
@@ -89,6 +92,10 @@ func (r *AgentGroupConfig) CheckAndSetDefaults() error {
 		r.HealthCheckTimeout = defaults.AgentReconnectTimeout
 	}
 
+	if r.Backoff == nil {
+		r.Backoff = r.ReconnectPolicy.Backoff
+	}
+
 	return nil
 }
 
@@ -167,6 +174,30 @@ func (r *AgentGroup) Remove(ctx context.Context, p Peer) error {
 	return nil
 }
 
+// Evict disconnects the peer specified with addr and removes it from the group.
+// Unlike Remove, the peer is not requested to shut down so a peer that has become
+// unresponsive can be removed without blocking the operation.
+// The evicted peer can rejoin the group after it has been restarted
+func (r *AgentGroup) Evict(addr string) error {
+	clt, exists := r.peers.getClient(addr)
+	if !exists {
+		return trace.NotFound("peer %v not found", addr)
+	}
+	r.peers.deleteAddr(addr)
+	if clt != nil {
+		if err := clt.Close(); err != nil {
+			r.WithError(err).WithField("peer", addr).Warn("Failed to close client.")
+		}
+	}
+	r.WithField("peer", addr).Info("Evicted peer.")
+	return nil
+}
+
+// GetPeerStatus returns the connection status of every peer in this group
+func (r *AgentGroup) GetPeerStatus() []PeerStatus {
+	return r.peers.getStatus()
+}
+
 // Shutdown requests agents to shut down
 func (r *AgentGroup) Shutdown(ctx context.Context, req *pb.ShutdownRequest) error {
 	err := r.peers.iterate(func(p peer) error {
@@ -255,6 +286,10 @@ func (r errorPeer) GravityCommand(context.Context, log.FieldLogger, io.Writer, .
 	return trace.Wrap(r.error)
 }
 
+func (r errorPeer) Exec(context.Context, client.ExecRequest) (*client.ExecResult, error) {
+	return nil, trace.Wrap(r.error)
+}
+
 func (r errorPeer) Validate(context.Context, *validationpb.ValidateRequest) ([]*agentpb.Probe, error) {
 	return nil, trace.Wrap(r.error)
 }
@@ -283,6 +318,26 @@ func (r errorPeer) CheckDisks(context.Context, *validationpb.CheckDisksRequest)
 	return nil, trace.Wrap(r.error)
 }
 
+func (r errorPeer) CheckPathMTU(context.Context, *validationpb.CheckPathMTURequest) (*validationpb.CheckPathMTUResponse, error) {
+	return nil, trace.Wrap(r.error)
+}
+
+func (r errorPeer) CheckLatency(context.Context, *validationpb.CheckLatencyRequest) (*validationpb.CheckLatencyResponse, error) {
+	return nil, trace.Wrap(r.error)
+}
+
+func (r errorPeer) CheckPacketLoss(context.Context, *validationpb.CheckPacketLossRequest) (*validationpb.CheckPacketLossResponse, error) {
+	return nil, trace.Wrap(r.error)
+}
+
+func (r errorPeer) CheckVXLAN(context.Context, *validationpb.CheckVXLANRequest) (*validationpb.CheckVXLANResponse, error) {
+	return nil, trace.Wrap(r.error)
+}
+
+func (r errorPeer) Transfer(context.Context, client.TransferRequest) error {
+	return trace.Wrap(r.error)
+}
+
 func (r errorPeer) Shutdown(context.Context, *pb.ShutdownRequest) error {
 	return trace.Wrap(r.error)
 }
@@ -207,6 +207,79 @@ func (r *S) TestAgentGroupReconnects(c *C) {
 	cancel()
 	c.Assert(err, IsNil)
 	c.Assert(buf.String(), DeepEquals, "test output")
+
+	for _, status := range group.GetPeerStatus() {
+		if status.Addr == proxyAddr {
+			c.Assert(status.Reconnects, Equals, 1)
+		}
+	}
+}
+
+func (r *S) TestAgentGroupEvictsPeer(c *C) {
+	ctx, cancel := context.WithTimeout(context.TODO(), 5*time.Second)
+	defer cancel()
+	creds := TestCredentials(c)
+	store := newPeerStore()
+	l := listen(c)
+	log := r.WithField("test", "AgentGroupEvictsPeer")
+	srv, err := New(Config{
+		FieldLogger:     log.WithField("from", l.Addr()),
+		Credentials:     creds,
+		PeerStore:       store,
+		Listener:        l,
+		commandExecutor: testCommand{"server output"},
+	})
+	c.Assert(err, IsNil)
+	go srv.Serve()
+	defer withTestCtx(srv.Stop)
+
+	serverAddr := srv.Addr().String()
+	p1 := r.newPeer(c, PeerConfig{Config: Config{Listener: listen(c)}}, serverAddr, log)
+	go p1.Serve()
+	defer withTestCtx(p1.Stop)
+
+	p2 := r.newPeer(c, PeerConfig{Config: Config{Listener: listen(c)}}, serverAddr, log)
+	go p2.Serve()
+	defer withTestCtx(p2.Stop)
+
+	c.Assert(store.expect(ctx, 2), IsNil)
+
+	watchCh := make(chan WatchEvent, 2)
+	config := AgentGroupConfig{
+		FieldLogger:        log.WithField(trace.Component, "agent.group"),
+		WatchCh:            watchCh,
+		HealthCheckTimeout: 100 * time.Millisecond,
+	}
+	group, err := NewAgentGroup(config, store.getPeers())
+	c.Assert(err, IsNil)
+	group.Start()
+	defer withTestCtx(group.Close)
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-watchCh:
+		case <-ctx.Done():
+			c.Fatal("failed to wait for connect")
+		}
+	}
+
+	status := group.GetPeerStatus()
+	c.Assert(status, HasLen, 2)
+	for _, peer := range status {
+		c.Assert(peer.Connected, Equals, true)
+		c.Assert(peer.LastHeartbeat.IsZero(), Equals, false)
+	}
+
+	evicted := p2.Addr().String()
+	c.Assert(group.Evict(evicted), IsNil)
+	c.Assert(group.NumPeers(), Equals, 1)
+	c.Assert(group.GetPeerStatus(), HasLen, 1)
+	c.Assert(trace.IsNotFound(group.Evict(evicted)), Equals, true)
+
+	var buf bytes.Buffer
+	err = group.WithContext(ctx, p1.Addr().String()).Command(ctx, log, &buf, "test")
+	c.Assert(err, IsNil)
+	c.Assert(buf.String(), Equals, "test output")
 }
 
 func (r *S) TestAgentGroupRemovesPeerItCannotReconnect(c *C) {
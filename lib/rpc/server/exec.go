@@ -0,0 +1,171 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+
+	pb "github.com/gravitational/gravity/lib/rpc/proto"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/gravitational/trace"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
+)
+
+// Exec executes the command given with req, streams its output and
+// completes with the command's result.
+// Command failures are reported with the result rather than as an error
+func (srv *agentServer) Exec(req *pb.ExecRequest, stream pb.Agent_ExecServer) error {
+	if len(req.Args) == 0 {
+		return trace.BadParameter("at least one argument is required")
+	}
+
+	logger := srv.WithFields(log.Fields{
+		"request": "Exec",
+		"args":    req.Args})
+	logger.Debug("Request received.")
+
+	args := req.Args
+	if req.SelfCommand {
+		gravityPath, err := os.Executable()
+		if err != nil {
+			return trace.ConvertSystemError(err)
+		}
+		args = append([]string{gravityPath}, args...)
+	}
+
+	ctx := stream.Context()
+	var timeout time.Duration
+	if req.Timeout != nil {
+		var err error
+		timeout, err = types.DurationFromProto(req.Timeout)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	out := &execOutputWriter{stream: stream}
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Dir = req.WorkDir
+	cmd.Env = append(os.Environ(), formatEnv(req.Env)...)
+	cmd.Stdout = out.writer(pb.ExecOutput_STDOUT)
+	cmd.Stderr = out.writer(pb.ExecOutput_STDERR)
+
+	started := time.Now()
+	err := cmd.Run()
+	result, err := newExecResult(cmd, started, err)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	result.TimedOut = timeout > 0 && ctx.Err() == context.DeadlineExceeded
+	logger.WithFields(log.Fields{
+		"exit":      result.ExitCode,
+		"timed-out": result.TimedOut,
+	}).Debug("Command completed.")
+	return trace.Wrap(out.send(&pb.ExecResponse{
+		Element: &pb.ExecResponse_Result{Result: result},
+	}))
+}
+
+func newExecResult(cmd *exec.Cmd, started time.Time, err error) (*pb.ExecResult, error) {
+	startedProto, errConvert := types.TimestampProto(started)
+	if errConvert != nil {
+		return nil, trace.Wrap(errConvert)
+	}
+	result := &pb.ExecResult{
+		Started:  startedProto,
+		Duration: types.DurationProto(time.Since(started)),
+		ExitCode: ExitCodeUndefined,
+	}
+	if err != nil {
+		result.Error = pb.EncodeError(trace.Wrap(err))
+	}
+	state := cmd.ProcessState
+	if state == nil {
+		// Command failed to start
+		return result, nil
+	}
+	if status, ok := state.Sys().(syscall.WaitStatus); ok {
+		result.ExitCode = int32(status.ExitStatus())
+	}
+	if usage, ok := state.SysUsage().(*syscall.Rusage); ok {
+		result.Usage = &pb.ResourceUsage{
+			UserTime:   types.DurationProto(time.Duration(usage.Utime.Nano())),
+			SystemTime: types.DurationProto(time.Duration(usage.Stime.Nano())),
+			// Maxrss is reported in kilobytes
+			MaxRss: usage.Maxrss * 1024,
+		}
+	}
+	return result, nil
+}
+
+// formatEnv formats the specified environment as a sorted list of key=value pairs
+func formatEnv(env map[string]string) (result []string) {
+	for k, v := range env {
+		result = append(result, fmt.Sprintf("%v=%v", k, v))
+	}
+	sort.Strings(result)
+	return result
+}
+
+func (r *execOutputWriter) writer(fd pb.ExecOutput_FD) *execFDWriter {
+	return &execFDWriter{execOutputWriter: r, fd: fd}
+}
+
+// send sends the specified response to the stream.
+// Since stdout and stderr are copied concurrently, access to the stream is serialized
+func (r *execOutputWriter) send(resp *pb.ExecResponse) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.stream.Send(resp)
+}
+
+type execOutputWriter struct {
+	mu     sync.Mutex
+	stream pb.Agent_ExecServer
+}
+
+// Write sends the data as command output to the stream
+func (r *execFDWriter) Write(p []byte) (n int, err error) {
+	err = r.send(&pb.ExecResponse{
+		Element: &pb.ExecResponse_Output{Output: &pb.ExecOutput{
+			Fd:   r.fd,
+			Data: p,
+		}},
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+type execFDWriter struct {
+	*execOutputWriter
+	fd pb.ExecOutput_FD
+}
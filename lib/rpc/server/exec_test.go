@@ -0,0 +1,70 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"github.com/gravitational/gravity/lib/rpc/client"
+
+	"github.com/gravitational/trace"
+	. "gopkg.in/check.v1"
+)
+
+func (r *S) TestExecReturnsStructuredResult(c *C) {
+	dir := c.MkDir()
+	clt := r.newTestClient(c)
+	var stdout, stderr bytes.Buffer
+	result, err := clt.Exec(context.TODO(), client.ExecRequest{
+		Args:    []string{"/bin/sh", "-c", `echo "$VALUE"; pwd; echo error >&2; exit 3`},
+		Env:     map[string]string{"VALUE": "value"},
+		WorkDir: dir,
+		Stdout:  &stdout,
+		Stderr:  &stderr,
+	})
+	c.Assert(err, IsNil)
+	c.Assert(result.ExitCode, Equals, 3)
+	c.Assert(result.TimedOut, Equals, false)
+	c.Assert(result.Started.IsZero(), Equals, false)
+	c.Assert(stdout.String(), Equals, "value\n"+dir+"\n")
+	c.Assert(stderr.String(), Equals, "error\n")
+	c.Assert(result.Check(), NotNil)
+}
+
+func (r *S) TestExecTimesOut(c *C) {
+	clt := r.newTestClient(c)
+	result, err := clt.Exec(context.TODO(), client.ExecRequest{
+		Args:    []string{"/bin/sleep", "10"},
+		Timeout: 100 * time.Millisecond,
+	})
+	c.Assert(err, IsNil)
+	c.Assert(result.TimedOut, Equals, true)
+	c.Assert(result.Duration < 10*time.Second, Equals, true)
+	c.Assert(trace.IsLimitExceeded(result.Check()), Equals, true)
+}
+
+func (r *S) TestExecReportsStartFailure(c *C) {
+	clt := r.newTestClient(c)
+	result, err := clt.Exec(context.TODO(), client.ExecRequest{
+		Args: []string{"/nonexistent"},
+	})
+	c.Assert(err, IsNil)
+	c.Assert(result.ExitCode, Equals, ExitCodeUndefined)
+	c.Assert(result.Error, NotNil)
+}
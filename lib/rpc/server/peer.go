@@ -28,6 +28,7 @@ import (
 
 	"github.com/cenkalti/backoff"
 	"github.com/gravitational/trace"
+	"github.com/gravitational/version"
 	log "github.com/sirupsen/logrus"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
@@ -125,6 +126,31 @@ type ReconnectStrategy struct {
 	ShouldReconnect func(err error) error `json:"-"`
 }
 
+// ReconnectPolicy defines the exponential backoff policy for peer reconnects
+type ReconnectPolicy struct {
+	// InitialInterval specifies the interval before the first reconnect attempt
+	InitialInterval time.Duration
+	// MaxInterval specifies the maximum interval between reconnect attempts
+	MaxInterval time.Duration
+	// Timeout specifies how long to keep reconnecting before giving up.
+	// Reconnects indefinitely if unspecified
+	Timeout time.Duration
+}
+
+// Backoff returns a new backoff interval for this policy
+func (r ReconnectPolicy) Backoff() backoff.BackOff {
+	b := backoff.NewExponentialBackOff()
+	if r.InitialInterval != 0 {
+		b.InitialInterval = r.InitialInterval
+	}
+	if r.MaxInterval != 0 {
+		b.MaxInterval = r.MaxInterval
+	}
+	b.MaxElapsedTime = r.Timeout
+	b.Reset()
+	return b
+}
+
 // Client defines the low-level agent client interface
 type Client interface {
 	pb.AgentClient
@@ -254,7 +280,12 @@ func (r *serverPeer) Reconnect(ctx context.Context) (Client, error) {
 		return nil, trace.Wrap(err)
 	}
 
-	_, err = clt.PeerJoin(ctx, &pb.PeerJoinRequest{Addr: r.addr, Config: &r.config, SystemInfo: payload})
+	_, err = clt.PeerJoin(ctx, &pb.PeerJoinRequest{
+		Addr:       r.addr,
+		Config:     &r.config,
+		SystemInfo: payload,
+		Version:    version.Get().Version,
+	})
 	if err != nil {
 		// Let ReconnectStrategy decide whether the peer should continue reconnecting
 		return nil, err
@@ -18,6 +18,7 @@ package server
 
 import (
 	"context"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -38,8 +39,10 @@ func newPeers(from []Peer, config peersConfig) (*peers, error) {
 	}
 
 	ps := make(map[string]*peer)
+	stats := make(map[string]*PeerStatus)
 	for _, p := range from {
 		ps[p.Addr()] = &peer{Peer: p, doneCh: make(chan struct{})}
+		stats[p.Addr()] = &PeerStatus{Addr: p.Addr()}
 	}
 
 	ctx, cancel := context.WithCancel(context.TODO())
@@ -49,6 +52,7 @@ func newPeers(from []Peer, config peersConfig) (*peers, error) {
 		ctx:               ctx,
 		cancel:            cancel,
 		peers:             ps,
+		stats:             stats,
 		checkTimeout:      config.checkTimeout,
 		watchCh:           config.watchCh,
 		updateCh:          make(chan peerUpdate, len(from)),
@@ -120,6 +124,7 @@ func (r *peers) monitorPeers() {
 				if prevClient != nil {
 					prevClient.Close()
 				}
+				r.recordConnect(peerUpdate.Addr(), prevClient != nil)
 			} else {
 				r.delete(peerUpdate.peer)
 			}
@@ -182,10 +187,13 @@ func (r *peers) monitorPeer(p Peer, clt Client, reconnectCh chan<- chan clientUp
 func (r *peers) checkPeer(p Peer, clt Client, reconnectCh chan<- chan clientUpdate, respCh chan clientUpdate, doneCh chan struct{}) (Client, error) {
 	log := r.WithField("checked", p)
 	if clt != nil {
+		start := time.Now()
 		resp, err := clt.Check(r.ctx, &healthpb.HealthCheckRequest{})
 		if err == nil && isPeerHealthy(*resp) {
+			r.recordHeartbeat(p.Addr(), start, time.Since(start))
 			return clt, nil
 		}
+		r.recordDisconnect(p.Addr())
 		log.Warnf("Failed health check: %+v (%v).", resp, err)
 	}
 	select {
@@ -266,6 +274,7 @@ func (r *peers) add(p peer) {
 	}
 	doneCh := make(chan struct{})
 	r.peers[p.Addr()] = &peer{Peer: p.Peer, doneCh: doneCh}
+	r.stats[p.Addr()] = &PeerStatus{Addr: p.Addr()}
 	r.Unlock()
 
 	reconnectCh := make(chan chan clientUpdate)
@@ -274,19 +283,78 @@ func (r *peers) add(p peer) {
 }
 
 func (r *peers) delete(p peer) {
+	r.deleteAddr(p.Addr())
+}
+
+func (r *peers) deleteAddr(addr string) {
 	r.Lock()
-	peer := r.peers[p.Addr()]
+	peer := r.peers[addr]
 	if peer != nil && peer.doneCh != nil {
 		close(peer.doneCh)
 	}
-	delete(r.peers, p.Addr())
+	delete(r.peers, addr)
+	delete(r.stats, addr)
 	r.Unlock()
 }
 
+func (r *peers) recordHeartbeat(addr string, when time.Time, rtt time.Duration) {
+	r.Lock()
+	defer r.Unlock()
+	if stats, ok := r.stats[addr]; ok {
+		stats.Connected = true
+		stats.LastHeartbeat = when
+		stats.RTT = rtt
+	}
+}
+
+func (r *peers) recordDisconnect(addr string) {
+	r.Lock()
+	defer r.Unlock()
+	if stats, ok := r.stats[addr]; ok {
+		stats.Connected = false
+	}
+}
+
+// recordConnect marks the peer specified with addr as connected.
+// reconnected indicates whether the peer has been connected before
+func (r *peers) recordConnect(addr string, reconnected bool) {
+	r.Lock()
+	defer r.Unlock()
+	if stats, ok := r.stats[addr]; ok {
+		stats.Connected = true
+		stats.LastHeartbeat = time.Now()
+		if reconnected {
+			stats.Reconnects++
+		}
+	}
+}
+
+// getStatus returns the status of all peers sorted by address
+func (r *peers) getStatus() []PeerStatus {
+	r.RLock()
+	defer r.RUnlock()
+	result := make([]PeerStatus, 0, len(r.stats))
+	for _, stats := range r.stats {
+		result = append(result, *stats)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Addr < result[j].Addr
+	})
+	return result
+}
+
 func (r *peers) update(p peer) Client {
 	r.Lock()
 	defer r.Unlock()
-	clt := r.peers[p.Addr()].Client
+	existing, ok := r.peers[p.Addr()]
+	if !ok {
+		// The peer has been removed while reconnecting
+		if p.Client != nil {
+			p.Client.Close()
+		}
+		return nil
+	}
+	clt := existing.Client
 	r.peers[p.Addr()] = &peer{Peer: p.Peer, Client: p.Client, doneCh: p.doneCh}
 	return clt
 }
@@ -341,6 +409,22 @@ type peers struct {
 	watchCh chan<- WatchEvent
 	sync.RWMutex
 	peers map[string]*peer
+	// stats maps peer address to its connection status
+	stats map[string]*PeerStatus
+}
+
+// PeerStatus describes the state of the connection to a peer
+type PeerStatus struct {
+	// Addr specifies the peer address
+	Addr string
+	// Connected indicates whether the last health check has been successful
+	Connected bool
+	// LastHeartbeat specifies the time of the last successful health check
+	LastHeartbeat time.Time
+	// RTT specifies the round trip time of the last successful health check
+	RTT time.Duration
+	// Reconnects specifies the number of times the peer has been reconnected
+	Reconnects int
 }
 
 func (r *peersConfig) checkAndSetDefaults() error {
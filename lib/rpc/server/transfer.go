@@ -0,0 +1,259 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/gravitational/gravity/lib/defaults"
+	"github.com/gravitational/gravity/lib/rpc/client"
+	pb "github.com/gravitational/gravity/lib/rpc/proto"
+
+	"github.com/gravitational/trace"
+	log "github.com/sirupsen/logrus"
+)
+
+// Transfer receives a file streamed by the client.
+// The file is written to a partial file next to the target path so that an interrupted
+// transfer can be resumed
+func (srv *agentServer) Transfer(stream pb.Agent_TransferServer) error {
+	req, err := stream.Recv()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	header := req.GetHeader()
+	if header == nil {
+		return trace.BadParameter("expected transfer header but got %T", req.Element)
+	}
+	if err := checkTransferHeader(*header); err != nil {
+		return trace.Wrap(err)
+	}
+
+	logger := srv.WithFields(log.Fields{
+		"request": "Transfer",
+		"path":    header.Path,
+	})
+	logger.Debug("Request received.")
+
+	w, err := newTransferWriter(*header)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	defer w.Close()
+
+	if err := stream.Send(&pb.TransferResponse{Offset: w.offset}); err != nil {
+		return trace.Wrap(err)
+	}
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		chunk := req.GetChunk()
+		if chunk == nil {
+			return trace.BadParameter("expected transfer chunk but got %T", req.Element)
+		}
+		if err := w.write(*chunk); err != nil {
+			return trace.Wrap(err)
+		}
+	}
+	if err := w.commit(); err != nil {
+		return trace.Wrap(err)
+	}
+	logger.WithField("size", header.Size_).Info("File received.")
+	return trace.Wrap(stream.Send(&pb.TransferResponse{
+		Offset:    header.Size_,
+		Completed: true,
+	}))
+}
+
+func checkTransferHeader(header pb.TransferHeader) error {
+	if !filepath.IsAbs(header.Path) {
+		return trace.BadParameter("transfer path should be absolute, got %q", header.Path)
+	}
+	if header.Size_ < 0 {
+		return trace.BadParameter("invalid file size %v", header.Size_)
+	}
+	if _, err := hex.DecodeString(header.Sha256); err != nil || len(header.Sha256) != sha256.Size*2 {
+		return trace.BadParameter("invalid SHA256 checksum %q", header.Sha256)
+	}
+	return nil
+}
+
+// newTransferWriter returns a new writer for the file described with header.
+// If the file has already been received, the writer is positioned at the end of the file.
+// If a partial file for the same contents exists, the writer resumes at the end of the partial file
+func newTransferWriter(header pb.TransferHeader) (*transferWriter, error) {
+	w := &transferWriter{
+		header:      header,
+		partialPath: fmt.Sprintf("%v.%v.partial", header.Path, header.Sha256[:16]),
+	}
+	completed, err := fileHasChecksum(header.Path, header.Sha256)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	if completed {
+		w.offset = header.Size_
+		w.completed = true
+		return w, nil
+	}
+	if err := removeStalePartials(header.Path, w.partialPath); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(header.Path), defaults.SharedDirMask); err != nil {
+		return nil, trace.ConvertSystemError(err)
+	}
+	f, err := os.OpenFile(w.partialPath, os.O_CREATE|os.O_WRONLY, defaults.PrivateFileMask)
+	if err != nil {
+		return nil, trace.ConvertSystemError(err)
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, trace.ConvertSystemError(err)
+	}
+	w.offset = fi.Size()
+	if w.offset > header.Size_ {
+		w.offset = 0
+		if err := f.Truncate(0); err != nil {
+			f.Close()
+			return nil, trace.ConvertSystemError(err)
+		}
+	}
+	if _, err := f.Seek(w.offset, io.SeekStart); err != nil {
+		f.Close()
+		return nil, trace.ConvertSystemError(err)
+	}
+	w.file = f
+	return w, nil
+}
+
+func (r *transferWriter) write(chunk pb.TransferChunk) error {
+	if r.completed {
+		return trace.BadParameter("file %v has already been received", r.header.Path)
+	}
+	if chunk.Offset != r.offset {
+		return trace.BadParameter("expected chunk at offset %v but got %v", r.offset, chunk.Offset)
+	}
+	if r.offset+int64(len(chunk.Data)) > r.header.Size_ {
+		return trace.BadParameter("chunk at offset %v exceeds file size %v", chunk.Offset, r.header.Size_)
+	}
+	if crc32.Checksum(chunk.Data, client.CRC32Table) != chunk.Crc32 {
+		return trace.BadParameter("checksum mismatch for chunk at offset %v", chunk.Offset)
+	}
+	n, err := r.file.Write(chunk.Data)
+	r.offset += int64(n)
+	return trace.ConvertSystemError(err)
+}
+
+// commit verifies the received file and moves it to the target path
+func (r *transferWriter) commit() error {
+	if r.completed {
+		return nil
+	}
+	if r.offset != r.header.Size_ {
+		return trace.BadParameter("received %v of %v bytes", r.offset, r.header.Size_)
+	}
+	if err := r.file.Sync(); err != nil {
+		return trace.ConvertSystemError(err)
+	}
+	if err := r.file.Close(); err != nil {
+		return trace.ConvertSystemError(err)
+	}
+	r.file = nil
+	verified, err := fileHasChecksum(r.partialPath, r.header.Sha256)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if !verified {
+		// Start over with the next attempt
+		os.Remove(r.partialPath)
+		return trace.BadParameter("checksum mismatch for %v", r.header.Path)
+	}
+	mode := os.FileMode(r.header.FileMode).Perm()
+	if mode == 0 {
+		mode = defaults.SharedReadMask
+	}
+	if err := os.Chmod(r.partialPath, mode); err != nil {
+		return trace.ConvertSystemError(err)
+	}
+	if err := os.Rename(r.partialPath, r.header.Path); err != nil {
+		return trace.ConvertSystemError(err)
+	}
+	r.completed = true
+	return nil
+}
+
+// Close closes the partial file if it is still open
+func (r *transferWriter) Close() error {
+	if r.file == nil {
+		return nil
+	}
+	return r.file.Close()
+}
+
+type transferWriter struct {
+	header      pb.TransferHeader
+	partialPath string
+	file        *os.File
+	offset      int64
+	completed   bool
+}
+
+// fileHasChecksum returns true if the file at path exists and has the specified SHA256 checksum
+func fileHasChecksum(path, checksum string) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, trace.ConvertSystemError(err)
+	}
+	defer f.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return false, trace.ConvertSystemError(err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)) == checksum, nil
+}
+
+// removeStalePartials removes partial files for path left from transfers
+// of different contents
+func removeStalePartials(path, partialPath string) error {
+	matches, err := filepath.Glob(path + ".*.partial")
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	for _, match := range matches {
+		if match == partialPath {
+			continue
+		}
+		if err := os.Remove(match); err != nil && !os.IsNotExist(err) {
+			return trace.ConvertSystemError(err)
+		}
+	}
+	return nil
+}
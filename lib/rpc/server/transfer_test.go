@@ -0,0 +1,122 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gravitational/gravity/lib/rpc/client"
+
+	. "gopkg.in/check.v1"
+)
+
+func (r *S) TestTransfersFile(c *C) {
+	dir := c.MkDir()
+	src := filepath.Join(dir, "src")
+	c.Assert(ioutil.WriteFile(src, []byte("file contents"), 0640), IsNil)
+	target := filepath.Join(dir, "target", "file")
+
+	clt := r.newTestClient(c)
+	err := clt.Transfer(context.TODO(), client.TransferRequest{
+		Path:       src,
+		TargetPath: target,
+	})
+	c.Assert(err, IsNil)
+
+	data, err := ioutil.ReadFile(target)
+	c.Assert(err, IsNil)
+	c.Assert(string(data), Equals, "file contents")
+	fi, err := os.Stat(target)
+	c.Assert(err, IsNil)
+	c.Assert(fi.Mode().Perm(), Equals, os.FileMode(0640))
+}
+
+func (r *S) TestResumesTransfer(c *C) {
+	dir := c.MkDir()
+	src := filepath.Join(dir, "src")
+	c.Assert(ioutil.WriteFile(src, []byte("file contents"), 0644), IsNil)
+	target := filepath.Join(dir, "target")
+	header, err := client.NewTransferHeader(client.TransferRequest{Path: src, TargetPath: target})
+	c.Assert(err, IsNil)
+	w, err := newTransferWriter(*header)
+	c.Assert(err, IsNil)
+	c.Assert(w.file.Close(), IsNil)
+	// Simulate an interrupted transfer
+	c.Assert(ioutil.WriteFile(w.partialPath, []byte("file"), 0600), IsNil)
+
+	clt := r.newTestClient(c)
+	err = clt.Transfer(context.TODO(), client.TransferRequest{
+		Path:       src,
+		TargetPath: target,
+	})
+	c.Assert(err, IsNil)
+
+	data, err := ioutil.ReadFile(target)
+	c.Assert(err, IsNil)
+	c.Assert(string(data), Equals, "file contents")
+	_, err = os.Stat(w.partialPath)
+	c.Assert(os.IsNotExist(err), Equals, true)
+}
+
+func (r *S) TestRejectsCorruptedTransfer(c *C) {
+	dir := c.MkDir()
+	src := filepath.Join(dir, "src")
+	c.Assert(ioutil.WriteFile(src, []byte("file contents"), 0644), IsNil)
+	target := filepath.Join(dir, "target")
+	header, err := client.NewTransferHeader(client.TransferRequest{Path: src, TargetPath: target})
+	c.Assert(err, IsNil)
+	w, err := newTransferWriter(*header)
+	c.Assert(err, IsNil)
+	c.Assert(w.file.Close(), IsNil)
+	c.Assert(ioutil.WriteFile(w.partialPath, []byte("corrupted"), 0600), IsNil)
+
+	clt := r.newTestClient(c)
+	err = clt.Transfer(context.TODO(), client.TransferRequest{
+		Path:       src,
+		TargetPath: target,
+	})
+	c.Assert(err, NotNil)
+	_, err = os.Stat(target)
+	c.Assert(os.IsNotExist(err), Equals, true)
+	_, err = os.Stat(w.partialPath)
+	c.Assert(os.IsNotExist(err), Equals, true, Commentf("partial file should be removed"))
+}
+
+func (r *S) newTestClient(c *C) client.Client {
+	creds := TestCredentials(c)
+	listener := listen(c)
+	srv, err := New(Config{
+		FieldLogger: r.WithField("server", listener.Addr()),
+		Listener:    listener,
+		Credentials: creds,
+	})
+	c.Assert(err, IsNil)
+	go srv.Serve()
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 5*time.Second)
+	defer cancel()
+	clt, err := client.New(ctx, client.Config{
+		ServerAddr:  srv.Addr().String(),
+		Credentials: creds.Client,
+	})
+	c.Assert(err, IsNil)
+	return clt
+}
@@ -19,6 +19,7 @@ package rpc
 import (
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/pem"
 	"fmt"
 	"io/ioutil"
 	"path/filepath"
@@ -71,6 +72,38 @@ func ValidateCredentials(archive utils.TLSArchive, now time.Time) error {
 	return nil
 }
 
+// VerifyCredentials validates the credentials in the specified archive and
+// verifies that the server and client certificates are signed by a CA from the bundle
+func VerifyCredentials(archive utils.TLSArchive, now time.Time) error {
+	for _, name := range []string{pb.Client, pb.Server, pb.CA} {
+		if archive[name] == nil {
+			return trace.BadParameter("credentials archive is missing %v key pair", name)
+		}
+	}
+	if err := ValidateCredentials(archive, now); err != nil {
+		return trace.Wrap(err)
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(archive[pb.CA].CertPEM) {
+		return trace.BadParameter("failed to decode CA certificate bundle")
+	}
+	for _, name := range []string{pb.Client, pb.Server} {
+		cert, err := tlsca.ParseCertificatePEM(archive[name].CertPEM)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		_, err = cert.Verify(x509.VerifyOptions{
+			Roots:       roots,
+			CurrentTime: now,
+			KeyUsages:   []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+		})
+		if err != nil {
+			return trace.BadParameter("%v certificate is not signed by the CA bundle: %v", name, err)
+		}
+	}
+	return nil
+}
+
 // CredentialsFromPackage reads the specified package as a package with credentials
 func CredentialsFromPackage(packages pack.PackageService, secretsPackage loc.Locator) (tls utils.TLSArchive, err error) {
 	_, reader, err := packages.ReadPackage(secretsPackage)
@@ -96,7 +129,7 @@ func GenerateAgentCredentialsPackage(packages pack.PackageService, pkgTemplate l
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
-	err = upsertPackage(packages, *secretsLocator, archive)
+	err = UpsertCredentialsPackage(packages, *secretsLocator, archive)
 	if err != nil {
 		return secretsLocator, trace.Wrap(err)
 	}
@@ -159,6 +192,54 @@ func GenerateAgentCredentials(hosts []string, commonName string, longLivedClient
 	return archive, nil
 }
 
+// RotateCredentials generates new agent credentials to replace the specified archive.
+//
+// To keep agents that have not yet reloaded their credentials connected, the rotation
+// happens in two steps: trusted keeps the existing client and server certificates but
+// extends the CA bundle with the new CA, while rotated contains the new client and server
+// certificates with the same CA bundle.
+// Only the most recent of the existing CA certificates is kept in the bundle and only
+// if it has not expired yet
+func RotateCredentials(archive utils.TLSArchive, hosts []string, commonName string, longLivedClient bool, now time.Time) (trusted, rotated utils.TLSArchive, err error) {
+	newArchive, err := GenerateAgentCredentials(hosts, commonName, longLivedClient)
+	if err != nil {
+		return nil, nil, trace.Wrap(err)
+	}
+	bundle, err := bundleCA(newArchive[pb.CA].CertPEM, archive[pb.CA].CertPEM, now)
+	if err != nil {
+		return nil, nil, trace.Wrap(err)
+	}
+	trusted = utils.TLSArchive{
+		pb.Server: archive[pb.Server],
+		pb.Client: archive[pb.Client],
+		pb.CA:     &authority.TLSKeyPair{CertPEM: bundle},
+	}
+	rotated = utils.TLSArchive{
+		pb.Server: newArchive[pb.Server],
+		pb.Client: newArchive[pb.Client],
+		pb.CA:     &authority.TLSKeyPair{CertPEM: bundle},
+	}
+	return trusted, rotated, nil
+}
+
+// bundleCA returns the CA bundle with the new CA certificate followed by the most recent
+// CA certificate from the existing bundle unless it has expired
+func bundleCA(newCAPEM, existingCAPEM []byte, now time.Time) ([]byte, error) {
+	block, _ := pem.Decode(existingCAPEM)
+	if block == nil {
+		return nil, trace.BadParameter("failed to decode existing CA certificate")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	bundle := append([]byte{}, newCAPEM...)
+	if now.After(cert.NotAfter) {
+		return bundle, nil
+	}
+	return append(bundle, pem.EncodeToMemory(block)...), nil
+}
+
 // Credentials returns both server and client credentials read from the
 // specified directory
 func Credentials(secretsDir string) (server credentials.TransportCredentials, client credentials.TransportCredentials, err error) {
@@ -335,7 +416,8 @@ func createPackage(packages pack.PackageService, pkg loc.Locator, archive utils.
 }
 
 // upsertPackage creates or updates the secrets package pkg from archive in packages.
-func upsertPackage(packages pack.PackageService, pkg loc.Locator, archive utils.TLSArchive) error {
+// UpsertCredentialsPackage creates or updates the specified package with the credentials archive
+func UpsertCredentialsPackage(packages pack.PackageService, pkg loc.Locator, archive utils.TLSArchive) error {
 	reader, err := utils.CreateTLSArchive(archive)
 	if err != nil {
 		return trace.Wrap(err)
@@ -107,6 +107,50 @@ const (
 	OpsCenterFlavor = "single"
 )
 
+const (
+	// DiskMountEtcd references the etcd data directory in disk requirements
+	DiskMountEtcd = "etcd"
+	// DiskMountDocker references the Docker data directory in disk requirements
+	DiskMountDocker = "docker"
+	// DiskMountState references the gravity state directory in disk requirements
+	DiskMountState = "state"
+
+	// DiskProfileEtcd is the fio profile that mimics etcd write-ahead log:
+	// small sequential writes each followed by fdatasync
+	DiskProfileEtcd = "etcd"
+	// DiskProfileSequentialWrite is the fio profile with large sequential writes
+	DiskProfileSequentialWrite = "sequential-write"
+	// DiskProfileRandomRead is the fio profile with small random reads
+	DiskProfileRandomRead = "random-read"
+	// DiskProfileRandomWrite is the fio profile with small random writes
+	DiskProfileRandomWrite = "random-write"
+
+	// DefaultFsyncPercentile is the default fsync latency percentile
+	// used in disk requirements
+	DefaultFsyncPercentile = 99
+)
+
+// DiskMounts lists the well-known directories that can be referenced
+// by name in disk requirements
+var DiskMounts = []string{
+	DiskMountEtcd,
+	DiskMountDocker,
+	DiskMountState,
+}
+
+// DiskProfiles lists the built-in fio profiles
+var DiskProfiles = []string{
+	DiskProfileEtcd,
+	DiskProfileSequentialWrite,
+	DiskProfileRandomRead,
+	DiskProfileRandomWrite,
+}
+
+// DiskSyncProfiles lists the built-in fio profiles that measure fsync latency
+var DiskSyncProfiles = []string{
+	DiskProfileEtcd,
+}
+
 // ServiceRole defines the type for the node service role
 type ServiceRole string
 
@@ -20,6 +20,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
@@ -30,6 +31,7 @@ import (
 	"github.com/gravitational/gravity/lib/loc"
 	"github.com/gravitational/gravity/lib/utils"
 
+	teleservices "github.com/gravitational/teleport/lib/services"
 	"github.com/gravitational/trace"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -705,6 +707,59 @@ type Requirements struct {
 	Devices []Device `json:"devices,omitempty"`
 	// CustomChecks lists additional preflight checks as inline scripts
 	CustomChecks []CustomCheck `json:"customChecks,omitempty"`
+	// Disks describes disk performance requirements
+	Disks []Disk `json:"disks,omitempty"`
+}
+
+// Disk describes performance requirements for the disk backing a directory
+type Disk struct {
+	// Path is the directory on host. The well-known directories
+	// can be referenced by name: etcd, docker or state
+	Path string `json:"path"`
+	// Profile is the name of the built-in fio benchmark profile
+	// to evaluate the disk with
+	Profile string `json:"profile,omitempty"`
+	// MinIOPS is the minimum required number of I/O operations per second
+	MinIOPS float64 `json:"minIOPS,omitempty"`
+	// MaxFsyncLatency is the maximum allowed fsync latency
+	// at the specified percentile
+	MaxFsyncLatency *teleservices.Duration `json:"maxFsyncLatency,omitempty"`
+	// FsyncPercentile is the fsync latency percentile to compare
+	// with MaxFsyncLatency
+	FsyncPercentile float64 `json:"fsyncPercentile,omitempty"`
+}
+
+// CheckAndSetDefaults validates disk requirements and sets defaults
+func (d *Disk) CheckAndSetDefaults() error {
+	if d.Path == "" {
+		return trace.BadParameter("disk path cannot be empty")
+	}
+	if !utils.StringInSlice(DiskMounts, d.Path) && !filepath.IsAbs(d.Path) {
+		return trace.BadParameter("disk path %q should be absolute or one of %v",
+			d.Path, DiskMounts)
+	}
+	if d.Profile == "" {
+		d.Profile = DiskProfileRandomWrite
+		if d.Path == DiskMountEtcd {
+			d.Profile = DiskProfileEtcd
+		}
+	}
+	if !utils.StringInSlice(DiskProfiles, d.Profile) {
+		return trace.BadParameter("unsupported disk profile %q for %v, supported profiles are %v",
+			d.Profile, d.Path, DiskProfiles)
+	}
+	if d.MaxFsyncLatency != nil && !utils.StringInSlice(DiskSyncProfiles, d.Profile) {
+		return trace.BadParameter("disk profile %q for %v does not measure fsync latency, use one of %v",
+			d.Profile, d.Path, DiskSyncProfiles)
+	}
+	if d.FsyncPercentile < 0 || d.FsyncPercentile > 100 {
+		return trace.BadParameter("fsync latency percentile for %v should be between 0 and 100",
+			d.Path)
+	}
+	if d.FsyncPercentile == 0 {
+		d.FsyncPercentile = DefaultFsyncPercentile
+	}
+	return nil
 }
 
 // Device describes a device that should be created inside container
@@ -821,6 +876,17 @@ type Network struct {
 	MinTransferRate utils.TransferRate `json:"minTransferRate,omitempty"`
 	// Ports specifies port ranges that should be available on the server
 	Ports []Port `json:"ports,omitempty"`
+	// MinMTU is the minimum required path MTU from the server to other servers
+	MinMTU int `json:"minMTU,omitempty"`
+	// MaxRTT is the maximum allowed 99th percentile of round-trip time
+	// from the server to other servers
+	MaxRTT *teleservices.Duration `json:"maxRTT,omitempty"`
+	// MaxJitter is the maximum allowed 99th percentile of jitter
+	// from the server to other servers
+	MaxJitter *teleservices.Duration `json:"maxJitter,omitempty"`
+	// MaxPacketLoss is the maximum allowed UDP packet loss, in percent,
+	// from the server to other servers
+	MaxPacketLoss float64 `json:"maxPacketLoss,omitempty"`
 }
 
 // Port describes port ranges
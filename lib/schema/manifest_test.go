@@ -18,6 +18,7 @@ package schema
 
 import (
 	"testing"
+	"time"
 
 	"github.com/gravitational/gravity/lib/compare"
 	"github.com/gravitational/gravity/lib/constants"
@@ -25,6 +26,7 @@ import (
 	"github.com/gravitational/gravity/lib/loc"
 	"github.com/gravitational/gravity/lib/utils"
 
+	teleservices "github.com/gravitational/teleport/lib/services"
 	"github.com/gravitational/trace"
 	. "gopkg.in/check.v1"
 	"k8s.io/api/core/v1"
@@ -101,6 +103,10 @@ nodeProfiles:
           versions: ["7.2", "7.3"]
       network:
         minTransferRate: "50MB/s"
+        minMTU: 1450
+        maxRTT: "5ms"
+        maxJitter: "2ms"
+        maxPacketLoss: 0.5
         ports:
           - protocol: tcp
             ranges:
@@ -254,6 +260,10 @@ systemOptions:
 				},
 				Network: Network{
 					MinTransferRate: utils.MustParseTransferRate("50MB/s"),
+					MinMTU:          1450,
+					MaxRTT:          &teleservices.Duration{Duration: 5 * time.Millisecond},
+					MaxJitter:       &teleservices.Duration{Duration: 2 * time.Millisecond},
+					MaxPacketLoss:   0.5,
 					Ports: []Port{
 						{
 							Protocol: "tcp",
@@ -488,6 +498,60 @@ nodeProfiles:
 	c.Assert(err, NotNil)
 }
 
+func (s *ManifestSuite) TestDiskRequirements(c *C) {
+	bytes := []byte(`apiVersion: bundle.gravitational.io/v2
+kind: Bundle
+metadata:
+  name: myapp
+  resourceVersion: 0.0.1
+installer:
+  flavors:
+    items:
+      - name: one
+        nodes:
+          - profile: node
+            count: 1
+nodeProfiles:
+  - name: node
+    requirements:
+      disks:
+      - path: etcd
+        maxFsyncLatency: 10ms
+      - path: /var/lib/data
+        minIOPS: 1000`)
+	manifest, err := ParseManifestYAML(bytes)
+	c.Assert(err, IsNil)
+	disks := manifest.NodeProfiles[0].Requirements.Disks
+	c.Assert(disks, HasLen, 2)
+	c.Assert(disks[0].Profile, Equals, DiskProfileEtcd)
+	c.Assert(disks[0].FsyncPercentile, Equals, float64(DefaultFsyncPercentile))
+	c.Assert(disks[1].Profile, Equals, DiskProfileRandomWrite)
+}
+
+func (s *ManifestSuite) TestInvalidDiskRequirement(c *C) {
+	bytes := []byte(`apiVersion: bundle.gravitational.io/v2
+kind: Bundle
+metadata:
+  name: myapp
+  resourceVersion: 0.0.1
+installer:
+  flavors:
+    items:
+      - name: one
+        nodes:
+          - profile: node
+            count: 1
+nodeProfiles:
+  - name: node
+    requirements:
+      disks:
+      - path: docker
+        profile: random-read
+        maxFsyncLatency: 10ms`)
+	_, err := ParseManifestYAML(bytes)
+	c.Assert(err, NotNil)
+}
+
 func (s *ManifestSuite) TestFlavorRequiredIfProfileDefined(c *C) {
 	bytes := []byte(`apiVersion: bundle.gravitational.io/v2
 kind: Bundle
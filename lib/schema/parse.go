@@ -160,6 +160,11 @@ func CheckAndSetDefaults(manifest *Manifest) error {
 				errors = append(errors, err)
 			}
 		}
+		for j := range nodeProfile.Requirements.Disks {
+			if err := manifest.NodeProfiles[i].Requirements.Disks[j].CheckAndSetDefaults(); err != nil {
+				errors = append(errors, err)
+			}
+		}
 	}
 
 	if manifest.SystemOptions != nil {
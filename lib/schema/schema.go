@@ -205,6 +205,10 @@ const manifestSchema = `
                     "additionalProperties": false,
                     "properties": {
                       "minTransferRate": {"type": "string"},
+                      "minMTU": {"type": "number"},
+                      "maxRTT": {"type": "string"},
+                      "maxJitter": {"type": "string"},
+                      "maxPacketLoss": {"type": "number"},
                       "ports": {
                         "type": "array",
                         "items": {
@@ -272,6 +276,21 @@ const manifestSchema = `
                         "script": {"type": "string"}
                       }
                     }
+                  },
+                  "disks": {
+                    "type": "array",
+                    "items": {
+                      "type": "object",
+                      "required": ["path"],
+                      "additionalProperties": false,
+                      "properties": {
+                        "path": {"type": "string"},
+                        "profile": {"type": "string"},
+                        "minIOPS": {"type": "number"},
+                        "maxFsyncLatency": {"type": "string"},
+                        "fsyncPercentile": {"type": "number"}
+                      }
+                    }
                   }
                 }
               },
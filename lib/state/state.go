@@ -109,6 +109,11 @@ func InEtcdDir(baseDir, filename string) string {
 	return filepath.Join(baseDir, defaults.PlanetDir, defaults.EtcdDir, filename)
 }
 
+// DockerDir returns full path to the planet docker data directory
+func DockerDir(baseDir string) string {
+	return filepath.Join(baseDir, defaults.PlanetDir, defaults.DockerDir)
+}
+
 // LogDir returns full path to the planet log directory
 func LogDir(baseDir string, suffixes ...string) string {
 	elems := []string{baseDir, defaults.PlanetDir, defaults.LogDir}
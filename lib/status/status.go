@@ -24,6 +24,7 @@ import (
 	"net/url"
 	"time"
 
+	"github.com/gravitational/gravity/lib/checks/background"
 	"github.com/gravitational/gravity/lib/constants"
 	"github.com/gravitational/gravity/lib/defaults"
 	"github.com/gravitational/gravity/lib/httplib"
@@ -33,12 +34,15 @@ import (
 	"github.com/gravitational/gravity/lib/storage"
 	"github.com/gravitational/gravity/lib/utils"
 
+	"github.com/gravitational/rigging"
 	"github.com/gravitational/roundtrip"
 	pb "github.com/gravitational/satellite/agent/proto/agentpb"
 	"github.com/gravitational/satellite/monitoring"
 	"github.com/gravitational/trace"
 	"github.com/prometheus/alertmanager/api/v2/models"
 	"github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // FromCluster collects cluster status information.
@@ -129,6 +133,16 @@ func FromCluster(ctx context.Context, operator ops.Operator, cluster ops.Site, o
 		return status, trace.Wrap(err, "failed to collect system status from agents")
 	}
 
+	err = markMaintenanceNodes(status.Agent.Nodes, cluster)
+	if err != nil {
+		logrus.WithError(err).Warn("Failed to query nodes in maintenance mode.")
+	}
+
+	err = markFailedChecks(status.Agent.Nodes, cluster)
+	if err != nil && !trace.IsNotFound(err) {
+		logrus.WithError(err).Warn("Failed to query background checks report.")
+	}
+
 	status.State = cluster.State
 
 	// Collect information from alertmanager
@@ -362,6 +376,10 @@ type ClusterServer struct {
 	FailedProbes []string `json:"failed_probes,omitempty"`
 	// WarnProbes lists all warning probes
 	WarnProbes []string `json:"warn_probes,omitempty"`
+	// Maintenance indicates whether the node is in maintenance mode
+	Maintenance bool `json:"maintenance,omitempty"`
+	// FailedChecks lists background checks that failed on the node
+	FailedChecks []string `json:"failed_checks,omitempty"`
 }
 
 func (r ClusterOperation) isFailed() bool {
@@ -419,6 +437,69 @@ func fromClusterState(systemStatus pb.SystemStatus, cluster []storage.Server) (o
 	return out
 }
 
+// markMaintenanceNodes marks the nodes that have been put into maintenance mode
+func markMaintenanceNodes(nodes []ClusterServer, cluster ops.Site) error {
+	client, _, err := httplib.GetClusterKubeClient(cluster.DNSConfig.Addr())
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	kubeNodes, err := client.CoreV1().Nodes().List(metav1.ListOptions{
+		LabelSelector: utils.MakeSelector(map[string]string{
+			defaults.MaintenanceLabel: constants.True,
+		}).String(),
+	})
+	if err != nil {
+		return trace.Wrap(rigging.ConvertError(err))
+	}
+	setMaintenance(nodes, kubeNodes.Items)
+	return nil
+}
+
+// setMaintenance marks the nodes matching the specified Kubernetes nodes
+// as being in maintenance mode
+func setMaintenance(nodes []ClusterServer, kubeNodes []v1.Node) {
+	maintenance := make(map[string]struct{}, len(kubeNodes))
+	for _, node := range kubeNodes {
+		maintenance[node.Labels[defaults.KubernetesAdvertiseIPLabel]] = struct{}{}
+	}
+	for i := range nodes {
+		if _, ok := maintenance[nodes[i].AdvertiseIP]; ok {
+			nodes[i].Maintenance = true
+		}
+	}
+}
+
+// markFailedChecks sets the failed background checks on the nodes
+// from the last background checks report
+func markFailedChecks(nodes []ClusterServer, cluster ops.Site) error {
+	client, _, err := httplib.GetClusterKubeClient(cluster.DNSConfig.Addr())
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	report, err := background.GetReport(client)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	setFailedChecks(nodes, *report)
+	return nil
+}
+
+// setFailedChecks sets the failed checks from the report
+// on the matching nodes
+func setFailedChecks(nodes []ClusterServer, report background.Report) {
+	failed := make(map[string][]string, len(report.Nodes))
+	for _, node := range report.Nodes {
+		for _, check := range node.Checks {
+			if check.Failed() {
+				failed[node.AdvertiseIP] = append(failed[node.AdvertiseIP], check.String())
+			}
+		}
+	}
+	for i := range nodes {
+		nodes[i].FailedChecks = failed[nodes[i].AdvertiseIP]
+	}
+}
+
 func planetAgentStatus(ctx context.Context, local bool) (*pb.SystemStatus, error) {
 	urlFormat := "https://%v:%v"
 	if local {
@@ -0,0 +1,119 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"time"
+
+	"github.com/gravitational/gravity/lib/utils"
+
+	"github.com/gravitational/trace"
+)
+
+// AccessRequests manages requests for temporary access to privileged roles
+type AccessRequests interface {
+	// CreateAccessRequest creates a new access request
+	CreateAccessRequest(AccessRequest) (*AccessRequest, error)
+	// UpdateAccessRequest updates an existing access request
+	UpdateAccessRequest(AccessRequest) (*AccessRequest, error)
+	// GetAccessRequest returns the access request by ID
+	GetAccessRequest(id string) (*AccessRequest, error)
+	// GetAccessRequests returns all access requests
+	GetAccessRequests() ([]AccessRequest, error)
+	// DeleteAccessRequest deletes the access request by ID
+	DeleteAccessRequest(id string) error
+}
+
+// AccessRequest is a request of a user to temporarily assume
+// privileged roles that needs to be approved by another user
+type AccessRequest struct {
+	// ID uniquely identifies the request
+	ID string `json:"id"`
+	// User is the name of the user requesting access
+	User string `json:"user"`
+	// Roles lists the requested roles
+	Roles []string `json:"roles"`
+	// Reason is the justification for the request
+	Reason string `json:"reason,omitempty"`
+	// Duration is how long the access is requested for
+	Duration time.Duration `json:"duration"`
+	// State is the request state, see AccessRequestState* constants
+	State string `json:"state"`
+	// Created is when the request has been created
+	Created time.Time `json:"created"`
+	// Reviewer is the name of the user who approved or denied the request
+	Reviewer string `json:"reviewer,omitempty"`
+	// Reviewed is when the request has been approved or denied
+	Reviewed time.Time `json:"reviewed,omitempty"`
+	// Expires is when the granted access expires
+	Expires time.Time `json:"expires,omitempty"`
+	// GrantedRoles lists the requested roles the user did not have
+	// at the time of the approval and that are revoked upon expiration
+	GrantedRoles []string `json:"granted_roles,omitempty"`
+}
+
+// Check validates the access request
+func (r AccessRequest) Check() error {
+	if r.ID == "" {
+		return trace.BadParameter("missing access request ID")
+	}
+	if err := utils.CheckUserName(r.User); err != nil {
+		return trace.Wrap(err)
+	}
+	if len(r.Roles) == 0 {
+		return trace.BadParameter("roles can't be empty")
+	}
+	if r.Duration <= 0 {
+		return trace.BadParameter("duration must be positive")
+	}
+	if !utils.StringInSlice(AccessRequestStates, r.State) {
+		return trace.BadParameter("unknown access request state %q, expected one of %v",
+			r.State, AccessRequestStates)
+	}
+	return nil
+}
+
+// IsPending returns true if the request is awaiting review
+func (r AccessRequest) IsPending() bool {
+	return r.State == AccessRequestStatePending
+}
+
+// IsActive returns true if the request has been approved and
+// the granted access has not expired by the specified time
+func (r AccessRequest) IsActive(now time.Time) bool {
+	return r.State == AccessRequestStateApproved && now.Before(r.Expires)
+}
+
+const (
+	// AccessRequestStatePending is the state of a request awaiting review
+	AccessRequestStatePending = "pending"
+	// AccessRequestStateApproved is the state of an approved request
+	AccessRequestStateApproved = "approved"
+	// AccessRequestStateDenied is the state of a denied request
+	AccessRequestStateDenied = "denied"
+	// AccessRequestStateExpired is the state of an approved request
+	// whose access has expired
+	AccessRequestStateExpired = "expired"
+)
+
+// AccessRequestStates lists all access request states
+var AccessRequestStates = []string{
+	AccessRequestStatePending,
+	AccessRequestStateApproved,
+	AccessRequestStateDenied,
+	AccessRequestStateExpired,
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/gravitational/trace"
+)
+
+// AuditRecords stores the audit log as a chain of records where
+// each record includes the hash of the previous one so any modification
+// or removal of a record can be detected, see VerifyAuditRecords
+type AuditRecords interface {
+	// AppendAuditRecord chains the provided record to the last record
+	// of the audit log and saves it
+	AppendAuditRecord(AuditRecord) (*AuditRecord, error)
+	// GetAuditRecords returns the audit records matching the filter
+	// ordered by index
+	GetAuditRecords(AuditRecordsFilter) ([]AuditRecord, error)
+	// GetLastAuditRecord returns the last appended record. The record may lag
+	// behind the actual last record if records are appended concurrently
+	GetLastAuditRecord() (*AuditRecord, error)
+	// PruneAuditRecords removes the records created before the specified time
+	// and returns the number of removed records
+	PruneAuditRecords(before time.Time) (int, error)
+	// GetAuditCursors returns the index of the last record sent to each of
+	// the log forwarders by name
+	GetAuditCursors() (map[string]uint64, error)
+	// UpsertAuditCursor saves the index of the last record sent to the log forwarder
+	UpsertAuditCursor(forwarder string, index uint64) error
+	// DeleteAuditCursor deletes the cursor of the log forwarder
+	DeleteAuditCursor(forwarder string) error
+	// AddPendingAuditRecord marks the record with the specified index as
+	// not yet emitted to the Teleport audit log
+	AddPendingAuditRecord(index uint64) error
+	// GetPendingAuditRecords returns the indexes of the records that have
+	// not been emitted to the Teleport audit log
+	GetPendingAuditRecords() ([]uint64, error)
+	// DeletePendingAuditRecord removes the mark from the record with the specified index
+	DeletePendingAuditRecord(index uint64) error
+}
+
+// AuditRecordsFilter defines the range of audit records to return
+type AuditRecordsFilter struct {
+	// FromIndex, if set, excludes records with lower indexes
+	FromIndex uint64
+	// Since, if set, excludes records created before it
+	Since time.Time
+	// Until, if set, excludes records created after it
+	Until time.Time
+	// Limit, if set, is the maximum number of records to return
+	Limit int
+}
+
+// Matches returns true if the provided record is within the filter range
+func (f AuditRecordsFilter) Matches(record AuditRecord) bool {
+	if record.Index < f.FromIndex {
+		return false
+	}
+	if !f.Since.IsZero() && record.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && record.Time.After(f.Until) {
+		return false
+	}
+	return true
+}
+
+// AuditRecord is a single hash-chained audit log record
+type AuditRecord struct {
+	// Index is the record position in the chain, starting from 1
+	Index uint64 `json:"index"`
+	// Time is when the event has been emitted
+	Time time.Time `json:"time"`
+	// Event is the audit event name
+	Event string `json:"event"`
+	// Code is the audit event code
+	Code string `json:"code,omitempty"`
+	// Fields is the audit event fields
+	Fields map[string]interface{} `json:"fields,omitempty"`
+	// PrevHash is the hash of the previous record, empty for the first record
+	PrevHash string `json:"prev_hash"`
+	// Hash is the SHA-256 hash of this record including PrevHash
+	Hash string `json:"hash"`
+}
+
+// NewAuditRecord returns a new unchained audit record for the provided event
+func NewAuditRecord(event, code string, fields map[string]interface{}, now time.Time) (*AuditRecord, error) {
+	record := AuditRecord{
+		Time:  now.UTC(),
+		Event: event,
+		Code:  code,
+	}
+	if len(fields) != 0 {
+		// fields are normalized to their JSON representation so the hash
+		// does not change after the record has been saved and read back
+		data, err := json.Marshal(fields)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		if err := json.Unmarshal(data, &record.Fields); err != nil {
+			return nil, trace.Wrap(err)
+		}
+	}
+	return &record, nil
+}
+
+// Check validates the audit record
+func (r AuditRecord) Check() error {
+	if r.Event == "" {
+		return trace.BadParameter("missing audit event name")
+	}
+	if r.Time.IsZero() {
+		return trace.BadParameter("missing audit record time")
+	}
+	return nil
+}
+
+// ComputeHash returns the hash of the record contents and the previous record hash
+func (r AuditRecord) ComputeHash() (string, error) {
+	r.Hash = ""
+	data, err := json.Marshal(r)
+	if err != nil {
+		return "", trace.Wrap(err)
+	}
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// Chain links the record to the provided previous record and updates
+// its index and hash. A nil prev makes this the first record of the chain
+func (r *AuditRecord) Chain(prev *AuditRecord) (err error) {
+	r.Index = 1
+	r.PrevHash = ""
+	if prev != nil {
+		r.Index = prev.Index + 1
+		r.PrevHash = prev.Hash
+	}
+	r.Hash, err = r.ComputeHash()
+	return trace.Wrap(err)
+}
+
+// VerifyAuditRecords checks the integrity of the provided consecutive
+// audit records. The records do not have to start from the beginning
+// of the chain, in which case the first record is trusted to link
+// to the records that precede it
+func VerifyAuditRecords(records []AuditRecord) error {
+	for i, record := range records {
+		hash, err := record.ComputeHash()
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		if hash != record.Hash {
+			return trace.CompareFailed("audit record %v has been modified", record.Index)
+		}
+		if i == 0 {
+			if record.Index == 1 && record.PrevHash != "" {
+				return trace.CompareFailed("audit record 1 links to a previous record")
+			}
+			continue
+		}
+		prev := records[i-1]
+		if record.Index != prev.Index+1 {
+			return trace.CompareFailed("audit record %v does not follow record %v",
+				record.Index, prev.Index)
+		}
+		if record.PrevHash != prev.Hash {
+			return trace.CompareFailed("audit record %v does not link to record %v",
+				record.Index, prev.Index)
+		}
+	}
+	return nil
+}
@@ -39,6 +39,8 @@ type Interface interface {
 	GetKubeletConfig() *Kubelet
 	// GetGlobalConfig returns the global configuration
 	GetGlobalConfig() *Global
+	// GetDockerConfig returns the Docker configuration
+	GetDockerConfig() *Docker
 	// SetCloudProvider sets the cloud provider for this configuration
 	SetCloudProvider(provider string)
 }
@@ -109,6 +111,11 @@ func (r *Resource) GetGlobalConfig() *Global {
 	return r.Spec.Global
 }
 
+// GetDockerConfig returns the Docker configuration
+func (r *Resource) GetDockerConfig() *Docker {
+	return r.Spec.Docker
+}
+
 // SetCloudProvider sets the cloud provider for this configuration
 func (r *Resource) SetCloudProvider(provider string) {
 	if r.Spec.Global == nil {
@@ -180,6 +187,8 @@ type Spec struct {
 	// TODO: Scheduler, ControllerManager, Proxy
 	// Global describes global configuration
 	Global *Global `json:"global,omitempty"`
+	// Docker describes Docker configuration
+	Docker *Docker `json:"docker,omitempty"`
 }
 
 // ComponentsConfigs groups component configurations
@@ -230,6 +239,20 @@ type Global struct {
 	FeatureGates map[string]bool `json:"featureGates,omitempty"`
 }
 
+// Docker describes Docker configuration
+type Docker struct {
+	// StorageDriver specifies the Docker storage driver.
+	// Changing the driver from devicemapper to overlay2 migrates the Docker
+	// device on each node
+	StorageDriver string `json:"storageDriver,omitempty"`
+	// Filesystem specifies the filesystem to format the Docker device with
+	// when migrating to overlay2: xfs (default) or ext4
+	Filesystem string `json:"filesystem,omitempty"`
+	// Quota specifies whether to enable project quotas on the Docker device
+	// when migrating to overlay2
+	Quota bool `json:"quota,omitempty"`
+}
+
 // specSchemaTemplate is JSON schema for the cluster configuration resource
 const specSchemaTemplate = `{
   "type": "object",
@@ -277,6 +300,15 @@ const specSchemaTemplate = `{
             }
           }
         },
+        "docker": {
+          "type": "object",
+          "additionalProperties": false,
+          "properties": {
+            "storageDriver": {"type": "string", "enum": ["overlay2"]},
+            "filesystem": {"type": "string", "enum": ["xfs", "ext4"]},
+            "quota": {"type": "boolean"}
+          }
+        },
         "kubelet": {
           "type": "object",
           "additionalProperties": false,
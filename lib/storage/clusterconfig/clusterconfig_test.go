@@ -103,6 +103,31 @@ password=pass`,
 		{
 			in: `kind: clusterconfiguration
 version: v1
+spec:
+  docker:
+    storageDriver: overlay2
+    filesystem: ext4
+    quota: true`,
+			resource: &Resource{
+				Kind:    storage.KindClusterConfiguration,
+				Version: "v1",
+				Metadata: teleservices.Metadata{
+					Name:      constants.ClusterConfigurationMap,
+					Namespace: defaults.KubeSystemNamespace,
+				},
+				Spec: Spec{
+					Docker: &Docker{
+						StorageDriver: "overlay2",
+						Filesystem:    "ext4",
+						Quota:         true,
+					},
+				},
+			},
+			comment: "parses docker configuration",
+		},
+		{
+			in: `kind: clusterconfiguration
+version: v1
 spec:
   kubelet:
     extraArgs: ['--foo', '--bar=baz']
@@ -0,0 +1,97 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keyval
+
+import (
+	"github.com/gravitational/gravity/lib/storage"
+	"github.com/gravitational/gravity/lib/utils"
+
+	"github.com/gravitational/trace"
+)
+
+func (b *backend) CreateAccessRequest(req storage.AccessRequest) (*storage.AccessRequest, error) {
+	if err := req.Check(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	err := b.createVal(b.key(accessRequestsP, req.ID), req, forever)
+	if err != nil {
+		if trace.IsAlreadyExists(err) {
+			return nil, trace.AlreadyExists("access request(%v) already exists", req.ID)
+		}
+		return nil, trace.Wrap(err)
+	}
+	return &req, nil
+}
+
+func (b *backend) UpdateAccessRequest(req storage.AccessRequest) (*storage.AccessRequest, error) {
+	if err := req.Check(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	err := b.updateVal(b.key(accessRequestsP, req.ID), req, forever)
+	if err != nil {
+		if trace.IsNotFound(err) {
+			return nil, trace.NotFound("access request(%v) not found", req.ID)
+		}
+		return nil, trace.Wrap(err)
+	}
+	return &req, nil
+}
+
+func (b *backend) GetAccessRequest(id string) (*storage.AccessRequest, error) {
+	var req storage.AccessRequest
+	err := b.getVal(b.key(accessRequestsP, id), &req)
+	if err != nil {
+		if trace.IsNotFound(err) {
+			return nil, trace.NotFound("access request(%v) not found", id)
+		}
+		return nil, trace.Wrap(err)
+	}
+	utils.UTC(&req.Created)
+	utils.UTC(&req.Reviewed)
+	utils.UTC(&req.Expires)
+	return &req, nil
+}
+
+func (b *backend) GetAccessRequests() ([]storage.AccessRequest, error) {
+	ids, err := b.getKeys(b.key(accessRequestsP))
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var out []storage.AccessRequest
+	for _, id := range ids {
+		req, err := b.GetAccessRequest(id)
+		if err != nil {
+			if trace.IsNotFound(err) {
+				continue
+			}
+			return nil, trace.Wrap(err)
+		}
+		out = append(out, *req)
+	}
+	return out, nil
+}
+
+func (b *backend) DeleteAccessRequest(id string) error {
+	err := b.deleteKey(b.key(accessRequestsP, id))
+	if err != nil {
+		if trace.IsNotFound(err) {
+			return trace.NotFound("access request(%v) not found", id)
+		}
+		return trace.Wrap(err)
+	}
+	return nil
+}
@@ -61,6 +61,8 @@ func (b *backend) GetAPIKeys(email string) ([]storage.APIKey, error) {
 			return nil, trace.Wrap(err)
 		}
 		utils.UTC(&apikey.Expires)
+		utils.UTC(&apikey.Created)
+		utils.UTC(&apikey.LastUsed)
 		out = append(out, apikey)
 	}
 	return out, nil
@@ -0,0 +1,217 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keyval
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gravitational/gravity/lib/storage"
+	"github.com/gravitational/gravity/lib/utils"
+
+	"github.com/gravitational/trace"
+)
+
+func (b *backend) AppendAuditRecord(record storage.AuditRecord) (*storage.AuditRecord, error) {
+	if err := record.Check(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	// head is only a hint about the last record, it may lag behind
+	// if records are appended concurrently
+	var prev *storage.AuditRecord
+	var head storage.AuditRecord
+	err := b.getVal(b.key(auditP, auditHeadP), &head)
+	if err != nil && !trace.IsNotFound(err) {
+		return nil, trace.Wrap(err)
+	}
+	if err == nil {
+		prev = &head
+	}
+	for {
+		if err := record.Chain(prev); err != nil {
+			return nil, trace.Wrap(err)
+		}
+		err := b.createVal(b.auditRecordKey(record.Index), record, forever)
+		if err == nil {
+			break
+		}
+		if !trace.IsAlreadyExists(err) {
+			return nil, trace.Wrap(err)
+		}
+		// the index has already been taken, chain to the existing record
+		prev, err = b.getAuditRecord(record.Index)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+	}
+	if err := b.upsertVal(b.key(auditP, auditHeadP), record, forever); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return &record, nil
+}
+
+func (b *backend) GetAuditRecords(filter storage.AuditRecordsFilter) ([]storage.AuditRecord, error) {
+	var out []storage.AuditRecord
+	err := b.getRange(b.key(auditP, auditRecordsP), auditRecordName(filter.FromIndex),
+		func(key string, decode func(interface{}) error) (bool, error) {
+			var record storage.AuditRecord
+			if err := decode(&record); err != nil {
+				return false, trace.Wrap(err)
+			}
+			utils.UTC(&record.Time)
+			if filter.Matches(record) {
+				out = append(out, record)
+			}
+			return filter.Limit == 0 || len(out) < filter.Limit, nil
+		})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return out, nil
+}
+
+func (b *backend) GetLastAuditRecord() (*storage.AuditRecord, error) {
+	var record storage.AuditRecord
+	err := b.getVal(b.key(auditP, auditHeadP), &record)
+	if err != nil {
+		if trace.IsNotFound(err) {
+			return nil, trace.NotFound("audit log is empty")
+		}
+		return nil, trace.Wrap(err)
+	}
+	utils.UTC(&record.Time)
+	return &record, nil
+}
+
+func (b *backend) PruneAuditRecords(before time.Time) (int, error) {
+	var names []string
+	err := b.getRange(b.key(auditP, auditRecordsP), "",
+		func(key string, decode func(interface{}) error) (bool, error) {
+			var record storage.AuditRecord
+			if err := decode(&record); err != nil {
+				return false, trace.Wrap(err)
+			}
+			// records are appended in order, so the rest are newer
+			if !record.Time.Before(before) {
+				return false, nil
+			}
+			names = append(names, key)
+			return true, nil
+		})
+	if err != nil {
+		return 0, trace.Wrap(err)
+	}
+	for i, name := range names {
+		err := b.deleteKey(b.key(auditP, auditRecordsP, name))
+		if err != nil && !trace.IsNotFound(err) {
+			return i, trace.Wrap(err)
+		}
+	}
+	return len(names), nil
+}
+
+func (b *backend) GetAuditCursors() (map[string]uint64, error) {
+	cursors := make(map[string]uint64)
+	err := b.getRange(b.key(auditP, auditCursorsP), "",
+		func(key string, decode func(interface{}) error) (bool, error) {
+			var index uint64
+			if err := decode(&index); err != nil {
+				return false, trace.Wrap(err)
+			}
+			cursors[key] = index
+			return true, nil
+		})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return cursors, nil
+}
+
+func (b *backend) UpsertAuditCursor(forwarder string, index uint64) error {
+	if forwarder == "" {
+		return trace.BadParameter("missing log forwarder name")
+	}
+	err := b.upsertVal(b.key(auditP, auditCursorsP, forwarder), index, forever)
+	return trace.Wrap(err)
+}
+
+func (b *backend) DeleteAuditCursor(forwarder string) error {
+	err := b.deleteKey(b.key(auditP, auditCursorsP, forwarder))
+	if err != nil {
+		if trace.IsNotFound(err) {
+			return trace.NotFound("audit cursor for %v not found", forwarder)
+		}
+		return trace.Wrap(err)
+	}
+	return nil
+}
+
+func (b *backend) AddPendingAuditRecord(index uint64) error {
+	err := b.upsertVal(b.key(auditP, auditPendingP, auditRecordName(index)), index, forever)
+	return trace.Wrap(err)
+}
+
+func (b *backend) GetPendingAuditRecords() ([]uint64, error) {
+	var indexes []uint64
+	err := b.getRange(b.key(auditP, auditPendingP), "",
+		func(key string, decode func(interface{}) error) (bool, error) {
+			var index uint64
+			if err := decode(&index); err != nil {
+				return false, trace.Wrap(err)
+			}
+			indexes = append(indexes, index)
+			return true, nil
+		})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return indexes, nil
+}
+
+func (b *backend) DeletePendingAuditRecord(index uint64) error {
+	err := b.deleteKey(b.key(auditP, auditPendingP, auditRecordName(index)))
+	if err != nil {
+		if trace.IsNotFound(err) {
+			return trace.NotFound("pending audit record(%v) not found", index)
+		}
+		return trace.Wrap(err)
+	}
+	return nil
+}
+
+func (b *backend) getAuditRecord(index uint64) (*storage.AuditRecord, error) {
+	var record storage.AuditRecord
+	err := b.getVal(b.auditRecordKey(index), &record)
+	if err != nil {
+		if trace.IsNotFound(err) {
+			return nil, trace.NotFound("audit record(%v) not found", index)
+		}
+		return nil, trace.Wrap(err)
+	}
+	utils.UTC(&record.Time)
+	return &record, nil
+}
+
+func (b *backend) auditRecordKey(index uint64) key {
+	return b.key(auditP, auditRecordsP, auditRecordName(index))
+}
+
+// auditRecordName returns the key name of the audit record with the specified
+// index. Indexes are zero-padded so the keys are ordered by index
+func auditRecordName(index uint64) string {
+	return fmt.Sprintf("%020d", index)
+}
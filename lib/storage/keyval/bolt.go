@@ -572,6 +572,35 @@ func (b *blt) getKeys(key key) ([]string, error) {
 	return out, nil
 }
 
+func (b *blt) getRange(key key, from string, fn rangeFn) error {
+	return b.db.View(func(tx *bolt.Tx) error {
+		bkt, err := getBucket(tx, key)
+		if err != nil {
+			if trace.IsNotFound(err) {
+				return nil
+			}
+			return trace.Wrap(err)
+		}
+		c := bkt.Cursor()
+		for k, v := c.Seek([]byte(from)); k != nil; k, v = c.Next() {
+			if v == nil {
+				// nested bucket
+				continue
+			}
+			next, err := fn(string(k), func(val interface{}) error {
+				return b.codec.DecodeFromBytes(v, val)
+			})
+			if err != nil {
+				return trace.Wrap(err)
+			}
+			if !next {
+				return nil
+			}
+		}
+		return nil
+	})
+}
+
 // Close closes the backend resources
 func (b *blt) Close() error {
 	b.Lock()
@@ -237,6 +237,30 @@ func (s *BSuite) TestClusterLogin(c *C) {
 	s.suite.ClusterLogin(c)
 }
 
+func (s *BSuite) TestMFAPolicyCRUD(c *C) {
+	s.suite.MFAPolicyCRUD(c)
+}
+
+func (s *BSuite) TestAccessRequestsCRUD(c *C) {
+	s.suite.AccessRequestsCRUD(c)
+}
+
+func (s *BSuite) TestAuditRecordsCRUD(c *C) {
+	s.suite.AuditRecordsCRUD(c)
+}
+
+func (s *BSuite) TestAuditRecordsRetention(c *C) {
+	s.suite.AuditRecordsRetention(c)
+}
+
+func (s *BSuite) TestAuditCursorsCRUD(c *C) {
+	s.suite.AuditCursorsCRUD(c)
+}
+
+func (s *BSuite) TestPasswordPolicyCRUD(c *C) {
+	s.suite.PasswordPolicyCRUD(c)
+}
+
 func (s *BSuite) TestIndexFile(c *C) {
 	s.suite.IndexFile(c)
 }
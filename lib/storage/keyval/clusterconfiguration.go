@@ -17,6 +17,8 @@ limitations under the License.
 package keyval
 
 import (
+	"github.com/gravitational/gravity/lib/storage"
+
 	"github.com/gravitational/trace"
 
 	teleservices "github.com/gravitational/teleport/lib/services"
@@ -84,7 +86,72 @@ func (b *backend) UpsertAuthPreference(authP teleservices.AuthPreference) error
 	return nil
 }
 
-// GetStaticTokens returns the list of static tokens
+// GetMFAPolicy returns the cluster second factor policy
+func (b *backend) GetMFAPolicy() (storage.MFAPolicy, error) {
+	data, err := b.getValBytes(b.key(mfaPolicyP, valP))
+	if err != nil {
+		if trace.IsNotFound(err) {
+			return nil, trace.NotFound("second factor policy not found")
+		}
+		return nil, trace.Wrap(err)
+	}
+	return storage.UnmarshalMFAPolicy(data)
+}
+
+// UpsertMFAPolicy creates or updates the cluster second factor policy
+func (b *backend) UpsertMFAPolicy(policy storage.MFAPolicy) error {
+	data, err := storage.MarshalMFAPolicy(policy)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	return trace.Wrap(b.upsertValBytes(b.key(mfaPolicyP, valP), data, forever))
+}
+
+// DeleteMFAPolicy deletes the cluster second factor policy
+func (b *backend) DeleteMFAPolicy() error {
+	err := b.deleteKey(b.key(mfaPolicyP, valP))
+	if err != nil {
+		if trace.IsNotFound(err) {
+			return trace.NotFound("second factor policy not found")
+		}
+		return trace.Wrap(err)
+	}
+	return nil
+}
+
+// GetPasswordPolicy returns the cluster password policy
+func (b *backend) GetPasswordPolicy() (storage.PasswordPolicy, error) {
+	data, err := b.getValBytes(b.key(passwordPolicyP, valP))
+	if err != nil {
+		if trace.IsNotFound(err) {
+			return nil, trace.NotFound("password policy not found")
+		}
+		return nil, trace.Wrap(err)
+	}
+	return storage.UnmarshalPasswordPolicy(data)
+}
+
+// UpsertPasswordPolicy creates or updates the cluster password policy
+func (b *backend) UpsertPasswordPolicy(policy storage.PasswordPolicy) error {
+	data, err := storage.MarshalPasswordPolicy(policy)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	return trace.Wrap(b.upsertValBytes(b.key(passwordPolicyP, valP), data, forever))
+}
+
+// DeletePasswordPolicy deletes the cluster password policy
+func (b *backend) DeletePasswordPolicy() error {
+	err := b.deleteKey(b.key(passwordPolicyP, valP))
+	if err != nil {
+		if trace.IsNotFound(err) {
+			return trace.NotFound("password policy not found")
+		}
+		return trace.Wrap(err)
+	}
+	return nil
+}
+
 func (b *backend) GetStaticTokens() (teleservices.StaticTokens, error) {
 	data, err := b.getValBytes(b.key(clusterConfigP, clusterConfigStaticTokenP))
 	if err != nil {
@@ -20,8 +20,16 @@ const (
 	// forever means no TTL is set
 	forever                     = 0
 	accountsP                   = "accounts"
+	accessRequestsP             = "accessrequests"
+	auditP                      = "audit"
+	auditRecordsP               = "records"
+	auditHeadP                  = "head"
+	auditCursorsP               = "cursors"
+	auditPendingP               = "pending"
 	apikeysP                    = "apikeys"
 	authPreferenceP             = "authpreference"
+	mfaPolicyP                  = "mfapolicy"
+	passwordPolicyP             = "passwordpolicy"
 	clusterConfigP              = "clusterconfig"
 	clusterConfigStaticTokenP   = "statictokens"
 	clusterConfigNameP          = "name"
@@ -453,6 +453,39 @@ func (e *engine) getKeys(key key) ([]string, error) {
 	return vals, nil
 }
 
+// getRange reads the directory with a single request, etcd v2 API
+// does not support reading a range of keys
+func (e *engine) getRange(key key, from string, fn rangeFn) error {
+	re, err := e.Get(context.TODO(), ekey(key), &client.GetOptions{Sort: true})
+	err = convertErr(err)
+	if err != nil {
+		if trace.IsNotFound(err) {
+			return nil
+		}
+		return trace.Wrap(err)
+	}
+	if !isDir(re.Node) {
+		return trace.BadParameter("'%v': expected directory", key)
+	}
+	for _, n := range re.Node.Nodes {
+		k := suffix(n.Key)
+		if isDir(n) || k < from {
+			continue
+		}
+		value := n.Value
+		next, err := fn(k, func(val interface{}) error {
+			return e.codec.DecodeFromString(value, val)
+		})
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		if !next {
+			return nil
+		}
+	}
+	return nil
+}
+
 func convertErr(e error) error {
 	if e == nil {
 		return nil
@@ -261,6 +261,30 @@ func (s *ESuite) TestClusterLogin(c *C) {
 	s.suite.ClusterLogin(c)
 }
 
+func (s *ESuite) TestMFAPolicyCRUD(c *C) {
+	s.suite.MFAPolicyCRUD(c)
+}
+
+func (s *ESuite) TestAccessRequestsCRUD(c *C) {
+	s.suite.AccessRequestsCRUD(c)
+}
+
+func (s *ESuite) TestAuditRecordsCRUD(c *C) {
+	s.suite.AuditRecordsCRUD(c)
+}
+
+func (s *ESuite) TestAuditRecordsRetention(c *C) {
+	s.suite.AuditRecordsRetention(c)
+}
+
+func (s *ESuite) TestAuditCursorsCRUD(c *C) {
+	s.suite.AuditCursorsCRUD(c)
+}
+
+func (s *ESuite) TestPasswordPolicyCRUD(c *C) {
+	s.suite.PasswordPolicyCRUD(c)
+}
+
 func (s *ESuite) TestIndexFile(c *C) {
 	s.suite.IndexFile(c)
 }
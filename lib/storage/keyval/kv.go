@@ -45,8 +45,15 @@ type kvengine interface {
 	tryAcquireLock(token key, ttl time.Duration) error
 	releaseLock(token key) error
 	getKeys(key key) ([]string, error)
+	// getRange calls fn with the values of the keys in the specified directory
+	// that are not less than the key from, in key order, until fn returns false
+	getRange(key key, from string, fn rangeFn) error
 }
 
+// rangeFn is called with the key of each value read by getRange and
+// the function that decodes the value. It returns false to stop reading
+type rangeFn func(key string, decode func(val interface{}) error) (next bool, err error)
+
 type key []string
 
 func (k key) split() ([]string, string) {
@@ -161,6 +161,12 @@ func (b *multiBolt) getKeys(key key) (keys []string, err error) {
 	return keys, trace.Wrap(err)
 }
 
+func (b *multiBolt) getRange(key key, from string, fn rangeFn) error {
+	return trace.Wrap(b.withBolt(func(b *blt) error {
+		return trace.Wrap(b.getRange(key, from, fn))
+	}))
+}
+
 func (b *multiBolt) key(prefix string, keys ...string) key {
 	return append([]string{"root", prefix}, keys...)
 }
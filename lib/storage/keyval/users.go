@@ -140,6 +140,15 @@ func (b *backend) UpdateUser(name string, req storage.UpdateUserReq) error {
 	if req.Password != nil {
 		u.SetPassword(*req.Password)
 	}
+	if req.PasswordHistory != nil {
+		u.SetPasswordHistory(*req.PasswordHistory)
+	}
+	if req.PasswordChanged != nil {
+		u.SetPasswordChanged(*req.PasswordChanged)
+	}
+	if req.Status != nil {
+		u.SetStatus(*req.Status)
+	}
 	if req.Roles != nil {
 		u.SetRoles(*req.Roles)
 	}
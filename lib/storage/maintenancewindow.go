@@ -0,0 +1,299 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	teledefaults "github.com/gravitational/teleport/lib/defaults"
+	teleservices "github.com/gravitational/teleport/lib/services"
+	teleutils "github.com/gravitational/teleport/lib/utils"
+
+	"github.com/gravitational/trace"
+	"github.com/jonboulle/clockwork"
+)
+
+// MaintenanceWindow defines a resource that restricts automatic cluster
+// operations, such as automatic updates and garbage collection, to a set
+// of recurring time windows.
+type MaintenanceWindow interface {
+	// Resource provides common resource methods.
+	teleservices.Resource
+	// CheckAndSetDefaults validates the resource and fills in some defaults.
+	CheckAndSetDefaults() error
+	// GetWindows returns the configured time windows.
+	GetWindows() []TimeWindow
+	// IsOpen returns whether the specified time falls into one of the windows.
+	IsOpen(time.Time) bool
+}
+
+// NewMaintenanceWindow creates a new maintenance window resource for the provided spec.
+func NewMaintenanceWindow(spec MaintenanceWindowSpecV1) MaintenanceWindow {
+	return &MaintenanceWindowV1{
+		Kind:    KindMaintenanceWindow,
+		Version: teleservices.V1,
+		Metadata: teleservices.Metadata{
+			Name:      KindMaintenanceWindow,
+			Namespace: teledefaults.Namespace,
+		},
+		Spec: spec,
+	}
+}
+
+// MaintenanceWindowV1 defines the maintenance window resource.
+type MaintenanceWindowV1 struct {
+	// Kind is the resource kind.
+	Kind string `json:"kind"`
+	// Version is the resource version.
+	Version string `json:"version"`
+	// Metadata is the resource metadata.
+	Metadata teleservices.Metadata `json:"metadata"`
+	// Spec is the resource specification.
+	Spec MaintenanceWindowSpecV1 `json:"spec"`
+}
+
+// MaintenanceWindowSpecV1 defines the maintenance window resource specification.
+type MaintenanceWindowSpecV1 struct {
+	// Windows is a list of recurring time windows.
+	Windows []TimeWindow `json:"windows"`
+}
+
+// TimeWindow defines a recurring time window.
+type TimeWindow struct {
+	// Days is a list of week days the window recurs on, e.g. "Sat".
+	// The window recurs daily if unspecified.
+	Days []string `json:"days,omitempty"`
+	// Start is the window start time of day in UTC, in 15:04 format.
+	Start string `json:"start"`
+	// Duration is the window duration.
+	Duration teleservices.Duration `json:"duration"`
+}
+
+// GetWindows returns the configured time windows.
+func (w *MaintenanceWindowV1) GetWindows() []TimeWindow {
+	return w.Spec.Windows
+}
+
+// IsOpen returns whether the specified time falls into one of the windows.
+func (w *MaintenanceWindowV1) IsOpen(now time.Time) bool {
+	for _, window := range w.Spec.Windows {
+		if window.contains(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckAndSetDefaults validates the resource and fills in some defaults.
+func (w *MaintenanceWindowV1) CheckAndSetDefaults() error {
+	if w.Metadata.Name == "" {
+		w.Metadata.Name = KindMaintenanceWindow
+	}
+	if err := w.Metadata.CheckAndSetDefaults(); err != nil {
+		return trace.Wrap(err)
+	}
+	if len(w.Spec.Windows) == 0 {
+		return trace.BadParameter("at least one time window is required")
+	}
+	for _, window := range w.Spec.Windows {
+		if err := window.check(); err != nil {
+			return trace.Wrap(err)
+		}
+	}
+	return nil
+}
+
+// GetName returns the resource name.
+func (w *MaintenanceWindowV1) GetName() string {
+	return w.Metadata.Name
+}
+
+// SetName sets the resource name.
+func (w *MaintenanceWindowV1) SetName(name string) {
+	w.Metadata.Name = name
+}
+
+// GetMetadata returns the resource metadata.
+func (w *MaintenanceWindowV1) GetMetadata() teleservices.Metadata {
+	return w.Metadata
+}
+
+// SetExpiry sets the resource expiration time.
+func (w *MaintenanceWindowV1) SetExpiry(expires time.Time) {
+	w.Metadata.SetExpiry(expires)
+}
+
+// Expiry returns the resource expiration time.
+func (w *MaintenanceWindowV1) Expiry() time.Time {
+	return w.Metadata.Expiry()
+}
+
+// SetTTL sets the resource TTL.
+func (w *MaintenanceWindowV1) SetTTL(clock clockwork.Clock, ttl time.Duration) {
+	w.Metadata.SetTTL(clock, ttl)
+}
+
+// String returns the object's string representation.
+func (w MaintenanceWindowV1) String() string {
+	var windows []string
+	for _, window := range w.Spec.Windows {
+		windows = append(windows, window.String())
+	}
+	return fmt.Sprintf("MaintenanceWindowV1(%s)", strings.Join(windows, ","))
+}
+
+// String returns the window's string representation.
+func (w TimeWindow) String() string {
+	days := "daily"
+	if len(w.Days) != 0 {
+		days = strings.Join(w.Days, "/")
+	}
+	return fmt.Sprintf("%v at %v UTC for %v", days, w.Start, w.Duration.Value())
+}
+
+func (w TimeWindow) check() error {
+	if _, err := time.Parse(timeWindowStartFormat, w.Start); err != nil {
+		return trace.BadParameter("invalid window start time %q, "+
+			"expected format is HH:MM", w.Start)
+	}
+	if w.Duration.Value() <= 0 {
+		return trace.BadParameter("window duration must be positive")
+	}
+	if w.Duration.Value() > 7*24*time.Hour {
+		return trace.BadParameter("window duration cannot exceed a week")
+	}
+	for _, day := range w.Days {
+		if _, err := parseWeekday(day); err != nil {
+			return trace.Wrap(err)
+		}
+	}
+	return nil
+}
+
+// contains returns true if the specified time falls into this window.
+// A window that starts on one of its days might extend into the next one
+func (w TimeWindow) contains(now time.Time) bool {
+	start, err := time.Parse(timeWindowStartFormat, w.Start)
+	if err != nil {
+		return false
+	}
+	now = now.UTC()
+	// Check the windows that started today as well as those started
+	// on previous days that could still be open
+	days := int(w.Duration.Value()/(24*time.Hour)) + 1
+	for i := 0; i <= days; i++ {
+		day := now.AddDate(0, 0, -i)
+		windowStart := time.Date(day.Year(), day.Month(), day.Day(),
+			start.Hour(), start.Minute(), 0, 0, time.UTC)
+		if !w.recursOn(windowStart.Weekday()) {
+			continue
+		}
+		if !now.Before(windowStart) && now.Before(windowStart.Add(w.Duration.Value())) {
+			return true
+		}
+	}
+	return false
+}
+
+func (w TimeWindow) recursOn(weekday time.Weekday) bool {
+	if len(w.Days) == 0 {
+		return true
+	}
+	for _, day := range w.Days {
+		if d, err := parseWeekday(day); err == nil && d == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+func parseWeekday(day string) (time.Weekday, error) {
+	for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+		name := weekday.String()
+		if strings.EqualFold(day, name) || strings.EqualFold(day, name[:3]) {
+			return weekday, nil
+		}
+	}
+	return 0, trace.BadParameter("invalid week day %q", day)
+}
+
+// UnmarshalMaintenanceWindow unmarshals maintenance window resource from the provided JSON data.
+func UnmarshalMaintenanceWindow(data []byte) (MaintenanceWindow, error) {
+	jsonData, err := teleutils.ToJSON(data)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var header teleservices.ResourceHeader
+	err = json.Unmarshal(jsonData, &header)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	switch header.Version {
+	case teleservices.V1:
+		var window MaintenanceWindowV1
+		err := teleutils.UnmarshalWithSchema(GetMaintenanceWindowSchema(), &window, jsonData)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		err = window.CheckAndSetDefaults()
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		return &window, nil
+	}
+	return nil, trace.BadParameter("%v resource version %q is not supported",
+		KindMaintenanceWindow, header.Version)
+}
+
+// MarshalMaintenanceWindow marshals provided maintenance window resource to JSON.
+func MarshalMaintenanceWindow(window MaintenanceWindow, opts ...teleservices.MarshalOption) ([]byte, error) {
+	return json.Marshal(window)
+}
+
+// GetMaintenanceWindowSchema returns the full maintenance window resource schema.
+func GetMaintenanceWindowSchema() string {
+	return fmt.Sprintf(teleservices.V2SchemaTemplate, MetadataSchema,
+		MaintenanceWindowSpecV1Schema, "")
+}
+
+// MaintenanceWindowSpecV1Schema defines the maintenance window spec schema.
+const MaintenanceWindowSpecV1Schema = `{
+  "type": "object",
+  "additionalProperties": false,
+  "required": ["windows"],
+  "properties": {
+    "windows": {
+      "type": "array",
+      "items": {
+        "type": "object",
+        "additionalProperties": false,
+        "required": ["start", "duration"],
+        "properties": {
+          "days": {"type": "array", "items": {"type": "string"}},
+          "start": {"type": "string"},
+          "duration": {"type": "string"}
+        }
+      }
+    }
+  }
+}`
+
+// timeWindowStartFormat is the format of the time window start time
+const timeWindowStartFormat = "15:04"
@@ -0,0 +1,125 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"time"
+
+	"github.com/gravitational/gravity/lib/compare"
+
+	teleservices "github.com/gravitational/teleport/lib/services"
+	check "gopkg.in/check.v1"
+)
+
+type MaintenanceWindowSuite struct{}
+
+var _ = check.Suite(&MaintenanceWindowSuite{})
+
+func (s *MaintenanceWindowSuite) TestResourceParsing(c *check.C) {
+	spec := `kind: maintenancewindow
+version: v1
+spec:
+  windows:
+  - days: ["Sat", "sunday"]
+    start: "22:00"
+    duration: 4h
+`
+	window, err := UnmarshalMaintenanceWindow([]byte(spec))
+	c.Assert(err, check.IsNil)
+	c.Assert(window, compare.DeepEquals, NewMaintenanceWindow(MaintenanceWindowSpecV1{
+		Windows: []TimeWindow{
+			{
+				Days:     []string{"Sat", "sunday"},
+				Start:    "22:00",
+				Duration: teleservices.NewDuration(4 * time.Hour),
+			},
+		},
+	}))
+}
+
+func (s *MaintenanceWindowSuite) TestValidation(c *check.C) {
+	specs := []string{
+		`kind: maintenancewindow
+version: v1
+spec:
+  windows: []`,
+		`kind: maintenancewindow
+version: v1
+spec:
+  windows:
+  - start: "25:00"
+    duration: 1h`,
+		`kind: maintenancewindow
+version: v1
+spec:
+  windows:
+  - days: ["Someday"]
+    start: "01:00"
+    duration: 1h`,
+	}
+	for _, spec := range specs {
+		_, err := UnmarshalMaintenanceWindow([]byte(spec))
+		c.Assert(err, check.NotNil, check.Commentf(spec))
+	}
+}
+
+func (s *MaintenanceWindowSuite) TestIsOpen(c *check.C) {
+	window := NewMaintenanceWindow(MaintenanceWindowSpecV1{
+		Windows: []TimeWindow{
+			{
+				Days:     []string{"Sat"},
+				Start:    "22:00",
+				Duration: teleservices.NewDuration(4 * time.Hour),
+			},
+		},
+	})
+	// 2019-06-01 is a Saturday
+	testCases := []struct {
+		time    time.Time
+		open    bool
+		comment string
+	}{
+		{
+			time:    time.Date(2019, 6, 1, 23, 0, 0, 0, time.UTC),
+			open:    true,
+			comment: "inside the window",
+		},
+		{
+			time:    time.Date(2019, 6, 2, 1, 59, 0, 0, time.UTC),
+			open:    true,
+			comment: "window extends into the next day",
+		},
+		{
+			time:    time.Date(2019, 6, 2, 2, 0, 0, 0, time.UTC),
+			open:    false,
+			comment: "window has closed",
+		},
+		{
+			time:    time.Date(2019, 6, 1, 21, 59, 0, 0, time.UTC),
+			open:    false,
+			comment: "window has not opened yet",
+		},
+		{
+			time:    time.Date(2019, 6, 7, 23, 0, 0, 0, time.UTC),
+			open:    false,
+			comment: "window does not recur on Fridays",
+		},
+	}
+	for _, tc := range testCases {
+		c.Assert(window.IsOpen(tc.time), check.Equals, tc.open, check.Commentf(tc.comment))
+	}
+}
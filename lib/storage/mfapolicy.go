@@ -0,0 +1,313 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gravitational/gravity/lib/utils"
+
+	teledefaults "github.com/gravitational/teleport/lib/defaults"
+	teleservices "github.com/gravitational/teleport/lib/services"
+	teleutils "github.com/gravitational/teleport/lib/utils"
+
+	"github.com/gravitational/trace"
+	"github.com/jonboulle/clockwork"
+)
+
+// MFAPolicy defines a resource that requires users to re-authenticate
+// with a second factor before performing certain cluster actions
+type MFAPolicy interface {
+	// Resource provides common resource methods.
+	teleservices.Resource
+	// CheckAndSetDefaults validates the resource and fills in some defaults.
+	CheckAndSetDefaults() error
+	// GetRules returns the policy rules.
+	GetRules() []MFARule
+	// RequiresSecondFactor returns whether the specified action performed
+	// by a user with the provided roles requires a second factor.
+	RequiresSecondFactor(action string, roles []string) bool
+	// AppliesTo returns whether any of the policy rules applies to a user
+	// with the provided roles.
+	AppliesTo(roles []string) bool
+}
+
+// NewMFAPolicy creates a new second factor policy resource for the provided spec.
+func NewMFAPolicy(spec MFAPolicySpecV1) MFAPolicy {
+	return &MFAPolicyV1{
+		Kind:    KindMFAPolicy,
+		Version: teleservices.V1,
+		Metadata: teleservices.Metadata{
+			Name:      KindMFAPolicy,
+			Namespace: teledefaults.Namespace,
+		},
+		Spec: spec,
+	}
+}
+
+// MFAPolicyV1 defines the second factor policy resource.
+type MFAPolicyV1 struct {
+	// Kind is the resource kind.
+	Kind string `json:"kind"`
+	// Version is the resource version.
+	Version string `json:"version"`
+	// Metadata is the resource metadata.
+	Metadata teleservices.Metadata `json:"metadata"`
+	// Spec is the resource specification.
+	Spec MFAPolicySpecV1 `json:"spec"`
+}
+
+// MFAPolicySpecV1 defines the second factor policy resource specification.
+type MFAPolicySpecV1 struct {
+	// Rules is a list of policy rules.
+	Rules []MFARule `json:"rules"`
+}
+
+// MFARule requires a second factor for a set of actions.
+type MFARule struct {
+	// Actions is a list of actions that require a second factor, e.g. "update".
+	// The wildcard "*" matches all actions.
+	Actions []string `json:"actions"`
+	// Roles is a list of user roles the rule applies to.
+	// The rule applies to all users if unspecified.
+	Roles []string `json:"roles,omitempty"`
+}
+
+// GetRules returns the policy rules.
+func (p *MFAPolicyV1) GetRules() []MFARule {
+	return p.Spec.Rules
+}
+
+// RequiresSecondFactor returns whether the specified action performed
+// by a user with the provided roles requires a second factor.
+func (p *MFAPolicyV1) RequiresSecondFactor(action string, roles []string) bool {
+	for _, rule := range p.Spec.Rules {
+		if rule.matchesRoles(roles) && rule.matchesAction(action) {
+			return true
+		}
+	}
+	return false
+}
+
+// AppliesTo returns whether any of the policy rules applies to a user
+// with the provided roles.
+func (p *MFAPolicyV1) AppliesTo(roles []string) bool {
+	for _, rule := range p.Spec.Rules {
+		if rule.matchesRoles(roles) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckAndSetDefaults validates the resource and fills in some defaults.
+func (p *MFAPolicyV1) CheckAndSetDefaults() error {
+	if p.Metadata.Name == "" {
+		p.Metadata.Name = KindMFAPolicy
+	}
+	if err := p.Metadata.CheckAndSetDefaults(); err != nil {
+		return trace.Wrap(err)
+	}
+	if len(p.Spec.Rules) == 0 {
+		return trace.BadParameter("at least one rule is required")
+	}
+	for _, rule := range p.Spec.Rules {
+		if err := rule.check(); err != nil {
+			return trace.Wrap(err)
+		}
+	}
+	return nil
+}
+
+// GetName returns the resource name.
+func (p *MFAPolicyV1) GetName() string {
+	return p.Metadata.Name
+}
+
+// SetName sets the resource name.
+func (p *MFAPolicyV1) SetName(name string) {
+	p.Metadata.Name = name
+}
+
+// GetMetadata returns the resource metadata.
+func (p *MFAPolicyV1) GetMetadata() teleservices.Metadata {
+	return p.Metadata
+}
+
+// SetExpiry sets the resource expiration time.
+func (p *MFAPolicyV1) SetExpiry(expires time.Time) {
+	p.Metadata.SetExpiry(expires)
+}
+
+// Expiry returns the resource expiration time.
+func (p *MFAPolicyV1) Expiry() time.Time {
+	return p.Metadata.Expiry()
+}
+
+// SetTTL sets the resource TTL.
+func (p *MFAPolicyV1) SetTTL(clock clockwork.Clock, ttl time.Duration) {
+	p.Metadata.SetTTL(clock, ttl)
+}
+
+// String returns the object's string representation.
+func (p MFAPolicyV1) String() string {
+	var rules []string
+	for _, rule := range p.Spec.Rules {
+		rules = append(rules, rule.String())
+	}
+	return fmt.Sprintf("MFAPolicyV1(%s)", strings.Join(rules, ","))
+}
+
+// String returns the rule's string representation.
+func (r MFARule) String() string {
+	roles := "all users"
+	if len(r.Roles) != 0 {
+		roles = strings.Join(r.Roles, "/")
+	}
+	return fmt.Sprintf("%v for %v", strings.Join(r.Actions, "/"), roles)
+}
+
+func (r MFARule) check() error {
+	if len(r.Actions) == 0 {
+		return trace.BadParameter("rule should specify at least one action")
+	}
+	for _, action := range r.Actions {
+		if action != teleservices.Wildcard && !utils.StringInSlice(MFAActions, action) {
+			return trace.BadParameter("unsupported action %q, supported actions are: %v",
+				action, strings.Join(MFAActions, ", "))
+		}
+	}
+	return nil
+}
+
+func (r MFARule) matchesAction(action string) bool {
+	for _, a := range r.Actions {
+		if a == teleservices.Wildcard || a == action {
+			return true
+		}
+	}
+	return false
+}
+
+func (r MFARule) matchesRoles(roles []string) bool {
+	if len(r.Roles) == 0 {
+		return true
+	}
+	for _, role := range r.Roles {
+		if role == teleservices.Wildcard || utils.StringInSlice(roles, role) {
+			return true
+		}
+	}
+	return false
+}
+
+// UnmarshalMFAPolicy unmarshals second factor policy resource from the provided JSON data.
+func UnmarshalMFAPolicy(data []byte) (MFAPolicy, error) {
+	jsonData, err := teleutils.ToJSON(data)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var header teleservices.ResourceHeader
+	err = json.Unmarshal(jsonData, &header)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	switch header.Version {
+	case teleservices.V1:
+		var policy MFAPolicyV1
+		err := teleutils.UnmarshalWithSchema(GetMFAPolicySchema(), &policy, jsonData)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		err = policy.CheckAndSetDefaults()
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		return &policy, nil
+	}
+	return nil, trace.BadParameter("%v resource version %q is not supported",
+		KindMFAPolicy, header.Version)
+}
+
+// MarshalMFAPolicy marshals provided second factor policy resource to JSON.
+func MarshalMFAPolicy(policy MFAPolicy, opts ...teleservices.MarshalOption) ([]byte, error) {
+	return json.Marshal(policy)
+}
+
+// GetMFAPolicySchema returns the full second factor policy resource schema.
+func GetMFAPolicySchema() string {
+	return fmt.Sprintf(teleservices.V2SchemaTemplate, MetadataSchema,
+		MFAPolicySpecV1Schema, "")
+}
+
+// MFAPolicySpecV1Schema defines the second factor policy spec schema.
+const MFAPolicySpecV1Schema = `{
+  "type": "object",
+  "additionalProperties": false,
+  "required": ["rules"],
+  "properties": {
+    "rules": {
+      "type": "array",
+      "items": {
+        "type": "object",
+        "additionalProperties": false,
+        "required": ["actions"],
+        "properties": {
+          "actions": {"type": "array", "items": {"type": "string"}},
+          "roles": {"type": "array", "items": {"type": "string"}}
+        }
+      }
+    }
+  }
+}`
+
+const (
+	// MFAActionUpdate is the cluster update action
+	MFAActionUpdate = "update"
+	// MFAActionExpand is the cluster expand action
+	MFAActionExpand = "expand"
+	// MFAActionShrink is the node removal action
+	MFAActionShrink = "shrink"
+	// MFAActionUninstall is the cluster uninstall (deletion) action
+	MFAActionUninstall = "uninstall"
+	// MFAActionGarbageCollect is the cluster garbage collection action
+	MFAActionGarbageCollect = "gc"
+	// MFAActionUpdateEnviron is the runtime environment update action
+	MFAActionUpdateEnviron = "update_environ"
+	// MFAActionUpdateConfig is the cluster configuration update action
+	MFAActionUpdateConfig = "update_config"
+	// MFAActionUpdateCertificate is the cluster web certificate update action
+	MFAActionUpdateCertificate = "update_certificate"
+	// MFAActionRotateCredentials is the RPC agent credentials rotation action
+	MFAActionRotateCredentials = "rotate_credentials"
+)
+
+// MFAActions lists actions supported by the second factor policy
+var MFAActions = []string{
+	MFAActionUpdate,
+	MFAActionExpand,
+	MFAActionShrink,
+	MFAActionUninstall,
+	MFAActionGarbageCollect,
+	MFAActionUpdateEnviron,
+	MFAActionUpdateConfig,
+	MFAActionUpdateCertificate,
+	MFAActionRotateCredentials,
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"github.com/gravitational/gravity/lib/compare"
+
+	check "gopkg.in/check.v1"
+)
+
+type MFAPolicySuite struct{}
+
+var _ = check.Suite(&MFAPolicySuite{})
+
+func (s *MFAPolicySuite) TestResourceParsing(c *check.C) {
+	spec := `kind: mfapolicy
+version: v1
+spec:
+  rules:
+  - actions: ["update", "shrink"]
+  - actions: ["*"]
+    roles: ["operator"]
+`
+	policy, err := UnmarshalMFAPolicy([]byte(spec))
+	c.Assert(err, check.IsNil)
+	c.Assert(policy, compare.DeepEquals, NewMFAPolicy(MFAPolicySpecV1{
+		Rules: []MFARule{
+			{
+				Actions: []string{MFAActionUpdate, MFAActionShrink},
+			},
+			{
+				Actions: []string{"*"},
+				Roles:   []string{"operator"},
+			},
+		},
+	}))
+}
+
+func (s *MFAPolicySuite) TestValidation(c *check.C) {
+	specs := []string{
+		`kind: mfapolicy
+version: v1
+spec:
+  rules: []`,
+		`kind: mfapolicy
+version: v1
+spec:
+  rules:
+  - roles: ["admin"]`,
+		`kind: mfapolicy
+version: v1
+spec:
+  rules:
+  - actions: ["reboot"]`,
+	}
+	for _, spec := range specs {
+		_, err := UnmarshalMFAPolicy([]byte(spec))
+		c.Assert(err, check.NotNil, check.Commentf(spec))
+	}
+}
+
+func (s *MFAPolicySuite) TestRequiresSecondFactor(c *check.C) {
+	policy := NewMFAPolicy(MFAPolicySpecV1{
+		Rules: []MFARule{
+			{
+				Actions: []string{MFAActionUpdate},
+			},
+			{
+				Actions: []string{"*"},
+				Roles:   []string{"operator"},
+			},
+		},
+	})
+	testCases := []struct {
+		action   string
+		roles    []string
+		required bool
+		comment  string
+	}{
+		{
+			action:   MFAActionUpdate,
+			roles:    []string{"viewer"},
+			required: true,
+			comment:  "rule without roles applies to everyone",
+		},
+		{
+			action:   MFAActionShrink,
+			roles:    []string{"viewer"},
+			required: false,
+			comment:  "action is not covered for this role",
+		},
+		{
+			action:   MFAActionShrink,
+			roles:    []string{"viewer", "operator"},
+			required: true,
+			comment:  "wildcard action applies to operator role",
+		},
+	}
+	for _, tc := range testCases {
+		c.Assert(policy.RequiresSecondFactor(tc.action, tc.roles), check.Equals,
+			tc.required, check.Commentf(tc.comment))
+	}
+}
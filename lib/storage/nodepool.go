@@ -0,0 +1,249 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"time"
+
+	teledefaults "github.com/gravitational/teleport/lib/defaults"
+	teleservices "github.com/gravitational/teleport/lib/services"
+	teleutils "github.com/gravitational/teleport/lib/utils"
+
+	"github.com/gravitational/trace"
+	"github.com/jonboulle/clockwork"
+)
+
+// NodePool defines a resource that describes an inventory of candidate
+// hosts and the number of them that should be cluster members.
+// The cluster expands onto the candidate hosts or shrinks to converge
+// on the desired count.
+type NodePool interface {
+	// Resource provides common resource methods.
+	teleservices.Resource
+	// CheckAndSetDefaults validates the resource and fills in some defaults.
+	CheckAndSetDefaults() error
+	// GetHosts returns the candidate hosts.
+	GetHosts() []NodePoolHost
+	// GetCount returns the desired number of pool hosts in the cluster.
+	GetCount() int
+}
+
+// NewNodePool creates a new node pool resource with the specified name and spec.
+func NewNodePool(name string, spec NodePoolSpecV1) NodePool {
+	return &NodePoolV1{
+		Kind:    KindNodePool,
+		Version: teleservices.V1,
+		Metadata: teleservices.Metadata{
+			Name:      name,
+			Namespace: teledefaults.Namespace,
+		},
+		Spec: spec,
+	}
+}
+
+// NodePoolV1 defines the node pool resource.
+type NodePoolV1 struct {
+	// Kind is the resource kind.
+	Kind string `json:"kind"`
+	// Version is the resource version.
+	Version string `json:"version"`
+	// Metadata is the resource metadata.
+	Metadata teleservices.Metadata `json:"metadata"`
+	// Spec is the resource specification.
+	Spec NodePoolSpecV1 `json:"spec"`
+}
+
+// NodePoolSpecV1 defines the node pool resource specification.
+type NodePoolSpecV1 struct {
+	// Hosts is the list of candidate hosts in the order of preference.
+	Hosts []NodePoolHost `json:"hosts"`
+	// Count is the desired number of pool hosts in the cluster.
+	Count int `json:"count"`
+}
+
+// NodePoolHost describes a candidate host of a node pool.
+type NodePoolHost struct {
+	// Addr is the host's SSH address in host:port format.
+	// The host part is used as the node's advertise address.
+	Addr string `json:"addr"`
+	// Role is the node profile the host joins the cluster with.
+	Role string `json:"role"`
+	// Labels is a set of Kubernetes labels to apply to the node.
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// AdvertiseIP returns the IP address this host joins the cluster with.
+func (h NodePoolHost) AdvertiseIP() string {
+	host, _, err := net.SplitHostPort(h.Addr)
+	if err != nil {
+		return h.Addr
+	}
+	return host
+}
+
+// String returns the host's string representation.
+func (h NodePoolHost) String() string {
+	return fmt.Sprintf("host(%v, role=%v)", h.Addr, h.Role)
+}
+
+// GetHosts returns the candidate hosts.
+func (p *NodePoolV1) GetHosts() []NodePoolHost {
+	return p.Spec.Hosts
+}
+
+// GetCount returns the desired number of pool hosts in the cluster.
+func (p *NodePoolV1) GetCount() int {
+	return p.Spec.Count
+}
+
+// CheckAndSetDefaults validates the resource and fills in some defaults.
+func (p *NodePoolV1) CheckAndSetDefaults() error {
+	if p.Metadata.Name == "" {
+		return trace.BadParameter("node pool name is required")
+	}
+	if err := p.Metadata.CheckAndSetDefaults(); err != nil {
+		return trace.Wrap(err)
+	}
+	if p.Spec.Count < 0 {
+		return trace.BadParameter("node pool count cannot be negative")
+	}
+	if p.Spec.Count > len(p.Spec.Hosts) {
+		return trace.BadParameter("node pool count %v exceeds the number of hosts %v",
+			p.Spec.Count, len(p.Spec.Hosts))
+	}
+	addrs := make(map[string]bool)
+	for _, host := range p.Spec.Hosts {
+		if host.Role == "" {
+			return trace.BadParameter("host %v is missing role", host.Addr)
+		}
+		ip, _, err := net.SplitHostPort(host.Addr)
+		if err != nil || net.ParseIP(ip) == nil {
+			return trace.BadParameter("host address %q should be in ip:port format", host.Addr)
+		}
+		if addrs[ip] {
+			return trace.BadParameter("duplicate host %v", ip)
+		}
+		addrs[ip] = true
+	}
+	return nil
+}
+
+// GetName returns the resource name.
+func (p *NodePoolV1) GetName() string {
+	return p.Metadata.Name
+}
+
+// SetName sets the resource name.
+func (p *NodePoolV1) SetName(name string) {
+	p.Metadata.Name = name
+}
+
+// GetMetadata returns the resource metadata.
+func (p *NodePoolV1) GetMetadata() teleservices.Metadata {
+	return p.Metadata
+}
+
+// SetExpiry sets the resource expiration time.
+func (p *NodePoolV1) SetExpiry(expires time.Time) {
+	p.Metadata.SetExpiry(expires)
+}
+
+// Expiry returns the resource expiration time.
+func (p *NodePoolV1) Expiry() time.Time {
+	return p.Metadata.Expiry()
+}
+
+// SetTTL sets the resource TTL.
+func (p *NodePoolV1) SetTTL(clock clockwork.Clock, ttl time.Duration) {
+	p.Metadata.SetTTL(clock, ttl)
+}
+
+// String returns the object's string representation.
+func (p NodePoolV1) String() string {
+	return fmt.Sprintf("NodePoolV1(Name=%v, Count=%v, Hosts=%v)",
+		p.Metadata.Name, p.Spec.Count, p.Spec.Hosts)
+}
+
+// UnmarshalNodePool unmarshals node pool resource from the provided JSON data.
+func UnmarshalNodePool(data []byte) (NodePool, error) {
+	jsonData, err := teleutils.ToJSON(data)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var header teleservices.ResourceHeader
+	err = json.Unmarshal(jsonData, &header)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	switch header.Version {
+	case teleservices.V1:
+		var pool NodePoolV1
+		err := teleutils.UnmarshalWithSchema(GetNodePoolSchema(), &pool, jsonData)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		err = pool.CheckAndSetDefaults()
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		return &pool, nil
+	}
+	return nil, trace.BadParameter("%v resource version %q is not supported",
+		KindNodePool, header.Version)
+}
+
+// MarshalNodePool marshals provided node pool resource to JSON.
+func MarshalNodePool(pool NodePool, opts ...teleservices.MarshalOption) ([]byte, error) {
+	return json.Marshal(pool)
+}
+
+// GetNodePoolSchema returns the full node pool resource schema.
+func GetNodePoolSchema() string {
+	return fmt.Sprintf(teleservices.V2SchemaTemplate, MetadataSchema,
+		NodePoolSpecV1Schema, "")
+}
+
+// NodePoolSpecV1Schema defines the node pool spec schema.
+const NodePoolSpecV1Schema = `{
+  "type": "object",
+  "additionalProperties": false,
+  "required": ["hosts", "count"],
+  "properties": {
+    "count": {"type": "number"},
+    "hosts": {
+      "type": "array",
+      "items": {
+        "type": "object",
+        "additionalProperties": false,
+        "required": ["addr", "role"],
+        "properties": {
+          "addr": {"type": "string"},
+          "role": {"type": "string"},
+          "labels": {
+            "type": "object",
+            "patternProperties": {
+              "^.*$": {"type": "string"}
+            }
+          }
+        }
+      }
+    }
+  }
+}`
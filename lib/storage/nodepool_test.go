@@ -0,0 +1,113 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"github.com/gravitational/gravity/lib/compare"
+
+	teledefaults "github.com/gravitational/teleport/lib/defaults"
+	teleservices "github.com/gravitational/teleport/lib/services"
+	"gopkg.in/check.v1"
+)
+
+type NodePoolSuite struct{}
+
+var _ = check.Suite(&NodePoolSuite{})
+
+func (s *NodePoolSuite) TestParse(c *check.C) {
+	spec := `kind: nodepool
+version: v1
+metadata:
+  name: workers
+spec:
+  count: 1
+  hosts:
+  - addr: 192.168.1.2:22
+    role: worker
+    labels:
+      rack: a
+  - addr: 192.168.1.3:22
+    role: worker`
+	pool, err := UnmarshalNodePool([]byte(spec))
+	c.Assert(err, check.IsNil)
+	compare.DeepCompare(c, pool, &NodePoolV1{
+		Kind:    KindNodePool,
+		Version: teleservices.V1,
+		Metadata: teleservices.Metadata{
+			Name:      "workers",
+			Namespace: teledefaults.Namespace,
+		},
+		Spec: NodePoolSpecV1{
+			Count: 1,
+			Hosts: []NodePoolHost{
+				{Addr: "192.168.1.2:22", Role: "worker", Labels: map[string]string{"rack": "a"}},
+				{Addr: "192.168.1.3:22", Role: "worker"},
+			},
+		},
+	})
+	c.Assert(pool.GetHosts()[0].AdvertiseIP(), check.Equals, "192.168.1.2")
+}
+
+func (s *NodePoolSuite) TestValidates(c *check.C) {
+	testCases := []struct {
+		spec    NodePoolSpecV1
+		comment string
+	}{
+		{
+			spec: NodePoolSpecV1{
+				Count: 2,
+				Hosts: []NodePoolHost{{Addr: "192.168.1.2:22", Role: "worker"}},
+			},
+			comment: "count exceeds the number of hosts",
+		},
+		{
+			spec: NodePoolSpecV1{
+				Count: 1,
+				Hosts: []NodePoolHost{{Addr: "node-1:22", Role: "worker"}},
+			},
+			comment: "host address is not an IP address",
+		},
+		{
+			spec: NodePoolSpecV1{
+				Count: 1,
+				Hosts: []NodePoolHost{{Addr: "192.168.1.2", Role: "worker"}},
+			},
+			comment: "host address is missing port",
+		},
+		{
+			spec: NodePoolSpecV1{
+				Count: 1,
+				Hosts: []NodePoolHost{{Addr: "192.168.1.2:22"}},
+			},
+			comment: "host is missing role",
+		},
+		{
+			spec: NodePoolSpecV1{
+				Count: 1,
+				Hosts: []NodePoolHost{
+					{Addr: "192.168.1.2:22", Role: "worker"},
+					{Addr: "192.168.1.2:3022", Role: "worker"},
+				},
+			},
+			comment: "duplicate host",
+		},
+	}
+	for _, tc := range testCases {
+		err := NewNodePool("workers", tc.spec).CheckAndSetDefaults()
+		c.Assert(err, check.NotNil, check.Commentf(tc.comment))
+	}
+}
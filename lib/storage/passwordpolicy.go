@@ -0,0 +1,313 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gravitational/gravity/lib/utils"
+
+	teledefaults "github.com/gravitational/teleport/lib/defaults"
+	teleservices "github.com/gravitational/teleport/lib/services"
+	teleutils "github.com/gravitational/teleport/lib/utils"
+
+	"github.com/gravitational/trace"
+	"github.com/jonboulle/clockwork"
+)
+
+// PasswordPolicy defines a resource with password and account lockout
+// rules for local users
+type PasswordPolicy interface {
+	// Resource provides common resource methods.
+	teleservices.Resource
+	// CheckAndSetDefaults validates the resource and fills in some defaults.
+	CheckAndSetDefaults() error
+	// CheckPassword checks the password against the length and
+	// character class requirements of the policy.
+	CheckPassword(password []byte) error
+	// GetMinLength returns the minimum password length.
+	GetMinLength() int
+	// GetCharacterClasses returns the character classes a password must contain.
+	GetCharacterClasses() []string
+	// GetHistoryDepth returns the number of previous passwords
+	// that cannot be reused.
+	GetHistoryDepth() int
+	// GetMaxAge returns how long a password is valid for, 0 if forever.
+	GetMaxAge() time.Duration
+	// GetMaxFailedAttempts returns the number of consecutive failed login
+	// attempts after which the account is locked, 0 if never.
+	GetMaxFailedAttempts() int
+	// GetLockoutDuration returns how long an account stays locked.
+	GetLockoutDuration() time.Duration
+}
+
+// NewPasswordPolicy creates a new password policy resource for the provided spec.
+func NewPasswordPolicy(spec PasswordPolicySpecV1) PasswordPolicy {
+	return &PasswordPolicyV1{
+		Kind:    KindPasswordPolicy,
+		Version: teleservices.V1,
+		Metadata: teleservices.Metadata{
+			Name:      KindPasswordPolicy,
+			Namespace: teledefaults.Namespace,
+		},
+		Spec: spec,
+	}
+}
+
+// PasswordPolicyV1 defines the password policy resource.
+type PasswordPolicyV1 struct {
+	// Kind is the resource kind.
+	Kind string `json:"kind"`
+	// Version is the resource version.
+	Version string `json:"version"`
+	// Metadata is the resource metadata.
+	Metadata teleservices.Metadata `json:"metadata"`
+	// Spec is the resource specification.
+	Spec PasswordPolicySpecV1 `json:"spec"`
+}
+
+// PasswordPolicySpecV1 defines the password policy resource specification.
+type PasswordPolicySpecV1 struct {
+	// MinLength is the minimum password length.
+	MinLength int `json:"min_length,omitempty"`
+	// CharacterClasses lists the character classes a password must contain,
+	// see PasswordCharacterClasses.
+	CharacterClasses []string `json:"character_classes,omitempty"`
+	// HistoryDepth is the number of previous passwords that cannot be reused.
+	HistoryDepth int `json:"history_depth,omitempty"`
+	// MaxAge is how long a password is valid for before it has to be reset.
+	MaxAge teleservices.Duration `json:"max_age,omitempty"`
+	// MaxFailedAttempts is the number of consecutive failed login attempts
+	// after which the account is locked.
+	MaxFailedAttempts int `json:"max_failed_attempts,omitempty"`
+	// LockoutDuration is how long an account stays locked.
+	LockoutDuration teleservices.Duration `json:"lockout_duration,omitempty"`
+}
+
+// CheckPassword checks the password against the length and
+// character class requirements of the policy.
+func (p *PasswordPolicyV1) CheckPassword(password []byte) error {
+	if len(password) < p.Spec.MinLength {
+		return trace.BadParameter("password is shorter than the minimum of %v characters",
+			p.Spec.MinLength)
+	}
+	var missing []string
+	for _, class := range p.Spec.CharacterClasses {
+		if !containsCharacterClass(string(password), class) {
+			missing = append(missing, class)
+		}
+	}
+	if len(missing) != 0 {
+		return trace.BadParameter("password must contain %v characters",
+			strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// GetMinLength returns the minimum password length.
+func (p *PasswordPolicyV1) GetMinLength() int {
+	return p.Spec.MinLength
+}
+
+// GetCharacterClasses returns the character classes a password must contain.
+func (p *PasswordPolicyV1) GetCharacterClasses() []string {
+	return p.Spec.CharacterClasses
+}
+
+// GetHistoryDepth returns the number of previous passwords that cannot be reused.
+func (p *PasswordPolicyV1) GetHistoryDepth() int {
+	return p.Spec.HistoryDepth
+}
+
+// GetMaxAge returns how long a password is valid for, 0 if forever.
+func (p *PasswordPolicyV1) GetMaxAge() time.Duration {
+	return p.Spec.MaxAge.Value()
+}
+
+// GetMaxFailedAttempts returns the number of consecutive failed login
+// attempts after which the account is locked, 0 if never.
+func (p *PasswordPolicyV1) GetMaxFailedAttempts() int {
+	return p.Spec.MaxFailedAttempts
+}
+
+// GetLockoutDuration returns how long an account stays locked.
+func (p *PasswordPolicyV1) GetLockoutDuration() time.Duration {
+	return p.Spec.LockoutDuration.Value()
+}
+
+// CheckAndSetDefaults validates the resource and fills in some defaults.
+func (p *PasswordPolicyV1) CheckAndSetDefaults() error {
+	if p.Metadata.Name == "" {
+		p.Metadata.Name = KindPasswordPolicy
+	}
+	if err := p.Metadata.CheckAndSetDefaults(); err != nil {
+		return trace.Wrap(err)
+	}
+	if p.Spec.MinLength < 0 || p.Spec.HistoryDepth < 0 || p.Spec.MaxFailedAttempts < 0 {
+		return trace.BadParameter("min_length, history_depth and max_failed_attempts cannot be negative")
+	}
+	if p.Spec.MaxAge.Value() < 0 || p.Spec.LockoutDuration.Value() < 0 {
+		return trace.BadParameter("max_age and lockout_duration cannot be negative")
+	}
+	for _, class := range p.Spec.CharacterClasses {
+		if !utils.StringInSlice(PasswordCharacterClasses, class) {
+			return trace.BadParameter("unsupported character class %q, supported are: %v",
+				class, strings.Join(PasswordCharacterClasses, ", "))
+		}
+	}
+	if p.Spec.MaxFailedAttempts != 0 && p.Spec.LockoutDuration.Value() == 0 {
+		p.Spec.LockoutDuration = teleservices.NewDuration(teledefaults.AccountLockInterval)
+	}
+	return nil
+}
+
+// GetName returns the resource name.
+func (p *PasswordPolicyV1) GetName() string {
+	return p.Metadata.Name
+}
+
+// SetName sets the resource name.
+func (p *PasswordPolicyV1) SetName(name string) {
+	p.Metadata.Name = name
+}
+
+// GetMetadata returns the resource metadata.
+func (p *PasswordPolicyV1) GetMetadata() teleservices.Metadata {
+	return p.Metadata
+}
+
+// SetExpiry sets the resource expiration time.
+func (p *PasswordPolicyV1) SetExpiry(expires time.Time) {
+	p.Metadata.SetExpiry(expires)
+}
+
+// Expiry returns the resource expiration time.
+func (p *PasswordPolicyV1) Expiry() time.Time {
+	return p.Metadata.Expiry()
+}
+
+// SetTTL sets the resource TTL.
+func (p *PasswordPolicyV1) SetTTL(clock clockwork.Clock, ttl time.Duration) {
+	p.Metadata.SetTTL(clock, ttl)
+}
+
+// String returns the object's string representation.
+func (p PasswordPolicyV1) String() string {
+	return fmt.Sprintf("PasswordPolicyV1(MinLength=%v, CharacterClasses=%v, HistoryDepth=%v, MaxAge=%v, MaxFailedAttempts=%v, LockoutDuration=%v)",
+		p.Spec.MinLength, p.Spec.CharacterClasses, p.Spec.HistoryDepth, p.Spec.MaxAge.Value(),
+		p.Spec.MaxFailedAttempts, p.Spec.LockoutDuration.Value())
+}
+
+func containsCharacterClass(password, class string) bool {
+	for _, r := range password {
+		switch class {
+		case PasswordClassLower:
+			if unicode.IsLower(r) {
+				return true
+			}
+		case PasswordClassUpper:
+			if unicode.IsUpper(r) {
+				return true
+			}
+		case PasswordClassDigit:
+			if unicode.IsDigit(r) {
+				return true
+			}
+		case PasswordClassSymbol:
+			if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsSpace(r) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// UnmarshalPasswordPolicy unmarshals password policy resource from the provided JSON data.
+func UnmarshalPasswordPolicy(data []byte) (PasswordPolicy, error) {
+	jsonData, err := teleutils.ToJSON(data)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var header teleservices.ResourceHeader
+	err = json.Unmarshal(jsonData, &header)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	switch header.Version {
+	case teleservices.V1:
+		var policy PasswordPolicyV1
+		err := teleutils.UnmarshalWithSchema(GetPasswordPolicySchema(), &policy, jsonData)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		err = policy.CheckAndSetDefaults()
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		return &policy, nil
+	}
+	return nil, trace.BadParameter("%v resource version %q is not supported",
+		KindPasswordPolicy, header.Version)
+}
+
+// MarshalPasswordPolicy marshals provided password policy resource to JSON.
+func MarshalPasswordPolicy(policy PasswordPolicy, opts ...teleservices.MarshalOption) ([]byte, error) {
+	return json.Marshal(policy)
+}
+
+// GetPasswordPolicySchema returns the full password policy resource schema.
+func GetPasswordPolicySchema() string {
+	return fmt.Sprintf(teleservices.V2SchemaTemplate, MetadataSchema,
+		PasswordPolicySpecV1Schema, "")
+}
+
+// PasswordPolicySpecV1Schema defines the password policy spec schema.
+const PasswordPolicySpecV1Schema = `{
+  "type": "object",
+  "additionalProperties": false,
+  "properties": {
+    "min_length": {"type": "number"},
+    "character_classes": {"type": "array", "items": {"type": "string"}},
+    "history_depth": {"type": "number"},
+    "max_age": {"type": "string"},
+    "max_failed_attempts": {"type": "number"},
+    "lockout_duration": {"type": "string"}
+  }
+}`
+
+const (
+	// PasswordClassLower requires a lowercase letter
+	PasswordClassLower = "lower"
+	// PasswordClassUpper requires an uppercase letter
+	PasswordClassUpper = "upper"
+	// PasswordClassDigit requires a digit
+	PasswordClassDigit = "digit"
+	// PasswordClassSymbol requires a character that is not a letter, digit or space
+	PasswordClassSymbol = "symbol"
+)
+
+// PasswordCharacterClasses lists character classes supported by the password policy
+var PasswordCharacterClasses = []string{
+	PasswordClassLower,
+	PasswordClassUpper,
+	PasswordClassDigit,
+	PasswordClassSymbol,
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"time"
+
+	"github.com/gravitational/gravity/lib/compare"
+
+	teleservices "github.com/gravitational/teleport/lib/services"
+	check "gopkg.in/check.v1"
+)
+
+type PasswordPolicySuite struct{}
+
+var _ = check.Suite(&PasswordPolicySuite{})
+
+func (s *PasswordPolicySuite) TestResourceParsing(c *check.C) {
+	spec := `kind: passwordpolicy
+version: v1
+spec:
+  min_length: 12
+  character_classes: ["lower", "digit"]
+  history_depth: 3
+  max_age: 2160h
+  max_failed_attempts: 5
+`
+	policy, err := UnmarshalPasswordPolicy([]byte(spec))
+	c.Assert(err, check.IsNil)
+	c.Assert(policy, compare.DeepEquals, NewPasswordPolicy(PasswordPolicySpecV1{
+		MinLength:         12,
+		CharacterClasses:  []string{PasswordClassLower, PasswordClassDigit},
+		HistoryDepth:      3,
+		MaxAge:            teleservices.NewDuration(2160 * time.Hour),
+		MaxFailedAttempts: 5,
+		LockoutDuration:   teleservices.NewDuration(20 * time.Minute),
+	}))
+}
+
+func (s *PasswordPolicySuite) TestValidation(c *check.C) {
+	specs := []string{
+		`kind: passwordpolicy
+version: v1
+spec:
+  character_classes: ["emoji"]`,
+		`kind: passwordpolicy
+version: v1
+spec:
+  min_length: -1`,
+	}
+	for _, spec := range specs {
+		_, err := UnmarshalPasswordPolicy([]byte(spec))
+		c.Assert(err, check.NotNil, check.Commentf(spec))
+	}
+}
+
+func (s *PasswordPolicySuite) TestCheckPassword(c *check.C) {
+	policy := NewPasswordPolicy(PasswordPolicySpecV1{
+		MinLength:        8,
+		CharacterClasses: PasswordCharacterClasses,
+	})
+	testCases := []struct {
+		password string
+		valid    bool
+		comment  string
+	}{
+		{password: "Passw0rd!", valid: true, comment: "all character classes"},
+		{password: "Pa0!", valid: false, comment: "too short"},
+		{password: "password0!", valid: false, comment: "missing uppercase"},
+		{password: "Password!", valid: false, comment: "missing digit"},
+		{password: "Password0", valid: false, comment: "missing symbol"},
+	}
+	for _, tc := range testCases {
+		err := policy.CheckPassword([]byte(tc.password))
+		c.Assert(err == nil, check.Equals, tc.valid, check.Commentf(tc.comment))
+	}
+}
@@ -151,6 +151,16 @@ type UpdateOperationData struct {
 	// The list might be a subset of all cluster servers in case
 	// the operation only operates on a specific part
 	Servers []UpdateServer `json:"updates,omitempty"`
+	// StorageDriver describes the optional change of the Docker storage driver
+	StorageDriver *StorageDriverChange `json:"storage_driver,omitempty"`
+}
+
+// StorageDriverChange describes a change of the Docker storage driver
+type StorageDriverChange struct {
+	// From is the storage driver the cluster is using before the update
+	From string `json:"from"`
+	// To is the storage driver the cluster is using after the update
+	To string `json:"to"`
 }
 
 // UpdateServer describes an intent to update runtime/teleport configuration
@@ -75,14 +75,73 @@ const (
 	KindRelease = "release"
 	// KindInvite defines the user invite token.
 	KindInvite = "invite"
+	// KindMaintenanceWindow defines the resource that restricts automatic
+	// cluster operations to configured time windows
+	KindMaintenanceWindow = "maintenancewindow"
+	// KindMFAPolicy defines the resource that requires a second factor
+	// for certain cluster actions
+	KindMFAPolicy = "mfapolicy"
+	// KindPasswordPolicy defines the resource with password and
+	// account lockout rules for local users
+	KindPasswordPolicy = "passwordpolicy"
+	// KindNodePool defines the resource that describes an inventory of
+	// candidate hosts the cluster scales onto
+	KindNodePool = "nodepool"
+	// KindOperation defines the resource used in role rules to grant or
+	// deny access to specific operations, the rule verbs are operation types
+	KindOperation = "operation"
+	// KindAccessRequest defines the request for temporary access
+	// to privileged roles
+	KindAccessRequest = "accessrequest"
 )
 
+const (
+	// OperationVerbInstall is the operation rule verb for cluster installation
+	OperationVerbInstall = "install"
+	// OperationVerbExpand is the operation rule verb for adding nodes
+	OperationVerbExpand = "expand"
+	// OperationVerbShrink is the operation rule verb for removing nodes
+	OperationVerbShrink = "shrink"
+	// OperationVerbUpdate is the operation rule verb for cluster upgrades
+	OperationVerbUpdate = "update"
+	// OperationVerbUninstall is the operation rule verb for cluster uninstall
+	OperationVerbUninstall = "uninstall"
+	// OperationVerbGarbageCollect is the operation rule verb for garbage collection
+	OperationVerbGarbageCollect = "gc"
+	// OperationVerbUpdateEnviron is the operation rule verb for updating
+	// cluster runtime environment variables
+	OperationVerbUpdateEnviron = "update_environ"
+	// OperationVerbUpdateConfig is the operation rule verb for updating
+	// cluster configuration
+	OperationVerbUpdateConfig = "update_config"
+	// OperationVerbImport is the operation rule verb for importing applications
+	OperationVerbImport = "import"
+	// OperationVerbRunHook is the operation rule verb for running application hooks
+	OperationVerbRunHook = "run_hook"
+)
+
+// OperationVerbs lists verbs supported in the rules for operations
+var OperationVerbs = []string{
+	OperationVerbInstall,
+	OperationVerbExpand,
+	OperationVerbShrink,
+	OperationVerbUpdate,
+	OperationVerbUninstall,
+	OperationVerbGarbageCollect,
+	OperationVerbUpdateEnviron,
+	OperationVerbUpdateConfig,
+	OperationVerbImport,
+	OperationVerbRunHook,
+}
+
 // CanonicalKind translates the specified kind to canonical form.
 // Returns the kind unmodified if it did not match any known resource
 func CanonicalKind(kind string) string {
 	switch strings.ToLower(kind) {
 	case teleservices.KindGithubConnector:
 		return teleservices.KindGithubConnector
+	case teleservices.KindOIDCConnector:
+		return teleservices.KindOIDCConnector
 	case teleservices.KindAuthConnector, "auth":
 		return teleservices.KindAuthConnector
 	case teleservices.KindUser, "users":
@@ -109,6 +168,16 @@ func CanonicalKind(kind string) string {
 		return KindPersistentStorage
 	case KindAuthGateway, "gw":
 		return KindAuthGateway
+	case KindMaintenanceWindow, "maintenancewindows", "mw":
+		return KindMaintenanceWindow
+	case KindMFAPolicy, "mfapolicies", "mfa":
+		return KindMFAPolicy
+	case KindPasswordPolicy, "passwordpolicies":
+		return KindPasswordPolicy
+	case KindNodePool, "nodepools", "np":
+		return KindNodePool
+	case KindOperation, "operations", "op":
+		return KindOperation
 	}
 	return kind
 }
@@ -164,6 +233,7 @@ func Encode(resources []UnknownResource, w io.Writer) error {
 var SupportedGravityResources = []string{
 	teleservices.KindClusterAuthPreference,
 	teleservices.KindGithubConnector,
+	teleservices.KindOIDCConnector,
 	teleservices.KindAuthConnector,
 	teleservices.KindUser,
 	KindToken,
@@ -176,12 +246,17 @@ var SupportedGravityResources = []string{
 	KindRuntimeEnvironment,
 	KindClusterConfiguration,
 	KindPersistentStorage,
+	KindMaintenanceWindow,
+	KindMFAPolicy,
+	KindPasswordPolicy,
+	KindNodePool,
 }
 
 // SupportedGravityResourcesToRemove is a list of resources supported by
 // "gravity resource rm" subcommand
 var SupportedGravityResourcesToRemove = []string{
 	teleservices.KindGithubConnector,
+	teleservices.KindOIDCConnector,
 	teleservices.KindUser,
 	KindToken,
 	KindLogForwarder,
@@ -191,6 +266,10 @@ var SupportedGravityResourcesToRemove = []string{
 	KindTLSKeyPair,
 	KindRuntimeEnvironment,
 	KindClusterConfiguration,
+	KindMaintenanceWindow,
+	KindMFAPolicy,
+	KindPasswordPolicy,
+	KindNodePool,
 }
 
 // MetadataSchema is a copy of teleport/lib/services.MetadataSchema but with
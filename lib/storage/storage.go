@@ -106,6 +106,34 @@ type APIKey struct {
 	Expires time.Time `json:"expires"`
 	// UserEmail is the name of the user the api key belongs to
 	UserEmail string `json:"user_email"`
+	// Roles optionally restricts the key to the subset of the user's roles.
+	// The key inherits all user's roles if unspecified
+	Roles []string `json:"roles,omitempty"`
+	// Rules optionally restricts the resources and verbs the key can access
+	// in addition to the restrictions imposed by the roles
+	Rules []teleservices.Rule `json:"rules,omitempty"`
+	// Clusters optionally restricts the key to the specified clusters
+	Clusters []string `json:"clusters,omitempty"`
+	// Created is the key creation time
+	Created time.Time `json:"created,omitempty"`
+	// LastUsed is the time the key was last used to authenticate
+	LastUsed time.Time `json:"last_used,omitempty"`
+	// LastUsedFrom is the address of the client that last used the key
+	LastUsedFrom string `json:"last_used_from,omitempty"`
+}
+
+// IsScoped returns true if this key grants a subset of its user's permissions
+func (a *APIKey) IsScoped() bool {
+	return len(a.Roles) != 0 || len(a.Rules) != 0 || len(a.Clusters) != 0
+}
+
+// LastActive returns the time this key was last used or created
+// if the key has never been used
+func (a *APIKey) LastActive() time.Time {
+	if a.LastUsed.After(a.Created) {
+		return a.LastUsed
+	}
+	return a.Created
 }
 
 // V2 returns V2 from token spec
@@ -130,6 +158,11 @@ func (a *APIKey) Check() error {
 	if a.Token == "" {
 		return trace.BadParameter("missing API Key token")
 	}
+	for i := range a.Rules {
+		if err := a.Rules[i].CheckAndSetDefaults(); err != nil {
+			return trace.Wrap(err)
+		}
+	}
 	return nil
 }
 
@@ -1461,12 +1494,16 @@ type Backend interface {
 	teleservices.Presence
 	teleservices.Access
 	ClusterConfiguration
+	MFAPolicies
+	PasswordPolicies
 	U2F
 	Locks
 	WebSessions
 	UserTokens
 	Tokens
 	UserInvites
+	AccessRequests
+	AuditRecords
 	Applications
 	AppOperations
 	AppProgressEntries
@@ -1822,6 +1859,28 @@ type InstallExpandOperationState struct {
 	Vars OperationVariables `json:"vars"`
 	// Package is the application being installed
 	Package loc.Locator `json:"package"`
+	// DiskBenchmarks lists results of disk benchmarks executed
+	// on the servers during preflight checks
+	DiskBenchmarks []DiskBenchmark `json:"disk_benchmarks,omitempty"`
+}
+
+// DiskBenchmark describes the result of a disk benchmark executed
+// with a fio profile on a server
+type DiskBenchmark struct {
+	// Hostname is the hostname of the server
+	Hostname string `json:"hostname"`
+	// AdvertiseIP is the advertise IP address of the server
+	AdvertiseIP string `json:"advertise_ip"`
+	// Path is the directory the benchmark was executed in
+	Path string `json:"path"`
+	// Profile is the name of the fio profile
+	Profile string `json:"profile"`
+	// ReadIOPS is the measured number of read iops
+	ReadIOPS float64 `json:"read_iops"`
+	// WriteIOPS is the measured number of write iops
+	WriteIOPS float64 `json:"write_iops"`
+	// FsyncLatency maps fsync latency percentiles to measured latencies
+	FsyncLatency map[string]time.Duration `json:"fsync_latency,omitempty"`
 }
 
 // OperationVariables is operation-specific set of variables
@@ -2069,6 +2128,12 @@ type ShrinkOperationState struct {
 	// Used in cases where we recieve an event where the node is being terminated, but may
 	// not have disconnected from the cluster yet.
 	NodeRemoved bool `json:"node_removed"`
+	// NodeLabels is a set of user-defined Kubernetes labels the removed node
+	// had before it was unregistered. It is used to carry the labels over to
+	// a replacement node
+	NodeLabels map[string]string `json:"node_labels,omitempty"`
+	// Replace indicates that the node is removed to be replaced by a joining node
+	Replace bool `json:"replace,omitempty"`
 }
 
 // UpdateOperationState describes the state of the update operation.
@@ -2175,6 +2240,26 @@ type ClusterConfiguration interface {
 	UpsertClusterConfig(teleservices.ClusterConfig) error
 }
 
+// MFAPolicies stores the cluster second factor policy
+type MFAPolicies interface {
+	// GetMFAPolicy returns the cluster second factor policy
+	GetMFAPolicy() (MFAPolicy, error)
+	// UpsertMFAPolicy creates or updates the cluster second factor policy
+	UpsertMFAPolicy(MFAPolicy) error
+	// DeleteMFAPolicy deletes the cluster second factor policy
+	DeleteMFAPolicy() error
+}
+
+// PasswordPolicies stores the cluster password policy
+type PasswordPolicies interface {
+	// GetPasswordPolicy returns the cluster password policy
+	GetPasswordPolicy() (PasswordPolicy, error)
+	// UpsertPasswordPolicy creates or updates the cluster password policy
+	UpsertPasswordPolicy(PasswordPolicy) error
+	// DeletePasswordPolicy deletes the cluster password policy
+	DeletePasswordPolicy() error
+}
+
 // CloudConfig represents additional cloud provider-specific configuration
 type CloudConfig struct {
 	// GCENodeTags lists additional node tags on GCE
@@ -1280,6 +1280,24 @@ func (s *StorageSuite) APIKeysCRUD(c *C) {
 	keys, err = s.Backend.GetAPIKeys(u.GetName())
 	c.Assert(err, IsNil)
 	c.Assert(len(keys), Equals, 1)
+
+	// scope and usage information is preserved
+	created := time.Date(2019, time.January, 1, 0, 0, 0, 0, time.UTC)
+	scoped := storage.APIKey{
+		Token:        "key3",
+		UserEmail:    u.GetName(),
+		Roles:        []string{"reader"},
+		Rules:        []teleservices.Rule{teleservices.NewRule(storage.KindCluster, teleservices.RO())},
+		Clusters:     []string{"example.com"},
+		Created:      created,
+		LastUsed:     created.Add(time.Hour),
+		LastUsedFrom: "192.168.1.1",
+	}
+	_, err = s.Backend.CreateAPIKey(scoped)
+	c.Assert(err, IsNil)
+	foundKey, err := s.Backend.GetAPIKey(scoped.Token)
+	c.Assert(err, IsNil)
+	c.Assert(*foundKey, DeepEquals, scoped)
 }
 
 func (s *StorageSuite) ProvisioningTokensCRUD(c *C) {
@@ -1597,6 +1615,204 @@ func (s *StorageSuite) LocksCRUD(c *C) {
 	c.Assert(err, IsNil)
 }
 
+// MFAPolicyCRUD tests second factor policy operations
+func (s *StorageSuite) MFAPolicyCRUD(c *C) {
+	_, err := s.Backend.GetMFAPolicy()
+	c.Assert(trace.IsNotFound(err), Equals, true, Commentf("%v", err))
+
+	policy := storage.NewMFAPolicy(storage.MFAPolicySpecV1{
+		Rules: []storage.MFARule{{Actions: []string{storage.MFAActionUpdate}}},
+	})
+	c.Assert(s.Backend.UpsertMFAPolicy(policy), IsNil)
+
+	out, err := s.Backend.GetMFAPolicy()
+	c.Assert(err, IsNil)
+	c.Assert(out, compare.DeepEquals, policy)
+
+	c.Assert(s.Backend.DeleteMFAPolicy(), IsNil)
+	err = s.Backend.DeleteMFAPolicy()
+	c.Assert(trace.IsNotFound(err), Equals, true, Commentf("%v", err))
+}
+
+// AccessRequestsCRUD tests access requests operations
+func (s *StorageSuite) AccessRequestsCRUD(c *C) {
+	out, err := s.Backend.GetAccessRequests()
+	c.Assert(err, IsNil)
+	c.Assert(len(out), Equals, 0)
+
+	req := storage.AccessRequest{
+		ID:       "r1",
+		User:     "alice@example.com",
+		Roles:    []string{"@teleadmin"},
+		Reason:   "investigate incident",
+		Duration: time.Hour,
+		State:    storage.AccessRequestStatePending,
+		Created:  s.Clock.Now().UTC(),
+	}
+	_, err = s.Backend.CreateAccessRequest(req)
+	c.Assert(err, IsNil)
+	_, err = s.Backend.CreateAccessRequest(req)
+	c.Assert(trace.IsAlreadyExists(err), Equals, true, Commentf("%v", err))
+
+	req.State = storage.AccessRequestStateApproved
+	req.Reviewer = "bob@example.com"
+	req.Reviewed = s.Clock.Now().UTC()
+	req.Expires = req.Reviewed.Add(req.Duration)
+	req.GrantedRoles = req.Roles
+	_, err = s.Backend.UpdateAccessRequest(req)
+	c.Assert(err, IsNil)
+
+	stored, err := s.Backend.GetAccessRequest(req.ID)
+	c.Assert(err, IsNil)
+	c.Assert(*stored, compare.DeepEquals, req)
+
+	out, err = s.Backend.GetAccessRequests()
+	c.Assert(err, IsNil)
+	c.Assert(out, compare.DeepEquals, []storage.AccessRequest{req})
+
+	c.Assert(s.Backend.DeleteAccessRequest(req.ID), IsNil)
+	_, err = s.Backend.GetAccessRequest(req.ID)
+	c.Assert(trace.IsNotFound(err), Equals, true, Commentf("%v", err))
+	_, err = s.Backend.UpdateAccessRequest(req)
+	c.Assert(trace.IsNotFound(err), Equals, true, Commentf("%v", err))
+}
+
+func (s *StorageSuite) AuditRecordsCRUD(c *C) {
+	start := s.Clock.Now().UTC()
+	var records []storage.AuditRecord
+	for i := 0; i < 3; i++ {
+		record, err := storage.NewAuditRecord("user.login", "T1000I", map[string]interface{}{
+			"user":    "alice@example.com",
+			"attempt": i,
+		}, s.Clock.Now())
+		c.Assert(err, IsNil)
+		out, err := s.Backend.AppendAuditRecord(*record)
+		c.Assert(err, IsNil)
+		c.Assert(out.Index, Equals, uint64(i+1))
+		records = append(records, *out)
+		s.Clock.Advance(time.Minute)
+	}
+	c.Assert(records[0].PrevHash, Equals, "")
+	c.Assert(records[1].PrevHash, Equals, records[0].Hash)
+
+	out, err := s.Backend.GetAuditRecords(storage.AuditRecordsFilter{})
+	c.Assert(err, IsNil)
+	c.Assert(out, compare.DeepEquals, records)
+	c.Assert(storage.VerifyAuditRecords(out), IsNil)
+
+	out, err = s.Backend.GetAuditRecords(storage.AuditRecordsFilter{
+		Since: start.Add(time.Minute),
+	})
+	c.Assert(err, IsNil)
+	c.Assert(out, compare.DeepEquals, records[1:])
+	c.Assert(storage.VerifyAuditRecords(out), IsNil)
+
+	out, err = s.Backend.GetAuditRecords(storage.AuditRecordsFilter{
+		FromIndex: 2,
+		Until:     start.Add(time.Minute),
+	})
+	c.Assert(err, IsNil)
+	c.Assert(out, compare.DeepEquals, records[1:2])
+
+	tampered := append([]storage.AuditRecord{}, records...)
+	tampered[1].Fields = map[string]interface{}{"user": "bob@example.com"}
+	c.Assert(trace.IsCompareFailed(storage.VerifyAuditRecords(tampered)), Equals, true)
+
+	removed := []storage.AuditRecord{records[0], records[2]}
+	c.Assert(trace.IsCompareFailed(storage.VerifyAuditRecords(removed)), Equals, true)
+}
+
+func (s *StorageSuite) AuditRecordsRetention(c *C) {
+	start := s.Clock.Now().UTC()
+	var records []storage.AuditRecord
+	for i := 0; i < 3; i++ {
+		record, err := storage.NewAuditRecord("user.login", "T1000I", nil, s.Clock.Now())
+		c.Assert(err, IsNil)
+		out, err := s.Backend.AppendAuditRecord(*record)
+		c.Assert(err, IsNil)
+		records = append(records, *out)
+		s.Clock.Advance(time.Minute)
+	}
+
+	out, err := s.Backend.GetAuditRecords(storage.AuditRecordsFilter{FromIndex: 2, Limit: 1})
+	c.Assert(err, IsNil)
+	c.Assert(out, compare.DeepEquals, records[1:2])
+
+	last, err := s.Backend.GetLastAuditRecord()
+	c.Assert(err, IsNil)
+	c.Assert(*last, compare.DeepEquals, records[2])
+
+	pruned, err := s.Backend.PruneAuditRecords(start.Add(time.Minute))
+	c.Assert(err, IsNil)
+	c.Assert(pruned, Equals, 1)
+	out, err = s.Backend.GetAuditRecords(storage.AuditRecordsFilter{})
+	c.Assert(err, IsNil)
+	c.Assert(out, compare.DeepEquals, records[1:])
+	c.Assert(storage.VerifyAuditRecords(out), IsNil)
+
+	// new records are chained to the remaining ones
+	record, err := storage.NewAuditRecord("user.login", "T1000I", nil, s.Clock.Now())
+	c.Assert(err, IsNil)
+	appended, err := s.Backend.AppendAuditRecord(*record)
+	c.Assert(err, IsNil)
+	c.Assert(appended.Index, Equals, uint64(4))
+	c.Assert(appended.PrevHash, Equals, records[2].Hash)
+}
+
+func (s *StorageSuite) AuditCursorsCRUD(c *C) {
+	cursors, err := s.Backend.GetAuditCursors()
+	c.Assert(err, IsNil)
+	c.Assert(cursors, HasLen, 0)
+
+	c.Assert(s.Backend.UpsertAuditCursor("forwarder1", 1), IsNil)
+	c.Assert(s.Backend.UpsertAuditCursor("forwarder2", 2), IsNil)
+	c.Assert(s.Backend.UpsertAuditCursor("forwarder1", 3), IsNil)
+	cursors, err = s.Backend.GetAuditCursors()
+	c.Assert(err, IsNil)
+	c.Assert(cursors, DeepEquals, map[string]uint64{"forwarder1": 3, "forwarder2": 2})
+
+	c.Assert(s.Backend.DeleteAuditCursor("forwarder2"), IsNil)
+	err = s.Backend.DeleteAuditCursor("forwarder2")
+	c.Assert(trace.IsNotFound(err), Equals, true, Commentf("%v", err))
+	cursors, err = s.Backend.GetAuditCursors()
+	c.Assert(err, IsNil)
+	c.Assert(cursors, DeepEquals, map[string]uint64{"forwarder1": 3})
+
+	c.Assert(s.Backend.AddPendingAuditRecord(12), IsNil)
+	c.Assert(s.Backend.AddPendingAuditRecord(3), IsNil)
+	pending, err := s.Backend.GetPendingAuditRecords()
+	c.Assert(err, IsNil)
+	c.Assert(pending, DeepEquals, []uint64{3, 12})
+	c.Assert(s.Backend.DeletePendingAuditRecord(3), IsNil)
+	pending, err = s.Backend.GetPendingAuditRecords()
+	c.Assert(err, IsNil)
+	c.Assert(pending, DeepEquals, []uint64{12})
+}
+
+// PasswordPolicyCRUD tests password policy operations
+func (s *StorageSuite) PasswordPolicyCRUD(c *C) {
+	_, err := s.Backend.GetPasswordPolicy()
+	c.Assert(trace.IsNotFound(err), Equals, true, Commentf("%v", err))
+
+	policy := storage.NewPasswordPolicy(storage.PasswordPolicySpecV1{
+		MinLength:         10,
+		CharacterClasses:  []string{storage.PasswordClassDigit},
+		HistoryDepth:      3,
+		MaxAge:            teleservices.NewDuration(90 * 24 * time.Hour),
+		MaxFailedAttempts: 3,
+		LockoutDuration:   teleservices.NewDuration(time.Hour),
+	})
+	c.Assert(s.Backend.UpsertPasswordPolicy(policy), IsNil)
+
+	out, err := s.Backend.GetPasswordPolicy()
+	c.Assert(err, IsNil)
+	c.Assert(out, compare.DeepEquals, policy)
+
+	c.Assert(s.Backend.DeletePasswordPolicy(), IsNil)
+	err = s.Backend.DeletePasswordPolicy()
+	c.Assert(trace.IsNotFound(err), Equals, true, Commentf("%v", err))
+}
+
 // PeersCRUD tests peers operations
 func (s *StorageSuite) PeersCRUD(c *C) {
 
@@ -147,6 +147,16 @@ type User interface {
 	SetPassword(pass string)
 	// GetPassword returns password hash
 	GetPassword() string
+	// GetPasswordHistory returns hashes of the previous passwords, most recent first
+	GetPasswordHistory() []string
+	// SetPasswordHistory sets hashes of the previous passwords
+	SetPasswordHistory([]string)
+	// GetPasswordChanged returns the time the password has been last set
+	GetPasswordChanged() time.Time
+	// SetPasswordChanged sets the time the password has been last set
+	SetPasswordChanged(time.Time)
+	// SetStatus sets user login status
+	SetStatus(teleservices.LoginStatus)
 	// GetHOTP sets HOTP token value
 	GetHOTP() []byte
 	// GetAccountID returns user account ID
@@ -231,6 +241,7 @@ func (u *UserV2) WithoutSecrets() User {
 		Spec:     u.Spec,
 	}
 	copy.Spec.Password = ""
+	copy.Spec.PasswordHistory = nil
 	copy.Spec.HOTP = nil
 	return copy
 }
@@ -326,6 +337,8 @@ const UserSpecV2Extension = `
   "cluster_name": {"type": "string"},
   "hotp": {"type": "string"},
   "password": {"type": "string"},
+  "password_history": {"type": "array", "items": {"type": "string"}},
+  "password_changed": {"type": "string"},
   "ops_center": {"type": "string"},
   "full_name": {"type": "string"}
 `
@@ -372,6 +385,12 @@ type UserSpecV2 struct {
 	// Password contains bcrypted password for human users
 	Password string `json:"password"`
 
+	// PasswordHistory contains bcrypted previous passwords, most recent first
+	PasswordHistory []string `json:"password_history,omitempty"`
+
+	// PasswordChanged is when the password has been last set
+	PasswordChanged time.Time `json:"password_changed,omitempty"`
+
 	// HOTP is HOTP secret used to generate 2nd factor auth challenges
 	HOTP []byte `json:"hotp,omitempty"`
 
@@ -503,6 +522,31 @@ func (u *UserV2) GetPassword() string {
 	return u.Spec.Password
 }
 
+// GetPasswordHistory returns hashes of the previous passwords, most recent first
+func (u *UserV2) GetPasswordHistory() []string {
+	return u.Spec.PasswordHistory
+}
+
+// SetPasswordHistory sets hashes of the previous passwords
+func (u *UserV2) SetPasswordHistory(history []string) {
+	u.Spec.PasswordHistory = history
+}
+
+// GetPasswordChanged returns the time the password has been last set
+func (u *UserV2) GetPasswordChanged() time.Time {
+	return u.Spec.PasswordChanged
+}
+
+// SetPasswordChanged sets the time the password has been last set
+func (u *UserV2) SetPasswordChanged(changed time.Time) {
+	u.Spec.PasswordChanged = changed
+}
+
+// SetStatus sets user login status
+func (u *UserV2) SetStatus(status teleservices.LoginStatus) {
+	u.Spec.Status = status
+}
+
 func (u *UserV2) String() string {
 	return fmt.Sprintf("User(Name=%v, Cluster=%v, Roles=%v, Identities=%v)",
 		u.Metadata.Name, u.Spec.ClusterName, u.Spec.Roles, u.Spec.OIDCIdentities)
@@ -669,6 +713,7 @@ func UnmarshalUser(bytes []byte) (User, error) {
 		utils.UTC(&u.Spec.Status.LockExpires)
 		utils.UTC(&u.Spec.Status.LockedTime)
 		utils.UTC(&u.Spec.Expires)
+		utils.UTC(&u.Spec.PasswordChanged)
 		u.rawObject = u
 		return &u, nil
 	}
@@ -760,6 +805,12 @@ type UpdateUserReq struct {
 	HOTP *[]byte
 	// Password is a request to update user password
 	Password *string
+	// PasswordHistory sets hashes of the previous passwords
+	PasswordHistory *[]string
+	// PasswordChanged sets the time the password has been last set
+	PasswordChanged *time.Time
+	// Status sets user login status
+	Status *teleservices.LoginStatus
 	// Roles sets user roles
 	Roles *[]string
 	// User full name
@@ -34,6 +34,7 @@ func Provider() terraform.ResourceProvider {
 		ResourcesMap: map[string]*schema.Resource{
 			"gravity_token":                   resourceGravityToken(),
 			"gravity_github":                  resourceGravityGithub(),
+			"gravity_oidc":                    resourceGravityOIDC(),
 			"gravity_user":                    resourceGravityUser(),
 			"gravity_log_forwarder":           resourceGravityLogForwarder(),
 			"gravity_tlskeypair":              resourceGravityTLSKeyPair(),
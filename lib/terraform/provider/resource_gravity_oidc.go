@@ -0,0 +1,220 @@
+package provider
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/gravitational/gravity/lib/defaults"
+	"github.com/gravitational/gravity/lib/ops"
+	"github.com/gravitational/gravity/lib/ops/opsclient"
+	"github.com/gravitational/teleport/lib/services"
+	"github.com/gravitational/trace"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourceGravityOIDC() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceGravityOIDCCreateOrUpdate,
+		Read:   resourceGravityOIDCRead,
+		Update: resourceGravityOIDCCreateOrUpdate,
+		Delete: resourceGravityOIDCDelete,
+		Exists: resourceGravityOIDCExists,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(1 * time.Minute),
+			Delete: schema.DefaultTimeout(1 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The name of the resource",
+			},
+			"issuer_url": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"client_id": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"client_secret": {
+				Type:     schema.TypeString,
+				Required: true,
+
+				Sensitive: true,
+			},
+			"redirect_url": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"display": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"scope": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"claims_to_roles": {
+				Type:     schema.TypeSet,
+				Required: true,
+				MinItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"claim": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"value": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"roles": {
+							Type:     schema.TypeList,
+							Required: true,
+							MinItems: 1,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func parseClaimMapping(m map[string]interface{}) services.ClaimMapping {
+	return services.ClaimMapping{
+		Claim: m["claim"].(string),
+		Value: m["value"].(string),
+		Roles: ExpandStringList(m["roles"].([]interface{})),
+	}
+}
+
+func resourceGravityOIDCCreateOrUpdate(d *schema.ResourceData, m interface{}) error {
+	client := m.(*opsclient.Client)
+
+	cluster, err := client.GetLocalSite()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	name := d.Get("name").(string)
+
+	var mappings []services.ClaimMapping
+	if v := d.Get("claims_to_roles").(*schema.Set); v.Len() > 0 {
+		mappings = make([]services.ClaimMapping, 0, v.Len())
+		for _, v := range v.List() {
+			mappings = append(mappings, parseClaimMapping(v.(map[string]interface{})))
+		}
+	}
+
+	connector := services.NewOIDCConnector(
+		name,
+		services.OIDCConnectorSpecV2{
+			IssuerURL:     d.Get("issuer_url").(string),
+			ClientID:      d.Get("client_id").(string),
+			ClientSecret:  d.Get("client_secret").(string),
+			RedirectURL:   d.Get("redirect_url").(string),
+			Display:       d.Get("display").(string),
+			Scope:         ExpandStringList(d.Get("scope").([]interface{})),
+			ClaimsToRoles: mappings,
+		},
+	)
+
+	clusterKey := ops.SiteKey{
+		AccountID:  defaults.SystemAccountID,
+		SiteDomain: cluster.Domain,
+	}
+	err = client.UpsertOIDCConnector(context.TODO(), clusterKey, connector)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	log.Printf("[INFO] OIDC connector %s created", name)
+	d.SetId(name)
+
+	return nil
+}
+
+func resourceGravityOIDCRead(d *schema.ResourceData, m interface{}) error {
+	client := m.(*opsclient.Client)
+	name := d.Get("name").(string)
+
+	cluster, err := client.GetLocalSite()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	clusterKey := ops.SiteKey{
+		AccountID:  defaults.SystemAccountID,
+		SiteDomain: cluster.Domain,
+	}
+
+	connector, err := client.GetOIDCConnector(clusterKey, name, true)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	d.Set("name", connector.GetName())
+	d.Set("issuer_url", connector.GetIssuerURL())
+	d.Set("client_id", connector.GetClientID())
+	d.Set("client_secret", connector.GetClientSecret())
+	d.Set("redirect_url", connector.GetRedirectURL())
+	d.Set("display", connector.GetDisplay())
+	d.Set("scope", connector.GetScope())
+
+	mappings := connector.GetClaimsToRoles()
+	var claimsToRoles []interface{}
+	for _, mapping := range mappings {
+		claimsToRoles = append(claimsToRoles, map[string]interface{}{
+			"claim": mapping.Claim,
+			"value": mapping.Value,
+			"roles": mapping.Roles,
+		})
+	}
+	d.Set("claims_to_roles", claimsToRoles)
+
+	return nil
+}
+
+func resourceGravityOIDCDelete(d *schema.ResourceData, m interface{}) error {
+	client := m.(*opsclient.Client)
+
+	cluster, err := client.GetLocalSite()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	clusterKey := ops.SiteKey{
+		AccountID:  defaults.SystemAccountID,
+		SiteDomain: cluster.Domain,
+	}
+
+	name := d.Get("name").(string)
+
+	err = client.DeleteOIDCConnector(context.TODO(), clusterKey, name)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	return nil
+}
+
+func resourceGravityOIDCExists(d *schema.ResourceData, m interface{}) (bool, error) {
+	err := resourceGravityOIDCRead(d, m)
+	if err != nil && trace.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, trace.Wrap(err)
+	}
+	return true, nil
+}
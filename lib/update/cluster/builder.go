@@ -334,19 +334,7 @@ func (r phaseBuilder) etcdPlan(
 		root.Description = fmt.Sprintf("Upgrade etcd to %v", desiredVersion)
 	}
 
-	// Backup etcd on each master server
-	// Do each master, just in case
-	backupEtcd := update.Phase{
-		ID:          root.ChildLiteral("backup"),
-		Description: "Backup etcd data",
-	}
-	backupEtcd.AddParallel(r.etcdBackupNode(leadMaster, backupEtcd))
-
-	for _, server := range otherMasters {
-		p := r.etcdBackupNode(server, backupEtcd)
-		backupEtcd.AddParallel(p)
-	}
-
+	backupEtcd := r.etcdBackup(root, leadMaster, otherMasters)
 	root.AddSequential(backupEtcd)
 
 	// Shutdown etcd
@@ -437,6 +425,79 @@ func (r phaseBuilder) etcdPlan(
 	return &root
 }
 
+// etcdRollingPlan returns the phase to upgrade etcd by replacing members
+// one at a time, keeping the cluster available during the upgrade.
+// The final phase verifies the outcome and falls back to the snapshot restore
+// if any of the members failed to upgrade
+func (r phaseBuilder) etcdRollingPlan(
+	leadMaster storage.Server,
+	otherMasters []storage.Server,
+	workers []storage.Server,
+	currentVersion string,
+	desiredVersion string,
+) *update.Phase {
+	root := update.RootPhase(update.Phase{
+		ID:          etcdPhaseName,
+		Description: fmt.Sprintf("Rolling upgrade of etcd %v to %v", currentVersion, desiredVersion),
+	})
+
+	// The backup is only used if the rolling upgrade fails
+	root.AddSequential(r.etcdBackup(root, leadMaster, otherMasters))
+
+	// Replace members one at a time, the lead master is replaced last
+	rolling := update.Phase{
+		ID:          root.ChildLiteral("rolling"),
+		Description: "Upgrade etcd servers one at a time",
+	}
+	for _, server := range otherMasters {
+		rolling.AddSequential(r.etcdRollingNode(server, rolling, desiredVersion))
+	}
+	rolling.AddSequential(r.etcdRollingNode(leadMaster, rolling, desiredVersion))
+	for _, server := range workers {
+		rolling.AddSequential(r.etcdRollingNode(server, rolling, desiredVersion))
+	}
+	root.AddSequential(rolling)
+
+	root.AddSequential(update.Phase{
+		ID:          root.ChildLiteral("fallback"),
+		Description: "Verify etcd upgrade or restore etcd data from backup",
+		Executor:    updateEtcdFallback,
+		Data: &storage.OperationPhaseData{
+			Server: &leadMaster,
+			Data:   desiredVersion,
+		},
+	})
+	return &root
+}
+
+// etcdBackup returns the phase to backup etcd on each master server.
+// Do each master, just in case
+func (r phaseBuilder) etcdBackup(root update.Phase, leadMaster storage.Server, otherMasters []storage.Server) update.Phase {
+	backupEtcd := update.Phase{
+		ID:          root.ChildLiteral("backup"),
+		Description: "Backup etcd data",
+	}
+	backupEtcd.AddParallel(r.etcdBackupNode(leadMaster, backupEtcd))
+
+	for _, server := range otherMasters {
+		p := r.etcdBackupNode(server, backupEtcd)
+		backupEtcd.AddParallel(p)
+	}
+	return backupEtcd
+}
+
+func (r phaseBuilder) etcdRollingNode(server storage.Server, parent update.Phase, desiredVersion string) update.Phase {
+	return update.Phase{
+		ID:          parent.ChildLiteral(server.Hostname),
+		Description: fmt.Sprintf("Upgrade etcd on node %q", server.Hostname),
+		Executor:    updateEtcdRolling,
+		Data: &storage.OperationPhaseData{
+			Server: &server,
+			Data:   desiredVersion,
+		},
+	}
+}
+
 func (r phaseBuilder) etcdBackupNode(server storage.Server, parent update.Phase) update.Phase {
 	return update.Phase{
 		ID:          parent.ChildLiteral(server.Hostname),
@@ -659,6 +720,27 @@ func shouldUpdateEtcd(p planConfig) (updateEtcd bool, installedEtcdVersion strin
 	return updateEtcd, installedEtcdVersion, updateEtcdVersion, nil
 }
 
+// supportsRollingEtcdUpgrade determines whether etcd can be upgraded one member at a time.
+// etcd only supports clusters with mixed member versions between adjacent minor releases
+// of the same major version, and replacing a member requires enough masters to keep quorum
+func supportsRollingEtcdUpgrade(currentVersion, desiredVersion string, numMasters int) bool {
+	if currentVersion == "" || numMasters < defaults.EtcdRollingUpgradeMinMasters {
+		return false
+	}
+	current, err := semver.NewVersion(currentVersion)
+	if err != nil {
+		return false
+	}
+	desired, err := semver.NewVersion(desiredVersion)
+	if err != nil {
+		return false
+	}
+	if current.Major != desired.Major || desired.Minor < current.Minor {
+		return false
+	}
+	return desired.Minor-current.Minor <= 1
+}
+
 func getEtcdVersion(searchLabel string, locator loc.Locator, packageService pack.PackageService) (*semver.Version, error) {
 	manifest, err := pack.GetPackageManifest(packageService, locator)
 	if err != nil {
@@ -256,6 +256,69 @@ func (s *PlanSuite) TestCorrectlyDeterminesWhetherToUpdateEtcd(c *check.C) {
 	c.Assert(updateVersion, check.Equals, "3.3.3")
 }
 
+func (s *PlanSuite) TestDeterminesWhetherEtcdSupportsRollingUpgrade(c *check.C) {
+	var testCases = []struct {
+		current, desired string
+		masters          int
+		supported        bool
+		comment          string
+	}{
+		{current: "3.3.11", desired: "3.3.12", masters: 3, supported: true, comment: "patch upgrade"},
+		{current: "3.3.11", desired: "3.4.3", masters: 5, supported: true, comment: "adjacent minor upgrade"},
+		{current: "3.2.24", desired: "3.4.3", masters: 3, supported: false, comment: "skips a minor version"},
+		{current: "2.3.8", desired: "3.3.11", masters: 3, supported: false, comment: "major upgrade"},
+		{current: "3.3.11", desired: "3.3.12", masters: 1, supported: false, comment: "loses quorum"},
+		{current: "", desired: "3.3.12", masters: 3, supported: false, comment: "unknown installed version"},
+	}
+	for _, tc := range testCases {
+		comment := check.Commentf(tc.comment)
+		c.Assert(supportsRollingEtcdUpgrade(tc.current, tc.desired, tc.masters),
+			check.Equals, tc.supported, comment)
+	}
+}
+
+func (s *PlanSuite) TestEtcdRollingPlan(c *check.C) {
+	builder := phaseBuilder{}
+	leadMaster := updates[1].Server
+	otherMaster := updates[0].Server
+	worker := updates[2].Server
+	phase := builder.etcdRollingPlan(leadMaster,
+		[]storage.Server{otherMaster}, []storage.Server{worker}, "3.3.11", "3.3.12")
+
+	rollingNode := func(server storage.Server, requires ...string) storage.OperationPhase {
+		return storage.OperationPhase{
+			ID:          fmt.Sprintf("/etcd/rolling/%v", server.Hostname),
+			Description: fmt.Sprintf("Upgrade etcd on node %q", server.Hostname),
+			Executor:    updateEtcdRolling,
+			Requires:    requires,
+			Data: &storage.OperationPhaseData{
+				Server: &server,
+				Data:   "3.3.12",
+			},
+		}
+	}
+	c.Assert(phase.Phases[1], check.DeepEquals, storage.OperationPhase{
+		ID:          "/etcd/rolling",
+		Description: "Upgrade etcd servers one at a time",
+		Requires:    []string{"/etcd/backup"},
+		Phases: []storage.OperationPhase{
+			rollingNode(otherMaster),
+			rollingNode(leadMaster, "/etcd/rolling/node-1"),
+			rollingNode(worker, "/etcd/rolling/node-2"),
+		},
+	})
+	c.Assert(phase.Phases[2], check.DeepEquals, storage.OperationPhase{
+		ID:          "/etcd/fallback",
+		Description: "Verify etcd upgrade or restore etcd data from backup",
+		Executor:    updateEtcdFallback,
+		Requires:    []string{"/etcd/rolling"},
+		Data: &storage.OperationPhaseData{
+			Server: &leadMaster,
+			Data:   "3.3.12",
+		},
+	})
+}
+
 func newTestPlan(c *check.C, params params) planConfig {
 	config := planConfig{
 		operator:  testOperator,
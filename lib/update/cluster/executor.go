@@ -76,6 +76,11 @@ const (
 	updateEtcdRestart = "etcd_restart"
 	// updateEtcdRestartGravity is the phase that restarts gravity-site
 	updateEtcdRestartGravity = "etcd_restart_gravity"
+	// updateEtcdRolling is the phase that replaces a single etcd member during rolling upgrade
+	updateEtcdRolling = "etcd_rolling"
+	// updateEtcdFallback is the phase that verifies rolling etcd upgrade and
+	// restores etcd data from backup if the upgrade has failed
+	updateEtcdFallback = "etcd_fallback"
 	// cleanupNode is the phase to clean up a node after the upgrade
 	cleanupNode = "cleanup_node"
 	// openebs is the phase that creates OpenEBS configuration
@@ -159,6 +164,10 @@ func fsmSpec(c Config) fsm.FSMSpecFunc {
 			return libphase.NewPhaseUpgradeEtcdRestart(p.Phase, logger)
 		case updateEtcdRestartGravity:
 			return libphase.NewPhaseUpgradeGravitySiteRestart(p.Phase, c.Client, logger)
+		case updateEtcdRolling:
+			return libphase.NewPhaseUpgradeEtcdRolling(p.Phase, p.Plan.Servers, logger)
+		case updateEtcdFallback:
+			return libphase.NewPhaseUpgradeEtcdFallback(p.Phase, p.Plan.Servers, c.Runner, c.Client, logger)
 		case cleanupNode:
 			return libphase.NewGarbageCollectPhase(p, remote, logger)
 		case openebs:
@@ -0,0 +1,644 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package phases
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/gravitational/gravity/lib/clients"
+	"github.com/gravitational/gravity/lib/defaults"
+	"github.com/gravitational/gravity/lib/fsm"
+	"github.com/gravitational/gravity/lib/rpc"
+	"github.com/gravitational/gravity/lib/schema"
+	"github.com/gravitational/gravity/lib/state"
+	"github.com/gravitational/gravity/lib/storage"
+	"github.com/gravitational/gravity/lib/update"
+	"github.com/gravitational/gravity/lib/utils"
+
+	etcd "github.com/coreos/etcd/client"
+	"github.com/gravitational/trace"
+	log "github.com/sirupsen/logrus"
+	kubeapi "k8s.io/client-go/kubernetes"
+)
+
+// Rolling etcd upgrade
+// For upgrades between minor-version-compatible etcd releases, etcd supports
+// running members of different versions side by side. Instead of taking the whole
+// cluster down, members are replaced one at a time so the cluster keeps quorum
+// and the API stays available:
+//
+// 1. Backup all etcd data via API (same as the offline upgrade). The backup is only used by the fallback
+// 2. For each master, one at a time:
+//      Verify that all members are healthy (the cluster can afford losing a member)
+//      Remove the member from the cluster and stop etcd
+//      Switch to the new version of etcd with a blank data directory (planet etcd upgrade)
+//      Add the member back and start etcd so it syncs the data from its peers
+//      Wait for the member to become healthy and report the new version
+// 3. Restart etcd proxies on regular nodes with the new version
+// 4. Fallback: verify that every member is healthy and runs the new version.
+//      If not and the upgraded members have quorum, replace the lagging members one at a time.
+//      Otherwise, run the snapshot restore procedure of the offline upgrade across the cluster,
+//      switching the version only on the lagging members.
+//
+// Member phases never fail the plan on a health check failure - instead, they stop
+// replacing members and leave the recovery to the fallback phase.
+// A member phase that failed midway can be resumed: if the member has already been
+// removed from the cluster, it is added back without being removed again.
+
+// PhaseUpgradeEtcdRolling replaces a single etcd member with a member running the new version
+type PhaseUpgradeEtcdRolling struct {
+	log.FieldLogger
+	// Server is the server to upgrade etcd on
+	Server storage.Server
+	// Version is the etcd version to upgrade to
+	Version string
+	// Cluster is the etcd cluster the member belongs to
+	Cluster *etcdCluster
+}
+
+// NewPhaseUpgradeEtcdRolling returns a new executor that upgrades etcd on a single node
+// while the rest of the cluster remains operational
+func NewPhaseUpgradeEtcdRolling(phase storage.OperationPhase, servers []storage.Server, logger log.FieldLogger) (fsm.PhaseExecutor, error) {
+	if phase.Data == nil || phase.Data.Server == nil {
+		return nil, trace.BadParameter("phase %q has no server", phase.ID)
+	}
+	cluster, err := newEtcdCluster(servers)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return &PhaseUpgradeEtcdRolling{
+		FieldLogger: logger,
+		Server:      *phase.Data.Server,
+		Version:     phase.Data.Data,
+		Cluster:     cluster,
+	}, nil
+}
+
+// Execute replaces the etcd member running on this node with a member of the new version.
+// Regular nodes run etcd in proxy mode and are simply restarted with the new version.
+// If a previous attempt has already removed the member from the cluster, the
+// replacement is resumed
+func (p *PhaseUpgradeEtcdRolling) Execute(ctx context.Context) error {
+	if !p.isMaster() {
+		p.Info("Upgrade etcd proxy.")
+		return trace.Wrap(p.upgradeProxy(ctx, "upgrade"))
+	}
+	member, err := p.Cluster.findMember(ctx, p.Server)
+	if err != nil && !trace.IsNotFound(err) {
+		return trace.Wrap(err)
+	}
+	if isStarted(member) {
+		upgraded, err := p.Cluster.memberHasVersion(ctx, p.Server, p.Version)
+		if err == nil && upgraded {
+			p.Infof("Etcd member on node %v already runs version %v.", p.Server.Hostname, p.Version)
+			return nil
+		}
+		// Replacing a member is only safe if the remaining members are healthy
+		if err := p.Cluster.checkHealth(ctx, ""); err != nil {
+			p.WithError(err).Warn("Etcd cluster is unhealthy, will not replace member and defer to snapshot restore.")
+			return nil
+		}
+	} else {
+		// The member has been removed (or re-added but not started) by a previous
+		// attempt, the remaining members are expected to have quorum
+		if err := p.Cluster.checkLeader(ctx, p.Server); err != nil {
+			p.WithError(err).Warn("Etcd cluster has no leader, will not resume member replacement and defer to snapshot restore.")
+			return nil
+		}
+		p.Infof("Resume replacement of etcd member on node %v.", p.Server.Hostname)
+	}
+	p.Infof("Replace etcd member on node %v.", p.Server.Hostname)
+	if err := p.Cluster.replaceMember(ctx, p.Server, "upgrade", p.runPlanetCommand, p.FieldLogger); err != nil {
+		return trace.Wrap(err)
+	}
+	if err := p.Cluster.waitForMember(ctx, p.Server, p.Version); err != nil {
+		p.WithError(err).Warn("Etcd member failed health check, will defer to snapshot restore.")
+		return nil
+	}
+	p.Infof("Etcd member on node %v has been upgraded to %v.", p.Server.Hostname, p.Version)
+	return nil
+}
+
+// Rollback replaces the member with a member running the previous version of etcd
+func (p *PhaseUpgradeEtcdRolling) Rollback(ctx context.Context) error {
+	if !p.isMaster() {
+		p.Info("Rollback etcd proxy.")
+		return trace.Wrap(p.upgradeProxy(ctx, "rollback"))
+	}
+	member, err := p.Cluster.findMember(ctx, p.Server)
+	if err != nil && !trace.IsNotFound(err) {
+		return trace.Wrap(err)
+	}
+	if isStarted(member) {
+		upgraded, err := p.Cluster.memberHasVersion(ctx, p.Server, p.Version)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		if !upgraded {
+			p.Infof("Etcd member on node %v has not been upgraded.", p.Server.Hostname)
+			return nil
+		}
+	}
+	p.Infof("Rollback etcd member on node %v.", p.Server.Hostname)
+	if err := p.Cluster.replaceMember(ctx, p.Server, "rollback", p.runPlanetCommand, p.FieldLogger); err != nil {
+		return trace.Wrap(err)
+	}
+	return trace.Wrap(p.Cluster.waitForMember(ctx, p.Server, ""))
+}
+
+// PreCheck makes sure the etcd cluster is reachable from the node
+func (p *PhaseUpgradeEtcdRolling) PreCheck(ctx context.Context) error {
+	if !p.isMaster() {
+		return nil
+	}
+	_, err := p.Cluster.findMember(ctx, p.Server)
+	if err != nil && !trace.IsNotFound(err) {
+		return trace.Wrap(err)
+	}
+	return nil
+}
+
+// PostCheck is no-op for this phase
+func (*PhaseUpgradeEtcdRolling) PostCheck(context.Context) error {
+	return nil
+}
+
+// upgradeProxy restarts the etcd proxy with the version selected by the specified
+// planet command (either upgrade or rollback)
+func (p *PhaseUpgradeEtcdRolling) upgradeProxy(ctx context.Context, versionCommand string) error {
+	for _, args := range [][]string{
+		{"etcd", "disable"},
+		{"etcd", versionCommand},
+		{"etcd", "enable"},
+	} {
+		if err := p.runPlanetCommand(ctx, args...); err != nil {
+			return trace.Wrap(err)
+		}
+	}
+	return nil
+}
+
+func (p *PhaseUpgradeEtcdRolling) runPlanetCommand(ctx context.Context, args ...string) error {
+	out, err := utils.RunPlanetCommand(ctx, p.FieldLogger, args...)
+	if err != nil {
+		return trace.Wrap(err, "failed to run %v: %s", args, out)
+	}
+	p.Info("command output: ", string(out))
+	return nil
+}
+
+func (p *PhaseUpgradeEtcdRolling) isMaster() bool {
+	return p.Server.ClusterRole == string(schema.ServiceRoleMaster)
+}
+
+// PhaseUpgradeEtcdFallback verifies the outcome of the rolling etcd upgrade and
+// upgrades the cluster using the snapshot restore procedure if any of the members
+// is not healthy or has not been upgraded
+type PhaseUpgradeEtcdFallback struct {
+	log.FieldLogger
+	// Leader is the lead master server that keeps the etcd backup
+	Leader storage.Server
+	// Servers lists all cluster servers
+	Servers []storage.Server
+	// Version is the etcd version to upgrade to
+	Version string
+	// Cluster is the cluster's etcd cluster
+	Cluster *etcdCluster
+	// Runner is used to run commands on cluster nodes
+	Runner rpc.AgentRepository
+	// Client is the cluster Kubernetes client
+	Client *kubeapi.Clientset
+}
+
+// NewPhaseUpgradeEtcdFallback returns a new executor that completes the rolling etcd
+// upgrade with a snapshot restore if necessary
+func NewPhaseUpgradeEtcdFallback(phase storage.OperationPhase, servers []storage.Server, runner rpc.AgentRepository, client *kubeapi.Clientset, logger log.FieldLogger) (fsm.PhaseExecutor, error) {
+	if client == nil {
+		return nil, trace.BadParameter("phase %q must be run from a master node (requires kubernetes client)", phase.ID)
+	}
+	if phase.Data == nil || phase.Data.Server == nil {
+		return nil, trace.BadParameter("phase %q has no server", phase.ID)
+	}
+	cluster, err := newEtcdCluster(servers)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return &PhaseUpgradeEtcdFallback{
+		FieldLogger: logger,
+		Leader:      *phase.Data.Server,
+		Servers:     servers,
+		Version:     phase.Data.Data,
+		Cluster:     cluster,
+		Runner:      runner,
+		Client:      client,
+	}, nil
+}
+
+// Execute completes the etcd upgrade if the rolling upgrade has not completed successfully.
+// Members that already run the new version are left intact: if they have quorum,
+// the remaining members are replaced one at a time, otherwise the cluster is
+// restored from the snapshot and only the lagging members are switched to the new version
+func (p *PhaseUpgradeEtcdFallback) Execute(ctx context.Context) error {
+	err := p.Cluster.checkHealth(ctx, p.Version)
+	if err == nil {
+		p.Infof("All etcd members are healthy and run version %v.", p.Version)
+		return nil
+	}
+	p.WithError(err).Warn("Rolling etcd upgrade has not completed.")
+	lagging := p.Cluster.laggingMasters(ctx, p.Version, p.FieldLogger)
+	if len(lagging) != 0 && p.Cluster.hasQuorum(ctx, len(p.Cluster.masters)-len(lagging)) {
+		err := p.replaceMembers(ctx, lagging)
+		if err == nil {
+			return nil
+		}
+		p.WithError(err).Warn("Failed to replace etcd members, fall back to snapshot restore.")
+		lagging = p.Cluster.laggingMasters(ctx, p.Version, p.FieldLogger)
+	}
+	return trace.Wrap(p.restoreSnapshot(ctx, lagging))
+}
+
+// replaceMembers upgrades the specified masters by replacing their etcd members one at a time
+func (p *PhaseUpgradeEtcdFallback) replaceMembers(ctx context.Context, lagging []storage.Server) error {
+	for _, server := range lagging {
+		server := server
+		p.Infof("Replace etcd member on node %v.", server.Hostname)
+		run := func(ctx context.Context, args ...string) error {
+			return p.run(ctx, server, args...)
+		}
+		if err := p.Cluster.replaceMember(ctx, server, "upgrade", run, p.FieldLogger); err != nil {
+			return trace.Wrap(err)
+		}
+		if err := p.Cluster.waitForMember(ctx, server, p.Version); err != nil {
+			return trace.Wrap(err)
+		}
+	}
+	return nil
+}
+
+// restoreSnapshot runs the offline etcd upgrade with the snapshot restore across the cluster.
+// The version is only switched on the lagging masters, the rest of the cluster
+// has already been upgraded by the rolling phases
+func (p *PhaseUpgradeEtcdFallback) restoreSnapshot(ctx context.Context, lagging []storage.Server) error {
+	p.Info("Restore etcd data from snapshot.")
+	backupFile, err := backupFile()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	// API outage starts
+	if err := p.runOnAll(ctx, "etcd", "disable", "--stop-api"); err != nil {
+		return trace.Wrap(err)
+	}
+	for _, server := range p.Servers {
+		args := offlineUpgradeCommand(server, lagging)
+		if len(args) == 0 {
+			continue
+		}
+		if err := p.run(ctx, server, args...); err != nil {
+			return trace.Wrap(err)
+		}
+	}
+	if err := p.runOnAll(ctx, "etcd", "enable", "--upgrade"); err != nil {
+		return trace.Wrap(err)
+	}
+	out, err := utils.RunCommand(ctx, p.FieldLogger,
+		utils.PlanetCommandArgs(defaults.WaitForEtcdScript, "https://127.0.0.2:2379")...)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	p.Info("command output: ", string(out))
+	if err := p.run(ctx, p.Leader, "etcd", "restore", backupFile); err != nil {
+		return trace.Wrap(err)
+	}
+	// API outage ends
+	if err := p.runOnAll(ctx, "etcd", "disable", "--upgrade"); err != nil {
+		return trace.Wrap(err)
+	}
+	if err := p.runOnAll(ctx, "etcd", "enable"); err != nil {
+		return trace.Wrap(err)
+	}
+	return trace.Wrap(restartGravitySite(ctx, p.Client, p.FieldLogger))
+}
+
+// offlineUpgradeCommand returns the planet command that prepares etcd on the specified
+// server for the snapshot restore. Lagging masters are switched to the new version,
+// upgraded masters only need a blank data directory, and proxies have already been
+// upgraded by the rolling phases so there's nothing to do
+func offlineUpgradeCommand(server storage.Server, lagging []storage.Server) []string {
+	for _, master := range lagging {
+		if master.AdvertiseIP == server.AdvertiseIP {
+			return []string{"etcd", "upgrade"}
+		}
+	}
+	if server.ClusterRole == string(schema.ServiceRoleMaster) {
+		return []string{"etcd", "wipe", "--confirm"}
+	}
+	return nil
+}
+
+// Rollback is no-op for this phase: the member phases roll back their own changes
+// and the data directories of the previous version are left intact by the snapshot restore
+func (*PhaseUpgradeEtcdFallback) Rollback(context.Context) error {
+	return nil
+}
+
+// PreCheck makes sure the etcd backup is available on the leader node
+func (p *PhaseUpgradeEtcdFallback) PreCheck(ctx context.Context) error {
+	backupFile, err := backupFile()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	exists, err := utils.IsFile(backupFile)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if !exists {
+		return trace.NotFound("etcd backup %v not found", backupFile)
+	}
+	return nil
+}
+
+// PostCheck verifies that the etcd cluster is healthy and upgraded
+func (p *PhaseUpgradeEtcdFallback) PostCheck(ctx context.Context) error {
+	return trace.Wrap(update.Retry(ctx, func() error {
+		return p.Cluster.checkHealth(ctx, p.Version)
+	}, defaults.EtcdMemberHealthTimeout))
+}
+
+// runOnAll runs the specified planet command on all cluster servers in order
+func (p *PhaseUpgradeEtcdFallback) runOnAll(ctx context.Context, args ...string) error {
+	for _, server := range p.Servers {
+		if err := p.run(ctx, server, args...); err != nil {
+			return trace.Wrap(err)
+		}
+	}
+	return nil
+}
+
+// run runs the specified planet command on the given server
+func (p *PhaseUpgradeEtcdFallback) run(ctx context.Context, server storage.Server, args ...string) error {
+	agent, err := p.Runner.GetClient(ctx, server.AdvertiseIP)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	var out bytes.Buffer
+	err = agent.Command(ctx, p.FieldLogger, &out, utils.PlanetEnterCommand(
+		append([]string{defaults.PlanetBin}, args...)...)...)
+	if err != nil {
+		return trace.Wrap(err, "failed to run %v on %v: %s", args, server.Hostname, out.String())
+	}
+	p.Infof("Ran %v on %v: %s.", args, server.Hostname, out.String())
+	return nil
+}
+
+// etcdCluster provides access to the cluster's etcd members
+type etcdCluster struct {
+	// masters lists the master servers running full etcd members
+	masters []storage.Server
+	// secretsDir is the directory with etcd client credentials
+	secretsDir string
+}
+
+func newEtcdCluster(servers []storage.Server) (*etcdCluster, error) {
+	stateDir, err := state.GetStateDir()
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var masters []storage.Server
+	for _, server := range servers {
+		if server.ClusterRole == string(schema.ServiceRoleMaster) {
+			masters = append(masters, server)
+		}
+	}
+	return &etcdCluster{
+		masters:    masters,
+		secretsDir: state.SecretDir(stateDir),
+	}, nil
+}
+
+// membersExcept returns the members API client that talks to all masters but
+// the specified server
+func (r *etcdCluster) membersExcept(server storage.Server) (etcd.MembersAPI, error) {
+	var endpoints []string
+	for _, master := range r.masters {
+		if master.AdvertiseIP != server.AdvertiseIP {
+			endpoints = append(endpoints, clientURL(master))
+		}
+	}
+	return clients.EtcdMembers(&clients.EtcdConfig{
+		Endpoints:  endpoints,
+		SecretsDir: r.secretsDir,
+	})
+}
+
+// findMember returns the etcd member running on the specified server
+func (r *etcdCluster) findMember(ctx context.Context, server storage.Server) (*etcd.Member, error) {
+	membersAPI, err := r.membersExcept(server)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	members, err := membersAPI.List(ctx)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	for _, member := range members {
+		if utils.StringInSlice(member.PeerURLs, peerURL(server)) {
+			return &member, nil
+		}
+	}
+	return nil, trace.NotFound("no etcd member found for node %v", server.Hostname)
+}
+
+// replaceMember removes the etcd member of the specified server from the cluster,
+// switches etcd to the version selected by planet command versionCommand (either
+// upgrade or rollback) and adds the node back as a new member with a blank data directory.
+// Planet commands are executed on the server with run.
+// The replacement can be resumed: the member is only removed if it is still
+// part of the cluster
+func (r *etcdCluster) replaceMember(ctx context.Context, server storage.Server, versionCommand string, run planetRunner, logger log.FieldLogger) error {
+	membersAPI, err := r.membersExcept(server)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	member, err := r.findMember(ctx, server)
+	if err != nil && !trace.IsNotFound(err) {
+		return trace.Wrap(err)
+	}
+	if member != nil {
+		logger.Infof("Remove etcd member %v.", member.ID)
+		if err := membersAPI.Remove(ctx, member.ID); err != nil {
+			return trace.Wrap(err)
+		}
+	} else {
+		logger.Infof("Etcd member on node %v has already been removed.", server.Hostname)
+	}
+	for _, args := range [][]string{
+		{"etcd", "disable"},
+		{"etcd", versionCommand},
+		{"etcd", "wipe", "--confirm"},
+	} {
+		if err := run(ctx, args...); err != nil {
+			return trace.Wrap(err)
+		}
+	}
+	added, err := membersAPI.Add(ctx, peerURL(server))
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	logger.Infof("Added etcd member %v.", added.ID)
+	return trace.Wrap(run(ctx, "etcd", "enable"))
+}
+
+// planetRunner runs the specified planet command on the node of the etcd member
+type planetRunner func(ctx context.Context, args ...string) error
+
+// laggingMasters returns the masters with etcd members that are missing,
+// unhealthy or do not run the specified version
+func (r *etcdCluster) laggingMasters(ctx context.Context, version string, logger log.FieldLogger) (lagging []storage.Server) {
+	for _, master := range r.masters {
+		member, err := r.findMember(ctx, master)
+		if err == nil && isStarted(member) {
+			err = r.checkMemberVersion(ctx, member.ClientURLs, version)
+		} else if err == nil {
+			err = trace.BadParameter("etcd member on %v has not started", master.Hostname)
+		}
+		if err != nil {
+			logger.WithError(err).Warnf("Etcd member on node %v has not been upgraded.", master.Hostname)
+			lagging = append(lagging, master)
+		}
+	}
+	return lagging
+}
+
+// hasQuorum returns true if the specified number of healthy members
+// form a majority of the cluster and the cluster has a leader
+func (r *etcdCluster) hasQuorum(ctx context.Context, healthy int) bool {
+	if healthy <= len(r.masters)/2 {
+		return false
+	}
+	return r.checkLeader(ctx, storage.Server{}) == nil
+}
+
+// checkLeader verifies that the members on all masters but the specified server
+// have elected a leader
+func (r *etcdCluster) checkLeader(ctx context.Context, server storage.Server) error {
+	membersAPI, err := r.membersExcept(server)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if _, err := membersAPI.Leader(ctx); err != nil {
+		return trace.Wrap(err, "etcd cluster has no leader")
+	}
+	return nil
+}
+
+// checkHealth verifies that all etcd members are started, reachable and the cluster
+// has a leader. If version is not empty, it also verifies that every member runs
+// the specified version of etcd
+func (r *etcdCluster) checkHealth(ctx context.Context, version string) error {
+	membersAPI, err := clients.EtcdMembers(&clients.EtcdConfig{
+		Endpoints:  r.endpoints(),
+		SecretsDir: r.secretsDir,
+	})
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	members, err := membersAPI.List(ctx)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if len(members) != len(r.masters) {
+		return trace.BadParameter("expected %v etcd members, found %v", len(r.masters), len(members))
+	}
+	for _, member := range members {
+		if len(member.ClientURLs) == 0 {
+			return trace.BadParameter("etcd member %v has not started", member.PeerURLs)
+		}
+		if err := r.checkMemberVersion(ctx, member.ClientURLs, version); err != nil {
+			return trace.Wrap(err)
+		}
+	}
+	if _, err := membersAPI.Leader(ctx); err != nil {
+		return trace.Wrap(err, "etcd cluster has no leader")
+	}
+	return nil
+}
+
+// waitForMember waits for the etcd member on the specified server to become healthy.
+// If version is not empty, the member is also expected to run the specified version
+func (r *etcdCluster) waitForMember(ctx context.Context, server storage.Server, version string) error {
+	return trace.Wrap(update.Retry(ctx, func() error {
+		member, err := r.findMember(ctx, server)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		if len(member.ClientURLs) == 0 {
+			return trace.BadParameter("etcd member on %v has not started", server.Hostname)
+		}
+		return trace.Wrap(r.checkMemberVersion(ctx, member.ClientURLs, version))
+	}, defaults.EtcdMemberHealthTimeout))
+}
+
+// memberHasVersion returns true if the etcd member on the specified server
+// runs the specified version of etcd
+func (r *etcdCluster) memberHasVersion(ctx context.Context, server storage.Server, version string) (bool, error) {
+	err := r.checkMemberVersion(ctx, []string{clientURL(server)}, version)
+	if err != nil && !trace.IsCompareFailed(err) {
+		return false, trace.Wrap(err)
+	}
+	return err == nil, nil
+}
+
+func (r *etcdCluster) checkMemberVersion(ctx context.Context, endpoints []string, version string) error {
+	client, err := clients.Etcd(&clients.EtcdConfig{
+		Endpoints:  endpoints,
+		SecretsDir: r.secretsDir,
+	})
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	versions, err := client.GetVersion(ctx)
+	if err != nil {
+		return trace.Wrap(err, "etcd member %v is unhealthy", endpoints)
+	}
+	if version != "" && versions.Server != version {
+		return trace.CompareFailed("etcd member %v runs version %v, expected %v",
+			endpoints, versions.Server, version)
+	}
+	return nil
+}
+
+func (r *etcdCluster) endpoints() (endpoints []string) {
+	for _, master := range r.masters {
+		endpoints = append(endpoints, clientURL(master))
+	}
+	return endpoints
+}
+
+// isStarted returns true if the specified member has been started
+func isStarted(member *etcd.Member) bool {
+	return member != nil && len(member.ClientURLs) != 0
+}
+
+func clientURL(server storage.Server) string {
+	return fmt.Sprintf("https://%v:%v", server.AdvertiseIP, defaults.EtcdAPIPort)
+}
+
+func peerURL(server storage.Server) string {
+	return fmt.Sprintf("https://%v:%v", server.AdvertiseIP, defaults.EtcdPeerPort)
+}
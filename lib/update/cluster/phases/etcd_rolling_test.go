@@ -0,0 +1,43 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package phases
+
+import (
+	"testing"
+
+	"github.com/gravitational/gravity/lib/schema"
+	"github.com/gravitational/gravity/lib/storage"
+
+	"gopkg.in/check.v1"
+)
+
+func TestPhases(t *testing.T) { check.TestingT(t) }
+
+type EtcdRollingSuite struct{}
+
+var _ = check.Suite(&EtcdRollingSuite{})
+
+func (s *EtcdRollingSuite) TestOfflineUpgradeSkipsUpgradedServers(c *check.C) {
+	upgraded := storage.Server{AdvertiseIP: "10.0.0.1", ClusterRole: string(schema.ServiceRoleMaster)}
+	lagging := storage.Server{AdvertiseIP: "10.0.0.2", ClusterRole: string(schema.ServiceRoleMaster)}
+	proxy := storage.Server{AdvertiseIP: "10.0.0.3", ClusterRole: string(schema.ServiceRoleNode)}
+
+	laggingMasters := []storage.Server{lagging}
+	c.Assert(offlineUpgradeCommand(lagging, laggingMasters), check.DeepEquals, []string{"etcd", "upgrade"})
+	c.Assert(offlineUpgradeCommand(upgraded, laggingMasters), check.DeepEquals, []string{"etcd", "wipe", "--confirm"})
+	c.Assert(offlineUpgradeCommand(proxy, laggingMasters), check.IsNil)
+}
@@ -375,7 +375,11 @@ func newOperationPlan(p planConfig) (*storage.OperationPlan, error) {
 		}
 
 		if updateEtcd {
-			etcdPhase := *builder.etcdPlan(p.leadMaster.Server,
+			etcdPlan := builder.etcdPlan
+			if supportsRollingEtcdUpgrade(currentVersion, desiredVersion, len(masters)) {
+				etcdPlan = builder.etcdRollingPlan
+			}
+			etcdPhase := *etcdPlan(p.leadMaster.Server,
 				serversToStorage(otherMasters...),
 				serversToStorage(nodes...),
 				currentVersion, desiredVersion)
@@ -59,6 +59,7 @@ func NewUpdateConfig(
 		packages:     packages,
 		hostPackages: hostPackages,
 		servers:      params.Phase.Data.Update.Servers,
+		driver:       params.Phase.Data.Update.StorageDriver,
 		manifest:     app.Manifest,
 	}, nil
 }
@@ -85,11 +86,14 @@ func (r *updateConfig) Execute(ctx context.Context) error {
 			return trace.Wrap(err)
 		}
 	}
-	err := r.operator.UpdateClusterConfiguration(ops.UpdateClusterConfigRequest{
+	req := ops.UpdateClusterConfigRequest{
 		ClusterKey: r.operation.ClusterKey(),
 		Config:     r.operation.UpdateConfig.Config,
-	})
-	return trace.Wrap(err)
+	}
+	if r.driver != nil {
+		req.DockerStorageDriver = r.driver.To
+	}
+	return trace.Wrap(r.operator.UpdateClusterConfiguration(req))
 }
 
 // Rollback resets the cluster configuration to the previous value
@@ -102,11 +106,14 @@ func (r *updateConfig) Rollback(context.Context) error {
 			}
 		}
 	}
-	err := r.operator.UpdateClusterConfiguration(ops.UpdateClusterConfigRequest{
+	req := ops.UpdateClusterConfigRequest{
 		ClusterKey: r.operation.ClusterKey(),
 		Config:     r.operation.UpdateConfig.PrevConfig,
-	})
-	return trace.Wrap(err)
+	}
+	if r.driver != nil {
+		req.DockerStorageDriver = r.driver.From
+	}
+	return trace.Wrap(r.operator.UpdateClusterConfiguration(req))
 }
 
 // PreCheck is a no-op
@@ -127,6 +134,7 @@ type updateConfig struct {
 	packages     packageService
 	hostPackages packageService
 	servers      []storage.UpdateServer
+	driver       *storage.StorageDriverChange
 	manifest     schema.Manifest
 }
 
@@ -18,6 +18,7 @@ package clusterconfig
 
 import (
 	"github.com/gravitational/gravity/lib/app"
+	"github.com/gravitational/gravity/lib/constants"
 	"github.com/gravitational/gravity/lib/ops"
 	"github.com/gravitational/gravity/lib/storage"
 	"github.com/gravitational/gravity/lib/storage/clusterconfig"
@@ -43,7 +44,15 @@ func NewOperationPlan(
 	if err != nil {
 		return nil, trace.Wrap(err, "failed to query installed application")
 	}
-	plan, err = newOperationPlan(*app, cluster.DNSConfig, operator, operation, clusterConfig, servers)
+	prevConfig, err := operator.GetClusterConfiguration(cluster.Key())
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	docker := cluster.ClusterState.Docker
+	if config := prevConfig.GetDockerConfig(); config != nil && config.StorageDriver != "" {
+		docker.StorageDriver = config.StorageDriver
+	}
+	plan, err = newOperationPlan(*app, cluster.DNSConfig, docker, operator, operation, clusterConfig, servers)
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
@@ -64,12 +73,16 @@ func NewOperationPlan(
 func newOperationPlan(
 	app app.Application,
 	dnsConfig storage.DNSConfig,
+	docker storage.DockerConfig,
 	operator rollingupdate.ConfigPackageRotator,
 	operation ops.SiteOperation,
 	clusterConfig clusterconfig.Interface,
 	servers []storage.Server,
 ) (*storage.OperationPlan, error) {
-	builder := rollingupdate.Builder{App: app.Package}
+	builder := rollingupdate.Builder{
+		App:            app.Package,
+		MigrateStorage: shouldMigrateStorage(docker, clusterConfig),
+	}
 	updates, err := rollingupdate.RuntimeConfigUpdates(app.Manifest, operator, operation.Key(), servers)
 	if err != nil {
 		return nil, trace.Wrap(err)
@@ -78,7 +91,8 @@ func newOperationPlan(
 	if len(masters) == 0 {
 		return nil, trace.NotFound("no master servers found in cluster state")
 	}
-	shouldUpdateNodes := shouldUpdateNodes(clusterConfig, len(nodes))
+	shouldUpdateNodes := shouldUpdateNodes(clusterConfig, len(nodes)) ||
+		(builder.MigrateStorage && len(nodes) != 0)
 	updateServers := updates
 	if !shouldUpdateNodes {
 		updateServers = masters
@@ -121,3 +135,11 @@ func shouldUpdateNodes(clusterConfig clusterconfig.Interface, numNodes int) bool
 	}
 	return (clusterConfig.GetKubeletConfig() != nil || hasComponentUpdate) && numNodes != 0
 }
+
+// shouldMigrateStorage returns true if the configuration changes the Docker
+// storage driver from devicemapper to overlay2
+func shouldMigrateStorage(docker storage.DockerConfig, clusterConfig clusterconfig.Interface) bool {
+	config := clusterConfig.GetDockerConfig()
+	return config != nil && config.StorageDriver == constants.DockerStorageDriverOverlay2 &&
+		docker.StorageDriver == constants.DockerStorageDriverDevicemapper
+}
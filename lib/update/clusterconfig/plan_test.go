@@ -21,6 +21,7 @@ import (
 
 	"github.com/gravitational/gravity/lib/app"
 	"github.com/gravitational/gravity/lib/compare"
+	"github.com/gravitational/gravity/lib/constants"
 	"github.com/gravitational/gravity/lib/loc"
 	"github.com/gravitational/gravity/lib/ops"
 	"github.com/gravitational/gravity/lib/schema"
@@ -66,7 +67,7 @@ func (S) TestSingleNodePlan(c *C) {
 	}
 	clusterConfig := clusterconfig.NewEmpty()
 
-	plan, err := newOperationPlan(app, storage.DefaultDNSConfig, testOperator, operation, clusterConfig, servers)
+	plan, err := newOperationPlan(app, storage.DefaultDNSConfig, storage.DockerConfig{}, testOperator, operation, clusterConfig, servers)
 	c.Assert(err, IsNil)
 	c.Assert(plan, compare.DeepEquals, &storage.OperationPlan{
 		OperationID:   operation.ID,
@@ -218,7 +219,7 @@ func (S) TestMultiNodePlan(c *C) {
 	}
 	clusterConfig := clusterconfig.NewEmpty()
 
-	plan, err := newOperationPlan(app, storage.DefaultDNSConfig, testOperator, operation, clusterConfig, servers)
+	plan, err := newOperationPlan(app, storage.DefaultDNSConfig, storage.DockerConfig{}, testOperator, operation, clusterConfig, servers)
 	c.Assert(err, IsNil)
 	c.Assert(plan, compare.DeepEquals, &storage.OperationPlan{
 		OperationID:   operation.ID,
@@ -496,7 +497,7 @@ kind: KubeletConfiguration
 address: "0.0.0.0"`),
 	}
 
-	plan, err := newOperationPlan(app, storage.DefaultDNSConfig, testOperator, operation, clusterConfig, servers)
+	plan, err := newOperationPlan(app, storage.DefaultDNSConfig, storage.DockerConfig{}, testOperator, operation, clusterConfig, servers)
 	c.Assert(err, IsNil)
 	c.Assert(plan, compare.DeepEquals, &storage.OperationPlan{
 		OperationID:   operation.ID,
@@ -709,6 +710,74 @@ address: "0.0.0.0"`),
 	})
 }
 
+func (S) TestMigratesStorage(c *C) {
+	operation := ops.SiteOperation{
+		ID:         "1",
+		AccountID:  "0",
+		Type:       ops.OperationUpdateConfig,
+		SiteDomain: "cluster",
+	}
+	servers := []storage.Server{
+		{Hostname: "node-1", Role: "node", ClusterRole: string(schema.ServiceRoleMaster)},
+		{Hostname: "node-2", Role: "knode", ClusterRole: string(schema.ServiceRoleNode)},
+	}
+	runtimeLoc := loc.Locator{Repository: "foo", Name: "runtime", Version: "0.0.1"}
+	app := app.Application{
+		Package: loc.MustParseLocator("gravitational.io/app:0.0.1"),
+		Manifest: schema.Manifest{
+			NodeProfiles: schema.NodeProfiles{
+				{
+					Name:        "node",
+					ServiceRole: "master",
+				},
+				{
+					Name:        "knode",
+					ServiceRole: "node",
+				},
+			},
+			SystemOptions: &schema.SystemOptions{
+				Dependencies: schema.SystemDependencies{
+					Runtime: &schema.Dependency{Locator: runtimeLoc},
+				},
+			},
+		},
+	}
+	clusterConfig := clusterconfig.New(clusterconfig.Spec{
+		Docker: &clusterconfig.Docker{StorageDriver: constants.DockerStorageDriverOverlay2},
+	})
+	docker := storage.DockerConfig{StorageDriver: constants.DockerStorageDriverDevicemapper}
+
+	plan, err := newOperationPlan(app, storage.DefaultDNSConfig, docker, testOperator, operation, clusterConfig, servers)
+	c.Assert(err, IsNil)
+	c.Assert(plan.Phases, HasLen, 3)
+	c.Assert(plan.Phases[0].Data.Update.StorageDriver, compare.DeepEquals, &storage.StorageDriverChange{
+		From: constants.DockerStorageDriverDevicemapper,
+		To:   constants.DockerStorageDriverOverlay2,
+	})
+	for i, node := range []storage.OperationPhase{plan.Phases[1].Phases[0], plan.Phases[2].Phases[0]} {
+		var ids []string
+		for _, phase := range node.Phases {
+			ids = append(ids, phase.ID)
+		}
+		prefix := node.ID + "/"
+		c.Assert(ids[:3], DeepEquals, []string{prefix + "drain", prefix + "migrate-storage", prefix + "restart"})
+		migrate := node.Phases[1]
+		c.Assert(migrate.Executor, Equals, libphase.MigrateStorage)
+		c.Assert(migrate.Data, compare.DeepEquals, &storage.OperationPhaseData{
+			Server:     &servers[i],
+			ExecServer: &servers[i],
+		})
+	}
+
+	docker.StorageDriver = constants.DockerStorageDriverOverlay2
+	plan, err = newOperationPlan(app, storage.DefaultDNSConfig, docker, testOperator, operation, clusterConfig, servers)
+	c.Assert(err, IsNil)
+	c.Assert(plan.Phases[0].Data.Update.StorageDriver, IsNil)
+	for _, phase := range plan.Phases[1].Phases[0].Phases {
+		c.Assert(phase.Executor, Not(Equals), libphase.MigrateStorage)
+	}
+}
+
 func (r testRotator) RotatePlanetConfig(ops.RotatePlanetConfigRequest) (*ops.RotatePackageResponse, error) {
 	return &ops.RotatePackageResponse{Locator: r.runtimeConfigPackage}, nil
 }
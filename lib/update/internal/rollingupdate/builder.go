@@ -3,6 +3,7 @@ package rollingupdate
 import (
 	"fmt"
 
+	"github.com/gravitational/gravity/lib/constants"
 	"github.com/gravitational/gravity/lib/loc"
 	"github.com/gravitational/gravity/lib/ops"
 	"github.com/gravitational/gravity/lib/schema"
@@ -65,11 +66,17 @@ func (r Builder) Config(rootText string, servers []storage.UpdateServer) *update
 			Package: &r.App,
 		},
 	})
-	if len(servers) != 0 {
+	if len(servers) != 0 || r.MigrateStorage {
 		phase.Data.Update = &storage.UpdateOperationData{
 			Servers: servers,
 		}
 	}
+	if r.MigrateStorage {
+		phase.Data.Update.StorageDriver = &storage.StorageDriverChange{
+			From: constants.DockerStorageDriverDevicemapper,
+			To:   constants.DockerStorageDriverOverlay2,
+		}
+	}
 	return &phase
 }
 
@@ -117,8 +124,11 @@ func (r Builder) Nodes(servers []storage.UpdateServer, master storage.Server, ro
 }
 
 func (r Builder) common(server storage.UpdateServer, master *storage.Server) (phases []update.Phase) {
+	phases = append(phases, r.drain(&server.Server, master))
+	if r.MigrateStorage {
+		phases = append(phases, r.migrateStorage(server.Server))
+	}
 	phases = append(phases,
-		r.drain(&server.Server, master),
 		r.restart(server),
 		r.taint(&server.Server, master),
 		r.uncordon(&server.Server, master),
@@ -141,6 +151,16 @@ func (r Builder) restart(server storage.UpdateServer) update.Phase {
 	return node
 }
 
+func (r Builder) migrateStorage(server storage.Server) update.Phase {
+	node := r.node("migrate-storage", "Migrate Docker storage to overlay2 on node %q", server.Hostname)
+	node.Executor = libphase.MigrateStorage
+	node.Data = &storage.OperationPhaseData{
+		Server:     &server,
+		ExecServer: &server,
+	}
+	return node
+}
+
 func (r Builder) taint(server, execer *storage.Server) update.Phase {
 	node := r.node("taint", "Taint node %q", server.Hostname)
 	node.Executor = libphase.Taint
@@ -212,6 +232,9 @@ func (r Builder) node(id, format string, args ...interface{}) update.Phase {
 type Builder struct {
 	// App specifies the cluster application
 	App loc.Locator
+	// MigrateStorage specifies whether to migrate Docker storage
+	// on each node from devicemapper to overlay2
+	MigrateStorage bool
 }
 
 // setLeaderElection creates a phase that will change the leader election state in the cluster
@@ -39,6 +39,9 @@ func (r *dispatcher) Dispatch(config Config, params fsm.ExecutorParams, remote f
 			config.Apps, config.LocalBackend,
 			config.ClusterPackages, config.HostLocalPackages,
 			logger)
+	case libphase.MigrateStorage:
+		return libphase.NewMigrateStorage(params, *config.Operation,
+			config.HostLocalPackages, remote, logger)
 	case libphase.Elections:
 		return libphase.NewElections(params, config.Operator, logger)
 	case libphase.Drain:
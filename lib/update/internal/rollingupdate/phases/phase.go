@@ -40,6 +40,9 @@ const (
 	Uncordon = "uncordon"
 	// Endpoints defines the phase to wait for endpoints on a node to be become active
 	Endpoints = "endpoints"
+	// MigrateStorage defines the phase to migrate Docker storage on a node
+	// from devicemapper to overlay2
+	MigrateStorage = "migrate-storage"
 )
 
 type appGetter interface {
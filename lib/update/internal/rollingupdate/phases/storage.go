@@ -0,0 +1,159 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package phases
+
+import (
+	"context"
+	"os"
+
+	"github.com/gravitational/gravity/lib/devicemapper"
+	libfsm "github.com/gravitational/gravity/lib/fsm"
+	"github.com/gravitational/gravity/lib/loc"
+	"github.com/gravitational/gravity/lib/ops"
+	"github.com/gravitational/gravity/lib/overlay"
+	"github.com/gravitational/gravity/lib/pack"
+	"github.com/gravitational/gravity/lib/storage"
+	"github.com/gravitational/gravity/lib/storage/clusterconfig"
+	"github.com/gravitational/gravity/lib/systemservice"
+
+	"github.com/gravitational/trace"
+	log "github.com/sirupsen/logrus"
+)
+
+// NewMigrateStorage returns a new executor to migrate the Docker storage on the node
+// from devicemapper to overlay2
+func NewMigrateStorage(
+	params libfsm.ExecutorParams,
+	operation ops.SiteOperation,
+	packages pack.PackageService,
+	remote libfsm.Remote,
+	logger log.FieldLogger,
+) (*migrateStorage, error) {
+	if params.Phase.Data == nil || params.Phase.Data.Server == nil {
+		return nil, trace.NotFound("no server specified for phase %q", params.Phase.ID)
+	}
+	if operation.UpdateConfig == nil {
+		return nil, trace.BadParameter("phase %q requires a cluster configuration update operation",
+			params.Phase.ID)
+	}
+	config, err := clusterconfig.Unmarshal(operation.UpdateConfig.Config)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	docker := config.GetDockerConfig()
+	if docker == nil {
+		return nil, trace.NotFound("no docker configuration specified for phase %q", params.Phase.ID)
+	}
+	return &migrateStorage{
+		FieldLogger: logger,
+		packages:    packages,
+		remote:      remote,
+		server:      *params.Phase.Data.Server,
+		docker:      *docker,
+	}, nil
+}
+
+// Execute stops the runtime container and moves the Docker storage
+// from the devicemapper thin pool to the overlay2 device.
+// The container is restarted with the new configuration in the subsequent phase
+func (r *migrateStorage) Execute(context.Context) error {
+	if err := r.stopRuntime(); err != nil {
+		return trace.Wrap(err)
+	}
+	r.Infof("Migrate Docker storage on %v to overlay2.", r.server)
+	err := overlay.Migrate(overlay.Config{
+		FieldLogger: r.FieldLogger,
+		Disk:        r.server.Docker.Device.Path(),
+		Filesystem:  r.docker.Filesystem,
+		Quota:       r.docker.Quota,
+		Out:         os.Stderr,
+	})
+	return trace.Wrap(err)
+}
+
+// Rollback removes the overlay2 device and restores the devicemapper thin pool
+func (r *migrateStorage) Rollback(context.Context) error {
+	if err := r.stopRuntime(); err != nil {
+		return trace.Wrap(err)
+	}
+	disk := r.server.Docker.Device.Path()
+	r.Infof("Restore devicemapper Docker storage on %v.", r.server)
+	err := overlay.Unmount(overlay.Config{
+		FieldLogger: r.FieldLogger,
+		Disk:        disk,
+		Out:         os.Stderr,
+	})
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if disk != "" {
+		if err := devicemapper.Mount(disk, os.Stderr, r.FieldLogger); err != nil {
+			return trace.Wrap(err)
+		}
+	}
+	return trace.Wrap(r.startRuntime())
+}
+
+// PreCheck makes sure the phase is executed on the correct node
+func (r *migrateStorage) PreCheck(ctx context.Context) error {
+	return trace.Wrap(r.remote.CheckServer(ctx, r.server))
+}
+
+// PostCheck is a no-op
+func (*migrateStorage) PostCheck(context.Context) error {
+	return nil
+}
+
+func (r *migrateStorage) stopRuntime() error {
+	runtimePackage, services, err := r.runtimeService()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	r.Info("Stop runtime container.")
+	return trace.Wrap(services.StopPackageService(*runtimePackage))
+}
+
+func (r *migrateStorage) startRuntime() error {
+	runtimePackage, services, err := r.runtimeService()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	r.Info("Start runtime container.")
+	noBlock := false
+	return trace.Wrap(services.StartPackageService(*runtimePackage, noBlock))
+}
+
+func (r *migrateStorage) runtimeService() (*loc.Locator, systemservice.ServiceManager, error) {
+	runtimePackage, err := pack.FindRuntimePackage(r.packages)
+	if err != nil {
+		return nil, nil, trace.Wrap(err)
+	}
+	services, err := systemservice.New()
+	if err != nil {
+		return nil, nil, trace.Wrap(err)
+	}
+	return runtimePackage, services, nil
+}
+
+type migrateStorage struct {
+	// FieldLogger specifies the logger for the phase
+	log.FieldLogger
+	packages pack.PackageService
+	remote   libfsm.Remote
+	server   storage.Server
+	docker   clusterconfig.Docker
+}
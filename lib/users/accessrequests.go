@@ -0,0 +1,229 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package users
+
+import (
+	"time"
+
+	"github.com/gravitational/gravity/lib/storage"
+	"github.com/gravitational/gravity/lib/utils"
+
+	teleservices "github.com/gravitational/teleport/lib/services"
+	"github.com/gravitational/trace"
+	"github.com/jonboulle/clockwork"
+	log "github.com/sirupsen/logrus"
+)
+
+// ReviewAccessRequest approves or denies the specified pending access request.
+//
+// Approving the request grants the user the requested roles they do not have
+// until the request expires. The stored user is not modified: the granted roles
+// are added to the user roles when issuing certificates and checking access,
+// see GrantedRoles
+func ReviewAccessRequest(backend storage.Backend, clock clockwork.Clock, id, reviewer string, approve bool) (*storage.AccessRequest, error) {
+	req, err := backend.GetAccessRequest(id)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	if !req.IsPending() {
+		return nil, trace.CompareFailed("access request %v is %v", req.ID, req.State)
+	}
+	if req.User == reviewer {
+		return nil, trace.AccessDenied("users cannot review their own access requests")
+	}
+	req.Reviewer = reviewer
+	req.Reviewed = clock.Now().UTC()
+	if !approve {
+		req.State = storage.AccessRequestStateDenied
+		return backend.UpdateAccessRequest(*req)
+	}
+	user, err := backend.GetUser(req.User)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	for _, role := range req.Roles {
+		if !utils.StringInSlice(user.GetRoles(), role) {
+			req.GrantedRoles = append(req.GrantedRoles, role)
+		}
+	}
+	req.State = storage.AccessRequestStateApproved
+	req.Expires = req.Reviewed.Add(req.Duration)
+	return backend.UpdateAccessRequest(*req)
+}
+
+// ExpireAccessRequests marks the approved access requests that have expired
+// and returns the list of expired requests.
+//
+// The roles granted by expired requests are no longer added to the user roles
+func ExpireAccessRequests(backend storage.Backend, clock clockwork.Clock) (expired []storage.AccessRequest, err error) {
+	requests, err := backend.GetAccessRequests()
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	now := clock.Now().UTC()
+	for _, req := range requests {
+		if req.State != storage.AccessRequestStateApproved || req.IsActive(now) {
+			continue
+		}
+		req.State = storage.AccessRequestStateExpired
+		if _, err := backend.UpdateAccessRequest(req); err != nil {
+			return nil, trace.Wrap(err)
+		}
+		log.WithFields(log.Fields{"user": req.User, "request": req.ID}).
+			WithField("roles", req.GrantedRoles).Info("Access request expired.")
+		expired = append(expired, req)
+	}
+	return expired, nil
+}
+
+// AccessExpires returns the time the earliest of the roles granted to
+// the specified user by active access requests expires, if there are any.
+//
+// The certificates issued to the user should not outlive it.
+func AccessExpires(backend storage.Backend, clock clockwork.Clock, username string) (expires time.Time, ok bool, err error) {
+	requests, err := backend.GetAccessRequests()
+	if err != nil {
+		return time.Time{}, false, trace.Wrap(err)
+	}
+	now := clock.Now().UTC()
+	for _, req := range requests {
+		if req.User != username || !req.IsActive(now) || len(req.GrantedRoles) == 0 {
+			continue
+		}
+		if !ok || req.Expires.Before(expires) {
+			expires, ok = req.Expires, true
+		}
+	}
+	return expires, ok, nil
+}
+
+// GrantedRoles returns the roles granted to the specified user
+// by active access requests
+func GrantedRoles(backend storage.Backend, clock clockwork.Clock, username string) (roles []string, err error) {
+	requests, err := backend.GetAccessRequests()
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	now := clock.Now().UTC()
+	for _, req := range requests {
+		if req.User != username || !req.IsActive(now) {
+			continue
+		}
+		for _, role := range req.GrantedRoles {
+			if !utils.StringInSlice(roles, role) {
+				roles = append(roles, role)
+			}
+		}
+	}
+	return roles, nil
+}
+
+// AddGrantedRoles adds the roles granted by active access requests
+// to the roles of the specified user.
+//
+// The user is expected to have been read from the backend by the caller
+// and must not be stored afterwards, the granted roles are never persisted
+func AddGrantedRoles(backend storage.Backend, clock clockwork.Clock, user teleservices.User) error {
+	granted, err := GrantedRoles(backend, clock, user.GetName())
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if len(granted) == 0 {
+		return nil
+	}
+	roles := append([]string{}, user.GetRoles()...)
+	for _, role := range granted {
+		if !utils.StringInSlice(roles, role) {
+			roles = append(roles, role)
+		}
+	}
+	user.SetRoles(roles)
+	return nil
+}
+
+// RemoveGrantedRoles removes the roles granted by access requests
+// from the roles of the specified user, unless the stored user has them.
+//
+// All requests are considered regardless of their state so that the roles
+// added before a request expired are removed as well
+func RemoveGrantedRoles(backend storage.Backend, user teleservices.User) error {
+	requests, err := backend.GetAccessRequests()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	var granted []string
+	for _, req := range requests {
+		if req.User == user.GetName() {
+			granted = append(granted, req.GrantedRoles...)
+		}
+	}
+	if len(granted) == 0 {
+		return nil
+	}
+	var assigned []string
+	stored, err := backend.GetUser(user.GetName())
+	if err != nil && !trace.IsNotFound(err) {
+		return trace.Wrap(err)
+	}
+	if stored != nil {
+		assigned = stored.GetRoles()
+	}
+	var roles []string
+	for _, role := range user.GetRoles() {
+		if !utils.StringInSlice(granted, role) || utils.StringInSlice(assigned, role) {
+			roles = append(roles, role)
+		}
+	}
+	user.SetRoles(roles)
+	return nil
+}
+
+// NewSessionIdentity returns the identity for the teleport auth server
+// that adds the roles granted by active access requests to the users,
+// so the certificates issued with GenerateUserCert carry them.
+//
+// The granted roles are removed from the users teleport writes back,
+// e.g. when it locks a user after too many failed login attempts
+func NewSessionIdentity(identity teleservices.Identity, backend storage.Backend, clock clockwork.Clock) teleservices.Identity {
+	return &sessionIdentity{Identity: identity, backend: backend, clock: clock}
+}
+
+type sessionIdentity struct {
+	teleservices.Identity
+	backend storage.Backend
+	clock   clockwork.Clock
+}
+
+// GetUser returns the user with the roles granted by active access requests
+func (i *sessionIdentity) GetUser(name string) (teleservices.User, error) {
+	user, err := i.Identity.GetUser(name)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	if err := AddGrantedRoles(i.backend, i.clock, user); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return user, nil
+}
+
+// UpsertUser stores the user without the roles granted by access requests
+func (i *sessionIdentity) UpsertUser(user teleservices.User) error {
+	if err := RemoveGrantedRoles(i.backend, user); err != nil {
+		return trace.Wrap(err)
+	}
+	return i.Identity.UpsertUser(user)
+}
@@ -94,6 +94,28 @@ func (i *IdentityACL) SetAuth(auth teleauth.ClientI) {
 	i.identity.SetAuth(auth)
 }
 
+// SetLockoutHook sets the function called when a user account gets locked
+func (i *IdentityACL) SetLockoutHook(hook LockoutHook) {
+	i.identity.SetLockoutHook(hook)
+}
+
+// UnlockUser clears the user account lockout and failed login attempts
+func (i *IdentityACL) UnlockUser(username string) error {
+	if err := i.usersAction(teleservices.VerbUpdate); err != nil {
+		return trace.Wrap(err)
+	}
+	return i.identity.UnlockUser(username)
+}
+
+// CheckPasswordExpired returns an error if the password of the user
+// is older than allowed by the password policy
+func (i *IdentityACL) CheckPasswordExpired(user storage.User) error {
+	if err := i.currentUserAction(user.GetName()); err != nil {
+		return trace.Wrap(err)
+	}
+	return i.identity.CheckPasswordExpired(user)
+}
+
 // authConnectorAction is a special checker that grants access to auth
 // connectors. It first checks if you have access to the specific connector.
 // If not, it checks if the requester has the meta KindAuthConnector access
@@ -286,6 +308,15 @@ func (i *IdentityACL) GetAPIKeyByToken(token string) (key *storage.APIKey, err e
 	return i.identity.GetAPIKeyByToken(token)
 }
 
+// CheckSecondFactor verifies the provided one-time token against
+// the second factor configured for the specified user
+func (i *IdentityACL) CheckSecondFactor(username, token string) error {
+	if err := i.currentUserAction(username); err != nil {
+		return trace.Wrap(err)
+	}
+	return i.identity.CheckSecondFactor(username, token)
+}
+
 func (i *IdentityACL) DeleteAPIKey(username, token string) error {
 	if err := i.currentUserAction(username); err != nil {
 		return trace.Wrap(err)
@@ -431,7 +462,8 @@ func (i *IdentityACL) Migrate() error {
 
 // UpdateUser updates certain user fields
 func (i *IdentityACL) UpdateUser(username string, req storage.UpdateUserReq) error {
-	if req.Roles != nil { // changing roles requires admin privileges
+	// changing roles, lockout status or password history requires admin privileges
+	if req.Roles != nil || req.Status != nil || req.PasswordHistory != nil || req.PasswordChanged != nil {
 		if err := i.usersAction(teleservices.VerbUpdate); err != nil {
 			return trace.Wrap(err)
 		}
@@ -0,0 +1,157 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package users
+
+import (
+	"time"
+
+	"github.com/gravitational/gravity/lib/storage"
+	"github.com/gravitational/gravity/lib/utils"
+
+	teleservices "github.com/gravitational/teleport/lib/services"
+	"github.com/gravitational/trace"
+	"github.com/jonboulle/clockwork"
+	log "github.com/sirupsen/logrus"
+)
+
+// NewAPIKeyChecker returns an access checker for the specified API key.
+//
+// The checker grants the permissions of the specified roles of the key owner
+// narrowed down to the roles, rules and clusters the key is scoped to.
+func NewAPIKeyChecker(key storage.APIKey, roles []teleservices.Role) teleservices.AccessChecker {
+	if !key.IsScoped() {
+		return teleservices.NewRoleSet(roles...)
+	}
+	if len(key.Roles) != 0 {
+		var scoped []teleservices.Role
+		for _, role := range roles {
+			if utils.StringInSlice(key.Roles, role.GetName()) {
+				scoped = append(scoped, role)
+			}
+		}
+		roles = scoped
+	}
+	return &apiKeyChecker{
+		AccessChecker: teleservices.NewRoleSet(roles...),
+		rules:         key.Rules,
+		clusters:      key.Clusters,
+	}
+}
+
+// IsScopedAPIKey returns true if the specified checker has been created
+// for an API key that grants a subset of its user's permissions
+func IsScopedAPIKey(checker teleservices.AccessChecker) bool {
+	_, ok := checker.(*apiKeyChecker)
+	return ok
+}
+
+// CheckAccessToRule checks access to the specified resource and verb
+// against both the roles and the scope of the API key
+func (r *apiKeyChecker) CheckAccessToRule(ctx teleservices.RuleContext, namespace string, resource string, verb string, silent bool) error {
+	if err := r.AccessChecker.CheckAccessToRule(ctx, namespace, resource, verb, silent); err != nil {
+		return trace.Wrap(err)
+	}
+	return trace.Wrap(r.checkScope(ctx, resource, verb))
+}
+
+// checkScope verifies that the specified resource and verb are within the scope of the API key.
+//
+// A key scoped to clusters only permits actions on these clusters
+func (r *apiKeyChecker) checkScope(ctx teleservices.RuleContext, resource, verb string) error {
+	if len(r.rules) != 0 && !r.hasRule(resource, verb) {
+		return trace.AccessDenied("API key does not permit %v on %v", verb, resource)
+	}
+	if len(r.clusters) == 0 {
+		return nil
+	}
+	target, err := ctx.GetResource()
+	if err != nil {
+		return trace.AccessDenied("API key only permits access to clusters %v", r.clusters)
+	}
+	cluster, ok := target.(storage.Cluster)
+	if !ok {
+		return trace.AccessDenied("API key only permits access to clusters %v", r.clusters)
+	}
+	if !utils.StringInSlice(r.clusters, cluster.GetName()) {
+		return trace.AccessDenied("API key does not permit access to cluster %v", cluster.GetName())
+	}
+	return nil
+}
+
+func (r *apiKeyChecker) hasRule(resource, verb string) bool {
+	for _, rule := range r.rules {
+		if !rule.HasResource(resource) && !rule.HasResource(teleservices.Wildcard) {
+			continue
+		}
+		if rule.HasVerb(verb) || rule.HasVerb(teleservices.Wildcard) {
+			return true
+		}
+	}
+	return false
+}
+
+// apiKeyChecker restricts the access granted by roles to the scope of an API key
+type apiKeyChecker struct {
+	teleservices.AccessChecker
+	// rules lists resources and verbs the key is restricted to
+	rules []teleservices.Rule
+	// clusters lists clusters the key is restricted to
+	clusters []string
+}
+
+// RevokeUnusedAPIKeys deletes API keys that have not been used for longer than maxIdle
+// and returns the list of revoked keys.
+//
+// Keys that belong to cluster agent users are never revoked.
+// Keys created before the usage tracking was introduced are considered
+// to be created at the time of the first check.
+func RevokeUnusedAPIKeys(backend storage.Backend, clock clockwork.Clock, maxIdle time.Duration) (revoked []storage.APIKey, err error) {
+	users, err := backend.GetAllUsers()
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	now := clock.Now().UTC()
+	for _, user := range users {
+		if user.GetClusterName() != "" {
+			continue
+		}
+		keys, err := backend.GetAPIKeys(user.GetName())
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		for _, key := range keys {
+			logger := log.WithField("user", key.UserEmail)
+			if key.LastActive().IsZero() {
+				key.Created = now
+				if _, err := backend.UpsertAPIKey(key); err != nil {
+					logger.WithError(err).Warn("Failed to update API key.")
+				}
+				continue
+			}
+			if now.Sub(key.LastActive()) < maxIdle {
+				continue
+			}
+			err := backend.DeleteAPIKey(key.UserEmail, key.Token)
+			if err != nil && !trace.IsNotFound(err) {
+				return nil, trace.Wrap(err)
+			}
+			logger.WithField("last-active", key.LastActive()).Info("Revoked unused API key.")
+			revoked = append(revoked, key)
+		}
+	}
+	return revoked, nil
+}
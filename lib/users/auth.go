@@ -164,6 +164,9 @@ func (a *authenticator) authenticateContext(r *http.Request) (*AuthenticateRespo
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
+	if err := a.Identity.CheckPasswordExpired(user); err != nil {
+		return nil, trace.Wrap(err)
+	}
 	checker, err := a.Identity.GetAccessChecker(user)
 	if err != nil {
 		return nil, trace.Wrap(err)
@@ -186,6 +189,9 @@ func (a *authenticator) authenticateSession(w http.ResponseWriter, r *http.Reque
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
+	if err := a.Identity.CheckPasswordExpired(user); err != nil {
+		return nil, trace.Wrap(err)
+	}
 	checker, err := a.Identity.GetAccessChecker(user)
 	if err != nil {
 		return nil, trace.Wrap(err)
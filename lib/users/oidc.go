@@ -0,0 +1,111 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package users
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	teleservices "github.com/gravitational/teleport/lib/services"
+	"github.com/gravitational/trace"
+)
+
+// OIDCProviderConfig is a subset of the OpenID Connect provider metadata
+// published by the provider's discovery endpoint
+type OIDCProviderConfig struct {
+	// Issuer is the provider's issuer identifier
+	Issuer string `json:"issuer"`
+	// AuthorizationEndpoint is the URL of the provider's authorization endpoint
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	// TokenEndpoint is the URL of the provider's token endpoint
+	TokenEndpoint string `json:"token_endpoint"`
+	// JWKSURI is the URL of the provider's JSON web key set
+	JWKSURI string `json:"jwks_uri"`
+	// ClaimsSupported lists the claims the provider may supply values for
+	ClaimsSupported []string `json:"claims_supported,omitempty"`
+}
+
+// GetOIDCProviderConfig fetches the OpenID Connect provider configuration
+// from the discovery endpoint of the specified issuer
+func GetOIDCProviderConfig(ctx context.Context, client *http.Client, issuerURL string) (*OIDCProviderConfig, error) {
+	discoveryURL := strings.TrimSuffix(issuerURL, "/") + oidcDiscoveryPath
+	req, err := http.NewRequest(http.MethodGet, discoveryURL, nil)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, trace.ConnectionProblem(err, "failed to fetch OIDC provider configuration from %v", discoveryURL)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, trace.BadParameter("OIDC provider at %v returned %v", discoveryURL, resp.Status)
+	}
+	var config OIDCProviderConfig
+	if err := json.NewDecoder(resp.Body).Decode(&config); err != nil {
+		return nil, trace.BadParameter("failed to decode OIDC provider configuration from %v: %v", discoveryURL, err)
+	}
+	if strings.TrimSuffix(config.Issuer, "/") != strings.TrimSuffix(issuerURL, "/") {
+		return nil, trace.BadParameter("OIDC provider issuer %q does not match the connector issuer URL %q",
+			config.Issuer, issuerURL)
+	}
+	if config.AuthorizationEndpoint == "" || config.TokenEndpoint == "" || config.JWKSURI == "" {
+		return nil, trace.BadParameter("OIDC provider configuration at %v is missing required endpoints", discoveryURL)
+	}
+	return &config, nil
+}
+
+// CheckOIDCConnector makes sure that the specified connector can be used
+// for authentication: all statically mapped roles exist and the issuer
+// publishes a valid OpenID Connect provider configuration
+func CheckOIDCConnector(ctx context.Context, client *http.Client, connector teleservices.OIDCConnector, roles RoleGetter) error {
+	if err := connector.Check(); err != nil {
+		return trace.Wrap(err)
+	}
+	if len(connector.GetClaimsToRoles()) == 0 {
+		return trace.BadParameter("OIDC connector %q does not map any claims to roles", connector.GetName())
+	}
+	for _, mapping := range connector.GetClaimsToRoles() {
+		for _, role := range mapping.Roles {
+			// roles computed from the claim value can only be verified during login
+			if strings.Contains(role, "$") {
+				continue
+			}
+			if _, err := roles.GetRole(role); err != nil {
+				if trace.IsNotFound(err) {
+					return trace.NotFound("role %q mapped from claim %v=%v does not exist",
+						role, mapping.Claim, mapping.Value)
+				}
+				return trace.Wrap(err)
+			}
+		}
+	}
+	_, err := GetOIDCProviderConfig(ctx, client, connector.GetIssuerURL())
+	return trace.Wrap(err)
+}
+
+// RoleGetter returns roles by name
+type RoleGetter interface {
+	// GetRole returns a role by name
+	GetRole(name string) (teleservices.Role, error)
+}
+
+// oidcDiscoveryPath is the path of the OpenID Connect provider configuration
+// relative to the issuer URL
+const oidcDiscoveryPath = "/.well-known/openid-configuration"
@@ -0,0 +1,235 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package users
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gravitational/gravity/lib/storage"
+
+	teleservices "github.com/gravitational/teleport/lib/services"
+	"github.com/gravitational/trace"
+	"github.com/vulcand/predicate"
+)
+
+// AccessRule identifies a permission as a verb on a resource kind
+type AccessRule struct {
+	// Kind is the resource kind
+	Kind string `json:"kind"`
+	// Verb is the action on the resource kind
+	Verb string `json:"verb"`
+}
+
+// String returns a textual representation of this rule
+func (r AccessRule) String() string {
+	return fmt.Sprintf("%v on %v", r.Verb, r.Kind)
+}
+
+// FallbackRule returns the coarse-grained permission that grants the specified
+// fine-grained permission to roles that do not define rules for its kind.
+//
+// This keeps the roles that were created before the fine-grained rules were
+// introduced working: for example, a role that can update clusters can also
+// run any cluster operation unless it has rules for the "operation" kind.
+func FallbackRule(kind, verb string) (*AccessRule, bool) {
+	switch kind {
+	case storage.KindOperation:
+		switch verb {
+		case storage.OperationVerbImport:
+			return &AccessRule{Kind: storage.KindApp, Verb: teleservices.VerbCreate}, true
+		case storage.OperationVerbRunHook:
+			return &AccessRule{Kind: storage.KindApp, Verb: teleservices.VerbRead}, true
+		}
+		return &AccessRule{Kind: storage.KindCluster, Verb: teleservices.VerbUpdate}, true
+	case storage.KindRuntimeEnvironment, storage.KindClusterConfiguration:
+		if verb == teleservices.VerbUpdate {
+			return &AccessRule{Kind: storage.KindCluster, Verb: teleservices.VerbUpdate}, true
+		}
+	case storage.KindAccessRequest:
+		// the roles users can request must be allowed explicitly
+		switch verb {
+		case teleservices.VerbList, teleservices.VerbUpdate:
+			// reviewing access requests grants roles to users
+			return &AccessRule{Kind: teleservices.KindUser, Verb: verb}, true
+		}
+	}
+	return nil, false
+}
+
+// CheckAccess checks access to the specified verb on the resource kind.
+//
+// If any of the roles define rules for the kind, they decide the outcome.
+// Otherwise, the coarse-grained fallback permission is checked, see FallbackRule.
+// The scope of an API key is always checked against the requested kind and verb
+func CheckAccess(checker teleservices.AccessChecker, ctx teleservices.RuleContext, namespace, kind, verb string) error {
+	if keyChecker, ok := checker.(*apiKeyChecker); ok {
+		if err := keyChecker.checkScope(ctx, kind, verb); err != nil {
+			return trace.Wrap(err)
+		}
+		checker = keyChecker.AccessChecker
+	}
+	if fallback, ok := FallbackRule(kind, verb); ok {
+		roles, ok := rolesFromChecker(checker)
+		if !ok || !definesRulesFor(roles, kind) {
+			kind, verb = fallback.Kind, fallback.Verb
+		}
+	}
+	return checker.CheckAccessToRule(ctx, namespace, kind, verb, false)
+}
+
+// AccessDecision describes the outcome of an access check
+type AccessDecision struct {
+	// AccessRule is the checked permission
+	AccessRule
+	// Allowed is whether the access is granted
+	Allowed bool `json:"allowed"`
+	// Role is the name of the role whose rule decided the outcome,
+	// empty if no rule matched
+	Role string `json:"role,omitempty"`
+	// Rule is the rule that decided the outcome
+	Rule *teleservices.Rule `json:"rule,omitempty"`
+	// Deny is whether the outcome was decided by a deny rule
+	Deny bool `json:"deny,omitempty"`
+	// Fallback is the decision for the coarse-grained permission
+	// if no roles define rules for the checked kind
+	Fallback *AccessDecision `json:"fallback,omitempty"`
+}
+
+// String returns a user-friendly explanation of this decision
+func (d AccessDecision) String() string {
+	switch {
+	case d.Fallback != nil:
+		return fmt.Sprintf("no role defines rules for %v, checked %v instead: %v",
+			d.Kind, d.Fallback.AccessRule, d.Fallback)
+	case d.Rule == nil:
+		return fmt.Sprintf("no role allows %v", d.AccessRule)
+	case d.Deny:
+		return fmt.Sprintf("denied by rule %v of role %v", formatRule(*d.Rule), d.Role)
+	}
+	return fmt.Sprintf("allowed by rule %v of role %v", formatRule(*d.Rule), d.Role)
+}
+
+// ExplainAccess returns the decision on access to the specified verb on
+// the resource kind together with the roles and rules that made it
+func ExplainAccess(checker teleservices.AccessChecker, ctx teleservices.RuleContext, namespace, kind, verb string) (*AccessDecision, error) {
+	roles, ok := rolesFromChecker(checker)
+	if !ok {
+		return nil, trace.BadParameter("unsupported access checker %T", checker)
+	}
+	if fallback, ok := FallbackRule(kind, verb); ok && !definesRulesFor(roles, kind) {
+		decision, err := explainAccess(roles, ctx, namespace, fallback.Kind, fallback.Verb)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		return &AccessDecision{
+			AccessRule: AccessRule{Kind: kind, Verb: verb},
+			Allowed:    decision.Allowed,
+			Fallback:   decision,
+		}, nil
+	}
+	return explainAccess(roles, ctx, namespace, kind, verb)
+}
+
+// explainAccess evaluates the rules of the specified roles the same
+// way teleservices.RoleSet does: a matching deny rule prohibits access,
+// otherwise a matching allow rule grants it
+func explainAccess(roles teleservices.RoleSet, ctx teleservices.RuleContext, namespace, kind, verb string) (*AccessDecision, error) {
+	parser, err := teleservices.GetWhereParserFn()(ctx)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	decision := &AccessDecision{AccessRule: AccessRule{Kind: kind, Verb: verb}}
+	for _, condition := range []teleservices.RoleConditionType{teleservices.Deny, teleservices.Allow} {
+		for _, role := range roles {
+			matchNamespace, _ := teleservices.MatchNamespace(role.GetNamespaces(condition),
+				teleservices.ProcessNamespace(namespace))
+			if !matchNamespace {
+				continue
+			}
+			rule, err := matchRule(role.GetRules(condition), parser, kind, verb)
+			if err != nil {
+				return nil, trace.Wrap(err)
+			}
+			if rule == nil {
+				continue
+			}
+			decision.Role = role.GetName()
+			decision.Rule = rule
+			decision.Deny = condition == teleservices.Deny
+			decision.Allowed = !decision.Deny
+			return decision, nil
+		}
+	}
+	return decision, nil
+}
+
+// matchRule returns the first of the rules that matches the specified kind and verb
+func matchRule(rules []teleservices.Rule, parser predicate.Parser, kind, verb string) (*teleservices.Rule, error) {
+	for _, rule := range rules {
+		if !rule.HasResource(kind) && !rule.HasResource(teleservices.Wildcard) {
+			continue
+		}
+		if !rule.HasVerb(verb) && !rule.HasVerb(teleservices.Wildcard) {
+			continue
+		}
+		match, err := rule.MatchesWhere(parser)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		if match {
+			rule := rule
+			return &rule, nil
+		}
+	}
+	return nil, nil
+}
+
+// definesRulesFor returns true if any of the roles has an allow or deny rule
+// that explicitly names the specified resource kind
+func definesRulesFor(roles teleservices.RoleSet, kind string) bool {
+	for _, role := range roles {
+		for _, condition := range []teleservices.RoleConditionType{teleservices.Allow, teleservices.Deny} {
+			for _, rule := range role.GetRules(condition) {
+				if rule.HasResource(kind) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// rolesFromChecker returns the roles the specified checker is based on
+func rolesFromChecker(checker teleservices.AccessChecker) (teleservices.RoleSet, bool) {
+	switch c := checker.(type) {
+	case teleservices.RoleSet:
+		return c, true
+	case *apiKeyChecker:
+		return rolesFromChecker(c.AccessChecker)
+	}
+	return nil, false
+}
+
+func formatRule(rule teleservices.Rule) string {
+	formatted := fmt.Sprintf("{resources: [%v], verbs: [%v]",
+		strings.Join(rule.Resources, ", "), strings.Join(rule.Verbs, ", "))
+	if rule.Where != "" {
+		formatted += fmt.Sprintf(", where: %v", rule.Where)
+	}
+	return formatted + "}"
+}
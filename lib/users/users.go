@@ -21,6 +21,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"net/url"
+	"strings"
 	"time"
 
 	"github.com/gravitational/gravity/lib/defaults"
@@ -36,6 +37,24 @@ import (
 
 type RemoteAccessUser storage.RemoteAccessUser
 
+// LockoutHook is called when a user account gets locked until the specified time
+type LockoutHook func(username string, until time.Time)
+
+// PasswordExpired returns an error that indicates that the password
+// of the specified user has expired and must be changed
+func PasswordExpired(username string) error {
+	return trace.AccessDenied("%v: %v", username, passwordExpired)
+}
+
+// IsPasswordExpiredError returns true if the provided error indicates
+// that the password of the user has expired
+func IsPasswordExpiredError(err error) bool {
+	return trace.IsAccessDenied(err) && strings.Contains(err.Error(), passwordExpired)
+}
+
+// passwordExpired is the message of the expired password error
+const passwordExpired = "password has expired and must be changed"
+
 // Identity service manages users and account entries,
 // permissions and authentication, signups
 type Identity interface {
@@ -74,6 +93,17 @@ type Users interface {
 	// auth service until we figure out a better interface/way to do it
 	SetAuth(auth teleauth.ClientI)
 
+	// SetLockoutHook sets the function that is called when a user
+	// account gets locked after too many failed login attempts
+	SetLockoutHook(hook LockoutHook)
+
+	// UnlockUser clears the user account lockout and failed login attempts
+	UnlockUser(username string) error
+
+	// CheckPasswordExpired returns an error if the password of the user
+	// is older than allowed by the password policy
+	CheckPasswordExpired(user storage.User) error
+
 	// GetSiteProvisioningTokens returns a list of tokens available for the site
 	GetSiteProvisioningTokens(siteDomain string) ([]storage.ProvisioningToken, error)
 
@@ -140,6 +170,10 @@ type Users interface {
 	// GetAPIKeyByToken returns an API key for the specified token
 	GetAPIKeyByToken(token string) (*storage.APIKey, error)
 
+	// CheckSecondFactor verifies the provided one-time token against
+	// the second factor configured for the specified user
+	CheckSecondFactor(username, token string) error
+
 	// CreateAPIKey creates API key for agent user
 	CreateAPIKey(key storage.APIKey, upsert bool) (*storage.APIKey, error)
 
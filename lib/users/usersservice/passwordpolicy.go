@@ -0,0 +1,225 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usersservice
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gravitational/gravity/lib/storage"
+	"github.com/gravitational/gravity/lib/users"
+
+	teledefaults "github.com/gravitational/teleport/lib/defaults"
+	teleservices "github.com/gravitational/teleport/lib/services"
+	"github.com/gravitational/trace"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// SetLockoutHook sets the function that is called when
+// a user account is locked by the password policy
+func (c *UsersService) SetLockoutHook(hook users.LockoutHook) {
+	c.lockoutHook = hook
+}
+
+// UnlockUser clears the user account lockout and failed login attempts
+func (c *UsersService) UnlockUser(username string) error {
+	status := teleservices.LoginStatus{}
+	err := c.backend.UpdateUser(username, storage.UpdateUserReq{Status: &status})
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	err = c.backend.DeleteUserLoginAttempts(username)
+	if err != nil && !trace.IsNotFound(err) {
+		return trace.Wrap(err)
+	}
+	return nil
+}
+
+// getPasswordPolicy returns the cluster password policy or nil if it is not set
+func (c *UsersService) getPasswordPolicy() (storage.PasswordPolicy, error) {
+	policy, err := c.backend.GetPasswordPolicy()
+	if err != nil {
+		if trace.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, trace.Wrap(err)
+	}
+	return policy, nil
+}
+
+// passwordUpdate validates the new password of the specified user against
+// the password policy and returns the request that sets it.
+// The user is nil for new users
+func (c *UsersService) passwordUpdate(user storage.User, password []byte) (*storage.UpdateUserReq, error) {
+	policy, err := c.getPasswordPolicy()
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	if policy != nil {
+		if err := policy.CheckPassword(password); err != nil {
+			return nil, trace.Wrap(err)
+		}
+		if err := checkPasswordReuse(user, password, policy.GetHistoryDepth()); err != nil {
+			return nil, trace.Wrap(err)
+		}
+	}
+	return c.newPasswordUpdate(user, password, policy)
+}
+
+// newPasswordUpdate returns the request that sets the new password of the specified
+// user and keeps the previous passwords as required by the password policy
+func (c *UsersService) newPasswordUpdate(user storage.User, password []byte, policy storage.PasswordPolicy) (*storage.UpdateUserReq, error) {
+	hash, err := bcrypt.GenerateFromPassword(password, bcrypt.DefaultCost)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	hashS := string(hash)
+	var history []string
+	if policy != nil && user != nil && policy.GetHistoryDepth() > 1 {
+		history = append([]string{user.GetPassword()}, user.GetPasswordHistory()...)
+		if len(history) > policy.GetHistoryDepth()-1 {
+			history = history[:policy.GetHistoryDepth()-1]
+		}
+	}
+	changed := c.clock.Now().UTC()
+	return &storage.UpdateUserReq{
+		Password:        &hashS,
+		PasswordHistory: &history,
+		PasswordChanged: &changed,
+	}, nil
+}
+
+// checkPasswordReuse returns an error if the password matches the current
+// or one of the previous passwords of the user within the history depth
+func checkPasswordReuse(user storage.User, password []byte, depth int) error {
+	if user == nil || depth == 0 {
+		return nil
+	}
+	hashes := append([]string{user.GetPassword()}, user.GetPasswordHistory()...)
+	if len(hashes) > depth {
+		hashes = hashes[:depth]
+	}
+	for _, hash := range hashes {
+		if bcrypt.CompareHashAndPassword([]byte(hash), password) == nil {
+			return trace.BadParameter("password cannot be one of the last %v passwords", depth)
+		}
+	}
+	return nil
+}
+
+// CheckPasswordExpired returns an error if the user password is older
+// than allowed by the password policy
+func (c *UsersService) CheckPasswordExpired(user storage.User) error {
+	if user.GetType() != storage.AdminUser {
+		return nil
+	}
+	policy, err := c.getPasswordPolicy()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if policy == nil || policy.GetMaxAge() == 0 {
+		return nil
+	}
+	changed := user.GetPasswordChanged()
+	if changed.IsZero() {
+		// passwords set before the password change time has been tracked
+		changed = user.GetCreatedBy().Time
+	}
+	if changed.IsZero() || c.clock.Now().UTC().Before(changed.Add(policy.GetMaxAge())) {
+		return nil
+	}
+	return users.PasswordExpired(user.GetName())
+}
+
+// checkUserLocked returns an error if the user account is locked
+func (c *UsersService) checkUserLocked(user storage.User) error {
+	status := user.GetStatus()
+	if status.IsLocked && status.LockExpires.After(c.clock.Now().UTC()) {
+		return trace.AccessDenied("%v is locked until %v", user.GetName(),
+			status.LockExpires.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// addFailedLoginAttempt records a failed login attempt of the user
+// and locks the account if required by the password policy
+func (c *UsersService) addFailedLoginAttempt(username string) error {
+	policy, err := c.getPasswordPolicy()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if policy == nil || policy.GetMaxFailedAttempts() == 0 {
+		return nil
+	}
+	attempt := teleservices.LoginAttempt{Time: c.clock.Now().UTC(), Success: false}
+	return trace.Wrap(c.AddUserLoginAttempt(username, attempt, teledefaults.AttemptTTL))
+}
+
+// resetFailedLoginAttempts removes failed login attempts of the user
+// after a successful login if the password policy tracks them
+func (c *UsersService) resetFailedLoginAttempts(username string) error {
+	policy, err := c.getPasswordPolicy()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if policy == nil || policy.GetMaxFailedAttempts() == 0 {
+		return nil
+	}
+	err = c.backend.DeleteUserLoginAttempts(username)
+	if err != nil && !trace.IsNotFound(err) {
+		return trace.Wrap(err)
+	}
+	return nil
+}
+
+// applyLockoutPolicy locks the user account if the last login attempts
+// exceed the number of failed attempts allowed by the password policy
+func (c *UsersService) applyLockoutPolicy(username string) error {
+	policy, err := c.getPasswordPolicy()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if policy == nil || policy.GetMaxFailedAttempts() == 0 {
+		return nil
+	}
+	attempts, err := c.backend.GetUserLoginAttempts(username)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if !teleservices.LastFailed(policy.GetMaxFailedAttempts(), attempts) {
+		return nil
+	}
+	now := c.clock.Now().UTC()
+	status := teleservices.LoginStatus{
+		IsLocked: true,
+		LockedMessage: fmt.Sprintf("user has exceeded %v failed login attempts",
+			policy.GetMaxFailedAttempts()),
+		LockedTime:  now,
+		LockExpires: now.Add(policy.GetLockoutDuration()),
+	}
+	err = c.backend.UpdateUser(username, storage.UpdateUserReq{Status: &status})
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	log.Infof("Locked %v until %v after %v failed login attempts.",
+		username, status.LockExpires, policy.GetMaxFailedAttempts())
+	if c.lockoutHook != nil {
+		c.lockoutHook(username, status.LockExpires)
+	}
+	return nil
+}
@@ -35,6 +35,8 @@ import (
 	teleservices "github.com/gravitational/teleport/lib/services"
 	"github.com/gravitational/trace"
 	"github.com/jonboulle/clockwork"
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
 	log "github.com/sirupsen/logrus"
 	"github.com/tstranex/u2f"
 	"golang.org/x/crypto/bcrypt"
@@ -53,6 +55,8 @@ type UsersService struct {
 	backend storage.Backend
 	clock   clockwork.Clock
 	auth    teleauth.ClientI
+	// lockoutHook is called when a user account gets locked
+	lockoutHook users.LockoutHook
 }
 
 // New returns a new instance of UsersService
@@ -91,6 +95,12 @@ func (u *UsersService) CreateAPIKey(key storage.APIKey, upsert bool) (*storage.A
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
+	if err := u.checkAPIKeyRoles(key); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	if key.Created.IsZero() {
+		key.Created = u.clock.Now().UTC()
+	}
 	if key.Token == "" {
 		key.Token, err = users.CryptoRandomToken(defaults.AgentTokenBytes)
 		if err != nil {
@@ -133,6 +143,28 @@ func (u *UsersService) DeleteAPIKey(userEmail, token string) error {
 	return trace.Wrap(u.backend.DeleteAPIKey(userEmail, token))
 }
 
+// checkAPIKeyRoles makes sure that the key does not request roles
+// the user does not have
+func (u *UsersService) checkAPIKeyRoles(key storage.APIKey) error {
+	if len(key.Roles) == 0 {
+		return nil
+	}
+	roles, err := u.backend.GetUserRoles(key.UserEmail)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	userRoles := make([]string, 0, len(roles))
+	for _, role := range roles {
+		userRoles = append(userRoles, role.GetName())
+	}
+	for _, role := range key.Roles {
+		if !utils.StringInSlice(userRoles, role) {
+			return trace.BadParameter("user %v does not have role %v", key.UserEmail, role)
+		}
+	}
+	return nil
+}
+
 func (u *UsersService) CreateProvisioningToken(t storage.ProvisioningToken) (*storage.ProvisioningToken, error) {
 	return u.backend.CreateProvisioningToken(t)
 }
@@ -153,7 +185,14 @@ func (u *UsersService) GetOperationProvisioningToken(clusterName, operationID st
 
 // AddUserLoginAttempt logs user login attempt
 func (u *UsersService) AddUserLoginAttempt(user string, attempt teleservices.LoginAttempt, ttl time.Duration) error {
-	return u.backend.AddUserLoginAttempt(user, attempt, ttl)
+	err := u.backend.AddUserLoginAttempt(user, attempt, ttl)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if attempt.Success {
+		return nil
+	}
+	return trace.Wrap(u.applyLockoutPolicy(user))
 }
 
 // GetUserLoginAttempts returns user login attempts
@@ -309,49 +348,107 @@ func (c *UsersService) GetUsers() ([]teleservices.User, error) {
 // basic auth only that is used by agents running on sites
 func (c *UsersService) AuthenticateUser(creds httplib.AuthCreds) (storage.User, teleservices.AccessChecker, error) {
 	var user storage.User
+	var key *storage.APIKey
 	var err error
 	switch creds.Type {
 	case httplib.AuthBasic:
-		user, err = c.AuthenticateUserBasicAuth(creds.Username, creds.Password)
+		user, key, err = c.authenticateUserBasicAuth(creds.Username, creds.Password)
 	case httplib.AuthBearer:
-		user, err = c.AuthenticateUserBearerAuth(creds.Password)
+		user, key, err = c.authenticateUserBearerAuth(creds.Password)
 	default:
 		err = trace.AccessDenied("unsupported auth type: %v", creds.Type)
 	}
 	if err != nil {
 		return nil, nil, trace.Wrap(err)
 	}
-	checker, err := c.GetAccessChecker(user)
+	if key == nil {
+		checker, err := c.GetAccessChecker(user)
+		if err != nil {
+			return nil, nil, trace.Wrap(err)
+		}
+		return user, checker, nil
+	}
+	c.recordAPIKeyUsage(*key, creds.RemoteAddr)
+	roles, err := c.backend.GetUserRoles(user.GetName())
 	if err != nil {
 		return nil, nil, trace.Wrap(err)
 	}
-	return user, checker, nil
+	return user, users.NewAPIKeyChecker(*key, roles), nil
+}
+
+// recordAPIKeyUsage updates the last used time and address of the specified key.
+// To avoid writing to the backend on every request, the usage is only recorded
+// if the key is used from a new address or after defaults.APIKeyUsageUpdateInterval
+func (c *UsersService) recordAPIKeyUsage(key storage.APIKey, remoteAddr string) {
+	now := c.clock.Now().UTC()
+	if key.LastUsedFrom == remoteAddr && now.Sub(key.LastUsed) < defaults.APIKeyUsageUpdateInterval {
+		return
+	}
+	key.LastUsed = now
+	key.LastUsedFrom = remoteAddr
+	if _, err := c.backend.UpsertAPIKey(key); err != nil {
+		log.WithError(err).WithField("user", key.UserEmail).Warn("Failed to record API key usage.")
+	}
 }
 
 // GetAccessChecker returns access checker for user based on users roles
+// and the roles granted to the user by active access requests
 func (c *UsersService) GetAccessChecker(user storage.User) (teleservices.AccessChecker, error) {
 	roles, err := c.backend.GetUserRoles(user.GetName())
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
+	granted, err := users.GrantedRoles(c.backend, c.clock, user.GetName())
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	for _, name := range granted {
+		if hasRole(roles, name) {
+			continue
+		}
+		role, err := c.backend.GetRole(name)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		roles = append(roles, role)
+	}
 	return teleservices.NewRoleSet(roles...), nil
 }
 
+func hasRole(roles []teleservices.Role, name string) bool {
+	for _, role := range roles {
+		if role.GetName() == name {
+			return true
+		}
+	}
+	return false
+}
+
 // AuthenticateUserBasicAuth authenticates user using basic auth, where password's hash
 // is checked against stored hash for AdminUser and token is compared as is
 // for AgentUser (treated as API key)
 func (c *UsersService) AuthenticateUserBasicAuth(username, password string) (storage.User, error) {
-	i, err := c.backend.GetUser(username)
+	user, _, err := c.authenticateUserBasicAuth(username, password)
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
+	return user, nil
+}
+
+// authenticateUserBasicAuth authenticates user using basic auth.
+// Returns the API key if the password matched one of the user's API keys
+func (c *UsersService) authenticateUserBasicAuth(username, password string) (storage.User, *storage.APIKey, error) {
+	i, err := c.backend.GetUser(username)
+	if err != nil {
+		return nil, nil, trace.Wrap(err)
+	}
 	user, ok := i.(storage.User)
 	if !ok {
-		return nil, trace.BadParameter("unexpected user type %T", i)
+		return nil, nil, trace.BadParameter("unexpected user type %T", i)
 	}
 
 	if err = c.checkCanUseBasicAuth(user); err != nil {
-		return nil, trace.Wrap(err)
+		return nil, nil, trace.Wrap(err)
 	}
 
 	switch user.GetType() {
@@ -359,34 +456,48 @@ func (c *UsersService) AuthenticateUserBasicAuth(username, password string) (sto
 		// check the provided password against agent api keys (it may have a few)
 		keys, err := c.backend.GetAPIKeys(user.GetName())
 		if err != nil {
-			return nil, trace.Wrap(err)
+			return nil, nil, trace.Wrap(err)
 		}
 		for _, k := range keys {
 			if subtle.ConstantTimeCompare([]byte(k.Token), []byte(password)) == 1 {
-				return user, nil
+				return user, &k, nil
 			}
 		}
 
-		return nil, trace.AccessDenied("bad agent api key")
+		return nil, nil, trace.AccessDenied("bad agent api key")
 
 	case storage.AdminUser, storage.RegularUser:
 		keys, err := c.backend.GetAPIKeys(user.GetName())
 		if err != nil {
-			return nil, trace.Wrap(err)
+			return nil, nil, trace.Wrap(err)
 		}
 		for _, k := range keys {
 			if subtle.ConstantTimeCompare([]byte(k.Token), []byte(password)) == 1 {
-				return user, nil
+				return user, &k, nil
 			}
 		}
 
+		if err := c.checkUserLocked(user); err != nil {
+			return nil, nil, trace.Wrap(err)
+		}
+
 		if err := bcrypt.CompareHashAndPassword([]byte(user.GetPassword()), []byte(password)); err == nil {
-			return user, nil
+			if err := c.CheckPasswordExpired(user); err != nil {
+				return nil, nil, trace.Wrap(err)
+			}
+			if err := c.resetFailedLoginAttempts(user.GetName()); err != nil {
+				return nil, nil, trace.Wrap(err)
+			}
+			return user, nil, nil
+		}
+
+		if err := c.addFailedLoginAttempt(user.GetName()); err != nil {
+			return nil, nil, trace.Wrap(err)
 		}
 
-		return nil, trace.AccessDenied("bad user or password")
+		return nil, nil, trace.AccessDenied("bad user or password")
 	default:
-		return nil, trace.AccessDenied("unsupported user type: %v", user.GetType())
+		return nil, nil, trace.AccessDenied("unsupported user type: %v", user.GetType())
 	}
 }
 
@@ -408,27 +519,41 @@ func (c *UsersService) checkCanUseBasicAuth(user storage.User) error {
 // AuthenticateUserBearerAuth is used to authenticate site agent users
 // that connect using provisioning tokens or API keys
 func (c *UsersService) AuthenticateUserBearerAuth(token string) (storage.User, error) {
-	user, err := c.authenticateAPIKey(token)
-	if err != nil && !trace.IsNotFound(err) {
+	user, _, err := c.authenticateUserBearerAuth(token)
+	if err != nil {
 		return nil, trace.Wrap(err)
 	}
+	return user, nil
+}
+
+// authenticateUserBearerAuth authenticates user using an API key or a provisioning token.
+// Returns the API key if the user has been authenticated with one
+func (c *UsersService) authenticateUserBearerAuth(token string) (storage.User, *storage.APIKey, error) {
+	user, key, err := c.authenticateAPIKey(token)
+	if err != nil && !trace.IsNotFound(err) {
+		return nil, nil, trace.Wrap(err)
+	}
 	if user != nil {
-		return user, nil
+		return user, key, nil
 	}
-	return c.authenticateProvisioningToken(token)
+	user, err = c.authenticateProvisioningToken(token)
+	if err != nil {
+		return nil, nil, trace.Wrap(err)
+	}
+	return user, nil, nil
 }
 
 // authenticateAPIKey is a helper to authenticate a user using API key
-func (c *UsersService) authenticateAPIKey(token string) (storage.User, error) {
+func (c *UsersService) authenticateAPIKey(token string) (storage.User, *storage.APIKey, error) {
 	key, err := c.backend.GetAPIKey(token)
 	if err != nil {
-		return nil, trace.Wrap(err)
+		return nil, nil, trace.Wrap(err)
 	}
 	u, err := c.backend.GetUser(key.UserEmail)
 	if err != nil {
-		return nil, trace.Wrap(err)
+		return nil, nil, trace.Wrap(err)
 	}
-	return (storage.User)(u), nil
+	return (storage.User)(u), key, nil
 }
 
 // authenticateProvisioningToken is a helper to authenticate using provisioning token
@@ -590,7 +715,7 @@ func (c *UsersService) CreateAdmin(email, password string) error {
 		return trace.BadParameter("expected 1 site, got: %v", sites)
 	}
 
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	update, err := c.passwordUpdate(nil, []byte(password))
 	if err != nil {
 		return trace.Wrap(err)
 	}
@@ -601,10 +726,11 @@ func (c *UsersService) CreateAdmin(email, password string) error {
 	}
 
 	user := storage.NewUser(email, storage.UserSpecV2{
-		Type:      storage.AdminUser,
-		Roles:     []string{role.GetName()},
-		Password:  string(hashedPassword),
-		AccountID: accounts[0].ID,
+		Type:            storage.AdminUser,
+		Roles:           []string{role.GetName()},
+		Password:        *update.Password,
+		PasswordChanged: *update.PasswordChanged,
+		AccountID:       accounts[0].ID,
 	})
 	_, err = c.createUserWithRoles(user, []teleservices.Role{role}, nil)
 	return trace.Wrap(err)
@@ -663,6 +789,9 @@ func (c *UsersService) upsertAPIKey(key storage.APIKey) (err error) {
 			return trace.Wrap(err)
 		}
 	}
+	if key.Created.IsZero() {
+		key.Created = c.clock.Now().UTC()
+	}
 
 	_, err = c.backend.CreateAPIKey(key)
 	if err != nil && !trace.IsAlreadyExists(err) {
@@ -689,6 +818,10 @@ func (c *UsersService) UpsertUser(teleuser teleservices.User) error {
 			return trace.Wrap(err)
 		}
 	}
+	existing, err := c.backend.GetUser(u.GetName())
+	if err != nil && !trace.IsNotFound(err) {
+		return trace.Wrap(err)
+	}
 	var keys []storage.APIKey
 	if u.GetType() == storage.AgentUser {
 		// generate a unique api key for the agent
@@ -696,19 +829,36 @@ func (c *UsersService) UpsertUser(teleuser teleservices.User) error {
 		if err != nil {
 			return trace.Wrap(err)
 		}
-		keys = []storage.APIKey{{Token: token, UserEmail: u.GetName()}}
+		keys = []storage.APIKey{{
+			Token:     token,
+			UserEmail: u.GetName(),
+			Created:   c.clock.Now().UTC(),
+		}}
+	} else if existing != nil && existing.GetPassword() == u.GetPassword() {
+		// the user has been updated with the stored password hash
+		// (e.g. when locked by teleport), so keep the password as is
+		u.SetPasswordHistory(existing.GetPasswordHistory())
+		u.SetPasswordChanged(existing.GetPasswordChanged())
 	} else {
 		err := teleservices.VerifyPassword([]byte(u.GetPassword()))
 		if err != nil {
 			return trace.Wrap(err)
 		}
 		// for regular users, don't store passwords in plaintext
-		hash, err := bcrypt.GenerateFromPassword(
-			[]byte(u.GetPassword()), bcrypt.DefaultCost)
+		req, err := c.passwordUpdate(existing, []byte(u.GetPassword()))
 		if err != nil {
 			return trace.Wrap(err)
 		}
-		u.SetPassword(string(hash))
+		u.SetPassword(*req.Password)
+		u.SetPasswordHistory(*req.PasswordHistory)
+		u.SetPasswordChanged(*req.PasswordChanged)
+	}
+	if existing != nil {
+		// do not shorten the lockout set by the password policy
+		status := existing.GetStatus()
+		if status.IsLocked && status.LockExpires.After(u.GetStatus().LockExpires) {
+			u.SetStatus(status)
+		}
 	}
 	if _, err := c.backend.UpsertUser(u); err != nil {
 		return trace.Wrap(err)
@@ -843,14 +993,19 @@ func (c *UsersService) UpsertPassword(user string, password []byte) error {
 		return err
 	}
 
-	hash, err := bcrypt.GenerateFromPassword(password, bcrypt.DefaultCost)
+	existing, err := c.backend.GetUser(user)
 	if err != nil {
 		return trace.Wrap(err)
 	}
 
-	err = c.UpsertPasswordHash(user, hash)
+	update, err := c.passwordUpdate(existing, password)
 	if err != nil {
-		return err
+		return trace.Wrap(err)
+	}
+
+	err = c.backend.UpdateUser(user, *update)
+	if err != nil {
+		return trace.Wrap(err)
 	}
 	return nil
 }
@@ -882,6 +1037,54 @@ func (c *UsersService) DeleteUsedTOTPToken(user string) error {
 	return c.backend.DeleteUsedTOTPToken(user)
 }
 
+// CheckSecondFactor verifies the provided one-time token against
+// the second factor configured for the specified user
+func (c *UsersService) CheckSecondFactor(username, token string) error {
+	if token == "" {
+		return trace.AccessDenied("second factor token is required")
+	}
+	secret, err := c.backend.GetTOTP(username)
+	if err != nil && !trace.IsNotFound(err) {
+		return trace.Wrap(err)
+	}
+	if secret != "" {
+		return trace.Wrap(c.checkTOTP(username, secret, token))
+	}
+	state, err := c.GetHOTP(username)
+	if err != nil {
+		if trace.IsNotFound(err) {
+			return trace.AccessDenied("user %v has no second factor configured", username)
+		}
+		return trace.Wrap(err)
+	}
+	if !state.Scan(token, defaults.HOTPFirstTokensRange) {
+		return trace.AccessDenied("invalid second factor token")
+	}
+	// the counter has been incremented so the state needs to be updated
+	return trace.Wrap(c.UpsertHOTP(username, state))
+}
+
+func (c *UsersService) checkTOTP(username, secret, token string) error {
+	// prevent replaying the token during its validity period
+	usedToken, err := c.backend.GetUsedTOTPToken(username)
+	if err != nil && !trace.IsNotFound(err) {
+		return trace.Wrap(err)
+	}
+	if subtle.ConstantTimeCompare([]byte(token), []byte(usedToken)) == 1 {
+		return trace.AccessDenied("second factor token has already been used")
+	}
+	valid, err := totp.ValidateCustom(token, secret, c.clock.Now(), totp.ValidateOpts{
+		Period:    teleport.TOTPValidityPeriod,
+		Skew:      teleport.TOTPSkew,
+		Digits:    otp.DigitsSix,
+		Algorithm: otp.AlgorithmSHA1,
+	})
+	if err != nil || !valid {
+		return trace.AccessDenied("invalid second factor token")
+	}
+	return trace.Wrap(c.backend.UpsertUsedTOTPToken(username, token))
+}
+
 // UpsertSignupToken upserts signup token - one time token that lets user to create a user account
 func (c *UsersService) UpsertSignupToken(token string, tokenData teleservices.SignupToken, ttl time.Duration) error {
 	return trace.Errorf("not implemnented")
@@ -1188,23 +1391,24 @@ func (u *UsersService) ResetUserWithToken(req users.UserTokenCompleteRequest) (t
 		return nil, trace.Wrap(err)
 	}
 
-	hash, err := bcrypt.GenerateFromPassword(pass, bcrypt.DefaultCost)
-	if err != nil {
-		return nil, trace.Wrap(err)
-	}
-
 	userToken, otpBytes, err := u.ProcessUserTokenCompleteRequest(storage.UserTokenTypeReset, req)
 	if err != nil {
 		log.Warningf("Failed to get user token: %v.", err)
 		return nil, trace.AccessDenied("expired or incorrect token")
 	}
 
-	hashString := string(hash)
+	user, err := u.backend.GetUser(userToken.User)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
 
-	err = u.backend.UpdateUser(userToken.User, storage.UpdateUserReq{
-		HOTP:     &otpBytes,
-		Password: &hashString,
-	})
+	update, err := u.passwordUpdate(user, pass)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	update.HOTP = &otpBytes
+
+	err = u.backend.UpdateUser(userToken.User, *update)
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
@@ -1241,13 +1445,12 @@ func (c *UsersService) UpdatePassword(email string, oldPassword, newPassword use
 		return trace.BadParameter("passwords do not match")
 	}
 
-	hash, err := bcrypt.GenerateFromPassword(newPassword, bcrypt.DefaultCost)
+	update, err := c.passwordUpdate(user, newPassword)
 	if err != nil {
 		return trace.Wrap(err)
 	}
-	hashString := string(hash)
 
-	err = c.backend.UpdateUser(email, storage.UpdateUserReq{Password: &hashString})
+	err = c.backend.UpdateUser(email, *update)
 	if err != nil {
 		return trace.Wrap(err)
 	}
@@ -1257,7 +1460,7 @@ func (c *UsersService) UpdatePassword(email string, oldPassword, newPassword use
 
 // ResetPassword resets the user password and returns the new one
 func (c *UsersService) ResetPassword(email string) (string, error) {
-	_, err := c.backend.GetUser(email)
+	user, err := c.backend.GetUser(email)
 	if err != nil {
 		return "", trace.Wrap(err)
 	}
@@ -1267,13 +1470,19 @@ func (c *UsersService) ResetPassword(email string) (string, error) {
 		return "", trace.Wrap(err)
 	}
 
-	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	policy, err := c.getPasswordPolicy()
+	if err != nil {
+		return "", trace.Wrap(err)
+	}
+
+	// the generated password is not checked for complexity but
+	// still becomes a part of the password history
+	update, err := c.newPasswordUpdate(user, []byte(password), policy)
 	if err != nil {
 		return "", trace.Wrap(err)
 	}
 
-	hashS := string(hash)
-	err = c.backend.UpdateUser(email, storage.UpdateUserReq{Password: &hashS})
+	err = c.backend.UpdateUser(email, *update)
 	if err != nil {
 		return "", trace.Wrap(err)
 	}
@@ -1431,7 +1640,7 @@ func (c *UsersService) CreateUserWithToken(completeReq users.UserTokenCompleteRe
 	if err := completeReq.Password.Check(); err != nil {
 		return nil, trace.Wrap(err)
 	}
-	hash, err := bcrypt.GenerateFromPassword(completeReq.Password, bcrypt.DefaultCost)
+	update, err := c.passwordUpdate(nil, completeReq.Password)
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
@@ -1453,11 +1662,12 @@ func (c *UsersService) CreateUserWithToken(completeReq users.UserTokenCompleteRe
 	}
 
 	user, err := c.backend.CreateUser(storage.NewUser(invite.Name, storage.UserSpecV2{
-		Type:      storage.AdminUser,
-		HOTP:      otpBytes,
-		Password:  string(hash),
-		AccountID: defaults.SystemAccountID,
-		Roles:     roles,
+		Type:            storage.AdminUser,
+		HOTP:            otpBytes,
+		Password:        *update.Password,
+		PasswordChanged: *update.PasswordChanged,
+		AccountID:       defaults.SystemAccountID,
+		Roles:           roles,
 		CreatedBy: teleservices.CreatedBy{
 			User: teleservices.UserRef{Name: invite.CreatedBy},
 			Time: time.Now().UTC(),
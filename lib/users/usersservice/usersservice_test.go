@@ -31,6 +31,7 @@ import (
 	"github.com/gravitational/gravity/lib/users/suite"
 
 	"github.com/gravitational/teleport"
+	"github.com/gravitational/teleport/lib/auth"
 	teledefaults "github.com/gravitational/teleport/lib/defaults"
 	teleservices "github.com/gravitational/teleport/lib/services"
 	teleutils "github.com/gravitational/teleport/lib/utils"
@@ -39,6 +40,7 @@ import (
 	"github.com/gokyle/hotp"
 	"github.com/jonboulle/clockwork"
 	log "github.com/sirupsen/logrus"
+	"golang.org/x/crypto/bcrypt"
 	. "gopkg.in/check.v1"
 )
 
@@ -520,3 +522,534 @@ func newClusterAgentRole(name string, clusterName string) storage.RoleV2 {
 		KubernetesGroups: users.GetAdminKubernetesGroups(),
 	})
 }
+
+func (s *UsersSuite) TestScopedAPIKeys(c *C) {
+	role, err := users.NewAdminRole()
+	c.Assert(err, IsNil)
+	c.Assert(s.suite.Users.UpsertRole(role, 0), IsNil)
+
+	email := "ci@example.com"
+	err = s.suite.Users.UpsertUser(storage.NewUser(email, storage.UserSpecV2{
+		Type:  storage.AgentUser,
+		Roles: []string{role.GetName()},
+	}))
+	c.Assert(err, IsNil)
+
+	// key cannot request roles the user does not have
+	_, err = s.suite.Users.CreateAPIKey(storage.APIKey{
+		UserEmail: email,
+		Roles:     []string{"unknown"},
+	}, false)
+	c.Assert(trace.IsBadParameter(err), Equals, true)
+
+	key, err := s.suite.Users.CreateAPIKey(storage.APIKey{
+		UserEmail: email,
+		Roles:     []string{role.GetName()},
+		Rules:     []teleservices.Rule{teleservices.NewRule(storage.KindCluster, teleservices.RO())},
+		Clusters:  []string{"example.com"},
+	}, false)
+	c.Assert(err, IsNil)
+	c.Assert(key.Created, DeepEquals, s.clock.Now().UTC())
+
+	user, checker, err := s.suite.Users.AuthenticateUser(httplib.AuthCreds{
+		Type:       httplib.AuthBearer,
+		Password:   key.Token,
+		RemoteAddr: "192.168.1.1",
+	})
+	c.Assert(err, IsNil)
+	c.Assert(user.GetName(), Equals, email)
+	c.Assert(users.IsScopedAPIKey(checker), Equals, true)
+
+	clusterContext := func(name string) *users.Context {
+		return &users.Context{Context: teleservices.Context{Resource: storage.NewCluster(name)}}
+	}
+	err = checker.CheckAccessToRule(clusterContext("example.com"), teledefaults.Namespace,
+		storage.KindCluster, teleservices.VerbRead, false)
+	c.Assert(err, IsNil)
+	err = checker.CheckAccessToRule(clusterContext("example.com"), teledefaults.Namespace,
+		storage.KindCluster, teleservices.VerbUpdate, false)
+	c.Assert(trace.IsAccessDenied(err), Equals, true)
+	err = checker.CheckAccessToRule(clusterContext("example2.com"), teledefaults.Namespace,
+		storage.KindCluster, teleservices.VerbRead, false)
+	c.Assert(trace.IsAccessDenied(err), Equals, true)
+	// actions not bound to a cluster are outside of the cluster scope
+	err = checker.CheckAccessToRule(&users.Context{}, teledefaults.Namespace,
+		storage.KindCluster, teleservices.VerbRead, false)
+	c.Assert(trace.IsAccessDenied(err), Equals, true)
+	err = checker.CheckAccessToRule(&users.Context{Context: teleservices.Context{Resource: storage.NewUser(email, storage.UserSpecV2{})}},
+		teledefaults.Namespace, storage.KindCluster, teleservices.VerbRead, false)
+	c.Assert(trace.IsAccessDenied(err), Equals, true)
+
+	// the key scope applies to the requested kind rather than the fallback one:
+	// the fallback for operations is updating the cluster
+	key, err = s.suite.Users.CreateAPIKey(storage.APIKey{
+		UserEmail: email,
+		Rules:     []teleservices.Rule{teleservices.NewRule(storage.KindCluster, teleservices.RW())},
+	}, false)
+	c.Assert(err, IsNil)
+	_, checker, err = s.suite.Users.AuthenticateUser(httplib.AuthCreds{
+		Type:     httplib.AuthBearer,
+		Password: key.Token,
+	})
+	c.Assert(err, IsNil)
+	err = users.CheckAccess(checker, clusterContext("example.com"), teledefaults.Namespace,
+		storage.KindOperation, storage.OperationVerbExpand)
+	c.Assert(trace.IsAccessDenied(err), Equals, true)
+
+	// wildcard rules match any kind and verb
+	key, err = s.suite.Users.CreateAPIKey(storage.APIKey{
+		UserEmail: email,
+		Rules: []teleservices.Rule{
+			teleservices.NewRule(teleservices.Wildcard, []string{teleservices.Wildcard}),
+		},
+	}, false)
+	c.Assert(err, IsNil)
+	_, checker, err = s.suite.Users.AuthenticateUser(httplib.AuthCreds{
+		Type:       httplib.AuthBearer,
+		Password:   key.Token,
+		RemoteAddr: "192.168.1.1",
+	})
+	c.Assert(err, IsNil)
+	c.Assert(users.IsScopedAPIKey(checker), Equals, true)
+	err = users.CheckAccess(checker, clusterContext("example.com"), teledefaults.Namespace,
+		storage.KindOperation, storage.OperationVerbExpand)
+	c.Assert(err, IsNil)
+
+	// usage has been recorded
+	key, err = s.backend.GetAPIKey(key.Token)
+	c.Assert(err, IsNil)
+	c.Assert(key.LastUsed, DeepEquals, s.clock.Now().UTC())
+	c.Assert(key.LastUsedFrom, Equals, "192.168.1.1")
+}
+
+func (s *UsersSuite) TestRevokesUnusedAPIKeys(c *C) {
+	email := "ci@example.com"
+	err := s.suite.Users.UpsertUser(storage.NewUser(email, storage.UserSpecV2{Type: storage.AgentUser}))
+	c.Assert(err, IsNil)
+	// agent users are created with an API key
+	keys, err := s.backend.GetAPIKeys(email)
+	c.Assert(err, IsNil)
+	c.Assert(keys, HasLen, 1)
+	unused := keys[0]
+	used, err := s.suite.Users.CreateAPIKey(storage.APIKey{UserEmail: email}, false)
+	c.Assert(err, IsNil)
+	// key created before usage was tracked
+	legacy, err := s.backend.CreateAPIKey(storage.APIKey{UserEmail: email, Token: "legacy"})
+	c.Assert(err, IsNil)
+
+	agent := "agent@example.com"
+	err = s.suite.Users.UpsertUser(storage.NewUser(agent, storage.UserSpecV2{
+		Type:        storage.AgentUser,
+		ClusterName: "example.com",
+	}))
+	c.Assert(err, IsNil)
+
+	s.clock.Advance(30 * time.Minute)
+	_, _, err = s.suite.Users.AuthenticateUser(httplib.AuthCreds{
+		Type:     httplib.AuthBearer,
+		Password: used.Token,
+	})
+	c.Assert(err, IsNil)
+
+	s.clock.Advance(45 * time.Minute)
+	revoked, err := users.RevokeUnusedAPIKeys(s.backend, s.clock, time.Hour)
+	c.Assert(err, IsNil)
+	c.Assert(revoked, HasLen, 1)
+	c.Assert(revoked[0].Token, Equals, unused.Token)
+
+	keys, err = s.backend.GetAPIKeys(email)
+	c.Assert(err, IsNil)
+	c.Assert(keys, HasLen, 2)
+
+	s.clock.Advance(2 * time.Hour)
+	revoked, err = users.RevokeUnusedAPIKeys(s.backend, s.clock, time.Hour)
+	c.Assert(err, IsNil)
+	c.Assert(revoked, HasLen, 2)
+
+	keys, err = s.backend.GetAPIKeys(email)
+	c.Assert(err, IsNil)
+	c.Assert(keys, HasLen, 0)
+	keys, err = s.backend.GetAPIKeys(agent)
+	c.Assert(err, IsNil)
+	c.Assert(keys, HasLen, 1)
+	c.Assert(legacy, NotNil)
+}
+
+func (s *UsersSuite) TestAccessRequests(c *C) {
+	for _, name := range []string{"reader", "admin"} {
+		role, err := teleservices.NewRole(name, teleservices.RoleSpecV3{})
+		c.Assert(err, IsNil)
+		c.Assert(s.suite.Users.UpsertRole(role, 0), IsNil)
+	}
+	email := "alice@example.com"
+	err := s.suite.Users.UpsertUser(storage.NewUser(email, storage.UserSpecV2{
+		Type:     storage.AdminUser,
+		Password: "password1",
+		Roles:    []string{"reader"},
+	}))
+	c.Assert(err, IsNil)
+
+	req, err := s.backend.CreateAccessRequest(storage.AccessRequest{
+		ID:       "1",
+		User:     email,
+		Roles:    []string{"reader", "admin"},
+		Duration: time.Hour,
+		State:    storage.AccessRequestStatePending,
+		Created:  s.clock.Now().UTC(),
+	})
+	c.Assert(err, IsNil)
+
+	_, err = users.ReviewAccessRequest(s.backend, s.clock, req.ID, email, true)
+	c.Assert(trace.IsAccessDenied(err), Equals, true, Commentf("%v", err))
+
+	req, err = users.ReviewAccessRequest(s.backend, s.clock, req.ID, "bob@example.com", true)
+	c.Assert(err, IsNil)
+	c.Assert(req.State, Equals, storage.AccessRequestStateApproved)
+	c.Assert(req.GrantedRoles, DeepEquals, []string{"admin"})
+	c.Assert(req.Expires, Equals, s.clock.Now().UTC().Add(time.Hour))
+
+	// the granted roles are not stored
+	user, err := s.backend.GetUser(email)
+	c.Assert(err, IsNil)
+	c.Assert(user.GetRoles(), DeepEquals, []string{"reader"})
+
+	identity := users.NewSessionIdentity(s.suite.Users, s.backend, s.clock)
+	sessionUser, err := identity.GetUser(email)
+	c.Assert(err, IsNil)
+	c.Assert(sessionUser.GetRoles(), DeepEquals, []string{"reader", "admin"})
+
+	checker, err := s.suite.Users.GetAccessChecker(user)
+	c.Assert(err, IsNil)
+	c.Assert(checker.(teleservices.RoleSet).HasRole("admin"), Equals, true)
+
+	expires, ok, err := users.AccessExpires(s.backend, s.clock, email)
+	c.Assert(err, IsNil)
+	c.Assert(ok, Equals, true)
+	c.Assert(expires, Equals, req.Expires)
+
+	_, err = users.ReviewAccessRequest(s.backend, s.clock, req.ID, "bob@example.com", false)
+	c.Assert(trace.IsCompareFailed(err), Equals, true, Commentf("%v", err))
+
+	expired, err := users.ExpireAccessRequests(s.backend, s.clock)
+	c.Assert(err, IsNil)
+	c.Assert(expired, HasLen, 0)
+
+	s.clock.Advance(2 * time.Hour)
+	expired, err = users.ExpireAccessRequests(s.backend, s.clock)
+	c.Assert(err, IsNil)
+	c.Assert(expired, HasLen, 1)
+	c.Assert(expired[0].State, Equals, storage.AccessRequestStateExpired)
+
+	sessionUser, err = identity.GetUser(email)
+	c.Assert(err, IsNil)
+	c.Assert(sessionUser.GetRoles(), DeepEquals, []string{"reader"})
+
+	checker, err = s.suite.Users.GetAccessChecker(user)
+	c.Assert(err, IsNil)
+	c.Assert(checker.(teleservices.RoleSet).HasRole("admin"), Equals, false)
+
+	_, ok, err = users.AccessExpires(s.backend, s.clock, email)
+	c.Assert(err, IsNil)
+	c.Assert(ok, Equals, false)
+}
+
+func (s *UsersSuite) TestExpiredAccessRequestKeepsAssignedRoles(c *C) {
+	for _, name := range []string{"reader", "admin"} {
+		role, err := teleservices.NewRole(name, teleservices.RoleSpecV3{})
+		c.Assert(err, IsNil)
+		c.Assert(s.suite.Users.UpsertRole(role, 0), IsNil)
+	}
+	email := "alice@example.com"
+	err := s.suite.Users.UpsertUser(storage.NewUser(email, storage.UserSpecV2{
+		Type:     storage.AdminUser,
+		Password: "password1",
+		Roles:    []string{"reader"},
+	}))
+	c.Assert(err, IsNil)
+	req, err := s.backend.CreateAccessRequest(storage.AccessRequest{
+		ID:       "1",
+		User:     email,
+		Roles:    []string{"admin"},
+		Duration: time.Hour,
+		State:    storage.AccessRequestStatePending,
+		Created:  s.clock.Now().UTC(),
+	})
+	c.Assert(err, IsNil)
+	_, err = users.ReviewAccessRequest(s.backend, s.clock, req.ID, "bob@example.com", true)
+	c.Assert(err, IsNil)
+
+	// an administrator assigns the role permanently while the request is active
+	roles := []string{"reader", "admin"}
+	c.Assert(s.backend.UpdateUser(email, storage.UpdateUserReq{Roles: &roles}), IsNil)
+
+	s.clock.Advance(2 * time.Hour)
+	expired, err := users.ExpireAccessRequests(s.backend, s.clock)
+	c.Assert(err, IsNil)
+	c.Assert(expired, HasLen, 1)
+
+	user, err := s.backend.GetUser(email)
+	c.Assert(err, IsNil)
+	c.Assert(user.GetRoles(), DeepEquals, []string{"reader", "admin"})
+}
+
+func (s *UsersSuite) TestLockoutDoesNotStoreGrantedRoles(c *C) {
+	for _, name := range []string{"reader", "admin"} {
+		role, err := teleservices.NewRole(name, teleservices.RoleSpecV3{})
+		c.Assert(err, IsNil)
+		c.Assert(s.suite.Users.UpsertRole(role, 0), IsNil)
+	}
+	email := "alice@example.com"
+	err := s.suite.Users.UpsertUser(storage.NewUser(email, storage.UserSpecV2{
+		Type:     storage.AdminUser,
+		Password: "password1",
+		Roles:    []string{"reader"},
+	}))
+	c.Assert(err, IsNil)
+	req, err := s.backend.CreateAccessRequest(storage.AccessRequest{
+		ID:       "1",
+		User:     email,
+		Roles:    []string{"admin"},
+		Duration: time.Hour,
+		State:    storage.AccessRequestStatePending,
+		Created:  s.clock.Now().UTC(),
+	})
+	c.Assert(err, IsNil)
+	_, err = users.ReviewAccessRequest(s.backend, s.clock, req.ID, "bob@example.com", true)
+	c.Assert(err, IsNil)
+
+	clusterName, err := teleservices.NewClusterName(teleservices.ClusterNameSpecV2{
+		ClusterName: "example.com",
+	})
+	c.Assert(err, IsNil)
+	authServer, err := auth.NewAuthServer(&auth.InitConfig{
+		ClusterName: clusterName,
+		Identity:    users.NewSessionIdentity(s.suite.Users, s.backend, s.clock),
+	})
+	c.Assert(err, IsNil)
+
+	// teleport locks the user it has read with the granted roles and writes it back
+	failedLogin := func() error { return trace.AccessDenied("bad password") }
+	for i := 0; i < teledefaults.MaxLoginAttempts; i++ {
+		err = authServer.WithUserLock(email, failedLogin)
+		c.Assert(trace.IsAccessDenied(err), Equals, true, Commentf("%v", err))
+	}
+	user, err := s.backend.GetUser(email)
+	c.Assert(err, IsNil)
+	c.Assert(user.GetStatus().IsLocked, Equals, true)
+	c.Assert(user.GetRoles(), DeepEquals, []string{"reader"})
+}
+
+func (s *UsersSuite) TestFineGrainedRules(c *C) {
+	// legacy role that is only aware of the cluster rules
+	legacy, err := teleservices.NewRole("legacy", teleservices.RoleSpecV3{
+		Allow: teleservices.RoleConditions{
+			Rules: []teleservices.Rule{
+				teleservices.NewRule(storage.KindCluster, teleservices.RW()),
+			},
+		},
+	})
+	c.Assert(err, IsNil)
+	// role that can only add nodes and read the environment
+	operator, err := teleservices.NewRole("operator", teleservices.RoleSpecV3{
+		Allow: teleservices.RoleConditions{
+			Rules: []teleservices.Rule{
+				teleservices.NewRule(storage.KindCluster, teleservices.RW()),
+				teleservices.NewRule(storage.KindOperation, []string{storage.OperationVerbExpand}),
+				teleservices.NewRule(storage.KindRuntimeEnvironment, teleservices.RO()),
+			},
+		},
+		Deny: teleservices.RoleConditions{
+			Rules: []teleservices.Rule{
+				teleservices.NewRule(storage.KindOperation, []string{storage.OperationVerbUpdate}),
+			},
+		},
+	})
+	c.Assert(err, IsNil)
+
+	ctx := &users.Context{Context: teleservices.Context{Resource: storage.NewCluster("example.com")}}
+	testCases := []struct {
+		comment  string
+		roles    []teleservices.Role
+		kind     string
+		verb     string
+		allowed  bool
+		role     string
+		deny     bool
+		fallback bool
+	}{
+		{
+			comment:  "legacy role can run operations",
+			roles:    []teleservices.Role{legacy},
+			kind:     storage.KindOperation,
+			verb:     storage.OperationVerbUpdate,
+			allowed:  true,
+			role:     "legacy",
+			fallback: true,
+		},
+		{
+			comment:  "legacy role can update environment",
+			roles:    []teleservices.Role{legacy},
+			kind:     storage.KindRuntimeEnvironment,
+			verb:     teleservices.VerbUpdate,
+			allowed:  true,
+			role:     "legacy",
+			fallback: true,
+		},
+		{
+			comment: "operator can expand",
+			roles:   []teleservices.Role{operator},
+			kind:    storage.KindOperation,
+			verb:    storage.OperationVerbExpand,
+			allowed: true,
+			role:    "operator",
+		},
+		{
+			comment: "operator cannot upgrade",
+			roles:   []teleservices.Role{operator},
+			kind:    storage.KindOperation,
+			verb:    storage.OperationVerbUpdate,
+			role:    "operator",
+			deny:    true,
+		},
+		{
+			comment: "operator cannot shrink",
+			roles:   []teleservices.Role{operator},
+			kind:    storage.KindOperation,
+			verb:    storage.OperationVerbShrink,
+		},
+		{
+			comment: "operator can read environment",
+			roles:   []teleservices.Role{operator},
+			kind:    storage.KindRuntimeEnvironment,
+			verb:    teleservices.VerbRead,
+			allowed: true,
+			role:    "operator",
+		},
+		{
+			comment: "operator cannot update environment",
+			roles:   []teleservices.Role{operator},
+			kind:    storage.KindRuntimeEnvironment,
+			verb:    teleservices.VerbUpdate,
+		},
+		{
+			comment: "deny rule wins over legacy role",
+			roles:   []teleservices.Role{legacy, operator},
+			kind:    storage.KindOperation,
+			verb:    storage.OperationVerbUpdate,
+			role:    "operator",
+			deny:    true,
+		},
+	}
+	for _, tc := range testCases {
+		comment := Commentf(tc.comment)
+		checker := teleservices.NewRoleSet(tc.roles...)
+		err := users.CheckAccess(checker, ctx, teledefaults.Namespace, tc.kind, tc.verb)
+		if tc.allowed {
+			c.Assert(err, IsNil, comment)
+		} else {
+			c.Assert(trace.IsAccessDenied(err), Equals, true, comment)
+		}
+		decision, err := users.ExplainAccess(checker, ctx, teledefaults.Namespace, tc.kind, tc.verb)
+		c.Assert(err, IsNil, comment)
+		c.Assert(decision.Allowed, Equals, tc.allowed, comment)
+		c.Assert(decision.Fallback != nil, Equals, tc.fallback, comment)
+		if decision.Fallback != nil {
+			decision = decision.Fallback
+		}
+		c.Assert(decision.Role, Equals, tc.role, comment)
+		c.Assert(decision.Deny, Equals, tc.deny, comment)
+	}
+}
+
+func (s *UsersSuite) TestPasswordPolicy(c *C) {
+	role, err := teleservices.NewRole("reader", teleservices.RoleSpecV3{})
+	c.Assert(err, IsNil)
+	c.Assert(s.suite.Users.UpsertRole(role, 0), IsNil)
+	email := "carol@example.com"
+	err = s.suite.Users.UpsertUser(storage.NewUser(email, storage.UserSpecV2{
+		Type:     storage.AdminUser,
+		Password: "password1",
+		Roles:    []string{"reader"},
+	}))
+	c.Assert(err, IsNil)
+
+	err = s.backend.UpsertPasswordPolicy(storage.NewPasswordPolicy(storage.PasswordPolicySpecV1{
+		MinLength:         8,
+		CharacterClasses:  []string{storage.PasswordClassLower, storage.PasswordClassDigit},
+		HistoryDepth:      2,
+		MaxAge:            teleservices.NewDuration(24 * time.Hour),
+		MaxFailedAttempts: 3,
+		LockoutDuration:   teleservices.NewDuration(time.Hour),
+	}))
+	c.Assert(err, IsNil)
+
+	// complexity and reuse of the recent passwords
+	err = s.suite.Users.UpsertPassword(email, []byte("pass1"))
+	c.Assert(trace.IsBadParameter(err), Equals, true, Commentf("%v", err))
+	err = s.suite.Users.UpsertPassword(email, []byte("passwordonly"))
+	c.Assert(trace.IsBadParameter(err), Equals, true, Commentf("%v", err))
+	err = s.suite.Users.UpsertPassword(email, []byte("password1"))
+	c.Assert(trace.IsBadParameter(err), Equals, true, Commentf("%v", err))
+	c.Assert(s.suite.Users.UpsertPassword(email, []byte("password22")), IsNil)
+	err = s.suite.Users.UpsertPassword(email, []byte("password1"))
+	c.Assert(trace.IsBadParameter(err), Equals, true, Commentf("%v", err))
+	c.Assert(s.suite.Users.UpsertPassword(email, []byte("password33")), IsNil)
+	c.Assert(s.suite.Users.UpsertPassword(email, []byte("password1")), IsNil)
+
+	login := func(password string) error {
+		_, _, err := s.suite.Users.AuthenticateUser(httplib.AuthCreds{
+			Type:     httplib.AuthBasic,
+			Username: email,
+			Password: password,
+		})
+		return err
+	}
+
+	// user upserted with the stored password hash keeps the password
+	user, err := s.suite.Users.GetUser(email)
+	c.Assert(err, IsNil)
+	c.Assert(s.suite.Users.UpsertUser(user), IsNil)
+	c.Assert(login("password1"), IsNil)
+
+	// lockout after too many failed attempts
+	var locked []string
+	s.suite.Users.SetLockoutHook(func(username string, until time.Time) {
+		c.Assert(until, Equals, s.clock.Now().UTC().Add(time.Hour))
+		locked = append(locked, username)
+	})
+	for i := 0; i < 3; i++ {
+		err = login("wrong1234")
+		c.Assert(trace.IsAccessDenied(err), Equals, true, Commentf("%v", err))
+	}
+	c.Assert(locked, DeepEquals, []string{email})
+	err = login("password1")
+	c.Assert(trace.IsAccessDenied(err), Equals, true, Commentf("%v", err))
+
+	c.Assert(s.suite.Users.UnlockUser(email), IsNil)
+	c.Assert(login("password1"), IsNil)
+
+	// password expiration
+	s.clock.Advance(25 * time.Hour)
+	err = login("password1")
+	c.Assert(users.IsPasswordExpiredError(err), Equals, true, Commentf("%v", err))
+	telekubeUser, err := s.suite.Users.GetTelekubeUser(email)
+	c.Assert(err, IsNil)
+	err = s.suite.Users.CheckPasswordExpired(telekubeUser)
+	c.Assert(users.IsPasswordExpiredError(err), Equals, true, Commentf("%v", err))
+
+	// the password hash is still available to log in and change the password
+	hash, err := s.suite.Users.GetPasswordHash(email)
+	c.Assert(err, IsNil)
+	c.Assert(bcrypt.CompareHashAndPassword(hash, []byte("password1")), IsNil)
+	err = s.suite.Users.UpdatePassword(email, []byte("password1"), []byte("password3"))
+	c.Assert(err, IsNil)
+	telekubeUser, err = s.suite.Users.GetTelekubeUser(email)
+	c.Assert(err, IsNil)
+	c.Assert(s.suite.Users.CheckPasswordExpired(telekubeUser), IsNil)
+	c.Assert(login("password3"), IsNil)
+
+	s.clock.Advance(25 * time.Hour)
+	password, err := s.suite.Users.ResetPassword(email)
+	c.Assert(err, IsNil)
+	c.Assert(login(password), IsNil)
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webapi
+
+import (
+	"net/http"
+
+	"github.com/gravitational/gravity/lib/ops"
+	"github.com/gravitational/gravity/lib/ops/watch"
+
+	"github.com/gravitational/trace"
+	"github.com/julienschmidt/httprouter"
+)
+
+// getClusterEvents streams the cluster state changes the user has access to:
+// operations, their progress and plan phases, node status and alerts.
+// The events are sent as server-sent events or as JSON messages if the
+// client requests a web socket upgrade
+//
+// GET /portalapi/v1/sites/:domain/events
+//
+// Output:
+// event: progress
+// data: {"type": "progress", "time": "timestamp RFC 3339", "progress": {...}}
+func (m *Handler) getClusterEvents(w http.ResponseWriter, r *http.Request, p httprouter.Params, context *AuthContext) (interface{}, error) {
+	key := ops.SiteKey{AccountID: context.User.GetAccountID(), SiteDomain: p.ByName("domain")}
+	err := watch.ServeCluster(w, r, watch.Config{
+		Operator:    context.Operator,
+		Key:         key,
+		Nodes:       watch.ClusterNodes(context.Operator, key),
+		Alerts:      watch.ClusterAlerts(context.Operator, key),
+		FieldLogger: m.FieldLogger,
+	})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return nil, nil
+}
@@ -201,11 +201,12 @@ func NewAPI(cfg Config) (*Handler, error) {
 
 	// OAuth2 callbacks
 	h.GET("/github/callback", telehttplib.MakeHandler(h.githubCallback))
+	h.GET("/oidc/callback", telehttplib.MakeHandler(h.oidcCallback))
 
 	// Users
 	h.GET("/sites/:domain/users", h.needsAuth(h.getUsers))
 	h.PUT("/sites/:domain/users", h.needsAuth(h.updateUser))
-	h.PUT("/sites/:domain/users/password", h.needsAuth(h.updateUserPassword))
+	h.PUT("/sites/:domain/users/password", h.needsAuthWithExpiredPassword(h.updateUserPassword))
 	h.POST("/sites/:domain/users/:username/reset", h.needsAuth(h.createUserReset))
 	h.DELETE("/sites/:domain/users/:username", h.needsAuth(h.deleteUser))
 
@@ -214,6 +215,11 @@ func NewAPI(cfg Config) (*Handler, error) {
 	h.GET("/sites/:domain/invites", h.needsAuth(h.getUserInvites))
 	h.DELETE("/sites/:domain/invites/:username", h.needsAuth(h.deleteUserInvite))
 
+	// Access requests
+	h.POST("/sites/:domain/accessrequests", h.needsAuth(h.createAccessRequest))
+	h.GET("/sites/:domain/accessrequests", h.needsAuth(h.getAccessRequests))
+	h.PUT("/sites/:domain/accessrequests/:id", h.needsAuth(h.reviewAccessRequest))
+
 	// Resources
 	h.GET("/sites/:domain/resources/:kind", h.needsAuth(h.getResourceHandler))
 	h.PUT("/sites/:domain/resources", h.needsAuth(h.upsertResourceHandler))
@@ -238,6 +244,9 @@ func NewAPI(cfg Config) (*Handler, error) {
 	h.GET("/sites/:domain/operations", h.needsAuth(h.getOperations))
 	h.POST("/sites/:domain/operations/:operation_id/prechecks", h.needsAuth(h.validateServers))
 
+	// Cluster events
+	h.GET("/sites/:domain/events", h.needsAuth(h.getClusterEvents))
+
 	// Sites
 	h.POST("/sites", h.needsAuth(h.createSite))
 	h.POST("/sites/:domain/expand", h.needsAuth(h.expandSite))
@@ -274,7 +283,7 @@ func NewAPI(cfg Config) (*Handler, error) {
 
 	// User
 	h.GET("/sites/:domain/context", h.needsAuth(h.getWebContext))
-	h.GET("/user/status", h.needsAuth(h.getUserStatus))
+	h.GET("/user/status", h.needsAuthWithExpiredPassword(h.getUserStatus))
 
 	// Connect to Pod
 	h.GET("/sites/:domain/connect", h.needsAuth(h.clusterContainerConnect))
@@ -363,7 +372,50 @@ func (m *Handler) githubCallback(w http.ResponseWriter, r *http.Request, p httpr
 	})
 }
 
+// oidcCallback handles the callback from an OpenID Connect provider during
+// OAuth2 authentication flow
+//
+//   GET /oidc/callback
+//
+func (m *Handler) oidcCallback(w http.ResponseWriter, r *http.Request, p httprouter.Params) (interface{}, error) {
+	result, err := m.cfg.Auth.ValidateOIDCAuthCallback(r.URL.Query())
+	if err != nil {
+		m.Warnf("Error validating callback: %v.", err)
+		http.Redirect(w, r, "/web/msg/error/login_failed", http.StatusFound)
+		return nil, nil
+	}
+	m.Infof("Callback: %v %v %v.", result.Username, result.Identity, result.Req.Type)
+	return nil, m.plugin.CallbackHandler(w, r, CallbackParams{
+		Username:          result.Username,
+		Identity:          result.Identity,
+		Session:           result.Session,
+		Cert:              result.Cert,
+		TLSCert:           result.TLSCert,
+		HostSigners:       result.HostSigners,
+		Type:              result.Req.Type,
+		CreateWebSession:  result.Req.CreateWebSession,
+		CSRFToken:         result.Req.CSRFToken,
+		PublicKey:         result.Req.PublicKey,
+		ClientRedirectURL: result.Req.ClientRedirectURL,
+	})
+}
+
+// getUserStatus returns the status of the logged in user
+//
+// GET /portalapi/v1/user/status
+//
+// {
+//     "message": "OK",
+//     "password_expired": true // is set if the user must change the password
+// }
 func (m *Handler) getUserStatus(w http.ResponseWriter, r *http.Request, p httprouter.Params, ctx *AuthContext) (interface{}, error) {
+	err := m.cfg.Identity.CheckPasswordExpired(ctx.User)
+	if err != nil && !users.IsPasswordExpiredError(err) {
+		return nil, trace.Wrap(err)
+	}
+	if err != nil {
+		return map[string]interface{}{"message": "OK", "password_expired": true}, nil
+	}
 	return httplib.OK(), nil
 }
 
@@ -452,6 +504,66 @@ func (m *Handler) deleteUserInvite(w http.ResponseWriter, r *http.Request, p htt
 	return httplib.OK(), nil
 }
 
+type accessRequestReq struct {
+	Roles    []string `json:"roles"`
+	Reason   string   `json:"reason"`
+	Duration string   `json:"duration"`
+}
+
+// createAccessRequest creates a request of the current user
+// for temporary access to roles.
+//
+// POST /portalapi/v1/sites/:domain/accessrequests
+//
+func (m *Handler) createAccessRequest(w http.ResponseWriter, r *http.Request, p httprouter.Params, ctx *AuthContext) (interface{}, error) {
+	var req accessRequestReq
+	if err := telehttplib.ReadJSON(r, &req); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var duration time.Duration
+	if req.Duration != "" {
+		var err error
+		duration, err = time.ParseDuration(req.Duration)
+		if err != nil {
+			return nil, trace.BadParameter("invalid duration %q: %v", req.Duration, err)
+		}
+	}
+	return ctx.Operator.CreateAccessRequest(r.Context(), ops.CreateAccessRequestRequest{
+		SiteKey:  clusterKey(ctx, p),
+		Roles:    req.Roles,
+		Reason:   req.Reason,
+		Duration: duration,
+	})
+}
+
+// getAccessRequests returns the access requests visible to the current user.
+//
+// GET /portalapi/v1/sites/:domain/accessrequests
+//
+func (m *Handler) getAccessRequests(w http.ResponseWriter, r *http.Request, p httprouter.Params, ctx *AuthContext) (interface{}, error) {
+	return ctx.Operator.GetAccessRequests(clusterKey(ctx, p))
+}
+
+type reviewAccessRequestReq struct {
+	Approve bool `json:"approve"`
+}
+
+// reviewAccessRequest approves or denies a pending access request.
+//
+// PUT /portalapi/v1/sites/:domain/accessrequests/:id
+//
+func (m *Handler) reviewAccessRequest(w http.ResponseWriter, r *http.Request, p httprouter.Params, ctx *AuthContext) (interface{}, error) {
+	var req reviewAccessRequestReq
+	if err := telehttplib.ReadJSON(r, &req); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return ctx.Operator.ReviewAccessRequest(r.Context(), ops.ReviewAccessRequestRequest{
+		SiteKey: clusterKey(ctx, p),
+		ID:      p.ByName("id"),
+		Approve: req.Approve,
+	})
+}
+
 // createUserReset resets user credentials and returns a user token
 //
 // GET /portalapi/v1/sites/:domain/users/:username/reset
@@ -624,9 +736,13 @@ func (m *Handler) GetHandlerContext(w http.ResponseWriter, r *http.Request) (*Au
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
+	// Enrich request context with authenticated user information.
+	ctx := context.WithValue(r.Context(), constants.UserContext, user.GetName())
+	if token := r.Header.Get(constants.SecondFactorHeader); token != "" {
+		ctx = ops.WithSecondFactor(ctx, token)
+	}
 	return &AuthContext{
-		// Enrich request context with authenticated user information.
-		Context:        context.WithValue(r.Context(), constants.UserContext, user.GetName()),
+		Context:        ctx,
 		User:           user,
 		Operator:       ops.OperatorWithACL(m.cfg.Operator, m.cfg.Identity, user, checker),
 		Applications:   app.ApplicationsWithACL(m.cfg.Applications, m.cfg.Identity, user, checker),
@@ -638,6 +754,24 @@ func (m *Handler) GetHandlerContext(w http.ResponseWriter, r *http.Request) (*Au
 }
 
 func (m *Handler) needsAuth(fn authenticatedHandler) httprouter.Handle {
+	return telehttplib.MakeHandler(func(w http.ResponseWriter, r *http.Request, params httprouter.Params) (interface{}, error) {
+		context, err := m.GetHandlerContext(w, r)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		// users with expired passwords can only change them
+		if err := m.cfg.Identity.CheckPasswordExpired(context.User); err != nil {
+			return nil, trace.Wrap(err)
+		}
+		result, err := fn(w, r.WithContext(context.Context), params, context)
+		log.Debugf("%v %v %v", r.Method, r.URL.String(), err)
+		return result, trace.Wrap(err)
+	})
+}
+
+// needsAuthWithExpiredPassword is like needsAuth but lets users whose
+// password has expired through so they can change it
+func (m *Handler) needsAuthWithExpiredPassword(fn authenticatedHandler) httprouter.Handle {
 	return telehttplib.MakeHandler(func(w http.ResponseWriter, r *http.Request, params httprouter.Params) (interface{}, error) {
 		context, err := m.GetHandlerContext(w, r)
 		if err != nil {
@@ -1093,7 +1227,7 @@ func (m *Handler) createSite(w http.ResponseWriter, r *http.Request, p httproute
 	key, err := context.Operator.CreateSiteInstallOperation(r.Context(), opReq)
 	if err != nil {
 		siteKey := site.Key()
-		errDelete := context.Operator.DeleteSite(siteKey)
+		errDelete := context.Operator.DeleteSite(r.Context(), siteKey)
 		if errDelete != nil {
 			log.Errorf("failed to delete site %v: %v", siteKey, trace.DebugReport(errDelete))
 		}
@@ -1612,7 +1746,7 @@ func (m *Handler) uninstallSite(w http.ResponseWriter, r *http.Request, p httpro
 	// if we're asked only to remove the site from OpsCenter, do not launch uninstall operation
 	if input.Remove {
 		log.Infof("removing site %v from OpsCenter", p.ByName("domain"))
-		err := context.Operator.DeleteSite(ops.SiteKey{
+		err := context.Operator.DeleteSite(context.Context, ops.SiteKey{
 			AccountID:  context.User.GetAccountID(),
 			SiteDomain: p.ByName("domain"),
 		})
@@ -0,0 +1,114 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/gravitational/gravity/lib/localenv"
+	"github.com/gravitational/gravity/lib/ops"
+	"github.com/gravitational/gravity/lib/utils"
+
+	"github.com/gravitational/trace"
+)
+
+func requestAccess(env *localenv.LocalEnvironment, username string, roles []string, reason string, duration time.Duration) error {
+	operator, err := env.SiteOperator()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	cluster, err := operator.GetLocalSite()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	request, err := operator.CreateAccessRequest(context.TODO(), ops.CreateAccessRequestRequest{
+		SiteKey:  cluster.Key(),
+		User:     username,
+		Roles:    utils.FlattenStringSlice(roles),
+		Reason:   reason,
+		Duration: duration,
+	})
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	fmt.Printf("Access request %v has been created and is awaiting approval.\n", request.ID)
+	return nil
+}
+
+func listAccessRequests(env *localenv.LocalEnvironment) error {
+	operator, err := env.SiteOperator()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	cluster, err := operator.GetLocalSite()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	requests, err := operator.GetAccessRequests(cluster.Key())
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	w := new(tabwriter.Writer)
+	w.Init(os.Stdout, 0, 8, 1, '\t', 0)
+	fmt.Fprintf(w, "id\tuser\troles\tduration\tstate\treviewer\texpires\treason\n")
+	for _, r := range requests {
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\n", r.ID, r.User, strings.Join(r.Roles, ","),
+			r.Duration, r.State, r.Reviewer, formatAPIKeyTime(r.Expires), r.Reason)
+	}
+	w.Flush()
+	return nil
+}
+
+func reviewAccessRequest(env *localenv.LocalEnvironment, id string, approve bool) error {
+	operator, err := env.SiteOperator()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	cluster, err := operator.GetLocalSite()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	request, err := operator.ReviewAccessRequest(context.TODO(), ops.ReviewAccessRequestRequest{
+		SiteKey: cluster.Key(),
+		ID:      id,
+		Approve: approve,
+	})
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	if !approve {
+		fmt.Printf("Access request %v has been denied.\n", request.ID)
+		return nil
+	}
+	fmt.Printf("Access request %v has been approved, %v can assume roles %v until %v.\n",
+		request.ID, request.User, strings.Join(request.Roles, ","), formatAPIKeyTime(request.Expires))
+	return nil
+}
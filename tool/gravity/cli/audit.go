@@ -0,0 +1,177 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/gravitational/gravity/lib/constants"
+	"github.com/gravitational/gravity/lib/localenv"
+	"github.com/gravitational/gravity/lib/ops"
+	"github.com/gravitational/gravity/lib/storage"
+
+	"github.com/gravitational/trace"
+)
+
+func exportAuditRecords(env *localenv.LocalEnvironment, since, until string, format constants.Format, output string) error {
+	if format != constants.EncodingJSONL && format != constants.EncodingJSON {
+		return trace.BadParameter("unsupported format %q, supported are: %v, %v",
+			format, constants.EncodingJSONL, constants.EncodingJSON)
+	}
+
+	now := time.Now().UTC()
+	req := ops.GetAuditRecordsRequest{}
+	var err error
+	if req.Since, err = parseAuditTime(since, now); err != nil {
+		return trace.Wrap(err)
+	}
+	if req.Until, err = parseAuditTime(until, now); err != nil {
+		return trace.Wrap(err)
+	}
+
+	records, err := getAuditRecords(env, req)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	w := io.Writer(os.Stdout)
+	if output != "" {
+		f, err := os.Create(output)
+		if err != nil {
+			return trace.ConvertSystemError(err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if format == constants.EncodingJSON {
+		data, err := json.MarshalIndent(records, "", "    ")
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return trace.Wrap(err)
+	}
+	encoder := json.NewEncoder(w)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			return trace.Wrap(err)
+		}
+	}
+	return nil
+}
+
+func verifyAuditRecords(env *localenv.LocalEnvironment, file string) error {
+	var records []storage.AuditRecord
+	var err error
+	if file != "" {
+		records, err = readAuditRecords(file)
+	} else {
+		records, err = getAuditRecords(env, ops.GetAuditRecordsRequest{})
+	}
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if len(records) == 0 {
+		fmt.Println("No audit records to verify.")
+		return nil
+	}
+	if err := storage.VerifyAuditRecords(records); err != nil {
+		return trace.Wrap(err)
+	}
+	fmt.Printf("Verified audit records %v to %v.\n",
+		records[0].Index, records[len(records)-1].Index)
+	if records[0].Index != 1 {
+		fmt.Printf("Records before %v have not been verified.\n", records[0].Index)
+	}
+	return nil
+}
+
+func getAuditRecords(env *localenv.LocalEnvironment, req ops.GetAuditRecordsRequest) ([]storage.AuditRecord, error) {
+	operator, err := env.SiteOperator()
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	cluster, err := operator.GetLocalSite()
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	req.SiteKey = cluster.Key()
+	records, err := operator.GetAuditRecords(context.TODO(), req)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return records, nil
+}
+
+// readAuditRecords reads the records exported in either of supported formats
+func readAuditRecords(file string) (records []storage.AuditRecord, err error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, trace.ConvertSystemError(err)
+	}
+	data = bytes.TrimSpace(data)
+	if bytes.HasPrefix(data, []byte("[")) {
+		if err := json.Unmarshal(data, &records); err != nil {
+			return nil, trace.Wrap(err)
+		}
+		return records, nil
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, len(data)+1)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var record storage.AuditRecord
+		if err := json.Unmarshal(line, &record); err != nil {
+			return nil, trace.Wrap(err)
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return records, nil
+}
+
+// parseAuditTime parses either RFC3339 timestamp or duration relative to now
+func parseAuditTime(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return time.Time{}, trace.BadParameter(
+			"expected RFC3339 timestamp or duration, got %q", value)
+	}
+	return now.Add(-d), nil
+}
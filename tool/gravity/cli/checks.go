@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"io/ioutil"
+	"os"
 	"time"
 
 	"github.com/gravitational/gravity/lib/app/service"
@@ -41,6 +42,7 @@ type preflightChecksConfig struct {
 	imagePath    string
 	profileName  string
 	autoFix      bool
+	dryRun       bool
 	timeout      time.Duration
 }
 
@@ -83,10 +85,21 @@ func checkInstall(ctx context.Context, env *localenv.LocalEnvironment, config pr
 		Manifest: *manifest,
 		Role:     profileName,
 		AutoFix:  config.autoFix,
+		FixHost:  config.autoFix,
+		DryRun:   config.dryRun,
 	})
 	if err != nil {
 		return trace.Wrap(err)
 	}
+	if len(result.Plans) != 0 {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return trace.ConvertSystemError(err)
+		}
+		env.Printf("The following changes would be made on node %v:\n%v"+
+			"Run the command with --fix flag to apply them.\n",
+			hostname, checks.FormatFixPlans(result.Plans))
+	}
 	if len(result.Failed)+len(result.Fixable) == 0 {
 		env.PrintStep(color.GreenString("Checks have succeeded!"))
 		return nil
@@ -96,8 +109,8 @@ func checkInstall(ctx context.Context, env *localenv.LocalEnvironment, config pr
 		failedErr = trace.BadParameter(fmt.Sprintf("The following checks failed:\n%v",
 			checks.FormatFailedChecks(result.Failed)))
 	}
-	if len(result.Fixable) > 0 {
-		fixableErr = trace.BadParameter(fmt.Sprintf("The following checks failed, provide --autofix flag to let gravity to autofix them:\n%v",
+	if len(result.Fixable) > 0 && !config.dryRun {
+		fixableErr = trace.BadParameter(fmt.Sprintf("The following checks failed, provide --fix flag to let gravity fix them:\n%v",
 			checks.FormatFailedChecks(result.Fixable)))
 	}
 	return trace.NewAggregate(failedErr, fixableErr)
@@ -64,6 +64,7 @@ func newUpgradeConfig(g *Application) (*upgradeConfig, error) {
 		Manual:           *g.UpgradeCmd.Manual,
 		SkipVersionCheck: *g.UpgradeCmd.SkipVersionCheck,
 		Values:           values,
+		SecondFactor:     *g.UpgradeCmd.SecondFactor,
 	}, nil
 }
 
@@ -77,6 +78,8 @@ type upgradeConfig struct {
 	SkipVersionCheck bool
 	// Values are helm values in a marshaled yaml format.
 	Values []byte
+	// SecondFactor is the second factor token for the upgrade operation.
+	SecondFactor string
 }
 
 func updateTrigger(
@@ -107,6 +110,7 @@ func newClusterUpdater(
 		updatePackage: config.UpgradePackage,
 		unattended:    !config.Manual,
 		values:        config.Values,
+		secondFactor:  config.SecondFactor,
 	}
 	updater, err := newUpdater(ctx, localEnv, updateEnv, init)
 	if err != nil {
@@ -234,15 +238,22 @@ func (r *clusterInitializer) validatePreconditions(localEnv *localenv.LocalEnvir
 	return nil
 }
 
-func (r clusterInitializer) newOperation(operator ops.Operator, cluster ops.Site) (*ops.SiteOperationKey, error) {
-	return operator.CreateSiteAppUpdateOperation(context.TODO(), ops.CreateSiteAppUpdateOperationRequest{
-		AccountID:  cluster.AccountID,
-		SiteDomain: cluster.Domain,
-		App:        r.updateLoc.String(),
-		Vars: storage.OperationVariables{
-			Values: r.values,
-		},
+func (r clusterInitializer) newOperation(operator ops.Operator, cluster ops.Site) (key *ops.SiteOperationKey, err error) {
+	err = withSecondFactor(context.TODO(), r.secondFactor, func(ctx context.Context) (err error) {
+		key, err = operator.CreateSiteAppUpdateOperation(ctx, ops.CreateSiteAppUpdateOperationRequest{
+			AccountID:  cluster.AccountID,
+			SiteDomain: cluster.Domain,
+			App:        r.updateLoc.String(),
+			Vars: storage.OperationVariables{
+				Values: r.values,
+			},
+		})
+		return trace.Wrap(err)
 	})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return key, nil
 }
 
 func (r clusterInitializer) newOperationPlan(
@@ -302,6 +313,7 @@ type clusterInitializer struct {
 	updatePackage string
 	unattended    bool
 	values        []byte
+	secondFactor  string
 }
 
 const (
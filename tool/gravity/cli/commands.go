@@ -62,10 +62,20 @@ type Application struct {
 	JoinCmd JoinCmd
 	// AutoJoinCmd uses cloud provider info to join existing cluster
 	AutoJoinCmd AutoJoinCmd
+	// ReplaceCmd replaces an existing cluster node with this node
+	ReplaceCmd ReplaceCmd
 	// LeaveCmd removes the current node from the cluster
 	LeaveCmd LeaveCmd
 	// RemoveCmd removes the specified node from the cluster
 	RemoveCmd RemoveCmd
+	// NodeCmd combines node management subcommands
+	NodeCmd NodeCmd
+	// NodeMaintenanceCmd combines node maintenance subcommands
+	NodeMaintenanceCmd NodeMaintenanceCmd
+	// NodeMaintenanceStartCmd puts a node into maintenance mode
+	NodeMaintenanceStartCmd NodeMaintenanceStartCmd
+	// NodeMaintenanceStopCmd brings a node out of maintenance mode
+	NodeMaintenanceStopCmd NodeMaintenanceStopCmd
 	// PlanCmd manages an operation plan
 	PlanCmd PlanCmd
 	// UpdatePlanInitCmd creates a new update operation plan
@@ -100,6 +110,8 @@ type Application struct {
 	UpgradeCmd UpgradeCmd
 	// StatusCmd displays cluster status
 	StatusCmd StatusCmd
+	// RotateCertsCmd rotates cluster credentials
+	RotateCertsCmd RotateCertsCmd
 	// StatusResetCmd resets the cluster to active state
 	StatusResetCmd StatusResetCmd
 	// RegistryCmd allows to interact with the cluster private registry
@@ -202,6 +214,29 @@ type Application struct {
 	UsersInviteCmd UsersInviteCmd
 	// UsersResetCmd generates a user password reset link
 	UsersResetCmd UsersResetCmd
+	// UsersCanICmd checks user permissions
+	UsersCanICmd UsersCanICmd
+	// UsersUnlockCmd unlocks a locked user account
+	UsersUnlockCmd UsersUnlockCmd
+
+	// AccessCmd combines access request subcommands
+	AccessCmd AccessCmd
+	// AccessRequestCmd requests temporary access to roles
+	AccessRequestCmd AccessRequestCmd
+	// AccessListCmd lists access requests
+	AccessListCmd AccessListCmd
+	// AccessApproveCmd approves an access request
+	AccessApproveCmd AccessReviewCmd
+	// AccessDenyCmd denies an access request
+	AccessDenyCmd AccessReviewCmd
+
+	// AuditCmd combines audit log subcommands
+	AuditCmd AuditCmd
+	// AuditExportCmd exports audit log records
+	AuditExportCmd AuditExportCmd
+	// AuditVerifyCmd verifies the integrity of audit log records
+	AuditVerifyCmd AuditVerifyCmd
+
 	// APIKeyCmd combines subcommands for API tokens
 	APIKeyCmd APIKeyCmd
 	// APIKeyCreateCmd creates a new token
@@ -240,6 +275,10 @@ type Application struct {
 	RPCAgentInstallCmd RPCAgentInstallCmd
 	// RPCAgentRunCmd runs RPC agent
 	RPCAgentRunCmd RPCAgentRunCmd
+	// RPCAgentStatusCmd displays the status of operation agents
+	RPCAgentStatusCmd RPCAgentStatusCmd
+	// RPCAgentEvictCmd disconnects an agent from the operation
+	RPCAgentEvictCmd RPCAgentEvictCmd
 	// SystemCmd combines system subcommands
 	SystemCmd SystemCmd
 	// SystemRotateCertsCmd renews cluster certificates on local node
@@ -282,6 +321,14 @@ type Application struct {
 	SystemDevicemapperUnmountCmd SystemDevicemapperUnmountCmd
 	// SystemDevicemapperSystemDirCmd show LVM system directory
 	SystemDevicemapperSystemDirCmd SystemDevicemapperSystemDirCmd
+	// SystemOverlayCmd combines overlay2 related subcommands
+	SystemOverlayCmd SystemOverlayCmd
+	// SystemOverlayMountCmd configures overlay2 device
+	SystemOverlayMountCmd SystemOverlayMountCmd
+	// SystemOverlayUnmountCmd removes overlay2 device
+	SystemOverlayUnmountCmd SystemOverlayUnmountCmd
+	// SystemOverlayMigrateCmd migrates docker storage from devicemapper to overlay2
+	SystemOverlayMigrateCmd SystemOverlayMigrateCmd
 	// SystemExportRuntimeJournalCmd exports runtime journal to a file
 	SystemExportRuntimeJournalCmd SystemExportRuntimeJournalCmd
 	// SystemStreamRuntimeJournalCmd streams contents of the runtime journal to a file
@@ -402,6 +449,8 @@ type InstallCmd struct {
 	Set *[]string
 	// Values is a list of YAML files with Helm chart values.
 	Values *[]string
+	// FixHost enables fixing host environment problems found by pre-flight checks
+	FixHost *bool
 }
 
 // JoinCmd joins to the installer or existing cluster
@@ -433,6 +482,33 @@ type JoinCmd struct {
 	// the client will simply connect to the service and stream its output and errors
 	// and control whether it should stop
 	FromService *bool
+	// FixHost enables fixing host environment problems found by pre-flight checks
+	FixHost *bool
+}
+
+// ReplaceCmd replaces an existing cluster node with this node
+type ReplaceCmd struct {
+	*kingpin.CmdClause
+	// Node is the node to replace
+	Node *string
+	// Token is join token
+	Token *string
+	// PeerAddr is cluster address
+	PeerAddr *string
+	// AdvertiseAddr is local node advertise IP address
+	AdvertiseAddr *string
+	// DockerDevice is device to use for Docker data
+	DockerDevice *string
+	// SystemDevice is device to use for system data
+	SystemDevice *string
+	// Mounts is additional app mounts
+	Mounts *configure.KeyVal
+	// Confirm suppresses confirmation prompt
+	Confirm *bool
+	// FromService specifies whether this process runs in service mode
+	FromService *bool
+	// FixHost enables fixing host environment problems found by pre-flight checks
+	FixHost *bool
 }
 
 // AutoJoinCmd uses cloud provider info to join existing cluster
@@ -471,6 +547,36 @@ type LeaveCmd struct {
 	Confirm *bool
 }
 
+// NodeCmd combines node management subcommands
+type NodeCmd struct {
+	*kingpin.CmdClause
+}
+
+// NodeMaintenanceCmd combines node maintenance subcommands
+type NodeMaintenanceCmd struct {
+	*kingpin.CmdClause
+}
+
+// NodeMaintenanceStartCmd cordons and drains a node and marks it as in maintenance
+type NodeMaintenanceStartCmd struct {
+	*kingpin.CmdClause
+	// Node is the node to put into maintenance mode
+	Node *string
+	// Timeout is the node drain timeout
+	Timeout *time.Duration
+	// StopServices specifies whether to stop cluster services on the node
+	StopServices *bool
+	// Confirm suppresses confirmation prompt
+	Confirm *bool
+}
+
+// NodeMaintenanceStopCmd uncordons a node and clears its maintenance mark
+type NodeMaintenanceStopCmd struct {
+	*kingpin.CmdClause
+	// Node is the node to bring out of maintenance mode
+	Node *string
+}
+
 // RemoveCmd removes the specified node from the cluster
 type RemoveCmd struct {
 	*kingpin.CmdClause
@@ -480,6 +586,8 @@ type RemoveCmd struct {
 	Force *bool
 	// Confirm suppresses confirmation prompt
 	Confirm *bool
+	// SecondFactor is the second factor token
+	SecondFactor *string
 }
 
 // ResumeCmd resumes active operation
@@ -658,6 +766,17 @@ type UpgradeCmd struct {
 	Set *[]string
 	// Values is a list of YAML files with Helm chart values.
 	Values *[]string
+	// SecondFactor is the second factor token
+	SecondFactor *string
+}
+
+// RotateCertsCmd rotates cluster credentials
+type RotateCertsCmd struct {
+	*kingpin.CmdClause
+	// RPC specifies whether to rotate RPC agent credentials
+	RPC *bool
+	// SecondFactor is the optional one-time token
+	SecondFactor *string
 }
 
 // StatusCmd displays cluster status
@@ -731,6 +850,8 @@ type CheckCmd struct {
 	Profile *string
 	// AutoFix enables automatic fixing of some failed checks
 	AutoFix *bool
+	// DryRun only prints the changes automatic fixing would make
+	DryRun *bool
 	// ImagePath is path to unpacked cluster image
 	ImagePath *string
 	// Timeout is the time allotted to run preflight checks
@@ -1244,6 +1365,79 @@ type UsersResetCmd struct {
 	TTL *time.Duration
 }
 
+// UsersCanICmd checks whether a user can perform an action
+type UsersCanICmd struct {
+	*kingpin.CmdClause
+	// Verb is the action to check
+	Verb *string
+	// Kind is the resource kind to check
+	Kind *string
+	// Name is user name
+	Name *string
+}
+
+// UsersUnlockCmd unlocks a user locked after too many failed logins
+type UsersUnlockCmd struct {
+	*kingpin.CmdClause
+	// Name is user name
+	Name *string
+}
+
+// AccessCmd combines access request subcommands
+type AccessCmd struct {
+	*kingpin.CmdClause
+}
+
+// AccessRequestCmd requests temporary access to roles
+type AccessRequestCmd struct {
+	*kingpin.CmdClause
+	// Roles is the requested roles
+	Roles *[]string
+	// Reason is the request justification
+	Reason *string
+	// Duration is how long the access is requested for
+	Duration *time.Duration
+	// User is the name of the user to request access for
+	User *string
+}
+
+// AccessListCmd lists access requests
+type AccessListCmd struct {
+	*kingpin.CmdClause
+}
+
+// AccessReviewCmd approves or denies an access request
+type AccessReviewCmd struct {
+	*kingpin.CmdClause
+	// ID is the access request ID
+	ID *string
+}
+
+// AuditCmd combines audit log subcommands
+type AuditCmd struct {
+	*kingpin.CmdClause
+}
+
+// AuditExportCmd exports audit log records
+type AuditExportCmd struct {
+	*kingpin.CmdClause
+	// Since excludes records created before it
+	Since *string
+	// Until excludes records created after it
+	Until *string
+	// Format is the output format
+	Format *constants.Format
+	// Output is the file to write records to, stdout if empty
+	Output *string
+}
+
+// AuditVerifyCmd verifies the integrity of audit log records
+type AuditVerifyCmd struct {
+	*kingpin.CmdClause
+	// File is the exported records file to verify instead of the cluster audit log
+	File *string
+}
+
 // APIKeyCmd combines subcommands for API tokens
 type APIKeyCmd struct {
 	*kingpin.CmdClause
@@ -1256,6 +1450,12 @@ type APIKeyCreateCmd struct {
 	Email *string
 	// OpsCenterURL is cluster URL
 	OpsCenterURL *string
+	// Roles optionally restricts the token to the subset of user's roles
+	Roles *[]string
+	// Rules optionally restricts the token to the specified resources and verbs
+	Rules *[]string
+	// Clusters optionally restricts the token to the specified clusters
+	Clusters *[]string
 }
 
 // APIKeyListCmd lists tokens
@@ -1379,6 +1579,24 @@ type RPCAgentRunCmd struct {
 	Args *[]string
 }
 
+// RPCAgentStatusCmd displays the status of operation agents
+type RPCAgentStatusCmd struct {
+	*kingpin.CmdClause
+	// OperationID is optional ID of the operation to display agents for.
+	// Defaults to the active operation
+	OperationID *string
+}
+
+// RPCAgentEvictCmd disconnects an agent from the operation
+type RPCAgentEvictCmd struct {
+	*kingpin.CmdClause
+	// Addr is the address of the agent to evict
+	Addr *string
+	// OperationID is optional ID of the operation to evict the agent from.
+	// Defaults to the active operation
+	OperationID *string
+}
+
 // SystemCmd combines system subcommands
 type SystemCmd struct {
 	*kingpin.CmdClause
@@ -1560,6 +1778,44 @@ type SystemDevicemapperSystemDirCmd struct {
 	*kingpin.CmdClause
 }
 
+// SystemOverlayCmd combines overlay2 related subcommands
+type SystemOverlayCmd struct {
+	*kingpin.CmdClause
+}
+
+// SystemOverlayMountCmd configures overlay2 device
+type SystemOverlayMountCmd struct {
+	*kingpin.CmdClause
+	// Disk is the overlay2 device
+	Disk *string
+	// Filesystem is the filesystem to format the device with
+	Filesystem *string
+	// Quota enables project quotas
+	Quota *bool
+	// Fstab persists the mount in /etc/fstab instead of a systemd mount unit
+	Fstab *bool
+}
+
+// SystemOverlayUnmountCmd removes overlay2 device
+type SystemOverlayUnmountCmd struct {
+	*kingpin.CmdClause
+	// Disk is the overlay2 device to wipe
+	Disk *string
+}
+
+// SystemOverlayMigrateCmd migrates docker storage from devicemapper to overlay2
+type SystemOverlayMigrateCmd struct {
+	*kingpin.CmdClause
+	// Disk is the overlay2 device
+	Disk *string
+	// Filesystem is the filesystem to format the device with
+	Filesystem *string
+	// Quota enables project quotas
+	Quota *bool
+	// Fstab persists the mount in /etc/fstab instead of a systemd mount unit
+	Fstab *bool
+}
+
 // SystemExportRuntimeJournalCmd exports runtime journal to a file
 type SystemExportRuntimeJournalCmd struct {
 	*kingpin.CmdClause
@@ -1613,6 +1869,8 @@ type GarbageCollectCmd struct {
 	// Confirmed is whether the user has confirmed the removal of custom docker
 	// images
 	Confirmed *bool
+	// Force allows to run garbage collection outside of the maintenance window
+	Force *bool
 }
 
 // GarbageCollectPlanCmd displays the plan of the garbage collection operation
@@ -1682,6 +1940,8 @@ type ResourceCreateCmd struct {
 	Manual *bool
 	// Confirmed suppresses confirmation prompt
 	Confirmed *bool
+	// SecondFactor is the second factor token
+	SecondFactor *string
 }
 
 // ResourceRemoveCmd removes specified resource
@@ -1701,6 +1961,8 @@ type ResourceRemoveCmd struct {
 	Manual *bool
 	// Confirmed suppresses confirmation prompt
 	Confirmed *bool
+	// SecondFactor is the second factor token
+	SecondFactor *string
 }
 
 // ResourceGetCmd shows specified resource
@@ -32,6 +32,7 @@ import (
 	"github.com/gravitational/gravity/lib/app"
 	appservice "github.com/gravitational/gravity/lib/app"
 	autoscaleaws "github.com/gravitational/gravity/lib/autoscale/aws"
+	autoscalegce "github.com/gravitational/gravity/lib/autoscale/gce"
 	awscloud "github.com/gravitational/gravity/lib/cloudprovider/aws"
 	cloudaws "github.com/gravitational/gravity/lib/cloudprovider/aws"
 	cloudgce "github.com/gravitational/gravity/lib/cloudprovider/gce"
@@ -112,6 +113,8 @@ type InstallConfig struct {
 	DockerDevice string
 	// Mounts is a list of mount points (name -> source pairs)
 	Mounts map[string]string
+	// FixHost enables fixing host environment problems found by pre-flight checks
+	FixHost bool
 	// DNSOverrides contains installer node DNS overrides
 	DNSOverrides storage.DNSOverrides
 	// PodCIDR is a pod network CIDR
@@ -191,6 +194,7 @@ func NewInstallConfig(env *localenv.LocalEnvironment, g *Application) (*InstallC
 		SystemDevice:  *g.InstallCmd.SystemDevice,
 		DockerDevice:  *g.InstallCmd.DockerDevice,
 		Mounts:        *g.InstallCmd.Mounts,
+		FixHost:       *g.InstallCmd.FixHost,
 		PodCIDR:       *g.InstallCmd.PodCIDR,
 		ServiceCIDR:   *g.InstallCmd.ServiceCIDR,
 		VxlanPort:     *g.InstallCmd.VxlanPort,
@@ -392,6 +396,7 @@ func (i *InstallConfig) NewInstallerConfig(
 		SystemDevice:       i.SystemDevice,
 		DockerDevice:       i.DockerDevice,
 		Mounts:             i.Mounts,
+		FixHost:            i.FixHost,
 		DNSConfig:          i.DNSConfig,
 		PodCIDR:            i.PodCIDR,
 		ServiceCIDR:        i.ServiceCIDR,
@@ -677,6 +682,10 @@ type JoinConfig struct {
 	// SkipWizard specifies to the join agents that this join request is not too a wizard,
 	// and as such wizard connectivity should be skipped
 	SkipWizard bool
+	// ReplaceNode is the optional cluster node the joining node replaces
+	ReplaceNode string
+	// FixHost enables fixing host environment problems found by pre-flight checks
+	FixHost bool
 }
 
 // NewJoinConfig populates join configuration from the provided CLI application
@@ -694,6 +703,26 @@ func NewJoinConfig(g *Application) JoinConfig {
 		Mounts:        *g.JoinCmd.Mounts,
 		OperationID:   *g.JoinCmd.OperationID,
 		FromService:   *g.JoinCmd.FromService,
+		FixHost:       *g.JoinCmd.FixHost,
+	}
+}
+
+// NewReplaceConfig populates join configuration for the replace command
+// from the provided CLI application
+func NewReplaceConfig(g *Application) JoinConfig {
+	return JoinConfig{
+		SystemLogFile: *g.SystemLogFile,
+		UserLogFile:   *g.UserLogFile,
+		PeerAddrs:     *g.ReplaceCmd.PeerAddr,
+		AdvertiseAddr: *g.ReplaceCmd.AdvertiseAddr,
+		Token:         *g.ReplaceCmd.Token,
+		SystemDevice:  *g.ReplaceCmd.SystemDevice,
+		DockerDevice:  *g.ReplaceCmd.DockerDevice,
+		Mounts:        *g.ReplaceCmd.Mounts,
+		FromService:   *g.ReplaceCmd.FromService,
+		ReplaceNode:   *g.ReplaceCmd.Node,
+		FixHost:       *g.ReplaceCmd.FixHost,
+		SkipWizard:    true,
 	}
 }
 
@@ -732,6 +761,8 @@ func (j *JoinConfig) NewPeerConfig(env, joinEnv *localenv.LocalEnvironment) (con
 		StateDir:           joinEnv.StateDir,
 		OperationID:        j.OperationID,
 		SkipWizard:         j.SkipWizard,
+		ReplaceNode:        j.ReplaceNode,
+		FixHost:            j.FixHost,
 	}, nil
 }
 
@@ -766,9 +797,10 @@ func (r *removeConfig) checkAndSetDefaults() error {
 }
 
 type removeConfig struct {
-	server    string
-	force     bool
-	confirmed bool
+	server       string
+	force        bool
+	confirmed    bool
+	secondFactor string
 }
 
 func (r *autojoinConfig) newJoinConfig() JoinConfig {
@@ -917,10 +949,13 @@ func retryUpdateJoinConfigFromCloudMetadata(ctx context.Context, config *autojoi
 }
 
 func updateJoinConfigFromCloudMetadata(ctx context.Context, config *autojoinConfig) error {
+	if gcemeta.OnGCE() {
+		return trace.Wrap(updateJoinConfigFromGCEMetadata(config))
+	}
 	instance, err := cloudaws.NewLocalInstance()
 	if err != nil {
 		log.WithError(err).Warn("Failed to fetch instance metadata on AWS.")
-		return trace.BadParameter("autojoin only supports AWS")
+		return trace.BadParameter("autojoin only supports AWS and GCE")
 	}
 	config.advertiseAddr = instance.PrivateIP
 
@@ -944,6 +979,26 @@ func updateJoinConfigFromCloudMetadata(ctx context.Context, config *autojoinConf
 	return nil
 }
 
+// updateJoinConfigFromGCEMetadata reads the join configuration published
+// by the cluster autoscaler to this instance's metadata
+func updateJoinConfigFromGCEMetadata(config *autojoinConfig) (err error) {
+	config.advertiseAddr, err = gcemeta.InternalIP()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	// Discovery information is published to the instance some time
+	// after it has been created
+	config.serviceURL, err = autoscalegce.GetServiceURL()
+	if err != nil {
+		return trace.Retry(err, "waiting for cluster discovery information")
+	}
+	config.token, err = autoscalegce.GetJoinToken()
+	if err != nil {
+		return trace.Retry(err, "waiting for cluster discovery information")
+	}
+	return nil
+}
+
 func convertMounts(mounts map[string]string) (result []*proto.Mount) {
 	result = make([]*proto.Mount, 0, len(mounts))
 	for name, source := range mounts {
@@ -37,7 +37,7 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-func garbageCollect(env *localenv.LocalEnvironment, manual, confirmed bool) error {
+func garbageCollect(env *localenv.LocalEnvironment, manual, confirmed, force bool) error {
 	if !confirmed {
 		env.Println("This operation will also remove docker images that " +
 			"you manually pushed to the docker registry. Are you sure?")
@@ -51,7 +51,7 @@ func garbageCollect(env *localenv.LocalEnvironment, manual, confirmed bool) erro
 		}
 	}
 
-	collector, err := newCollector(env)
+	collector, err := newCollector(env, force)
 	if err != nil {
 		return trace.Wrap(err)
 	}
@@ -84,7 +84,7 @@ $ gravity plan resume`)
 	return nil
 }
 
-func newCollector(env *localenv.LocalEnvironment) (*vacuum.Collector, error) {
+func newCollector(env *localenv.LocalEnvironment, force bool) (*vacuum.Collector, error) {
 	clusterPackages, err := env.ClusterPackages()
 	if err != nil {
 		return nil, trace.Wrap(err)
@@ -119,6 +119,7 @@ func newCollector(env *localenv.LocalEnvironment) (*vacuum.Collector, error) {
 		ops.CreateClusterGarbageCollectOperationRequest{
 			AccountID:   cluster.AccountID,
 			ClusterName: cluster.Domain,
+			Force:       force,
 		},
 	)
 	if err != nil {
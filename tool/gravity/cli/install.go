@@ -384,13 +384,17 @@ func remove(env *localenv.LocalEnvironment, c removeConfig) error {
 		}
 	}
 
-	key, err := operator.CreateSiteShrinkOperation(context.TODO(),
-		ops.CreateSiteShrinkOperationRequest{
-			AccountID:  site.AccountID,
-			SiteDomain: site.Domain,
-			Servers:    []string{server.Hostname},
-			Force:      c.force,
-		})
+	var key *ops.SiteOperationKey
+	err = withSecondFactor(context.TODO(), c.secondFactor, func(ctx context.Context) (err error) {
+		key, err = operator.CreateSiteShrinkOperation(ctx,
+			ops.CreateSiteShrinkOperationRequest{
+				AccountID:  site.AccountID,
+				SiteDomain: site.Domain,
+				Servers:    []string{server.Hostname},
+				Force:      c.force,
+			})
+		return trace.Wrap(err)
+	})
 	if err != nil {
 		return trace.Wrap(err)
 	}
@@ -701,6 +705,18 @@ func join(env *localenv.LocalEnvironment, environ LocalEnvironmentFactory, confi
 	return trace.Wrap(err)
 }
 
+// replace joins this node to the cluster in place of the node specified in config
+func replace(env *localenv.LocalEnvironment, environ LocalEnvironmentFactory, config JoinConfig, confirmed bool) error {
+	if !config.FromService && !confirmed {
+		err := enforceConfirmation("Node %v will be removed from the cluster "+
+			"and replaced with this node. Proceed?", config.ReplaceNode)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+	}
+	return join(env, environ, config)
+}
+
 // TerminationHandler implements the default interrupt handler for the installer service
 func TerminationHandler(interrupt *signals.InterruptHandler, printer utils.Printer) {
 	for {
@@ -0,0 +1,223 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/gravitational/gravity/lib/constants"
+	"github.com/gravitational/gravity/lib/defaults"
+	"github.com/gravitational/gravity/lib/httplib"
+	kubeutils "github.com/gravitational/gravity/lib/kubernetes"
+	"github.com/gravitational/gravity/lib/localenv"
+	"github.com/gravitational/gravity/lib/ops"
+	"github.com/gravitational/gravity/lib/pack"
+	"github.com/gravitational/gravity/lib/storage"
+	"github.com/gravitational/gravity/lib/systeminfo"
+	"github.com/gravitational/gravity/lib/systemservice"
+	"github.com/gravitational/gravity/lib/utils"
+
+	"github.com/gravitational/trace"
+)
+
+// nodeMaintenanceConfig describes the request to put a node into maintenance mode
+type nodeMaintenanceConfig struct {
+	// node is the node's hostname or advertise IP address
+	node string
+	// timeout is the drain timeout
+	timeout time.Duration
+	// stopServices specifies whether to stop planet on the node after drain
+	stopServices bool
+	// confirmed suppresses the confirmation prompt
+	confirmed bool
+}
+
+// startNodeMaintenance cordons and drains the specified node and marks
+// it as being in maintenance mode.
+// If requested, it also stops the cluster services on the node which is
+// only supported when executed on the node itself
+func startNodeMaintenance(env *localenv.LocalEnvironment, config nodeMaintenanceConfig) error {
+	cluster, server, err := findMaintenanceNode(env, config.node)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if config.stopServices {
+		if err := checkLocalNode(*cluster, *server); err != nil {
+			return trace.Wrap(err, "services can only be stopped when "+
+				"executed on the node being put into maintenance mode")
+		}
+	}
+	if !config.confirmed {
+		err = enforceConfirmation("Node %v will be drained and put into maintenance mode. Proceed?",
+			server.Hostname)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+	}
+	client, _, err := httplib.GetClusterKubeClient(env.DNS.Addr())
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	ctx := context.TODO()
+	env.PrintStep("Marking node %v as in maintenance", server.Hostname)
+	err = kubeutils.UpdateLabels(ctx, client.CoreV1().Nodes(), server.KubeNodeID(),
+		map[string]string{defaults.MaintenanceLabel: constants.True})
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	env.PrintStep("Draining node %v", server.Hostname)
+	err = kubeutils.DrainWithTimeout(ctx, client, server.KubeNodeID(), config.timeout)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if config.stopServices {
+		env.PrintStep("Stopping cluster services")
+		err = stopPlanetService(env)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+	}
+	env.Printf("Node %v is in maintenance mode.\n", server.Hostname)
+	return nil
+}
+
+// stopNodeMaintenance brings the specified node out of maintenance mode.
+// If the command is executed on the node itself, cluster services
+// are started in case they have been stopped
+func stopNodeMaintenance(env *localenv.LocalEnvironment, node string) error {
+	// Cluster services need to be running on the local node
+	// before the cluster can be queried
+	local, err := isLocalNode(node)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if local {
+		err = ensurePlanetService(env)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+	}
+	_, server, err := findMaintenanceNode(env, node)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	client, _, err := httplib.GetClusterKubeClient(env.DNS.Addr())
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	ctx := context.TODO()
+	env.PrintStep("Uncordoning node %v", server.Hostname)
+	// Kubernetes API might not be available immediately after services start
+	err = utils.Retry(defaults.RetryInterval, defaults.RetryAttempts, func() error {
+		return kubeutils.SetUnschedulable(ctx, client.CoreV1().Nodes(), server.KubeNodeID(), false)
+	})
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	err = kubeutils.RemoveLabels(ctx, client.CoreV1().Nodes(), server.KubeNodeID(),
+		defaults.MaintenanceLabel)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	env.Printf("Node %v is out of maintenance mode.\n", server.Hostname)
+	return nil
+}
+
+// findMaintenanceNode returns the cluster server matching the specified node
+func findMaintenanceNode(env *localenv.LocalEnvironment, node string) (*ops.Site, *storage.Server, error) {
+	operator, err := env.SiteOperator()
+	if err != nil {
+		return nil, nil, trace.Wrap(err)
+	}
+	cluster, err := operator.GetLocalSite()
+	if err != nil {
+		return nil, nil, trace.Wrap(err)
+	}
+	server, err := findServer(*cluster, []string{node})
+	if err != nil {
+		return nil, nil, trace.Wrap(err)
+	}
+	return cluster, server, nil
+}
+
+// checkLocalNode returns an error if the specified server is not the node
+// this command is executed on
+func checkLocalNode(cluster ops.Site, server storage.Server) error {
+	local, err := findLocalServer(cluster)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if local.AdvertiseIP != server.AdvertiseIP {
+		return trace.BadParameter("%v is not the local node", server.Hostname)
+	}
+	return nil
+}
+
+// isLocalNode returns true if the specified node is the hostname
+// or one of the IP addresses of this node
+func isLocalNode(node string) (bool, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return false, trace.ConvertSystemError(err)
+	}
+	if node == hostname {
+		return true, nil
+	}
+	ifaces, err := systeminfo.NetworkInterfaces()
+	if err != nil {
+		return false, trace.Wrap(err)
+	}
+	for _, iface := range ifaces {
+		if iface.IPv4 == node {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func stopPlanetService(env *localenv.LocalEnvironment) error {
+	runtimePackage, err := pack.FindRuntimePackage(env.Packages)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	services, err := systemservice.New()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	return trace.Wrap(services.StopPackageService(*runtimePackage))
+}
+
+// ensurePlanetService starts the planet service unless it is already running
+func ensurePlanetService(env *localenv.LocalEnvironment) error {
+	runtimePackage, err := pack.FindRuntimePackage(env.Packages)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	services, err := systemservice.New()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	status, err := services.StatusPackageService(*runtimePackage)
+	if err == nil && status == systemservice.ServiceStatusActive {
+		return nil
+	}
+	env.PrintStep("Starting cluster services")
+	noBlock := false
+	return trace.Wrap(services.StartPackageService(*runtimePackage, noBlock))
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"os"
+
+	"github.com/gravitational/gravity/lib/localenv"
+	"github.com/gravitational/gravity/lib/overlay"
+
+	"github.com/gravitational/trace"
+	"github.com/sirupsen/logrus"
+)
+
+func overlayMount(config overlay.Config) error {
+	config.FieldLogger = logrus.NewEntry(logrus.New())
+	config.Out = os.Stderr
+	return overlay.Mount(config)
+}
+
+func overlayUnmount(config overlay.Config) error {
+	config.FieldLogger = logrus.NewEntry(logrus.New())
+	config.Out = os.Stderr
+	return overlay.Unmount(config)
+}
+
+// overlayMigrate stops the runtime container and moves docker storage on this node
+// from devicemapper to overlay2.
+// The runtime container is left stopped as it needs to be restarted with the
+// configuration for the new storage driver
+func overlayMigrate(env *localenv.LocalEnvironment, config overlay.Config) error {
+	env.PrintStep("Stopping cluster services")
+	if err := stopPlanetService(env); err != nil {
+		return trace.Wrap(err)
+	}
+	config.FieldLogger = logrus.NewEntry(logrus.New())
+	config.Out = os.Stderr
+	env.PrintStep("Migrating docker storage to overlay2")
+	if err := overlay.Migrate(config); err != nil {
+		return trace.Wrap(err)
+	}
+	env.PrintStep("Docker storage has been migrated. Cluster services need to be restarted " +
+		"with the overlay2 storage driver configuration")
+	return nil
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/gravitational/gravity/lib/defaults"
 	"github.com/gravitational/gravity/lib/loc"
 	"github.com/gravitational/gravity/lib/modules"
+	"github.com/gravitational/gravity/lib/overlay"
 	"github.com/gravitational/gravity/lib/schema"
 	"github.com/gravitational/gravity/lib/storage"
 	"github.com/gravitational/gravity/lib/utils"
@@ -98,6 +99,7 @@ func RegisterCommands(app *kingpin.Application) *Application {
 	g.InstallCmd.FromService = g.InstallCmd.Flag("from-service", "Run in service mode.").Hidden().Bool()
 	g.InstallCmd.Set = g.InstallCmd.Flag("set", "Set Helm chart values on the command line. Can be specified multiple times and/or as comma-separated values: key1=val1,key2=val2.").Strings()
 	g.InstallCmd.Values = g.InstallCmd.Flag("values", "Set Helm chart values from the provided YAML file. Can be specified multiple times.").Strings()
+	g.InstallCmd.FixHost = g.InstallCmd.Flag("fix-host", "Fix host environment problems found by pre-flight checks (e.g. disable conflicting services and swap).").Bool()
 
 	g.JoinCmd.CmdClause = g.Command("join", "Join the existing cluster or an on-going install operation.")
 	g.JoinCmd.PeerAddr = g.JoinCmd.Arg("peer-addrs", "One or several IP addresses of cluster nodes to join, as comma-separated values.").String()
@@ -111,6 +113,19 @@ func RegisterCommands(app *kingpin.Application) *Application {
 	g.JoinCmd.CloudProvider = g.JoinCmd.Flag("cloud-provider", "[DEPRECATED] This flag has no effect and will be removed in a future version.").String()
 	g.JoinCmd.OperationID = g.JoinCmd.Flag("operation-id", "ID of the operation that was created via UI.").Hidden().String()
 	g.JoinCmd.FromService = g.JoinCmd.Flag("from-service", "Run in service mode.").Hidden().Bool()
+	g.JoinCmd.FixHost = g.JoinCmd.Flag("fix-host", "Fix host environment problems found by pre-flight checks (e.g. disable conflicting services and swap).").Bool()
+
+	g.ReplaceCmd.CmdClause = g.Command("replace", "Replace an offline cluster node with this node.")
+	g.ReplaceCmd.Node = g.ReplaceCmd.Arg("node", "Node to replace: can be IP address or hostname.").Required().String()
+	g.ReplaceCmd.Token = g.ReplaceCmd.Flag("with", "Unique token to authorize this node to join the cluster.").Required().String()
+	g.ReplaceCmd.PeerAddr = g.ReplaceCmd.Flag("peer", "One or several IP addresses of cluster nodes to join, as comma-separated values.").Required().String()
+	g.ReplaceCmd.AdvertiseAddr = g.ReplaceCmd.Flag("advertise-addr", "IP address this node will advertise to other cluster nodes.").String()
+	g.ReplaceCmd.DockerDevice = g.ReplaceCmd.Flag("docker-device", "Docker device to use.").Hidden().String()
+	g.ReplaceCmd.SystemDevice = g.ReplaceCmd.Flag("system-device", "Device to use for system data directory.").Hidden().String()
+	g.ReplaceCmd.Mounts = configure.KeyValParam(g.ReplaceCmd.Flag("mount", "One or several mounts in form <mount-name>:<path>, e.g. data:/var/lib/data."))
+	g.ReplaceCmd.Confirm = g.ReplaceCmd.Flag("confirm", "Do not ask for confirmation.").Bool()
+	g.ReplaceCmd.FromService = g.ReplaceCmd.Flag("from-service", "Run in service mode.").Hidden().Bool()
+	g.ReplaceCmd.FixHost = g.ReplaceCmd.Flag("fix-host", "Fix host environment problems found by pre-flight checks (e.g. disable conflicting services and swap).").Bool()
 
 	g.AutoJoinCmd.CmdClause = g.Command("autojoin", "Use cloud provider data to join a node to existing cluster.")
 	g.AutoJoinCmd.ClusterName = g.AutoJoinCmd.Arg("cluster-name", "Cluster name used for discovery.").Required().String()
@@ -132,6 +147,19 @@ func RegisterCommands(app *kingpin.Application) *Application {
 		Required().String()
 	g.RemoveCmd.Force = g.RemoveCmd.Flag("force", "Force removal of an offline node.").Bool()
 	g.RemoveCmd.Confirm = g.RemoveCmd.Flag("confirm", "Do not ask for confirmation.").Bool()
+	g.RemoveCmd.SecondFactor = g.RemoveCmd.Flag("otp", "One-time token, if the cluster requires a second factor for removing nodes.").String()
+
+	g.NodeCmd.CmdClause = g.Command("node", "Manage cluster nodes.")
+	g.NodeMaintenanceCmd.CmdClause = g.NodeCmd.Command("maintenance", "Manage node maintenance mode.")
+	g.NodeMaintenanceStartCmd.CmdClause = g.NodeMaintenanceCmd.Command("start", "Drain a node and put it into maintenance mode.")
+	g.NodeMaintenanceStartCmd.Node = g.NodeMaintenanceStartCmd.Arg("node", "Node to put into maintenance mode: can be IP address or hostname.").
+		Required().String()
+	g.NodeMaintenanceStartCmd.Timeout = g.NodeMaintenanceStartCmd.Flag("timeout", "Node drain timeout.").Default(defaults.DrainTimeout.String()).Duration()
+	g.NodeMaintenanceStartCmd.StopServices = g.NodeMaintenanceStartCmd.Flag("stop-services", "Stop cluster services on the node after drain. Must be run on the node itself.").Bool()
+	g.NodeMaintenanceStartCmd.Confirm = g.NodeMaintenanceStartCmd.Flag("confirm", "Do not ask for confirmation.").Bool()
+	g.NodeMaintenanceStopCmd.CmdClause = g.NodeMaintenanceCmd.Command("stop", "Bring a node out of maintenance mode.")
+	g.NodeMaintenanceStopCmd.Node = g.NodeMaintenanceStopCmd.Arg("node", "Node to bring out of maintenance mode: can be IP address or hostname.").
+		Required().String()
 
 	g.ResumeCmd.CmdClause = g.Command("resume", "Resume the last aborted operation.")
 	g.ResumeCmd.OperationID = g.ResumeCmd.Flag("operation-id", "ID of the active operation. It not specified, the last operation will be used.").Hidden().String()
@@ -190,6 +218,7 @@ func RegisterCommands(app *kingpin.Application) *Application {
 	g.UpgradeCmd.SkipVersionCheck = g.UpgradeCmd.Flag("skip-version-check", "Bypass version compatibility check.").Hidden().Bool()
 	g.UpgradeCmd.Set = g.UpgradeCmd.Flag("set", "Set Helm chart values on the command line. Can be specified multiple times and/or as comma-separated values: key1=val1,key2=val2.").Strings()
 	g.UpgradeCmd.Values = g.UpgradeCmd.Flag("values", "Set Helm chart values from the provided YAML file. Can be specified multiple times.").Strings()
+	g.UpgradeCmd.SecondFactor = g.UpgradeCmd.Flag("otp", "One-time token, if the cluster requires a second factor for upgrades.").String()
 
 	g.UpdateUploadCmd.CmdClause = g.UpdateCmd.Command("upload", "Upload update package to locally running site").Hidden()
 	g.UpdateUploadCmd.OpsCenterURL = g.UpdateUploadCmd.Flag("ops-url", "Optional Gravity Hub URL to upload new packages to (defaults to local gravity site)").Default(defaults.GravityServiceURL).String()
@@ -205,6 +234,10 @@ func RegisterCommands(app *kingpin.Application) *Application {
 	g.UpdateSystemCmd.WithStatus = g.UpdateSystemCmd.Flag("with-status", "Verify the system status at the end of the operation").Bool()
 	g.UpdateSystemCmd.RuntimePackage = Locator(g.UpdateSystemCmd.Flag("runtime-package", "The name of the runtime package to update to").Required())
 
+	g.RotateCertsCmd.CmdClause = g.Command("rotate-certs", "Rotate cluster credentials without restarting services.")
+	g.RotateCertsCmd.RPC = g.RotateCertsCmd.Flag("rpc", "Rotate RPC agent credentials").Bool()
+	g.RotateCertsCmd.SecondFactor = g.RotateCertsCmd.Flag("otp", "One-time token, if the cluster requires a second factor for rotating credentials.").String()
+
 	g.StatusCmd.CmdClause = g.Command("status", "Display overall cluster status.")
 	g.StatusCmd.Token = g.StatusCmd.Flag("token", "Display only the cluster join token.").Bool()
 	g.StatusCmd.Tail = g.StatusCmd.Flag("tail", "Tail logs of the currently running operation until it completes.").Bool()
@@ -233,7 +266,10 @@ func RegisterCommands(app *kingpin.Application) *Application {
 	g.CheckCmd.CmdClause = g.Command("check", "Execute preflight checks")
 	g.CheckCmd.ManifestFile = g.CheckCmd.Arg("manifest", "Cluster image manifest file").Default(defaults.ManifestFileName).String()
 	g.CheckCmd.Profile = g.CheckCmd.Flag("profile", "Name of the node profile to check against").Short('p').String()
-	g.CheckCmd.AutoFix = g.CheckCmd.Flag("autofix", "Attempt to fix discovered problems on a best-effort basis").Bool()
+	g.CheckCmd.AutoFix = g.CheckCmd.Flag("fix", "Attempt to fix discovered problems on a best-effort basis").Bool()
+	// Deprecated alias for --fix
+	g.CheckCmd.Flag("autofix", "Attempt to fix discovered problems on a best-effort basis").Hidden().BoolVar(g.CheckCmd.AutoFix)
+	g.CheckCmd.DryRun = g.CheckCmd.Flag("dry-run", "Print the changes --fix would make to this node without applying them").Bool()
 	g.CheckCmd.ImagePath = g.CheckCmd.Flag("image-path", "Path to unpacked cluster image").String()
 	g.CheckCmd.Timeout = g.CheckCmd.Flag("timeout", "Checks execution timeout").Default(defaults.PreflightChecksTimeout.String()).Duration()
 
@@ -510,6 +546,48 @@ func RegisterCommands(app *kingpin.Application) *Application {
 			int(defaults.MaxUserResetTokenTTL/time.Hour))).
 		Default(fmt.Sprintf("%v", defaults.UserResetTokenTTL)).Duration()
 
+	// check user permissions
+	g.UsersCanICmd.CmdClause = g.UsersCmd.Command("can-i", "Check whether a user can perform an action and explain the decision.")
+	g.UsersCanICmd.Verb = g.UsersCanICmd.Arg("verb", "Action to check, e.g. read, update or an operation type like expand.").Required().String()
+	g.UsersCanICmd.Kind = g.UsersCanICmd.Arg("kind", "Resource kind to check, e.g. runtimeenvironment or operation.").Required().String()
+	g.UsersCanICmd.Name = g.UsersCanICmd.Flag("user", "User account name. Defaults to the current user.").String()
+
+	// unlock a user
+	g.UsersUnlockCmd.CmdClause = g.UsersCmd.Command("unlock", "Unlock a user locked after too many failed login attempts.")
+	g.UsersUnlockCmd.Name = g.UsersUnlockCmd.Arg("account", "User account name.").Required().String()
+
+	// temporary access to privileged roles
+	g.AccessCmd.CmdClause = g.Command("access", "Request and review temporary access to privileged roles.")
+
+	g.AccessRequestCmd.CmdClause = g.AccessCmd.Command("request", "Request temporary access to roles.")
+	g.AccessRequestCmd.Roles = g.AccessRequestCmd.Flag("roles", "List of roles to request.").Required().Strings()
+	g.AccessRequestCmd.Reason = g.AccessRequestCmd.Flag("reason", "Justification for the request.").String()
+	g.AccessRequestCmd.Duration = g.AccessRequestCmd.Flag("duration",
+		fmt.Sprintf("How long the access is requested for. Maximum is %v hours.",
+			int(defaults.MaxAccessRequestDuration/time.Hour))).
+		Default(defaults.AccessRequestDuration.String()).Duration()
+	g.AccessRequestCmd.User = g.AccessRequestCmd.Flag("user", "User account name to request access for. Defaults to the current user.").String()
+
+	g.AccessListCmd.CmdClause = g.AccessCmd.Command("ls", "List access requests.")
+
+	g.AccessApproveCmd.CmdClause = g.AccessCmd.Command("approve", "Approve an access request.")
+	g.AccessApproveCmd.ID = g.AccessApproveCmd.Arg("id", "Access request ID.").Required().String()
+
+	g.AccessDenyCmd.CmdClause = g.AccessCmd.Command("deny", "Deny an access request.")
+	g.AccessDenyCmd.ID = g.AccessDenyCmd.Arg("id", "Access request ID.").Required().String()
+
+	// tamper-evident audit log
+	g.AuditCmd.CmdClause = g.Command("audit", "Export and verify the cluster audit log.")
+
+	g.AuditExportCmd.CmdClause = g.AuditCmd.Command("export", "Export hash-chained audit log records.")
+	g.AuditExportCmd.Since = g.AuditExportCmd.Flag("since", "Export records created after this time, either RFC3339 timestamp or duration relative to now, e.g. 24h.").String()
+	g.AuditExportCmd.Until = g.AuditExportCmd.Flag("until", "Export records created before this time, either RFC3339 timestamp or duration relative to now.").String()
+	g.AuditExportCmd.Format = common.Format(g.AuditExportCmd.Flag("format", "Output format: jsonl or json.").Default(string(constants.EncodingJSONL)))
+	g.AuditExportCmd.Output = g.AuditExportCmd.Flag("output", "File to write the records to. Defaults to stdout.").Short('o').String()
+
+	g.AuditVerifyCmd.CmdClause = g.AuditCmd.Command("verify", "Verify the integrity of the audit log records.")
+	g.AuditVerifyCmd.File = g.AuditVerifyCmd.Flag("file", "Verify records exported to this file instead of the cluster audit log.").String()
+
 	// operations with api keys
 	g.APIKeyCmd.CmdClause = g.Command("apikey", "operations with api keys")
 
@@ -517,9 +595,12 @@ func RegisterCommands(app *kingpin.Application) *Application {
 	g.APIKeyCreateCmd.CmdClause = g.APIKeyCmd.Command("create", "create a new api key").Hidden()
 	g.APIKeyCreateCmd.Email = g.APIKeyCreateCmd.Flag("email", "email of the agent user to create an api key for").Required().String()
 	g.APIKeyCreateCmd.OpsCenterURL = g.APIKeyCreateCmd.Flag("ops-url", "remote Gravity Hub URL").Required().String()
+	g.APIKeyCreateCmd.Roles = g.APIKeyCreateCmd.Flag("role", "restrict the api key to the user role, can be repeated").Strings()
+	g.APIKeyCreateCmd.Rules = g.APIKeyCreateCmd.Flag("rule", "restrict the api key to the resource and verbs in the format resource:verb[,verb...], can be repeated").Strings()
+	g.APIKeyCreateCmd.Clusters = g.APIKeyCreateCmd.Flag("cluster", "restrict the api key to the cluster, can be repeated").Strings()
 
 	// view api keys for a user
-	g.APIKeyListCmd.CmdClause = g.APIKeyCmd.Command("list", "view user api keys").Hidden()
+	g.APIKeyListCmd.CmdClause = g.APIKeyCmd.Command("list", "view user api keys").Alias("ls").Hidden()
 	g.APIKeyListCmd.Email = g.APIKeyListCmd.Flag("email", "email of the user to view api keys for").Required().String()
 	g.APIKeyListCmd.OpsCenterURL = g.APIKeyListCmd.Flag("ops-url", "remote Gravity Hub URL").Required().String()
 
@@ -582,6 +663,13 @@ func RegisterCommands(app *kingpin.Application) *Application {
 	g.RPCAgentRunCmd.CmdClause = g.RPCAgentCmd.Command("run", "run RPC agent").Hidden()
 	g.RPCAgentRunCmd.Args = g.RPCAgentRunCmd.Arg("arg", "additional arguments").Strings()
 
+	g.RPCAgentStatusCmd.CmdClause = g.RPCAgentCmd.Command("status", "display the status of agents taking part in the operation")
+	g.RPCAgentStatusCmd.OperationID = g.RPCAgentStatusCmd.Flag("operation-id", "ID of the operation. Defaults to the active operation").String()
+
+	g.RPCAgentEvictCmd.CmdClause = g.RPCAgentCmd.Command("evict", "disconnect an unresponsive agent from the operation without aborting it")
+	g.RPCAgentEvictCmd.Addr = g.RPCAgentEvictCmd.Arg("addr", "address of the agent as displayed by 'gravity agent status'").Required().String()
+	g.RPCAgentEvictCmd.OperationID = g.RPCAgentEvictCmd.Flag("operation-id", "ID of the operation. Defaults to the active operation").String()
+
 	g.SystemCmd.CmdClause = g.Command("system", "operations on system components")
 
 	g.SystemRotateCertsCmd.CmdClause = g.SystemCmd.Command("rotate-certs", "Renew cluster certificates on a node").Hidden()
@@ -666,6 +754,21 @@ func RegisterCommands(app *kingpin.Application) *Application {
 	g.SystemDevicemapperUnmountCmd.CmdClause = g.SystemDevicemapperCmd.Command("unmount", "remove devicemapper environment").Hidden()
 	g.SystemDevicemapperSystemDirCmd.CmdClause = g.SystemDevicemapperCmd.Command("system-dir", "query the location of the lvm system directory").Hidden()
 
+	// manage docker overlay2 device
+	g.SystemOverlayCmd.CmdClause = g.SystemCmd.Command("overlay2", "manage docker overlay2 device").Hidden()
+	g.SystemOverlayMountCmd.CmdClause = g.SystemOverlayCmd.Command("mount", "format and mount the device for overlay2").Hidden()
+	g.SystemOverlayMountCmd.Disk = g.SystemOverlayMountCmd.Arg("disk", "disk/partition to use for docker storage").Required().String()
+	g.SystemOverlayMountCmd.Filesystem = g.SystemOverlayMountCmd.Flag("filesystem", "filesystem to format the disk with: xfs or ext4").Default(overlay.FilesystemXFS).Enum(overlay.FilesystemXFS, overlay.FilesystemExt4)
+	g.SystemOverlayMountCmd.Quota = g.SystemOverlayMountCmd.Flag("quota", "enable project quotas on the filesystem").Bool()
+	g.SystemOverlayMountCmd.Fstab = g.SystemOverlayMountCmd.Flag("fstab", "persist the mount in /etc/fstab instead of a systemd mount unit").Bool()
+	g.SystemOverlayUnmountCmd.CmdClause = g.SystemOverlayCmd.Command("unmount", "unmount the overlay2 device").Hidden()
+	g.SystemOverlayUnmountCmd.Disk = g.SystemOverlayUnmountCmd.Arg("disk", "disk/partition to wipe filesystem signatures from").String()
+	g.SystemOverlayMigrateCmd.CmdClause = g.SystemOverlayCmd.Command("migrate", "migrate docker storage on this node from devicemapper to overlay2").Hidden()
+	g.SystemOverlayMigrateCmd.Disk = g.SystemOverlayMigrateCmd.Arg("disk", "disk/partition to use for docker storage. Defaults to the devicemapper physical volume").String()
+	g.SystemOverlayMigrateCmd.Filesystem = g.SystemOverlayMigrateCmd.Flag("filesystem", "filesystem to format the disk with: xfs or ext4").Default(overlay.FilesystemXFS).Enum(overlay.FilesystemXFS, overlay.FilesystemExt4)
+	g.SystemOverlayMigrateCmd.Quota = g.SystemOverlayMigrateCmd.Flag("quota", "enable project quotas on the filesystem").Bool()
+	g.SystemOverlayMigrateCmd.Fstab = g.SystemOverlayMigrateCmd.Flag("fstab", "persist the mount in /etc/fstab instead of a systemd mount unit").Bool()
+
 	// journal helpers
 	g.SystemExportRuntimeJournalCmd.CmdClause = g.SystemCmd.Command("export-runtime-journal", "Export runtime journal logs to a file").Hidden()
 	g.SystemExportRuntimeJournalCmd.OutputFile = g.SystemExportRuntimeJournalCmd.Flag("output", "Name of resulting tarball. Output to stdout if unspecified").String()
@@ -676,6 +779,7 @@ func RegisterCommands(app *kingpin.Application) *Application {
 	g.GarbageCollectCmd.CmdClause = g.Command("gc", "Prune cluster resources")
 	g.GarbageCollectCmd.Manual = g.GarbageCollectCmd.Flag("manual", "Do not start the operation automatically").Short('m').Bool()
 	g.GarbageCollectCmd.Confirmed = g.GarbageCollectCmd.Flag("confirm", "Confirm to remove unrelated docker images").Short('c').Bool()
+	g.GarbageCollectCmd.Force = g.GarbageCollectCmd.Flag("force", "Run garbage collection outside of the cluster maintenance window").Bool()
 
 	// system clean up tasks
 	systemGCCmd := g.SystemCmd.Command("gc", "Run system clean up tasks")
@@ -726,6 +830,7 @@ func RegisterCommands(app *kingpin.Application) *Application {
 	g.ResourceCreateCmd.User = g.ResourceCreateCmd.Flag("user", "User to create the resource for. Defaults to the currently logged in user.").String()
 	g.ResourceCreateCmd.Manual = g.ResourceCreateCmd.Flag("manual", "Manually execute operation phases for resource which trigger an operation.").Short('m').Bool()
 	g.ResourceCreateCmd.Confirmed = g.ResourceCreateCmd.Flag("confirm", "Do not ask for confirmation.").Bool()
+	g.ResourceCreateCmd.SecondFactor = g.ResourceCreateCmd.Flag("otp", "One-time token, if the cluster requires a second factor for the change.").String()
 
 	// remove one or many resources
 	g.ResourceRemoveCmd.CmdClause = g.ResourceCmd.Command("rm", fmt.Sprintf("Remove a configuration resource, e.g. gravity resource rm oidc google. Supported resources are: %v.", modules.GetResources().SupportedResourcesToRemove()))
@@ -736,6 +841,7 @@ func RegisterCommands(app *kingpin.Application) *Application {
 	g.ResourceRemoveCmd.User = g.ResourceRemoveCmd.Flag("user", "User to remove the resource for. Defaults to the currently logged in user.").String()
 	g.ResourceRemoveCmd.Manual = g.ResourceRemoveCmd.Flag("manual", "Manually execute operation phases for resources which trigger an operation.").Short('m').Bool()
 	g.ResourceRemoveCmd.Confirmed = g.ResourceRemoveCmd.Flag("confirm", "Do not ask for confirmation.").Bool()
+	g.ResourceRemoveCmd.SecondFactor = g.ResourceRemoveCmd.Flag("otp", "One-time token, if the cluster requires a second factor for the change.").String()
 
 	// get resources returns resources
 	g.ResourceGetCmd.CmdClause = g.ResourceCmd.Command("get", fmt.Sprintf("Get configuration resources, e.g. gravity get oidc. Supported resources are: %v.",
@@ -37,7 +37,7 @@ import (
 // manual controls whether the operation is created in manual mode if resource creation is implemented
 // as a cluster operation.
 // confirmed specifies if the user has explicitly approved the operation
-func createResource(env *localenv.LocalEnvironment, factory LocalEnvironmentFactory, filename string, upsert bool, user string, manual, confirmed bool) error {
+func createResource(env *localenv.LocalEnvironment, factory LocalEnvironmentFactory, filename string, upsert bool, user string, manual, confirmed bool, secondFactor string) error {
 	operator, err := env.SiteOperator()
 	if err != nil {
 		return trace.Wrap(err)
@@ -70,7 +70,9 @@ func createResource(env *localenv.LocalEnvironment, factory LocalEnvironmentFact
 			Manual:    manual,
 			Confirmed: confirmed,
 		}
-		return trace.Wrap(control.Create(context.TODO(), bytes.NewReader(resource.Raw), req))
+		return withSecondFactor(context.TODO(), secondFactor, func(ctx context.Context) error {
+			return trace.Wrap(control.Create(ctx, bytes.NewReader(resource.Raw), req))
+		})
 	})
 	return trace.Wrap(err)
 }
@@ -83,6 +85,7 @@ func removeResource(
 	force bool,
 	user string,
 	manual, confirmed bool,
+	secondFactor string,
 ) error {
 	operator, err := env.SiteOperator()
 	if err != nil {
@@ -111,7 +114,9 @@ func removeResource(
 		Manual:    manual,
 		Confirmed: confirmed,
 	}
-	err = resources.NewControl(gravityResources).Remove(context.TODO(), req)
+	err = withSecondFactor(context.TODO(), secondFactor, func(ctx context.Context) error {
+		return trace.Wrap(resources.NewControl(gravityResources).Remove(ctx, req))
+	})
 	return trace.Wrap(err)
 
 }
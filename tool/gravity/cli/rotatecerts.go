@@ -0,0 +1,124 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"context"
+
+	"github.com/gravitational/gravity/lib/constants"
+	"github.com/gravitational/gravity/lib/defaults"
+	"github.com/gravitational/gravity/lib/loc"
+	"github.com/gravitational/gravity/lib/localenv"
+	"github.com/gravitational/gravity/lib/ops"
+	"github.com/gravitational/gravity/lib/rpc"
+	"github.com/gravitational/gravity/lib/utils"
+
+	"github.com/gravitational/trace"
+)
+
+func rotateCerts(env *localenv.LocalEnvironment, rpcCreds bool, secondFactor string) error {
+	if !rpcCreds {
+		return trace.BadParameter("specify --rpc to rotate RPC agent credentials. " +
+			"To renew cluster certificates on a node, use 'gravity system rotate-certs'")
+	}
+	return rotateRPCCredentials(env, secondFactor)
+}
+
+// rotateRPCCredentials replaces the cluster RPC credentials and pushes them to the
+// agents on all cluster nodes.
+// Running processes reload the credentials without restart.
+// The credentials package is updated via the cluster controller so the update
+// is subject to the cluster access control and second factor policy
+func rotateRPCCredentials(env *localenv.LocalEnvironment, secondFactor string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaults.AgentDeployTimeout)
+	defer cancel()
+
+	clusterEnv, err := env.NewClusterEnvironment()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	cluster, err := clusterEnv.Operator.GetLocalSite()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	if cluster.State != ops.SiteStateActive {
+		return trace.BadParameter("cluster is %v, RPC credentials can only be rotated "+
+			"on an active cluster", cluster.State)
+	}
+
+	teleportClient, err := env.TeleportClient(constants.Localhost)
+	if err != nil {
+		return trace.Wrap(err, "failed to create a teleport client")
+	}
+
+	proxy, err := teleportClient.ConnectToProxy(ctx)
+	if err != nil {
+		return trace.Wrap(err, "failed to connect to teleport proxy")
+	}
+
+	servers, err := verifyCluster(ctx, cluster.ClusterState, proxy)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	// The cluster controller updates the credentials package
+	operator, err := env.SiteOperator()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	env.PrintStep("Rotating RPC credentials in %v", loc.RPCSecrets)
+	err = rpc.RotatePackageCredentials(ctx, rpc.RotateCredentialsRequest{
+		Packages:        clusterEnv.ClusterPackages,
+		SecretsPackage:  loc.RPCSecrets,
+		CommonName:      defaults.SystemAccountOrg,
+		LongLivedClient: true,
+		Servers:         servers,
+		Proxy:           proxy,
+		Updater: &clusterCredentialsUpdater{
+			operator:     operator,
+			clusterKey:   cluster.Key(),
+			secondFactor: secondFactor,
+		},
+	})
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	env.PrintStep("RPC credentials rotated")
+	return nil
+}
+
+// UpdateCredentials replaces the RPC credentials via the cluster operator.
+// The user is prompted for a second factor if the cluster requires one
+func (r *clusterCredentialsUpdater) UpdateCredentials(ctx context.Context, archive utils.TLSArchive) error {
+	return withSecondFactor(ctx, r.secondFactor, func(ctx context.Context) error {
+		return r.operator.UpdateRPCCredentials(ctx, ops.UpdateRPCCredentialsRequest{
+			ClusterKey:  r.clusterKey,
+			Credentials: archive,
+		})
+	})
+}
+
+// clusterCredentialsUpdater updates the RPC credentials package via the cluster operator
+type clusterCredentialsUpdater struct {
+	operator     ops.Operator
+	clusterKey   ops.SiteKey
+	secondFactor string
+}
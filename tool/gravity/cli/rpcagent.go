@@ -24,6 +24,7 @@ import (
 	"os"
 	"strings"
 	"sync"
+	"text/tabwriter"
 	"time"
 
 	"github.com/gravitational/gravity/lib/constants"
@@ -31,6 +32,7 @@ import (
 	"github.com/gravitational/gravity/lib/fsm"
 	"github.com/gravitational/gravity/lib/loc"
 	"github.com/gravitational/gravity/lib/localenv"
+	"github.com/gravitational/gravity/lib/ops"
 	"github.com/gravitational/gravity/lib/pack"
 	"github.com/gravitational/gravity/lib/rpc"
 	pb "github.com/gravitational/gravity/lib/rpc/proto"
@@ -41,6 +43,7 @@ import (
 	"github.com/gravitational/gravity/lib/utils"
 
 	"github.com/cenkalti/backoff"
+	"github.com/dustin/go-humanize"
 	teleclient "github.com/gravitational/teleport/lib/client"
 	"github.com/gravitational/trace"
 	"github.com/gravitational/version"
@@ -63,7 +66,9 @@ func rpcAgentInstall(env *localenv.LocalEnvironment, args []string) error {
 
 // rpcAgentRun runs a local agent executing the function specified with optional args
 func rpcAgentRun(localEnv, updateEnv *localenv.LocalEnvironment, args []string) error {
-	agent, err := newAgent()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	agent, err := newAgent(ctx)
 	if err != nil {
 		return trace.Wrap(err)
 	}
@@ -117,7 +122,9 @@ func runAgentFunction(
 	return trace.Wrap(err)
 }
 
-func newAgent() (rpcserver.Server, error) {
+// newAgent creates a new RPC agent with credentials from the agent secrets directory.
+// The credentials are reloaded whenever they are updated until the specified context expires
+func newAgent(ctx context.Context) (rpcserver.Server, error) {
 	secretsDir, err := fsm.AgentSecretsDir()
 	if err != nil {
 		return nil, trace.Wrap(err)
@@ -127,6 +134,8 @@ func newAgent() (rpcserver.Server, error) {
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
+	reloadableServerCreds := rpc.NewReloadableCredentials(serverCreds)
+	reloadableClientCreds := rpc.NewReloadableCredentials(clientCreds)
 
 	serverAddr := fmt.Sprintf(":%v", defaults.GravityRPCAgentPort)
 	listener, err := net.Listen("tcp4", serverAddr)
@@ -136,8 +145,8 @@ func newAgent() (rpcserver.Server, error) {
 
 	config := rpcserver.Config{
 		Credentials: rpcserver.Credentials{
-			Server: serverCreds,
-			Client: clientCreds,
+			Server: reloadableServerCreds,
+			Client: reloadableClientCreds,
 		},
 		Listener: listener,
 	}
@@ -145,6 +154,11 @@ func newAgent() (rpcserver.Server, error) {
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
+	go rpc.WatchCredentials(ctx, rpc.WatchConfig{
+		Source: rpc.NewDirectorySource(secretsDir),
+		Server: reloadableServerCreds,
+		Client: reloadableClientCreds,
+	})
 	log.Infof("Starting RPC agent on %v.", listener.Addr().String())
 
 	return server, nil
@@ -404,3 +418,79 @@ type deployAgentsRequest struct {
 	leader       *storage.Server
 	nodeParams   string
 }
+
+// rpcAgentStatus displays the status of agents taking part in the specified
+// operation or the active cluster operation if operationID is unspecified
+func rpcAgentStatus(env *localenv.LocalEnvironment, operationID string) error {
+	operator, key, err := getAgentOperation(env, operationID)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	agents, err := operator.GetOperationAgents(*key)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if len(agents) == 0 {
+		env.Printf("No agents connected for operation %v.\n", key.OperationID)
+		return nil
+	}
+	w := new(tabwriter.Writer)
+	w.Init(os.Stdout, 0, 8, 1, '\t', 0)
+	fmt.Fprintf(w, "Address\tHostname\tVersion\tStatus\tLast heartbeat\tRTT\tReconnects\n")
+	fmt.Fprintf(w, "-------\t--------\t-------\t------\t--------------\t---\t----------\n")
+	now := time.Now()
+	for _, agent := range agents {
+		status := "disconnected"
+		if agent.Connected {
+			status = "connected"
+		}
+		heartbeat := "never"
+		if !agent.LastHeartbeat.IsZero() {
+			heartbeat = humanize.RelTime(agent.LastHeartbeat, now, "ago", "")
+		}
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t%v\t%v\n", agent.Addr, agent.Hostname,
+			agent.Version, status, heartbeat, agent.RTT, agent.Reconnects)
+	}
+	return trace.Wrap(w.Flush())
+}
+
+// rpcAgentEvict disconnects the agent specified with addr from the operation
+// without aborting the operation
+func rpcAgentEvict(env *localenv.LocalEnvironment, addr, operationID string) error {
+	operator, key, err := getAgentOperation(env, operationID)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if err := operator.EvictOperationAgent(*key, addr); err != nil {
+		return trace.Wrap(err)
+	}
+	env.PrintStep("Evicted agent %v from operation %v", addr, key.OperationID)
+	return nil
+}
+
+// getAgentOperation returns the cluster operator and the key of the operation
+// specified with operationID or the active cluster operation
+func getAgentOperation(env *localenv.LocalEnvironment, operationID string) (ops.Operator, *ops.SiteOperationKey, error) {
+	operator, err := env.SiteOperator()
+	if err != nil {
+		return nil, nil, trace.Wrap(err)
+	}
+	cluster, err := operator.GetLocalSite()
+	if err != nil {
+		return nil, nil, trace.Wrap(err)
+	}
+	if operationID != "" {
+		key := ops.SiteOperationKey{
+			AccountID:   cluster.AccountID,
+			SiteDomain:  cluster.Domain,
+			OperationID: operationID,
+		}
+		return operator, &key, nil
+	}
+	operations, err := ops.GetActiveOperations(cluster.Key(), operator)
+	if err != nil {
+		return nil, nil, trace.Wrap(err)
+	}
+	key := operations[0].Key()
+	return operator, &key, nil
+}
@@ -33,6 +33,7 @@ import (
 	"github.com/gravitational/gravity/lib/fsm"
 	"github.com/gravitational/gravity/lib/httplib"
 	"github.com/gravitational/gravity/lib/localenv"
+	"github.com/gravitational/gravity/lib/overlay"
 	"github.com/gravitational/gravity/lib/process"
 	"github.com/gravitational/gravity/lib/schema"
 	"github.com/gravitational/gravity/lib/state"
@@ -113,12 +114,15 @@ func InitAndCheck(g *Application, cmd string) error {
 		g.WizardCmd.FullCommand(),
 		g.JoinCmd.FullCommand(),
 		g.AutoJoinCmd.FullCommand(),
+		g.ReplaceCmd.FullCommand(),
 		g.UpdateTriggerCmd.FullCommand(),
 		g.UpdatePlanInitCmd.FullCommand(),
 		g.UpgradeCmd.FullCommand(),
 		g.RPCAgentRunCmd.FullCommand(),
 		g.LeaveCmd.FullCommand(),
 		g.RemoveCmd.FullCommand(),
+		g.NodeMaintenanceStartCmd.FullCommand(),
+		g.NodeMaintenanceStopCmd.FullCommand(),
 		g.ResumeCmd.FullCommand(),
 		g.PlanResumeCmd.FullCommand(),
 		g.PlanExecuteCmd.FullCommand(),
@@ -151,7 +155,9 @@ func InitAndCheck(g *Application, cmd string) error {
 	switch cmd {
 	case g.UpdateCompleteCmd.FullCommand(),
 		g.UpdateTriggerCmd.FullCommand(),
-		g.RemoveCmd.FullCommand():
+		g.RemoveCmd.FullCommand(),
+		g.NodeMaintenanceStartCmd.FullCommand(),
+		g.NodeMaintenanceStopCmd.FullCommand():
 		if err := checkRunningInGravity(g); err != nil {
 			return trace.Wrap(err)
 		}
@@ -182,8 +188,11 @@ func InitAndCheck(g *Application, cmd string) error {
 		g.InstallCmd.FullCommand(),
 		g.JoinCmd.FullCommand(),
 		g.AutoJoinCmd.FullCommand(),
+		g.ReplaceCmd.FullCommand(),
 		g.LeaveCmd.FullCommand(),
 		g.RemoveCmd.FullCommand(),
+		g.NodeMaintenanceStartCmd.FullCommand(),
+		g.NodeMaintenanceStopCmd.FullCommand(),
 		g.SystemDevicemapperMountCmd.FullCommand(),
 		g.SystemDevicemapperUnmountCmd.FullCommand(),
 		g.BackupCmd.FullCommand(),
@@ -237,7 +246,7 @@ func Execute(g *Application, cmd string, extraArgs []string) (err error) {
 
 	var localEnv *localenv.LocalEnvironment
 	switch cmd {
-	case g.InstallCmd.FullCommand(), g.JoinCmd.FullCommand():
+	case g.InstallCmd.FullCommand(), g.JoinCmd.FullCommand(), g.ReplaceCmd.FullCommand():
 		if *g.StateDir != "" {
 			if err := state.SetStateDir(*g.StateDir); err != nil {
 				return trace.Wrap(err)
@@ -302,6 +311,8 @@ func Execute(g *Application, cmd string, extraArgs []string) (err error) {
 		return startInstall(localEnv, *config)
 	case g.JoinCmd.FullCommand():
 		return join(localEnv, g, NewJoinConfig(g))
+	case g.ReplaceCmd.FullCommand():
+		return replace(localEnv, g, NewReplaceConfig(g), *g.ReplaceCmd.Confirm)
 	case g.AutoJoinCmd.FullCommand():
 		return autojoin(localEnv, g, autojoinConfig{
 			systemLogFile: *g.SystemLogFile,
@@ -415,10 +426,22 @@ func Execute(g *Application, cmd string, extraArgs []string) (err error) {
 		})
 	case g.RemoveCmd.FullCommand():
 		return remove(localEnv, removeConfig{
-			server:    *g.RemoveCmd.Node,
-			force:     *g.RemoveCmd.Force,
-			confirmed: *g.RemoveCmd.Confirm,
+			server:       *g.RemoveCmd.Node,
+			force:        *g.RemoveCmd.Force,
+			confirmed:    *g.RemoveCmd.Confirm,
+			secondFactor: *g.RemoveCmd.SecondFactor,
+		})
+	case g.NodeMaintenanceStartCmd.FullCommand():
+		return startNodeMaintenance(localEnv, nodeMaintenanceConfig{
+			node:         *g.NodeMaintenanceStartCmd.Node,
+			timeout:      *g.NodeMaintenanceStartCmd.Timeout,
+			stopServices: *g.NodeMaintenanceStartCmd.StopServices,
+			confirmed:    *g.NodeMaintenanceStartCmd.Confirm,
 		})
+	case g.NodeMaintenanceStopCmd.FullCommand():
+		return stopNodeMaintenance(localEnv, *g.NodeMaintenanceStopCmd.Node)
+	case g.RotateCertsCmd.FullCommand():
+		return rotateCerts(localEnv, *g.RotateCertsCmd.RPC, *g.RotateCertsCmd.SecondFactor)
 	case g.StatusCmd.FullCommand():
 		printOptions := printOptions{
 			token:       *g.StatusCmd.Token,
@@ -668,7 +691,10 @@ func Execute(g *Application, cmd string, extraArgs []string) (err error) {
 	case g.APIKeyCreateCmd.FullCommand():
 		return createAPIKey(localEnv,
 			*g.APIKeyCreateCmd.OpsCenterURL,
-			*g.APIKeyCreateCmd.Email)
+			*g.APIKeyCreateCmd.Email,
+			*g.APIKeyCreateCmd.Roles,
+			*g.APIKeyCreateCmd.Rules,
+			*g.APIKeyCreateCmd.Clusters)
 	case g.APIKeyListCmd.FullCommand():
 		return getAPIKeys(localEnv,
 			*g.APIKeyListCmd.OpsCenterURL,
@@ -798,7 +824,7 @@ func Execute(g *Application, cmd string, extraArgs []string) (err error) {
 	case g.SystemStreamRuntimeJournalCmd.FullCommand():
 		return streamRuntimeJournal(localEnv)
 	case g.GarbageCollectCmd.FullCommand():
-		return garbageCollect(localEnv, *g.GarbageCollectCmd.Manual, *g.GarbageCollectCmd.Confirmed)
+		return garbageCollect(localEnv, *g.GarbageCollectCmd.Manual, *g.GarbageCollectCmd.Confirmed, *g.GarbageCollectCmd.Force)
 	case g.SystemGCJournalCmd.FullCommand():
 		return removeUnusedJournalFiles(localEnv,
 			*g.SystemGCJournalCmd.MachineIDFile,
@@ -829,6 +855,24 @@ func Execute(g *Application, cmd string, extraArgs []string) (err error) {
 		return devicemapperUnmount()
 	case g.SystemDevicemapperSystemDirCmd.FullCommand():
 		return devicemapperQuerySystemDirectory()
+	case g.SystemOverlayMountCmd.FullCommand():
+		return overlayMount(overlay.Config{
+			Disk:       *g.SystemOverlayMountCmd.Disk,
+			Filesystem: *g.SystemOverlayMountCmd.Filesystem,
+			Quota:      *g.SystemOverlayMountCmd.Quota,
+			Fstab:      *g.SystemOverlayMountCmd.Fstab,
+		})
+	case g.SystemOverlayUnmountCmd.FullCommand():
+		return overlayUnmount(overlay.Config{
+			Disk: *g.SystemOverlayUnmountCmd.Disk,
+		})
+	case g.SystemOverlayMigrateCmd.FullCommand():
+		return overlayMigrate(localEnv, overlay.Config{
+			Disk:       *g.SystemOverlayMigrateCmd.Disk,
+			Filesystem: *g.SystemOverlayMigrateCmd.Filesystem,
+			Quota:      *g.SystemOverlayMigrateCmd.Quota,
+			Fstab:      *g.SystemOverlayMigrateCmd.Fstab,
+		})
 	case g.UsersInviteCmd.FullCommand():
 		return inviteUser(localEnv,
 			*g.UsersInviteCmd.Name,
@@ -838,13 +882,41 @@ func Execute(g *Application, cmd string, extraArgs []string) (err error) {
 		return resetUser(localEnv,
 			*g.UsersResetCmd.Name,
 			*g.UsersResetCmd.TTL)
+	case g.AccessRequestCmd.FullCommand():
+		return requestAccess(localEnv,
+			*g.AccessRequestCmd.User,
+			*g.AccessRequestCmd.Roles,
+			*g.AccessRequestCmd.Reason,
+			*g.AccessRequestCmd.Duration)
+	case g.AccessListCmd.FullCommand():
+		return listAccessRequests(localEnv)
+	case g.AccessApproveCmd.FullCommand():
+		return reviewAccessRequest(localEnv, *g.AccessApproveCmd.ID, true)
+	case g.AccessDenyCmd.FullCommand():
+		return reviewAccessRequest(localEnv, *g.AccessDenyCmd.ID, false)
+	case g.AuditExportCmd.FullCommand():
+		return exportAuditRecords(localEnv,
+			*g.AuditExportCmd.Since,
+			*g.AuditExportCmd.Until,
+			*g.AuditExportCmd.Format,
+			*g.AuditExportCmd.Output)
+	case g.AuditVerifyCmd.FullCommand():
+		return verifyAuditRecords(localEnv, *g.AuditVerifyCmd.File)
+	case g.UsersCanICmd.FullCommand():
+		return checkUserAccess(localEnv,
+			*g.UsersCanICmd.Name,
+			*g.UsersCanICmd.Verb,
+			*g.UsersCanICmd.Kind)
+	case g.UsersUnlockCmd.FullCommand():
+		return unlockUser(localEnv, *g.UsersUnlockCmd.Name)
 	case g.ResourceCreateCmd.FullCommand():
 		return createResource(localEnv, g,
 			*g.ResourceCreateCmd.Filename,
 			*g.ResourceCreateCmd.Upsert,
 			*g.ResourceCreateCmd.User,
 			*g.ResourceCreateCmd.Manual,
-			*g.ResourceCreateCmd.Confirmed)
+			*g.ResourceCreateCmd.Confirmed,
+			*g.ResourceCreateCmd.SecondFactor)
 	case g.ResourceRemoveCmd.FullCommand():
 		return removeResource(localEnv, g,
 			*g.ResourceRemoveCmd.Kind,
@@ -852,7 +924,8 @@ func Execute(g *Application, cmd string, extraArgs []string) (err error) {
 			*g.ResourceRemoveCmd.Force,
 			*g.ResourceRemoveCmd.User,
 			*g.ResourceRemoveCmd.Manual,
-			*g.ResourceRemoveCmd.Confirmed)
+			*g.ResourceRemoveCmd.Confirmed,
+			*g.ResourceRemoveCmd.SecondFactor)
 	case g.ResourceGetCmd.FullCommand():
 		return getResources(localEnv,
 			*g.ResourceGetCmd.Kind,
@@ -876,12 +949,17 @@ func Execute(g *Application, cmd string, extraArgs []string) (err error) {
 			*g.RPCAgentRunCmd.Args)
 	case g.RPCAgentShutdownCmd.FullCommand():
 		return rpcAgentShutdown(localEnv)
+	case g.RPCAgentStatusCmd.FullCommand():
+		return rpcAgentStatus(localEnv, *g.RPCAgentStatusCmd.OperationID)
+	case g.RPCAgentEvictCmd.FullCommand():
+		return rpcAgentEvict(localEnv, *g.RPCAgentEvictCmd.Addr, *g.RPCAgentEvictCmd.OperationID)
 	case g.CheckCmd.FullCommand():
 		return executePreflightChecks(localEnv, preflightChecksConfig{
 			manifestPath: *g.CheckCmd.ManifestFile,
 			imagePath:    *g.CheckCmd.ImagePath,
 			profileName:  *g.CheckCmd.Profile,
 			autoFix:      *g.CheckCmd.AutoFix,
+			dryRun:       *g.CheckCmd.DryRun,
 			timeout:      *g.CheckCmd.Timeout,
 		})
 	case g.TopCmd.FullCommand():
@@ -0,0 +1,45 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"context"
+
+	"github.com/gravitational/gravity/lib/ops"
+
+	"github.com/gravitational/trace"
+)
+
+// withSecondFactor invokes fn with the context that carries the provided
+// second factor token.
+//
+// If the cluster requires a second factor for the action and no token
+// has been provided, the user is prompted for one and fn is invoked again
+func withSecondFactor(ctx context.Context, token string, fn func(context.Context) error) error {
+	if token != "" {
+		return trace.Wrap(fn(ops.WithSecondFactor(ctx, token)))
+	}
+	err := fn(ctx)
+	if !ops.IsSecondFactorRequiredError(err) {
+		return trace.Wrap(err)
+	}
+	token, err = readInput("This action requires a second factor. Enter the one-time token from your authenticator app")
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	return trace.Wrap(fn(ops.WithSecondFactor(ctx, token)))
+}
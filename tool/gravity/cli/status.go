@@ -383,6 +383,15 @@ func printNodeStatus(node statusapi.ClusterServer, w io.Writer) {
 			fmt.Fprintf(w, "            [%v]\t%v\n", constants.WarnMark, color.New(color.FgYellow).SprintFunc()(probe))
 		}
 	}
+	if node.Maintenance {
+		fmt.Fprintf(w, "            Maintenance:\t%v\n", color.YellowString("enabled"))
+	}
+	if len(node.FailedChecks) != 0 {
+		fmt.Fprintf(w, "            Failed checks:\n")
+		for _, check := range node.FailedChecks {
+			fmt.Fprintf(w, "            [%v]\t%v\n", constants.FailureMark, color.New(color.FgRed).SprintFunc()(check))
+		}
+	}
 }
 
 func printPrometheusAlerts(alerts []*models.GettableAlert, w io.Writer) {
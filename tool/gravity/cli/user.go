@@ -20,12 +20,17 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 	"text/tabwriter"
 	"time"
 
+	"github.com/gravitational/gravity/lib/constants"
 	"github.com/gravitational/gravity/lib/localenv"
 	"github.com/gravitational/gravity/lib/ops"
+	"github.com/gravitational/gravity/lib/storage"
 	"github.com/gravitational/gravity/lib/utils"
+
+	teleservices "github.com/gravitational/teleport/lib/services"
 	"github.com/gravitational/trace"
 )
 
@@ -63,14 +68,22 @@ func deleteUser(localEnv *localenv.LocalEnvironment, opsCenterURL, username stri
 	return nil
 }
 
-func createAPIKey(localEnv *localenv.LocalEnvironment, opsCenterURL, username string) error {
+func createAPIKey(localEnv *localenv.LocalEnvironment, opsCenterURL, username string, roles, rules, clusters []string) error {
 	operator, err := localEnv.OperatorService(opsCenterURL)
 	if err != nil {
 		return trace.Wrap(err)
 	}
 
+	keyRules, err := parseAPIKeyRules(rules)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
 	key, err := operator.CreateAPIKey(context.Background(), ops.NewAPIKeyRequest{
 		UserEmail: username,
+		Roles:     roles,
+		Rules:     keyRules,
+		Clusters:  clusters,
 	})
 	if err != nil {
 		return trace.Wrap(err)
@@ -94,14 +107,54 @@ func getAPIKeys(localEnv *localenv.LocalEnvironment, opsCenterURL, username stri
 	// output all api keys in a table
 	w := new(tabwriter.Writer)
 	w.Init(os.Stdout, 0, 8, 1, '\t', 0)
-	fmt.Fprintf(w, "key\texpires\n")
+	fmt.Fprintf(w, "key\tscope\tcreated\tlast used\tlast used from\texpires\n")
 	for _, k := range keys {
-		fmt.Fprintf(w, "%v\t%v\n", k.Token, k.Expires)
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t%v\n", k.Token, formatAPIKeyScope(k),
+			formatAPIKeyTime(k.Created), formatAPIKeyTime(k.LastUsed), k.LastUsedFrom, formatAPIKeyTime(k.Expires))
 	}
 	w.Flush()
 	return nil
 }
 
+// parseAPIKeyRules parses API key rules specified in the format resource:verb[,verb...]
+func parseAPIKeyRules(rules []string) (result []teleservices.Rule, err error) {
+	for _, rule := range rules {
+		parts := strings.SplitN(rule, ":", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, trace.BadParameter("expected rule in the format resource:verb[,verb...], got %q", rule)
+		}
+		result = append(result, teleservices.NewRule(parts[0], strings.Split(parts[1], ",")))
+	}
+	return result, nil
+}
+
+// formatAPIKeyScope returns a textual representation of the API key scope
+func formatAPIKeyScope(key storage.APIKey) string {
+	if !key.IsScoped() {
+		return "all"
+	}
+	var scope []string
+	if len(key.Roles) != 0 {
+		scope = append(scope, fmt.Sprintf("roles=%v", strings.Join(key.Roles, ",")))
+	}
+	for _, rule := range key.Rules {
+		scope = append(scope, fmt.Sprintf("%v:%v",
+			strings.Join(rule.Resources, ","), strings.Join(rule.Verbs, ",")))
+	}
+	if len(key.Clusters) != 0 {
+		scope = append(scope, fmt.Sprintf("clusters=%v", strings.Join(key.Clusters, ",")))
+	}
+	return strings.Join(scope, " ")
+}
+
+// formatAPIKeyTime formats the specified API key timestamp
+func formatAPIKeyTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.UTC().Format(constants.HumanDateFormat)
+}
+
 func deleteAPIKey(localEnv *localenv.LocalEnvironment, opsCenterURL, username, token string) error {
 	operator, err := localEnv.OperatorService(opsCenterURL)
 	if err != nil {
@@ -142,6 +195,29 @@ func resetUser(env *localenv.LocalEnvironment, email string, ttl time.Duration)
 	return nil
 }
 
+func unlockUser(env *localenv.LocalEnvironment, username string) error {
+	operator, err := env.SiteOperator()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	cluster, err := operator.GetLocalSite()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	err = operator.UnlockUser(context.TODO(), ops.UnlockUserRequest{
+		SiteKey: cluster.Key(),
+		Name:    username,
+	})
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	fmt.Printf("User %v has been unlocked\n", username)
+	return nil
+}
+
 func inviteUser(env *localenv.LocalEnvironment, username string, roles []string, ttl time.Duration) error {
 	operator, err := env.SiteOperator()
 	if err != nil {
@@ -168,3 +244,33 @@ func inviteUser(env *localenv.LocalEnvironment, username string, roles []string,
 
 	return nil
 }
+
+func checkUserAccess(env *localenv.LocalEnvironment, username, verb, kind string) error {
+	operator, err := env.SiteOperator()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	cluster, err := operator.GetLocalSite()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	decision, err := operator.CheckUserAccess(context.TODO(), ops.CheckUserAccessRequest{
+		SiteKey: cluster.Key(),
+		Name:    username,
+		Verb:    verb,
+		Kind:    kind,
+	})
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	answer := "no"
+	if decision.Allowed {
+		answer = "yes"
+	}
+	fmt.Printf("%v - %v\n", answer, decision)
+
+	return nil
+}
@@ -16,18 +16,27 @@ limitations under the License.
 
 import React from 'react'
 import PropTypes from 'prop-types';
-import { ButtonSecondary, ButtonWarning, Text } from 'shared/components';
+import { ButtonSecondary, ButtonWarning, Text, Box, Input, LabelInput } from 'shared/components';
 import * as Alerts from 'shared/components/Alert';
 import { useAttempt, withState } from 'shared/hooks';
 import Dialog, { DialogHeader, DialogTitle, DialogContent, DialogFooter} from 'shared/components/DialogConfirmation';
 import { startShrinkOperation } from 'app/cluster/flux/nodes/actions';
+import { isSecondFactorRequired } from 'app/services/api';
 
 export function DeleteNodeDialog(props){
   const { node, onClose, onDelete, attempt, attemptActions } = props;
   const { hostname } = node;
+  const [ token, setToken ] = React.useState('');
+  const [ tokenRequired, setTokenRequired ] = React.useState(false);
 
   const onOk = () => {
-    attemptActions.do(() => onDelete(hostname))
+    attemptActions.do(() => onDelete(hostname, token)
+      .fail(err => {
+        // the cluster requires a second factor to remove nodes
+        if (isSecondFactorRequired(err)) {
+          setTokenRequired(true);
+        }
+      }))
       .then(() => onClose());
   };
 
@@ -54,6 +63,19 @@ export function DeleteNodeDialog(props){
           <br/>
           This operation cannot be undone. Are you sure?
         </Text>
+        {tokenRequired && (
+          <Box mt="4" width="50%">
+            <LabelInput>2nd factor token</LabelInput>
+            <Input
+              type="text"
+              autoFocus
+              autoComplete="off"
+              placeholder="OTP Token"
+              value={token}
+              onChange={e => setToken(e.target.value)}
+            />
+          </Box>
+        )}
       </DialogContent>
       <DialogFooter>
         <ButtonWarning mr="3" disabled={isDisabled} onClick={onOk}>
@@ -25,8 +25,8 @@ import { SITE_SERVERS_RECEIVE } from './actionTypes';
 import opsService from 'app/services/operations';
 import * as featureFlags from 'app/cluster/featureFlags';
 
-export function startShrinkOperation(hostname) {
-  return opsService.shrink(cfg.defaultSiteId, hostname)
+export function startShrinkOperation(hostname, secondFactorToken) {
+  return opsService.shrink(cfg.defaultSiteId, hostname, secondFactorToken)
     // get the cluster info to update cluster state label
     .then(() => fetchSiteInfo());
 }
@@ -114,6 +114,17 @@ const api = {
   }
 }
 
+// SECOND_FACTOR_HEADER is used to pass the one-time token
+// for actions that require a second factor
+export const SECOND_FACTOR_HEADER = 'X-Gravity-Second-Factor';
+
+// isSecondFactorRequired returns true if the action has been rejected
+// because it requires a second factor
+export function isSecondFactorRequired(err){
+  const msg = err && err.message ? err.message : '';
+  return err && err.status === 403 && msg.indexOf('second factor token is required') !== -1;
+}
+
 // Signal allows to cancel on-goining HTTP requests
 export function Signal(){
   const subs = [];
@@ -15,7 +15,7 @@ limitations under the License.
 */
 
 import { map } from 'lodash';
-import api from 'app/services/api';
+import api, { SECOND_FACTOR_HEADER } from 'app/services/api';
 import cfg from 'app/config';
 import makeOperation from './makeOperation';
 import makeProgress from './makeProgress';
@@ -34,12 +34,21 @@ const service = {
     return api.get(url).then(json => map(json, makeOperation))
   },
 
-  shrink(siteId, hostname) {
+  shrink(siteId, hostname, secondFactorToken) {
     const request = {
       servers: [hostname],
     };
 
-    return api.post(cfg.getShrinkSiteUrl(siteId), request);
+    if (!secondFactorToken) {
+      return api.post(cfg.getShrinkSiteUrl(siteId), request);
+    }
+
+    return api.ajax({
+      url: cfg.getShrinkSiteUrl(siteId),
+      data: JSON.stringify(request),
+      type: 'POST',
+      headers: { [SECOND_FACTOR_HEADER]: secondFactorToken },
+    });
   }
 
 }